@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const aiJSONReinforcement = "Respond with valid JSON only. Do not wrap it in Markdown and do not add any text before or after it."
+
+// ErrUnparseableAIResponse keeps the raw response so that it can be shown and copied manually instead of being lost.
+type ErrUnparseableAIResponse struct {
+	Raw string
+	Err error
+}
+
+func (e *ErrUnparseableAIResponse) Error() string {
+	return fmt.Sprintf("the AI response is not valid JSON (%s). The response was:\n%s", e.Err, e.Raw)
+}
+
+func (e *ErrUnparseableAIResponse) Unwrap() error {
+	return e.Err
+}
+
+// runFabric runs the fabric pattern with the input and returns its output.
+var runFabric = func(pattern, input string) (string, error) {
+	outputBytes, err := exec.Command("fabric", "--pattern", pattern, input).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s\n%s", err.Error(), string(outputBytes))
+	}
+	return string(outputBytes), nil
+}
+
+// parseAIJSON tries the output as it is and, if that fails, repaired with repairAIJSON.
+func parseAIJSON(output string, target any) error {
+	err := json.Unmarshal([]byte(strings.TrimSpace(output)), target)
+	if err == nil {
+		return nil
+	}
+	if repaired := repairAIJSON(output); json.Unmarshal([]byte(repaired), target) == nil {
+		return nil
+	}
+	return &ErrUnparseableAIResponse{Raw: output, Err: err}
+}
+
+// repairAIJSON fixes the mistakes models make most often: Markdown code fences, text around the JSON, and trailing commas.
+// Single quotes are not replaced since they cannot be told apart from apostrophes reliably.
+func repairAIJSON(output string) string {
+	output = strings.TrimSpace(output)
+	if start := strings.Index(output, "```"); start >= 0 {
+		fenced := output[start+3:]
+		if newLine := strings.Index(fenced, "\n"); newLine >= 0 {
+			fenced = fenced[newLine+1:]
+		}
+		if end := strings.Index(fenced, "```"); end >= 0 {
+			fenced = fenced[:end]
+		}
+		output = fenced
+	}
+	return removeJSONTrailingCommas(extractJSON(output))
+}
+
+// extractJSON returns the first complete JSON array or object in the text or the text itself if there is none.
+func extractJSON(text string) string {
+	start := strings.IndexAny(text, "[{")
+	if start < 0 {
+		return text
+	}
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(text); i++ {
+		char := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && char == '\\':
+			escaped = true
+		case char == '"':
+			inString = !inString
+		case inString:
+		case char == '[' || char == '{':
+			depth++
+		case char == ']' || char == '}':
+			depth--
+			if depth == 0 {
+				return text[start : i+1]
+			}
+		}
+	}
+	return text[start:]
+}
+
+func removeJSONTrailingCommas(text string) string {
+	var builder strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		char := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && char == '\\':
+			escaped = true
+		case char == '"':
+			inString = !inString
+		case !inString && char == ',':
+			next := strings.TrimLeft(text[i+1:], " \t\r\n")
+			if strings.HasPrefix(next, "]") || strings.HasPrefix(next, "}") {
+				continue
+			}
+		}
+		builder.WriteByte(char)
+	}
+	return builder.String()
+}
+
+// runFabricJSON runs the pattern and parses the output into the target. If the output cannot be parsed even after repairs, the pattern is run once more with an explicit request for JSON.
+// Retries are reported with their duration and the approximate number of tokens since they are paid for.
+func runFabricJSON(pattern, input string, target any) error {
+	response, err := runFabric(pattern, input)
+	if err != nil {
+		return err
+	}
+	if err = parseAIJSON(response, target); err == nil {
+		return nil
+	}
+	start := time.Now()
+	retryInput := fmt.Sprintf("%s\n\n%s", input, aiJSONReinforcement)
+	retryOutput, retryErr := runFabric(pattern, retryInput)
+	output.Warn(fmt.Sprintf("The %s response was not valid JSON and was retried in %s (~%d tokens).", pattern, time.Since(start).Round(time.Millisecond), getApproximateTokens(retryInput)+getApproximateTokens(retryOutput)))
+	if retryErr != nil {
+		return errors.Join(err, retryErr)
+	}
+	return parseAIJSON(retryOutput, target)
+}
+
+// getApproximateTokens uses the common rule of thumb of four characters per token.
+func getApproximateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const aiJSONOutcomeParsed = "parsed"
+const aiJSONOutcomeRepaired = "repaired"
+const aiJSONOutcomeUnparseable = "unparseable"
+
+type aiJSONTestItem struct {
+	Text string `json:"text"`
+}
+
+// aiJSONCatalogue contains outputs models returned instead of plain JSON.
+var aiJSONCatalogue = []struct {
+	name     string
+	output   string
+	expected string
+}{
+	{"valid", `[{"text": "one"}, {"text": "two"}]`, aiJSONOutcomeParsed},
+	{"markdown fence", "```json\n[{\"text\": \"one\"}, {\"text\": \"two\"}]\n```", aiJSONOutcomeRepaired},
+	{"fence without language", "```\n[{\"text\": \"one\"}, {\"text\": \"two\"}]\n```", aiJSONOutcomeRepaired},
+	{"prose preamble", "Sure! Here are the suggestions:\n\n[{\"text\": \"one\"}, {\"text\": \"two\"}]", aiJSONOutcomeRepaired},
+	{"prose around a fence", "Here you go:\n```json\n[{\"text\": \"one\"}, {\"text\": \"two\"}]\n```\nLet me know if you need more.", aiJSONOutcomeRepaired},
+	{"prose after", "[{\"text\": \"one\"}, {\"text\": \"two\"}]\n\nThe second one [is] better.", aiJSONOutcomeRepaired},
+	{"trailing commas", "[{\"text\": \"one\",}, {\"text\": \"two\"},\n]", aiJSONOutcomeRepaired},
+	{"brackets and commas in strings", `Result: [{"text": "one"}, {"text": "two"}] and [more]`, aiJSONOutcomeRepaired},
+	{"single quotes", `[{'text': 'one'}, {'text': 'two'}]`, aiJSONOutcomeUnparseable},
+	{"prose only", "I could not find any good clips in this manuscript.", aiJSONOutcomeUnparseable},
+	{"truncated", `[{"text": "one"}, {"text": "tw`, aiJSONOutcomeUnparseable},
+}
+
+func TestAIJSON_parseAIJSON(t *testing.T) {
+	expected := []aiJSONTestItem{{Text: "one"}, {Text: "two"}}
+	for _, test := range aiJSONCatalogue {
+		t.Run(test.name, func(t *testing.T) {
+			items := []aiJSONTestItem{}
+			err := parseAIJSON(test.output, &items)
+			var unparseable *ErrUnparseableAIResponse
+			switch test.expected {
+			case aiJSONOutcomeParsed, aiJSONOutcomeRepaired:
+				if err != nil {
+					t.Fatalf("Expected the output to be parsed, but got %v", err)
+				}
+				if !reflect.DeepEqual(items, expected) {
+					t.Errorf("Expected: %v\nGot: %v", expected, items)
+				}
+				if repaired := repairAIJSON(test.output) != strings.TrimSpace(test.output); repaired != (test.expected == aiJSONOutcomeRepaired) {
+					t.Errorf("Expected the output to be %s", test.expected)
+				}
+			default:
+				if !errors.As(err, &unparseable) || unparseable.Raw != test.output {
+					t.Errorf("Expected ErrUnparseableAIResponse with the raw output, but got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestAIJSON_runFabricJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		outputs       []string
+		expectedRuns  int
+		expectedError bool
+	}{
+		{"repaired without a retry", []string{"```json\n[{\"text\": \"one\"}]\n```"}, 1, false},
+		{"retried", []string{`[{'text': 'one'}]`, `[{"text": "one"}]`}, 2, false},
+		{"surfaced after the retry", []string{"Sorry, I can't.", `[{'text': 'one'}]`}, 2, true},
+	}
+	original := runFabric
+	defer func() { runFabric = original }()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inputs := []string{}
+			runFabric = func(pattern, input string) (string, error) {
+				inputs = append(inputs, input)
+				return test.outputs[len(inputs)-1], nil
+			}
+			items := []aiJSONTestItem{}
+			err := runFabricJSON("clips_dot", "manuscript", &items)
+			if len(inputs) != test.expectedRuns {
+				t.Errorf("Expected %d runs, but got %d", test.expectedRuns, len(inputs))
+			}
+			if len(inputs) > 1 && !strings.HasSuffix(inputs[1], aiJSONReinforcement) {
+				t.Errorf("Expected the retry to ask for JSON only, but got %q", inputs[1])
+			}
+			var unparseable *ErrUnparseableAIResponse
+			if test.expectedError {
+				if !errors.As(err, &unparseable) || unparseable.Raw != test.outputs[1] {
+					t.Errorf("Expected ErrUnparseableAIResponse with the last output, but got %v", err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(items, []aiJSONTestItem{{Text: "one"}}) {
+				t.Errorf("Expected the items to be parsed, but got %v and %v", items, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+	"google.golang.org/api/googleapi"
+)
+
+type Analytics = workflow.Analytics
+
+// youtubeQuotaReasons are the reasons YouTube responds with when the daily quota or the rate limit is exceeded.
+var youtubeQuotaReasons = []string{"quotaExceeded", "dailyLimitExceeded", "rateLimitExceeded", "userRateLimitExceeded"}
+
+// VideoStatsService fetches the statistics of uploaded videos.
+type VideoStatsService interface {
+	GetVideoStats(videoId string) (Analytics, error)
+}
+
+// youTubeStatsService creates the YouTube client only when the statistics are fetched so that cached ones do not require the token.
+type youTubeStatsService struct{}
+
+func (s youTubeStatsService) GetVideoStats(videoId string) (Analytics, error) {
+	service, err := newYouTubeService()
+	if err != nil {
+		return Analytics{}, err
+	}
+	response, err := service.Videos.List([]string{"statistics"}).Id(videoId).Do()
+	if err != nil {
+		return Analytics{}, fmt.Errorf("Error getting the statistics from YouTube: %w", err)
+	}
+	if len(response.Items) == 0 || response.Items[0].Statistics == nil {
+		return Analytics{}, fmt.Errorf("video %s was not found on YouTube", videoId)
+	}
+	statistics := response.Items[0].Statistics
+	return Analytics{Views: statistics.ViewCount, Likes: statistics.LikeCount, Comments: statistics.CommentCount}, nil
+}
+
+func isYouTubeQuotaError(err error) bool {
+	apiErr := &googleapi.Error{}
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	for _, item := range apiErr.Errors {
+		for _, reason := range youtubeQuotaReasons {
+			if item.Reason == reason {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isAnalyticsFresh(analytics Analytics, ttl time.Duration, now time.Time) bool {
+	fetchedAt, err := time.Parse(time.RFC3339, analytics.FetchedAt)
+	return err == nil && now.Sub(fetchedAt) < ttl
+}
+
+// refreshVideoAnalytics fetches the statistics of the video unless the snapshot is younger than the TTL. When the YouTube quota is exceeded,
+// the snapshot is kept and the returned note says how stale it is. It returns whether the snapshot was replaced and has to be saved.
+func refreshVideoAnalytics(service VideoStatsService, video *Video, ttl time.Duration, now time.Time) (bool, string, error) {
+	if len(video.VideoId) == 0 {
+		return false, "", fmt.Errorf("%s was not uploaded to YouTube", video.Name)
+	}
+	if isAnalyticsFresh(video.Analytics, ttl, now) {
+		return false, "", nil
+	}
+	analytics, err := service.GetVideoStats(video.VideoId)
+	if err != nil {
+		if isYouTubeQuotaError(err) && len(video.Analytics.FetchedAt) > 0 {
+			return false, fmt.Sprintf("The YouTube quota is exceeded, so these are the statistics fetched at %s.", video.Analytics.FetchedAt), nil
+		}
+		return false, "", err
+	}
+	analytics.FetchedAt = now.UTC().Format(time.RFC3339)
+	video.Analytics = analytics
+	return true, "", nil
+}
+
+func getAnalyticsText(analytics Analytics, note string) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%-10s %d\n", "Views", analytics.Views))
+	builder.WriteString(fmt.Sprintf("%-10s %d\n", "Likes", analytics.Likes))
+	builder.WriteString(fmt.Sprintf("%-10s %d\n", "Comments", analytics.Comments))
+	builder.WriteString(fmt.Sprintf("%-10s %s", "Fetched", analytics.FetchedAt))
+	if len(note) > 0 {
+		builder.WriteString("\n\n" + note)
+	}
+	return builder.String()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+type fakeStatsService struct {
+	analytics Analytics
+	err       error
+	calls     *int
+}
+
+func (s fakeStatsService) GetVideoStats(videoId string) (Analytics, error) {
+	*s.calls++
+	return s.analytics, s.err
+}
+
+func TestAnalytics_refreshVideoAnalytics(t *testing.T) {
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	cached := Analytics{Views: 10, Likes: 2, Comments: 1, FetchedAt: "2030-01-21T10:00:00Z"}
+	quotaErr := &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}
+	tests := map[string]struct {
+		cached    Analytics
+		err       error
+		ttl       time.Duration
+		refreshed bool
+		stale     bool
+		expected  Analytics
+		calls     int
+		fails     bool
+	}{
+		"fresh":              {cached: cached, ttl: 24 * time.Hour, expected: cached},
+		"expired":            {cached: cached, ttl: time.Hour, refreshed: true, expected: Analytics{Views: 100, Likes: 20, Comments: 10, FetchedAt: "2030-01-21T16:00:00Z"}, calls: 1},
+		"never fetched":      {ttl: time.Hour, refreshed: true, expected: Analytics{Views: 100, Likes: 20, Comments: 10, FetchedAt: "2030-01-21T16:00:00Z"}, calls: 1},
+		"quota":              {cached: cached, err: quotaErr, ttl: time.Hour, stale: true, expected: cached, calls: 1},
+		"quota without data": {err: quotaErr, ttl: time.Hour, calls: 1, fails: true},
+		"other error":        {cached: cached, err: errors.New("the request failed"), ttl: time.Hour, expected: cached, calls: 1, fails: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			service := fakeStatsService{analytics: Analytics{Views: 100, Likes: 20, Comments: 10}, err: test.err, calls: &calls}
+			video := Video{Name: "my-video", VideoId: "123", Analytics: test.cached}
+			refreshed, note, err := refreshVideoAnalytics(service, &video, test.ttl, now)
+			if test.fails != (err != nil) {
+				t.Fatalf("Expected: failure %t\nGot: %v", test.fails, err)
+			}
+			if refreshed != test.refreshed || test.stale != (len(note) > 0) || calls != test.calls {
+				t.Errorf("Expected: refreshed %t, stale %t, %d calls\nGot: refreshed %t, note %q, %d calls", test.refreshed, test.stale, test.calls, refreshed, note, calls)
+			}
+			if !test.fails && video.Analytics != test.expected {
+				t.Errorf("Expected: %+v\nGot: %+v", test.expected, video.Analytics)
+			}
+		})
+	}
+}
+
+func TestAnalytics_refreshVideoAnalyticsNotUploaded(t *testing.T) {
+	calls := 0
+	video := Video{Name: "my-video"}
+	if _, _, err := refreshVideoAnalytics(fakeStatsService{calls: &calls}, &video, time.Hour, time.Now()); err == nil || calls > 0 {
+		t.Errorf("Expected: an error without calling YouTube\nGot: %v after %d calls", err, calls)
+	}
+}
+
+func TestAnalytics_isYouTubeQuotaError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"quota":      {err: &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, expected: true},
+		"rate":       {err: &googleapi.Error{Code: http.StatusTooManyRequests}, expected: true},
+		"wrapped":    {err: errors.Join(errors.New("listing"), &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "dailyLimitExceeded"}}}), expected: true},
+		"forbidden":  {err: &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}},
+		"not google": {err: errors.New("quotaExceeded")},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := isYouTubeQuotaError(test.err); actual != test.expected {
+				t.Errorf("Expected: %t\nGot: %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAnalytics_getAnalyticsText(t *testing.T) {
+	text := getAnalyticsText(Analytics{Views: 1234, Likes: 56, Comments: 7, FetchedAt: "2030-01-21T16:00:00Z"}, "stale")
+	expected := "Views      1234\nLikes      56\nComments   7\nFetched    2030-01-21T16:00:00Z\n\nstale"
+	if text != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, text)
+	}
+	if strings.Contains(getAnalyticsText(Analytics{}, ""), "\n\n") {
+		t.Errorf("Expected: no note")
+	}
+}
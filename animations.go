@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const animationSectionPrefix = "Section: "
+
+var animationIgnoredSections = []string{"Intro", "Setup", "Destroy"}
+
+// AnimationCue is either an animation cue (e.g., "TODO: Logo: nix.png") or, when IsSection is true, a section header.
+// Section is the header the cue belongs to and Line is the one-based line number in the manuscript.
+type AnimationCue struct {
+	Text      string
+	Section   string
+	Line      int
+	IsSection bool
+}
+
+type AnimationOptions struct {
+	CuePrefixes  []string
+	HeaderLevels []int
+}
+
+// getAnimationOptions fills whatever is not configured with the defaults ("TODO:" cues and "## " sections).
+func getAnimationOptions(animations SettingsAnimations) AnimationOptions {
+	options := AnimationOptions{CuePrefixes: animations.CuePrefixes, HeaderLevels: animations.HeaderLevels}
+	if len(options.CuePrefixes) == 0 {
+		options.CuePrefixes = []string{"TODO:"}
+	}
+	if len(options.HeaderLevels) == 0 {
+		options.HeaderLevels = []int{2}
+	}
+	return options
+}
+
+// parseAnimationCues returns cues and sections in the order they appear in the manuscript.
+// Cues can be standalone lines or bullet list items. Lines inside code blocks are ignored.
+// A cue repeated right after itself within the same section (e.g., in a list and in the paragraph that follows) is kept once.
+func parseAnimationCues(content string, options AnimationOptions) []AnimationCue {
+	cues := []AnimationCue{}
+	section := ""
+	inCode := false
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, " ", " "))
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if header, ok := getAnimationHeader(line, options.HeaderLevels); ok {
+			section = header
+			if !containsMember(animationIgnoredSections, header) {
+				cues = append(cues, AnimationCue{Text: animationSectionPrefix + header, Section: section, Line: i + 1, IsSection: true})
+			}
+			continue
+		}
+		text, ok := getAnimationCueText(line, options.CuePrefixes)
+		if !ok {
+			continue
+		}
+		if last := len(cues) - 1; last >= 0 && !cues[last].IsSection && cues[last].Section == section && cues[last].Text == text {
+			continue
+		}
+		cues = append(cues, AnimationCue{Text: text, Section: section, Line: i + 1})
+	}
+	return cues
+}
+
+func getAnimationHeader(line string, levels []int) (string, bool) {
+	for _, level := range levels {
+		prefix := strings.Repeat("#", level) + " "
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+		}
+	}
+	return "", false
+}
+
+func getAnimationCueText(line string, prefixes []string) (string, bool) {
+	for _, bullet := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(line, bullet) {
+			line = strings.TrimSpace(strings.TrimPrefix(line, bullet))
+			break
+		}
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+		}
+	}
+	return "", false
+}
+
+func getAnimationLines(cues []AnimationCue) (animations, sections []string) {
+	for _, cue := range cues {
+		animations = append(animations, cue.Text)
+		if cue.IsSection {
+			sections = append(sections, cue.Text)
+		}
+	}
+	return animations, sections
+}
+
+// getAnimationsPreview groups the cues by the section they belong to.
+func getAnimationsPreview(cues []AnimationCue) string {
+	var builder strings.Builder
+	section := ""
+	for i, cue := range cues {
+		if i == 0 || cue.Section != section {
+			section = cue.Section
+			title := section
+			if len(title) == 0 {
+				title = "(before the first section)"
+			}
+			if i > 0 {
+				builder.WriteString("\n")
+			}
+			builder.WriteString(fmt.Sprintf("%s\n", title))
+		}
+		if !cue.IsSection {
+			builder.WriteString(fmt.Sprintf("  %d: %s\n", cue.Line, cue.Text))
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
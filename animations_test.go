@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnimations_parseAnimationCues(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		options  AnimationOptions
+		expected []AnimationCue
+	}{
+		{
+			"todo markers",
+			"# [[title]] #\n\nTODO: Logo: nix.png\n\n## Intro\n\nTODO: Thumbnail: abc\n\n## Nix\n\nTODO: Logos: jenkins.png\n",
+			getAnimationOptions(SettingsAnimations{}),
+			[]AnimationCue{
+				{Text: "Logo: nix.png", Line: 3},
+				{Text: "Thumbnail: abc", Section: "Intro", Line: 7},
+				{Text: "Section: Nix", Section: "Nix", Line: 9, IsSection: true},
+				{Text: "Logos: jenkins.png", Section: "Nix", Line: 11},
+			},
+		},
+		{
+			"anim markers in lists",
+			"## Setup\n\n- ANIM: Logo: kind.png\n* ANIM: Logo: kind.png\n\n## Demo\n\nSome text\n\n- ANIM: Diagram: diag-01\n- regular item\nTODO: not a cue\n",
+			AnimationOptions{CuePrefixes: []string{"ANIM:"}, HeaderLevels: []int{2}},
+			[]AnimationCue{
+				{Text: "Logo: kind.png", Section: "Setup", Line: 3},
+				{Text: "Section: Demo", Section: "Demo", Line: 6, IsSection: true},
+				{Text: "Diagram: diag-01", Section: "Demo", Line: 10},
+			},
+		},
+		{
+			"nested headers",
+			"## Main\n\nTODO: Logo: a.png\n\n### Sub\n\nTODO: Logo: b.png\n\n#### Deeper\n\nTODO: Logo: c.png\n",
+			AnimationOptions{CuePrefixes: []string{"TODO:"}, HeaderLevels: []int{2, 3}},
+			[]AnimationCue{
+				{Text: "Section: Main", Section: "Main", Line: 1, IsSection: true},
+				{Text: "Logo: a.png", Section: "Main", Line: 3},
+				{Text: "Section: Sub", Section: "Sub", Line: 5, IsSection: true},
+				{Text: "Logo: b.png", Section: "Sub", Line: 7},
+				{Text: "Logo: c.png", Section: "Sub", Line: 11},
+			},
+		},
+		{
+			"code blocks",
+			"## Demo\n\n```sh\n# TODO: not a cue\nTODO: not a cue either\n## not a section\n```\n\nTODO: Logo: a.png\n",
+			getAnimationOptions(SettingsAnimations{}),
+			[]AnimationCue{
+				{Text: "Section: Demo", Section: "Demo", Line: 1, IsSection: true},
+				{Text: "Logo: a.png", Section: "Demo", Line: 9},
+			},
+		},
+		{
+			"ordering and repeats",
+			"## One\n\nTODO: Logo: a.png\nTODO: Logo: b.png\nTODO: Logo: a.png\n\n## Two\n\nTODO: Logo: a.png\n",
+			getAnimationOptions(SettingsAnimations{}),
+			[]AnimationCue{
+				{Text: "Section: One", Section: "One", Line: 1, IsSection: true},
+				{Text: "Logo: a.png", Section: "One", Line: 3},
+				{Text: "Logo: b.png", Section: "One", Line: 4},
+				{Text: "Logo: a.png", Section: "One", Line: 5},
+				{Text: "Section: Two", Section: "Two", Line: 7, IsSection: true},
+				{Text: "Logo: a.png", Section: "Two", Line: 9},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := parseAnimationCues(test.content, test.options); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected: %+v\nGot: %+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAnimations_getAnimationsPreview(t *testing.T) {
+	cues := parseAnimationCues("TODO: Logo: a.png\n\n## Intro\n\nTODO: Logo: b.png\n\n## Demo\n\nTODO: Logo: c.png\n", getAnimationOptions(SettingsAnimations{}))
+	expected := "(before the first section)\n  1: Logo: a.png\n\nIntro\n  5: Logo: b.png\n\nDemo\n  9: Logo: c.png"
+	if actual := getAnimationsPreview(cues); actual != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, actual)
+	}
+}
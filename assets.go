@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const assetKindImage = "image"
+const assetKindCue = "cue"
+
+var assetImageRegex = regexp.MustCompile(`!\[([^\]]*)\]\(<?([^)\s>]+)>?(?:\s+"[^"]*")?\)`)
+var assetSlugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Asset is a figure referenced by the manuscript, either through the markdown image syntax or through a cue phrase (e.g., "see the screenshot of the dashboard").
+type Asset struct {
+	Kind   string
+	Name   string
+	Alt    string
+	Line   int
+	Path   string
+	Exists bool
+}
+
+// extractAssets finds image references and cue phrases outside of code blocks. Repeated references are reported once.
+func extractAssets(manuscript string, cues []string) []Asset {
+	cueRegexes := []*regexp.Regexp{}
+	for _, cue := range cues {
+		if len(strings.TrimSpace(cue)) == 0 {
+			continue
+		}
+		cueRegexes = append(cueRegexes, regexp.MustCompile(`(?i)\b(`+regexp.QuoteMeta(strings.TrimSpace(cue))+`)s?\b(?:\s+(?:of|showing|with)\s+(?:the\s+|a\s+|an\s+)?([\w-]+))?`))
+	}
+	assets := []Asset{}
+	seen := map[string]bool{}
+	inCode := false
+	for i, line := range strings.Split(manuscript, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		for _, match := range assetImageRegex.FindAllStringSubmatch(line, -1) {
+			if seen[match[2]] {
+				continue
+			}
+			seen[match[2]] = true
+			assets = append(assets, Asset{Kind: assetKindImage, Name: match[2], Alt: match[1], Line: i + 1})
+		}
+		text := assetImageRegex.ReplaceAllString(line, "")
+		for _, cueRegex := range cueRegexes {
+			for _, match := range cueRegex.FindAllStringSubmatch(text, -1) {
+				name := strings.ToLower(match[1])
+				if len(match[2]) > 0 {
+					name = fmt.Sprintf("%s of %s", name, strings.ToLower(match[2]))
+				}
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+				assets = append(assets, Asset{Kind: assetKindCue, Name: name, Line: i + 1})
+			}
+		}
+	}
+	return assets
+}
+
+// getAssetFiles lists the files in the material directory keyed by their lowercase path relative to it.
+// Keys are lowercase because macOS file systems are case-insensitive so references often differ in case from the files.
+func getAssetFiles(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[strings.ToLower(filepath.ToSlash(relPath))] = path
+		return nil
+	})
+	return files, err
+}
+
+// matchAssets sets the path of each asset to the matching file, if there is one.
+// Image references match by path relative to the material directory and, failing that, by file name.
+// Cue phrases match files whose names contain all the words of the phrase (e.g., "screenshot of dashboard" matches dashboard-screenshot.png).
+func matchAssets(assets []Asset, files map[string]string) []Asset {
+	matched := []Asset{}
+	for _, asset := range assets {
+		asset.Path = ""
+		if asset.Kind == assetKindImage {
+			reference := strings.ToLower(filepath.ToSlash(filepath.Clean(asset.Name)))
+			for strings.HasPrefix(reference, "../") {
+				reference = strings.TrimPrefix(reference, "../")
+			}
+			if path, ok := files[reference]; ok {
+				asset.Path = path
+			} else {
+				for key, path := range files {
+					if filepath.Base(key) == filepath.Base(reference) {
+						asset.Path = path
+						break
+					}
+				}
+			}
+		} else {
+			words := strings.Fields(strings.ReplaceAll(asset.Name, " of ", " "))
+			for key, path := range files {
+				slug := assetSlugRegex.ReplaceAllString(filepath.Base(key), "-")
+				found := true
+				for _, word := range words {
+					if !strings.Contains(slug, assetSlugRegex.ReplaceAllString(word, "-")) {
+						found = false
+						break
+					}
+				}
+				if found {
+					asset.Path = path
+					break
+				}
+			}
+		}
+		asset.Exists = len(asset.Path) > 0
+		matched = append(matched, asset)
+	}
+	return matched
+}
+
+func getVideoAssets(video Video) ([]Asset, error) {
+	if len(video.Gist) == 0 {
+		return nil, nil
+	}
+	manuscript, _, err := readManuscript(video.Gist)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	files, err := getAssetFiles(getMaterialDir(video))
+	if err != nil {
+		return nil, err
+	}
+	return matchAssets(extractAssets(manuscript, settings.Assets.Cues), files), nil
+}
+
+func getMissingAssets(assets []Asset) []Asset {
+	missing := []Asset{}
+	for _, asset := range assets {
+		if !asset.Exists {
+			missing = append(missing, asset)
+		}
+	}
+	return missing
+}
+
+func getAssetChecklist(assets []Asset) string {
+	lines := []string{}
+	for _, asset := range assets {
+		name := asset.Name
+		if len(asset.Alt) > 0 {
+			name = fmt.Sprintf("%s (%s)", asset.Alt, asset.Name)
+		}
+		if asset.Exists {
+			lines = append(lines, greenStyle.Render(fmt.Sprintf("✓ %s", name)))
+		} else {
+			lines = append(lines, redStyle.Render(fmt.Sprintf("✗ %s (line %d)", name, asset.Line)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
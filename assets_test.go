@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAssets_extractAssets(t *testing.T) {
+	manuscript := `# Intro
+
+![Architecture](diagrams/architecture.png "The big picture")
+As shown in the diagram, everything goes through the gateway.
+
+` + "```sh" + `
+echo "see the screenshot of the terminal"
+` + "```" + `
+
+Let's see the screenshot of the dashboard.
+![](./Dashboard.PNG) and again ![Architecture](diagrams/architecture.png)
+Two Screenshots follow.`
+	expected := []Asset{
+		{Kind: assetKindImage, Name: "diagrams/architecture.png", Alt: "Architecture", Line: 3},
+		{Kind: assetKindCue, Name: "diagram", Line: 4},
+		{Kind: assetKindCue, Name: "screenshot of dashboard", Line: 10},
+		{Kind: assetKindImage, Name: "./Dashboard.PNG", Line: 11},
+		{Kind: assetKindCue, Name: "screenshot", Line: 12},
+	}
+	actual := extractAssets(manuscript, []string{"diagram", "screenshot"})
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected: %v\nGot: %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Expected: %+v\nGot: %+v", expected[i], actual[i])
+		}
+	}
+	if actual := extractAssets(manuscript, nil); len(actual) != 2 {
+		t.Errorf("Expected only image references without cues, but got %v", actual)
+	}
+}
+
+func TestAssets_matchAssets(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"diagrams/Architecture.png", "dashboard.png", "grafana-dashboard-screenshot.png"} {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Error occurred while creating %s: %v", file, err)
+		}
+		if err := os.WriteFile(path, []byte("image"), 0644); err != nil {
+			t.Fatalf("Error occurred while writing %s: %v", file, err)
+		}
+	}
+	files, err := getAssetFiles(dir)
+	if err != nil {
+		t.Fatalf("Error occurred while listing the assets: %v", err)
+	}
+	assets := []struct {
+		asset    Asset
+		expected string
+	}{
+		{Asset{Kind: assetKindImage, Name: "diagrams/architecture.png"}, "diagrams/Architecture.png"},
+		{Asset{Kind: assetKindImage, Name: "../material/diagrams/ARCHITECTURE.png"}, "diagrams/Architecture.png"},
+		{Asset{Kind: assetKindImage, Name: "./Dashboard.PNG"}, "dashboard.png"},
+		{Asset{Kind: assetKindImage, Name: "missing.png"}, ""},
+		{Asset{Kind: assetKindCue, Name: "screenshot of dashboard"}, "grafana-dashboard-screenshot.png"},
+		{Asset{Kind: assetKindCue, Name: "diagram"}, ""},
+	}
+	for _, asset := range assets {
+		actual := matchAssets([]Asset{asset.asset}, files)[0]
+		expected := ""
+		if len(asset.expected) > 0 {
+			expected = filepath.Join(dir, asset.expected)
+		}
+		if actual.Path != expected || actual.Exists != (len(expected) > 0) {
+			t.Errorf("%s\nExpected: %q\nGot: %q (exists=%t)", asset.asset.Name, expected, actual.Path, actual.Exists)
+		}
+	}
+	if missing := getMissingAssets(matchAssets([]Asset{assets[0].asset, assets[3].asset}, files)); len(missing) != 1 || missing[0].Name != "missing.png" {
+		t.Errorf("Expected missing.png to be missing, but got %v", missing)
+	}
+}
+
+func TestAssets_getAssetFilesMissingDir(t *testing.T) {
+	files, err := getAssetFiles(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil || len(files) != 0 {
+		t.Errorf("Expected no files and no error, but got %v %v", files, err)
+	}
+}
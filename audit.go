@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const auditActorCLI = "cli"
+
+const auditActionCreate = "create"
+const auditActionSave = "save"
+const auditActionMastodon = "mastodon"
+const auditActionSlack = "slack"
+const auditActionEmailThumbnail = "email-thumbnail"
+const auditActionEmailEdit = "email-edit"
+const auditActionEmailSponsors = "email-sponsors"
+const auditActionRelocate = "relocate"
+
+// auditActionRollback is prefixed to the name of the publish step that was rolled back.
+const auditActionRollback = "rollback-"
+
+// auditMutex serializes appends so that lines written concurrently are not interleaved.
+var auditMutex sync.Mutex
+
+// AuditEntry is a change of a video file or an external action run for it.
+// Changes are the YAML keys of the fields that changed when the video was saved. From and To are the paths of a relocated video.
+type AuditEntry struct {
+	Time    time.Time
+	Action  string
+	Actor   string
+	Changes []string `json:",omitempty"`
+	From    string   `json:",omitempty"`
+	To      string   `json:",omitempty"`
+	Error   string   `json:",omitempty"`
+}
+
+// getAuditLogPath returns the path of the audit log of the video. It's next to the video file so that it moves and is deleted with it.
+func getAuditLogPath(videoPath string) string {
+	return strings.TrimSuffix(videoPath, ".yaml") + ".log"
+}
+
+// recordAudit appends the entry to the audit log of the video. It's best effort and never fails what it records.
+// Videos without a path were not written yet and have nowhere to keep the log.
+func recordAudit(videoPath string, entry AuditEntry) {
+	if len(videoPath) == 0 {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if len(entry.Actor) == 0 {
+		entry.Actor = auditActorCLI
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+	file, err := os.OpenFile(getAuditLogPath(videoPath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.Write(append(data, '\n'))
+}
+
+func recordAuditAction(videoPath, action string, err error) {
+	entry := AuditEntry{Action: action}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	recordAudit(videoPath, entry)
+}
+
+// recordAuditSave records the fields that changed since the previous version of the video file. Saves without changes are not recorded.
+func recordAuditSave(path string, before *Video, after Video) {
+	if before == nil {
+		recordAudit(path, AuditEntry{Action: auditActionCreate})
+		return
+	}
+	if changes := getVideoChanges(*before, after); len(changes) > 0 {
+		recordAudit(path, AuditEntry{Action: auditActionSave, Changes: changes})
+	}
+}
+
+// auditIgnoredFields are bookkeeping fields that change without anyone changing the video. The progress of phases is derived from the other fields.
+var auditIgnoredFields = []string{"Index", "Path", "SchemaVersion", "Init", "Work", "Define", "Edit", "Publish"}
+
+// getVideoChanges returns the YAML keys of the fields that differ.
+func getVideoChanges(before, after Video) []string {
+	changes := []string{}
+	beforeValue, afterValue := reflect.ValueOf(before), reflect.ValueOf(after)
+	for i := 0; i < beforeValue.NumField(); i++ {
+		field := beforeValue.Type().Field(i)
+		if slices.Contains(auditIgnoredFields, field.Name) {
+			continue
+		}
+		// Fields are compared as they are written so that, for example, nil and empty slices read from the file are the same.
+		beforeData, beforeErr := yaml.Marshal(beforeValue.Field(i).Interface())
+		afterData, afterErr := yaml.Marshal(afterValue.Field(i).Interface())
+		if beforeErr != nil || afterErr != nil || !bytes.Equal(beforeData, afterData) {
+			changes = append(changes, strings.ToLower(field.Name))
+		}
+	}
+	return changes
+}
+
+// readAuditLog returns the entries of the video in the order they were recorded. Lines that cannot be parsed are skipped.
+func readAuditLog(videoPath string) ([]AuditEntry, error) {
+	file, err := os.Open(getAuditLogPath(videoPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return []AuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	entries := []AuditEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry := AuditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func getAuditText(entries []AuditEntry) string {
+	if len(entries) == 0 {
+		return "There is no history of the video."
+	}
+	lines := []string{}
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s %s %s", entry.Time.Local().Format("2006-01-02 15:04"), entry.Actor, entry.Action)
+		if len(entry.Changes) > 0 {
+			line = fmt.Sprintf("%s: %s", line, strings.Join(entry.Changes, ", "))
+		}
+		if len(entry.From) > 0 {
+			line = fmt.Sprintf("%s from %s to %s", line, entry.From, entry.To)
+		}
+		if len(entry.Error) > 0 {
+			line = fmt.Sprintf("%s failed: %s", line, entry.Error)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func getAuditActions(entries []AuditEntry) []string {
+	actions := []string{}
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+func TestAudit_writeVideo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	yaml := YAML{}
+	video := Video{Name: "my-video", Path: path}
+	if err := yaml.writeVideo(&video, path); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	video.Title = "My video"
+	video.Tags = "kubernetes"
+	if err := yaml.writeVideo(&video, path); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	if err := yaml.writeVideo(&video, path); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	entries, err := readAuditLog(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading the audit log: %v", err)
+	}
+	if actual := getAuditActions(entries); !slices.Equal(actual, []string{auditActionCreate, auditActionSave}) {
+		t.Fatalf("Expected: a create and a single save since the last one changed nothing\nGot: %v", actual)
+	}
+	if expected := []string{"title", "tags"}; !slices.Equal(entries[1].Changes, expected) || entries[1].Actor != auditActorCLI {
+		t.Errorf("Expected: %v by %s\nGot: %v by %s", expected, auditActorCLI, entries[1].Changes, entries[1].Actor)
+	}
+}
+
+func TestAudit_publishVideo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	video := Video{Name: "my-video", Path: path, Title: "Something"}
+	publishVideo(&video, fakePublisher{failThumbnail: true}, false, true)
+	entries, err := readAuditLog(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading the audit log: %v", err)
+	}
+	if actual := getAuditActions(entries); !slices.Equal(actual, []string{publishStepUpload, publishStepThumbnail}) {
+		t.Fatalf("Expected: %v\nGot: %v", []string{publishStepUpload, publishStepThumbnail}, actual)
+	}
+	if len(entries[0].Error) > 0 || len(entries[1].Error) == 0 {
+		t.Errorf("Expected: only the thumbnail to fail\nGot: %+v", entries)
+	}
+}
+
+func TestAudit_getVideoChanges(t *testing.T) {
+	tests := map[string]struct {
+		before   Video
+		after    Video
+		expected []string
+	}{
+		"none":          {before: Video{Name: "a"}, after: Video{Name: "a"}, expected: []string{}},
+		"bookkeeping":   {before: Video{Index: 1, Path: "a.yaml"}, after: Video{Index: 2, Path: "b.yaml", SchemaVersion: videoSchemaVersion}, expected: []string{}},
+		"nil and empty": {before: Video{PublishPending: nil}, after: Video{PublishPending: []string{}}, expected: []string{}},
+		"fields":        {before: Video{Title: "a", Delayed: true}, after: Video{Title: "b"}, expected: []string{"delayed", "title"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getVideoChanges(test.before, test.after); !slices.Equal(actual, test.expected) {
+				t.Errorf("Expected: %v\nGot: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAudit_recordAuditConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recordAuditAction(path, auditActionMastodon, errors.New("the request failed"))
+		}()
+	}
+	wg.Wait()
+	entries, err := readAuditLog(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading the audit log: %v", err)
+	}
+	if len(entries) != 50 {
+		t.Errorf("Expected: 50 entries\nGot: %d", len(entries))
+	}
+}
+
+func TestAudit_readAuditLogMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	entries, err := readAuditLog(path)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected: no entries\nGot: %v %v", entries, err)
+	}
+	if _, err := os.Stat(getAuditLogPath(path)); !os.IsNotExist(err) {
+		t.Errorf("Expected: reading not to create the log\nGot: %v", err)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/youtube/v3"
+)
+
+// ErrYouTubeReconsent means that the stored token cannot be refreshed anymore (it was revoked or expired) and the channel owner needs to log in again.
+var ErrYouTubeReconsent = errors.New("YouTube authorization was revoked or expired, run `youtube-automation auth login`")
+
+// ErrYouTubeTransient means that the token could not be refreshed because of a network or server problem and that retrying later might work.
+var ErrYouTubeTransient = errors.New("YouTube authorization failed temporarily")
+
+// getYouTubeScopes requests all scopes at once so that a single token works for uploads and playlists. The restricted memberships scope is
+// added only when members are synced (members.sync). Tokens stored before it was enabled do not include it, so the channel owner has to run
+// `youtube-automation auth login` again.
+func getYouTubeScopes(members SettingsMembers) []string {
+	scopes := []string{youtube.YoutubeUploadScope, youtube.YoutubeScope}
+	if members.Sync {
+		scopes = append(scopes, youtube.YoutubeChannelMembershipsCreatorScope)
+	}
+	return scopes
+}
+
+var youTubeRevokeURL = "https://oauth2.googleapis.com/revoke"
+
+var youTubeTokenSource *persistentTokenSource
+var youTubeTokenSourceMu sync.Mutex
+
+// persistentTokenSource refreshes expired tokens, retrying transient failures, and stores refreshed tokens in the token file.
+// Refreshes are serialized so that concurrent requests result in a single call to the token endpoint.
+type persistentTokenSource struct {
+	mu      sync.Mutex
+	config  *oauth2.Config
+	token   *oauth2.Token
+	path    string
+	retries int
+	backoff time.Duration
+}
+
+func newPersistentTokenSource(config *oauth2.Config, token *oauth2.Token, path string) *persistentTokenSource {
+	return &persistentTokenSource{config: config, token: token, path: path, retries: 3, backoff: time.Second}
+}
+
+func (s *persistentTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	var err error
+	for attempt := 0; attempt <= s.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(s.backoff * time.Duration(attempt))
+		}
+		var token *oauth2.Token
+		token, err = s.config.TokenSource(context.Background(), s.token).Token()
+		if err == nil {
+			s.token = token
+			if err := writeToken(s.path, token); err != nil {
+				return nil, err
+			}
+			return token, nil
+		}
+		err = getTokenError(err)
+		if errors.Is(err, ErrYouTubeReconsent) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// getTokenError wraps token endpoint errors into ErrYouTubeReconsent or ErrYouTubeTransient.
+func getTokenError(err error) error {
+	retrieveErr := &oauth2.RetrieveError{}
+	if errors.As(err, &retrieveErr) {
+		if retrieveErr.Response != nil && (retrieveErr.Response.StatusCode >= 500 || retrieveErr.Response.StatusCode == http.StatusTooManyRequests) {
+			return fmt.Errorf("%w: %v", ErrYouTubeTransient, err)
+		}
+		return fmt.Errorf("%w: %v", ErrYouTubeReconsent, err)
+	}
+	if strings.Contains(err.Error(), "refresh token is not set") {
+		return fmt.Errorf("%w: %v", ErrYouTubeReconsent, err)
+	}
+	return fmt.Errorf("%w: %v", ErrYouTubeTransient, err)
+}
+
+func getOAuthConfig() (*oauth2.Config, error) {
+	b, err := os.ReadFile("client_secret.json")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client secret file: %w", err)
+	}
+	config, err := google.ConfigFromJSON(b, getYouTubeScopes(settings.Members)...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse client secret file to config: %w", err)
+	}
+	// Use a redirect URI like this for a web app. The redirect URI must be a
+	// valid one for your OAuth2 credentials.
+	config.RedirectURL = "http://localhost:8090"
+	return config, nil
+}
+
+// getTokenPath returns youtube.tokenPath from settings or ~/.credentials/youtube-go.json.
+func getTokenPath() (string, error) {
+	if len(settings.YouTube.TokenPath) > 0 {
+		return settings.YouTube.TokenPath, nil
+	}
+	return tokenCacheFile()
+}
+
+// getYouTubeTokenSource returns the token source shared by all YouTube clients, asking for consent only if there is no stored token.
+func getYouTubeTokenSource() (*persistentTokenSource, error) {
+	youTubeTokenSourceMu.Lock()
+	defer youTubeTokenSourceMu.Unlock()
+	if youTubeTokenSource != nil {
+		return youTubeTokenSource, nil
+	}
+	config, err := getOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	path, err := getTokenPath()
+	if err != nil {
+		return nil, err
+	}
+	token, err := tokenFromFile(path)
+	if err != nil {
+		if token, err = loginYouTube(config, path); err != nil {
+			return nil, err
+		}
+	}
+	youTubeTokenSource = newPersistentTokenSource(config, token, path)
+	return youTubeTokenSource, nil
+}
+
+// loginYouTube runs the consent flow and stores the token.
+// Consent is always forced so that Google returns a refresh token even if the app was authorized before.
+func loginYouTube(config *oauth2.Config, path string) (*oauth2.Token, error) {
+	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+	var token *oauth2.Token
+	var err error
+	if launchWebServer {
+		output.Print("Trying to get token from web")
+		token, err = getTokenFromWeb(config, authURL)
+	} else {
+		output.Print("Trying to get token from prompt")
+		token, err = getTokenFromPrompt(config, authURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := writeToken(path, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// writeToken stores the token through a temporary file so that an interrupted write does not lose the refresh token.
+func writeToken(path string, token *oauth2.Token) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0600)
+}
+
+func revokeYouTubeToken(revokeURL string, token *oauth2.Token) error {
+	value := token.RefreshToken
+	if len(value) == 0 {
+		value = token.AccessToken
+	}
+	response, err := http.PostForm(revokeURL, url.Values{"token": {value}})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrYouTubeTransient, err)
+	}
+	defer response.Body.Close()
+	// Google responds with 400 when the token was already revoked or expired, so there is nothing left to revoke.
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("%w: revoking the token failed with %s", ErrYouTubeTransient, response.Status)
+	}
+	return nil
+}
+
+func getTokenStatusMessage(token *oauth2.Token, err error) string {
+	switch {
+	case errors.Is(err, ErrYouTubeReconsent):
+		return "YouTube authorization needs to be renewed. Run `youtube-automation auth login`."
+	case err != nil:
+		return fmt.Sprintf("YouTube authorization could not be checked: %s", err)
+	case token.Expiry.IsZero():
+		return "YouTube is authorized."
+	}
+	return fmt.Sprintf("YouTube is authorized. The access token is valid until %s.", token.Expiry.Local().Format(dateFormat))
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manages the YouTube authorization.",
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authorizes access to YouTube and stores the token.",
+	Run: runAuthCommand(func() error {
+		config, err := getOAuthConfig()
+		if err != nil {
+			return err
+		}
+		path, err := getTokenPath()
+		if err != nil {
+			return err
+		}
+		if _, err := loginYouTube(config, path); err != nil {
+			return err
+		}
+		output.Result(fmt.Sprintf("YouTube token was stored in %s.", path))
+		return nil
+	}),
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Checks whether the stored YouTube token is still valid, refreshing it if needed.",
+	Run: runAuthCommand(func() error {
+		config, err := getOAuthConfig()
+		if err != nil {
+			return err
+		}
+		path, err := getTokenPath()
+		if err != nil {
+			return err
+		}
+		stored, err := tokenFromFile(path)
+		if err != nil {
+			return fmt.Errorf("%w: there is no token in %s", ErrYouTubeReconsent, path)
+		}
+		token, err := newPersistentTokenSource(config, stored, path).Token()
+		if err != nil {
+			return errors.New(getTokenStatusMessage(token, err))
+		}
+		output.Result(getTokenStatusMessage(token, nil))
+		return nil
+	}),
+}
+
+var authRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revokes the stored YouTube token and deletes it.",
+	Run: runAuthCommand(func() error {
+		path, err := getTokenPath()
+		if err != nil {
+			return err
+		}
+		token, err := tokenFromFile(path)
+		if err != nil {
+			output.Result("There is no YouTube token to revoke.")
+			return nil
+		}
+		if err := revokeYouTubeToken(youTubeRevokeURL, token); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		output.Result(fmt.Sprintf("YouTube token was revoked and %s was deleted.", path))
+		return nil
+	}),
+}
+
+// runAuthCommand exits once the command is done so that the interactive menu is not started.
+func runAuthCommand(run func() error) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		if err := run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Whoops. There was an error while managing the YouTube authorization '%s'", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+}
+
+func init() {
+	authCmd.AddCommand(authLoginCmd, authStatusCmd, authRevokeCmd)
+}
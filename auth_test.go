@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/youtube/v3"
+)
+
+// startFakeOAuthServer responds to token requests with the given statuses, one per request, and with 200 once they are exhausted.
+func startFakeOAuthServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := int(atomic.AddInt32(&requests, 1))
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		if count <= len(statuses) && statuses[count-1] != http.StatusOK {
+			w.WriteHeader(statuses[count-1])
+			if statuses[count-1] == http.StatusBadRequest {
+				w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`))
+			}
+			return
+		}
+		w.Write([]byte(`{"access_token":"new-access","token_type":"Bearer","expires_in":3600}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func getTestTokenSource(t *testing.T, server *httptest.Server) *persistentTokenSource {
+	config := &oauth2.Config{ClientID: "id", ClientSecret: "secret", Endpoint: oauth2.Endpoint{TokenURL: server.URL, AuthStyle: oauth2.AuthStyleInParams}}
+	expired := &oauth2.Token{AccessToken: "old-access", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}
+	source := newPersistentTokenSource(config, expired, filepath.Join(t.TempDir(), "credentials", "youtube-go.json"))
+	source.backoff = time.Millisecond
+	return source
+}
+
+func TestAuth_TokenRefresh(t *testing.T) {
+	server, requests := startFakeOAuthServer(t)
+	source := getTestTokenSource(t, server)
+	token, err := source.Token()
+	if err != nil {
+		t.Fatalf("Expected the token to be refreshed, but got %v", err)
+	}
+	if token.AccessToken != "new-access" || *requests != 1 {
+		t.Errorf("Expected a single refresh with the new access token, but got %s after %d requests", token.AccessToken, *requests)
+	}
+	stored, err := tokenFromFile(source.path)
+	if err != nil {
+		t.Fatalf("Expected the refreshed token to be stored, but got %v", err)
+	}
+	if stored.AccessToken != "new-access" || stored.RefreshToken != "refresh" {
+		t.Errorf("Expected the stored token to keep the refresh token, but got %+v", stored)
+	}
+	if _, err := source.Token(); err != nil || *requests != 1 {
+		t.Errorf("Expected a valid token to be reused, but got %v after %d requests", err, *requests)
+	}
+}
+
+func TestAuth_TokenConcurrentRefresh(t *testing.T) {
+	server, requests := startFakeOAuthServer(t)
+	source := getTestTokenSource(t, server)
+	client := oauth2.NewClient(context.Background(), source)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new-access" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer api.Close()
+	var wg sync.WaitGroup
+	failures := int32(0)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := client.Get(api.URL)
+			if err != nil || response.StatusCode != http.StatusOK {
+				atomic.AddInt32(&failures, 1)
+				return
+			}
+			response.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if failures > 0 || *requests != 1 {
+		t.Errorf("Expected all requests to share a single refresh, but got %d failures and %d refreshes", failures, *requests)
+	}
+}
+
+func TestAuth_TokenRevoked(t *testing.T) {
+	server, requests := startFakeOAuthServer(t, http.StatusBadRequest)
+	source := getTestTokenSource(t, server)
+	_, err := source.Token()
+	if !errors.Is(err, ErrYouTubeReconsent) {
+		t.Fatalf("Expected a re-consent error, but got %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("Expected revoked tokens not to be retried, but got %d requests", *requests)
+	}
+}
+
+func TestAuth_TokenTransient(t *testing.T) {
+	server, requests := startFakeOAuthServer(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+	source := getTestTokenSource(t, server)
+	token, err := source.Token()
+	if err != nil || token.AccessToken != "new-access" || *requests != 3 {
+		t.Errorf("Expected the refresh to succeed on the third attempt, but got %v after %d requests", err, *requests)
+	}
+	server, _ = startFakeOAuthServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	if _, err := getTestTokenSource(t, server).Token(); !errors.Is(err, ErrYouTubeTransient) {
+		t.Errorf("Expected a transient error once retries are exhausted, but got %v", err)
+	}
+}
+
+func TestAuth_TokenWithoutRefreshToken(t *testing.T) {
+	server, _ := startFakeOAuthServer(t)
+	source := getTestTokenSource(t, server)
+	source.token.RefreshToken = ""
+	if _, err := source.Token(); !errors.Is(err, ErrYouTubeReconsent) {
+		t.Errorf("Expected a re-consent error, but got %v", err)
+	}
+}
+
+func TestAuth_revokeYouTubeToken(t *testing.T) {
+	revoked := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		revoked = r.FormValue("token")
+	}))
+	defer server.Close()
+	if err := revokeYouTubeToken(server.URL, &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}); err != nil {
+		t.Fatalf("Expected the token to be revoked, but got %v", err)
+	}
+	if revoked != "refresh" {
+		t.Errorf("Expected the refresh token to be revoked, but got %q", revoked)
+	}
+}
+
+// The restricted memberships scope is requested only when members are synced so that other channels keep their tokens.
+func TestAuth_getYouTubeScopes(t *testing.T) {
+	defaults := []string{youtube.YoutubeUploadScope, youtube.YoutubeScope}
+	if actual := getYouTubeScopes(SettingsMembers{}); !slices.Equal(actual, defaults) {
+		t.Errorf("Expected: %v\nGot: %v", defaults, actual)
+	}
+	expected := append(slices.Clone(defaults), youtube.YoutubeChannelMembershipsCreatorScope)
+	if actual := getYouTubeScopes(SettingsMembers{Sync: true}); !slices.Equal(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BlackoutWindow is a period in which a sponsor does not want competing content published. An empty Start or End leaves the window open on that side.
+// Annual windows repeat every year on the same days. Those that end before they start span the new year (e.g., from 12-20 to 01-05).
+type BlackoutWindow struct {
+	Sponsor string
+	Start   string
+	End     string
+	Annual  bool
+}
+
+// BlackoutRange is a window in a specific year. A zero Start or End is open.
+type BlackoutRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (r BlackoutRange) Contains(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return (r.Start.IsZero() || !day.Before(r.Start)) && (r.End.IsZero() || !day.After(r.End))
+}
+
+func parseBlackoutDay(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(dayFormat, value)
+}
+
+func validateBlackoutWindow(window BlackoutWindow) error {
+	if len(strings.TrimSpace(window.Sponsor)) == 0 {
+		return fmt.Errorf("sponsor is required")
+	}
+	start, err := parseBlackoutDay(window.Start)
+	if err != nil {
+		return fmt.Errorf("start %q must be in the %s format", window.Start, dayFormat)
+	}
+	end, err := parseBlackoutDay(window.End)
+	if err != nil {
+		return fmt.Errorf("end %q must be in the %s format", window.End, dayFormat)
+	}
+	if window.Annual && (start.IsZero() || end.IsZero()) {
+		return fmt.Errorf("annual windows require both start and end")
+	}
+	if !window.Annual && !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return fmt.Errorf("end %s is before start %s", window.End, window.Start)
+	}
+	return nil
+}
+
+// getBlackoutRanges returns the ranges of the window that may include days of the year. Only the month and the day of annual windows are used.
+func getBlackoutRanges(window BlackoutWindow, year int) ([]BlackoutRange, error) {
+	if err := validateBlackoutWindow(window); err != nil {
+		return nil, err
+	}
+	start, _ := parseBlackoutDay(window.Start)
+	end, _ := parseBlackoutDay(window.End)
+	if !window.Annual {
+		return []BlackoutRange{{Start: start, End: end}}, nil
+	}
+	ranges := []BlackoutRange{}
+	// The range that starts in the previous year covers the beginning of the year when the window spans the new year.
+	for _, rangeYear := range []int{year - 1, year} {
+		rangeStart := time.Date(rangeYear, start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		rangeEnd := time.Date(rangeYear, end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+		if rangeEnd.Before(rangeStart) {
+			rangeEnd = rangeEnd.AddDate(1, 0, 0)
+		}
+		ranges = append(ranges, BlackoutRange{Start: rangeStart, End: rangeEnd})
+	}
+	return ranges, nil
+}
+
+// getBlackoutConflicts returns the windows the date falls in, except those of the video's own sponsor. Invalid windows are ignored since the settings are validated at startup.
+func getBlackoutConflicts(date time.Time, sponsor string, windows []BlackoutWindow) []BlackoutWindow {
+	conflicts := []BlackoutWindow{}
+	for _, window := range windows {
+		if len(sponsor) > 0 && strings.EqualFold(strings.TrimSpace(sponsor), strings.TrimSpace(window.Sponsor)) {
+			continue
+		}
+		ranges, err := getBlackoutRanges(window, date.Year())
+		if err != nil {
+			continue
+		}
+		for _, blackout := range ranges {
+			if blackout.Contains(date) {
+				conflicts = append(conflicts, window)
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+func getBlackoutWindowText(window BlackoutWindow) string {
+	start, end := window.Start, window.End
+	if len(start) == 0 {
+		start = "…"
+	}
+	if len(end) == 0 {
+		end = "…"
+	}
+	if window.Annual {
+		start, end = start[5:], end[5:]
+		return fmt.Sprintf("%s (%s to %s every year)", window.Sponsor, start, end)
+	}
+	return fmt.Sprintf("%s (%s to %s)", window.Sponsor, start, end)
+}
+
+func getBlackoutConflictMessage(conflicts []BlackoutWindow) string {
+	lines := []string{"The publish date is in the blackout window of:"}
+	for _, conflict := range conflicts {
+		lines = append(lines, fmt.Sprintf("- %s", getBlackoutWindowText(conflict)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getVideoBlackoutConflicts returns no conflicts when the video has no publish date.
+func getVideoBlackoutConflicts(video Video, windows []BlackoutWindow) []BlackoutWindow {
+	date, err := time.Parse(dateFormat, video.Date)
+	if err != nil {
+		return nil
+	}
+	return getBlackoutConflicts(date, video.Sponsorship.Sponsor, windows)
+}
+
+// getBlackoutCalendarEvents returns all-day events of the windows between from and to. Open sides of windows are limited to that period.
+func getBlackoutCalendarEvents(windows []BlackoutWindow, from, to time.Time) []CalendarEvent {
+	events := []CalendarEvent{}
+	seen := map[string]bool{}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	for _, window := range windows {
+		for year := from.Year(); year <= to.Year(); year++ {
+			ranges, err := getBlackoutRanges(window, year)
+			if err != nil {
+				break
+			}
+			for _, blackout := range ranges {
+				start, end := blackout.Start, blackout.End
+				if start.IsZero() || start.Before(from) {
+					start = from
+				}
+				if end.IsZero() || end.After(to) {
+					end = to
+				}
+				if end.Before(start) {
+					continue
+				}
+				// The UID uses the start of the window, not of the period, so that it does not change as the period moves.
+				qualifier := "open"
+				if !blackout.Start.IsZero() {
+					qualifier = blackout.Start.Format(calendarDateFormat)
+				}
+				uid := fmt.Sprintf("blackout-%s-%s@youtube-automation", strings.Trim(assetSlugRegex.ReplaceAllString(strings.ToLower(window.Sponsor), "-"), "-"), qualifier)
+				if seen[uid] {
+					continue
+				}
+				seen[uid] = true
+				events = append(events, CalendarEvent{
+					UID:         uid,
+					Summary:     fmt.Sprintf("Blackout: %s", window.Sponsor),
+					Description: fmt.Sprintf("Do not publish content competing with %s.", window.Sponsor),
+					Category:    "Blackout",
+					Start:       start,
+					End:         end.AddDate(0, 0, 1),
+					AllDay:      true,
+				})
+			}
+		}
+	}
+	return events
+}
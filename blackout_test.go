@@ -0,0 +1,116 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBlackout_getBlackoutConflicts(t *testing.T) {
+	windows := []BlackoutWindow{
+		{Sponsor: "Acme", Start: "2030-03-04", End: "2030-03-10"},
+		{Sponsor: "Globex", Start: "2030-06-01"},
+		{Sponsor: "Initech", End: "2030-01-15"},
+		{Sponsor: "Umbrella", Start: "2000-12-20", End: "2000-01-05", Annual: true},
+		{Sponsor: "Hooli", Start: "2000-07-01", End: "2000-07-07", Annual: true},
+		{Sponsor: "Invalid", Start: "tomorrow"},
+	}
+	tests := map[string]struct {
+		date     string
+		sponsor  string
+		expected []string
+	}{
+		"day before the window":    {date: "2030-03-03T23:59", expected: []string{}},
+		"first day of the window":  {date: "2030-03-04T00:00", expected: []string{"Acme"}},
+		"last day of the window":   {date: "2030-03-10T23:59", expected: []string{"Acme"}},
+		"day after the window":     {date: "2030-03-11T00:00", expected: []string{}},
+		"own sponsor":              {date: "2030-03-05T16:00", sponsor: "acme ", expected: []string{}},
+		"open end":                 {date: "2031-11-01T16:00", expected: []string{"Globex"}},
+		"open start":               {date: "2029-05-01T16:00", expected: []string{"Initech"}},
+		"open start last day":      {date: "2030-01-15T16:00", expected: []string{"Initech"}},
+		"annual before new year":   {date: "2034-12-31T16:00", expected: []string{"Globex", "Umbrella"}},
+		"annual after new year":    {date: "2029-01-05T16:00", expected: []string{"Initech", "Umbrella"}},
+		"annual after the window":  {date: "2029-01-06T16:00", expected: []string{"Initech"}},
+		"annual in a later year":   {date: "2045-07-07T16:00", expected: []string{"Globex", "Hooli"}},
+		"annual outside the range": {date: "2045-07-08T16:00", expected: []string{"Globex"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			date, _ := time.Parse(dateFormat, tc.date)
+			actual := []string{}
+			for _, window := range getBlackoutConflicts(date, tc.sponsor, windows) {
+				actual = append(actual, window.Sponsor)
+			}
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("Expected: %v\nGot: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBlackout_validateBlackoutWindow(t *testing.T) {
+	tests := map[string]struct {
+		window   BlackoutWindow
+		expected string
+	}{
+		"valid":        {window: BlackoutWindow{Sponsor: "Acme", Start: "2030-03-04", End: "2030-03-10"}},
+		"open":         {window: BlackoutWindow{Sponsor: "Acme", Start: "2030-03-04"}},
+		"no sponsor":   {window: BlackoutWindow{Start: "2030-03-04"}, expected: "sponsor is required"},
+		"invalid date": {window: BlackoutWindow{Sponsor: "Acme", End: "2030-3-4"}, expected: `end "2030-3-4" must be in the 2006-01-02 format`},
+		"reversed":     {window: BlackoutWindow{Sponsor: "Acme", Start: "2030-03-10", End: "2030-03-04"}, expected: "end 2030-03-04 is before start 2030-03-10"},
+		"annual open":  {window: BlackoutWindow{Sponsor: "Acme", Start: "2030-03-10", Annual: true}, expected: "annual windows require both start and end"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := ""
+			if err := validateBlackoutWindow(tc.window); err != nil {
+				actual = err.Error()
+			}
+			if actual != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBlackout_getVideoBlackoutConflicts(t *testing.T) {
+	windows := []BlackoutWindow{{Sponsor: "Acme", Start: "2030-03-04", End: "2030-03-10"}}
+	if conflicts := getVideoBlackoutConflicts(Video{Date: "2030-03-05T16:00"}, windows); len(conflicts) != 1 {
+		t.Errorf("Expected a conflict\nGot: %v", conflicts)
+	}
+	if conflicts := getVideoBlackoutConflicts(Video{Date: "2030-03-05T16:00", Sponsorship: Sponsorship{Sponsor: "Acme"}}, windows); len(conflicts) != 0 {
+		t.Errorf("Expected the own sponsor to be exempt\nGot: %v", conflicts)
+	}
+	if conflicts := getVideoBlackoutConflicts(Video{}, windows); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts without a publish date\nGot: %v", conflicts)
+	}
+	expected := "The publish date is in the blackout window of:\n- Acme (2030-03-04 to 2030-03-10)\n- Umbrella (12-20 to 01-05 every year)"
+	if actual := getBlackoutConflictMessage(append(windows, BlackoutWindow{Sponsor: "Umbrella", Start: "2000-12-20", End: "2000-01-05", Annual: true})); actual != expected {
+		t.Errorf("Expected: %s\nGot: %s", expected, actual)
+	}
+}
+
+func TestBlackout_getBlackoutCalendarEvents(t *testing.T) {
+	windows := []BlackoutWindow{
+		{Sponsor: "Umbrella Corp", Start: "2000-12-20", End: "2000-01-05", Annual: true},
+		{Sponsor: "Globex", Start: "2030-06-01"},
+	}
+	from := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	events := getBlackoutCalendarEvents(windows, from, from.AddDate(1, 0, 0))
+	expected := []struct {
+		uid        string
+		start, end string
+	}{
+		{"blackout-umbrella-corp-20301220@youtube-automation", "20301220", "20310106"},
+		{"blackout-globex-20300601@youtube-automation", "20300601", "20310122"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events\nGot: %+v", len(expected), events)
+	}
+	for i := range expected {
+		event := events[i]
+		if event.UID != expected[i].uid || event.Start.Format(calendarDateFormat) != expected[i].start || event.End.Format(calendarDateFormat) != expected[i].end || !event.AllDay {
+			t.Errorf("Expected: %+v\nGot: %s %s %s", expected[i], event.UID, event.Start.Format(calendarDateFormat), event.End.Format(calendarDateFormat))
+		}
+	}
+}
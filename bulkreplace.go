@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const bulkFieldTitle = "title"
+const bulkFieldDescription = "description"
+const bulkFieldTags = "tags"
+const bulkFieldTweet = "tweet"
+
+// bulkPatternMaxLength bounds regular expressions. Go regular expressions run in linear time so there are no catastrophic patterns, only huge ones.
+const bulkPatternMaxLength = 1000
+
+const bulkSnippetContext = 40
+
+var bulkFields = []string{bulkFieldTitle, bulkFieldDescription, bulkFieldTags, bulkFieldTweet}
+
+var bulkBackupDir = ".backup"
+
+type BulkReplaceOptions struct {
+	Fields      []string
+	Pattern     string
+	Regex       bool
+	Replacement string
+	// Phase is one of the rulePhases names. Empty matches all phases.
+	Phase    string
+	Category string
+	// From and To limit the publish date (inclusive). Videos without a date are excluded when either is set.
+	From string
+	To   string
+}
+
+type BulkFieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// BulkChange is a preview of what would change in a video. Uploaded videos will differ from YouTube until their metadata is pushed.
+type BulkChange struct {
+	Video    Video
+	Fields   []BulkFieldChange
+	Uploaded bool
+}
+
+type BulkResult struct {
+	Path string
+	Err  error
+}
+
+func newBulkReplacer(pattern, replacement string, regex bool) (func(string) string, error) {
+	if len(pattern) == 0 {
+		return nil, fmt.Errorf("the pattern is empty")
+	}
+	if !regex {
+		return func(value string) string {
+			return strings.ReplaceAll(value, pattern, replacement)
+		}, nil
+	}
+	if len(pattern) > bulkPatternMaxLength {
+		return nil, fmt.Errorf("the pattern is longer than %d characters", bulkPatternMaxLength)
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("the pattern is not a valid regular expression: %w", err)
+	}
+	return func(value string) string {
+		return expression.ReplaceAllString(value, replacement)
+	}, nil
+}
+
+// replaceTags replaces within each tag so that the replacement cannot merge or split tags. Tags that become empty or duplicated are removed.
+func replaceTags(tags string, replace func(string) string) string {
+	output := []string{}
+	seen := map[string]bool{}
+	for _, tag := range splitTags(tags) {
+		tag = strings.TrimSpace(replace(tag))
+		if len(tag) == 0 || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		output = append(output, tag)
+	}
+	return strings.Join(output, ",")
+}
+
+func getBulkFieldValue(video *Video, field string) (*string, error) {
+	switch field {
+	case bulkFieldTitle:
+		return &video.Title, nil
+	case bulkFieldDescription:
+		return &video.Description, nil
+	case bulkFieldTags:
+		return &video.Tags, nil
+	case bulkFieldTweet:
+		return &video.Tweet, nil
+	}
+	return nil, fmt.Errorf("field %q is not one of %s", field, strings.Join(bulkFields, ", "))
+}
+
+func isBulkVideoSelected(video Video, phase int, options BulkReplaceOptions, from, to time.Time) bool {
+	if len(options.Category) > 0 && video.Category != options.Category {
+		return false
+	}
+	if len(options.Phase) > 0 && rulePhases[options.Phase] != phase {
+		return false
+	}
+	if from.IsZero() && to.IsZero() {
+		return true
+	}
+	date, err := parseDate(video.Date)
+	if err != nil {
+		return false
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return (from.IsZero() || !day.Before(from)) && (to.IsZero() || !day.After(to))
+}
+
+// getBulkChanges returns the changes the replacement would make without writing anything.
+func getBulkChanges(videos []Video, phaseOf func(Video) int, options BulkReplaceOptions) ([]BulkChange, error) {
+	replace, err := newBulkReplacer(options.Pattern, options.Replacement, options.Regex)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+	for _, field := range options.Fields {
+		if _, err := getBulkFieldValue(&Video{}, field); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := rulePhases[options.Phase]; len(options.Phase) > 0 && !ok {
+		return nil, fmt.Errorf("unknown phase %q", options.Phase)
+	}
+	var from, to time.Time
+	if len(options.From) > 0 {
+		if from, err = parseDate(options.From); err != nil {
+			return nil, err
+		}
+	}
+	if len(options.To) > 0 {
+		if to, err = parseDate(options.To); err != nil {
+			return nil, err
+		}
+	}
+	changes := []BulkChange{}
+	for _, video := range videos {
+		if !isBulkVideoSelected(video, phaseOf(video), options, from, to) {
+			continue
+		}
+		change := BulkChange{Video: video, Uploaded: len(video.VideoId) > 0}
+		for _, field := range options.Fields {
+			value, _ := getBulkFieldValue(&video, field)
+			after := replace(*value)
+			if field == bulkFieldTags {
+				after = replaceTags(*value, replace)
+				if after == strings.Join(splitTags(*value), ",") {
+					continue
+				}
+			}
+			if after != *value {
+				change.Fields = append(change.Fields, BulkFieldChange{Field: field, Before: *value, After: after})
+			}
+		}
+		if len(change.Fields) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// getBulkSnippets trims both values to the part around the first difference.
+func getBulkSnippets(before, after string) (string, string) {
+	beforeRunes, afterRunes := []rune(before), []rune(after)
+	start := 0
+	for start < len(beforeRunes) && start < len(afterRunes) && beforeRunes[start] == afterRunes[start] {
+		start++
+	}
+	end := 0
+	for end < len(beforeRunes)-start && end < len(afterRunes)-start && beforeRunes[len(beforeRunes)-1-end] == afterRunes[len(afterRunes)-1-end] {
+		end++
+	}
+	snippet := func(runes []rune) string {
+		from := max(0, start-bulkSnippetContext)
+		to := min(len(runes), len(runes)-end+bulkSnippetContext)
+		text := strings.ReplaceAll(string(runes[from:to]), "\n", " ")
+		if from > 0 {
+			text = "…" + text
+		}
+		if to < len(runes) {
+			text = text + "…"
+		}
+		return text
+	}
+	return snippet(beforeRunes), snippet(afterRunes)
+}
+
+func getBulkChangesPreview(changes []BulkChange) string {
+	if len(changes) == 0 {
+		return "No videos match."
+	}
+	var builder strings.Builder
+	uploaded := 0
+	for _, change := range changes {
+		title := change.Video.Name
+		if change.Uploaded {
+			uploaded++
+			title = fmt.Sprintf("%s (uploaded)", title)
+		}
+		builder.WriteString(fmt.Sprintf("%s\n", title))
+		for _, field := range change.Fields {
+			before, after := getBulkSnippets(field.Before, field.After)
+			builder.WriteString(fmt.Sprintf("  %s:\n  - %s\n  + %s\n", field.Field, before, after))
+		}
+	}
+	builder.WriteString(fmt.Sprintf("\n%d videos will change.", len(changes)))
+	if uploaded > 0 {
+		builder.WriteString(fmt.Sprintf(" %d of them are uploaded and their YouTube metadata will differ until it is pushed (Compare with uploaded).", uploaded))
+	}
+	return builder.String()
+}
+
+// backupVideoFiles copies the files into a new timestamped directory under bulkBackupDir and returns that directory.
+func backupVideoFiles(paths []string, now time.Time) (string, error) {
+	dir := filepath.Join(bulkBackupDir, now.Format("20060102-150405"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return dir, err
+		}
+		target := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return dir, err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+// applyBulkChanges backs up the affected videos and writes the changes. Nothing is written if the backup fails.
+func applyBulkChanges(changes []BulkChange, write func(*Video, string) error, now time.Time) (string, []BulkResult, error) {
+	paths := []string{}
+	for _, change := range changes {
+		paths = append(paths, change.Video.Path)
+	}
+	backupDir, err := backupVideoFiles(paths, now)
+	if err != nil {
+		return backupDir, nil, fmt.Errorf("backup failed, nothing was changed: %w", err)
+	}
+	results := []BulkResult{}
+	for _, change := range changes {
+		video := change.Video
+		for _, field := range change.Fields {
+			value, _ := getBulkFieldValue(&video, field.Field)
+			*value = field.After
+		}
+		results = append(results, BulkResult{Path: video.Path, Err: write(&video, video.Path)})
+	}
+	return backupDir, results, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getBulkTestVideos() []Video {
+	return []Video{
+		{Path: "manuscript/ai/01.yaml", Name: "01", Category: "ai", Date: "2030-01-07T16:00", Title: "Acme AI", Description: "Sponsored by Acme Corp.", Tags: "acme,ai, acme corp", VideoId: "abc"},
+		{Path: "manuscript/ai/02.yaml", Name: "02", Category: "ai", Date: "2030-02-04T16:00", Title: "More AI", Description: "Nothing to see.", Tags: "ai"},
+		{Path: "manuscript/k8s/01.yaml", Name: "k01", Category: "k8s", Date: "2030-01-14T16:00", Title: "Kubernetes", Description: "Acme Corp loves Kubernetes.", Tweet: "Acme Corp [YouTube Link]"},
+	}
+}
+
+func getBulkTestPhase(video Video) int {
+	if len(video.VideoId) > 0 {
+		return videosPhasePublished
+	}
+	return videosPhaseStarted
+}
+
+func getBulkChangedNames(changes []BulkChange) []string {
+	names := []string{}
+	for _, change := range changes {
+		names = append(names, change.Video.Name)
+	}
+	return names
+}
+
+func TestBulkReplace_getBulkChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  BulkReplaceOptions
+		expected map[string][]BulkFieldChange
+	}{
+		{
+			"literal",
+			BulkReplaceOptions{Fields: []string{bulkFieldDescription, bulkFieldTweet}, Pattern: "Acme Corp", Replacement: "Globex"},
+			map[string][]BulkFieldChange{
+				"01":  {{Field: bulkFieldDescription, Before: "Sponsored by Acme Corp.", After: "Sponsored by Globex."}},
+				"k01": {{Field: bulkFieldDescription, Before: "Acme Corp loves Kubernetes.", After: "Globex loves Kubernetes."}, {Field: bulkFieldTweet, Before: "Acme Corp [YouTube Link]", After: "Globex [YouTube Link]"}},
+			},
+		},
+		{
+			"regex",
+			BulkReplaceOptions{Fields: []string{bulkFieldTitle, bulkFieldDescription}, Pattern: `(?i)acme( corp)?`, Regex: true, Replacement: "Globex"},
+			map[string][]BulkFieldChange{
+				"01":  {{Field: bulkFieldTitle, Before: "Acme AI", After: "Globex AI"}, {Field: bulkFieldDescription, Before: "Sponsored by Acme Corp.", After: "Sponsored by Globex."}},
+				"k01": {{Field: bulkFieldDescription, Before: "Acme Corp loves Kubernetes.", After: "Globex loves Kubernetes."}},
+			},
+		},
+		{
+			"tags keep the comma separated structure",
+			BulkReplaceOptions{Fields: []string{bulkFieldTags}, Pattern: `^acme( corp)?$`, Regex: true, Replacement: "globex"},
+			map[string][]BulkFieldChange{
+				"01": {{Field: bulkFieldTags, Before: "acme,ai, acme corp", After: "globex,ai"}},
+			},
+		},
+		{
+			"tags that become empty are removed",
+			BulkReplaceOptions{Fields: []string{bulkFieldTags}, Pattern: "acme corp", Replacement: ""},
+			map[string][]BulkFieldChange{
+				"01": {{Field: bulkFieldTags, Before: "acme,ai, acme corp", After: "acme,ai"}},
+			},
+		},
+		{
+			"filters",
+			BulkReplaceOptions{Fields: []string{bulkFieldDescription}, Pattern: "Acme", Replacement: "Globex", Phase: "started", Category: "k8s", From: "2030-01-14", To: "2030-01-14"},
+			map[string][]BulkFieldChange{
+				"k01": {{Field: bulkFieldDescription, Before: "Acme Corp loves Kubernetes.", After: "Globex Corp loves Kubernetes."}},
+			},
+		},
+		{
+			"date range excludes",
+			BulkReplaceOptions{Fields: []string{bulkFieldDescription}, Pattern: "Acme", Replacement: "Globex", From: "2030-01-08"},
+			map[string][]BulkFieldChange{
+				"k01": {{Field: bulkFieldDescription, Before: "Acme Corp loves Kubernetes.", After: "Globex Corp loves Kubernetes."}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes, err := getBulkChanges(getBulkTestVideos(), getBulkTestPhase, test.options)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			actual := map[string][]BulkFieldChange{}
+			for _, change := range changes {
+				actual[change.Video.Name] = change.Fields
+				if change.Uploaded != (len(change.Video.VideoId) > 0) {
+					t.Errorf("Expected %s to be flagged as uploaded=%t", change.Video.Name, len(change.Video.VideoId) > 0)
+				}
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected: %v\nGot: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBulkReplace_getBulkChangesInvalid(t *testing.T) {
+	tests := []BulkReplaceOptions{
+		{Fields: []string{bulkFieldDescription}},
+		{Fields: []string{bulkFieldDescription}, Pattern: "(unclosed", Regex: true},
+		{Fields: []string{bulkFieldDescription}, Pattern: strings.Repeat("a", bulkPatternMaxLength+1), Regex: true},
+		{Fields: []string{"gist"}, Pattern: "acme"},
+		{Fields: []string{bulkFieldDescription}, Pattern: "acme", Phase: "finished"},
+		{Fields: []string{bulkFieldDescription}, Pattern: "acme", From: "last week"},
+	}
+	for _, options := range tests {
+		if _, err := getBulkChanges(getBulkTestVideos(), getBulkTestPhase, options); err == nil {
+			t.Errorf("Expected %+v to be rejected", options)
+		}
+	}
+}
+
+func TestBulkReplace_getBulkSnippets(t *testing.T) {
+	before := strings.Repeat("a", 100) + " Acme Corp " + strings.Repeat("b", 100)
+	after := strings.Repeat("a", 100) + " Globex " + strings.Repeat("b", 100)
+	actualBefore, actualAfter := getBulkSnippets(before, after)
+	expectedBefore := "…" + strings.Repeat("a", 39) + " Acme Corp " + strings.Repeat("b", 39) + "…"
+	expectedAfter := "…" + strings.Repeat("a", 39) + " Globex " + strings.Repeat("b", 39) + "…"
+	if actualBefore != expectedBefore || actualAfter != expectedAfter {
+		t.Errorf("Expected: %q and %q\nGot: %q and %q", expectedBefore, expectedAfter, actualBefore, actualAfter)
+	}
+}
+
+func TestBulkReplace_previewAndApply(t *testing.T) {
+	dir := t.TempDir()
+	bulkBackupDir = filepath.Join(dir, ".backup")
+	defer func() { bulkBackupDir = ".backup" }()
+	videos := getBulkTestVideos()
+	for i := range videos {
+		videos[i].Path = filepath.Join(dir, videos[i].Name+".yaml")
+		if err := os.WriteFile(videos[i].Path, []byte(videos[i].Description), 0644); err != nil {
+			t.Fatalf("Error occurred while writing %s: %v", videos[i].Path, err)
+		}
+	}
+	changes, err := getBulkChanges(videos, getBulkTestPhase, BulkReplaceOptions{Fields: []string{bulkFieldDescription}, Pattern: "Acme Corp", Replacement: "Globex"})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(getBulkChangedNames(changes), []string{"01", "k01"}) {
+		t.Fatalf("Unexpected changes %v", getBulkChangedNames(changes))
+	}
+	if _, err := os.Stat(bulkBackupDir); !os.IsNotExist(err) {
+		t.Errorf("Expected the preview not to create backups")
+	}
+	preview := getBulkChangesPreview(changes)
+	if !strings.Contains(preview, "01 (uploaded)") || !strings.Contains(preview, "2 videos will change. 1 of them are uploaded") {
+		t.Errorf("Unexpected preview %q", preview)
+	}
+	written := map[string]string{}
+	write := func(video *Video, path string) error {
+		if video.Name == "k01" {
+			return errors.New("disk full")
+		}
+		written[path] = video.Description
+		return nil
+	}
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	backupDir, results, err := applyBulkChanges(changes, write, now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("Expected the first video to succeed and the second to fail, but got %v", results)
+	}
+	if written[videos[0].Path] != "Sponsored by Globex." {
+		t.Errorf("Expected the replaced description to be written, but got %v", written)
+	}
+	backup, err := os.ReadFile(filepath.Join(backupDir, videos[0].Path))
+	if err != nil || string(backup) != "Sponsored by Acme Corp." {
+		t.Errorf("Expected the original to be backed up, but got %q and %v", backup, err)
+	}
+}
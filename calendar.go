@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const calendarEventPublish = "publish"
+const calendarEventCFP = "cfp"
+const calendarEventTalk = "talk"
+
+const calendarPublishDuration = time.Hour
+
+// calendarTokenMinLength keeps feed tokens long enough not to be guessed.
+const calendarTokenMinLength = 32
+
+// calendarBlackoutYears is how far ahead blackout windows are added. Annual and open-ended windows would otherwise never end.
+const calendarBlackoutYears = 2
+
+// calendarDateTimeFormat is the UTC form of iCalendar dates with time. Times are converted to UTC so that the feed does not need VTIMEZONE definitions.
+const calendarDateTimeFormat = "20060102T150405Z"
+const calendarDateFormat = "20060102"
+
+var calendarPath string
+
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Writes publish dates, CFP deadlines, talks, and sponsor blackout windows to an iCalendar file and prints the URL calendar apps can subscribe to once the file is published at calendar.url.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		location, err := time.LoadLocation(settings.Schedule.Timezone)
+		if err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+		yaml := YAML{IndexPath: "index.yaml"}
+		choices := Choices{}
+		videos := choices.getVideos(yaml.GetIndex())
+		for i := range videos {
+			// Videos saved before IDs were introduced get one now so that their UIDs do not change later.
+			if len(videos[i].ID) == 0 {
+				if err := yaml.writeVideo(&videos[i], videos[i].Path); err != nil {
+					output.Error(err.Error())
+				}
+			}
+		}
+		now := time.Now()
+		events := append(getCalendarEvents(videos, location), getBlackoutCalendarEvents(settings.Sponsorship.BlackoutWindows, now.AddDate(-1, 0, 0), now.AddDate(calendarBlackoutYears, 0, 0))...)
+		sortCalendarEvents(events)
+		feed := renderCalendar(events, settings.Schedule.Timezone, now)
+		if err := writeFileAtomic(calendarPath, []byte(feed), 0644); err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+		subscriptionURL, err := getCalendarSubscriptionURL(settings.Calendar)
+		if err != nil {
+			output.Warn(fmt.Sprintf("%s was written, but there is no subscription URL: %v", calendarPath, err))
+			return
+		}
+		output.Result(subscriptionURL)
+	},
+}
+
+// getCalendarSubscriptionURL adds the feed token to the URL the feed is published at. The token is a query parameter rather than a part of the path
+// so that the server can check it without exposing the feed under a guessable path.
+func getCalendarSubscriptionURL(calendar SettingsCalendar) (string, error) {
+	if len(calendar.URL) == 0 {
+		return "", fmt.Errorf("calendar.url is not set")
+	}
+	if len(calendar.Token) < calendarTokenMinLength {
+		return "", fmt.Errorf("the CALENDAR_TOKEN environment variable must have at least %d characters", calendarTokenMinLength)
+	}
+	parsed, err := url.Parse(calendar.URL)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("token", calendar.Token)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+func init() {
+	calendarCmd.Flags().StringVar(&calendarPath, "output", "calendar.ics", "Path of the iCalendar file.")
+}
+
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Category    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+}
+
+// getCalendarUID is derived from the ID of the video and the event type (and, for talks, the conference) so that calendar apps update events instead of
+// duplicating them, even after the video is moved or renamed. Videos that were never saved with an ID fall back to the category and the name.
+func getCalendarUID(video Video, eventType, qualifier string) string {
+	id := video.ID
+	if len(id) == 0 {
+		name, err := sanitizeVideoName(video.Name, getNameOptions())
+		if err != nil {
+			name = assetSlugRegex.ReplaceAllString(strings.ToLower(video.Name), "-")
+		}
+		id = fmt.Sprintf("%s-%s", getImportCategoryDir(video.Category), name)
+	}
+	uid := fmt.Sprintf("%s-%s", eventType, id)
+	if len(qualifier) > 0 {
+		uid = fmt.Sprintf("%s-%s", uid, strings.Trim(assetSlugRegex.ReplaceAllString(strings.ToLower(qualifier), "-"), "-"))
+	}
+	return uid + "@youtube-automation"
+}
+
+// getCalendarEvents returns the events of the videos that are not superseded by newer versions. Publish dates are in the location, while CFP deadlines (of talks not submitted yet) and accepted talks are all-day events.
+func getCalendarEvents(videos []Video, location *time.Location) []CalendarEvent {
+	events := []CalendarEvent{}
+	for _, video := range videos {
+		if len(video.SupersededBy) > 0 {
+			continue
+		}
+		title := video.Title
+		if len(title) == 0 {
+			title = video.Name
+		}
+		if date, err := time.ParseInLocation(dateFormat, video.Date, location); err == nil {
+			event := CalendarEvent{
+				UID:         getCalendarUID(video, calendarEventPublish, ""),
+				Summary:     fmt.Sprintf("Publish: %s", title),
+				Description: fmt.Sprintf("%s (%s)", video.Name, video.Category),
+				Category:    "Publish",
+				Start:       date,
+				End:         date.Add(calendarPublishDuration),
+			}
+			if len(video.VideoId) > 0 {
+				event.URL = getYouTubeURL(video.VideoId)
+			}
+			events = append(events, event)
+		}
+		for _, talk := range video.Talks {
+			if deadline, err := time.Parse(dayFormat, talk.CFPDeadline); err == nil && (len(talk.Status) == 0 || talk.Status == talkStatusPlanned) {
+				events = append(events, CalendarEvent{
+					UID:         getCalendarUID(video, calendarEventCFP, talk.Conference),
+					Summary:     fmt.Sprintf("CFP deadline: %s (%s)", talk.Conference, title),
+					Description: fmt.Sprintf("Submit %s to %s.", video.Name, talk.Conference),
+					Category:    "CFP",
+					Start:       deadline,
+					End:         deadline.AddDate(0, 0, 1),
+					AllDay:      true,
+				})
+			}
+			if date, err := time.Parse(dayFormat, talk.Date); err == nil && talk.Status == talkStatusAccepted {
+				events = append(events, CalendarEvent{
+					UID:         getCalendarUID(video, calendarEventTalk, talk.Conference),
+					Summary:     fmt.Sprintf("Talk: %s (%s)", title, talk.Conference),
+					Description: fmt.Sprintf("%s at %s.", video.Name, talk.Conference),
+					Category:    "Talk",
+					Start:       date,
+					End:         date.AddDate(0, 0, 1),
+					AllDay:      true,
+				})
+			}
+		}
+	}
+	sortCalendarEvents(events)
+	return events
+}
+
+func sortCalendarEvents(events []CalendarEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].UID < events[j].UID
+	})
+}
+
+// renderCalendar returns the iCalendar (RFC 5545) feed. Lines end with CRLF and are folded at 75 octets.
+func renderCalendar(events []CalendarEvent, timezone string, now time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//DevOps Toolkit//youtube-automation//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:YouTube Automation",
+		"X-WR-TIMEZONE:" + escapeCalendarText(timezone),
+	}
+	stamp := now.UTC().Format(calendarDateTimeFormat)
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+stamp,
+		)
+		if event.AllDay {
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+event.Start.Format(calendarDateFormat),
+				"DTEND;VALUE=DATE:"+event.End.Format(calendarDateFormat),
+			)
+		} else {
+			lines = append(lines,
+				"DTSTART:"+event.Start.UTC().Format(calendarDateTimeFormat),
+				"DTEND:"+event.End.UTC().Format(calendarDateTimeFormat),
+			)
+		}
+		lines = append(lines,
+			"SUMMARY:"+escapeCalendarText(event.Summary),
+			"DESCRIPTION:"+escapeCalendarText(event.Description),
+			"CATEGORIES:"+escapeCalendarText(event.Category),
+		)
+		if len(event.URL) > 0 {
+			lines = append(lines, "URL:"+event.URL)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(foldCalendarLine(line))
+		builder.WriteString("\r\n")
+	}
+	return builder.String()
+}
+
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldCalendarLine splits lines longer than 75 octets without breaking multi-byte characters. Continuation lines start with a space.
+func foldCalendarLine(line string) string {
+	var builder strings.Builder
+	length := 0
+	for _, char := range line {
+		size := len(string(char))
+		if length+size > 75 {
+			builder.WriteString("\r\n ")
+			length = 1
+		}
+		builder.WriteRune(char)
+		length += size
+	}
+	return builder.String()
+}
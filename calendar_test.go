@@ -0,0 +1,182 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getTestCalendarVideos() []Video {
+	return []Video{
+		{
+			ID:       "4f2a9c",
+			Name:     "Kubernetes Operators",
+			Category: "Development",
+			Title:    "Kubernetes Operators, Explained; Finally",
+			Date:     "2030-01-21T16:00",
+			VideoId:  "abc123",
+			Talks: []Talk{
+				{Conference: "KubeCon EU", CFPDeadline: "2030-02-01", Status: talkStatusPlanned},
+				{Conference: "DevOpsDays", CFPDeadline: "2030-01-10", Status: talkStatusSubmitted, Date: "2030-05-01"},
+				{Conference: "SREcon", Date: "2030-07-15", Status: talkStatusAccepted},
+			},
+		},
+		{Name: "Summer Video", Category: "Development", Date: "2030-07-16T16:00"},
+		{Name: "Old Version", Category: "Development", Date: "2030-01-22T16:00", SupersededBy: "manuscript/development/new-version.yaml"},
+		{Name: "No Date", Category: "Development"},
+	}
+}
+
+func TestCalendar_getCalendarEvents(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := getCalendarEvents(getTestCalendarVideos(), berlin)
+	expected := []string{
+		"publish-4f2a9c@youtube-automation",
+		"cfp-4f2a9c-kubecon-eu@youtube-automation",
+		"talk-4f2a9c-srecon@youtube-automation",
+		"publish-development-summer-video@youtube-automation",
+	}
+	actual := []string{}
+	for _, event := range events {
+		actual = append(actual, event.UID)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+	if events[0].URL != "https://youtu.be/abc123" || events[0].End.Sub(events[0].Start) != calendarPublishDuration {
+		t.Errorf("Expected the publish event to link to the video and last %s, but got %+v", calendarPublishDuration, events[0])
+	}
+}
+
+func TestCalendar_getCalendarUIDMovedVideo(t *testing.T) {
+	video := getTestCalendarVideos()[0]
+	moved := video
+	moved.Name, moved.Category = "operators", "Kubernetes"
+	if getCalendarUID(video, calendarEventPublish, "") != getCalendarUID(moved, calendarEventPublish, "") {
+		t.Errorf("Expected the UID not to change when the video is moved or renamed")
+	}
+}
+
+func TestCalendar_getCalendarSubscriptionURL(t *testing.T) {
+	token := strings.Repeat("a1", 16)
+	tests := map[string]struct {
+		calendar SettingsCalendar
+		expected string
+		fails    bool
+	}{
+		"url":         {calendar: SettingsCalendar{URL: "https://example.com/calendar.ics", Token: token}, expected: "https://example.com/calendar.ics?token=" + token},
+		"query":       {calendar: SettingsCalendar{URL: "https://example.com/feed?name=videos", Token: token}, expected: "https://example.com/feed?name=videos&token=" + token},
+		"no url":      {calendar: SettingsCalendar{Token: token}, fails: true},
+		"short token": {calendar: SettingsCalendar{URL: "https://example.com/calendar.ics", Token: "secret"}, fails: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := getCalendarSubscriptionURL(test.calendar)
+			if test.fails != (err != nil) || actual != test.expected {
+				t.Errorf("Expected: %q (failure %t)\nGot: %q (%v)", test.expected, test.fails, actual, err)
+			}
+		})
+	}
+}
+
+func TestCalendar_renderCalendarTimezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		expected []string
+	}{
+		// Berlin is UTC+1 in winter and UTC+2 in summer.
+		{"Europe/Berlin", []string{"DTSTART:20300121T150000Z", "DTEND:20300121T160000Z", "DTSTART:20300716T140000Z"}},
+		{"America/New_York", []string{"DTSTART:20300121T210000Z", "DTSTART:20300716T200000Z"}},
+		{"UTC", []string{"DTSTART:20300121T160000Z", "DTSTART:20300716T160000Z"}},
+	}
+	for _, test := range tests {
+		location, err := time.LoadLocation(test.timezone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		feed := renderCalendar(getCalendarEvents(getTestCalendarVideos(), location), test.timezone, time.Now())
+		for _, expected := range append(test.expected, "X-WR-TIMEZONE:"+test.timezone, "DTSTART;VALUE=DATE:20300201", "DTEND;VALUE=DATE:20300202") {
+			if !strings.Contains(feed, expected+"\r\n") {
+				t.Errorf("%s: Expected the feed to contain %q, but got:\n%s", test.timezone, expected, feed)
+			}
+		}
+	}
+}
+
+// Calendar apps update events with the same UID, so regenerating the feed must not change them.
+func TestCalendar_renderCalendarIsStable(t *testing.T) {
+	videos := getTestCalendarVideos()
+	first := renderCalendar(getCalendarEvents(videos, time.UTC), "UTC", time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC))
+	reversed := []Video{}
+	for i := len(videos) - 1; i >= 0; i-- {
+		reversed = append(reversed, videos[i])
+	}
+	second := renderCalendar(getCalendarEvents(reversed, time.UTC), "UTC", time.Date(2030, 1, 5, 10, 0, 0, 0, time.UTC))
+	withoutStamps := func(feed string) string {
+		lines := []string{}
+		for _, line := range strings.Split(feed, "\r\n") {
+			if !strings.HasPrefix(line, "DTSTAMP:") {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\r\n")
+	}
+	if withoutStamps(first) != withoutStamps(second) {
+		t.Errorf("Expected the same feed, apart from the stamps\nExpected:\n%s\nGot:\n%s", first, second)
+	}
+}
+
+func TestCalendar_renderCalendarSyntax(t *testing.T) {
+	videos := getTestCalendarVideos()
+	videos[1].Title = strings.Repeat("Ünïcödé ", 20)
+	feed := renderCalendar(getCalendarEvents(videos, time.UTC), "UTC", time.Now())
+	if !strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:") || !strings.HasSuffix(feed, "END:VCALENDAR\r\n") {
+		t.Fatalf("Expected a calendar with the version and the product, but got:\n%s", feed)
+	}
+	if strings.Contains(strings.ReplaceAll(feed, "\r\n", ""), "\n") {
+		t.Errorf("Expected all lines to end with CRLF")
+	}
+	unfolded := []string{}
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines of at most 75 octets, but got %d: %q", len(line), line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+	events := 0
+	properties := map[string]bool{}
+	for _, line := range unfolded {
+		name := strings.SplitN(strings.SplitN(line, ":", 2)[0], ";", 2)[0]
+		switch {
+		case line == "BEGIN:VEVENT":
+			events++
+			properties = map[string]bool{}
+		case line == "END:VEVENT":
+			for _, required := range []string{"UID", "DTSTAMP", "DTSTART", "DTEND", "SUMMARY"} {
+				if !properties[required] {
+					t.Errorf("Expected event %d to have %s", events, required)
+				}
+			}
+		default:
+			properties[name] = true
+		}
+	}
+	if events != 4 || strings.Count(feed, "END:VEVENT") != events {
+		t.Errorf("Expected 4 events, but got %d", events)
+	}
+	summary := `SUMMARY:Publish: Kubernetes Operators\, Explained\; Finally`
+	if !strings.Contains(strings.Join(unfolded, "\n"), summary) {
+		t.Errorf("Expected the escaped summary %q, but got:\n%s", summary, feed)
+	}
+	if !strings.Contains(strings.Join(unfolded, "\n"), "SUMMARY:Publish: "+strings.Repeat("Ünïcödé ", 20)) {
+		t.Errorf("Expected folded lines not to break multi-byte characters, but got:\n%s", feed)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"google.golang.org/api/youtube/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const channelStatePath = "channel.yaml"
+
+const defaultCommunityPostTemplate = `{{.Text}}
+
+▶️ {{.URL}}`
+
+// ChannelState records which video holds the channel trailer slot so that the previous holder can be shown and restored.
+type ChannelState struct {
+	Trailer         ChannelHighlight
+	PreviousTrailer ChannelHighlight
+}
+
+type ChannelHighlight struct {
+	VideoId string
+	Title   string
+	Since   string
+}
+
+type CommunityPost struct {
+	Title string
+	Text  string
+	URL   string
+}
+
+func loadChannelState(path string) (ChannelState, error) {
+	state := ChannelState{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("channel state %s is corrupted: %w", path, err)
+	}
+	return state, nil
+}
+
+func saveChannelState(path string, state ChannelState) error {
+	data, err := yaml.Marshal(&state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// getCommunityPost renders the community post from the tweet or, if there is none, from the description.
+func getCommunityPost(video Video, postTemplate string) (string, error) {
+	url := getYouTubeURL(video.VideoId)
+	post := CommunityPost{Title: video.Title, Text: video.Description, URL: url}
+	if len(video.Tweet) > 0 {
+		post.Text = strings.TrimSpace(strings.ReplaceAll(video.Tweet, "[YouTube Link]", ""))
+	}
+	if len(postTemplate) == 0 {
+		postTemplate = defaultCommunityPostTemplate
+	}
+	tmpl, err := template.New("post").Parse(postTemplate)
+	if err != nil {
+		return "", fmt.Errorf("community post template is invalid: %w", err)
+	}
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, post); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output.String()), nil
+}
+
+// setChannelTrailer makes the video the channel trailer (shown to visitors who are not subscribed) and returns the ID of the previous trailer.
+// Branding settings are replaced as a whole on update so they are read first and only the trailer is changed.
+func setChannelTrailer(service *youtube.Service, videoId string) (string, error) {
+	response, err := service.Channels.List([]string{"brandingSettings"}).Mine(true).Do()
+	if err != nil {
+		return "", fmt.Errorf("Error reading the channel: %w", err)
+	}
+	if len(response.Items) == 0 {
+		return "", fmt.Errorf("the authorized account has no channel")
+	}
+	channel := response.Items[0]
+	if channel.BrandingSettings == nil {
+		channel.BrandingSettings = &youtube.ChannelBrandingSettings{}
+	}
+	if channel.BrandingSettings.Channel == nil {
+		channel.BrandingSettings.Channel = &youtube.ChannelSettings{}
+	}
+	previous := channel.BrandingSettings.Channel.UnsubscribedTrailer
+	channel.BrandingSettings.Channel.UnsubscribedTrailer = videoId
+	update := &youtube.Channel{Id: channel.Id, BrandingSettings: channel.BrandingSettings}
+	if _, err := service.Channels.Update([]string{"brandingSettings"}, update).Do(); err != nil {
+		return "", fmt.Errorf("Error updating the channel trailer: %w", err)
+	}
+	return previous, nil
+}
+
+// promoteChannelTrailer sets the video as the trailer and moves the current holder to the previous slot of the state.
+func promoteChannelTrailer(service *youtube.Service, state ChannelState, highlight ChannelHighlight) (ChannelState, error) {
+	previous, err := setChannelTrailer(service, highlight.VideoId)
+	if err != nil {
+		return state, err
+	}
+	if state.Trailer.VideoId != previous {
+		// The trailer was changed outside of this tool so only the ID is known.
+		state.Trailer = ChannelHighlight{VideoId: previous}
+	}
+	if len(state.Trailer.VideoId) > 0 && state.Trailer.VideoId != highlight.VideoId {
+		state.PreviousTrailer = state.Trailer
+	}
+	state.Trailer = highlight
+	return state, nil
+}
+
+func updateChannelTrailer(state ChannelState, highlight ChannelHighlight) (ChannelState, error) {
+	service, err := youtube.New(getClient())
+	if err != nil {
+		return state, fmt.Errorf("Error creating YouTube client: %v", err)
+	}
+	return promoteChannelTrailer(service, state, highlight)
+}
+
+func copyCommunityPost(post string) {
+	clipboard.WriteAll(post)
+	output.Result("The community post has been copied to clipboard. Please paste it into YouTube Studio and pin it manually.")
+}
+
+func getChannelHighlight(video Video, now time.Time) ChannelHighlight {
+	return ChannelHighlight{VideoId: video.VideoId, Title: video.Title, Since: now.Format(dayFormat)}
+}
+
+func getChannelHighlightTitle(highlight ChannelHighlight) string {
+	switch {
+	case len(highlight.VideoId) == 0:
+		return "none"
+	case len(highlight.Title) == 0:
+		return getYouTubeURL(highlight.VideoId)
+	case len(highlight.Since) == 0:
+		return highlight.Title
+	}
+	return fmt.Sprintf("%s (since %s)", highlight.Title, highlight.Since)
+}
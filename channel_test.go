@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestChannel_getCommunityPost(t *testing.T) {
+	tests := []struct {
+		name     string
+		video    Video
+		template string
+		expected string
+	}{
+		{"tweet", Video{VideoId: "abc", Title: "Title", Description: "Description", Tweet: "Check it out [YouTube Link]"}, "", "Check it out\n\n▶️ https://youtu.be/abc"},
+		{"description", Video{VideoId: "abc", Title: "Title", Description: "Description"}, "", "Description\n\n▶️ https://youtu.be/abc"},
+		{"custom template", Video{VideoId: "abc", Title: "Title", Description: "Description"}, "New: {{.Title}} {{.URL}}", "New: Title https://youtu.be/abc"},
+	}
+	for _, test := range tests {
+		actual, err := getCommunityPost(test.video, test.template)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, but got %v", test.name, err)
+		}
+		if actual != test.expected {
+			t.Errorf("%s: Expected: %q\nGot: %q", test.name, test.expected, actual)
+		}
+	}
+	if _, err := getCommunityPost(Video{}, "{{.Title"); err == nil {
+		t.Errorf("Expected an invalid template to fail")
+	}
+}
+
+func TestChannel_promoteChannelTrailer(t *testing.T) {
+	var updateMethod, updatePart string
+	var updated youtube.Channel
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(youtube.ChannelListResponse{Items: []*youtube.Channel{{
+				Id: "channel",
+				BrandingSettings: &youtube.ChannelBrandingSettings{Channel: &youtube.ChannelSettings{
+					Title:               "DevOps Toolkit",
+					Keywords:            "devops kubernetes",
+					UnsubscribedTrailer: "old",
+				}},
+			}}})
+			return
+		}
+		updateMethod = r.Method
+		updatePart = r.URL.Query().Get("part")
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &updated)
+		w.Write(data)
+	}))
+	defer server.Close()
+	service, err := youtube.New(server.Client())
+	if err != nil {
+		t.Fatalf("Error occurred while creating the service: %v", err)
+	}
+	service.BasePath = server.URL + "/"
+	state := ChannelState{Trailer: ChannelHighlight{VideoId: "old", Title: "Old", Since: "2030-01-01"}}
+	highlight := ChannelHighlight{VideoId: "new", Title: "New", Since: "2030-01-21"}
+	state, err = promoteChannelTrailer(service, state, highlight)
+	if err != nil {
+		t.Fatalf("Expected the update to succeed, but got %v", err)
+	}
+	if updateMethod != http.MethodPut || updatePart != "brandingSettings" {
+		t.Errorf("Expected PUT with the brandingSettings part, but got %s with %s", updateMethod, updatePart)
+	}
+	channelSettings := updated.BrandingSettings.Channel
+	if updated.Id != "channel" || channelSettings.UnsubscribedTrailer != "new" || channelSettings.Title != "DevOps Toolkit" || channelSettings.Keywords != "devops kubernetes" {
+		t.Errorf("Expected only the trailer to change, but got %+v", channelSettings)
+	}
+	expected := ChannelState{Trailer: highlight, PreviousTrailer: ChannelHighlight{VideoId: "old", Title: "Old", Since: "2030-01-01"}}
+	if !reflect.DeepEqual(state, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, state)
+	}
+}
+
+func TestChannel_promoteChannelTrailerChangedOutside(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(youtube.ChannelListResponse{Items: []*youtube.Channel{{Id: "channel", BrandingSettings: &youtube.ChannelBrandingSettings{Channel: &youtube.ChannelSettings{UnsubscribedTrailer: "studio"}}}}})
+			return
+		}
+		io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+	service, _ := youtube.New(server.Client())
+	service.BasePath = server.URL + "/"
+	state := ChannelState{Trailer: ChannelHighlight{VideoId: "old", Title: "Old"}}
+	state, err := promoteChannelTrailer(service, state, ChannelHighlight{VideoId: "new", Title: "New"})
+	if err != nil {
+		t.Fatalf("Expected the update to succeed, but got %v", err)
+	}
+	if state.PreviousTrailer != (ChannelHighlight{VideoId: "studio"}) {
+		t.Errorf("Expected the trailer set outside of the tool to become the previous one, but got %+v", state.PreviousTrailer)
+	}
+}
+
+func TestChannel_channelStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "channel.yaml")
+	state, err := loadChannelState(path)
+	if err != nil || state != (ChannelState{}) {
+		t.Fatalf("Expected an empty state when the file does not exist, but got %+v and %v", state, err)
+	}
+	expected := ChannelState{
+		Trailer:         ChannelHighlight{VideoId: "new", Title: "New", Since: "2030-01-21"},
+		PreviousTrailer: ChannelHighlight{VideoId: "old", Title: "Old", Since: "2030-01-01"},
+	}
+	if err := saveChannelState(path, expected); err != nil {
+		t.Fatalf("Error occurred while saving the state: %v", err)
+	}
+	actual, err := loadChannelState(path)
+	if err != nil {
+		t.Fatalf("Error occurred while loading the state: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, actual)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const chapterMinCount = 3
+const chapterMinLength = 10 * time.Second
+
+type Chapter struct {
+	Line  int
+	Time  time.Duration
+	Title string
+}
+
+// ChapterViolation is a YouTube chapter rule that the timecodes break. Blockers cannot be fixed automatically.
+type ChapterViolation struct {
+	Line    int
+	Message string
+	Blocker bool
+}
+
+func (v ChapterViolation) String() string {
+	if v.Line == 0 {
+		return v.Message
+	}
+	return fmt.Sprintf("line %d: %s", v.Line, v.Message)
+}
+
+// parseChapters reads timecodes (one "mm:ss Title" per line) and reports lines that are not chapters.
+func parseChapters(timecodes string) ([]Chapter, []ChapterViolation) {
+	chapters := []Chapter{}
+	violations := []ChapterViolation{}
+	for i, line := range strings.Split(timecodes, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if strings.Contains(line, "TODO:") || strings.Contains(line, "FIXME:") {
+			violations = append(violations, ChapterViolation{Line: i + 1, Message: fmt.Sprintf("%q is a placeholder", line), Blocker: true})
+			continue
+		}
+		fields := strings.SplitN(line, " ", 2)
+		value, err := parseTimestamp(fields[0])
+		if err != nil {
+			violations = append(violations, ChapterViolation{Line: i + 1, Message: fmt.Sprintf("%q does not start with a timestamp", line), Blocker: true})
+			continue
+		}
+		if len(fields) < 2 || len(strings.TrimSpace(fields[1])) == 0 {
+			violations = append(violations, ChapterViolation{Line: i + 1, Message: fmt.Sprintf("chapter at %s has no title", fields[0]), Blocker: true})
+			continue
+		}
+		chapters = append(chapters, Chapter{Line: i + 1, Time: value, Title: strings.TrimSpace(fields[1])})
+	}
+	return chapters, violations
+}
+
+// validateChapters checks the rules YouTube applies before it shows chapters.
+func validateChapters(chapters []Chapter) []ChapterViolation {
+	violations := []ChapterViolation{}
+	if len(chapters) < chapterMinCount {
+		violations = append(violations, ChapterViolation{Message: fmt.Sprintf("there must be at least %d chapters, found %d", chapterMinCount, len(chapters)), Blocker: true})
+	}
+	if len(chapters) > 0 && chapters[0].Time != 0 {
+		violations = append(violations, ChapterViolation{Line: chapters[0].Line, Message: "the first chapter must start at 00:00"})
+	}
+	for i := 1; i < len(chapters); i++ {
+		previous := chapters[i-1]
+		if chapters[i].Time <= previous.Time {
+			violations = append(violations, ChapterViolation{Line: chapters[i].Line, Message: fmt.Sprintf("chapter at %s must be after the previous one (%s)", formatTimestamp(chapters[i].Time), formatTimestamp(previous.Time))})
+		} else if chapters[i].Time-previous.Time < chapterMinLength {
+			violations = append(violations, ChapterViolation{Line: previous.Line, Message: fmt.Sprintf("chapter at %s is shorter than %d seconds", formatTimestamp(previous.Time), int(chapterMinLength.Seconds())), Blocker: true})
+		}
+	}
+	return violations
+}
+
+func getChapterViolations(timecodes string) ([]Chapter, []ChapterViolation) {
+	chapters, violations := parseChapters(timecodes)
+	violations = append(violations, validateChapters(chapters)...)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Line < violations[j].Line
+	})
+	return chapters, violations
+}
+
+// fixChapters sorts chapters, removes those with duplicate times, and adds the intro when the first chapter does not start at 00:00.
+func fixChapters(chapters []Chapter) []Chapter {
+	sorted := append([]Chapter{}, chapters...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time < sorted[j].Time
+	})
+	fixed := []Chapter{}
+	for _, chapter := range sorted {
+		if len(fixed) > 0 && fixed[len(fixed)-1].Time == chapter.Time {
+			continue
+		}
+		fixed = append(fixed, chapter)
+	}
+	if len(fixed) == 0 || fixed[0].Time != 0 {
+		fixed = append([]Chapter{{Time: 0, Title: "Intro"}}, fixed...)
+	}
+	return fixed
+}
+
+func formatChapters(chapters []Chapter) string {
+	lines := []string{}
+	for _, chapter := range chapters {
+		lines = append(lines, fmt.Sprintf("%s %s", formatTimestamp(chapter.Time), chapter.Title))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getCanonicalTimecodes returns the chapters in the canonical format when they follow YouTube rules and the timecodes as they are otherwise.
+func getCanonicalTimecodes(timecodes string) string {
+	chapters, violations := getChapterViolations(timecodes)
+	if len(violations) > 0 {
+		return timecodes
+	}
+	return formatChapters(chapters)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChapters_getChapterViolations(t *testing.T) {
+	tests := []struct {
+		name      string
+		timecodes string
+		expected  []string
+	}{
+		{"valid", "00:00 Intro\n01:00 Setup\n05:30 Demo", []string{}},
+		{"too few", "00:00 Intro\n01:00 Demo", []string{"there must be at least 3 chapters, found 2"}},
+		{"no intro", "00:10 Setup\n01:00 Demo\n05:30 Outro", []string{"line 1: the first chapter must start at 00:00"}},
+		{"too short", "00:00 Intro\n00:05 Setup\n05:30 Demo", []string{"line 1: chapter at 00:00 is shorter than 10 seconds"}},
+		{"not ascending", "00:00 Intro\n05:30 Demo\n01:00 Setup", []string{"line 3: chapter at 01:00 must be after the previous one (05:30)"}},
+		{"placeholder", "00:00 Intro\n01:00 Setup\nFIXME: add demo\n05:30 Demo", []string{`line 3: "FIXME: add demo" is a placeholder`}},
+		{"title mentions TODO", "00:00 Intro\n01:00 TODO Apps\n05:30 FIXME Comments", []string{}},
+		{"no title", "00:00 Intro\n01:00\n02:00 Setup\n05:30 Demo", []string{"line 2: chapter at 01:00 has no title"}},
+	}
+	for _, test := range tests {
+		_, violations := getChapterViolations(test.timecodes)
+		actual := []string{}
+		for _, violation := range violations {
+			actual = append(actual, violation.String())
+		}
+		if !slices.Equal(actual, test.expected) {
+			t.Errorf("%s\nExpected: %v\nGot: %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestChapters_getChapterViolationsBlockers(t *testing.T) {
+	_, violations := getChapterViolations("00:00 TODO:\nTODO:TODO Demo\n01:00 Setup")
+	blockers := 0
+	for _, violation := range violations {
+		if violation.Blocker {
+			blockers++
+		}
+	}
+	if blockers != 3 {
+		t.Errorf("Expected both placeholders and the chapter count to be blockers, but got %v", violations)
+	}
+}
+
+func TestChapters_fixChapters(t *testing.T) {
+	tests := []struct {
+		name      string
+		timecodes string
+		expected  string
+	}{
+		{"sort", "00:00 Intro\n05:30 Demo\n01:00 Setup", "00:00 Intro\n01:00 Setup\n05:30 Demo"},
+		{"insert intro", "00:30 Setup\n05:30 Demo", "00:00 Intro\n00:30 Setup\n05:30 Demo"},
+		{"duplicates", "00:00 Intro\n01:00 Setup\n01:00 Setup again\n05:30 Demo", "00:00 Intro\n01:00 Setup\n05:30 Demo"},
+		{"hours", "0:00 Intro\n1:00:05 Outro\n30:00 Demo", "00:00 Intro\n30:00 Demo\n01:00:05 Outro"},
+	}
+	for _, test := range tests {
+		chapters, _ := parseChapters(test.timecodes)
+		actual := formatChapters(fixChapters(chapters))
+		if actual != test.expected {
+			t.Errorf("%s\nExpected:\n%s\nGot:\n%s", test.name, test.expected, actual)
+		}
+		if _, violations := getChapterViolations(actual); len(violations) > 0 {
+			t.Errorf("%s: expected fixed chapters to be valid, but got %v", test.name, violations)
+		}
+	}
+}
+
+func TestChapters_formatChaptersRoundTrip(t *testing.T) {
+	chapters := []Chapter{
+		{Line: 1, Time: 0, Title: "Intro"},
+		{Line: 2, Time: 75 * time.Second, Title: "What is it?"},
+		{Line: 3, Time: time.Hour + 2*time.Minute, Title: "Pros and cons"},
+	}
+	actual, violations := parseChapters(formatChapters(chapters))
+	if len(violations) > 0 || !slices.Equal(actual, chapters) {
+		t.Errorf("Expected: %v\nGot: %v %v", chapters, actual, violations)
+	}
+}
+
+func TestChapters_getCanonicalTimecodes(t *testing.T) {
+	actual := getCanonicalTimecodes("0:00 Intro\n\n1:15   Setup\n10:00 Demo")
+	expected := "00:00 Intro\n01:15 Setup\n10:00 Demo"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+	invalid := "00:00 TODO:\n01:00 Setup"
+	if actual := getCanonicalTimecodes(invalid); actual != invalid {
+		t.Errorf("Expected invalid timecodes to be left alone, but got %s", actual)
+	}
+	if !strings.Contains(getUploadRequest(Video{Timecodes: "0:00 Intro\n1:15 Setup\n10:00 Demo"}, UploadStatus{}).Snippet.Description, expected) {
+		t.Errorf("Expected the description to contain the canonical chapters")
+	}
+}
@@ -1,16 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
-	"reflect"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"devopstoolkitseries/youtube-automation/pkg/utils"
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/text/cases"
@@ -31,6 +34,13 @@ var orangeStyle = lipgloss.NewStyle().
 	Bold(true).
 	Foreground(lipgloss.Color("3"))
 
+var imminentStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.Color("5"))
+
+var farFutureStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("6"))
+
 var confirmationStyle = lipgloss.NewStyle().
 	Bold(true).
 	Foreground(lipgloss.Color("#FFFFFF")).
@@ -53,26 +63,56 @@ var errorStyle = lipgloss.NewStyle().
 	MarginTop(1).
 	MarginBottom(1)
 
-const videosPhasePublished = 0
-const videosPhasePublishPending = 1
-const videosPhaseEditRequested = 2
-const videosPhaseMaterialDone = 3
-const videosPhaseStarted = 4
-const videosPhaseDelayed = 5
-const videosPhaseSponsoredBlocked = 6
-const videosPhaseIdeas = 7
+const videosPhasePublished = workflow.PhasePublished
+const videosPhasePublishPending = workflow.PhasePublishPending
+const videosPhaseEditRequested = workflow.PhaseEditRequested
+const videosPhaseMaterialDone = workflow.PhaseMaterialDone
+const videosPhaseStarted = workflow.PhaseStarted
+const videosPhaseDelayed = workflow.PhaseDelayed
+const videosPhaseSponsoredBlocked = workflow.PhaseSponsoredBlocked
+const videosPhaseIdeas = workflow.PhaseIdeas
 
 const indexCreateVideo = 0
 const indexListVideos = 1
+const indexSendTestEmail = 2
+const indexTalks = 3
+const indexAIFeedback = 4
+const indexNormalizeTags = 5
+const indexRegenerateHugo = 6
+const indexSearch = 7
+const indexRebuildSearch = 8
+const indexCostsReport = 9
+const indexBulkReplace = 10
+const indexTrends = 11
+const indexPodcast = 12
+const indexYouTubeDrift = 13
+const indexWorkload = 14
+const indexInvoices = 15
+const indexRebuildIndex = 16
 
 const actionEdit = 0
 const actionDelete = 1
+const actionMove = 2
+const actionCompareUploaded = 3
+const actionNudgeSponsor = 4
+const actionLintDescription = 5
+const actionPromoteHighlight = 6
+const actionRefresh = 7
+const actionSecrets = 8
+const actionSyncYouTube = 9
+const actionCleanup = 10
+const actionReplaceThumbnail = 11
+const actionRecordSession = 12
+const actionAttributions = 13
+const actionFocus = 14
+const actionHistory = 15
+const actionShowStats = 16
 const actionReturn = 99
 
 func (c *Choices) ChooseIndex() {
 	var selectedIndex int
 	yaml := YAML{IndexPath: "index.yaml"}
-	form := huh.NewForm(
+	form := newForm(
 		huh.NewGroup(
 			huh.NewSelect[int]().
 				Title("What do you want to do?").
@@ -80,18 +120,13 @@ func (c *Choices) ChooseIndex() {
 				Value(&selectedIndex),
 		),
 	)
-	err := form.Run()
+	err := runForm(form)
 	if err != nil {
 		log.Fatal(err)
 	}
 	switch selectedIndex {
 	case indexCreateVideo:
-		index := yaml.GetIndex()
-		item := c.ChooseCreateVideo()
-		if len(item.Category) > 0 && len(item.Name) > 0 {
-			index = append(index, item)
-			yaml.WriteIndex(index)
-		}
+		c.ChooseCreateVideo()
 	case indexListVideos:
 		for {
 			index := yaml.GetIndex()
@@ -100,6 +135,74 @@ func (c *Choices) ChooseIndex() {
 				break
 			}
 		}
+	case indexTalks:
+		if err := c.ChooseUpcomingTalks(yaml.GetIndex()); err != nil {
+			output.Error(err.Error())
+		}
+	case indexAIFeedback:
+		entries, err := readAIFeedback(aiFeedbackPath)
+		if err != nil {
+			output.Error(err.Error())
+		} else {
+			output.Result(getAIFeedbackReport(getAIFeedbackStats(entries)))
+		}
+	case indexCostsReport:
+		output.Result(getCostReportText(getCostReport(c.getVideos(yaml.GetIndex()), settings.Costs.Currency)))
+	case indexInvoices:
+		output.ResultText(getUnpaidInvoicesText(getUnpaidInvoices(c.getVideos(yaml.GetIndex()), time.Now(), settings.Sponsorship.InvoiceTermDays)))
+	case indexTrends:
+		if err := c.ChooseTrends(yaml.GetIndex()); err != nil {
+			output.Error(err.Error())
+		}
+	case indexWorkload:
+		workload := getWorkload(c.getVideos(yaml.GetIndex()), settings.Workload, time.Now())
+		text := getWorkloadText(workload)
+		if warning := getWorkloadCapacityWarning(workload); len(warning) > 0 {
+			text = fmt.Sprintf("%s\n%s", text, orangeStyle.Render(warning))
+		}
+		output.ResultText(text)
+	case indexPodcast:
+		output.Result(getPodcastReport(c.getVideos(yaml.GetIndex()), settings.Podcast.Enabled))
+	case indexYouTubeDrift:
+		if err := c.ChooseYouTubeDrift(yaml.GetIndex()); err != nil {
+			output.Error(err.Error())
+		}
+	case indexBulkReplace:
+		if err := c.ChooseBulkReplace(yaml.GetIndex()); err != nil {
+			output.Error(err.Error())
+		}
+	case indexNormalizeTags:
+		if err := c.ChooseNormalizeTags(yaml.GetIndex()); err != nil {
+			output.Error(err.Error())
+		}
+	case indexRegenerateHugo:
+		if err := c.ChooseRegenerateHugo(yaml.GetIndex()); err != nil {
+			output.Error(err.Error())
+		}
+	case indexSearch:
+		if err := c.ChooseSearch(yaml.GetIndex()); err != nil {
+			output.Error(err.Error())
+		}
+	case indexRebuildSearch:
+		index := buildSearchIndex(c.getVideos(yaml.GetIndex()))
+		if err := saveSearchIndex(searchIndexPath, index); err != nil {
+			output.Error(err.Error())
+		} else {
+			output.Result(fmt.Sprintf("Search index was rebuilt with %d videos.", len(index.Documents)))
+		}
+	case indexRebuildIndex:
+		if err := c.ChooseRebuildIndex(yaml.IndexPath); err != nil {
+			output.Error(err.Error())
+		}
+	case indexSendTestEmail:
+		email := NewEmail(settings.Email.Password)
+		ctx, cancel := newEmailContext()
+		defer cancel()
+		if err := email.SendTest(ctx, settings.Email.From); err != nil {
+			output.Error(err.Error())
+		} else {
+			output.Result(fmt.Sprintf("Test email was sent to %s.", settings.Email.From))
+		}
 	case actionReturn:
 		os.Exit(0)
 	}
@@ -122,72 +225,134 @@ func (c *Choices) ChoosePhase(video Video) {
 		const phaseDefine = 2
 		const phaseEdit = 3
 		const phasePublish = 4
+		const phaseCosts = 5
 		var selected int
 		title := "Which type of tasks would you like to work on?"
 		if len(errorMsg) > 0 {
 			title = fmt.Sprintf("%s\n%s", errorStyle.Render(errorMsg), title)
 		}
-		form := huh.NewForm(
+		form := newForm(
 			huh.NewGroup(
 				huh.NewSelect[int]().
 					Title(title).
 					Options(
 						huh.NewOption(c.GetPhaseText("Initialize", video.Init), phaseInit),
 						huh.NewOption(c.GetPhaseText("Work", video.Work), phaseWork),
-						huh.NewOption(c.GetPhaseText("Define", video.Define), phaseDefine),
+						huh.NewOption(fmt.Sprintf("%s quality %d/100", c.GetPhaseText("Define", video.Define), getQualityScore(video, settings.Quality.Weights, settings.Tags.Aliases).Score), phaseDefine),
 						huh.NewOption(c.GetPhaseText("Edit", video.Edit), phaseEdit),
 						huh.NewOption(c.GetPhaseText("Publish", video.Publish), phasePublish),
+						huh.NewOption(fmt.Sprintf("Log cost/time (%s)", getCostSummary(video)), phaseCosts),
 						huh.NewOption("Return", actionReturn),
 					).
 					Value(&selected),
 			),
 		)
 		errorMsg = ""
-		err := form.Run()
+		err := runForm(form)
 		if err != nil {
 			log.Fatal(err)
 		}
 		switch selected {
 		case phaseInit:
-			var err error
-			if video, err = c.ChooseInit(video); err != nil {
+			if video, err = c.ChoosePhaseTasks(video, customFieldPhaseInit); err != nil {
 				panic(err)
 			}
 		case phaseWork:
-			var err error
-			if video, err = c.ChooseWork(video); err != nil {
+			if video, err = c.ChoosePhaseTasks(video, customFieldPhaseWork); err != nil {
 				panic(err)
 			}
 		case phaseDefine:
-			var err error
-			if video, err = c.ChooseDefine(video); err != nil {
-				panic(err)
+			if video, err = c.ChoosePhaseTasks(video, customFieldPhaseDefine); err != nil {
+				errorMsg = err.Error()
 			}
 		case phaseEdit:
-			var err error
-			if video, err = c.ChooseEdit(video); err != nil {
+			if video, err = c.ChoosePhaseTasks(video, customFieldPhaseEdit); err != nil {
 				errorMsg = err.Error()
 			}
 		case phasePublish:
-			var err error
-			if video, err = c.ChoosePublish(video); err != nil {
+			if video, err = c.ChoosePhaseTasks(video, customFieldPhasePublish); err != nil {
 				panic(err)
 			}
+		case phaseCosts:
+			if err := c.ChooseLogCost(&video); err != nil {
+				errorMsg = err.Error()
+			}
 		case actionReturn:
 			returnVar = true
 		}
 	}
 }
 
+// ChoosePhaseTasks runs the editor of the phase followed by its custom fields. The video is returned unchanged if the editor fails.
+func (c *Choices) ChoosePhaseTasks(video Video, phase string) (Video, error) {
+	editors := map[string]struct {
+		choose func(Video) (Video, error)
+		tasks  func(*Video) *Tasks
+	}{
+		customFieldPhaseInit:    {c.ChooseInit, func(v *Video) *Tasks { return &v.Init }},
+		customFieldPhaseWork:    {c.ChooseWork, func(v *Video) *Tasks { return &v.Work }},
+		customFieldPhaseDefine:  {c.ChooseDefine, func(v *Video) *Tasks { return &v.Define }},
+		customFieldPhaseEdit:    {c.ChooseEdit, func(v *Video) *Tasks { return &v.Edit }},
+		customFieldPhasePublish: {c.ChoosePublish, func(v *Video) *Tasks { return &v.Publish }},
+	}
+	editor, ok := editors[phase]
+	if !ok {
+		return video, fmt.Errorf("unknown phase %s", phase)
+	}
+	edited, err := editor.choose(video)
+	if err != nil {
+		return video, err
+	}
+	if err := c.ChooseCustomFields(&edited, phase, editor.tasks(&edited)); err != nil {
+		return edited, err
+	}
+	return edited, nil
+}
+
+// ChooseLogCost adds a cost or time entry to the video.
+func (c *Choices) ChooseLogCost(video *Video) error {
+	cost := Cost{Kind: costKindEditing, Currency: settings.Costs.Currency, Date: time.Now().Format(dayFormat)}
+	save := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Kind").Options(huh.NewOptions(costKinds...)...).Value(&cost.Kind),
+			huh.NewInput().Title("Amount (e.g., 150 or $150, empty if only time is logged)").Value(&cost.Amount),
+			huh.NewInput().Title("Currency").Value(&cost.Currency),
+			huh.NewInput().Title("Hours (e.g., 2h30m or 1.5)").Value(&cost.Hours),
+			huh.NewInput().Title("Note").Value(&cost.Note),
+			huh.NewInput().Title(fmt.Sprintf("Date (%s)", dayFormat)).Value(&cost.Date),
+			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !save {
+		return nil
+	}
+	if len(cost.Amount) == 0 {
+		cost.Currency = ""
+	} else if _, currency, err := parseAmount(cost.Amount); err == nil && len(currency) > 0 {
+		cost.Currency = currency
+	}
+	if err := validateCost(cost); err != nil {
+		return err
+	}
+	video.Costs = append(video.Costs, cost)
+	yaml := YAML{}
+	yaml.WriteVideo(video, video.Path)
+	return nil
+}
+
 func (c *Choices) ChooseCreateVideo() VideoIndex {
-	var name, category string
+	var name, category, templateName string
 	save := true
-	fields, err := c.getCreateVideoFields(&name, &category, &save)
+	fields, err := c.getCreateVideoFields(&name, &category, &templateName, &save)
 	if err != nil {
 		panic(err)
 	}
-	form := huh.NewForm(huh.NewGroup(fields...))
-	err = form.Run()
+	form := newForm(huh.NewGroup(fields...))
+	err = runForm(form)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -198,49 +363,132 @@ func (c *Choices) ChooseCreateVideo() VideoIndex {
 	if !save {
 		return vi
 	}
-	dirPath := c.GetDirPath(vi.Category)
-	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		os.Mkdir(dirPath, 0755)
+	fileName, err := sanitizeVideoName(name, getNameOptions())
+	if err != nil {
+		output.Error(err.Error())
+		return VideoIndex{}
+	}
+	vi.Name = getIndexedVideoName(name, fileName)
+	yaml := YAML{IndexPath: "index.yaml"}
+	creator := NewVideoCreator(yaml)
+	index := yaml.GetIndex()
+	state, err := creator.GetState(vi, index)
+	if err != nil {
+		output.Error(err.Error())
+		return VideoIndex{}
 	}
-	scriptContent := `## Intro
-
-FIXME: Welcome to DevOps Toolkit, the channel where we...
-
-FIXME: Explanation...
-
-FIXME: This is...
-
-FIXME: It's supposed to...
-
-## Setup
-
-FIXME:
-
-## FIXME:
-
-FIXME:
-
-## FIXME: Pros and Cons
-
-TODO: Header: Cons; Items: FIXME:
+	if state.IsPartial() {
+		complete := true
+		form := newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(fmt.Sprintf("Video %s was partially created: %s.", vi.Name, state)).
+					Description("Complete it with the existing files?").
+					Affirmative("Complete").
+					Negative("Cancel").
+					Value(&complete),
+			),
+		)
+		if err := runForm(form); err != nil {
+			log.Fatal(err)
+		}
+		if !complete {
+			return VideoIndex{}
+		}
+		if _, err := creator.Complete(vi, state, index); err != nil {
+			output.Error(err.Error())
+			return VideoIndex{}
+		}
+		return vi
+	}
+	var collision *ErrNameCollision
+	if errors.As(checkVideoNameCollision(c.GetDirPath(vi.Category), fileName), &collision) {
+		useSuggested := true
+		form := newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(fmt.Sprintf("Video %s already exists (%s).", collision.Name, collision.Path)).
+					Description(fmt.Sprintf("Create it as %s instead?", collision.SuggestedName)).
+					Affirmative("Create").
+					Negative("Cancel").
+					Value(&useSuggested),
+			),
+		)
+		if err := runForm(form); err != nil {
+			log.Fatal(err)
+		}
+		if !useSuggested {
+			return VideoIndex{}
+		}
+		vi.Name = collision.SuggestedName
+	}
+	if templateName != templateEmpty {
+		template, err := loadManuscriptTemplate(templatesDir, templateName, vi, time.Now())
+		if err != nil {
+			output.Error(err.Error())
+			return VideoIndex{}
+		}
+		creator.Template = &template
+	}
+	if _, err := creator.Create(vi, index); err != nil {
+		output.Error(err.Error())
+		return VideoIndex{}
+	}
+	return vi
+}
 
-TODO: Header: Pros; Items: FIXME:
+// deleteVideo removes the manuscript and the video file. The manuscript must exist while the video file may not have been written yet.
+func deleteVideo(video Video) error {
+	err := os.Remove(getManuscriptPath(video.Path))
+	if err == nil {
+		if removeErr := os.Remove(video.Path); removeErr != nil && !os.IsNotExist(removeErr) {
+			err = removeErr
+		}
+		os.Remove(getAuditLogPath(video.Path))
+		removeFromSearchIndex(video.Path)
+	}
+	output.Event(outputActionDelete, video.Path, "deleted", err)
+	return err
+}
 
-## Destroy
+// getGistPath derives the manuscript path the same way it is derived when videos are created.
+func (c *Choices) getGistPath(video Video) string {
+	if len(video.Category) > 0 && len(video.Name) > 0 {
+		return c.GetFilePath(video.Category, video.Name, "md")
+	}
+	return getManuscriptPath(video.Path)
+}
 
-FIXME:
-`
-	filePath := c.GetFilePath(vi.Category, vi.Name, "md")
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		f, err := os.Create(filePath)
-		if err != nil {
-			panic(err)
-		}
-		defer f.Close()
-		f.Write([]byte(scriptContent))
-		return vi
+// ConfirmCreateManuscript offers to create the manuscript from the template when the Gist is empty or points to a missing file.
+func (c *Choices) ConfirmCreateManuscript(video *Video) error {
+	if len(video.Gist) == 0 {
+		video.Gist = c.getGistPath(*video)
+	}
+	if manuscriptExists(video.Gist) {
+		return nil
+	}
+	create := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Manuscript %s does not exist.", video.Gist)).
+				Description("Create it from the template?").
+				Affirmative("Create").
+				Negative("Skip").
+				Value(&create),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !create {
+		return nil
+	}
+	if err := createManuscript(video.Gist); err != nil {
+		return err
 	}
-	return VideoIndex{}
+	output.Info(fmt.Sprintf("Manuscript %s was created.", video.Gist))
+	return nil
 }
 
 func (c *Choices) GetDirPath(category string) string {
@@ -249,50 +497,80 @@ func (c *Choices) GetDirPath(category string) string {
 
 func (c *Choices) GetFilePath(category, name, extension string) string {
 	dirPath := c.GetDirPath(category)
-	filePath := fmt.Sprintf("%s/%s.%s", dirPath, strings.ToLower(name), extension)
-	filePath = strings.ReplaceAll(filePath, " ", "-")
-	filePath = strings.ReplaceAll(filePath, "?", "")
-	return filePath
+	return fmt.Sprintf("%s/%s.%s", dirPath, getVideoFileName(name), extension)
 }
 
 func (c *Choices) Count(fields []interface{}) (green, all int) {
-	for _, field := range fields {
-		valueType := reflect.TypeOf(field)
-		if valueType.Kind() == reflect.String && len(field.(string)) > 0 {
-			green++
-		} else if valueType.Kind() == reflect.Bool && field.(bool) {
-			green++
-		} else if valueType.Kind() == reflect.Slice && reflect.Indirect(reflect.ValueOf(field)).Len() > 0 {
-			green++
-		}
-		all++
-	}
-	return green, all
+	return workflow.Count(fields)
 }
 
 func (c *Choices) ChooseInit(video Video) (Video, error) {
 	save := true
 	if len(video.Gist) == 0 {
-		video.Gist = strings.Replace(video.Path, ".yaml", ".md", 1)
+		video.Gist = c.getGistPath(video)
 	}
 	sponsoredEmailsTitle, _ := c.ColorFromSponsoredEmails("Sponsorship emails (comma separated)", video.Sponsorship.Amount, video.Sponsorship.Emails)
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().Title(c.ColorFromString("Project name", video.ProjectName)).Value(&video.ProjectName),
-			huh.NewInput().Title(c.ColorFromString("Project URL", video.ProjectURL)).Value(&video.ProjectURL),
-			huh.NewInput().Title(c.ColorFromString("Sponsorship amount", video.Sponsorship.Amount)).Value(&video.Sponsorship.Amount),
-			huh.NewInput().Title(sponsoredEmailsTitle).Value(&video.Sponsorship.Emails),
-			huh.NewInput().Title(c.ColorFromStringInverse("Sponsorship blocked", video.Sponsorship.Blocked)).Value(&video.Sponsorship.Blocked),
-			huh.NewInput().Title(c.ColorFromString("Publish date (e.g., 2030-01-21T16:00)", video.Date)).Value(&video.Date),
-			huh.NewConfirm().Title(c.ColorFromBool("Delayed", !video.Delayed)).Value(&video.Delayed),
-			huh.NewInput().Title(c.ColorFromString("Gist path", video.Gist)).Value(&video.Gist),
-			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
-		),
-	)
-	err := form.Run()
+	schedule, err := NewSchedule(settings.Schedule.Weekdays, settings.Schedule.Time, settings.Schedule.MinGapDays)
+	if err != nil {
+		return Video{}, err
+	}
+	scheduled := c.getScheduledVideos(video.Path)
+	now := time.Now()
+	after := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, time.UTC)
+	suggestedDate := ""
+	suggestedOptions := []huh.Option[string]{huh.NewOption("Keep the publish date above", "")}
+	for _, suggestion := range schedule.GetSuggestions(after, scheduled, 5) {
+		date := suggestion.Format(dateFormat)
+		text := fmt.Sprintf("%s (%s)", date, suggestion.Weekday())
+		if blackouts := getBlackoutConflicts(suggestion, video.Sponsorship.Sponsor, settings.Sponsorship.BlackoutWindows); len(blackouts) > 0 {
+			text = fmt.Sprintf("%s, blackout of %s", text, blackouts[0].Sponsor)
+		}
+		suggestedOptions = append(suggestedOptions, huh.NewOption(renderScheduleDate(date, text, now, settings.Schedule), date))
+	}
+	projectURLOrig := video.ProjectURL
+	manageAssets := false
+	fields := []huh.Field{
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseInit, "Project name", "Project name", len(video.ProjectName) > 0)).Value(&video.ProjectName),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseInit, "Project URL", "Project URL", len(video.ProjectURL) > 0)).Value(&video.ProjectURL),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseInit, "Sponsorship amount", "Sponsorship amount", len(video.Sponsorship.Amount) > 0)).Value(&video.Sponsorship.Amount),
+		huh.NewInput().Title(sponsoredEmailsTitle).Value(&video.Sponsorship.Emails),
+		huh.NewInput().Title("Sponsor (matched against blackout windows)").Value(&video.Sponsorship.Sponsor),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseInit, "Sponsorship blocked", "Sponsorship blocked", len(video.Sponsorship.Blocked) == 0)).Value(&video.Sponsorship.Blocked),
+		withInputHint(huh.NewInput().Title(c.ColorFromField(customFieldPhaseInit, "Publish date", "Publish date (e.g., 2030-01-21T16:00)", len(video.Date) > 0)).Value(&video.Date), &video.Date, func(date string) FieldHint {
+			return getDateHint(date, time.Now())
+		}),
+		huh.NewSelect[string]().Title("Pick a suggested date").Options(suggestedOptions...).Value(&suggestedDate),
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseInit, "Delayed", "Delayed", !video.Delayed)).Value(&video.Delayed),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseInit, "Gist path", "Gist path", len(video.Gist) > 0)).Value(&video.Gist),
+	}
+	if workflow.IsSponsored(video.Sponsorship) {
+		received := len(getReceivedSponsorAssets(video.Sponsorship.Assets))
+		title := fmt.Sprintf("Manage sponsor assets (%d/%d received)", received, len(video.Sponsorship.Assets))
+		fields = append(fields, huh.NewConfirm().Title(c.ColorFromBool(title, received == len(video.Sponsorship.Assets))).Value(&manageAssets))
+	}
+	fields = append(fields, huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save))
+	form := newForm(huh.NewGroup(fields...))
+	err = runForm(form)
 	if err != nil {
 		return Video{}, err
 	}
+	if len(suggestedDate) > 0 {
+		video.Date = suggestedDate
+	}
+	if err := c.ConfirmCreateManuscript(&video); err != nil {
+		return Video{}, err
+	}
+	if video.ProjectURL != projectURLOrig && workflow.IsSponsored(video.Sponsorship) {
+		c.PrintLinkCheck(video)
+	}
+	if date, err := time.Parse(dateFormat, video.Date); err == nil {
+		if conflicts := schedule.GetConflicts(date, scheduled); len(conflicts) > 0 {
+			output.Error(getScheduleConflictMessage(conflicts))
+		}
+		if blackouts := getBlackoutConflicts(date, video.Sponsorship.Sponsor, settings.Sponsorship.BlackoutWindows); len(blackouts) > 0 {
+			output.Error(getBlackoutConflictMessage(blackouts))
+		}
+	}
 	// TODO: Remove
 	if len(video.Sponsorship.Amount) == 0 {
 		video.Sponsorship.Amount = video.Sponsored
@@ -300,110 +578,344 @@ func (c *Choices) ChooseInit(video Video) (Video, error) {
 	if len(video.Sponsorship.Blocked) == 0 {
 		video.Sponsorship.Blocked = video.SponsorshipBlocked
 	}
-	video.Init.Completed, video.Init.Total = c.Count([]interface{}{
-		video.ProjectName,
-		video.ProjectURL,
-		video.Sponsorship.Amount,
-		video.Gist,
-		video.Date,
-	})
-	if _, completed := c.ColorFromSponsoredEmails("Sponsorship emails (comma separated)", video.Sponsorship.Amount, video.Sponsorship.Emails); completed {
-		video.Init.Completed++
-	}
-	if video.Sponsorship.Blocked == "" {
-		video.Init.Completed++
-	}
-	if !video.Delayed {
-		video.Init.Completed++
-	}
-	video.Init.Total += 3
+	video.Init = getBuiltInPhaseProgress(video, customFieldPhaseInit, settings)
 	if save {
 		yaml := YAML{}
-		yaml.WriteVideo(video, video.Path)
+		yaml.WriteVideo(&video, video.Path)
+		// Assets are saved as they are changed so they are managed only after the rest of the details are saved.
+		if manageAssets {
+			if err := c.ChooseSponsorAssets(&video); err != nil {
+				return video, err
+			}
+		}
 	}
 	return video, err
 }
 
 func (c *Choices) ChooseWork(video Video) (Video, error) {
 	save := true
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewConfirm().Title(c.ColorFromBool("Code done", video.Code)).Value(&video.Code),
-			huh.NewConfirm().Title(c.ColorFromBool("Talking head done", video.Head)).Value(&video.Head),
-			huh.NewConfirm().Title(c.ColorFromBool("Screen done", video.Screen)).Value(&video.Screen),
-			huh.NewText().Lines(3).CharLimit(10000).Title(c.ColorFromString("Related videos", video.RelatedVideos)).Value(&video.RelatedVideos),
-			huh.NewConfirm().Title(c.ColorFromBool("Thumbnails done", video.Thumbnails)).Value(&video.Thumbnails),
-			huh.NewConfirm().Title(c.ColorFromBool("Diagrams done", video.Diagrams)).Value(&video.Diagrams),
-			huh.NewInput().Title(c.ColorFromString("Files location", video.Location)).Value(&video.Location),
-			huh.NewInput().Title(c.ColorFromString("Tagline", video.Tagline)).Value(&video.Tagline),
-			huh.NewInput().Title(c.ColorFromString("Tagline ideas", video.TaglineIdeas)).Value(&video.TaglineIdeas),
-			huh.NewInput().Title(c.ColorFromString("Other logos", video.OtherLogos)).Value(&video.OtherLogos),
-			huh.NewConfirm().Title(c.ColorFromBool("Screenshots done", video.Screenshots)).Value(&video.Screenshots),
+	suggestRelated := false
+	manageDemos := false
+	manageSections := false
+	recordedSections, _, totalSections := getSectionsProgress(video.Sections)
+	assets, err := getVideoAssets(video)
+	if err != nil {
+		return Video{}, err
+	}
+	assetsChecklist := getAssetChecklist(assets)
+	if len(assets) == 0 {
+		assetsChecklist = "There are no figures referenced in the manuscript."
+	}
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseWork, "Code", "Code done", video.Code)).Value(&video.Code),
+			huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseWork, "Talking head", "Talking head done", video.Head)).Value(&video.Head),
+			huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseWork, "Screen", "Screen done", video.Screen)).Value(&video.Screen),
+			huh.NewText().Lines(3).CharLimit(10000).Title(c.ColorFromField(customFieldPhaseWork, "Related videos", "Related videos", len(video.RelatedVideos) > 0)).Value(&video.RelatedVideos),
+			huh.NewConfirm().Title("Suggest related videos").Value(&suggestRelated),
+			huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseWork, "Thumbnails", "Thumbnails done", video.Thumbnails)).Value(&video.Thumbnails),
+			huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseWork, "Diagrams", "Diagrams done", video.Diagrams)).Value(&video.Diagrams),
+			huh.NewInput().Title(c.ColorFromField(customFieldPhaseWork, "Files location", "Files location", len(video.Location) > 0)).Value(&video.Location),
+			huh.NewInput().Title(c.ColorFromField(customFieldPhaseWork, "Tagline", "Tagline", len(video.Tagline) > 0)).Value(&video.Tagline),
+			huh.NewInput().Title(c.ColorFromField(customFieldPhaseWork, "Tagline ideas", "Tagline ideas", len(video.TaglineIdeas) > 0)).Value(&video.TaglineIdeas),
+			huh.NewInput().Title(c.ColorFromField(customFieldPhaseWork, "Other logos", "Other logos", len(video.OtherLogos) > 0)).Value(&video.OtherLogos),
+			huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseWork, "Screenshots", "Screenshots done", video.Screenshots)).Value(&video.Screenshots),
+			huh.NewNote().Title("Referenced assets").Description(assetsChecklist),
+			huh.NewConfirm().Title(fmt.Sprintf("Manage demo environments (%d running)", len(getUndestroyedDemos(video)))).Value(&manageDemos),
+			huh.NewConfirm().Title(fmt.Sprintf("Manage manuscript sections (%d/%d recorded)", recordedSections, totalSections)).Value(&manageSections),
 			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
 		),
 	)
-	err := form.Run()
+	err = runForm(form)
 	if err != nil {
 		return Video{}, err
 	}
-	video.Work.Completed, video.Work.Total = c.Count([]interface{}{
-		video.Code,
-		video.Screen,
-		video.Head,
-		video.RelatedVideos,
-		video.Thumbnails,
-		video.Diagrams,
-		video.Location,
-		video.Tagline,
-		video.TaglineIdeas,
-		video.OtherLogos,
-		video.Screenshots,
-	})
+	if suggestRelated {
+		if err := c.ChooseRelatedVideos(&video); err != nil {
+			return Video{}, err
+		}
+	}
+	if missing := getMissingAssets(assets); settings.Assets.RequireForMaterialDone && len(missing) > 0 && video.Diagrams {
+		video.Diagrams = false
+		output.Error(fmt.Sprintf("Diagrams cannot be done while %d referenced assets are missing:\n%s", len(missing), getAssetChecklist(missing)))
+	}
+	video.Work = getBuiltInPhaseProgress(video, customFieldPhaseWork, settings)
 	if save {
 		yaml := YAML{}
-		yaml.WriteVideo(video, video.Path)
+		yaml.WriteVideo(&video, video.Path)
+		// Environments are saved as they are logged so they are managed only after the rest of the details are saved.
+		if manageDemos {
+			if err := c.ChooseDemoEnvironments(&video, time.Now()); err != nil {
+				return video, err
+			}
+		}
+		if manageSections {
+			if err := c.ChooseSections(&video); err != nil {
+				return video, err
+			}
+		}
 	}
 	return video, err
 }
 
+// ChooseDemoEnvironments logs the creation and the destruction of demo environments until the user is done.
+func (c *Choices) ChooseDemoEnvironments(video *Video, now time.Time) error {
+	const demoNew = -1
+	const demoDone = -2
+	for {
+		selected := demoDone
+		options := []huh.Option[int]{huh.NewOption("Log a new environment", demoNew)}
+		for i, environment := range video.DemoEnvironments {
+			if !environment.Destroyed {
+				options = append(options, huh.NewOption(fmt.Sprintf("Destroyed: %s", getDemoTitle(environment)), i))
+			}
+		}
+		options = append(options, huh.NewOption("Done", demoDone))
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().Title("Demo environments").Options(options...).Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch selected {
+		case demoDone:
+			return nil
+		case demoNew:
+			environment := DemoEnvironment{Created: now.Format(dayFormat)}
+			cost := ""
+			form := newForm(
+				huh.NewGroup(
+					huh.NewInput().Title("Provider (e.g., AWS, Google Cloud, Azure)").Value(&environment.Provider).Validate(c.IsEmpty),
+					huh.NewInput().Title("Description").Value(&environment.Description),
+					huh.NewInput().Title("Created (YYYY-MM-DD)").Value(&environment.Created).Validate(func(value string) error {
+						_, err := time.Parse(dayFormat, value)
+						return err
+					}),
+					huh.NewInput().Title("Estimated monthly cost").Value(&cost).Validate(func(value string) error {
+						if len(value) == 0 {
+							return nil
+						}
+						_, err := strconv.ParseFloat(value, 64)
+						return err
+					}),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+			environment.MonthlyCost, _ = strconv.ParseFloat(cost, 64)
+			video.DemoEnvironments = append(video.DemoEnvironments, environment)
+		default:
+			video.DemoEnvironments[selected].Destroyed = true
+			video.DemoEnvironments[selected].DestroyedDate = now.Format(dayFormat)
+		}
+		yaml := YAML{}
+		yaml.WriteVideo(video, video.Path)
+	}
+}
+
+// ChooseSections toggles the recorded and edited flags of the manuscript sections. Sections are synced with the manuscript first.
+func (c *Choices) ChooseSections(video *Video) error {
+	content, _, err := readManuscript(video.Gist)
+	if err != nil {
+		return err
+	}
+	sections := syncSections(video.Sections, getManuscriptHeadings(content, getAnimationOptions(settings.Animations).HeaderLevels))
+	if len(sections) == 0 {
+		return fmt.Errorf("there are no sections in %s", video.Gist)
+	}
+	options := huh.NewOptions[int]()
+	recorded, edited := []int{}, []int{}
+	for i, section := range sections {
+		options = append(options, huh.NewOption(getSectionTitle(section), i))
+		if section.Recorded {
+			recorded = append(recorded, i)
+		}
+		if section.Edited {
+			edited = append(edited, i)
+		}
+	}
+	orphaned := getOrphanedSections(sections)
+	removeOrphaned := false
+	fields := []huh.Field{
+		huh.NewMultiSelect[int]().Title("Recorded sections").Options(options...).Value(&recorded),
+		huh.NewMultiSelect[int]().Title("Edited sections").Options(options...).Value(&edited),
+	}
+	if len(orphaned) > 0 {
+		fields = append(fields, huh.NewConfirm().Title(fmt.Sprintf("Remove %d orphaned sections (no longer in the manuscript)", len(orphaned))).Value(&removeOrphaned))
+	}
+	if err := runForm(newForm(huh.NewGroup(fields...))); err != nil {
+		return err
+	}
+	video.Sections = []ManuscriptSection{}
+	for i, section := range sections {
+		if removeOrphaned && section.Orphaned {
+			continue
+		}
+		section.Recorded = slices.Contains(recorded, i)
+		section.Edited = slices.Contains(edited, i)
+		video.Sections = append(video.Sections, section)
+	}
+	*video = applySectionsRecorded(*video, settings.Record.SectionsSetRecorded)
+	video.Work = getBuiltInPhaseProgress(*video, customFieldPhaseWork, settings)
+	yaml := YAML{}
+	yaml.WriteVideo(video, video.Path)
+	return nil
+}
+
+// ChooseAttributions adds and removes credits of reused assets and exports them to a file next to the manuscript.
+func (c *Choices) ChooseAttributions(video *Video) error {
+	const attributionNew = -1
+	const attributionExport = -2
+	const attributionDone = -3
+	for {
+		selected := attributionDone
+		options := []huh.Option[int]{huh.NewOption("Add an attribution", attributionNew)}
+		for i, attribution := range video.Attributions {
+			options = append(options, huh.NewOption(fmt.Sprintf("Remove: %s", getCreditLine(attribution)), i))
+		}
+		if len(video.Attributions) > 0 {
+			options = append(options, huh.NewOption("Export credits file", attributionExport))
+		}
+		options = append(options, huh.NewOption("Done", attributionDone))
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().Title("Attributions").Options(options...).Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch selected {
+		case attributionDone:
+			return nil
+		case attributionExport:
+			path, err := exportCredits(*video)
+			if err != nil {
+				return err
+			}
+			output.Info(fmt.Sprintf("Credits were written to %s.", path))
+			continue
+		case attributionNew:
+			attribution := Attribution{}
+			licenses := huh.NewOptions(settings.Credits.Licenses...)
+			form := newForm(
+				huh.NewGroup(
+					huh.NewInput().Title("Asset (e.g., the name of the song or the project)").Value(&attribution.Asset).Validate(c.IsEmpty),
+					huh.NewInput().Title("Author").Value(&attribution.Author),
+					huh.NewSelect[string]().Title("License").Options(licenses...).Value(&attribution.License),
+					huh.NewInput().Title("Source URL").Value(&attribution.SourceURL),
+					huh.NewInput().Title("Where it's used (e.g., intro music)").Value(&attribution.UsedIn),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+			if err := validateAttribution(attribution, settings.Credits); err != nil {
+				output.Error(err.Error())
+				continue
+			}
+			video.Attributions = append(video.Attributions, attribution)
+		default:
+			video.Attributions = append(video.Attributions[:selected:selected], video.Attributions[selected+1:]...)
+		}
+		yaml := YAML{}
+		yaml.WriteVideo(video, video.Path)
+	}
+}
+
+// confirmDemoAcknowledgment asks whether the video can be published while its demo environments are still running.
+var confirmDemoAcknowledgment = func(environments []DemoEnvironment) bool {
+	acknowledged := false
+	titles := []string{}
+	for _, environment := range environments {
+		titles = append(titles, getDemoTitle(environment))
+	}
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("%d demo environments are still running. Publish anyway?", len(environments))).
+				Description(strings.Join(titles, "\n")).
+				Affirmative("I know, publish").
+				Negative("Cancel").
+				Value(&acknowledged),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return false
+	}
+	return acknowledged
+}
+
 func (c *Choices) ChooseFabric(video *Video, field *string, fieldName, pattern string, addToField bool) error {
+	return c.ChooseFabricWithContext(video, field, fieldName, pattern, "", addToField)
+}
+
+// ChooseFabricWithContext works like ChooseFabric but prepends additionalContext (e.g., the chosen title) to the manuscript sent to fabric.
+func (c *Choices) ChooseFabricWithContext(video *Video, field *string, fieldName, pattern, additionalContext string, addToField bool) error {
 	askAgain := true
-	content, err := os.ReadFile(video.Gist)
+	content, _, err := readManuscript(video.Gist)
 	if err != nil {
 		return err
 	}
+	if len(additionalContext) > 0 {
+		content = fmt.Sprintf("%s\n\n%s", additionalContext, content)
+	}
 	firstIteration := true
 	output := ""
+	suggestion := ""
+	// Changes are shown against the text the field had before the first suggestion, not against the previous suggestion.
+	original := *field
+	var changes []DiffRun
 	for askAgain || firstIteration {
 		askAgain = false
 		if firstIteration {
 			firstIteration = false
 		} else {
-			cmd := exec.Command("fabric", "--pattern", pattern, string(content))
+			cmd := exec.Command("fabric", "--pattern", pattern, content)
 			outputBytes, err := cmd.Output()
 			if err != nil {
 				return fmt.Errorf("%s\n%s", err.Error(), string(outputBytes))
 			}
 			output = string(outputBytes)
 			output = strings.ReplaceAll(output, "TAGS:", "")
+			suggestion = output
 			if addToField {
+				changes = nil
+				if len(strings.TrimSpace(original)) > 0 {
+					changes = utils.GetWordDiff(original, output)
+				}
 				*field = output
 			}
 		}
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewText().Lines(20).CharLimit(10000).Title(c.ColorFromString(fieldName, *field)).Value(field),
-				huh.NewText().Lines(20).CharLimit(10000).Title("AI Responses").Value(&output),
-				huh.NewConfirm().Affirmative("Ask").Negative("Save & Continue").Value(&askAgain),
-			).Title(fieldName),
-		)
-		err = form.Run()
+		fieldText := huh.NewText().Lines(20).CharLimit(10000).Title(c.ColorFromField(customFieldPhaseDefine, fieldName, fieldName, len(*field) > 0)).Value(field)
+		if hint, ok := fieldHints[fieldName]; ok {
+			fieldText = withTextHint(fieldText, field, hint)
+		}
+		fields := []huh.Field{
+			fieldText,
+			huh.NewText().Lines(20).CharLimit(10000).Title("AI Responses").Value(&output),
+		}
+		if utils.HasDiffChanges(changes) {
+			fields = append(fields, huh.NewNote().Title("Changes").Description(renderWordDiff(changes)))
+		}
+		fields = append(fields, huh.NewConfirm().Affirmative("Ask").Negative("Save & Continue").Value(&askAgain))
+		form := newForm(huh.NewGroup(fields...).Title(fieldName))
+		err = runForm(form)
 		if err != nil {
 			return err
 		}
 	}
+	if len(suggestion) > 0 {
+		if !addToField {
+			suggestion = getClosestSuggestion(suggestion, *field)
+		}
+		recordAIFeedback(aiFeedbackPath, newAIFeedbackEntry(pattern, fieldName, suggestion, *field, *video))
+	}
 	yaml := YAML{}
-	yaml.WriteVideo(*video, video.Path)
+	yaml.WriteVideo(video, video.Path)
 	return nil
 }
 
@@ -432,7 +944,7 @@ func (c *Choices) ChooseFabric(video *Video, field *string, fieldName, pattern s
 // 			}
 // 			question = ""
 // 		}
-// 		form := huh.NewForm(
+// 		form := newForm(
 // 			huh.NewGroup(
 // 				huh.NewText().Lines(20).CharLimit(10000).Title(c.ColorFromString(fieldName, *field)).Value(field),
 // 				huh.NewText().Lines(20).CharLimit(10000).Title("AI Responses").Value(&history),
@@ -440,22 +952,36 @@ func (c *Choices) ChooseFabric(video *Video, field *string, fieldName, pattern s
 // 				huh.NewConfirm().Affirmative("Ask").Negative("Save & Continue").Value(&askAgain),
 // 			).Title(fieldName),
 // 		)
-// 		err := form.Run()
+// 		err := runForm(form)
 // 		if err != nil {
 // 			return err
 // 		}
 // 	}
 // 	yaml := YAML{}
-// 	yaml.WriteVideo(*video, video.Path)
+// 	yaml.WriteVideo(video, video.Path)
 // 	return nil
 // }
 
 func (c *Choices) ChooseDefine(video Video) (Video, error) {
+	if err := c.ConfirmCreateManuscript(&video); err != nil {
+		return video, err
+	}
+	output.Print(getQualityScoreText(getQualityScore(video, settings.Quality.Weights, settings.Tags.Aliases)))
+	// Target keywords are chosen before the title so that the title can include them.
+	if err := c.ChooseTargetKeywords(&video); err != nil {
+		return video, err
+	}
+
 	// Title
 	if err := c.ChooseFabric(&video, &video.Title, "Title", "title_dot", false); err != nil {
 		return video, err
 	}
 
+	// Slug
+	if err := c.ChooseSlug(&video); err != nil {
+		return video, err
+	}
+
 	// Description
 	if err := c.ChooseFabric(&video, &video.Description, "Description", "description_dot", true); err != nil {
 		return video, err
@@ -465,9 +991,12 @@ func (c *Choices) ChooseDefine(video Video) (Video, error) {
 	if err := c.ChooseFabric(&video, &video.Highlight, "Highlight", "highlight_dot", true); err != nil {
 		return video, err
 	}
+	if err := c.ChooseHighlightTimestamp(&video); err != nil {
+		return video, err
+	}
 
 	// Tags
-	if err := c.ChooseFabric(&video, &video.Tags, "Tags", "tags_dot", true); err != nil {
+	if err := c.ChooseTags(&video); err != nil {
 		return video, err
 	}
 
@@ -482,443 +1011,2832 @@ func (c *Choices) ChooseDefine(video Video) (Video, error) {
 		return video, err
 	}
 
+	// Intro
+	if err := c.ChooseFabricWithContext(&video, &video.Intro, "Intro", "intro_dot", fmt.Sprintf("Title: %s", video.Title), true); err != nil {
+		return video, err
+	}
+
+	// Outro
+	if err := c.ChooseFabricWithContext(&video, &video.Outro, "Outro", "outro_dot", fmt.Sprintf("Title: %s", video.Title), true); err != nil {
+		return video, err
+	}
+
 	// Animations
 	generateAnimations := true
 	for generateAnimations {
 		generateAnimations = false
 		video.Animations = strings.TrimSpace(video.Animations)
-		formAnimations := huh.NewForm(
+		formAnimations := newForm(
 			huh.NewGroup(
-				huh.NewText().Lines(40).CharLimit(10000).Title(c.ColorFromString("Animations", video.Animations)).Value(&video.Animations).Editor("vi"),
+				huh.NewText().Lines(40).CharLimit(10000).Title(c.ColorFromField(customFieldPhaseDefine, "Animations", "Animations", len(video.Animations) > 0)).Value(&video.Animations).Editor("vi"),
 				huh.NewConfirm().Affirmative("Generate").Negative("Continue").Value(&generateAnimations),
 			).Title("Animations"),
 		)
-		err := formAnimations.Run()
+		err := runForm(formAnimations)
 		if err != nil {
 			return Video{}, err
 		}
 		if generateAnimations {
-			video.Animations = ""
-			video.Timecodes = ""
-			repo := Repo{}
-			linesSlice, sectionsSlice, err := repo.GetAnimations(video.Gist)
-			if err != nil {
-				panic(err)
-			}
-			if err != nil {
+			if err := c.ChooseGenerateAnimations(&video); err != nil {
 				return Video{}, err
 			}
-			for _, line := range linesSlice {
-				video.Animations = fmt.Sprintf("%s\n- %s", video.Animations, line)
-			}
-			if len(video.Timecodes) == 0 {
-				video.Timecodes = "00:00 TODO:"
-				for _, section := range sectionsSlice {
-					video.Timecodes = fmt.Sprintf("%s\nTODO:TODO %s", video.Timecodes, strings.TrimLeft(section, "Section: "))
-				}
-			}
 		}
 	}
+	// Thumbnail text
+
 	// Thumbnail
 	save := true
+	exportTeleprompter := false
 	requestThumbnailOrig := video.RequestThumbnail
-	form := huh.NewForm(
+	form := newForm(
 		huh.NewGroup(
-			huh.NewConfirm().Title(c.ColorFromBool("Thumbnail request", video.RequestThumbnail)).Value(&video.RequestThumbnail),
+			huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseDefine, "Thumbnail request", "Thumbnail request", video.RequestThumbnail)).Value(&video.RequestThumbnail),
+			huh.NewConfirm().Title("Age restricted").Value(&video.ContentFlags.AgeRestricted),
+			huh.NewConfirm().Title("Contains security exploits").Value(&video.ContentFlags.ContainsSecurityExploits),
+			huh.NewText().Lines(3).CharLimit(1000).Title("Disclaimer (the configured one is used if empty)").Value(&video.ContentFlags.DisclaimerText),
+			huh.NewConfirm().Title("Export teleprompter script").Value(&exportTeleprompter),
 			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
 		),
 	)
-	err := form.Run()
+	err := runForm(form)
 	if err != nil {
 		return Video{}, err
 	}
-	video.Define.Completed, video.Define.Total = c.Count([]interface{}{
-		video.Title,
-		video.Description,
-		video.Tags,
-		video.DescriptionTags,
-		video.RequestThumbnail,
-		video.Gist,
-		video.Animations,
-		video.Tweet,
-	})
+	video.Define = getBuiltInPhaseProgress(video, customFieldPhaseDefine, settings)
+	if exportTeleprompter {
+		teleprompterPath, err := exportTeleprompterScript(video, settings.Teleprompter.LineWidth, settings.Teleprompter.HTML)
+		if err != nil {
+			return video, err
+		}
+		output.Info(fmt.Sprintf("Teleprompter script was written to %s.", teleprompterPath))
+	}
 	if !requestThumbnailOrig && video.RequestThumbnail {
 		email := NewEmail(settings.Email.Password)
-		if email.SendThumbnail(settings.Email.From, settings.Email.ThumbnailTo, video) != nil {
-			panic(err)
+		ctx, cancel := newEmailContext()
+		defer cancel()
+		err = email.SendThumbnail(ctx, settings.Email.From, settings.Email.ThumbnailTo, video)
+		recordAuditAction(video.Path, auditActionEmailThumbnail, err)
+		if err != nil {
+			// The rest of the changes are saved and the request is sent again the next time it is selected.
+			video.RequestThumbnail = false
+			err = fmt.Errorf("the thumbnail request was not sent: %w", err)
 		}
 	}
 	if save {
 		yaml := YAML{}
-		yaml.WriteVideo(video, video.Path)
+		yaml.WriteVideo(&video, video.Path)
 	}
 	return video, err
 }
 
-func (c *Choices) ChooseEdit(video Video) (Video, error) {
-	save := true
-	requestEditOrig := video.RequestEdit
-	timeCodesTitle := "Timecodes"
-	if strings.Contains(video.Timecodes, "TODO:") {
-		timeCodesTitle = redStyle.Render(timeCodesTitle)
-	} else {
-		timeCodesTitle = greenStyle.Render(timeCodesTitle)
+// appendKeepCurrentOption adds the option to keep the current value unless it is empty or already one of the options.
+func appendKeepCurrentOption(options []huh.Option[string], current string) []huh.Option[string] {
+	if len(current) == 0 || slices.ContainsFunc(options, func(option huh.Option[string]) bool { return option.Value == current }) {
+		return options
 	}
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().Title(c.ColorFromString("Thumbnail 1 Path", video.Thumbnail)).Value(&video.Thumbnail),
-			huh.NewInput().Title(c.ColorFromString("Thumbnail 2 Path", video.Thumbnail02)).Value(&video.Thumbnail02),
-			huh.NewInput().Title(c.ColorFromString("Thumbnail 3 Path", video.Thumbnail03)).Value(&video.Thumbnail03),
-			huh.NewInput().Title(c.ColorFromString("Members (comma separated)", video.Members)).Value(&video.Members),
-			huh.NewConfirm().Title(c.ColorFromBool("Edit Request", video.RequestEdit)).Value(&video.RequestEdit),
-			huh.NewText().Lines(5).CharLimit(10000).Title(timeCodesTitle).Value(&video.Timecodes),
-			huh.NewConfirm().Title(c.ColorFromBool("Movie Done", video.Movie)).Value(&video.Movie),
-			huh.NewConfirm().Title(c.ColorFromBool("Slides Done", video.Slides)).Value(&video.Slides),
-			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
-		),
-	)
-	err := form.Run()
-	if err != nil {
-		return Video{}, err
+	return append(options, huh.NewOption(fmt.Sprintf("Keep current (%s)", current), current))
+}
+
+// ChooseThumbnailText edits the text shown on the thumbnail with AI suggestions.
+func (c *Choices) ChooseThumbnailText(video *Video) error {
+	const thumbnailTextActionContinue = 0
+	const thumbnailTextActionAsk = 1
+	for {
+		action := thumbnailTextActionContinue
+		form := newForm(
+			huh.NewGroup(
+				huh.NewInput().Title(c.ColorFromField(customFieldPhaseEdit, "Thumbnail text", "Thumbnail text", len(video.ThumbnailText) > 0)).Validate(validateThumbnailText).Value(&video.ThumbnailText),
+				huh.NewSelect[int]().
+					Options(
+						huh.NewOption("Save & Continue", thumbnailTextActionContinue),
+						huh.NewOption("Ask AI", thumbnailTextActionAsk),
+					).
+					Value(&action),
+			).Title("Thumbnail Text"),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch action {
+		case thumbnailTextActionContinue:
+			yaml := YAML{}
+			yaml.WriteVideo(video, video.Path)
+			return nil
+		case thumbnailTextActionAsk:
+			suggestions, err := c.getThumbnailTextSuggestions(*video)
+			if err != nil {
+				output.Error(err.Error())
+				continue
+			}
+			options := huh.NewOptions[string]()
+			for _, suggestion := range suggestions {
+				options = append(options, huh.NewOption(fmt.Sprintf("%s (%s)", suggestion.Text, suggestion.Notes), suggestion.Text))
+			}
+			options = appendKeepCurrentOption(options, video.ThumbnailText)
+			form := newForm(
+				huh.NewGroup(
+					huh.NewSelect[string]().
+						Title("Which thumbnail text would you like to use?").
+						Options(options...).
+						Value(&video.ThumbnailText),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *Choices) getThumbnailTextSuggestions(video Video) ([]ThumbnailTextSuggestion, error) {
+	content, _, err := readManuscript(video.Gist)
+	if err != nil {
+		return nil, err
+	}
+	content = fmt.Sprintf("Title: %s\nTagline: %s\n\n%s", video.Title, video.Tagline, content)
+	parsed := []ThumbnailTextSuggestion{}
+	if err := runFabricJSON("thumbnail_text_dot", content, &parsed); err != nil {
+		return nil, err
+	}
+	return getValidThumbnailTextSuggestions(parsed), nil
+}
+
+// ChooseTargetKeywords edits the search phrases the video targets with AI suggestions based on the manuscript.
+func (c *Choices) ChooseTargetKeywords(video *Video) error {
+	for {
+		ask := false
+		form := newForm(
+			huh.NewGroup(
+				huh.NewInput().Title(c.ColorFromString("Target keywords (comma separated, primary first)", video.TargetKeywords)).Value(&video.TargetKeywords),
+				huh.NewConfirm().Affirmative("Ask AI").Negative("Save & Continue").Value(&ask),
+			).Title("Target Keywords"),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		if !ask {
+			yaml := YAML{}
+			yaml.WriteVideo(video, video.Path)
+			return nil
+		}
+		suggestions, err := c.getKeywordSuggestions(*video)
+		if err != nil {
+			output.Error(err.Error())
+			continue
+		}
+		options := huh.NewOptions[string]()
+		for _, suggestion := range suggestions {
+			options = append(options, huh.NewOption(fmt.Sprintf("%s (%s)", suggestion.Keyword, suggestion.Notes), suggestion.Keyword))
+		}
+		selected := []string{}
+		form = newForm(
+			huh.NewGroup(
+				huh.NewMultiSelect[string]().
+					Title("Which keywords would you like to target? The first one in the list becomes the primary keyword.").
+					Options(options...).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		if len(selected) > 0 {
+			video.TargetKeywords = strings.Join(selected, ",")
+		}
+	}
+}
+
+func (c *Choices) getKeywordSuggestions(video Video) ([]KeywordSuggestion, error) {
+	content, _, err := readManuscript(video.Gist)
+	if err != nil {
+		return nil, err
+	}
+	content = fmt.Sprintf("Title: %s\nTags: %s\n\n%s", video.Title, video.Tags, content)
+	parsed := []KeywordSuggestion{}
+	if err := runFabricJSON("keywords_dot", content, &parsed); err != nil {
+		return nil, err
+	}
+	return getValidKeywordSuggestions(parsed), nil
+}
+
+// ChooseSlug edits the slug of the Hugo post. It starts as the one derived from the title so that it changes with the title until it's overridden.
+func (c *Choices) ChooseSlug(video *Video) error {
+	yaml := YAML{IndexPath: "index.yaml"}
+	vi := yaml.GetIndex()
+	// The slugs of the other videos come from the phase summary so that only the videos that changed are read.
+	others := map[string]string{}
+	for i, entry := range c.getPhaseSummaryEntries(vi) {
+		if c.GetFilePath(vi[i].Category, vi[i].Name, "yaml") != video.Path {
+			others[entry.Slug] = vi[i].Name
+		}
+	}
+	slug := getVideoSlug(*video)
+	form := newForm(
+		huh.NewGroup(
+			huh.NewInput().Title("Slug of the Hugo post").Value(&slug).Validate(func(value string) error {
+				return validateSlug(value, others)
+			}),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if slug == getSlug(video.Title) {
+		slug = ""
+	}
+	video.Slug = slug
+	yaml.WriteVideo(video, video.Path)
+	return nil
+}
+
+// ChooseCustomFields shows the custom fields declared for the phase and adds them to the phase tasks.
+func (c *Choices) ChooseCustomFields(video *Video, phase string, tasks *Tasks) error {
+	customFields := getPhaseCustomFields(settings.CustomFields, phase)
+	if len(customFields) == 0 {
+		return nil
+	}
+	if video.CustomFields == nil {
+		video.CustomFields = map[string]string{}
+	}
+	save := true
+	values := make([]string, len(customFields))
+	bools := make([]bool, len(customFields))
+	fields := []huh.Field{}
+	for i, customField := range customFields {
+		values[i] = video.CustomFields[customField.Key]
+		title := c.ColorFromString(customField.Label, values[i])
+		switch customField.Type {
+		case customFieldTypeBool:
+			bools[i], _ = strconv.ParseBool(values[i])
+			fields = append(fields, huh.NewConfirm().Title(c.ColorFromBool(customField.Label, bools[i])).Value(&bools[i]))
+		case customFieldTypeSelect:
+			fields = append(fields, huh.NewSelect[string]().Title(title).Options(huh.NewOptions(append([]string{""}, customField.Options...)...)...).Value(&values[i]))
+		default:
+			fields = append(fields, huh.NewInput().Title(title).Value(&values[i]).Validate(func(value string) error {
+				return validateCustomFieldValue(customField, value)
+			}))
+		}
+	}
+	fields = append(fields, huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save))
+	form := newForm(huh.NewGroup(fields...))
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if save {
+		for i, customField := range customFields {
+			if customField.Type == customFieldTypeBool {
+				values[i] = strconv.FormatBool(bools[i])
+			}
+			video.CustomFields[customField.Key] = values[i]
+		}
+	}
+	completed, total := getCustomFieldsCompletion(settings.CustomFields, video.CustomFields, phase)
+	tasks.Completed += completed
+	tasks.Total += total
+	if save {
+		yaml := YAML{}
+		yaml.WriteVideo(video, video.Path)
+	}
+	return nil
+}
+
+func (c *Choices) ChooseHighlightTimestamp(video *Video) error {
+	duration := getVideoDuration(*video)
+	form := newForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(c.ColorFromString("Highlight timestamp (e.g., 12:34)", video.HighlightTimestamp)).
+				Description("Added to timecodes as a chapter and used for [HIGHLIGHT] links.").
+				Value(&video.HighlightTimestamp).
+				Validate(func(value string) error {
+					return validateHighlightTimestamp(value, duration)
+				}),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	yaml := YAML{}
+	yaml.WriteVideo(video, video.Path)
+	return nil
+}
+
+func (c *Choices) ChooseThumbnail(video *Video) error {
+	candidates, err := getThumbnailCandidates(getMaterialDir(*video))
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	if len(candidates) == 1 && len(video.Thumbnail) == 0 {
+		video.Thumbnail = candidates[0]
+		return nil
+	}
+	if len(candidates) == 1 && candidates[0] == video.Thumbnail {
+		return nil
+	}
+	selected := video.Thumbnail
+	options := huh.NewOptions[string]()
+	for _, candidate := range candidates {
+		options = append(options, huh.NewOption(candidate, candidate))
+	}
+	options = appendKeepCurrentOption(options, video.Thumbnail)
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Which thumbnail would you like to use?").
+				Options(options...).
+				Value(&selected),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	video.Thumbnail = selected
+	return nil
+}
+
+// ChooseReplaceThumbnail uploads a new thumbnail for a published video. The video is written only if the upload succeeds.
+func (c *Choices) ChooseReplaceThumbnail(video Video) error {
+	candidates, err := getThumbnailCandidates(getMaterialDir(video))
+	if err != nil {
+		return err
+	}
+	const otherPath = ""
+	selected := otherPath
+	options := huh.NewOptions[string]()
+	for _, candidate := range candidates {
+		if candidate != video.Thumbnail {
+			options = append(options, huh.NewOption(candidate, candidate))
+		}
+	}
+	options = append(options, huh.NewOption("Other path", otherPath))
+	form := newForm(
+		huh.NewGroup(
+			huh.NewNote().Title(fmt.Sprintf("Current thumbnail: %s", video.Thumbnail)).Description(getThumbnailHistoryText(video.ThumbnailHistory)),
+			huh.NewSelect[string]().Title("Which thumbnail would you like to use instead?").Options(options...).Value(&selected),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if selected == otherPath {
+		form := newForm(
+			huh.NewGroup(
+				huh.NewInput().Title("Path to the new thumbnail").Value(&selected).Validate(validateThumbnail),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+	}
+	video, err = replaceThumbnail(video, selected, youTubePublisher{}, time.Now())
+	if err != nil {
+		return err
+	}
+	yaml := YAML{}
+	yaml.WriteVideo(&video, video.Path)
+	output.Info(fmt.Sprintf("The thumbnail of %s was replaced with %s.", video.Name, selected))
+	return nil
+}
+
+// ChooseRecordSession walks through the recording checklist one item at a time. Stopping keeps the session, with the time spent and the confirmed items, so that it can be resumed.
+func (c *Choices) ChooseRecordSession(video Video) error {
+	if phase := c.getPhase(video); phase != videosPhaseStarted {
+		return fmt.Errorf("recording sessions are available only for videos in the Started phase")
+	}
+	history := getRecordingSessionsText(video.RecordingSessions)
+	video, index, resumed := startRecordingSession(video, time.Now())
+	start := time.Now()
+	yaml := YAML{}
+	stop := func() {
+		video = pauseRecordingSession(video, index, time.Since(start))
+		yaml.WriteVideo(&video, video.Path)
+		output.Info(fmt.Sprintf("The recording session of %s was stopped. Choose Record session again to resume it.", video.Name))
+	}
+	manuscript, _, _ := readManuscript(video.Gist)
+	checklist := getRecordChecklist(video, manuscript, settings.Record)
+	remaining := getRemainingRecordChecklist(checklist, video.RecordingSessions[index])
+	if resumed {
+		output.Info(fmt.Sprintf("Resuming the session started on %s with %d of %d items remaining.", video.RecordingSessions[index].Started, len(remaining), len(checklist)))
+	}
+	for i, item := range remaining {
+		done := true
+		description := ""
+		if i == 0 {
+			description = fmt.Sprintf("Sessions:\n%s", history)
+		}
+		if item.Automatic {
+			status := "✔"
+			if !item.Passed {
+				status = "✘"
+			}
+			description = strings.TrimSpace(fmt.Sprintf("%s %s\n\n%s", status, item.Detail, description))
+		}
+		form := newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(c.ColorFromBool(fmt.Sprintf("%d/%d %s", len(checklist)-len(remaining)+i+1, len(checklist), item.Title), !item.Automatic || item.Passed)).
+					Description(description).
+					Affirmative("Done").
+					Negative("Stop for now").
+					Value(&done),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		if !done {
+			stop()
+			return nil
+		}
+		video.RecordingSessions[index].Checked = append(video.RecordingSessions[index].Checked, item.Title)
+	}
+	head, screen, finish := video.Head, video.Screen, true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewNote().Title("Recording").Description(fmt.Sprintf("The session timer has been running since %s.", start.Format("15:04"))),
+			huh.NewConfirm().Title("Talking head was recorded").Value(&head),
+			huh.NewConfirm().Title("Screen was recorded").Value(&screen),
+			huh.NewConfirm().Affirmative("Finish the session").Negative("Stop for now").Value(&finish),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !finish {
+		stop()
+		return nil
+	}
+	video = completeRecordingSession(video, index, time.Since(start), head, screen, time.Now())
+	yaml.WriteVideo(&video, video.Path)
+	output.Info(video.Notes[len(video.Notes)-1])
+	return nil
+}
+
+func (c *Choices) ChooseEdit(video Video) (Video, error) {
+	if err := c.ChooseThumbnailText(&video); err != nil {
+		return Video{}, err
+	}
+	if err := c.ChooseThumbnail(&video); err != nil {
+		return Video{}, err
+	}
+	save := true
+	syncMembers := false
+	requestEditOrig := video.RequestEdit
+	timeCodesTitle := "Timecodes"
+	if strings.Contains(video.Timecodes, "TODO:") {
+		timeCodesTitle = redStyle.Render(timeCodesTitle)
+	} else {
+		timeCodesTitle = greenStyle.Render(timeCodesTitle)
+	}
+	fields := []huh.Field{
+		huh.NewInput().Title(getThumbnailTitle("Thumbnail 1 Path", video.Thumbnail)).Value(&video.Thumbnail),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseEdit, "Thumbnail 02", "Thumbnail 2 Path", len(video.Thumbnail02) > 0)).Value(&video.Thumbnail02),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseEdit, "Thumbnail 03", "Thumbnail 3 Path", len(video.Thumbnail03) > 0)).Value(&video.Thumbnail03),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhaseEdit, "Members", "Members (comma separated)", len(video.Members) > 0)).Value(&video.Members),
+	}
+	// Syncing needs the memberships scope, which is requested only when members.sync is enabled.
+	if settings.Members.Sync {
+		fields = append(fields, huh.NewConfirm().Title("Sync members from YouTube").Value(&syncMembers))
+	}
+	fields = append(fields,
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseEdit, "Edit request", "Edit Request", video.RequestEdit)).Value(&video.RequestEdit),
+		huh.NewText().Lines(5).CharLimit(10000).Title(timeCodesTitle).Value(&video.Timecodes),
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseEdit, "Movie", "Movie Done", video.Movie)).Value(&video.Movie),
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhaseEdit, "Slides", "Slides Done", video.Slides)).Value(&video.Slides),
+		huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
+	)
+	form := newForm(huh.NewGroup(fields...))
+	err := runForm(form)
+	if err != nil {
+		return Video{}, err
+	}
+	video.Members = formatMembers(parseMembers(video.Members))
+	if syncMembers {
+		if err := c.ChooseSyncMembers(&video); err != nil {
+			output.Error(err.Error())
+		}
+	}
+	if len(video.Timecodes) > 0 && video.Timecodes != "N/A" {
+		if err := c.ChooseChapters(&video); err != nil {
+			return Video{}, err
+		}
+	}
+	if save {
+		yaml := YAML{}
+		yaml.WriteVideo(&video, video.Path)
+	}
+	video.Edit = getBuiltInPhaseProgress(video, customFieldPhaseEdit, settings)
+	if !requestEditOrig && video.RequestEdit {
+		email := NewEmail(settings.Email.Password)
+		ctx, cancel := newEmailContext()
+		defer cancel()
+		err = email.SendEdit(ctx, settings.Email.From, settings.Email.EditTo, video)
+		recordAuditAction(video.Path, auditActionEmailEdit, err)
+		if err != nil {
+			return video, err
+		}
+	}
+	if save {
+		yaml := YAML{}
+		yaml.WriteVideo(&video, video.Path)
+	}
+	return video, err
+}
+
+func (c *Choices) ChooseChapters(video *Video) error {
+	const chaptersActionValidate = 0
+	const chaptersActionFix = 1
+	for {
+		selected := actionReturn
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title("Timecodes").
+					Options(
+						huh.NewOption("Validate chapters", chaptersActionValidate),
+						huh.NewOption("Auto-fix", chaptersActionFix),
+						huh.NewOption("Continue", actionReturn),
+					).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		chapters, violations := getChapterViolations(video.Timecodes)
+		switch selected {
+		case actionReturn:
+			return nil
+		case chaptersActionValidate:
+			if len(violations) == 0 {
+				output.Info("Chapters follow YouTube rules.")
+			}
+			for _, violation := range violations {
+				output.Error(violation.String())
+			}
+		case chaptersActionFix:
+			blocked := false
+			for _, violation := range violations {
+				if violation.Blocker && violation.Line > 0 {
+					output.Error(violation.String())
+					blocked = true
+				}
+			}
+			if blocked {
+				output.Error("Fix the lines above before using auto-fix.")
+				continue
+			}
+			video.Timecodes = formatChapters(fixChapters(chapters))
+			output.Result(video.Timecodes)
+		}
+	}
+}
+
+func (c *Choices) ChoosePublish(video Video) (Video, error) {
+	save := true
+	sponsorsNotifyText := "Sponsors notify"
+	notifiedSponsorsOrig := video.NotifiedSponsors
+	if workflow.IsSponsorNotified(video) {
+		sponsorsNotifyText = greenStyle.Render(sponsorsNotifyText)
+	} else {
+		sponsorsNotifyText = redStyle.Render(sponsorsNotifyText)
+	}
+	createHugo := video.HugoPath != "" || slices.Contains(video.PublishPending, publishStepHugo)
+	postReddit := isRedditPosted(video.RedditPosted, settings.Reddit.Subreddits)
+	manageClips := false
+	manageTalks := false
+	managePodcast := false
+	manageInvoice := false
+	fields := []huh.Field{
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Hugo post", "Create Hugo Post", createHugo)).Value(&createHugo),
+		huh.NewSelect[string]().Title("Visibility").Options(
+			huh.NewOption("Default", ""),
+			huh.NewOption("Scheduled", visibilityScheduled),
+			huh.NewOption("Private", visibilityPrivate),
+			huh.NewOption("Unlisted", visibilityUnlisted),
+			huh.NewOption("Public", visibilityPublic),
+		).Value(&video.Visibility),
+		huh.NewSelect[string]().Title("Made for kids").Options(
+			huh.NewOption("Default", ""),
+			huh.NewOption("Yes", "true"),
+			huh.NewOption("No", "false"),
+		).Value(&video.MadeForKids),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhasePublish, "Upload video", "Upload video", len(video.UploadVideo) > 0)).Value(&video.UploadVideo),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Tweet posted", "Twitter post", video.TweetPosted)).Value(&video.TweetPosted),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "LinkedIn post", "LinkedIn post", video.LinkedInPosted)).Value(&video.LinkedInPosted),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Slack post", "Slack post", video.SlackPosted)).Value(&video.SlackPosted),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Hacker News post", "Hacker News post", video.HNPosted)).Value(&video.HNPosted),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Technology Conversations post", "Technology Conversations post", video.TCPosted)).Value(&video.TCPosted),
+		huh.NewInput().Title("Reddit title (defaults to the video title)").Value(&video.RedditTitle),
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Reddit posts", fmt.Sprintf("Reddit post (%d/%d)", len(video.RedditPosted), len(settings.Reddit.Subreddits)), postReddit)).Value(&postReddit),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "YouTube highlight", "YouTube Highlight", video.YouTubeHighlight)).Value(&video.YouTubeHighlight),
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Pinned comment", "Pinned comment", video.YouTubeComment)).Value(&video.YouTubeComment),
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Replies to comments", "Replies to comments", video.YouTubeCommentReply)).Value(&video.YouTubeCommentReply),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "GDE post", "https://gde.advocu.com post", video.GDE)).Value(&video.GDE),
+		// TODO: Automate
+		huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Twitter Space post", "Twitter Spaces post", video.TwitterSpace)).Value(&video.TwitterSpace),
+		huh.NewInput().Title(c.ColorFromField(customFieldPhasePublish, "Code repository", "Code repo", len(video.Repo) > 0)).Value(&video.Repo),
+		huh.NewConfirm().Title(sponsorsNotifyText).Value(&video.NotifiedSponsors),
+		huh.NewConfirm().Title(fmt.Sprintf("Manage clips (%d)", len(video.Clips))).Value(&manageClips),
+		huh.NewConfirm().Title(fmt.Sprintf("Manage conference talks (%d)", len(video.Talks))).Value(&manageTalks),
+	}
+	if settings.Podcast.Enabled {
+		fields = append(fields, huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Podcast episode", "Manage podcast episode", workflow.IsPodcastPublished(video.Podcast))).Value(&managePodcast))
+	}
+	if workflow.IsSponsored(video.Sponsorship) {
+		fields = append(fields, huh.NewConfirm().Title(c.ColorFromBool("Manage invoice", isInvoicePaid(video.Sponsorship))).Value(&manageInvoice))
+	}
+	if isMastodonEnabled(settings.Mastodon) {
+		fields = append(fields, huh.NewConfirm().Title(c.ColorFromField(customFieldPhasePublish, "Mastodon post", "Mastodon post", video.MastodonPosted)).Value(&video.MastodonPosted))
+	}
+	for index := range fields {
+		uploadVideoOrig := video.UploadVideo
+		tweetPostedOrig := video.TweetPosted
+		linkedInPostedOrig := video.LinkedInPosted
+		slackPostedOrig := video.SlackPosted
+		mastodonPostedOrig := video.MastodonPosted
+		hnPostedOrig := video.HNPosted
+		tcPosted := video.TCPosted
+		twitterSpaceOrig := video.TwitterSpace
+		repoOrig := video.Repo
+		postRedditOrig := postReddit
+		form := newForm(
+			huh.NewGroup(
+				fields[index],
+				huh.NewConfirm().Affirmative("Save & continue").Negative("Cancel").Value(&save),
+			),
+		)
+		err := runForm(form)
+		if err != nil {
+			return Video{}, err
+		}
+		before := video
+		before.Repo = repoOrig
+		if running := requiresDemoAcknowledgment(before, video); len(running) > 0 && !confirmDemoAcknowledgment(running) {
+			video.Repo = repoOrig
+			output.Warn("The code repo was not saved so the video is not published.")
+		}
+		video.Publish = getBuiltInPhaseProgress(video, customFieldPhasePublish, settings)
+		if !createHugo {
+			video.HugoPath = ""
+		}
+		uploadRequested := len(uploadVideoOrig) == 0 && len(video.UploadVideo) > 0
+		if uploadRequested {
+			if uploadRequested, err = c.ConfirmUpload(video); err != nil {
+				return video, err
+			}
+			if !uploadRequested {
+				video.UploadVideo = uploadVideoOrig
+			}
+		}
+		result := publishVideo(&video, youTubePublisher{}, createHugo, uploadRequested)
+		for _, step := range result.Completed {
+			output.Event(outputActionPublish, video.Path, step, nil)
+		}
+		if result.Err != nil {
+			output.Event(outputActionPublish, video.Path, "", result.Err)
+			output.Error(fmt.Sprintf("%s\n%s", result.Err.Error(), getPublishResultMessage(result)))
+			yaml := YAML{}
+			yaml.WriteVideo(&video, video.Path)
+			return video, result.Err
+		}
+		if len(result.Completed) > 0 {
+			output.Info(getPublishResultMessage(result))
+		}
+		if slices.Contains(result.Completed, publishStepUpload) {
+			// TODO: Automate
+			output.Result(`Following should be set manually:
+- End screen
+- Playlists
+- Language
+- Monetization`)
+			if len(video.Supersedes) > 0 {
+				if old, err := readVideo(video.Supersedes); err == nil && len(old.VideoId) > 0 {
+					copySupersededComment(old, video)
+				}
+			}
+		}
+		twitter := Twitter{}
+		if !tweetPostedOrig && len(video.Tweet) > 0 && video.TweetPosted {
+			twitter.Post(replaceHighlightPlaceholder(video.Tweet, video.VideoId, video.HighlightTimestamp), video.VideoId)
+		}
+		if !linkedInPostedOrig && len(video.Tweet) > 0 && video.LinkedInPosted {
+			postLinkedIn(replaceHighlightPlaceholder(video.Tweet, video.VideoId, video.HighlightTimestamp), video.VideoId)
+		}
+		if !slackPostedOrig && len(video.VideoId) > 0 && video.SlackPosted {
+			if err := c.PostSlack(&video); err != nil {
+				output.Error(fmt.Sprintf("Slack: %s", err))
+				video.SlackPosted = false
+			}
+		}
+		if !mastodonPostedOrig && len(video.VideoId) > 0 && len(video.Tweet) > 0 && video.MastodonPosted {
+			err := c.PostMastodon(video)
+			recordAuditAction(video.Path, auditActionMastodon, err)
+			if err != nil {
+				output.Error(fmt.Sprintf("Mastodon: %s", err))
+				video.MastodonPosted = false
+			}
+		}
+		if !hnPostedOrig && len(video.VideoId) > 0 && video.HNPosted {
+			postHackerNews(video.Title, video.VideoId)
+		}
+		if !postRedditOrig && postReddit && len(video.VideoId) > 0 {
+			c.PostReddit(&video)
+			postReddit = isRedditPosted(video.RedditPosted, settings.Reddit.Subreddits)
+		}
+		if !tcPosted && len(video.VideoId) > 0 && video.TCPosted {
+			postTechnologyConversations(video.Title, video.Description, video.VideoId, video.Gist, video.ProjectName, getProjectURL(video), video.RelatedVideos)
+		}
+		if !twitterSpaceOrig && len(video.VideoId) > 0 && video.TwitterSpace {
+			twitter.PostSpace(video.VideoId)
+		}
+		if len(repoOrig) == 0 && len(video.Repo) > 0 && video.Repo != "N/A" {
+			repo := Repo{}
+			repo.Update(video.Repo, video.Title, video.VideoId)
+		}
+		if !notifiedSponsorsOrig && video.NotifiedSponsors {
+			email := NewEmail(settings.Email.Password)
+			ctx, cancel := newEmailContext()
+			err := email.SendSponsors(ctx, settings.Email.From, video.Sponsorship.Emails, video.VideoId, video.Sponsorship.Amount)
+			cancel()
+			recordAuditAction(video.Path, auditActionEmailSponsors, err)
+			if err != nil {
+				output.Error(err.Error())
+			}
+		}
+		if manageClips {
+			manageClips = false
+			if err := c.ChooseClips(&video); err != nil {
+				return video, err
+			}
+		}
+		if manageTalks {
+			manageTalks = false
+			if err := c.ChooseTalks(&video); err != nil {
+				return video, err
+			}
+		}
+		if managePodcast {
+			managePodcast = false
+			if err := c.ChoosePodcast(&video); err != nil {
+				return video, err
+			}
+			video.Publish = getBuiltInPhaseProgress(video, customFieldPhasePublish, settings)
+		}
+		if manageInvoice {
+			manageInvoice = false
+			// Invoice errors do not stop publishing since the rest of the video is already saved.
+			if err := c.ChooseInvoice(&video, time.Now()); err != nil {
+				output.Error(err.Error())
+			}
+		}
+		if !save {
+			break
+		}
+		yaml := YAML{}
+		yaml.WriteVideo(&video, video.Path)
+	}
+	return video, nil
+}
+
+// ChooseInvoice records the invoice of the sponsorship and, if it's overdue, offers to send a reminder to the sponsor.
+func (c *Choices) ChooseInvoice(video *Video, now time.Time) error {
+	sponsorship := video.Sponsorship
+	if len(sponsorship.InvoiceSentDate) == 0 {
+		sponsorship.InvoiceSentDate = now.Format(dayFormat)
+	}
+	if len(sponsorship.AmountInvoiced) == 0 {
+		sponsorship.AmountInvoiced = sponsorship.Amount
+	}
+	save := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewInput().Title(c.ColorFromString("Invoice number", sponsorship.InvoiceNumber)).Value(&sponsorship.InvoiceNumber),
+			huh.NewInput().Title("Amount invoiced (e.g., 1500 or $1500)").Value(&sponsorship.AmountInvoiced),
+			huh.NewInput().Title(fmt.Sprintf("Sent date (%s)", dayFormat)).Value(&sponsorship.InvoiceSentDate),
+			huh.NewInput().Title(fmt.Sprintf("Due date (%s, empty for %d days after it was sent)", dayFormat, settings.Sponsorship.InvoiceTermDays)).Value(&sponsorship.DueDate),
+			huh.NewInput().Title(c.ColorFromString(fmt.Sprintf("Paid date (%s)", dayFormat), sponsorship.PaidDate)).Value(&sponsorship.PaidDate),
+			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
+		).Title("Invoice"),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !save {
+		return nil
+	}
+	if err := validateInvoice(sponsorship); err != nil {
+		return err
+	}
+	video.Sponsorship = sponsorship
+	yaml := YAML{}
+	yaml.WriteVideo(video, video.Path)
+	unpaid := getUnpaidInvoices([]Video{*video}, now, settings.Sponsorship.InvoiceTermDays)
+	if len(unpaid) == 0 || unpaid[0].DaysOverdue == 0 {
+		return nil
+	}
+	if len(strings.TrimSpace(sponsorship.Emails)) == 0 {
+		output.Warn(fmt.Sprintf("The invoice is %d days overdue but there are no sponsorship emails to remind.", unpaid[0].DaysOverdue))
+		return nil
+	}
+	subject, body := getInvoiceReminder(*video, unpaid[0].Due)
+	send := false
+	form = newForm(
+		huh.NewGroup(
+			huh.NewNote().Title(fmt.Sprintf("The invoice is %d days overdue", unpaid[0].DaysOverdue)).Description(fmt.Sprintf("To: %s\nSubject: %s\n\n%s", sponsorship.Emails, subject, body)),
+			huh.NewConfirm().Affirmative("Send the reminder").Negative("Skip").Value(&send),
+		),
+	)
+	if err := runForm(form); err != nil || !send {
+		return err
+	}
+	email := NewEmail(settings.Email.Password)
+	ctx, cancel := newEmailContext()
+	defer cancel()
+	if err := email.SendInvoiceReminder(ctx, settings.Email.From, *video, unpaid[0].Due); err != nil {
+		return err
+	}
+	output.Info(fmt.Sprintf("The reminder about invoice %s was sent.", sponsorship.InvoiceNumber))
+	return nil
+}
+
+// ConfirmUpload states the effective visibility and made-for-kids setting so that mistakes are caught before the upload.
+func (c *Choices) ConfirmUpload(video Video) (bool, error) {
+	status, warning, err := getScheduledUploadStatus(video, settings, time.Now())
+	if err != nil {
+		output.Error(err.Error())
+		return false, nil
+	}
+	if len(warning) > 0 {
+		output.Warn(warning)
+	}
+	if workflow.IsSponsored(video.Sponsorship) && !c.PrintLinkCheck(video) {
+		output.Error("The project link of a sponsored video is broken. Fix it before uploading or upload anyway.")
+	}
+	if findings := lintVideoDescription(video); len(findings) > 0 {
+		output.Print(getDescriptionFindingsText(findings))
+	}
+	for _, warning := range getSponsorAssetWarnings(video, getMaterialDir(video), settings.Sponsorship.AssetTypes) {
+		output.Warn(warning)
+	}
+	if blackouts := getVideoBlackoutConflicts(video, settings.Sponsorship.BlackoutWindows); len(blackouts) > 0 {
+		output.Error(fmt.Sprintf("%s\nMove the publish date before uploading.", getBlackoutConflictMessage(blackouts)))
+		return false, nil
+	}
+	if creditsErrors := getCreditsErrors(video.Attributions, getUploadRequest(video, status).Snippet.Description, settings.Credits); len(creditsErrors) > 0 {
+		output.Error(fmt.Sprintf("The video cannot be uploaded until the attributions are fixed:\n%s", strings.Join(creditsErrors, "\n")))
+		return false, nil
+	}
+	if warning := getVideoContentFlagsWarning(video, settings.Content.TriggerKeywords); len(warning) > 0 {
+		output.Warn(warning)
+	}
+	if video.ContentFlags.AgeRestricted {
+		ageRestricted := false
+		form := newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title("The video is age restricted. It will not be shown to viewers who are signed out or younger than 18.").
+					Affirmative("Upload as age restricted").
+					Negative("Cancel").
+					Value(&ageRestricted),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return false, err
+		}
+		if !ageRestricted {
+			return false, nil
+		}
+	}
+	upload := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(getUploadStatusMessage(status)).
+				Affirmative("Upload").
+				Negative("Cancel").
+				Value(&upload),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return false, err
+	}
+	return upload, nil
+}
+
+// PostReddit submits the video to the configured subreddits it was not posted to yet and stores the URLs of successful posts.
+func (c *Choices) PostReddit(video *Video) {
+	if video.RedditPosted == nil {
+		video.RedditPosted = map[string]string{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	reddit := NewReddit(settings.Reddit)
+	for _, result := range reddit.PostAll(ctx, settings.Reddit.Subreddits, video.RedditPosted, getRedditTitle(*video), getYouTubeURL(video.VideoId)) {
+		if result.Err != nil {
+			output.Error(fmt.Sprintf("r/%s: %s", result.Subreddit, result.Err))
+			continue
+		}
+		video.RedditPosted[result.Subreddit] = result.URL
+		output.Info(fmt.Sprintf("r/%s: %s", result.Subreddit, result.URL))
+	}
+}
+
+func (c *Choices) PostMastodon(video Video) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	mastodon := NewMastodon(settings.Mastodon)
+	url, err := mastodon.PostVideo(ctx, video)
+	retried := ""
+	if mastodon.Retried > 0 {
+		retried = fmt.Sprintf(" (retried %d times)", mastodon.Retried)
+	}
+	if errors.As(err, new(*ErrMastodonAuth)) {
+		return fmt.Errorf("%w. Check mastodon.server and MASTODON_ACCESS_TOKEN", err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w%s", err, retried)
+	}
+	output.Info(fmt.Sprintf("Mastodon: %s%s", url, retried))
+	return nil
+}
+
+// PostSlack posts the video to the Slack channels or, if there are none, copies its URL to the clipboard.
+// Only posts to channels are recorded in the audit log since copying to the clipboard does not post anything.
+func (c *Choices) PostSlack(video *Video) error {
+	if !isSlackEnabled(settings.Slack) {
+		postSlack(video.VideoId)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	posted, err := NewSlack(settings.Slack).PostVideo(ctx, video)
+	if posted > 0 || err != nil {
+		recordAuditAction(video.Path, auditActionSlack, err)
+	}
+	if err != nil {
+		return err
+	}
+	if posted > 0 {
+		output.Info(fmt.Sprintf("The video was posted to %d Slack channels.", posted))
+	}
+	return nil
+}
+
+// PrintLinkCheck checks the project URL as it is rendered in the description and reports whether it works.
+func (c *Choices) PrintLinkCheck(video Video) bool {
+	if len(video.ProjectURL) == 0 || video.ProjectURL == "N/A" || video.ProjectURL == "-" {
+		return true
+	}
+	result := checkProjectURL(video)
+	if result.OK() {
+		output.Info(result.String())
+	} else {
+		output.Error(result.String())
+	}
+	return result.OK()
+}
+
+// ChoosePodcast edits the podcast episode of the video. Episode numbers are checked against all the videos in the index.
+func (c *Choices) ChoosePodcast(video *Video) error {
+	yaml := YAML{IndexPath: "index.yaml"}
+	videos := c.getVideos(yaml.GetIndex())
+	episode := video.Podcast
+	number := ""
+	if episode.Episode > 0 {
+		number = strconv.Itoa(episode.Episode)
+	} else {
+		number = strconv.Itoa(getNextPodcastEpisode(videos))
+	}
+	save := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().Title("Published").Value(&episode.Published),
+			huh.NewInput().Title("Episode number").Value(&number).Validate(func(value string) error {
+				edited := *video
+				edited.Podcast.Published = episode.Published
+				if len(value) > 0 {
+					parsed, err := strconv.Atoi(value)
+					if err != nil {
+						return fmt.Errorf("episode number must be a number")
+					}
+					edited.Podcast.Episode = parsed
+				} else {
+					edited.Podcast.Episode = 0
+				}
+				return validatePodcastEpisode(edited, videos)
+			}),
+			huh.NewInput().Title("Platform URL").Value(&episode.URL),
+			huh.NewInput().Title("Audio file path").Value(&episode.AudioPath),
+			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !save {
+		return nil
+	}
+	episode.Episode, _ = strconv.Atoi(number)
+	video.Podcast = episode
+	return nil
+}
+
+func (c *Choices) ChooseClips(video *Video) error {
+	const clipActionAdd = -1
+	const clipActionSuggest = -2
+	duration := getVideoDuration(*video)
+	for {
+		selected := actionReturn
+		options := huh.NewOptions[int]()
+		for i, clip := range video.Clips {
+			options = append(options, huh.NewOption(getClipTitle(clip), i))
+		}
+		options = append(options,
+			huh.NewOption("Add clip", clipActionAdd),
+			huh.NewOption("Suggest clips", clipActionSuggest),
+			huh.NewOption("Return", actionReturn),
+		)
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title("Which clip would you like to work on?").
+					Options(options...).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch selected {
+		case actionReturn:
+			return nil
+		case clipActionAdd:
+			clip := Clip{Status: clipStatusPlanned}
+			save, err := c.ChooseClip(&clip, duration)
+			if err != nil {
+				return err
+			}
+			if !save {
+				continue
+			}
+			video.Clips = append(video.Clips, clip)
+		case clipActionSuggest:
+			suggested, err := c.getClipSuggestions(video.Gist)
+			if err != nil {
+				output.Error(err.Error())
+				continue
+			}
+			selectedClips := []Clip{}
+			clipOptions := huh.NewOptions[Clip]()
+			for _, clip := range suggested {
+				if validateClip(clip, duration) != nil {
+					continue
+				}
+				clipOptions = append(clipOptions, huh.NewOption(fmt.Sprintf("%s-%s %s: %s", clip.Start, clip.End, clip.Title, clip.Hook), clip))
+			}
+			form := newForm(
+				huh.NewGroup(
+					huh.NewMultiSelect[Clip]().
+						Title("Which clips would you like to add?").
+						Options(clipOptions...).
+						Value(&selectedClips),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+			video.Clips = append(video.Clips, selectedClips...)
+		default:
+			clip := video.Clips[selected]
+			save, err := c.ChooseClip(&clip, duration)
+			if err != nil {
+				return err
+			}
+			if !save {
+				continue
+			}
+			video.Clips[selected] = clip
+		}
+		yaml := YAML{}
+		yaml.WriteVideo(video, video.Path)
+	}
+}
+
+func (c *Choices) ChooseSponsorAssets(video *Video) error {
+	const assetActionAdd = -1
+	materialDir := getMaterialDir(*video)
+	for {
+		selected := actionReturn
+		options := huh.NewOptions[int]()
+		for i, asset := range video.Sponsorship.Assets {
+			options = append(options, huh.NewOption(getSponsorAssetTitle(asset), i))
+		}
+		options = append(options,
+			huh.NewOption("Add asset", assetActionAdd),
+			huh.NewOption("Return", actionReturn),
+		)
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(fmt.Sprintf("Which sponsor asset would you like to work on (paths are relative to %s)?", materialDir)).
+					Options(options...).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch selected {
+		case actionReturn:
+			return nil
+		case assetActionAdd:
+			asset := SponsorAsset{}
+			save, err := c.ChooseSponsorAsset(&asset, materialDir)
+			if err != nil {
+				return err
+			}
+			if !save {
+				continue
+			}
+			video.Sponsorship.Assets = append(video.Sponsorship.Assets, asset)
+		default:
+			asset := video.Sponsorship.Assets[selected]
+			save, err := c.ChooseSponsorAsset(&asset, materialDir)
+			if err != nil {
+				return err
+			}
+			if !save {
+				continue
+			}
+			video.Sponsorship.Assets[selected] = asset
+		}
+		video.Init = getBuiltInPhaseProgress(*video, customFieldPhaseInit, settings)
+		yaml := YAML{}
+		yaml.WriteVideo(video, video.Path)
+	}
+}
+
+func (c *Choices) ChooseSponsorAsset(asset *SponsorAsset, materialDir string) (bool, error) {
+	save := true
+	typeOptions := huh.NewOptions(settings.Sponsorship.AssetTypes...)
+	if len(asset.Type) > 0 && !slices.Contains(settings.Sponsorship.AssetTypes, asset.Type) {
+		typeOptions = append(typeOptions, huh.NewOption(asset.Type, asset.Type))
+	}
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Type").Options(typeOptions...).Value(&asset.Type),
+			huh.NewConfirm().Title(c.ColorFromBool("Received", asset.Received)).Value(&asset.Received),
+			huh.NewInput().Title(c.ColorFromString("Path", asset.Path)).Value(&asset.Path).Validate(func(value string) error {
+				return validateSponsorAsset(SponsorAsset{Type: asset.Type, Received: asset.Received, Path: value}, materialDir, settings.Sponsorship.AssetTypes)
+			}),
+			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return false, err
+	}
+	if !asset.Received {
+		asset.ReceivedDate = ""
+	} else if len(asset.ReceivedDate) == 0 {
+		asset.ReceivedDate = time.Now().Format(dayFormat)
+	}
+	return save, nil
+}
+
+func (c *Choices) ChooseClip(clip *Clip, duration time.Duration) (bool, error) {
+	save := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewInput().Title(c.ColorFromString("Start (e.g., 01:20)", clip.Start)).Value(&clip.Start).Validate(func(value string) error {
+				_, err := parseTimestamp(value)
+				return err
+			}),
+			huh.NewInput().Title(c.ColorFromString("End (e.g., 02:05)", clip.End)).Value(&clip.End).Validate(func(value string) error {
+				return validateClip(Clip{Start: clip.Start, End: value}, duration)
+			}),
+			huh.NewInput().Title(c.ColorFromString("Title", clip.Title)).Value(&clip.Title),
+			huh.NewInput().Title(c.ColorFromString("Hook", clip.Hook)).Value(&clip.Hook),
+			huh.NewSelect[string]().Title("Status").Options(
+				huh.NewOption("Planned", clipStatusPlanned),
+				huh.NewOption("Recorded", clipStatusRecorded),
+				huh.NewOption("Published", clipStatusPublished),
+			).Value(&clip.Status),
+			huh.NewInput().Title("Short video ID").Value(&clip.VideoId),
+			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return false, err
+	}
+	return save, nil
+}
+
+func (c *Choices) ChooseTags(video *Video) error {
+	const tagsActionAI = 0
+	const tagsActionSuggest = 1
+	const tagsActionNormalize = 2
+	for {
+		selected := actionReturn
+		title := c.ColorFromField(customFieldPhaseDefine, "Tags", "Tags", len(video.Tags) > 0)
+		if len(video.Tags) > 0 {
+			title = fmt.Sprintf("%s (%s)", title, video.Tags)
+		}
+		if warnings := getTagAliasWarnings(video.Tags, settings.Tags.Aliases); len(warnings) > 0 {
+			title = fmt.Sprintf("%s\n%s", title, errorStyle.Render(strings.Join(warnings, "\n")))
+		}
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(title).
+					Options(
+						huh.NewOption("Generate with AI", tagsActionAI),
+						huh.NewOption("Suggest from similar videos", tagsActionSuggest),
+						huh.NewOption("Normalize aliases", tagsActionNormalize),
+						huh.NewOption("Continue", actionReturn),
+					).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch selected {
+		case actionReturn:
+			return nil
+		case tagsActionAI:
+			if err := c.ChooseFabric(video, &video.Tags, "Tags", "tags_dot", true); err != nil {
+				return err
+			}
+			continue
+		case tagsActionSuggest:
+			yaml := YAML{IndexPath: "index.yaml"}
+			suggestions := suggestTags(*video, c.getVideos(yaml.GetIndex()), 20)
+			if len(suggestions) == 0 {
+				output.Info("There are no tags in other videos of the same category.")
+				continue
+			}
+			selectedTags := []string{}
+			form := newForm(
+				huh.NewGroup(
+					huh.NewMultiSelect[string]().
+						Title("Which tags would you like to add?").
+						Options(huh.NewOptions(suggestions...)...).
+						Value(&selectedTags),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+			video.Tags = strings.Join(append(splitTags(video.Tags), selectedTags...), ",")
+		case tagsActionNormalize:
+			video.Tags = normalizeTags(video.Tags, settings.Tags.Aliases)
+		}
+		yaml := YAML{}
+		yaml.WriteVideo(video, video.Path)
+	}
+}
+
+// ChooseGenerateAnimations shows the cues found in the manuscript grouped by section and, if confirmed, replaces the animations and the timecodes.
+func (c *Choices) ChooseGenerateAnimations(video *Video) error {
+	repo := Repo{}
+	var animations, sections []string
+	var err error
+	preview := ""
+	if strings.HasSuffix(video.Gist, ".sh") {
+		animations, sections, err = repo.GetAnimations(video.Gist)
+		preview = strings.Join(animations, "\n")
+	} else {
+		var cues []AnimationCue
+		cues, err = repo.GetAnimationCues(video.Gist)
+		animations, sections = getAnimationLines(cues)
+		preview = getAnimationsPreview(cues)
+	}
+	if err != nil {
+		return err
+	}
+	if len(animations) == 0 {
+		output.Error(fmt.Sprintf("No animation cues (%s) were found in %s.", strings.Join(getAnimationOptions(settings.Animations).CuePrefixes, ", "), video.Gist))
+		return nil
+	}
+	write := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewNote().Title("Animations").Description(preview),
+			huh.NewConfirm().
+				Title("Would you like to replace the animations and the timecodes?").
+				Affirmative("Replace").
+				Negative("Keep").
+				Value(&write),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !write {
+		return nil
+	}
+	video.Animations = ""
+	for _, line := range animations {
+		video.Animations = fmt.Sprintf("%s\n- %s", video.Animations, line)
+	}
+	video.Timecodes = "00:00 TODO:"
+	for _, section := range sections {
+		video.Timecodes = fmt.Sprintf("%s\nTODO:TODO %s", video.Timecodes, strings.TrimPrefix(section, animationSectionPrefix))
+	}
+	return nil
+}
+
+func (c *Choices) ChooseRelatedVideos(video *Video) error {
+	yaml := YAML{IndexPath: "index.yaml"}
+	index, err := loadSearchIndex(searchIndexPath)
+	if err != nil {
+		index = nil
+	}
+	suggestions := suggestRelatedVideos(*video, c.getVideos(yaml.GetIndex()), index, relatedSuggestionsCount)
+	if len(suggestions) == 0 {
+		output.Info("There are no published videos similar to this one.")
+		return nil
+	}
+	options := []huh.Option[int]{}
+	for i, suggestion := range suggestions {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s (%.2f)", formatRelatedVideo(suggestion), suggestion.Score), i))
+	}
+	selected := []int{}
+	form := newForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("Which related videos would you like to add?").
+				Options(options...).
+				Value(&selected),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	accepted := []RelatedSuggestion{}
+	for _, i := range selected {
+		accepted = append(accepted, suggestions[i])
+	}
+	video.RelatedVideos = appendRelatedVideos(video.RelatedVideos, accepted)
+	return nil
+}
+
+func (c *Choices) ChooseNormalizeTags(vi []VideoIndex) error {
+	for i := range vi {
+		video := c.getVideo(vi, i)
+		normalized := normalizeTags(video.Tags, settings.Tags.Aliases)
+		if normalized == strings.Join(splitTags(video.Tags), ",") {
+			continue
+		}
+		apply := true
+		form := newForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(video.Name).
+					Description(fmt.Sprintf("- %s\n+ %s", video.Tags, normalized)).
+					Affirmative("Normalize").
+					Negative("Skip").
+					Value(&apply),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		if apply {
+			video.Tags = normalized
+			yaml := YAML{}
+			yaml.WriteVideo(&video, video.Path)
+		}
+	}
+	return nil
+}
+
+// ChooseBulkReplace previews a find-and-replace across the selected videos and writes it only if confirmed.
+func (c *Choices) ChooseBulkReplace(vi []VideoIndex) error {
+	options := BulkReplaceOptions{Fields: []string{bulkFieldDescription}}
+	phaseOptions := []huh.Option[string]{huh.NewOption("All", "")}
+	for _, phase := range []string{"published", "publishPending", "editRequested", "materialDone", "started", "delayed", "sponsoredBlocked", "ideas"} {
+		phaseOptions = append(phaseOptions, huh.NewOption(phase, phase))
+	}
+	form := newForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().Title("Fields").Options(huh.NewOptions(bulkFields...)...).Value(&options.Fields),
+			huh.NewInput().Title("Find").Value(&options.Pattern).Validate(c.IsEmpty),
+			huh.NewConfirm().Title("Is it a regular expression?").Value(&options.Regex),
+			huh.NewInput().Title("Replace with").Description("Use $1, $2, etc. for regular expression groups.").Value(&options.Replacement),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Phase").Options(phaseOptions...).Value(&options.Phase),
+			huh.NewInput().Title("Category").Description("Leave empty for all categories.").Value(&options.Category),
+			huh.NewInput().Title("Published from").Description(fmt.Sprintf("Leave empty or use the %s format.", dayFormat)).Value(&options.From),
+			huh.NewInput().Title("Published to").Description(fmt.Sprintf("Leave empty or use the %s format.", dayFormat)).Value(&options.To),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	changes, err := getBulkChanges(c.getVideos(vi), c.getPhase, options)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		output.Info(getBulkChangesPreview(changes))
+		return nil
+	}
+	apply := false
+	form = newForm(
+		huh.NewGroup(
+			huh.NewNote().Title("Preview").Description(getBulkChangesPreview(changes)),
+			huh.NewConfirm().Title("Would you like to apply the changes?").Affirmative("Apply").Negative("Cancel").Value(&apply),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !apply {
+		return nil
+	}
+	yaml := YAML{}
+	backupDir, results, err := applyBulkChanges(changes, yaml.writeVideo, time.Now())
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			output.Error(fmt.Sprintf("%s: %s", result.Path, result.Err))
+		} else {
+			output.Info(fmt.Sprintf("%s was updated.", result.Path))
+		}
+	}
+	output.Result(fmt.Sprintf("%d of %d videos were updated. The originals were backed up to %s.", len(results)-failed, len(results), backupDir))
+	return nil
+}
+
+func (c *Choices) ChooseRegenerateHugo(vi []VideoIndex) error {
+	hugo := Hugo{}
+	videos := c.getVideos(vi)
+	results := hugo.Regenerate(videos, true)
+	output.Result(getHugoRegenerationSummary(results))
+	changed := 0
+	for _, result := range results {
+		if result.Changed {
+			changed++
+		}
+	}
+	if changed == 0 {
+		return nil
+	}
+	write := false
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Write changes to %d Hugo posts?", changed)).
+				Affirmative("Write").
+				Negative("Cancel").
+				Value(&write),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !write {
+		return nil
+	}
+	for _, result := range hugo.Regenerate(videos, false) {
+		if len(result.Error) > 0 {
+			output.Error(fmt.Sprintf("%s: %s", result.Path, result.Error))
+		}
+	}
+	return nil
+}
+
+func (c *Choices) ChooseTalks(video *Video) error {
+	const talkActionAdd = -1
+	for {
+		selected := actionReturn
+		options := huh.NewOptions[int]()
+		for i, talk := range video.Talks {
+			options = append(options, huh.NewOption(getTalkTitle(talk, time.Now()), i))
+		}
+		options = append(options,
+			huh.NewOption("Add talk", talkActionAdd),
+			huh.NewOption("Return", actionReturn),
+		)
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title("Which conference talk would you like to work on?").
+					Options(options...).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch selected {
+		case actionReturn:
+			return nil
+		case talkActionAdd:
+			talk := Talk{Status: talkStatusPlanned}
+			save, err := c.ChooseTalk(&talk)
+			if err != nil {
+				return err
+			}
+			if !save {
+				continue
+			}
+			video.Talks = append(video.Talks, talk)
+		default:
+			talk := video.Talks[selected]
+			save, err := c.ChooseTalk(&talk)
+			if err != nil {
+				return err
+			}
+			if !save {
+				continue
+			}
+			video.Talks[selected] = talk
+		}
+		yaml := YAML{}
+		yaml.WriteVideo(video, video.Path)
+	}
+}
+
+func (c *Choices) ChooseTalk(talk *Talk) (bool, error) {
+	save := true
+	statusOrig := talk.Status
+	form := newForm(
+		huh.NewGroup(
+			huh.NewInput().Title(c.ColorFromString("Conference", talk.Conference)).Value(&talk.Conference).Validate(c.IsEmpty),
+			huh.NewInput().Title(c.ColorFromString("CFP deadline (e.g., 2030-01-21)", talk.CFPDeadline)).Value(&talk.CFPDeadline).Validate(c.validateOptionalDate),
+			huh.NewSelect[string]().Title("Status").Options(
+				huh.NewOption("Planned", talkStatusPlanned),
+				huh.NewOption("Submitted", talkStatusSubmitted),
+				huh.NewOption("Accepted", talkStatusAccepted),
+				huh.NewOption("Rejected", talkStatusRejected),
+			).Value(&talk.Status).Validate(func(value string) error {
+				return validateTalkTransition(statusOrig, value)
+			}),
+			huh.NewInput().Title(c.ColorFromString("Talk date (e.g., 2030-01-21)", talk.Date)).Value(&talk.Date).Validate(c.validateOptionalDate),
+			huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(&save),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return false, err
+	}
+	return save, nil
+}
+
+func (c *Choices) ChooseUpcomingTalks(vi []VideoIndex) error {
+	now := time.Now()
+	deadlines := getUpcomingCFPs(c.getVideos(vi), now)
+	if len(deadlines) == 0 {
+		output.Info("There are no upcoming CFP deadlines.")
+		return nil
+	}
+	selected := actionReturn
+	options := huh.NewOptions[int]()
+	for i, deadline := range deadlines {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s: %s", deadline.Video.Name, getTalkTitle(deadline.Talk, now)), i))
+	}
+	options = append(options, huh.NewOption("Return", actionReturn))
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Upcoming CFP deadlines").
+				Options(options...).
+				Value(&selected),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if selected == actionReturn {
+		return nil
+	}
+	video := deadlines[selected].Video
+	talk := video.Talks[deadlines[selected].Index]
+	save, err := c.ChooseTalk(&talk)
+	if err != nil || !save {
+		return err
+	}
+	video.Talks[deadlines[selected].Index] = talk
+	yaml := YAML{}
+	yaml.WriteVideo(&video, video.Path)
+	return nil
+}
+
+func (c *Choices) validateOptionalDate(value string) error {
+	if len(value) == 0 {
+		return nil
+	}
+	_, err := parseDate(value)
+	return err
+}
+
+func (c *Choices) getClipSuggestions(gist string) ([]Clip, error) {
+	content, _, err := readManuscript(gist)
+	if err != nil {
+		return nil, err
+	}
+	suggestions := []clipSuggestion{}
+	if err := runFabricJSON("clips_dot", content, &suggestions); err != nil {
+		return nil, err
+	}
+	return getSuggestedClips(suggestions), nil
+}
+
+func (c *Choices) ColorFromSponsoredEmails(title, sponsored string, sponsoredEmails string) (string, bool) {
+	if workflow.IsSponsorshipDone(Sponsorship{Amount: sponsored, Emails: sponsoredEmails}) {
+		return greenStyle.Render(title), true
+	}
+	return redStyle.Render(title), false
+}
+
+func (c *Choices) ColorFromString(title, value string) string {
+	if len(value) > 0 {
+		return greenStyle.Render(title)
+	}
+	return redStyle.Render(title)
+}
+
+func (c *Choices) ColorFromStringInverse(title, value string) string {
+	if len(value) > 0 {
+		return redStyle.Render(title)
+	}
+	return greenStyle.Render(title)
+}
+
+// ColorFromField colors the title of a field counted by the phase progress the way its progress override counts it. Titles of fields that are not counted are not colored.
+func (c *Choices) ColorFromField(phase, name, title string, done bool) string {
+	override, ok := getProgressOverride(settings.Progress, phase, name)
+	switch {
+	case ok && override.Enabled != nil && !*override.Enabled:
+		return title
+	case ok && override.Criteria == progressCriteriaAlways:
+		done = true
+	case ok && override.Criteria == progressCriteriaTrue && !isProgressFieldBool(phase, name):
+		done = false
+	}
+	return c.ColorFromBool(title, done)
+}
+
+func (c *Choices) ColorFromBool(title string, value bool) string {
+	if value {
+		return greenStyle.Render(title)
+	}
+	return redStyle.Render(title)
+}
+
+// recordTrends is best effort. A failure to record the history should not stop listing videos.
+func (c *Choices) recordTrends(snapshot PhaseSnapshot) {
+	if _, err := recordPhaseSnapshot(trendsDir, snapshot, settings.Trends.IntervalDays); err != nil {
+		output.Error(fmt.Sprintf("Phase counts could not be recorded: %v", err))
+	}
+}
+
+// ChooseTrends records today's snapshot, if it's due, and shows how the number of videos in each phase changed over time.
+func (c *Choices) ChooseTrends(vi []VideoIndex) error {
+	phases := make(map[int]int)
+	videos := c.getVideos(vi)
+	for _, video := range videos {
+		phases[c.getPhase(video)]++
+	}
+	now := time.Now()
+	c.recordTrends(newPhaseSnapshot(videos, phases, len(getOverdueSponsorships(videos, now, settings.Sponsorship.ReminderDays)), now))
+	snapshots, err := readPhaseSnapshots(trendsDir, now.AddDate(0, 0, -settings.Trends.Days+1), now)
+	if err != nil {
+		return err
+	}
+	dates, series := getTrendSeries(snapshots, now, settings.Trends.Days)
+	output.Result(getTrendsText(dates, series))
+	return nil
+}
+
+func (c *Choices) ChooseVideosPhase(vi []VideoIndex) bool {
+	var selection int
+	entries := c.getPhaseSummaryEntries(vi)
+	phases := getPhaseSummaryCounts(entries)
+	// Only videos that might need attention are read. The rest is served from the summary.
+	videos := []Video{}
+	pending := []Video{}
+	invoiced := []Video{}
+	for i, entry := range entries {
+		if !entry.Blocked && !entry.Demos && !entry.Invoice && entry.Phase != videosPhasePublishPending {
+			continue
+		}
+		video := c.getVideo(vi, i)
+		if entry.Invoice {
+			invoiced = append(invoiced, video)
+		}
+		if !entry.Blocked && !entry.Demos && entry.Phase != videosPhasePublishPending {
+			continue
+		}
+		videos = append(videos, video)
+		if entry.Phase == videosPhasePublishPending {
+			pending = append(pending, video)
+		}
+	}
+	overdue := getOverdueSponsorships(videos, time.Now(), settings.Sponsorship.ReminderDays)
+	c.recordTrends(getPhaseSummarySnapshot(entries, len(overdue), time.Now()))
+	warning := getOverdueSponsorshipsWarning(overdue, settings.Sponsorship.ReminderDays)
+	if invoiceWarning := getOverdueInvoicesWarning(getUnpaidInvoices(invoiced, time.Now(), settings.Sponsorship.InvoiceTermDays)); len(invoiceWarning) > 0 {
+		warning = strings.TrimSpace(fmt.Sprintf("%s\n\n%s", warning, invoiceWarning))
+	}
+	if demoWarning := getStaleDemosWarning(videos, time.Now(), settings.Demo.ReminderDays); len(demoWarning) > 0 {
+		warning = strings.TrimSpace(fmt.Sprintf("%s\n\n%s", warning, demoWarning))
+	}
+	if len(warning) > 0 {
+		warning = errorStyle.Render(warning)
+	}
+	if len(settings.Demo.Webhook) > 0 {
+		changed, err := notifyStaleDemos(demoClient, settings.Demo.Webhook, videos, time.Now(), settings.Demo.ReminderDays)
+		for _, video := range changed {
+			yaml := YAML{}
+			yaml.WriteVideo(&video, video.Path)
+		}
+		if err != nil {
+			output.Error(err.Error())
+		}
+	}
+	options := huh.NewOptions[int]()
+	if text, count := c.GetPhaseColoredText(phases, videosPhasePublished, "Published"); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhasePublished))
+	}
+	pendingTitle := fmt.Sprintf("Pending publish, average quality %d", getAverageQualityScore(pending, settings.Quality.Weights, settings.Tags.Aliases))
+	if text, count := c.GetPhaseColoredText(phases, videosPhasePublishPending, pendingTitle); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhasePublishPending))
+	}
+	if text, count := c.GetPhaseColoredText(phases, videosPhaseEditRequested, "Edit requested"); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhaseEditRequested))
+	}
+	if text, count := c.GetPhaseColoredText(phases, videosPhaseMaterialDone, "Material done"); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhaseMaterialDone))
+	}
+	if text, count := c.GetPhaseColoredText(phases, videosPhaseStarted, "Started"); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhaseStarted))
+	}
+	if text, count := c.GetPhaseColoredText(phases, videosPhaseDelayed, "Delayed"); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhaseDelayed))
+	}
+	if text, count := c.GetPhaseColoredText(phases, videosPhaseSponsoredBlocked, "Sponsored blocked"); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhaseSponsoredBlocked))
+	}
+	if text, count := c.GetPhaseColoredText(phases, videosPhaseIdeas, "Ideas"); count > 0 {
+		options = append(options, huh.NewOption(text, videosPhaseIdeas))
+	}
+	options = append(options, huh.NewOption("Return", actionReturn))
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("From which phase would you like to list the videos?").
+				Description(warning).
+				Options(options...).
+				Value(&selection),
+		),
+	)
+	err := runForm(form)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if selection == actionReturn {
+		return true
+	}
+	c.ChooseVideos(vi, selection)
+	return false
+}
+
+// getPhaseSummaryEntries returns the summary entries of the videos, in the same order, and stores the summary if any of the videos had to be read.
+// A missing or broken summary is rebuilt.
+func (c *Choices) getPhaseSummaryEntries(vi []VideoIndex) []PhaseSummaryEntry {
+	summary, err := loadPhaseSummary(phaseSummaryPath)
+	if err != nil {
+		summary = NewPhaseSummary()
+	}
+	paths := make([]string, len(vi))
+	for i := range vi {
+		paths[i] = c.GetFilePath(vi[i].Category, vi[i].Name, "yaml")
+	}
+	entries, reads := summary.Refresh(paths, func(i int) Video {
+		return c.getVideoConcurrently(vi, i)
+	})
+	if reads > 0 {
+		if err := savePhaseSummary(phaseSummaryPath, summary); err != nil {
+			output.Error(fmt.Sprintf("The phase summary could not be saved: %v", err))
+		}
+	}
+	return entries
+}
+
+func (c *Choices) getPhase(video Video) int {
+	return workflow.GetPhase(video)
+}
+
+func getVideoOptionTitle(video Video, now time.Time) string {
+	title := video.Name
+	if isSponsorshipBlocked(video.Sponsorship) {
+		title = fmt.Sprintf("%s (%s)", title, getBlockedTitle(video.Sponsorship, now))
+	} else {
+		if len(video.Date) > 0 {
+			title = fmt.Sprintf("%s (%s)", title, renderScheduleDate(video.Date, video.Date, now, settings.Schedule))
+		}
+		if workflow.IsSponsored(video.Sponsorship) {
+			title = fmt.Sprintf("%s (sponsored)", title)
+		}
+		if video.Category == "ama" {
+			title = fmt.Sprintf("%s (AMA)", title)
+		}
+	}
+	if len(video.SupersededBy) > 0 {
+		title = fmt.Sprintf("%s (superseded)", title)
+	}
+	if hasStaleDemos(video, now, settings.Demo.ReminderDays) {
+		title = fmt.Sprintf("%s %s", demoMarker, title)
+	}
+	return fmt.Sprintf("%s %s", title, getCompletionText(video))
+}
+
+func (c *Choices) ChooseVideos(vi []VideoIndex, phase int) {
+	const videosSortToggle = -1
+	const videosSupersededToggle = -2
+	const videosMoveSeveral = -3
+	const videosChangeFilter = -4
+	var selectedVideoIndex int
+	positions := []int{}
+	for i, entry := range c.getPhaseSummaryEntries(vi) {
+		if entry.Phase == phase {
+			positions = append(positions, i)
+		}
+	}
+	sortedVideos := []Video{}
+	for _, video := range c.getIndexedVideos(vi, positions) {
+		if c.getPhase(video) == phase {
+			sortedVideos = append(sortedVideos, video)
+		}
+	}
+	filter, err := askOptionsFilter(len(sortedVideos))
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, superseded := filterSupersededVideos(sortedVideos, true)
+	visibleVideos := sortedVideos
+	for {
+		sortVideos(sortedVideos, videosSortOrder)
+		visibleVideos, _ = filterSupersededVideos(sortedVideos, videosHideSuperseded)
+		options := huh.NewOptions[int]()
+		nextSortOrder := getNextSortOrder(videosSortOrder)
+		options = append(options, huh.NewOption(fmt.Sprintf("Sorted by %s (sort by %s)", videosSortNames[videosSortOrder], videosSortNames[nextSortOrder]), videosSortToggle))
+		if superseded > 0 {
+			if videosHideSuperseded {
+				options = append(options, huh.NewOption(fmt.Sprintf("Show %d superseded videos", superseded), videosSupersededToggle))
+			} else {
+				options = append(options, huh.NewOption(fmt.Sprintf("Hide %d superseded videos", superseded), videosSupersededToggle))
+			}
+		}
+		if len(visibleVideos) > 1 {
+			options = append(options, huh.NewOption("Move several videos", videosMoveSeveral))
+		}
+		if len(filter) > 0 {
+			options = append(options, huh.NewOption(fmt.Sprintf("Change the filter %q", filter), videosChangeFilter))
+		}
+		for i, video := range visibleVideos {
+			options = append(options, huh.NewOption(getVideoOptionTitle(video, time.Now()), i))
+		}
+		options = filterOptions(options, filter, videosSortToggle, videosSupersededToggle, videosMoveSeveral, videosChangeFilter)
+		title := "Which video would you like to work on?"
+		if !slices.ContainsFunc(options, func(option huh.Option[int]) bool { return option.Value >= 0 }) {
+			title = fmt.Sprintf("No videos match %q.", filter)
+		}
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(title).
+					Options(options...).
+					Value(&selectedVideoIndex),
+			),
+		)
+		if err := runForm(form); err != nil {
+			log.Fatal(err)
+		}
+		if selectedVideoIndex == videosSupersededToggle {
+			videosHideSuperseded = !videosHideSuperseded
+			continue
+		}
+		if selectedVideoIndex == videosChangeFilter {
+			if filter, err = askOptionsFilter(len(sortedVideos)); err != nil {
+				log.Fatal(err)
+			}
+			continue
+		}
+		if selectedVideoIndex == videosMoveSeveral {
+			if err := c.ChooseMoveVideos(vi, visibleVideos); err != nil {
+				output.Error(err.Error())
+			}
+			return
+		}
+		if selectedVideoIndex != videosSortToggle {
+			break
+		}
+		videosSortOrder = nextSortOrder
+	}
+	c.ChooseVideoAction(vi, visibleVideos[selectedVideoIndex])
+}
+
+// ChooseVideoAction asks what to do with the video selected from a phase or from search results.
+func (c *Choices) ChooseVideoAction(vi []VideoIndex, selectedVideo Video) {
+	var selectedAction int
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("What would you like to do with the video?").
+				Options(c.getActionOptions()...).
+				Value(&selectedAction),
+		),
+	)
+	if err := runForm(form); err != nil {
+		log.Fatal(err)
+	}
+	switch selectedAction {
+	case actionEdit:
+		choices := Choices{}
+		choices.ChoosePhase(selectedVideo)
+	case actionDelete:
+		if err := deleteVideo(selectedVideo); err != nil {
+			output.Error(err.Error())
+			return
+		}
+		vi = append(vi[:selectedVideo.Index], vi[selectedVideo.Index+1:]...)
+	case actionMove:
+		moved, err := c.ChooseMoveVideo(vi, selectedVideo)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		vi = moved
+	case actionLintDescription:
+		output.ResultText(getDescriptionFindingsText(lintVideoDescription(selectedVideo)))
+		return
+	case actionNudgeSponsor:
+		if err := c.NudgeSponsor(selectedVideo, time.Now()); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionCompareUploaded:
+		if err := c.ChooseCompareUploaded(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionSyncYouTube:
+		if err := c.ChooseYouTubeSync(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionPromoteHighlight:
+		if err := c.ChoosePromoteHighlight(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionRefresh:
+		refreshed, err := c.ChooseRefreshVideo(selectedVideo)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		if len(refreshed.Name) == 0 {
+			return
+		}
+		vi = append(vi, refreshed)
+	case actionSecrets:
+		if err := c.ChooseSecrets(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionCleanup:
+		if err := c.ChooseCleanup(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionReplaceThumbnail:
+		if err := c.ChooseReplaceThumbnail(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionRecordSession:
+		if err := c.ChooseRecordSession(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionAttributions:
+		if err := c.ChooseAttributions(&selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionFocus:
+		if err := c.ChooseFocus(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionHistory:
+		entries, err := readAuditLog(selectedVideo.Path)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		output.ResultText(getAuditText(entries))
+		return
+	case actionShowStats:
+		if err := c.ChooseShowStats(selectedVideo); err != nil {
+			output.Error(err.Error())
+		}
+		return
+	case actionReturn:
+		return
+	}
+	yaml := YAML{IndexPath: "index.yaml"}
+	yaml.WriteIndex(vi)
+}
+
+// ChooseRefreshVideo creates a new version of the video that supersedes it and returns its index entry. The entry is empty if nothing was created.
+func (c *Choices) ChooseRefreshVideo(old Video) (VideoIndex, error) {
+	categories, err := c.getCategories()
+	if err != nil {
+		return VideoIndex{}, err
+	}
+	name := old.Name
+	category := old.Category
+	if collision, ok := checkVideoNameCollision(c.GetDirPath(category), name).(*ErrNameCollision); ok {
+		name = collision.SuggestedName
+	}
+	save := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewInput().Title("Name of the new version").Value(&name).Validate(c.IsEmpty),
+			huh.NewSelect[string]().Title("Category").Options(categories...).Value(&category),
+			huh.NewConfirm().Affirmative("Create").Negative("Cancel").Value(&save),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return VideoIndex{}, err
+	}
+	if !save {
+		return VideoIndex{}, nil
+	}
+	name, err = sanitizeVideoName(name, getNameOptions())
+	if err != nil {
+		return VideoIndex{}, err
+	}
+	yaml := YAML{}
+	refreshed, _, err := createRefreshedVideo(old, name, category, c.GetDirPath(category), yaml.writeVideo)
+	output.Event(outputActionRefresh, old.Path, refreshed.Path, err)
+	if err != nil {
+		return VideoIndex{}, err
+	}
+	output.Info(fmt.Sprintf("Video %s was created as the new version of %s.", refreshed.Name, old.Name))
+	return VideoIndex{Name: refreshed.Name, Category: category}, nil
+}
+
+// ChooseSyncMembers shows who joined and who left since the members were stored and replaces them with the current members if confirmed.
+func (c *Choices) ChooseSyncMembers(video *Video) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	current, err := getChannelMembers(ctx)
+	if err != nil {
+		return err
+	}
+	added, removed := getMembersDiff(parseMembers(video.Members), current)
+	if len(added) == 0 && len(removed) == 0 {
+		output.Info("Members are up to date.")
+		return nil
+	}
+	update := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Members changes").
+				Description(fmt.Sprintf("Joined: %s\nLeft: %s", formatMembers(added), formatMembers(removed))),
+			huh.NewConfirm().
+				Title("Would you like to update the members?").
+				Value(&update),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if update {
+		video.Members = formatMembers(current)
+	}
+	return nil
+}
+
+// NudgeSponsor sends a follow-up to the sponsor of a blocked video at most once per reminder period.
+func (c *Choices) NudgeSponsor(video Video, now time.Time) error {
+	if err := validateSponsorNudge(video.Sponsorship, now, settings.Sponsorship.ReminderDays); err != nil {
+		return err
+	}
+	email := NewEmail(settings.Email.Password)
+	ctx, cancel := newEmailContext()
+	defer cancel()
+	if err := email.SendSponsorNudge(ctx, settings.Email.From, video); err != nil {
+		return err
+	}
+	video.Sponsorship.LastNudged = now.Format(dayFormat)
+	yaml := YAML{}
+	yaml.WriteVideo(&video, video.Path)
+	output.Info(fmt.Sprintf("The sponsor was nudged at %s.", video.Sponsorship.Emails))
+	return nil
+}
+
+const highlightActionCopyPost = 0
+const highlightActionTrailer = 1
+const highlightActionRestore = 2
+
+// ChooseSecrets manages the secrets of the video. Values are encrypted before they are written and are shown only after an explicit confirmation.
+func (c *Choices) ChooseSecrets(video Video) error {
+	const secretActionAdd = -1
+	const secretActionReveal = 0
+	const secretActionReplace = 1
+	const secretActionRemove = 2
+	key, keyErr := loadSecretsKey(settings.Secrets.KeyFile)
+	if keyErr != nil && !errors.Is(keyErr, ErrSecretsKeyUnavailable) {
+		return keyErr
+	}
+	if keyErr != nil && len(video.Secrets) > 0 {
+		output.Warn(keyErr.Error())
+	}
+	yaml := YAML{}
+	for {
+		statuses := getSecretStatuses(video.Secrets)
+		options := huh.NewOptions[int]()
+		for i, status := range statuses {
+			options = append(options, huh.NewOption(getSecretTitle(status), i))
+		}
+		options = append(options,
+			huh.NewOption("Add secret", secretActionAdd),
+			huh.NewOption("Return", actionReturn),
+		)
+		selected := actionReturn
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(fmt.Sprintf("Secrets of %s", video.Name)).
+					Options(options...).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		name := ""
+		action := secretActionReplace
+		switch selected {
+		case actionReturn:
+			return nil
+		case secretActionAdd:
+		default:
+			name = statuses[selected].Name
+			action = secretActionReveal
+			form := newForm(
+				huh.NewGroup(
+					huh.NewSelect[int]().
+						Title(getSecretTitle(statuses[selected])).
+						Options(
+							huh.NewOption("Reveal", secretActionReveal),
+							huh.NewOption("Replace", secretActionReplace),
+							huh.NewOption("Remove", secretActionRemove),
+							huh.NewOption("Return", actionReturn),
+						).
+						Value(&action),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+		}
+		switch action {
+		case actionReturn:
+			continue
+		case secretActionReveal:
+			if key == nil {
+				output.Error(keyErr.Error())
+				continue
+			}
+			reveal := false
+			form := newForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title(fmt.Sprintf("Reveal %s?", name)).
+						Description("The value will be shown on the screen.").
+						Affirmative("Reveal").
+						Negative("Cancel").
+						Value(&reveal),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+			if !reveal {
+				continue
+			}
+			value, err := decryptSecret(key, video.Secrets[name])
+			if err != nil {
+				output.Error(err.Error())
+				continue
+			}
+			output.Result(fmt.Sprintf("%s: %s", name, value))
+			continue
+		case secretActionRemove:
+			remove := false
+			form := newForm(
+				huh.NewGroup(
+					huh.NewConfirm().Title(fmt.Sprintf("Remove %s?", name)).Affirmative("Remove").Negative("Cancel").Value(&remove),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return err
+			}
+			if !remove {
+				continue
+			}
+			delete(video.Secrets, name)
+		case secretActionReplace:
+			if key == nil {
+				var err error
+				if key, err = c.ConfirmCreateSecretsKey(); err != nil || key == nil {
+					return err
+				}
+			}
+			value := ""
+			fields := []huh.Field{}
+			if len(name) == 0 {
+				fields = append(fields, huh.NewInput().Title("Name").Value(&name).Validate(func(value string) error {
+					if len(strings.TrimSpace(value)) == 0 {
+						return fmt.Errorf("name is required")
+					}
+					if _, ok := video.Secrets[strings.TrimSpace(value)]; ok {
+						return fmt.Errorf("%s already exists", value)
+					}
+					return nil
+				}))
+			}
+			fields = append(fields, huh.NewInput().Title("Value").EchoMode(huh.EchoModePassword).Value(&value))
+			if err := runForm(newForm(huh.NewGroup(fields...))); err != nil {
+				return err
+			}
+			encrypted, err := encryptSecret(key, value)
+			if err != nil {
+				return err
+			}
+			if video.Secrets == nil {
+				video.Secrets = map[string]string{}
+			}
+			video.Secrets[strings.TrimSpace(name)] = encrypted
+		}
+		if err := yaml.writeVideo(&video, video.Path); err != nil {
+			return err
+		}
+	}
+}
+
+// ConfirmCreateSecretsKey offers to create the key the first time a secret is added. It returns no key if the offer is declined.
+func (c *Choices) ConfirmCreateSecretsKey() ([]byte, error) {
+	create := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("There is no secrets key. Create it in %s?", settings.Secrets.KeyFile)).
+				Description(fmt.Sprintf("Keep a copy of the key somewhere safe. Values cannot be recovered without it. It can also be set through the %s environment variable.", secretsKeyEnv)).
+				Affirmative("Create").
+				Negative("Cancel").
+				Value(&create),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return nil, err
+	}
+	if !create {
+		return nil, nil
+	}
+	return createSecretsKey(settings.Secrets.KeyFile)
+}
+
+// ChoosePromoteHighlight copies the community post for the video and, if selected, makes it the channel trailer or restores the previous one.
+func (c *Choices) ChoosePromoteHighlight(video Video) error {
+	if len(video.VideoId) == 0 {
+		return fmt.Errorf("%s was not uploaded yet", video.Name)
+	}
+	post, err := getCommunityPost(video, settings.Community.PostTemplate)
+	if err != nil {
+		return err
+	}
+	state, err := loadChannelState(channelStatePath)
+	if err != nil {
+		return err
+	}
+	options := []huh.Option[int]{
+		huh.NewOption("Copy community post", highlightActionCopyPost),
+		huh.NewOption("Copy community post and set as channel trailer", highlightActionTrailer),
+	}
+	if len(state.PreviousTrailer.VideoId) > 0 {
+		options = append(options, huh.NewOption(fmt.Sprintf("Restore %s as channel trailer", getChannelHighlightTitle(state.PreviousTrailer)), highlightActionRestore))
+	}
+	options = append(options, huh.NewOption("Return", actionReturn))
+	selected := highlightActionCopyPost
+	form := newForm(
+		huh.NewGroup(
+			huh.NewNote().Title("Community post").Description(post),
+			huh.NewSelect[int]().
+				Title(fmt.Sprintf("Current channel trailer: %s", getChannelHighlightTitle(state.Trailer))).
+				Options(options...).
+				Value(&selected),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	highlight := getChannelHighlight(video, time.Now())
+	switch selected {
+	case actionReturn:
+		return nil
+	case highlightActionRestore:
+		highlight = state.PreviousTrailer
+	default:
+		copyCommunityPost(post)
+		if selected == highlightActionCopyPost {
+			return nil
+		}
+	}
+	if state, err = updateChannelTrailer(state, highlight); err != nil {
+		return err
+	}
+	if err := saveChannelState(channelStatePath, state); err != nil {
+		return err
+	}
+	output.Info(fmt.Sprintf("%s is the channel trailer.", getChannelHighlightTitle(state.Trailer)))
+	return nil
+}
+
+// ChooseCompareUploaded shows how the current values differ from those uploaded to YouTube and, if confirmed, pushes the current values to YouTube.
+func (c *Choices) ChooseCompareUploaded(video Video) error {
+	if !hasUploadedSnapshot(video) {
+		return fmt.Errorf("%s has no snapshot of the uploaded metadata", video.Name)
+	}
+	differences := getSnapshotDifferences(video)
+	output.ResultText(fmt.Sprintf("Uploaded at %s\n\n%s", video.UploadedSnapshot.UploadedAt, getSnapshotDifferencesText(differences)))
+	if len(differences) == 0 {
+		return nil
+	}
+	push := false
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Would you like to push the current values to YouTube?").
+				Affirmative("Push").
+				Negative("Keep uploaded").
+				Value(&push),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !push {
+		return nil
+	}
+	snapshot, err := updateUploadedMetadata(video)
+	if err != nil {
+		return err
+	}
+	video.UploadedSnapshot = snapshot
+	yaml := YAML{}
+	yaml.WriteVideo(&video, video.Path)
+	output.Info("The video was updated on YouTube.")
+	return nil
+}
+
+// ChooseYouTubeSync compares the video on YouTube with the local values and pulls or pushes each of the fields that drifted.
+func (c *Choices) ChooseYouTubeSync(video Video) error {
+	service, err := newYouTubeService()
+	if err != nil {
+		return err
+	}
+	live, err := fetchLiveSnippet(service, video.VideoId)
+	if err != nil {
+		return err
+	}
+	drift := getSyncDrift(video, live)
+	output.ResultText(getSyncDriftText(drift))
+	if len(drift) == 0 {
+		return nil
+	}
+	resolutions := make([]string, len(drift))
+	fields := []huh.Field{}
+	for i, field := range drift {
+		options := []huh.Option[string]{huh.NewOption("Keep as is", "")}
+		if field.Pullable {
+			options = append(options, huh.NewOption("Pull from YouTube", syncPull))
+		}
+		options = append(options, huh.NewOption("Push to YouTube", syncPush))
+		title := field.Field
+		if !field.Pullable {
+			title = fmt.Sprintf("%s (composed from several fields, so it can only be pushed)", field.Field)
+		}
+		fields = append(fields, huh.NewSelect[string]().Title(title).Options(options...).Value(&resolutions[i]))
+	}
+	if err := runForm(newForm(huh.NewGroup(fields...))); err != nil {
+		return err
+	}
+	selected := map[string]string{}
+	for i, field := range drift {
+		if len(resolutions[i]) > 0 {
+			selected[field.Field] = resolutions[i]
+		}
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+	video, err = applySyncResolutions(service, video, live, selected)
+	if err != nil {
+		return err
+	}
+	youtubeSyncCache.Invalidate(video.VideoId)
+	yaml := YAML{}
+	yaml.WriteVideo(&video, video.Path)
+	output.Info(fmt.Sprintf("%s was synced with YouTube.", video.Name))
+	return nil
+}
+
+// ChooseCleanup archives or deletes the render file of a published video, and optionally its raw footage, after YouTube confirms it has the same video.
+// Nothing is removed before the exact list of what will be removed is confirmed.
+// ChooseShowStats prints the YouTube statistics of the video, fetching them first if the stored snapshot is stale.
+func (c *Choices) ChooseShowStats(video Video) error {
+	refreshed, note, err := refreshVideoAnalytics(youTubeStatsService{}, &video, time.Duration(settings.YouTube.StatsTTLHours)*time.Hour, time.Now())
+	if err != nil {
+		return err
+	}
+	if refreshed {
+		yaml := YAML{}
+		if err := yaml.writeVideo(&video, video.Path); err != nil {
+			return err
+		}
+	}
+	output.ResultText(getAnalyticsText(video.Analytics, note))
+	return nil
+}
+
+func (c *Choices) ChooseCleanup(video Video) error {
+	mode := cleanupModeArchive
+	includeMaterial := false
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("What should be done with the render file?").
+				Options(
+					huh.NewOption(fmt.Sprintf("Move to %s", settings.Cleanup.ArchiveDir), cleanupModeArchive),
+					huh.NewOption("Delete", cleanupModeDelete),
+				).
+				Value(&mode),
+			huh.NewConfirm().Title(fmt.Sprintf("Include the raw footage (%s)?", getMaterialDir(video))).Value(&includeMaterial),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
 	}
-	if save {
-		yaml := YAML{}
-		yaml.WriteVideo(video, video.Path)
+	plan, err := getCleanupPlan(video, mode, includeMaterial, settings.Cleanup.ArchiveDir)
+	if err != nil {
+		return err
 	}
-	video.Edit.Completed, video.Edit.Total = c.Count([]interface{}{
-		video.Thumbnail,
-		video.Thumbnail02,
-		video.Thumbnail03,
-		video.Members,
-		video.RequestEdit,
-		video.Movie,
-		video.Slides,
-	})
-	video.Edit.Total++
-	if !strings.Contains(video.Timecodes, "TODO:") {
-		video.Edit.Completed++
+	service, err := newYouTubeService()
+	if err != nil {
+		return err
 	}
-	if !requestEditOrig && video.RequestEdit {
-		email := NewEmail(settings.Email.Password)
-		if err = email.SendEdit(settings.Email.From, settings.Email.EditTo, video); err != nil {
-			return video, err
-		}
+	processing, err := fetchYouTubeProcessing(service, video.VideoId)
+	if err != nil {
+		return err
 	}
-	if save {
+	if _, err := cleanupRender(video, plan, processing, getMediaDuration, true, time.Now()); err != nil {
+		return err
+	}
+	confirmed := false
+	form = newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("The video on YouTube matches the local file. Would you like to continue?").
+				Description(getCleanupPlanText(plan)).
+				Affirmative("Clean up").
+				Negative("Cancel").
+				Value(&confirmed),
+		),
+	)
+	if err := runForm(form); err != nil || !confirmed {
+		return err
+	}
+	video, err = cleanupRender(video, plan, processing, getMediaDuration, false, time.Now())
+	if len(video.RenderCleanup.Path) > 0 {
 		yaml := YAML{}
-		yaml.WriteVideo(video, video.Path)
+		yaml.WriteVideo(&video, video.Path)
 	}
-	return video, err
+	if err != nil {
+		return err
+	}
+	output.Info(fmt.Sprintf("The render files of %s were cleaned up.", video.Name))
+	return nil
 }
 
-func (c *Choices) ChoosePublish(video Video) (Video, error) {
-	save := true
-	sponsorsNotifyText := "Sponsors notify"
-	notifiedSponsorsOrig := video.NotifiedSponsors
-	if video.NotifiedSponsors || len(video.Sponsorship.Amount) == 0 || video.Sponsorship.Amount == "N/A" || video.Sponsorship.Amount == "-" {
-		sponsorsNotifyText = greenStyle.Render(sponsorsNotifyText)
-	} else {
-		sponsorsNotifyText = redStyle.Render(sponsorsNotifyText)
+// ChooseYouTubeDrift lists uploaded videos that drifted from YouTube and offers to sync one of them.
+func (c *Choices) ChooseYouTubeDrift(index []VideoIndex) error {
+	service, err := newYouTubeService()
+	if err != nil {
+		return err
 	}
-	createHugo := video.HugoPath != ""
-	fields := []huh.Field{
-		huh.NewConfirm().Title(c.ColorFromBool("Create Hugo Post", createHugo)).Value(&createHugo),
-		huh.NewInput().Title(c.ColorFromString("Upload video", video.UploadVideo)).Value(&video.UploadVideo),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("Twitter post", video.TweetPosted)).Value(&video.TweetPosted),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("LinkedIn post", video.LinkedInPosted)).Value(&video.LinkedInPosted),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("Slack post", video.SlackPosted)).Value(&video.SlackPosted),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("Hacker News post", video.HNPosted)).Value(&video.HNPosted),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("Technology Conversations post", video.TCPosted)).Value(&video.TCPosted),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("YouTube Highlight", video.YouTubeHighlight)).Value(&video.YouTubeHighlight),
-		huh.NewConfirm().Title(c.ColorFromBool("Pinned comment", video.YouTubeComment)).Value(&video.YouTubeComment),
-		huh.NewConfirm().Title(c.ColorFromBool("Replies to comments", video.YouTubeCommentReply)).Value(&video.YouTubeCommentReply),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("https://gde.advocu.com post", video.GDE)).Value(&video.GDE),
-		// TODO: Automate
-		huh.NewConfirm().Title(c.ColorFromBool("Twitter Spaces post", video.TwitterSpace)).Value(&video.TwitterSpace),
-		huh.NewInput().Title(c.ColorFromString("Code repo", video.Repo)).Value(&video.Repo),
-		huh.NewConfirm().Title(sponsorsNotifyText).Value(&video.NotifiedSponsors),
+	videos := []Video{}
+	for _, video := range c.getVideos(index) {
+		if len(video.VideoId) > 0 {
+			videos = append(videos, video)
+		}
 	}
-	for index := range fields {
-		uploadVideoOrig := video.UploadVideo
-		tweetPostedOrig := video.TweetPosted
-		linkedInPostedOrig := video.LinkedInPosted
-		slackPostedOrig := video.SlackPosted
-		hnPostedOrig := video.HNPosted
-		tcPosted := video.TCPosted
-		twitterSpaceOrig := video.TwitterSpace
-		repoOrig := video.Repo
-		form := huh.NewForm(
+	drift, err := youtubeSyncCache.HasDrift(service, videos, time.Now())
+	if err != nil {
+		return err
+	}
+	options := []huh.Option[int]{}
+	for i, video := range videos {
+		if drift[video.VideoId] {
+			options = append(options, huh.NewOption(video.Name, i))
+		}
+	}
+	if len(options) == 0 {
+		output.Result("None of the uploaded videos drifted from YouTube.")
+		return nil
+	}
+	selected := -1
+	options = append(options, huh.NewOption("Return", -1))
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().Title(fmt.Sprintf("%d videos drifted from YouTube. Which one would you like to sync?", len(options)-1)).Options(options...).Value(&selected),
+		),
+	)
+	if err := runForm(form); err != nil || selected < 0 {
+		return err
+	}
+	return c.ChooseYouTubeSync(videos[selected])
+}
+
+// ChooseMoveVideo moves the video to another category and returns the index with its entry updated.
+// When the destination already has files with the same name, the video can be renamed, the files overwritten, or the move aborted.
+func (c *Choices) ChooseMoveVideo(vi []VideoIndex, video Video) ([]VideoIndex, error) {
+	categories, err := c.getCategories()
+	if err != nil {
+		return vi, err
+	}
+	category := video.Category
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("To which category would you like to move the video?").Options(categories...).Value(&category),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return vi, err
+	}
+	resolution := moveResolutionAbort
+	moved, err := moveVideo(video, category, c.GetDirPath(category), resolution)
+	var collision *ErrMoveCollision
+	if errors.As(err, &collision) {
+		options := []huh.Option[int]{
+			huh.NewOption(fmt.Sprintf("Move it as %s", collision.SuggestedName), moveResolutionRename),
+			huh.NewOption("Overwrite the existing files", moveResolutionOverwrite),
+			huh.NewOption("Abort", moveResolutionAbort),
+		}
+		form := newForm(
 			huh.NewGroup(
-				fields[index],
-				huh.NewConfirm().Affirmative("Save & continue").Negative("Cancel").Value(&save),
+				huh.NewSelect[int]().
+					Title(fmt.Sprintf("The destination already contains:\n%s", strings.Join(collision.Paths, "\n"))).
+					Options(options...).
+					Value(&resolution),
 			),
 		)
-		err := form.Run()
-		if err != nil {
-			return Video{}, err
+		if err := runForm(form); err != nil {
+			return vi, err
 		}
-		video.Publish.Completed, video.Publish.Total = c.Count([]interface{}{
-			video.HugoPath,
-			video.UploadVideo,
-			video.TweetPosted,
-			video.LinkedInPosted,
-			video.SlackPosted,
-			video.HNPosted,
-			video.TCPosted,
-			video.YouTubeHighlight,
-			video.YouTubeComment,
-			video.YouTubeCommentReply,
-			video.GDE,
-			video.TwitterSpace,
-			video.Repo,
-		})
-		video.Publish.Total++
-		if video.NotifiedSponsors || len(video.Sponsorship.Amount) == 0 || video.Sponsorship.Amount == "N/A" || video.Sponsorship.Amount == "-" {
-			video.Publish.Completed++
-		}
-		if createHugo && len(video.HugoPath) == 0 {
-			hugo := Hugo{}
-			video.HugoPath, err = hugo.Post(video.Gist, video.Title, video.Date)
-			if err != nil {
-				return Video{}, err
+		if resolution == moveResolutionOverwrite {
+			confirmed := false
+			form := newForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title(fmt.Sprintf("%s will be deleted. Are you sure?", strings.Join(collision.Paths, ", "))).
+						Affirmative("Overwrite").
+						Negative("Abort").
+						Value(&confirmed),
+				),
+			)
+			if err := runForm(form); err != nil {
+				return vi, err
+			}
+			if !confirmed {
+				resolution = moveResolutionAbort
 			}
-		} else if !createHugo {
-			video.HugoPath = ""
-		}
-		if len(uploadVideoOrig) == 0 && len(video.UploadVideo) > 0 {
-			video.VideoId = uploadVideo(video)
-			uploadThumbnail(video)
-			// TODO: Automate
-			println(confirmationStyle.Render(`Following should be set manually:
-- End screen
-- Playlists
-- Language
-- Monetization`))
-		}
-		twitter := Twitter{}
-		if !tweetPostedOrig && len(video.Tweet) > 0 && video.TweetPosted {
-			twitter.Post(video.Tweet, video.VideoId)
-		}
-		if !linkedInPostedOrig && len(video.Tweet) > 0 && video.LinkedInPosted {
-			postLinkedIn(video.Tweet, video.VideoId)
-		}
-		if !slackPostedOrig && len(video.VideoId) > 0 && video.SlackPosted {
-			postSlack(video.VideoId)
-		}
-		if !hnPostedOrig && len(video.VideoId) > 0 && video.HNPosted {
-			postHackerNews(video.Title, video.VideoId)
-		}
-		if !tcPosted && len(video.VideoId) > 0 && video.TCPosted {
-			postTechnologyConversations(video.Title, video.Description, video.VideoId, video.Gist, video.ProjectName, video.ProjectURL, video.RelatedVideos)
-		}
-		if !twitterSpaceOrig && len(video.VideoId) > 0 && video.TwitterSpace {
-			twitter.PostSpace(video.VideoId)
-		}
-		if len(repoOrig) == 0 && len(video.Repo) > 0 && video.Repo != "N/A" {
-			repo := Repo{}
-			repo.Update(video.Repo, video.Title, video.VideoId)
-		}
-		if !notifiedSponsorsOrig && video.NotifiedSponsors {
-			email := NewEmail(settings.Email.Password)
-			email.SendSponsors(settings.Email.From, video.Sponsorship.Emails, video.VideoId, video.Sponsorship.Amount)
 		}
-		if !save {
-			break
+		if resolution == moveResolutionAbort {
+			return vi, nil
 		}
-		yaml := YAML{}
-		yaml.WriteVideo(video, video.Path)
-	}
-	return video, nil
-}
-
-func (c *Choices) ColorFromSponsoredEmails(title, sponsored string, sponsoredEmails string) (string, bool) {
-	if len(sponsored) == 0 || sponsored == "N/A" || sponsored == "-" || len(sponsoredEmails) > 0 {
-		return greenStyle.Render(title), true
-	}
-	return redStyle.Render(title), false
-}
-
-func (c *Choices) ColorFromString(title, value string) string {
-	if len(value) > 0 {
-		return greenStyle.Render(title)
-	}
-	return redStyle.Render(title)
-}
-
-func (c *Choices) ColorFromStringInverse(title, value string) string {
-	if len(value) > 0 {
-		return redStyle.Render(title)
+		moved, err = moveVideo(video, category, c.GetDirPath(category), resolution)
 	}
-	return greenStyle.Render(title)
-}
-
-func (c *Choices) ColorFromBool(title string, value bool) string {
-	if value {
-		return greenStyle.Render(title)
+	output.Event(outputActionMove, video.Path, moved.Path, err)
+	if err != nil {
+		return vi, err
 	}
-	return redStyle.Render(title)
+	output.Info(fmt.Sprintf("Video %s was moved to %s.", moved.Name, moved.Path))
+	return setMovedIndexEntry(vi, video.Index, VideoIndex{Name: moved.Name, Category: category}), nil
 }
 
-func (c *Choices) ChooseVideosPhase(vi []VideoIndex) bool {
-	var selection int
-	phases := make(map[int]int)
-	for i := range vi {
-		phase := c.GetVideoPhase(vi[i])
-		phases[phase] = phases[phase] + 1
+// ChooseMoveVideos moves the selected videos to another category and writes the index with the entries of those that were moved.
+func (c *Choices) ChooseMoveVideos(vi []VideoIndex, videos []Video) error {
+	categories, err := c.getCategories()
+	if err != nil {
+		return err
 	}
 	options := huh.NewOptions[int]()
-	if text, count := c.GetPhaseColoredText(phases, videosPhasePublished, "Published"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhasePublished))
+	for i, video := range videos {
+		options = append(options, huh.NewOption(getVideoOptionTitle(video, time.Now()), i))
 	}
-	if text, count := c.GetPhaseColoredText(phases, videosPhasePublishPending, "Pending publish"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhasePublishPending))
+	selected := []int{}
+	category := ""
+	form := newForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().Title("Which videos would you like to move?").Options(options...).Value(&selected),
+			huh.NewSelect[string]().Title("To which category would you like to move them?").Options(categories...).Value(&category),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
 	}
-	if text, count := c.GetPhaseColoredText(phases, videosPhaseEditRequested, "Edit requested"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhaseEditRequested))
+	if len(selected) == 0 {
+		return nil
 	}
-	if text, count := c.GetPhaseColoredText(phases, videosPhaseMaterialDone, "Material done"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhaseMaterialDone))
+	batch := []Video{}
+	for _, i := range selected {
+		batch = append(batch, videos[i])
 	}
-	if text, count := c.GetPhaseColoredText(phases, videosPhaseStarted, "Started"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhaseStarted))
+	results := moveVideos(vi, batch, category, c.GetDirPath(category))
+	yaml := YAML{IndexPath: "index.yaml"}
+	yaml.WriteIndex(vi)
+	output.ResultText(getMoveResultsText(results))
+	return nil
+}
+
+// ChooseSearch searches the full-text index or, if it is not available, names, titles, tags, and descriptions, and asks what to do with the selected video.
+func (c *Choices) ChooseSearch(vi []VideoIndex) error {
+	query := ""
+	form := newForm(huh.NewGroup(huh.NewInput().Title("Search").Value(&query)))
+	if err := runForm(form); err != nil {
+		return err
 	}
-	if text, count := c.GetPhaseColoredText(phases, videosPhaseDelayed, "Delayed"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhaseDelayed))
+	highlight := func(word string) string { return orangeStyle.Render(word) }
+	videos := c.getVideos(vi)
+	var results []SearchResult
+	if index, err := loadSearchIndex(searchIndexPath); err == nil {
+		results = index.Search(query)
+		index.addSearchSnippets(results, query, highlight)
+	} else {
+		if !os.IsNotExist(err) {
+			output.Error(err.Error())
+		}
+		output.Info("Manuscripts are not searched since the search index is not available. Use Rebuild Search Index to create it.")
+		results = searchVideoMetadata(videos, query, highlight)
 	}
-	if text, count := c.GetPhaseColoredText(phases, videosPhaseSponsoredBlocked, "Sponsored blocked"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhaseSponsoredBlocked))
+	results, resultVideos := getSearchResultVideos(results, videos)
+	if len(results) == 0 {
+		output.Result(fmt.Sprintf("Nothing matches %q.", query))
+		return nil
 	}
-	if text, count := c.GetPhaseColoredText(phases, videosPhaseIdeas, "Ideas"); count > 0 {
-		options = append(options, huh.NewOption(text, videosPhaseIdeas))
+	lines := []string{}
+	options := huh.NewOptions[int]()
+	for i, result := range results {
+		title := fmt.Sprintf("%s (%s, %s)", result.Name, result.Category, getPhaseTitle(result.Phase))
+		lines = append(lines, title)
+		if len(result.Snippet) > 0 {
+			lines = append(lines, fmt.Sprintf("    %s", result.Snippet))
+		}
+		options = append(options, huh.NewOption(title, i))
 	}
+	output.ResultText(strings.Join(lines, "\n"))
 	options = append(options, huh.NewOption("Return", actionReturn))
-	form := huh.NewForm(
+	selected := actionReturn
+	form = newForm(
 		huh.NewGroup(
 			huh.NewSelect[int]().
-				Title("From which phase would you like to list the videos?").
+				Title("Which video would you like to work on?").
 				Options(options...).
-				Value(&selection),
+				Value(&selected),
 		),
 	)
-	err := form.Run()
-	if err != nil {
-		log.Fatal(err)
+	if err := runForm(form); err != nil {
+		return err
 	}
-	if selection == actionReturn {
-		return true
+	if selected == actionReturn {
+		return nil
 	}
-	c.ChooseVideos(vi, selection)
-	return false
+	c.ChooseVideoAction(vi, resultVideos[selected])
+	return nil
 }
 
-func (c *Choices) GetVideoPhase(vi VideoIndex) int {
+// getVideo reads the video at the index position. If the video is relocated, the entry is updated in place so that callers writing the index later keep the new location.
+func (c *Choices) getVideo(vi []VideoIndex, index int) Video {
 	yaml := YAML{}
-	video := yaml.GetVideo(c.GetFilePath(vi.Category, vi.Name, "yaml"))
-	// TODO: Remove
-	if len(video.Sponsorship.Blocked) == 0 {
-		video.Sponsorship.Blocked = video.SponsorshipBlocked
-	}
-	if video.Delayed {
-		return videosPhaseDelayed
-	} else if len(video.Sponsorship.Blocked) > 0 {
-		return videosPhaseSponsoredBlocked
-	} else if len(video.Repo) > 0 {
-		return videosPhasePublished
-	} else if len(video.UploadVideo) > 0 && len(video.Tweet) > 0 {
-		return videosPhasePublishPending
-	} else if video.RequestEdit {
-		return videosPhaseEditRequested
-	} else if video.Code && video.Screen && video.Head && video.Diagrams {
-		return videosPhaseMaterialDone
-	} else if len(video.Date) > 0 {
-		return videosPhaseStarted
-	} else {
-		return videosPhaseIdeas
+	path := c.GetFilePath(vi[index].Category, vi[index].Name, "yaml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if relocated, ok := c.ChooseRelocateVideo(vi, index, path); ok {
+			relocated.Index = index
+			return relocated
+		}
 	}
+	video := yaml.GetVideo(path)
+	video.Name = vi[index].Name
+	video.Path = path
+	video.Index = index
+	video.Category = vi[index].Category
+	return video
 }
 
-func (c *Choices) ChooseVideos(vi []VideoIndex, phase int) {
-	var selectedVideo Video
-	var selectedAction int
-	options := huh.NewOptions[Video]()
-	sortedVideos := []Video{}
-	for i := range vi {
-		videoPhase := c.GetVideoPhase(vi[i])
-		if videoPhase == phase {
-			yaml := YAML{}
-			path := c.GetFilePath(vi[i].Category, vi[i].Name, "yaml")
-			video := yaml.GetVideo(path)
-			video.Name = vi[i].Name
-			video.Path = path
-			video.Index = i
-			video.Category = vi[i].Category
-			sortedVideos = append(sortedVideos, video)
-		}
-	}
-	sort.Slice(sortedVideos, func(i, j int) bool {
-		date1, _ := time.Parse("2006-01-02T15:04", sortedVideos[i].Date)
-		date2, _ := time.Parse("2006-01-02T15:04", sortedVideos[j].Date)
-		return date1.Before(date2)
-	})
-	for _, video := range sortedVideos {
-		title := video.Name
-		if len(video.Sponsorship.Blocked) > 0 && video.Sponsorship.Blocked != "-" && video.Sponsorship.Blocked != "N/A" {
-			title = fmt.Sprintf("%s (%s)", title, video.Sponsorship.Blocked)
-		} else {
-			if len(video.Date) > 0 {
-				title = fmt.Sprintf("%s (%s)", title, video.Date)
-			}
-			if len(video.Sponsorship.Amount) > 0 && video.Sponsorship.Amount != "-" && video.Sponsorship.Amount != "N/A" {
-				title = fmt.Sprintf("%s (sponsored)", title)
-			}
-			if video.Category == "ama" {
-				title = fmt.Sprintf("%s (AMA)", title)
-			}
-		}
+// videoRelocationAsked remembers missing videos that were already handled in this session so that the question is not repeated in every list.
+var videoRelocationAsked = map[string]bool{}
 
-		options = append(options, huh.NewOption(title, video))
+// ChooseRelocateVideo offers to fix a video whose category directory was renamed outside of the tool. It does nothing unless exactly one category has a video with the same name.
+func (c *Choices) ChooseRelocateVideo(vi []VideoIndex, index int, path string) (Video, bool) {
+	if videoRelocationAsked[path] {
+		return Video{}, false
+	}
+	videoRelocationAsked[path] = true
+	newPath, err := findVideoLocation("manuscript", path, getManuscriptIgnore())
+	if err != nil {
+		output.Error(err.Error())
+		return Video{}, false
+	}
+	if len(newPath) == 0 {
+		return Video{}, false
 	}
-	form := huh.NewForm(
+	fix := true
+	form := newForm(
 		huh.NewGroup(
-			huh.NewSelect[Video]().
-				Title("Which video would you like to work on?").
-				Options(options...).
-				Value(&selectedVideo),
-			huh.NewSelect[int]().
-				Title("What would you like to do with the video?").
-				Options(c.getActionOptions()...).
-				Value(&selectedAction),
+			huh.NewConfirm().
+				Title(fmt.Sprintf("%s was not found but %s exists. Update the video and the index to point to it?", path, newPath)).
+				Affirmative("Update").
+				Negative("Skip").
+				Value(&fix),
 		),
 	)
-
-	err := form.Run()
+	if err := runForm(form); err != nil || !fix {
+		return Video{}, false
+	}
+	yaml := YAML{IndexPath: "index.yaml"}
+	video, updated, err := relocateVideo(vi[index], path, newPath, vi, yaml.writeVideo)
+	output.Event(outputActionRelocate, path, newPath, err)
 	if err != nil {
-		log.Fatal(err)
+		output.Error(err.Error())
+		return Video{}, false
 	}
-	switch selectedAction {
-	case actionEdit:
-		choices := Choices{}
-		choices.ChoosePhase(selectedVideo)
-	case actionDelete:
-		shPath := strings.ReplaceAll(selectedVideo.Path, ".yaml", ".md")
-		if os.Remove(shPath) != nil {
-			panic(err)
+	vi[index] = updated[index]
+	yaml.WriteIndex(vi)
+	recordAudit(newPath, AuditEntry{Action: auditActionRelocate, From: path, To: newPath})
+	output.Info(fmt.Sprintf("Video %s now points to %s.", vi[index].Name, newPath))
+	return video, true
+}
+
+func (c *Choices) getScheduledVideos(excludePath string) []ScheduledVideo {
+	yaml := YAML{IndexPath: "index.yaml"}
+	vi := yaml.GetIndex()
+	scheduled := []ScheduledVideo{}
+	for i := range vi {
+		video := c.getVideo(vi, i)
+		if video.Path == excludePath {
+			continue
 		}
-		os.Remove(selectedVideo.Path)
-		// selectedVideoIndex = vi[len(vi)-1]
-		vi = append(vi[:selectedVideo.Index], vi[selectedVideo.Index+1:]...)
-	case actionReturn:
-		return
+		date, err := time.Parse(dateFormat, video.Date)
+		if err != nil {
+			continue
+		}
+		scheduled = append(scheduled, ScheduledVideo{Name: video.Name, Date: date, Immovable: workflow.IsSponsored(video.Sponsorship)})
 	}
-	yaml := YAML{IndexPath: "index.yaml"}
-	yaml.WriteIndex(vi)
+	return scheduled
+}
+
+func (c *Choices) getVideos(vi []VideoIndex) []Video {
+	positions := make([]int, len(vi))
+	for i := range vi {
+		positions[i] = i
+	}
+	return c.getIndexedVideos(vi, positions)
 }
 
 func (c *Choices) IsEmpty(str string) error {
@@ -966,16 +3884,27 @@ func (c *Choices) GetOptionTextFromString(title, value string) (string, bool) {
 	return title, completed
 }
 
-func (c *Choices) getCreateVideoFields(name, category *string, save *bool) ([]huh.Field, error) {
+func (c *Choices) getCreateVideoFields(name, category, templateName *string, save *bool) ([]huh.Field, error) {
 	categories, err := c.getCategories()
 	if err != nil {
 		return nil, err
 	}
-	return []huh.Field{
+	templates, err := getManuscriptTemplates(templatesDir)
+	if err != nil {
+		return nil, err
+	}
+	fields := []huh.Field{
 		huh.NewInput().Prompt("Name: ").Value(name),
 		huh.NewSelect[string]().Title("Category").Options(categories...).Value(category),
-		huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(save),
-	}, nil
+	}
+	if len(templates) > 0 {
+		options := []huh.Option[string]{huh.NewOption("Empty", templateEmpty)}
+		for _, template := range templates {
+			options = append(options, huh.NewOption(template, template))
+		}
+		fields = append(fields, huh.NewSelect[string]().Title("Template").Options(options...).Value(templateName))
+	}
+	return append(fields, huh.NewConfirm().Affirmative("Save").Negative("Cancel").Value(save)), nil
 }
 
 func (c *Choices) getCategories() ([]huh.Option[string], error) {
@@ -984,8 +3913,9 @@ func (c *Choices) getCategories() ([]huh.Option[string], error) {
 		return nil, err
 	}
 	options := huh.NewOptions[string]()
+	ignore := getManuscriptIgnore()
 	for _, file := range files {
-		if file.IsDir() {
+		if file.IsDir() && !ignore.Match(file.Name(), true) {
 			caser := cases.Title(language.AmericanEnglish)
 			categoryKey := strings.ReplaceAll(file.Name(), "-", " ")
 			categoryKey = caser.String(categoryKey)
@@ -999,6 +3929,21 @@ func (c *Choices) getIndexOptions() []huh.Option[int] {
 	return []huh.Option[int]{
 		huh.NewOption("List Videos", indexListVideos),
 		huh.NewOption("Create Video", indexCreateVideo),
+		huh.NewOption("Search Videos", indexSearch),
+		huh.NewOption("Talks", indexTalks),
+		huh.NewOption("AI Feedback Report", indexAIFeedback),
+		huh.NewOption("Costs Report", indexCostsReport),
+		huh.NewOption("Unpaid Invoices", indexInvoices),
+		huh.NewOption("Trends", indexTrends),
+		huh.NewOption("Workload", indexWorkload),
+		huh.NewOption("Podcast Episodes", indexPodcast),
+		huh.NewOption("YouTube Drift", indexYouTubeDrift),
+		huh.NewOption("Normalize Tags", indexNormalizeTags),
+		huh.NewOption("Bulk Find & Replace", indexBulkReplace),
+		huh.NewOption("Regenerate Hugo Posts", indexRegenerateHugo),
+		huh.NewOption("Rebuild Search Index", indexRebuildSearch),
+		huh.NewOption("Rebuild Index", indexRebuildIndex),
+		huh.NewOption("Send Test Email", indexSendTestEmail),
 		huh.NewOption("Exit", actionReturn),
 	}
 }
@@ -1006,8 +3951,22 @@ func (c *Choices) getIndexOptions() []huh.Option[int] {
 func (c *Choices) getActionOptions() []huh.Option[int] {
 	return []huh.Option[int]{
 		huh.NewOption("Edit", actionEdit),
+		huh.NewOption("Focus on this video", actionFocus),
 		huh.NewOption("Delete", actionDelete),
-		// TODO: Add the option to move video files to a different directory
+		huh.NewOption("Move", actionMove),
+		huh.NewOption("Compare with uploaded", actionCompareUploaded),
+		huh.NewOption("Sync with YouTube", actionSyncYouTube),
+		huh.NewOption("Nudge sponsor", actionNudgeSponsor),
+		huh.NewOption("Lint description", actionLintDescription),
+		huh.NewOption("Promote as channel highlight", actionPromoteHighlight),
+		huh.NewOption("Create refreshed version", actionRefresh),
+		huh.NewOption("Manage secrets", actionSecrets),
+		huh.NewOption("Clean up render files", actionCleanup),
+		huh.NewOption("Replace thumbnail", actionReplaceThumbnail),
+		huh.NewOption("Record session", actionRecordSession),
+		huh.NewOption("Manage attributions", actionAttributions),
+		huh.NewOption("View history", actionHistory),
+		huh.NewOption("Show stats", actionShowStats),
 		huh.NewOption("Return", actionReturn),
 	}
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"slices"
 	"testing"
 
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
 	"github.com/charmbracelet/huh"
 )
 
@@ -34,9 +36,9 @@ func TestChoices_getCategories(t *testing.T) {
 
 func TestChoices_getCreateVideoFields(t *testing.T) {
 	choices := &Choices{}
-	var name, category string
+	var name, category, templateName string
 	var save bool
-	fields, err := choices.getCreateVideoFields(&name, &category, &save)
+	fields, err := choices.getCreateVideoFields(&name, &category, &templateName, &save)
 	if err != nil {
 		t.Errorf("Error occurred while getting fields: %v", err)
 	}
@@ -52,6 +54,21 @@ func TestChoices_getIndexOptions(t *testing.T) {
 	expectedIndexOptions := []huh.Option[int]{
 		huh.NewOption("List Videos", indexListVideos),
 		huh.NewOption("Create Video", indexCreateVideo),
+		huh.NewOption("Search Videos", indexSearch),
+		huh.NewOption("Talks", indexTalks),
+		huh.NewOption("AI Feedback Report", indexAIFeedback),
+		huh.NewOption("Costs Report", indexCostsReport),
+		huh.NewOption("Unpaid Invoices", indexInvoices),
+		huh.NewOption("Trends", indexTrends),
+		huh.NewOption("Workload", indexWorkload),
+		huh.NewOption("Podcast Episodes", indexPodcast),
+		huh.NewOption("YouTube Drift", indexYouTubeDrift),
+		huh.NewOption("Normalize Tags", indexNormalizeTags),
+		huh.NewOption("Bulk Find & Replace", indexBulkReplace),
+		huh.NewOption("Regenerate Hugo Posts", indexRegenerateHugo),
+		huh.NewOption("Rebuild Search Index", indexRebuildSearch),
+		huh.NewOption("Rebuild Index", indexRebuildIndex),
+		huh.NewOption("Send Test Email", indexSendTestEmail),
 		huh.NewOption("Exit", actionReturn),
 	}
 	if len(indexOptions) != len(expectedIndexOptions) {
@@ -68,7 +85,22 @@ func TestChoices_getActionOptions(t *testing.T) {
 	actionOptions := choices.getActionOptions()
 	expectedActionOptions := []huh.Option[int]{
 		huh.NewOption("Edit", actionEdit),
+		huh.NewOption("Focus on this video", actionFocus),
 		huh.NewOption("Delete", actionDelete),
+		huh.NewOption("Move", actionMove),
+		huh.NewOption("Compare with uploaded", actionCompareUploaded),
+		huh.NewOption("Sync with YouTube", actionSyncYouTube),
+		huh.NewOption("Nudge sponsor", actionNudgeSponsor),
+		huh.NewOption("Lint description", actionLintDescription),
+		huh.NewOption("Promote as channel highlight", actionPromoteHighlight),
+		huh.NewOption("Create refreshed version", actionRefresh),
+		huh.NewOption("Manage secrets", actionSecrets),
+		huh.NewOption("Clean up render files", actionCleanup),
+		huh.NewOption("Replace thumbnail", actionReplaceThumbnail),
+		huh.NewOption("Record session", actionRecordSession),
+		huh.NewOption("Manage attributions", actionAttributions),
+		huh.NewOption("View history", actionHistory),
+		huh.NewOption("Show stats", actionShowStats),
 		huh.NewOption("Return", actionReturn),
 	}
 	if len(actionOptions) != len(expectedActionOptions) {
@@ -80,3 +112,86 @@ func TestChoices_getActionOptions(t *testing.T) {
 		}
 	}
 }
+
+// TestChoices_workflowCompatibility makes sure that the CLI and the workflow package agree on the fixture videos.
+// The phases, counts, and progress were calculated by Choices before they moved to pkg/workflow. Fixed values make sure they did not change.
+func TestChoices_workflowCompatibility(t *testing.T) {
+	choices := &Choices{}
+	subreddits := []SettingsRedditSubreddit{{Name: "kubernetes"}}
+	tests := map[string]struct {
+		video     Video
+		phase     int
+		completed int
+		posted    bool
+	}{
+		"idea":             {video: Video{}, phase: workflow.PhaseIdeas},
+		"started":          {video: Video{Date: "2030-01-21T16:00", Title: "Title", Code: true}, phase: workflow.PhaseStarted, completed: 2},
+		"material done":    {video: Video{Code: true, Screen: true, Head: true, Diagrams: true}, phase: workflow.PhaseMaterialDone, completed: 1},
+		"edit requested":   {video: Video{Code: true, RequestEdit: true}, phase: workflow.PhaseEditRequested, completed: 1},
+		"publish pending":  {video: Video{UploadVideo: "video.mp4", Tweet: "Tweet", PublishPending: []string{publishStepThumbnail}}, phase: workflow.PhasePublishPending, completed: 1},
+		"published":        {video: Video{Repo: "N/A", RedditPosted: map[string]string{"kubernetes": "https://reddit.com/1"}}, phase: workflow.PhasePublished, posted: true},
+		"delayed":          {video: Video{Repo: "N/A", Delayed: true}, phase: workflow.PhaseDelayed},
+		"blocked":          {video: Video{Repo: "N/A", Sponsorship: Sponsorship{Blocked: "No answer"}}, phase: workflow.PhaseSponsoredBlocked},
+		"legacy blocked":   {video: Video{SponsorshipBlocked: "No answer"}, phase: workflow.PhaseSponsoredBlocked},
+		"posted elsewhere": {video: Video{RedditPosted: map[string]string{"devops": "https://reddit.com/2"}}, phase: workflow.PhaseIdeas},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := choices.getPhase(test.video); actual != test.phase {
+				t.Errorf("Expected phase: %d\nGot: %d", test.phase, actual)
+			}
+			if actual := isRedditPosted(test.video.RedditPosted, subreddits); actual != test.posted {
+				t.Errorf("Expected Reddit posted: %t\nGot: %t", test.posted, actual)
+			}
+			if completed, total := choices.Count([]interface{}{test.video.Title, test.video.Code, test.video.PublishPending}); completed != test.completed || total != 3 {
+				t.Errorf("Expected: %d of 3\nGot: %d of %d", test.completed, completed, total)
+			}
+		})
+	}
+}
+
+func TestChoices_workflowCompatibilityProgress(t *testing.T) {
+	video := Video{Date: "2030-01-21T16:00", Code: true, Screen: true, Title: "Title", Tags: "k8s", Thumbnail: "thumbnail.png", Timecodes: "TODO: add"}
+	expected := map[string]Tasks{
+		customFieldPhaseInit:   {Completed: 4, Total: 8},
+		customFieldPhaseWork:   {Completed: 2, Total: 11},
+		customFieldPhaseDefine: {Completed: 2, Total: 10},
+		customFieldPhaseEdit:   {Completed: 1, Total: 9},
+	}
+	for phase, tasks := range expected {
+		if actual := getBuiltInPhaseProgress(video, phase, Settings{}); actual != tasks {
+			t.Errorf("%s: Expected: %+v\nGot: %+v", phase, tasks, actual)
+		}
+	}
+	names := []string{}
+	for _, field := range getBuiltInPhaseFields(video, customFieldPhaseEdit, Settings{}) {
+		names = append(names, field.Name)
+	}
+	expectedNames := []string{"Thumbnail text", "Thumbnail", "Thumbnail 02", "Thumbnail 03", "Members", "Edit request", "Movie", "Slides", "Timecodes"}
+	if !slices.Equal(names, expectedNames) {
+		t.Errorf("Expected: %v\nGot: %v", expectedNames, names)
+	}
+}
+
+func TestChoices_appendKeepCurrentOption(t *testing.T) {
+	tests := map[string]struct {
+		current  string
+		expected int
+	}{
+		"new":     {current: "thumbnail-00.png", expected: 3},
+		"listed":  {current: "thumbnail-01.png", expected: 2},
+		"not set": {expected: 2},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			options := []huh.Option[string]{huh.NewOption("thumbnail-01.png", "thumbnail-01.png"), huh.NewOption("thumbnail-02.png", "thumbnail-02.png")}
+			actual := appendKeepCurrentOption(options, test.current)
+			if len(actual) != test.expected {
+				t.Fatalf("Expected: %d options\nGot: %v", test.expected, actual)
+			}
+			if test.expected == 3 && actual[2].Key != "Keep current (thumbnail-00.png)" {
+				t.Errorf("Expected: Keep current (thumbnail-00.png)\nGot: %s", actual[2].Key)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+	"google.golang.org/api/youtube/v3"
+)
+
+type RenderCleanup = workflow.RenderCleanup
+
+const cleanupModeArchive = "archive"
+const cleanupModeDelete = "delete"
+
+// cleanupDurationTolerance absorbs YouTube rounding durations to whole seconds.
+const cleanupDurationTolerance = 2 * time.Second
+
+const youtubeProcessingSucceeded = "succeeded"
+
+var youtubeDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// YouTubeProcessing is what YouTube knows about the uploaded file.
+type YouTubeProcessing struct {
+	Status   string
+	Duration time.Duration
+}
+
+type ErrCleanupDurationMismatch struct {
+	Local  time.Duration
+	Remote time.Duration
+}
+
+func (e *ErrCleanupDurationMismatch) Error() string {
+	return fmt.Sprintf("the local file is %s long and the video on YouTube %s; nothing was removed", formatTimestamp(e.Local), formatTimestamp(e.Remote))
+}
+
+// CleanupPlan lists exactly what will be removed and, when archiving, where it will be moved.
+type CleanupPlan struct {
+	Mode           string
+	Render         string
+	RenderTarget   string
+	Material       string
+	MaterialTarget string
+}
+
+// parseYouTubeDuration parses ISO 8601 durations as returned by YouTube (e.g., PT1H2M3S).
+func parseYouTubeDuration(value string) (time.Duration, error) {
+	matches := youtubeDurationPattern.FindStringSubmatch(value)
+	if matches == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("%q is not an ISO 8601 duration", value)
+	}
+	duration := time.Duration(0)
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if len(matches[i+1]) > 0 {
+			number, _ := strconv.Atoi(matches[i+1])
+			duration += time.Duration(number) * unit
+		}
+	}
+	return duration, nil
+}
+
+func fetchYouTubeProcessing(service *youtube.Service, videoId string) (YouTubeProcessing, error) {
+	response, err := service.Videos.List([]string{"processingDetails", "contentDetails"}).Id(videoId).Do()
+	if err != nil {
+		return YouTubeProcessing{}, fmt.Errorf("Error getting the video from YouTube: %w", err)
+	}
+	if len(response.Items) == 0 {
+		return YouTubeProcessing{}, fmt.Errorf("video %s was not found on YouTube", videoId)
+	}
+	item := response.Items[0]
+	processing := YouTubeProcessing{}
+	if item.ProcessingDetails != nil {
+		processing.Status = item.ProcessingDetails.ProcessingStatus
+	}
+	if item.ContentDetails != nil {
+		if processing.Duration, err = parseYouTubeDuration(item.ContentDetails.Duration); err != nil {
+			return processing, err
+		}
+	}
+	return processing, nil
+}
+
+// getCleanupPlan fails if there is nothing to clean up. The raw footage is included only when asked for and when it exists.
+func getCleanupPlan(video Video, mode string, includeMaterial bool, archiveDir string) (CleanupPlan, error) {
+	if mode != cleanupModeArchive && mode != cleanupModeDelete {
+		return CleanupPlan{}, fmt.Errorf("%q is not one of %s or %s", mode, cleanupModeArchive, cleanupModeDelete)
+	}
+	if len(video.UploadVideo) == 0 {
+		return CleanupPlan{}, fmt.Errorf("%s has no render file", video.Name)
+	}
+	if info, err := os.Stat(video.UploadVideo); err != nil || info.IsDir() {
+		return CleanupPlan{}, fmt.Errorf("render file %s does not exist", video.UploadVideo)
+	}
+	plan := CleanupPlan{Mode: mode, Render: video.UploadVideo}
+	if includeMaterial {
+		if info, err := os.Stat(getMaterialDir(video)); err == nil && info.IsDir() {
+			plan.Material = getMaterialDir(video)
+		}
+	}
+	if mode == cleanupModeArchive {
+		if len(archiveDir) == 0 {
+			return CleanupPlan{}, fmt.Errorf("cleanup.archiveDir is not set")
+		}
+		dir := filepath.Join(archiveDir, video.Category)
+		plan.RenderTarget = filepath.Join(dir, filepath.Base(plan.Render))
+		if len(plan.Material) > 0 {
+			plan.MaterialTarget = filepath.Join(dir, filepath.Base(plan.Material))
+		}
+		for _, target := range []string{plan.RenderTarget, plan.MaterialTarget} {
+			if _, err := os.Stat(target); len(target) > 0 && err == nil {
+				return CleanupPlan{}, fmt.Errorf("%s already exists", target)
+			}
+		}
+	}
+	return plan, nil
+}
+
+func getCleanupPlanText(plan CleanupPlan) string {
+	lines := []string{}
+	for _, item := range [][2]string{{plan.Render, plan.RenderTarget}, {plan.Material, plan.MaterialTarget}} {
+		switch {
+		case len(item[0]) == 0:
+		case plan.Mode == cleanupModeArchive:
+			lines = append(lines, fmt.Sprintf("Move %s to %s", item[0], item[1]))
+		default:
+			lines = append(lines, fmt.Sprintf("Delete %s", item[0]))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// verifyCleanup is the gate that has to pass before anything is removed. YouTube has to be done processing the video and its duration has to match the local file.
+func verifyCleanup(video Video, processing YouTubeProcessing, local time.Duration) error {
+	if workflow.GetPhase(video) != workflow.PhasePublished {
+		return fmt.Errorf("%s is not published", video.Name)
+	}
+	if processing.Status != youtubeProcessingSucceeded {
+		return fmt.Errorf("YouTube has not finished processing the video (status %q); nothing was removed", processing.Status)
+	}
+	difference := local - processing.Duration
+	if difference < 0 {
+		difference = -difference
+	}
+	if local == 0 || difference > cleanupDurationTolerance {
+		return &ErrCleanupDurationMismatch{Local: local, Remote: processing.Duration}
+	}
+	return nil
+}
+
+func getFileProvenance(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// moveFile falls back to copying when the archive is on another device. The source is removed only after the copy is complete.
+func moveFile(source, target string) error {
+	if err := os.Rename(source, target); err == nil {
+		return nil
+	}
+	if err := copyFile(source, target); err != nil {
+		return err
+	}
+	return os.Remove(source)
+}
+
+// copyFile does not overwrite the target and removes it when the copy fails.
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(target)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(target)
+		return err
+	}
+	return nil
+}
+
+// moveDir works like moveFile for directories. A partial copy is removed so that the source stays the only copy when the move fails.
+func moveDir(source, target string) error {
+	if err := os.Rename(source, target); err == nil {
+		return nil
+	}
+	if err := os.Mkdir(target, 0755); err != nil {
+		return err
+	}
+	if err := copyDir(source, target); err != nil {
+		os.RemoveAll(target)
+		return err
+	}
+	return os.RemoveAll(source)
+}
+
+func copyDir(source, target string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(source, path)
+		if err != nil || relative == "." {
+			return err
+		}
+		if entry.IsDir() {
+			return os.Mkdir(filepath.Join(target, relative), 0755)
+		}
+		return copyFile(path, filepath.Join(target, relative))
+	})
+}
+
+// archiveRenderFiles moves the material before the render file and moves it back when the render file cannot be moved so that a failure leaves nothing archived.
+func archiveRenderFiles(plan CleanupPlan) error {
+	if err := os.MkdirAll(filepath.Dir(plan.RenderTarget), 0755); err != nil {
+		return err
+	}
+	if len(plan.Material) > 0 {
+		if err := moveDir(plan.Material, plan.MaterialTarget); err != nil {
+			return fmt.Errorf("%s could not be archived: %w", plan.Material, err)
+		}
+	}
+	if err := moveFile(plan.Render, plan.RenderTarget); err != nil {
+		if len(plan.Material) > 0 {
+			if restoreErr := moveDir(plan.MaterialTarget, plan.Material); restoreErr != nil {
+				return errors.Join(err, fmt.Errorf("%s could not be moved back from %s: %w", plan.Material, plan.MaterialTarget, restoreErr))
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// cleanupRender verifies the video and, unless it's a dry run, removes what is in the plan and records the provenance of the render file.
+// The returned video has to be written by the caller.
+func cleanupRender(video Video, plan CleanupPlan, processing YouTubeProcessing, probe func(string) (time.Duration, error), dryRun bool, now time.Time) (Video, error) {
+	local, err := probe(plan.Render)
+	if err != nil {
+		return video, fmt.Errorf("the duration of %s could not be read: %w", plan.Render, err)
+	}
+	if err := verifyCleanup(video, processing, local); err != nil {
+		return video, err
+	}
+	size, hash, err := getFileProvenance(plan.Render)
+	if err != nil {
+		return video, err
+	}
+	record := RenderCleanup{Path: plan.Render, Size: size, SHA256: hash, Mode: plan.Mode, ArchivePath: plan.RenderTarget, Material: plan.Material, MaterialArchive: plan.MaterialTarget, Date: now.Format(dateFormat)}
+	if dryRun {
+		return video, nil
+	}
+	if plan.Mode == cleanupModeArchive {
+		err = archiveRenderFiles(plan)
+	} else {
+		err = os.Remove(plan.Render)
+	}
+	if err != nil {
+		output.Event(outputActionCleanup, video.Name, "", err)
+		return video, err
+	}
+	video.RenderCleanup = record
+	if len(plan.Material) > 0 && plan.Mode != cleanupModeArchive {
+		if err := os.RemoveAll(plan.Material); err != nil {
+			video.RenderCleanup.Material, video.RenderCleanup.MaterialArchive = "", ""
+			output.Event(outputActionCleanup, video.Name, "", err)
+			return video, fmt.Errorf("the render file was cleaned up but %s was not: %w", plan.Material, err)
+		}
+	}
+	output.Event(outputActionCleanup, video.Name, plan.Mode, nil)
+	return video, nil
+}
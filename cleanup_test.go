@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func getCleanupTestVideo(t *testing.T) (Video, string) {
+	dir := t.TempDir()
+	render := filepath.Join(dir, "render.mp4")
+	if err := os.WriteFile(render, []byte("render"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing the render file: %v", err)
+	}
+	material := filepath.Join(dir, "material")
+	if err := os.MkdirAll(material, 0755); err != nil {
+		t.Fatalf("Error occurred while creating the material directory: %v", err)
+	}
+	return Video{Name: "something", Category: "ai", UploadVideo: render, Location: material, Repo: "N/A", VideoId: "abc"}, dir
+}
+
+func probeDuration(duration time.Duration) func(string) (time.Duration, error) {
+	return func(string) (time.Duration, error) {
+		return duration, nil
+	}
+}
+
+func TestCleanup_parseYouTubeDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"PT15M33S", 15*time.Minute + 33*time.Second},
+		{"PT1H2S", time.Hour + 2*time.Second},
+		{"P1DT1M", 24*time.Hour + time.Minute},
+		{"PT0S", 0},
+	}
+	for _, test := range tests {
+		if actual, err := parseYouTubeDuration(test.value); err != nil || actual != test.expected {
+			t.Errorf("%s\nExpected: %s\nGot: %s (%v)", test.value, test.expected, actual, err)
+		}
+	}
+	for _, value := range []string{"", "PT", "15:33", "PT1.5S"} {
+		if _, err := parseYouTubeDuration(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestCleanup_cleanupRenderVerificationGate(t *testing.T) {
+	processing := YouTubeProcessing{Status: youtubeProcessingSucceeded, Duration: 10 * time.Minute}
+	tests := []struct {
+		name       string
+		change     func(video *Video, processing *YouTubeProcessing)
+		local      time.Duration
+		isMismatch bool
+	}{
+		{"mismatched duration", func(*Video, *YouTubeProcessing) {}, 9 * time.Minute, true},
+		{"unknown local duration", func(*Video, *YouTubeProcessing) {}, 0, true},
+		{"still processing", func(_ *Video, processing *YouTubeProcessing) { processing.Status = "processing" }, 10 * time.Minute, false},
+		{"not published", func(video *Video, _ *YouTubeProcessing) { video.Repo = "" }, 10 * time.Minute, false},
+	}
+	for _, test := range tests {
+		video, _ := getCleanupTestVideo(t)
+		currentProcessing := processing
+		test.change(&video, &currentProcessing)
+		plan, err := getCleanupPlan(video, cleanupModeDelete, true, "")
+		if err != nil {
+			t.Fatalf("%s: expected a plan, but got %v", test.name, err)
+		}
+		updated, err := cleanupRender(video, plan, currentProcessing, probeDuration(test.local), false, time.Now())
+		mismatch := &ErrCleanupDurationMismatch{}
+		if err == nil || errors.As(err, &mismatch) != test.isMismatch {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if _, err := os.Stat(video.UploadVideo); err != nil {
+			t.Errorf("%s: expected the render file to stay, but got %v", test.name, err)
+		}
+		if _, err := os.Stat(video.Location); err != nil {
+			t.Errorf("%s: expected the raw footage to stay, but got %v", test.name, err)
+		}
+		if len(updated.RenderCleanup.Path) > 0 {
+			t.Errorf("%s: expected no provenance, but got %+v", test.name, updated.RenderCleanup)
+		}
+	}
+}
+
+func TestCleanup_cleanupRenderWithinTolerance(t *testing.T) {
+	video, _ := getCleanupTestVideo(t)
+	plan, _ := getCleanupPlan(video, cleanupModeDelete, false, "")
+	processing := YouTubeProcessing{Status: youtubeProcessingSucceeded, Duration: 10 * time.Minute}
+	if _, err := cleanupRender(video, plan, processing, probeDuration(10*time.Minute+1500*time.Millisecond), true, time.Now()); err != nil {
+		t.Errorf("Expected YouTube rounding to be accepted, but got %v", err)
+	}
+	if _, err := os.Stat(video.UploadVideo); err != nil {
+		t.Errorf("Expected a dry run to keep the render file, but got %v", err)
+	}
+}
+
+func TestCleanup_cleanupRenderArchive(t *testing.T) {
+	video, dir := getCleanupTestVideo(t)
+	archive := filepath.Join(dir, "archive")
+	plan, err := getCleanupPlan(video, cleanupModeArchive, true, archive)
+	if err != nil {
+		t.Fatalf("Expected a plan, but got %v", err)
+	}
+	expectedText := "Move " + video.UploadVideo + " to " + filepath.Join(archive, "ai", "render.mp4") + "\nMove " + video.Location + " to " + filepath.Join(archive, "ai", "material")
+	if actual := getCleanupPlanText(plan); actual != expectedText {
+		t.Errorf("Expected: %s\nGot: %s", expectedText, actual)
+	}
+	processing := YouTubeProcessing{Status: youtubeProcessingSucceeded, Duration: 10 * time.Minute}
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	updated, err := cleanupRender(video, plan, processing, probeDuration(10*time.Minute), false, now)
+	if err != nil {
+		t.Fatalf("Expected the cleanup to succeed, but got %v", err)
+	}
+	if _, err := os.Stat(video.UploadVideo); !os.IsNotExist(err) {
+		t.Errorf("Expected the render file to be moved, but got %v", err)
+	}
+	if data, err := os.ReadFile(plan.RenderTarget); err != nil || string(data) != "render" {
+		t.Errorf("Expected the render file in the archive, but got %q and %v", data, err)
+	}
+	if info, err := os.Stat(plan.MaterialTarget); err != nil || !info.IsDir() {
+		t.Errorf("Expected the raw footage in the archive, but got %v", err)
+	}
+	expected := RenderCleanup{
+		Path:            video.UploadVideo,
+		Size:            6,
+		SHA256:          "887270d0cbc560af35f1326d55e9dbdc35ea2301c2cd26633fb6d4932deee268",
+		Mode:            cleanupModeArchive,
+		ArchivePath:     plan.RenderTarget,
+		Material:        video.Location,
+		MaterialArchive: plan.MaterialTarget,
+		Date:            "2030-01-21T16:00",
+	}
+	if updated.RenderCleanup != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, updated.RenderCleanup)
+	}
+	if _, err := getCleanupPlan(video, cleanupModeArchive, false, archive); err == nil {
+		t.Errorf("Expected no plan once the render file is gone")
+	}
+}
+
+func TestCleanup_cleanupRenderArchiveFailure(t *testing.T) {
+	video, dir := getCleanupTestVideo(t)
+	plan, err := getCleanupPlan(video, cleanupModeArchive, true, filepath.Join(dir, "archive"))
+	if err != nil {
+		t.Fatalf("Expected a plan, but got %v", err)
+	}
+	// A directory in place of the archived render file cannot be replaced, so the render file is not moved.
+	if err := os.MkdirAll(filepath.Join(plan.RenderTarget, "something"), 0755); err != nil {
+		t.Fatalf("Error occurred while creating %s: %v", plan.RenderTarget, err)
+	}
+	processing := YouTubeProcessing{Status: youtubeProcessingSucceeded, Duration: 10 * time.Minute}
+	updated, err := cleanupRender(video, plan, processing, probeDuration(10*time.Minute), false, time.Now())
+	if err == nil {
+		t.Fatalf("Expected the cleanup to fail")
+	}
+	if _, err := os.Stat(video.UploadVideo); err != nil {
+		t.Errorf("Expected the render file to stay, but got %v", err)
+	}
+	if _, err := os.Stat(video.Location); err != nil {
+		t.Errorf("Expected the raw footage to be moved back, but got %v", err)
+	}
+	if _, err := os.Stat(plan.MaterialTarget); !os.IsNotExist(err) {
+		t.Errorf("Expected no raw footage in the archive, but got %v", err)
+	}
+	if updated.RenderCleanup != (RenderCleanup{}) {
+		t.Errorf("Expected no provenance\nGot: %+v", updated.RenderCleanup)
+	}
+}
+
+func TestCleanup_copyDir(t *testing.T) {
+	source, target := t.TempDir(), filepath.Join(t.TempDir(), "material")
+	if err := os.MkdirAll(filepath.Join(source, "raw"), 0755); err != nil {
+		t.Fatalf("Error occurred while creating the source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "raw", "head.mp4"), []byte("head"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing the source: %v", err)
+	}
+	if err := os.Mkdir(target, 0755); err != nil {
+		t.Fatalf("Error occurred while creating the target: %v", err)
+	}
+	if err := copyDir(source, target); err != nil {
+		t.Fatalf("Expected the copy to succeed, but got %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(target, "raw", "head.mp4")); err != nil || string(data) != "head" {
+		t.Errorf("Expected the file to be copied, but got %q and %v", data, err)
+	}
+}
+
+func TestCleanup_cleanupRenderDelete(t *testing.T) {
+	video, _ := getCleanupTestVideo(t)
+	plan, _ := getCleanupPlan(video, cleanupModeDelete, false, "")
+	if actual := getCleanupPlanText(plan); actual != "Delete "+video.UploadVideo {
+		t.Errorf("Expected only the render file to be deleted\nGot: %s", actual)
+	}
+	processing := YouTubeProcessing{Status: youtubeProcessingSucceeded, Duration: 10 * time.Minute}
+	updated, err := cleanupRender(video, plan, processing, probeDuration(10*time.Minute), false, time.Now())
+	if err != nil {
+		t.Fatalf("Expected the cleanup to succeed, but got %v", err)
+	}
+	if _, err := os.Stat(video.UploadVideo); !os.IsNotExist(err) {
+		t.Errorf("Expected the render file to be deleted, but got %v", err)
+	}
+	if _, err := os.Stat(video.Location); err != nil {
+		t.Errorf("Expected the raw footage to stay, but got %v", err)
+	}
+	record := updated.RenderCleanup
+	if record.Mode != cleanupModeDelete || record.Size != 6 || len(record.SHA256) != 64 || len(record.ArchivePath) > 0 || len(record.Material) > 0 {
+		t.Errorf("Unexpected provenance %+v", record)
+	}
+}
+
+func TestCleanup_getCleanupPlanExistingArchive(t *testing.T) {
+	video, dir := getCleanupTestVideo(t)
+	archive := filepath.Join(dir, "archive")
+	os.MkdirAll(filepath.Join(archive, "ai"), 0755)
+	os.WriteFile(filepath.Join(archive, "ai", "render.mp4"), []byte("older"), 0644)
+	if _, err := getCleanupPlan(video, cleanupModeArchive, false, archive); err == nil {
+		t.Errorf("Expected an error instead of overwriting an archived file")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -11,14 +12,54 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "youtube-release",
 	Short: "youtube-release is a super fancy CLI for releasing YouTube videos.",
-	Run:   func(cmd *cobra.Command, args []string) {},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		mode, err := getOutputMode(outputQuiet, outputJSON)
+		if err != nil {
+			return err
+		}
+		output.Mode = mode
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {},
 }
 
 type Settings struct {
-	Email   SettingsEmail
-	AI      SettingsAI
-	YouTube SettingsYouTube
-	Hugo    SettingsHugo
+	Email        SettingsEmail
+	AI           SettingsAI
+	YouTube      SettingsYouTube
+	Hugo         SettingsHugo
+	Teleprompter SettingsTeleprompter
+	UI           SettingsUI
+	Schedule     SettingsSchedule
+	Tags         SettingsTags
+	Names        SettingsNames
+	CustomFields []CustomField
+	Upload       SettingsUpload
+	Assets       SettingsAssets
+	UTM          map[string]string
+	Costs        SettingsCosts
+	Reddit       SettingsReddit
+	Mastodon     SettingsMastodon
+	Slack        SettingsSlack
+	Progress     map[string]map[string]SettingsProgressField
+	Rules        []Rule
+	Sponsorship  SettingsSponsorship
+	Members      SettingsMembers
+	Description  SettingsDescription
+	Community    SettingsCommunity
+	Animations   SettingsAnimations
+	Trends       SettingsTrends
+	Podcast      SettingsPodcast
+	Secrets      SettingsSecrets
+	Import       SettingsImport
+	Workload     SettingsWorkload
+	Cleanup      SettingsCleanup
+	Demo         SettingsDemo
+	Quality      SettingsQuality
+	Content      SettingsContent
+	Record       SettingsRecord
+	Credits      SettingsCredits
+	Calendar     SettingsCalendar
 }
 
 type SettingsEmail struct {
@@ -26,6 +67,7 @@ type SettingsEmail struct {
 	ThumbnailTo string
 	EditTo      string
 	FinanceTo   string
+	ReplyTo     string
 	Password    string
 }
 
@@ -33,25 +75,231 @@ type SettingsHugo struct {
 	Path string
 }
 
+type SettingsTeleprompter struct {
+	LineWidth int
+	HTML      bool
+}
+
+type SettingsUI struct {
+	Theme      string
+	Accessible bool
+	HideHelp   bool
+}
+
+type SettingsSchedule struct {
+	Weekdays   []string
+	Time       string
+	MinGapDays int
+	// FarFuture and Imminent are horizons (e.g., 6w or 7d) that style publish dates further away or closer than them.
+	FarFuture string
+	Imminent  string
+	// Timezone of the publish dates (e.g., Europe/Berlin), used for the calendar and for scheduling uploads. Local is the timezone of the machine.
+	Timezone string
+}
+
+type SettingsTags struct {
+	Aliases map[string]string
+}
+
+type SettingsNames struct {
+	Transliterate bool
+	MaxLength     int
+	Reserved      []string
+}
+
+type SettingsUpload struct {
+	Visibility  string
+	MadeForKids bool
+	Categories  map[string]SettingsUploadCategory
+	// Language is the default language of uploaded videos. When it is empty, it is not sent and YouTube uses the default of the channel.
+	Language string
+}
+
+type SettingsUploadCategory struct {
+	Visibility  string
+	MadeForKids string
+}
+
+type SettingsAssets struct {
+	Cues                   []string
+	RequireForMaterialDone bool
+}
+
+type SettingsCosts struct {
+	Currency string
+}
+
+type SettingsDescription struct {
+	CTAMaxOffset int
+}
+
+type SettingsAnimations struct {
+	CuePrefixes  []string
+	HeaderLevels []int
+}
+
+// SettingsTrends controls the phase count history. IntervalDays is how often a snapshot is recorded and Days how many days the Trends view shows.
+type SettingsTrends struct {
+	IntervalDays int
+	Days         int
+}
+
+// SettingsPodcast enables tracking of videos republished as podcast episodes.
+type SettingsPodcast struct {
+	Enabled bool
+}
+
+// SettingsSecrets points to the file with the key used to encrypt video secrets. The VIDEO_SECRETS_KEY environment variable takes precedence.
+type SettingsSecrets struct {
+	KeyFile string
+}
+
+type SettingsImport struct {
+	MaxRows int
+}
+
+// SettingsQuality holds the weights of the checks that make the definition quality score, keyed by check names (e.g., title or chapters). A weight of zero disables the check.
+type SettingsQuality struct {
+	Weights map[string]int
+}
+
+// SettingsCredits lists the licenses reused assets may have. Assets with licenses in AttributionRequired must be credited in the description.
+type SettingsCredits struct {
+	Licenses            []string
+	AttributionRequired []string
+}
+
+// SettingsCalendar is where the iCalendar feed is published (e.g., https://example.com/calendar.ics) and the feed token from the CALENDAR_TOKEN
+// environment variable. The token is added to the subscription URL as a query parameter.
+type SettingsCalendar struct {
+	URL   string
+	Token string
+}
+
+// SettingsRecord is the checklist of recording sessions. Items of the category of a video, keyed by category directories, are added after the common ones.
+// SectionsSetRecorded marks the talking head and the screen as done once all manuscript sections are recorded.
+type SettingsRecord struct {
+	Checklist           []string
+	Categories          map[string][]string
+	SectionsSetRecorded bool
+}
+
+// SettingsContent holds the disclaimer added to descriptions of videos with content flags and the manuscript keywords that suggest a video should have them.
+type SettingsContent struct {
+	Disclaimer      string
+	TriggerKeywords []string
+}
+
+// SettingsDemo controls the reminders about demo environments that were not destroyed after ReminderDays. Webhook, if set, is called once per environment with its details.
+type SettingsDemo struct {
+	ReminderDays int
+	Webhook      string
+}
+
+// SettingsCleanup is where render files and raw footage are moved when they are archived after publishing.
+type SettingsCleanup struct {
+	ArchiveDir string
+}
+
+// SettingsWorkload estimates the queued production work. Hours are the hours of work left for a video in a phase, keyed by the phase names used by rules.
+// Weighted scales the hours by how much of each video is not completed yet. Sponsored videos published within MustDoDays are listed as must do this week.
+type SettingsWorkload struct {
+	Hours          map[string]float64
+	WeeklyCapacity float64
+	Weighted       bool
+	MustDoDays     int
+}
+
+type SettingsCommunity struct {
+	PostTemplate string
+}
+
+type SettingsMembers struct {
+	Exclude []string
+	// Sync pulls members from the YouTube Memberships API, which needs the restricted memberships scope.
+	Sync bool
+}
+
+type SettingsSponsorship struct {
+	ReminderDays int
+	// InvoiceTermDays are the payment terms used when an invoice has no due date.
+	InvoiceTermDays int
+	// AssetTypes is the taxonomy of the assets sponsors are asked for.
+	AssetTypes      []string
+	BlackoutWindows []BlackoutWindow
+}
+
+type SettingsReddit struct {
+	ClientID        string
+	ClientSecret    string
+	Username        string
+	Password        string
+	UserAgent       string
+	MinDelaySeconds int
+	Subreddits      []SettingsRedditSubreddit
+}
+
+// SettingsSlack are the channels the videos are posted to with the bot token from the SLACK_TOKEN environment variable.
+// Without channels, the URL is copied to the clipboard instead.
+type SettingsSlack struct {
+	Token    string
+	Channels []SettingsSlackChannel
+}
+
+// SettingsSlackChannel is a channel ID (e.g., C0123456789). Thread replies to the post with the description and the chapters.
+type SettingsSlackChannel struct {
+	ID     string
+	Thread bool
+}
+
+// SettingsProgressField overrides how a built-in field counts toward the progress of its phase. Fields that are not enabled are not counted at all.
+// Overrides are keyed by the phase and then by the field name without spaces (e.g., progress.work.otherLogos).
+type SettingsProgressField struct {
+	Enabled  *bool
+	Criteria string
+}
+
+// SettingsMastodon is the account the videos are posted to. Posting is enabled when the server is set. The access token comes from the MASTODON_ACCESS_TOKEN environment variable.
+// MaxAttempts includes the first request.
+type SettingsMastodon struct {
+	Server          string
+	AccessToken     string
+	AttachThumbnail bool
+	MaxAttempts     int
+}
+
+type SettingsRedditSubreddit struct {
+	Name    string
+	FlairID string
+}
+
 type SettingsAI struct {
 	Key        string
 	Endpoint   string
 	Deployment string
 }
 
+// SettingsYouTube is the access to the channel. Statistics of videos are fetched again only when they are older than StatsTTLHours.
 type SettingsYouTube struct {
-	APIKey string
+	APIKey        string
+	TokenPath     string
+	ChannelID     string
+	StatsTTLHours int
 }
 
 var settings Settings
 
 func init() {
-	viper.SetConfigFile("settings.yaml")
-	if err := viper.ReadInConfig(); err != nil {
-		fmt.Printf("Error reading config file, %s", err)
-		return
-	}
-
+	initCmd.Flags().BoolVar(&initMinimal, "minimal", false, "Create only the directory structure and a settings file with empty values, without asking any questions.")
+	rootCmd.PersistentFlags().BoolVar(&outputQuiet, "quiet", false, "Print only results and errors.")
+	rootCmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "Print results, errors, and every operation as JSON events, one per line.")
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(calendarCmd)
+	rootCmd.AddCommand(phasesCmd)
+	rootCmd.AddCommand(migrationsCmd)
 	rootCmd.Flags().StringVar(&settings.Email.From, "email-from", "", "From which email to send messages. (required)")
 	rootCmd.Flags().StringVar(&settings.Email.ThumbnailTo, "email-thumbnail-to", "", "To which email to send requests for thumbnails. (required)")
 	rootCmd.Flags().StringVar(&settings.Email.EditTo, "email-edit-to", "", "To which email to send requests for edits. (required)")
@@ -62,6 +310,18 @@ func init() {
 	rootCmd.Flags().StringVar(&settings.AI.Deployment, "ai-deployment", "", "AI Deployment. Only Azure OpenAI is currently supported. (required)")
 	rootCmd.Flags().StringVar(&settings.YouTube.APIKey, "youtube-api-key", "", "AI Deployment. Only Azure OpenAI is currently supported. (required)")
 	rootCmd.Flags().StringVar(&settings.Hugo.Path, "hugo-path", "", "Path to the repo with Hugo posts. (required)")
+	viper.SetConfigFile("settings.yaml")
+	// A directory that was never set up has no settings yet. The setup wizard creates them and reads them again.
+	if err := readSettings(); err != nil && (!errors.Is(err, os.ErrNotExist) || !needsOnboarding(".")) {
+		output.Error(fmt.Sprintf("Error reading config file, %s", err))
+	}
+}
+
+// readSettings reads settings.yaml into settings. Values that are not set there are required as flags.
+func readSettings() error {
+	if err := viper.ReadInConfig(); err != nil {
+		return err
+	}
 	if viper.IsSet("email.from") {
 		settings.Email.From = viper.GetString("email.from")
 	} else {
@@ -82,6 +342,9 @@ func init() {
 	} else {
 		rootCmd.MarkFlagRequired("email-finance-to")
 	}
+	if viper.IsSet("email.replyTo") {
+		settings.Email.ReplyTo = viper.GetString("email.replyTo")
+	}
 	if len(os.Getenv("EMAIL_PASSWORD")) > 0 {
 		settings.Email.Password = os.Getenv("EMAIL_PASSWORD")
 	} else {
@@ -107,11 +370,295 @@ func init() {
 	} else {
 		rootCmd.MarkFlagRequired("youtube-api-key")
 	}
+	settings.YouTube.ChannelID = channelID
+	if viper.IsSet("youtube.channelId") {
+		settings.YouTube.ChannelID = viper.GetString("youtube.channelId")
+	}
+	if viper.IsSet("youtube.tokenPath") {
+		settings.YouTube.TokenPath = viper.GetString("youtube.tokenPath")
+	}
+	settings.YouTube.StatsTTLHours = 24
+	if viper.IsSet("youtube.statsTTLHours") {
+		settings.YouTube.StatsTTLHours = viper.GetInt("youtube.statsTTLHours")
+	}
 	if viper.IsSet("hugo.path") {
 		settings.Hugo.Path = viper.GetString("hugo.path")
 	} else {
 		rootCmd.MarkFlagRequired("hugo-path")
 	}
+	settings.Teleprompter.LineWidth = 50
+	if viper.IsSet("teleprompter.lineWidth") {
+		settings.Teleprompter.LineWidth = viper.GetInt("teleprompter.lineWidth")
+	}
+	if viper.IsSet("teleprompter.html") {
+		settings.Teleprompter.HTML = viper.GetBool("teleprompter.html")
+	}
+	if viper.IsSet("ui.theme") {
+		settings.UI.Theme = viper.GetString("ui.theme")
+	}
+	if viper.IsSet("ui.accessible") {
+		settings.UI.Accessible = viper.GetBool("ui.accessible")
+	}
+	if viper.IsSet("ui.hideHelp") {
+		settings.UI.HideHelp = viper.GetBool("ui.hideHelp")
+	}
+	settings.Schedule.Weekdays = []string{"Tuesday", "Thursday"}
+	settings.Schedule.Time = "16:00"
+	if viper.IsSet("schedule.weekdays") {
+		settings.Schedule.Weekdays = viper.GetStringSlice("schedule.weekdays")
+	}
+	if viper.IsSet("schedule.time") {
+		settings.Schedule.Time = viper.GetString("schedule.time")
+	}
+	settings.Schedule.Timezone = "Local"
+	if viper.IsSet("schedule.timezone") {
+		settings.Schedule.Timezone = viper.GetString("schedule.timezone")
+	}
+	if viper.IsSet("schedule.minGapDays") {
+		settings.Schedule.MinGapDays = viper.GetInt("schedule.minGapDays")
+	}
+	settings.Schedule.FarFuture = "3mo"
+	if viper.IsSet("schedule.farFuture") {
+		settings.Schedule.FarFuture = viper.GetString("schedule.farFuture")
+	}
+	settings.Schedule.Imminent = "7d"
+	if viper.IsSet("schedule.imminent") {
+		settings.Schedule.Imminent = viper.GetString("schedule.imminent")
+	}
+	if viper.IsSet("tags.aliases") {
+		settings.Tags.Aliases = viper.GetStringMapString("tags.aliases")
+	}
+	if viper.IsSet("names.transliterate") {
+		settings.Names.Transliterate = viper.GetBool("names.transliterate")
+	}
+	if viper.IsSet("names.maxLength") {
+		settings.Names.MaxLength = viper.GetInt("names.maxLength")
+	}
+	if viper.IsSet("names.reserved") {
+		settings.Names.Reserved = viper.GetStringSlice("names.reserved")
+	}
+	if viper.IsSet("upload.visibility") {
+		settings.Upload.Visibility = viper.GetString("upload.visibility")
+	}
+	if viper.IsSet("upload.madeForKids") {
+		settings.Upload.MadeForKids = viper.GetBool("upload.madeForKids")
+	}
+	if viper.IsSet("upload.language") {
+		settings.Upload.Language = viper.GetString("upload.language")
+	}
+	if viper.IsSet("upload.categories") {
+		if err := viper.UnmarshalKey("upload.categories", &settings.Upload.Categories); err != nil {
+			output.Error(fmt.Sprintf("Error reading upload categories, %s", err))
+		}
+	}
+	settings.Description.CTAMaxOffset = 150
+	if viper.IsSet("description.ctaMaxOffset") {
+		settings.Description.CTAMaxOffset = viper.GetInt("description.ctaMaxOffset")
+	}
+	if viper.IsSet("animations.cuePrefixes") {
+		settings.Animations.CuePrefixes = viper.GetStringSlice("animations.cuePrefixes")
+	}
+	if viper.IsSet("animations.headerLevels") {
+		settings.Animations.HeaderLevels = viper.GetIntSlice("animations.headerLevels")
+	}
+	if viper.IsSet("community.postTemplate") {
+		settings.Community.PostTemplate = viper.GetString("community.postTemplate")
+	}
+	if viper.IsSet("members.exclude") {
+		settings.Members.Exclude = viper.GetStringSlice("members.exclude")
+	}
+	if viper.IsSet("members.sync") {
+		settings.Members.Sync = viper.GetBool("members.sync")
+	}
+	settings.Sponsorship.ReminderDays = 14
+	if viper.IsSet("sponsorship.reminderDays") {
+		settings.Sponsorship.ReminderDays = viper.GetInt("sponsorship.reminderDays")
+	}
+	settings.Sponsorship.InvoiceTermDays = 60
+	if viper.IsSet("sponsorship.invoiceTermDays") {
+		settings.Sponsorship.InvoiceTermDays = viper.GetInt("sponsorship.invoiceTermDays")
+	}
+	settings.Sponsorship.AssetTypes = []string{"logo-svg", "logo-png", "logo-eps", "brand-guidelines", "approved-copy"}
+	if viper.IsSet("sponsorship.assetTypes") {
+		settings.Sponsorship.AssetTypes = viper.GetStringSlice("sponsorship.assetTypes")
+	}
+	if viper.IsSet("sponsorship.blackoutWindows") {
+		if err := viper.UnmarshalKey("sponsorship.blackoutWindows", &settings.Sponsorship.BlackoutWindows); err != nil {
+			output.Error(fmt.Sprintf("Error reading sponsorship blackout windows, %s", err))
+		}
+	}
+	settings.Trends.IntervalDays = 1
+	if viper.IsSet("trends.intervalDays") {
+		settings.Trends.IntervalDays = viper.GetInt("trends.intervalDays")
+	}
+	settings.Trends.Days = 90
+	if viper.IsSet("trends.days") {
+		settings.Trends.Days = viper.GetInt("trends.days")
+	}
+	settings.Import.MaxRows = 200
+	if viper.IsSet("import.maxRows") {
+		settings.Import.MaxRows = viper.GetInt("import.maxRows")
+	}
+	settings.Quality.Weights = getDefaultQualityWeights()
+	if viper.IsSet("quality.weights") {
+		weights := map[string]int{}
+		if err := viper.UnmarshalKey("quality.weights", &weights); err != nil {
+			output.Error(fmt.Sprintf("Error reading quality weights, %s", err))
+		}
+		for check, weight := range weights {
+			settings.Quality.Weights[check] = weight
+		}
+	}
+	settings.Credits.Licenses = getDefaultCreditsLicenses()
+	if viper.IsSet("credits.licenses") {
+		settings.Credits.Licenses = viper.GetStringSlice("credits.licenses")
+	}
+	settings.Credits.AttributionRequired = getDefaultCreditsAttributionRequired()
+	if viper.IsSet("credits.attributionRequired") {
+		settings.Credits.AttributionRequired = viper.GetStringSlice("credits.attributionRequired")
+	}
+	if viper.IsSet("calendar.url") {
+		settings.Calendar.URL = viper.GetString("calendar.url")
+	}
+	if len(os.Getenv("CALENDAR_TOKEN")) > 0 {
+		settings.Calendar.Token = os.Getenv("CALENDAR_TOKEN")
+	}
+	settings.Record.Checklist = getDefaultRecordChecklist()
+	if viper.IsSet("record.checklist") {
+		settings.Record.Checklist = viper.GetStringSlice("record.checklist")
+	}
+	if viper.IsSet("record.categories") {
+		if err := viper.UnmarshalKey("record.categories", &settings.Record.Categories); err != nil {
+			output.Error(fmt.Sprintf("Error reading recording checklist categories, %s", err))
+		}
+	}
+	if viper.IsSet("record.sectionsSetRecorded") {
+		settings.Record.SectionsSetRecorded = viper.GetBool("record.sectionsSetRecorded")
+	}
+	settings.Content.Disclaimer = contentDefaultDisclaimer
+	if viper.IsSet("content.disclaimer") {
+		settings.Content.Disclaimer = viper.GetString("content.disclaimer")
+	}
+	settings.Content.TriggerKeywords = []string{"CVE", "exploit"}
+	if viper.IsSet("content.triggerKeywords") {
+		settings.Content.TriggerKeywords = viper.GetStringSlice("content.triggerKeywords")
+	}
+	settings.Demo.ReminderDays = 7
+	if viper.IsSet("demo.reminderDays") {
+		settings.Demo.ReminderDays = viper.GetInt("demo.reminderDays")
+	}
+	if viper.IsSet("demo.webhook") {
+		settings.Demo.Webhook = viper.GetString("demo.webhook")
+	}
+	settings.Cleanup.ArchiveDir = "archive"
+	if viper.IsSet("cleanup.archiveDir") {
+		settings.Cleanup.ArchiveDir = viper.GetString("cleanup.archiveDir")
+	}
+	settings.Workload.Hours = getDefaultWorkloadHours()
+	if viper.IsSet("workload.hours") {
+		hours := map[string]float64{}
+		if err := viper.UnmarshalKey("workload.hours", &hours); err != nil {
+			output.Error(fmt.Sprintf("Error reading workload hours, %s", err))
+		}
+		for phase, value := range hours {
+			settings.Workload.Hours[phase] = value
+		}
+	}
+	settings.Workload.WeeklyCapacity = 20
+	if viper.IsSet("workload.weeklyCapacity") {
+		settings.Workload.WeeklyCapacity = viper.GetFloat64("workload.weeklyCapacity")
+	}
+	if viper.IsSet("workload.weighted") {
+		settings.Workload.Weighted = viper.GetBool("workload.weighted")
+	}
+	settings.Workload.MustDoDays = 7
+	if viper.IsSet("workload.mustDoDays") {
+		settings.Workload.MustDoDays = viper.GetInt("workload.mustDoDays")
+	}
+	settings.Secrets.KeyFile = ".secrets.key"
+	if viper.IsSet("secrets.keyFile") {
+		settings.Secrets.KeyFile = viper.GetString("secrets.keyFile")
+	}
+	if viper.IsSet("podcast.enabled") {
+		settings.Podcast.Enabled = viper.GetBool("podcast.enabled")
+	}
+	settings.Costs.Currency = "USD"
+	if viper.IsSet("costs.currency") {
+		settings.Costs.Currency = viper.GetString("costs.currency")
+	}
+	settings.UTM = getDefaultUTMParameters()
+	if viper.IsSet("utm") {
+		settings.UTM = viper.GetStringMapString("utm")
+	}
+	settings.Assets.Cues = []string{"diagram", "screenshot"}
+	if viper.IsSet("assets.cues") {
+		settings.Assets.Cues = viper.GetStringSlice("assets.cues")
+	}
+	if viper.IsSet("assets.requireForMaterialDone") {
+		settings.Assets.RequireForMaterialDone = viper.GetBool("assets.requireForMaterialDone")
+	}
+	settings.Reddit.UserAgent = "youtube-automation"
+	settings.Reddit.MinDelaySeconds = 10
+	if viper.IsSet("reddit.clientId") {
+		settings.Reddit.ClientID = viper.GetString("reddit.clientId")
+	}
+	if len(os.Getenv("REDDIT_CLIENT_SECRET")) > 0 {
+		settings.Reddit.ClientSecret = os.Getenv("REDDIT_CLIENT_SECRET")
+	}
+	if viper.IsSet("reddit.username") {
+		settings.Reddit.Username = viper.GetString("reddit.username")
+	}
+	if len(os.Getenv("REDDIT_PASSWORD")) > 0 {
+		settings.Reddit.Password = os.Getenv("REDDIT_PASSWORD")
+	}
+	if viper.IsSet("reddit.userAgent") {
+		settings.Reddit.UserAgent = viper.GetString("reddit.userAgent")
+	}
+	if viper.IsSet("reddit.minDelaySeconds") {
+		settings.Reddit.MinDelaySeconds = viper.GetInt("reddit.minDelaySeconds")
+	}
+	if viper.IsSet("reddit.subreddits") {
+		if err := viper.UnmarshalKey("reddit.subreddits", &settings.Reddit.Subreddits); err != nil {
+			output.Error(fmt.Sprintf("Error reading Reddit subreddits, %s", err))
+		}
+	}
+	if viper.IsSet("mastodon.server") {
+		settings.Mastodon.Server = viper.GetString("mastodon.server")
+	}
+	if len(os.Getenv("MASTODON_ACCESS_TOKEN")) > 0 {
+		settings.Mastodon.AccessToken = os.Getenv("MASTODON_ACCESS_TOKEN")
+	}
+	if viper.IsSet("mastodon.attachThumbnail") {
+		settings.Mastodon.AttachThumbnail = viper.GetBool("mastodon.attachThumbnail")
+	}
+	settings.Mastodon.MaxAttempts = 3
+	if viper.IsSet("mastodon.maxAttempts") {
+		settings.Mastodon.MaxAttempts = viper.GetInt("mastodon.maxAttempts")
+	}
+	if len(os.Getenv("SLACK_TOKEN")) > 0 {
+		settings.Slack.Token = os.Getenv("SLACK_TOKEN")
+	}
+	if viper.IsSet("slack.channels") {
+		if err := viper.UnmarshalKey("slack.channels", &settings.Slack.Channels); err != nil {
+			output.Error(fmt.Sprintf("Error reading Slack channels, %s", err))
+		}
+	}
+	if viper.IsSet("progress") {
+		if err := viper.UnmarshalKey("progress", &settings.Progress); err != nil {
+			output.Error(fmt.Sprintf("Error reading progress overrides, %s", err))
+		}
+	}
+	if viper.IsSet("customFields") {
+		if err := viper.UnmarshalKey("customFields", &settings.CustomFields); err != nil {
+			output.Error(fmt.Sprintf("Error reading custom fields, %s", err))
+		}
+	}
+	if viper.IsSet("rules") {
+		if err := viper.UnmarshalKey("rules", &settings.Rules); err != nil {
+			output.Error(fmt.Sprintf("Error reading rules, %s", err))
+		}
+	}
+	return nil
 }
 
 func getArgs() {
@@ -119,4 +666,16 @@ func getArgs() {
 		fmt.Fprintf(os.Stderr, "Whoops. There was an error while executing the CLI '%s'", err)
 		os.Exit(1)
 	}
+	// Network checks are left to `config validate --check-integrations` so that startup stays fast and works offline.
+	if _, err := os.Stat(viper.ConfigFileUsed()); err != nil {
+		return
+	}
+	findings := getConfigFindings(viper.ConfigFileUsed(), settings)
+	if hasConfigErrors(findings) {
+		fmt.Fprintf(os.Stderr, "Invalid settings:\n%s\n", getConfigFindingsText(findings))
+		os.Exit(1)
+	}
+	if len(findings) > 0 {
+		output.Warn(getConfigFindingsText(findings))
+	}
 }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+const clipStatusPlanned = "planned"
+const clipStatusRecorded = "recorded"
+const clipStatusPublished = "published"
+
+type Clip = workflow.Clip
+
+type clipSuggestion struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Title string `json:"title"`
+	Hook  string `json:"hook"`
+}
+
+// parseTimestamp converts mm:ss or hh:mm:ss into a duration.
+func parseTimestamp(timestamp string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(timestamp), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("timestamp %q must be in the mm:ss or hh:mm:ss format", timestamp)
+	}
+	duration := time.Duration(0)
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 || (i > 0 && value >= 60) {
+			return 0, fmt.Errorf("timestamp %q must be in the mm:ss or hh:mm:ss format", timestamp)
+		}
+		duration = duration*60 + time.Duration(value)*time.Second
+	}
+	return duration, nil
+}
+
+// validateClip checks the clip range. The duration is ignored when it is not known (zero).
+func validateClip(clip Clip, duration time.Duration) error {
+	start, err := parseTimestamp(clip.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseTimestamp(clip.End)
+	if err != nil {
+		return err
+	}
+	if end <= start {
+		return fmt.Errorf("clip end (%s) must be after its start (%s)", clip.End, clip.Start)
+	}
+	if duration > 0 && end > duration {
+		return fmt.Errorf("clip end (%s) is after the end of the video (%s)", clip.End, formatTimestamp(duration))
+	}
+	return nil
+}
+
+func formatTimestamp(duration time.Duration) string {
+	seconds := int(duration.Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// getMediaDuration uses ffprobe to find out the duration of a media file.
+func getMediaDuration(path string) (time.Duration, error) {
+	cmd := exec.Command("ffprobe", "-v", "error", "-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func getVideoDuration(video Video) time.Duration {
+	if len(video.UploadVideo) == 0 {
+		return 0
+	}
+	duration, err := getMediaDuration(video.UploadVideo)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// getSuggestedClips drops the suggestions that are not valid clips.
+func getSuggestedClips(suggestions []clipSuggestion) []Clip {
+	clips := []Clip{}
+	for _, suggestion := range suggestions {
+		clip := Clip{
+			Start:  suggestion.Start,
+			End:    suggestion.End,
+			Title:  suggestion.Title,
+			Hook:   suggestion.Hook,
+			Status: clipStatusPlanned,
+		}
+		if validateClip(clip, 0) != nil {
+			continue
+		}
+		clips = append(clips, clip)
+	}
+	return clips
+}
+
+func getClipTitle(clip Clip) string {
+	title := fmt.Sprintf("%s-%s %s (%s)", clip.Start, clip.End, clip.Title, clip.Status)
+	if clip.Status == clipStatusPublished {
+		return greenStyle.Render(title)
+	}
+	return orangeStyle.Render(title)
+}
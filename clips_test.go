@@ -0,0 +1,85 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClips_parseTimestamp(t *testing.T) {
+	valid := map[string]time.Duration{
+		"00:00":    0,
+		"01:20":    80 * time.Second,
+		"1:02:03":  time.Hour + 2*time.Minute + 3*time.Second,
+		" 10:05 ":  10*time.Minute + 5*time.Second,
+		"75:00":    75 * time.Minute,
+		"00:59:59": 59*time.Minute + 59*time.Second,
+	}
+	for timestamp, expected := range valid {
+		actual, err := parseTimestamp(timestamp)
+		if err != nil {
+			t.Errorf("Error occurred while parsing %q: %v", timestamp, err)
+		}
+		if actual != expected {
+			t.Errorf("Expected %q to be %v, but got %v", timestamp, expected, actual)
+		}
+	}
+	for _, timestamp := range []string{"", "10", "01:60", "aa:10", "-1:10", "1:2:3:4"} {
+		if _, err := parseTimestamp(timestamp); err == nil {
+			t.Errorf("Expected %q to be rejected", timestamp)
+		}
+	}
+}
+
+func TestClips_validateClip(t *testing.T) {
+	duration := 10 * time.Minute
+	if err := validateClip(Clip{Start: "01:00", End: "02:00"}, duration); err != nil {
+		t.Errorf("Expected a valid clip, but got %v", err)
+	}
+	if err := validateClip(Clip{Start: "09:00", End: "10:00"}, duration); err != nil {
+		t.Errorf("Expected a clip ending at the end of the video to be valid, but got %v", err)
+	}
+	if err := validateClip(Clip{Start: "09:00", End: "10:01"}, duration); err == nil {
+		t.Errorf("Expected a clip ending after the video to be rejected")
+	}
+	if err := validateClip(Clip{Start: "09:00", End: "10:01"}, 0); err != nil {
+		t.Errorf("Expected the duration to be ignored when unknown, but got %v", err)
+	}
+	if err := validateClip(Clip{Start: "02:00", End: "02:00"}, duration); err == nil {
+		t.Errorf("Expected an empty range to be rejected")
+	}
+}
+
+func TestClips_getSuggestedClips(t *testing.T) {
+	output := `[
+  {"start": "01:10", "end": "01:55", "title": "Restaurants", "hook": "Nobody orders a cow."},
+  {"start": "03:00", "end": "02:00", "title": "Broken", "hook": "Ignored"}
+]`
+	suggestions := []clipSuggestion{}
+	if err := parseAIJSON(output, &suggestions); err != nil {
+		t.Fatalf("Error occurred while parsing suggestions: %v", err)
+	}
+	clips := getSuggestedClips(suggestions)
+	expected := []Clip{{Start: "01:10", End: "01:55", Title: "Restaurants", Hook: "Nobody orders a cow.", Status: clipStatusPlanned}}
+	if !reflect.DeepEqual(clips, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, clips)
+	}
+}
+
+func TestClips_yamlRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	video := Video{
+		Name: "something",
+		Clips: []Clip{
+			{Start: "01:10", End: "01:55", Title: "Restaurants", Status: clipStatusPublished, VideoId: "abc"},
+			{Start: "03:00", End: "03:40", Title: "Cooks", Status: clipStatusPlanned},
+		},
+	}
+	yaml := YAML{}
+	yaml.WriteVideo(&video, path)
+	actual := yaml.GetVideo(path)
+	if !reflect.DeepEqual(actual.Clips, video.Clips) {
+		t.Errorf("Expected: %v\nGot: %v", video.Clips, actual.Clips)
+	}
+}
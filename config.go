@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const configSeverityError = "error"
+const configSeverityWarning = "warning"
+
+const configIntegrationTimeout = 30 * time.Second
+
+// ConfigFinding is a problem with the settings. Path is the YAML path of the offending key (e.g., email.thumbnailTo).
+type ConfigFinding struct {
+	Path     string
+	Severity string
+	Message  string
+}
+
+func (f ConfigFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Path, f.Message)
+}
+
+// settingsEnvKeys are settings that are read only from environment variables.
+var settingsEnvKeys = map[string]string{
+	"email.password":       "EMAIL_PASSWORD",
+	"ai.key":               "AI_KEY",
+	"youtube.apikey":       "YOUTUBE_API_KEY",
+	"reddit.clientsecret":  "REDDIT_CLIENT_SECRET",
+	"reddit.password":      "REDDIT_PASSWORD",
+	"mastodon.accesstoken": "MASTODON_ACCESS_TOKEN",
+	"slack.token":          "SLACK_TOKEN",
+	"calendar.token":       "CALENDAR_TOKEN",
+}
+
+var configCheckIntegrations bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manages the settings.",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates settings.yaml and lists errors and warnings.",
+	Run: func(cmd *cobra.Command, args []string) {
+		findings := getConfigFindings(viper.ConfigFileUsed(), settings)
+		if configCheckIntegrations {
+			findings = append(findings, checkIntegrations(settings)...)
+		}
+		if len(findings) == 0 {
+			output.Result("Settings are valid.")
+			os.Exit(0)
+		}
+		output.ResultText(getConfigFindingsText(findings))
+		if hasConfigErrors(findings) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+func init() {
+	configValidateCmd.Flags().BoolVar(&configCheckIntegrations, "check-integrations", false, "Check that email, Reddit, and YouTube credentials work. Requires network access.")
+	configCmd.AddCommand(configValidateCmd)
+}
+
+// getConfigFindings runs the structural validation of the settings file (unknown keys) and of the loaded settings (cross-field rules).
+func getConfigFindings(path string, s Settings) []ConfigFinding {
+	findings := []ConfigFinding{}
+	if len(path) == 0 {
+		path = "settings.yaml"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return append(findings, ConfigFinding{Path: path, Severity: configSeverityError, Message: err.Error()})
+	}
+	findings = append(findings, getSettingsKeyFindings(data)...)
+	return append(findings, validateSettings(s)...)
+}
+
+// getSettingsKeyFindings reports keys that do not exist in Settings. Misspelled or wrongly indented keys would otherwise be silently ignored.
+func getSettingsKeyFindings(data []byte) []ConfigFinding {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return []ConfigFinding{{Path: "settings.yaml", Severity: configSeverityError, Message: err.Error()}}
+	}
+	findings := []ConfigFinding{}
+	checkSettingsKeys(values, reflect.TypeOf(Settings{}), "", &findings)
+	return findings
+}
+
+func checkSettingsKeys(value interface{}, t reflect.Type, path string, findings *[]ConfigFinding) {
+	switch t.Kind() {
+	case reflect.Struct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := joinConfigPath(path, key)
+			if env, ok := settingsEnvKeys[strings.ToLower(keyPath)]; ok {
+				*findings = append(*findings, ConfigFinding{Path: keyPath, Severity: configSeverityWarning, Message: fmt.Sprintf("is ignored; use the %s environment variable instead", env)})
+				continue
+			}
+			field, ok := t.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, key) })
+			if !ok {
+				*findings = append(*findings, ConfigFinding{Path: keyPath, Severity: configSeverityError, Message: "unknown key (check the spelling and the indentation)"})
+				continue
+			}
+			checkSettingsKeys(values[key], field.Type, keyPath, findings)
+		}
+	case reflect.Map:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, item := range values {
+			checkSettingsKeys(item, t.Elem(), joinConfigPath(path, key), findings)
+		}
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			checkSettingsKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), findings)
+		}
+	}
+}
+
+func joinConfigPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// validateSettings applies the rules that span more than one key or that the types alone cannot express.
+func validateSettings(s Settings) []ConfigFinding {
+	findings := []ConfigFinding{}
+	add := func(path, severity, format string, args ...interface{}) {
+		findings = append(findings, ConfigFinding{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	emails := []struct {
+		path  string
+		value string
+	}{
+		{"email.from", s.Email.From},
+		{"email.thumbnailTo", s.Email.ThumbnailTo},
+		{"email.editTo", s.Email.EditTo},
+		{"email.financeTo", s.Email.FinanceTo},
+		{"email.replyTo", s.Email.ReplyTo},
+	}
+	for _, email := range emails {
+		if len(email.value) == 0 {
+			continue
+		}
+		if _, err := mail.ParseAddress(email.value); err != nil {
+			add(email.path, configSeverityError, "%q is not a valid email address", email.value)
+		} else if email.path != "email.from" && email.path != "email.replyTo" && len(s.Email.Password) == 0 {
+			add(email.path, configSeverityError, "requires the EMAIL_PASSWORD environment variable")
+		}
+	}
+	if len(s.Reddit.Subreddits) > 0 {
+		if len(s.Reddit.ClientID) == 0 {
+			add("reddit.clientId", configSeverityError, "is required when reddit.subreddits are set")
+		}
+		if len(s.Reddit.Username) == 0 {
+			add("reddit.username", configSeverityError, "is required when reddit.subreddits are set")
+		}
+		if len(s.Reddit.ClientSecret) == 0 {
+			add("reddit.subreddits", configSeverityError, "requires the REDDIT_CLIENT_SECRET environment variable")
+		}
+		if len(s.Reddit.Password) == 0 {
+			add("reddit.subreddits", configSeverityError, "requires the REDDIT_PASSWORD environment variable")
+		}
+	}
+	if isMastodonEnabled(s.Mastodon) {
+		if parsed, err := url.Parse(s.Mastodon.Server); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || len(parsed.Host) == 0 {
+			add("mastodon.server", configSeverityError, "%q is not a URL (e.g., https://mastodon.social)", s.Mastodon.Server)
+		}
+		if len(s.Mastodon.AccessToken) == 0 {
+			add("mastodon.server", configSeverityError, "requires the MASTODON_ACCESS_TOKEN environment variable")
+		}
+		if s.Mastodon.MaxAttempts < 1 {
+			add("mastodon.maxAttempts", configSeverityError, "must be greater than zero")
+		}
+	}
+	if isSlackEnabled(s.Slack) && len(s.Slack.Token) == 0 {
+		add("slack.channels", configSeverityError, "requires the SLACK_TOKEN environment variable")
+	}
+	for i, channel := range s.Slack.Channels {
+		if len(channel.ID) == 0 {
+			add(fmt.Sprintf("slack.channels[%d].id", i), configSeverityError, "is required")
+		}
+	}
+	if len(s.Calendar.URL) > 0 {
+		if parsed, err := url.Parse(s.Calendar.URL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || len(parsed.Host) == 0 {
+			add("calendar.url", configSeverityError, "%q is not a URL (e.g., https://example.com/calendar.ics)", s.Calendar.URL)
+		}
+		if len(s.Calendar.Token) < calendarTokenMinLength {
+			add("calendar.url", configSeverityError, "requires the CALENDAR_TOKEN environment variable with at least %d characters", calendarTokenMinLength)
+		}
+	}
+	for i, subreddit := range s.Reddit.Subreddits {
+		if len(subreddit.Name) == 0 {
+			add(fmt.Sprintf("reddit.subreddits[%d].name", i), configSeverityError, "is required")
+		}
+	}
+	positive := []struct {
+		path  string
+		value int
+	}{
+		{"teleprompter.lineWidth", s.Teleprompter.LineWidth},
+		{"trends.intervalDays", s.Trends.IntervalDays},
+		{"trends.days", s.Trends.Days},
+		{"import.maxRows", s.Import.MaxRows},
+		{"sponsorship.invoiceTermDays", s.Sponsorship.InvoiceTermDays},
+		{"youtube.statsTTLHours", s.YouTube.StatsTTLHours},
+	}
+	for _, number := range positive {
+		if number.value <= 0 {
+			add(number.path, configSeverityError, "must be greater than zero")
+		}
+	}
+	nonNegative := []struct {
+		path  string
+		value int
+	}{
+		{"schedule.minGapDays", s.Schedule.MinGapDays},
+		{"names.maxLength", s.Names.MaxLength},
+		{"description.ctaMaxOffset", s.Description.CTAMaxOffset},
+		{"sponsorship.reminderDays", s.Sponsorship.ReminderDays},
+		{"reddit.minDelaySeconds", s.Reddit.MinDelaySeconds},
+		{"workload.mustDoDays", s.Workload.MustDoDays},
+		{"demo.reminderDays", s.Demo.ReminderDays},
+	}
+	for _, number := range nonNegative {
+		if number.value < 0 {
+			add(number.path, configSeverityError, "must not be negative")
+		}
+	}
+	if webhookURL, err := url.Parse(s.Demo.Webhook); len(s.Demo.Webhook) > 0 && (err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https")) {
+		add("demo.webhook", configSeverityError, "%q must be an http or https URL", s.Demo.Webhook)
+	}
+	for i, window := range s.Sponsorship.BlackoutWindows {
+		if err := validateBlackoutWindow(window); err != nil {
+			add(fmt.Sprintf("sponsorship.blackoutWindows[%d]", i), configSeverityError, "%s", err)
+		}
+	}
+	for i, license := range s.Credits.AttributionRequired {
+		if !slices.Contains(s.Credits.Licenses, license) {
+			add(fmt.Sprintf("credits.attributionRequired[%d]", i), configSeverityError, "%q is not one of credits.licenses", license)
+		}
+	}
+	for i, item := range s.Record.Checklist {
+		if len(strings.TrimSpace(item)) == 0 {
+			add(fmt.Sprintf("record.checklist[%d]", i), configSeverityError, "must not be empty")
+		}
+	}
+	recordCategories := make([]string, 0, len(s.Record.Categories))
+	for category := range s.Record.Categories {
+		recordCategories = append(recordCategories, category)
+	}
+	sort.Strings(recordCategories)
+	for _, category := range recordCategories {
+		for i, item := range s.Record.Categories[category] {
+			if len(strings.TrimSpace(item)) == 0 {
+				add(fmt.Sprintf("record.categories.%s[%d]", category, i), configSeverityError, "must not be empty")
+			}
+		}
+	}
+	for i, keyword := range s.Content.TriggerKeywords {
+		if len(strings.TrimSpace(keyword)) == 0 {
+			add(fmt.Sprintf("content.triggerKeywords[%d]", i), configSeverityError, "must not be empty")
+		}
+	}
+	checks := make([]string, 0, len(s.Quality.Weights))
+	weights := 0
+	for check, weight := range s.Quality.Weights {
+		checks = append(checks, check)
+		weights += max(weight, 0)
+	}
+	sort.Strings(checks)
+	for _, check := range checks {
+		path := fmt.Sprintf("quality.weights.%s", check)
+		if !slices.Contains(qualityChecks, check) {
+			add(path, configSeverityError, "is not one of %s", strings.Join(qualityChecks, ", "))
+		} else if s.Quality.Weights[check] < 0 {
+			add(path, configSeverityError, "must not be negative")
+		}
+	}
+	if len(s.Quality.Weights) > 0 && weights == 0 {
+		add("quality.weights", configSeverityError, "at least one weight must be greater than zero")
+	}
+	if s.Workload.WeeklyCapacity <= 0 {
+		add("workload.weeklyCapacity", configSeverityError, "must be greater than zero")
+	}
+	phases := make([]string, 0, len(s.Workload.Hours))
+	for phase := range s.Workload.Hours {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	for _, phase := range phases {
+		path := fmt.Sprintf("workload.hours.%s", phase)
+		if _, ok := rulePhases[phase]; !ok {
+			add(path, configSeverityError, "is not a known phase")
+		} else if s.Workload.Hours[phase] < 0 {
+			add(path, configSeverityError, "must not be negative")
+		}
+	}
+	for i, weekday := range s.Schedule.Weekdays {
+		if _, err := NewSchedule([]string{weekday}, "", 0); err != nil {
+			add(fmt.Sprintf("schedule.weekdays[%d]", i), configSeverityError, "%s", err)
+		}
+	}
+	if _, err := NewSchedule(nil, s.Schedule.Time, 0); err != nil {
+		add("schedule.time", configSeverityError, "%s", err)
+	}
+	farFuture, farFutureErr := parseDateHorizon(s.Schedule.FarFuture)
+	if farFutureErr != nil {
+		add("schedule.farFuture", configSeverityError, "%s", farFutureErr)
+	}
+	imminent, imminentErr := parseDateHorizon(s.Schedule.Imminent)
+	if imminentErr != nil {
+		add("schedule.imminent", configSeverityError, "%s", imminentErr)
+	}
+	if farFutureErr == nil && imminentErr == nil {
+		now := time.Now()
+		if !imminent.From(now).Before(farFuture.From(now)) {
+			add("schedule.imminent", configSeverityError, "must be shorter than schedule.farFuture")
+		}
+	}
+	if _, err := time.LoadLocation(s.Schedule.Timezone); err != nil || len(s.Schedule.Timezone) == 0 {
+		add("schedule.timezone", configSeverityError, "%q is not a known timezone (e.g., Europe/Berlin)", s.Schedule.Timezone)
+	}
+	if len(s.Upload.Visibility) > 0 && !isValidVisibility(s.Upload.Visibility) {
+		add("upload.visibility", configSeverityError, "%q is not one of private, unlisted, public, or scheduled", s.Upload.Visibility)
+	}
+	categories := make([]string, 0, len(s.Upload.Categories))
+	for category := range s.Upload.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		defaults := s.Upload.Categories[category]
+		if len(defaults.Visibility) > 0 && !isValidVisibility(defaults.Visibility) {
+			add(fmt.Sprintf("upload.categories.%s.visibility", category), configSeverityError, "%q is not one of private, unlisted, public, or scheduled", defaults.Visibility)
+		}
+		if _, err := strconv.ParseBool(defaults.MadeForKids); len(defaults.MadeForKids) > 0 && err != nil {
+			add(fmt.Sprintf("upload.categories.%s.madeForKids", category), configSeverityError, "must be true or false")
+		}
+	}
+	if len(s.UI.Theme) > 0 && !slices.Contains([]string{"charm", "base", "dracula", "base16", "catppuccin"}, strings.ToLower(s.UI.Theme)) {
+		add("ui.theme", configSeverityWarning, "%q is not a known theme; charm is used instead", s.UI.Theme)
+	}
+	if len(s.Hugo.Path) > 0 {
+		if _, err := os.Stat(s.Hugo.Path); err != nil {
+			add("hugo.path", configSeverityWarning, "%s does not exist", s.Hugo.Path)
+		}
+	}
+	for i, level := range s.Animations.HeaderLevels {
+		if level < 1 || level > 6 {
+			add(fmt.Sprintf("animations.headerLevels[%d]", i), configSeverityError, "must be between 1 and 6")
+		}
+	}
+	if _, err := getCommunityPost(Video{}, s.Community.PostTemplate); err != nil {
+		add("community.postTemplate", configSeverityError, "%s", err)
+	}
+	for i, field := range s.CustomFields {
+		if len(field.Key) == 0 {
+			add(fmt.Sprintf("customFields[%d].key", i), configSeverityError, "is required")
+		}
+		if !slices.Contains([]string{customFieldTypeString, customFieldTypeBool, customFieldTypeDate, customFieldTypeSelect}, field.Type) {
+			add(fmt.Sprintf("customFields[%d].type", i), configSeverityError, "%q is not one of string, bool, date, or select", field.Type)
+		}
+	}
+	findings = append(findings, getProgressOverrideFindings(s)...)
+	for i, rule := range s.Rules {
+		if err := validateRules([]Rule{rule}); err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				add(fmt.Sprintf("rules[%d]", i), configSeverityError, "%s", line)
+			}
+		}
+		for j, action := range rule.Actions {
+			if webhookURL, err := url.Parse(action.URL); action.Type == ruleActionWebhook && err == nil && webhookURL.Scheme == "http" {
+				add(fmt.Sprintf("rules[%d].actions[%d].url", i, j), configSeverityError, "webhook URLs must use https")
+			}
+		}
+	}
+	return findings
+}
+
+func isValidVisibility(visibility string) bool {
+	return slices.Contains([]string{visibilityPrivate, visibilityUnlisted, visibilityPublic, visibilityScheduled}, visibility)
+}
+
+// checkIntegrations verifies that the configured credentials are accepted. Unlike validateSettings, it talks to the services.
+func checkIntegrations(s Settings) []ConfigFinding {
+	findings := []ConfigFinding{}
+	ctx, cancel := context.WithTimeout(context.Background(), configIntegrationTimeout)
+	defer cancel()
+	if len(s.Email.From) > 0 && len(s.Email.Password) > 0 {
+		if err := NewEmail(s.Email.Password).Check(ctx, s.Email.From); err != nil {
+			findings = append(findings, ConfigFinding{Path: "email.from", Severity: configSeverityError, Message: err.Error()})
+		}
+	}
+	if len(s.Reddit.Subreddits) > 0 {
+		if err := NewReddit(s.Reddit).authenticate(ctx); err != nil {
+			findings = append(findings, ConfigFinding{Path: "reddit.clientId", Severity: configSeverityError, Message: err.Error()})
+		}
+	}
+	if isMastodonEnabled(s.Mastodon) && len(s.Mastodon.AccessToken) > 0 {
+		if err := NewMastodon(s.Mastodon).VerifyCredentials(ctx); err != nil {
+			findings = append(findings, ConfigFinding{Path: "mastodon.server", Severity: configSeverityError, Message: err.Error()})
+		}
+	}
+	if isSlackEnabled(s.Slack) && len(s.Slack.Token) > 0 {
+		if err := NewSlack(s.Slack).VerifyCredentials(ctx); err != nil {
+			findings = append(findings, ConfigFinding{Path: "slack.channels", Severity: configSeverityError, Message: err.Error()})
+		}
+	}
+	if err := checkYouTubeToken(); err != nil {
+		findings = append(findings, ConfigFinding{Path: "youtube.tokenPath", Severity: configSeverityError, Message: err.Error()})
+	}
+	return findings
+}
+
+func checkYouTubeToken() error {
+	config, err := getOAuthConfig()
+	if err != nil {
+		return err
+	}
+	path, err := getTokenPath()
+	if err != nil {
+		return err
+	}
+	stored, err := tokenFromFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: there is no token in %s", ErrYouTubeReconsent, path)
+	}
+	_, err = newPersistentTokenSource(config, stored, path).Token()
+	return err
+}
+
+func hasConfigErrors(findings []ConfigFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == configSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func getConfigFindingsText(findings []ConfigFinding) string {
+	lines := []string{}
+	for _, finding := range findings {
+		lines = append(lines, finding.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func getValidTestSettings() Settings {
+	return Settings{
+		Email:        SettingsEmail{From: "me@example.com", EditTo: "editor@example.com", Password: "secret"},
+		Teleprompter: SettingsTeleprompter{LineWidth: 50},
+		YouTube:      SettingsYouTube{StatsTTLHours: 24},
+		Mastodon:     SettingsMastodon{MaxAttempts: 3},
+		Schedule:     SettingsSchedule{Weekdays: []string{"Tuesday"}, Time: "16:00", FarFuture: "6w", Imminent: "7d", Timezone: "UTC"},
+		Trends:       SettingsTrends{IntervalDays: 1, Days: 90},
+		Import:       SettingsImport{MaxRows: 200},
+		Workload:     SettingsWorkload{WeeklyCapacity: 20},
+		Sponsorship:  SettingsSponsorship{InvoiceTermDays: 60},
+	}
+}
+
+func TestConfig_getSettingsKeyFindings(t *testing.T) {
+	data := []byte(`email:
+  from: me@example.com
+  editTO: editor@example.com
+  password: secret
+thumbnailTo: designer@example.com
+tags:
+  aliases:
+    k8s: kubernetes
+upload:
+  categories:
+    ai:
+      visibility: public
+      madeForKid: "true"
+reddit:
+  subreddits:
+    - name: kubernetes
+      flair: abc
+rules:
+  - name: Notify
+    trigger:
+      type: created
+    actions:
+      - type: webhook
+        url: https://example.com
+`)
+	expected := []ConfigFinding{
+		{Path: "email.password", Severity: configSeverityWarning, Message: "is ignored; use the EMAIL_PASSWORD environment variable instead"},
+		{Path: "reddit.subreddits[0].flair", Severity: configSeverityError, Message: "unknown key (check the spelling and the indentation)"},
+		{Path: "thumbnailTo", Severity: configSeverityError, Message: "unknown key (check the spelling and the indentation)"},
+		{Path: "upload.categories.ai.madeForKid", Severity: configSeverityError, Message: "unknown key (check the spelling and the indentation)"},
+	}
+	actual := getSettingsKeyFindings(data)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestConfig_getSettingsKeyFindingsInvalidYAML(t *testing.T) {
+	actual := getSettingsKeyFindings([]byte("email:\n  from: me@example.com\n editTo: editor@example.com\n"))
+	if len(actual) != 1 || actual[0].Path != "settings.yaml" || actual[0].Severity != configSeverityError {
+		t.Errorf("Expected a single YAML error, but got %v", actual)
+	}
+}
+
+func TestConfig_validateSettings(t *testing.T) {
+	missingPath := filepath.Join(t.TempDir(), "missing")
+	tests := []struct {
+		name     string
+		change   func(s *Settings)
+		expected []ConfigFinding
+	}{
+		{"valid", func(s *Settings) {}, []ConfigFinding{}},
+		{"invalid email", func(s *Settings) { s.Email.From = "FIXME" }, []ConfigFinding{
+			{Path: "email.from", Severity: configSeverityError, Message: `"FIXME" is not a valid email address`},
+		}},
+		{"recipient without password", func(s *Settings) { s.Email.Password = "" }, []ConfigFinding{
+			{Path: "email.editTo", Severity: configSeverityError, Message: "requires the EMAIL_PASSWORD environment variable"},
+		}},
+		{"reddit without credentials", func(s *Settings) {
+			s.Reddit.Subreddits = []SettingsRedditSubreddit{{Name: "kubernetes"}, {}}
+		}, []ConfigFinding{
+			{Path: "reddit.clientId", Severity: configSeverityError, Message: "is required when reddit.subreddits are set"},
+			{Path: "reddit.username", Severity: configSeverityError, Message: "is required when reddit.subreddits are set"},
+			{Path: "reddit.subreddits", Severity: configSeverityError, Message: "requires the REDDIT_CLIENT_SECRET environment variable"},
+			{Path: "reddit.subreddits", Severity: configSeverityError, Message: "requires the REDDIT_PASSWORD environment variable"},
+			{Path: "reddit.subreddits[1].name", Severity: configSeverityError, Message: "is required"},
+		}},
+		{"mastodon without credentials", func(s *Settings) { s.Mastodon.Server = "mastodon.social" }, []ConfigFinding{
+			{Path: "mastodon.server", Severity: configSeverityError, Message: `"mastodon.social" is not a URL (e.g., https://mastodon.social)`},
+			{Path: "mastodon.server", Severity: configSeverityError, Message: "requires the MASTODON_ACCESS_TOKEN environment variable"},
+		}},
+		{"mastodon without attempts", func(s *Settings) {
+			s.Mastodon = SettingsMastodon{Server: "https://mastodon.social", AccessToken: "token", MaxAttempts: 0}
+		}, []ConfigFinding{
+			{Path: "mastodon.maxAttempts", Severity: configSeverityError, Message: "must be greater than zero"},
+		}},
+		{"slack without token", func(s *Settings) {
+			s.Slack.Channels = []SettingsSlackChannel{{ID: "C123", Thread: true}, {}}
+		}, []ConfigFinding{
+			{Path: "slack.channels", Severity: configSeverityError, Message: "requires the SLACK_TOKEN environment variable"},
+			{Path: "slack.channels[1].id", Severity: configSeverityError, Message: "is required"},
+		}},
+		{"calendar with a short token", func(s *Settings) {
+			s.Calendar = SettingsCalendar{URL: "example.com/calendar.ics", Token: "secret"}
+		}, []ConfigFinding{
+			{Path: "calendar.url", Severity: configSeverityError, Message: `"example.com/calendar.ics" is not a URL (e.g., https://example.com/calendar.ics)`},
+			{Path: "calendar.url", Severity: configSeverityError, Message: "requires the CALENDAR_TOKEN environment variable with at least 32 characters"},
+		}},
+		{"stats ttl", func(s *Settings) { s.YouTube.StatsTTLHours = 0 }, []ConfigFinding{
+			{Path: "youtube.statsTTLHours", Severity: configSeverityError, Message: "must be greater than zero"},
+		}},
+		{"line width", func(s *Settings) { s.Teleprompter.LineWidth = 0 }, []ConfigFinding{
+			{Path: "teleprompter.lineWidth", Severity: configSeverityError, Message: "must be greater than zero"},
+		}},
+		{"trends", func(s *Settings) { s.Trends.IntervalDays = 0 }, []ConfigFinding{
+			{Path: "trends.intervalDays", Severity: configSeverityError, Message: "must be greater than zero"},
+		}},
+		{"date horizons", func(s *Settings) {
+			s.Schedule.FarFuture = "soon"
+			s.Schedule.Imminent = "0d"
+		}, []ConfigFinding{
+			{Path: "schedule.farFuture", Severity: configSeverityError, Message: `"soon" must be a number followed by d, w, or mo (e.g., 7d, 6w, or 3mo)`},
+			{Path: "schedule.imminent", Severity: configSeverityError, Message: `"0d" must be greater than zero`},
+		}},
+		{"imminent after far future", func(s *Settings) { s.Schedule.Imminent = "2mo" }, []ConfigFinding{
+			{Path: "schedule.imminent", Severity: configSeverityError, Message: "must be shorter than schedule.farFuture"},
+		}},
+		{"demo webhook", func(s *Settings) { s.Demo.Webhook = "hooks.example.com" }, []ConfigFinding{
+			{Path: "demo.webhook", Severity: configSeverityError, Message: `"hooks.example.com" must be an http or https URL`},
+		}},
+		{"blackout windows", func(s *Settings) {
+			s.Sponsorship.BlackoutWindows = []BlackoutWindow{{Sponsor: "Acme", Start: "2030-03-04"}, {Start: "2030-03-04"}}
+		}, []ConfigFinding{
+			{Path: "sponsorship.blackoutWindows[1]", Severity: configSeverityError, Message: "sponsor is required"},
+		}},
+		{"invoice terms", func(s *Settings) { s.Sponsorship.InvoiceTermDays = 0 }, []ConfigFinding{
+			{Path: "sponsorship.invoiceTermDays", Severity: configSeverityError, Message: "must be greater than zero"},
+		}},
+		{"credits", func(s *Settings) {
+			s.Credits = SettingsCredits{Licenses: []string{"MIT"}, AttributionRequired: []string{"CC-BY-4.0"}}
+		}, []ConfigFinding{
+			{Path: "credits.attributionRequired[0]", Severity: configSeverityError, Message: `"CC-BY-4.0" is not one of credits.licenses`},
+		}},
+		{"record checklist", func(s *Settings) {
+			s.Record.Checklist = []string{""}
+			s.Record.Categories = map[string][]string{"ama": {"Chat is open", " "}}
+		}, []ConfigFinding{
+			{Path: "record.checklist[0]", Severity: configSeverityError, Message: "must not be empty"},
+			{Path: "record.categories.ama[1]", Severity: configSeverityError, Message: "must not be empty"},
+		}},
+		{"content trigger keywords", func(s *Settings) { s.Content.TriggerKeywords = []string{"CVE", " "} }, []ConfigFinding{
+			{Path: "content.triggerKeywords[1]", Severity: configSeverityError, Message: "must not be empty"},
+		}},
+		{"quality weights", func(s *Settings) { s.Quality.Weights = map[string]int{"title": -1, "thumbnail": 5} }, []ConfigFinding{
+			{Path: "quality.weights.thumbnail", Severity: configSeverityError, Message: "is not one of title, description, tags, tweet, thumbnailText, chapters"},
+			{Path: "quality.weights.title", Severity: configSeverityError, Message: "must not be negative"},
+		}},
+		{"no quality weights", func(s *Settings) { s.Quality.Weights = map[string]int{"title": 0} }, []ConfigFinding{
+			{Path: "quality.weights", Severity: configSeverityError, Message: "at least one weight must be greater than zero"},
+		}},
+		{"workload", func(s *Settings) {
+			s.Workload.WeeklyCapacity = 0
+			s.Workload.Hours = map[string]float64{"started": -1, "filming": 2}
+		}, []ConfigFinding{
+			{Path: "workload.weeklyCapacity", Severity: configSeverityError, Message: "must be greater than zero"},
+			{Path: "workload.hours.filming", Severity: configSeverityError, Message: "is not a known phase"},
+			{Path: "workload.hours.started", Severity: configSeverityError, Message: "must not be negative"},
+		}},
+		{"unknown timezone", func(s *Settings) { s.Schedule.Timezone = "Mars/Olympus" }, []ConfigFinding{
+			{Path: "schedule.timezone", Severity: configSeverityError, Message: `"Mars/Olympus" is not a known timezone (e.g., Europe/Berlin)`},
+		}},
+		{"negative numbers", func(s *Settings) {
+			s.Schedule.MinGapDays = -1
+			s.Sponsorship.ReminderDays = -14
+		}, []ConfigFinding{
+			{Path: "schedule.minGapDays", Severity: configSeverityError, Message: "must not be negative"},
+			{Path: "sponsorship.reminderDays", Severity: configSeverityError, Message: "must not be negative"},
+		}},
+		{"schedule", func(s *Settings) {
+			s.Schedule.Weekdays = []string{"Tuesday", "Tusday"}
+			s.Schedule.Time = "4pm"
+		}, []ConfigFinding{
+			{Path: "schedule.weekdays[1]", Severity: configSeverityError, Message: "Tusday is not a valid weekday"},
+			{Path: "schedule.time", Severity: configSeverityError, Message: `schedule time "4pm" must be in the 15:04 format`},
+		}},
+		{"upload", func(s *Settings) {
+			s.Upload.Visibility = "Public"
+			s.Upload.Categories = map[string]SettingsUploadCategory{"ai": {Visibility: "hidden", MadeForKids: "maybe"}}
+		}, []ConfigFinding{
+			{Path: "upload.visibility", Severity: configSeverityError, Message: `"Public" is not one of private, unlisted, public, or scheduled`},
+			{Path: "upload.categories.ai.visibility", Severity: configSeverityError, Message: `"hidden" is not one of private, unlisted, public, or scheduled`},
+			{Path: "upload.categories.ai.madeForKids", Severity: configSeverityError, Message: "must be true or false"},
+		}},
+		{"theme", func(s *Settings) { s.UI.Theme = "solarized" }, []ConfigFinding{
+			{Path: "ui.theme", Severity: configSeverityWarning, Message: `"solarized" is not a known theme; charm is used instead`},
+		}},
+		{"hugo path", func(s *Settings) { s.Hugo.Path = missingPath }, []ConfigFinding{
+			{Path: "hugo.path", Severity: configSeverityWarning, Message: missingPath + " does not exist"},
+		}},
+		{"custom fields", func(s *Settings) {
+			s.CustomFields = []CustomField{{Key: "guest", Type: customFieldTypeString}, {Type: "number"}}
+		}, []ConfigFinding{
+			{Path: "customFields[1].key", Severity: configSeverityError, Message: "is required"},
+			{Path: "customFields[1].type", Severity: configSeverityError, Message: `"number" is not one of string, bool, date, or select`},
+		}},
+		{"rules", func(s *Settings) {
+			s.Rules = []Rule{
+				{Name: "Ok", Trigger: RuleTrigger{Type: ruleTriggerCreated}, Actions: []RuleAction{{Type: ruleActionWebhook, URL: "https://example.com"}}},
+				{Name: "Plain", Trigger: RuleTrigger{Type: ruleTriggerCreated}, Actions: []RuleAction{{Type: ruleActionWebhook, URL: "http://example.com"}}},
+				{Name: "Empty", Trigger: RuleTrigger{Type: "deleted"}},
+			}
+		}, []ConfigFinding{
+			{Path: "rules[1].actions[0].url", Severity: configSeverityError, Message: "webhook URLs must use https"},
+			{Path: "rules[2]", Severity: configSeverityError, Message: `rule Empty: unknown trigger "deleted"`},
+			{Path: "rules[2]", Severity: configSeverityError, Message: "rule Empty: at least one action is required"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := getValidTestSettings()
+			test.change(&s)
+			if actual := validateSettings(s); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected: %v\nGot: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConfig_getConfigFindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	if err := os.WriteFile(path, []byte("email:\n  from: me@example.com\n  editTo: editor@example.com\n  thumbnailTp: x\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	s := getValidTestSettings()
+	s.Email.Password = ""
+	findings := getConfigFindings(path, s)
+	expected := "error: email.thumbnailTp: unknown key (check the spelling and the indentation)\nerror: email.editTo: requires the EMAIL_PASSWORD environment variable"
+	if actual := getConfigFindingsText(findings); actual != expected {
+		t.Errorf("Expected: %s\nGot: %s", expected, actual)
+	}
+	if !hasConfigErrors(findings) {
+		t.Errorf("Expected the findings to contain errors")
+	}
+	if hasConfigErrors([]ConfigFinding{{Path: "ui.theme", Severity: configSeverityWarning}}) {
+		t.Errorf("Expected warnings not to count as errors")
+	}
+	if actual := getConfigFindings(filepath.Join(t.TempDir(), "missing.yaml"), s); !hasConfigErrors(actual) {
+		t.Errorf("Expected a missing settings file to be an error, but got %v", actual)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type ContentFlags = workflow.ContentFlags
+
+const contentDefaultDisclaimer = "This video is for educational purposes only. Do not use the techniques shown in it against systems you are not authorized to test."
+
+// contentAgeRestrictedRating is the YouTube content rating of age-restricted videos.
+const contentAgeRestrictedRating = "ytAgeRestricted"
+
+func hasContentFlags(flags ContentFlags) bool {
+	return flags.AgeRestricted || flags.ContainsSecurityExploits
+}
+
+// getContentDisclaimer returns the disclaimer block appended to the description or an empty string if the video has no content flags.
+func getContentDisclaimer(flags ContentFlags, disclaimer string) string {
+	if !hasContentFlags(flags) {
+		return ""
+	}
+	if len(strings.TrimSpace(flags.DisclaimerText)) > 0 {
+		disclaimer = flags.DisclaimerText
+	}
+	if len(strings.TrimSpace(disclaimer)) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("▬▬▬▬▬▬ ⚠️ Disclaimer ⚠️ ▬▬▬▬▬▬\n%s\n", strings.TrimSpace(disclaimer))
+}
+
+// getContentTriggerKeywords returns the keywords found in the manuscript, matched case-insensitively, in the order they are configured.
+func getContentTriggerKeywords(manuscript string, keywords []string) []string {
+	manuscript = strings.ToLower(manuscript)
+	found := []string{}
+	for _, keyword := range keywords {
+		keyword = strings.TrimSpace(keyword)
+		if len(keyword) > 0 && strings.Contains(manuscript, strings.ToLower(keyword)) {
+			found = append(found, keyword)
+		}
+	}
+	return found
+}
+
+// getContentFlagsWarning returns a warning if the manuscript mentions any of the keywords while the video has no content flags.
+func getContentFlagsWarning(flags ContentFlags, manuscript string, keywords []string) string {
+	if hasContentFlags(flags) {
+		return ""
+	}
+	found := getContentTriggerKeywords(manuscript, keywords)
+	if len(found) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("The manuscript mentions %s but the video has no content flags. Set them in the Definition phase if it shows security exploits or is not suitable for everyone.", strings.Join(found, ", "))
+}
+
+func getVideoContentFlagsWarning(video Video, keywords []string) string {
+	if len(video.Gist) == 0 {
+		return ""
+	}
+	manuscript, _, err := readManuscript(video.Gist)
+	if err != nil {
+		return ""
+	}
+	return getContentFlagsWarning(video.ContentFlags, manuscript, keywords)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContentFlags_getContentDisclaimer(t *testing.T) {
+	tests := map[string]struct {
+		flags    ContentFlags
+		expected string
+	}{
+		"no flags": {
+			flags:    ContentFlags{DisclaimerText: "Custom"},
+			expected: "",
+		},
+		"configured disclaimer": {
+			flags:    ContentFlags{ContainsSecurityExploits: true},
+			expected: "▬▬▬▬▬▬ ⚠️ Disclaimer ⚠️ ▬▬▬▬▬▬\nConfigured\n",
+		},
+		"video disclaimer": {
+			flags:    ContentFlags{AgeRestricted: true, DisclaimerText: " Custom "},
+			expected: "▬▬▬▬▬▬ ⚠️ Disclaimer ⚠️ ▬▬▬▬▬▬\nCustom\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getContentDisclaimer(tc.flags, "Configured"); actual != tc.expected {
+				t.Errorf("Expected: %q\nGot: %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestContentFlags_getUploadRequest(t *testing.T) {
+	settingsOrig := settings
+	defer func() { settings = settingsOrig }()
+	settings.Content.Disclaimer = "Do not try this at home."
+
+	upload := getUploadRequest(Video{Description: "Hacking things.", ContentFlags: ContentFlags{AgeRestricted: true}}, UploadStatus{})
+	if !strings.HasSuffix(upload.Snippet.Description, "▬▬▬▬▬▬ ⚠️ Disclaimer ⚠️ ▬▬▬▬▬▬\nDo not try this at home.\n") {
+		t.Errorf("Expected the description to end with the disclaimer\nGot: %s", upload.Snippet.Description)
+	}
+	if upload.ContentDetails == nil || upload.ContentDetails.ContentRating == nil || upload.ContentDetails.ContentRating.YtRating != contentAgeRestrictedRating {
+		t.Errorf("Expected the %s rating\nGot: %+v", contentAgeRestrictedRating, upload.ContentDetails)
+	}
+
+	upload = getUploadRequest(Video{Description: "Hacking things.", ContentFlags: ContentFlags{ContainsSecurityExploits: true}}, UploadStatus{})
+	if !strings.Contains(upload.Snippet.Description, "Do not try this at home.") {
+		t.Errorf("Expected the description to contain the disclaimer\nGot: %s", upload.Snippet.Description)
+	}
+	if upload.ContentDetails != nil {
+		t.Errorf("Expected no content details\nGot: %+v", upload.ContentDetails)
+	}
+
+	upload = getUploadRequest(Video{Description: "Nothing to see."}, UploadStatus{})
+	if strings.Contains(upload.Snippet.Description, "Disclaimer") {
+		t.Errorf("Expected no disclaimer\nGot: %s", upload.Snippet.Description)
+	}
+}
+
+func TestContentFlags_getUploadStatus(t *testing.T) {
+	defaults := SettingsUpload{Visibility: visibilityPrivate, MadeForKids: true}
+	tests := map[string]struct {
+		video    Video
+		expected bool
+	}{
+		"no flags":       {video: Video{}, expected: true},
+		"age restricted": {video: Video{ContentFlags: ContentFlags{AgeRestricted: true}}, expected: false},
+		"exploits":       {video: Video{MadeForKids: "true", ContentFlags: ContentFlags{ContainsSecurityExploits: true}}, expected: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, err := getUploadStatus(tc.video, defaults)
+			if err != nil {
+				t.Fatalf("Expected no error\nGot: %v", err)
+			}
+			if status.MadeForKids != tc.expected {
+				t.Errorf("Expected: %t\nGot: %t", tc.expected, status.MadeForKids)
+			}
+		})
+	}
+}
+
+func TestContentFlags_getContentFlagsWarning(t *testing.T) {
+	keywords := []string{"CVE", "exploit", " "}
+	tests := map[string]struct {
+		flags      ContentFlags
+		manuscript string
+		expected   string
+	}{
+		"no keywords": {
+			manuscript: "Let's deploy an app.",
+			expected:   "",
+		},
+		"keywords without flags": {
+			manuscript: "We'll use cve-2024-1234 to Exploit the cluster.",
+			expected:   "The manuscript mentions CVE, exploit but the video has no content flags. Set them in the Definition phase if it shows security exploits or is not suitable for everyone.",
+		},
+		"keywords with flags": {
+			flags:      ContentFlags{ContainsSecurityExploits: true},
+			manuscript: "We'll use CVE-2024-1234.",
+			expected:   "",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getContentFlagsWarning(tc.flags, tc.manuscript, keywords); actual != tc.expected {
+				t.Errorf("Expected: %q\nGot: %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestContentFlags_getVideoContentFlagsWarning(t *testing.T) {
+	gist := filepath.Join(t.TempDir(), "video.md")
+	if err := os.WriteFile(gist, []byte("## Exploit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if warning := getVideoContentFlagsWarning(Video{Gist: gist}, []string{"exploit"}); len(warning) == 0 {
+		t.Errorf("Expected a warning\nGot none")
+	}
+	if warning := getVideoContentFlagsWarning(Video{Gist: filepath.Join(t.TempDir(), "missing.md")}, []string{"exploit"}); len(warning) > 0 {
+		t.Errorf("Expected no warning for a missing manuscript\nGot: %s", warning)
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+const costKindRecording = "recording"
+const costKindEditing = "editing"
+const costKindEditorInvoice = "editor invoice"
+const costKindStockFootage = "stock footage"
+const costKindOther = "other"
+
+var costKinds = []string{costKindRecording, costKindEditing, costKindEditorInvoice, costKindStockFootage, costKindOther}
+
+var amountRegex = regexp.MustCompile(`^([^\d\-.,]*)\s*(-?[\d.,]+)\s*([^\d\s]*)$`)
+
+var amountCommaGroupedRegex = regexp.MustCompile(`^-?\d{1,3}(,\d{3})+(\.\d+)?$`)
+var amountDotGroupedRegex = regexp.MustCompile(`^-?\d{1,3}(\.\d{3})+(,\d+)?$`)
+var amountPlainRegex = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+var amountDecimalCommaRegex = regexp.MustCompile(`^-?\d+,\d+$`)
+
+var currencySymbols = map[string]string{"$": "USD", "€": "EUR", "£": "GBP"}
+
+type Cost = workflow.Cost
+
+type CostTotals struct {
+	Amounts map[string]float64
+	Hours   time.Duration
+}
+
+// VideoCost is the total cost of a video together with the sponsorship revenue, when there is any.
+type VideoCost struct {
+	Name     string
+	Category string
+	CostTotals
+	Revenue         float64
+	RevenueCurrency string
+	// HasMargin is false when there is no revenue or the revenue is in a currency different from the costs.
+	HasMargin bool
+	Margin    float64
+}
+
+type CostReport struct {
+	// Months maps months (2030-01) to categories to totals.
+	Months map[string]map[string]CostTotals
+	Videos []VideoCost
+	// Revenue maps months to currencies to the sponsorship revenue attributed to them.
+	Revenue map[string]map[string]float64
+}
+
+// parseHours accepts durations like 2h30m or 45m as well as plain numbers of hours (e.g., 1.5).
+func parseHours(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.ReplaceAll(value, " ", ""))
+	if len(value) == 0 {
+		return 0, nil
+	}
+	if hours, err := strconv.ParseFloat(value, 64); err == nil {
+		if hours < 0 {
+			return 0, fmt.Errorf("hours %q cannot be negative", value)
+		}
+		return time.Duration(hours * float64(time.Hour)), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("hours %q must be a number or a duration like 2h30m", value)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("hours %q cannot be negative", value)
+	}
+	return duration, nil
+}
+
+// parseAmount parses amounts like 1,500, $1500, 1500 USD, €200.50, or 1.500,00 €. The currency is empty when it is not specified.
+func parseAmount(value string) (float64, string, error) {
+	value = strings.TrimSpace(value)
+	match := amountRegex.FindStringSubmatch(value)
+	if match == nil {
+		return 0, "", fmt.Errorf("amount %q is not a number", value)
+	}
+	currency := strings.ToUpper(strings.TrimSpace(match[1]))
+	if len(currency) == 0 {
+		currency = strings.ToUpper(match[3])
+	}
+	if code, ok := currencySymbols[currency]; ok {
+		currency = code
+	}
+	amount, err := parseAmountNumber(match[2])
+	if err != nil {
+		return 0, "", fmt.Errorf("amount %q is not a number", value)
+	}
+	return amount, currency, nil
+}
+
+// parseAmountNumber accepts numbers grouped with commas and using a decimal dot (1,500.50) as well as those grouped with dots and using
+// a decimal comma (1.500,50). A single separator is a decimal one unless it is a comma followed by three digits (1,500).
+func parseAmountNumber(number string) (float64, error) {
+	switch {
+	case amountCommaGroupedRegex.MatchString(number), amountPlainRegex.MatchString(number):
+		return strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+	case amountDotGroupedRegex.MatchString(number), amountDecimalCommaRegex.MatchString(number):
+		return strconv.ParseFloat(strings.Replace(strings.ReplaceAll(number, ".", ""), ",", ".", 1), 64)
+	}
+	return 0, fmt.Errorf("%q is not a number", number)
+}
+
+func validateCost(cost Cost) error {
+	if len(cost.Amount) == 0 && len(cost.Hours) == 0 {
+		return fmt.Errorf("either the amount or the hours are required")
+	}
+	if len(cost.Amount) > 0 {
+		if _, _, err := parseAmount(cost.Amount); err != nil {
+			return err
+		}
+	}
+	if _, err := parseHours(cost.Hours); err != nil {
+		return err
+	}
+	if _, err := time.Parse(dayFormat, cost.Date); err != nil {
+		return fmt.Errorf("date %q must be in the %s format", cost.Date, dayFormat)
+	}
+	return nil
+}
+
+func (t *CostTotals) add(cost Cost, defaultCurrency string) {
+	if t.Amounts == nil {
+		t.Amounts = map[string]float64{}
+	}
+	if amount, currency, err := parseAmount(cost.Amount); err == nil {
+		if len(currency) == 0 {
+			currency = strings.ToUpper(cost.Currency)
+		}
+		if len(currency) == 0 {
+			currency = defaultCurrency
+		}
+		t.Amounts[currency] += amount
+	}
+	if hours, err := parseHours(cost.Hours); err == nil {
+		t.Hours += hours
+	}
+}
+
+func getCostTotals(costs []Cost, defaultCurrency string) CostTotals {
+	totals := CostTotals{Amounts: map[string]float64{}}
+	for _, cost := range costs {
+		totals.add(cost, defaultCurrency)
+	}
+	return totals
+}
+
+// getSponsorshipRevenue returns the month the revenue of a sponsored video is attributed to. Paid invoices are attributed to the month
+// they were paid in with the invoiced amount, when there is one. Everything else is attributed to the publish month with the sponsorship amount.
+func getSponsorshipRevenue(video Video, defaultCurrency string) (string, float64, string, bool) {
+	date, err := parseDate(video.Date)
+	value := video.Sponsorship.Amount
+	if isInvoicePaid(video.Sponsorship) {
+		date, err = parseDate(video.Sponsorship.PaidDate)
+		if len(video.Sponsorship.AmountInvoiced) > 0 {
+			value = video.Sponsorship.AmountInvoiced
+		}
+	}
+	if err != nil {
+		return "", 0, "", false
+	}
+	amount, currency, err := parseAmount(value)
+	if err != nil {
+		return "", 0, "", false
+	}
+	if len(currency) == 0 {
+		currency = defaultCurrency
+	}
+	return date.Format("2006-01"), amount, currency, true
+}
+
+// getCostReport groups costs by month and category, attributes sponsorship revenue to months, and calculates the margin of videos with both costs and sponsorships.
+func getCostReport(videos []Video, defaultCurrency string) CostReport {
+	report := CostReport{Months: map[string]map[string]CostTotals{}, Revenue: map[string]map[string]float64{}}
+	for _, video := range videos {
+		if workflow.IsSponsored(video.Sponsorship) {
+			if month, amount, currency, ok := getSponsorshipRevenue(video, defaultCurrency); ok {
+				if report.Revenue[month] == nil {
+					report.Revenue[month] = map[string]float64{}
+				}
+				report.Revenue[month][currency] += amount
+			}
+		}
+		if len(video.Costs) == 0 {
+			continue
+		}
+		for _, cost := range video.Costs {
+			date, err := time.Parse(dayFormat, cost.Date)
+			if err != nil {
+				continue
+			}
+			month := date.Format("2006-01")
+			if report.Months[month] == nil {
+				report.Months[month] = map[string]CostTotals{}
+			}
+			totals := report.Months[month][video.Category]
+			totals.add(cost, defaultCurrency)
+			report.Months[month][video.Category] = totals
+		}
+		videoCost := VideoCost{Name: video.Name, Category: video.Category, CostTotals: getCostTotals(video.Costs, defaultCurrency)}
+		if workflow.IsSponsored(video.Sponsorship) {
+			if revenue, currency, err := parseAmount(video.Sponsorship.Amount); err == nil {
+				if len(currency) == 0 {
+					currency = defaultCurrency
+				}
+				videoCost.Revenue = revenue
+				videoCost.RevenueCurrency = currency
+				if len(videoCost.Amounts) <= 1 {
+					videoCost.HasMargin = true
+					for costCurrency, amount := range videoCost.Amounts {
+						if costCurrency != currency {
+							videoCost.HasMargin = false
+						}
+						revenue -= amount
+					}
+					if videoCost.HasMargin {
+						videoCost.Margin = revenue
+					}
+				}
+			}
+		}
+		report.Videos = append(report.Videos, videoCost)
+	}
+	sort.SliceStable(report.Videos, func(i, j int) bool {
+		return report.Videos[i].Name < report.Videos[j].Name
+	})
+	return report
+}
+
+func formatAmounts(amounts map[string]float64) string {
+	currencies := []string{}
+	for currency := range amounts {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	parts := []string{}
+	for _, currency := range currencies {
+		parts = append(parts, fmt.Sprintf("%.2f %s", amounts[currency], currency))
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+	return strings.Join(parts, " + ")
+}
+
+func formatHours(hours time.Duration) string {
+	return fmt.Sprintf("%.1fh", hours.Hours())
+}
+
+func getCostSummary(video Video) string {
+	totals := getCostTotals(video.Costs, settings.Costs.Currency)
+	return fmt.Sprintf("%s, %s", formatAmounts(totals.Amounts), formatHours(totals.Hours))
+}
+
+func getCostReportText(report CostReport) string {
+	revenue := getRevenueReportLines(report.Revenue)
+	if len(report.Videos) == 0 {
+		return strings.Join(append([]string{"No costs were logged yet."}, revenue...), "\n")
+	}
+	lines := []string{"Costs by month and category:"}
+	months := []string{}
+	for month := range report.Months {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	for _, month := range months {
+		categories := []string{}
+		for category := range report.Months[month] {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			totals := report.Months[month][category]
+			lines = append(lines, fmt.Sprintf("%s %s: %s, %s", month, category, formatAmounts(totals.Amounts), formatHours(totals.Hours)))
+		}
+	}
+	lines = append(lines, "", "Costs by video:")
+	for _, video := range report.Videos {
+		line := fmt.Sprintf("%s: %s, %s", video.Name, formatAmounts(video.Amounts), formatHours(video.Hours))
+		if video.HasMargin {
+			line = fmt.Sprintf("%s, margin %.2f %s", line, video.Margin, video.RevenueCurrency)
+		} else if video.Revenue > 0 {
+			line = fmt.Sprintf("%s, revenue %.2f %s (different currency)", line, video.Revenue, video.RevenueCurrency)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(append(lines, revenue...), "\n")
+}
+
+func getRevenueReportLines(revenue map[string]map[string]float64) []string {
+	if len(revenue) == 0 {
+		return nil
+	}
+	months := []string{}
+	for month := range revenue {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	lines := []string{"", "Sponsorship revenue by month (paid invoices by the payment date):"}
+	for _, month := range months {
+		lines = append(lines, fmt.Sprintf("%s: %s", month, formatAmounts(revenue[month])))
+	}
+	return lines
+}
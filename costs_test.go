@@ -0,0 +1,153 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCosts_parseHours(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		err      bool
+	}{
+		{"2h30m", 150 * time.Minute, false},
+		{"2h 30m", 150 * time.Minute, false},
+		{"45m", 45 * time.Minute, false},
+		{"1.5", 90 * time.Minute, false},
+		{"3", 3 * time.Hour, false},
+		{"", 0, false},
+		{"-1h", 0, true},
+		{"two hours", 0, true},
+	}
+	for _, test := range tests {
+		actual, err := parseHours(test.value)
+		if (err != nil) != test.err || actual != test.expected {
+			t.Errorf("%q: expected %v (error=%t), but got %v (%v)", test.value, test.expected, test.err, actual, err)
+		}
+	}
+}
+
+func TestCosts_parseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		amount   float64
+		currency string
+		err      bool
+	}{
+		{"1500", 1500, "", false},
+		{"1,500", 1500, "", false},
+		{"$1,500.50", 1500.5, "USD", false},
+		{"€200", 200, "EUR", false},
+		{"1500 usd", 1500, "USD", false},
+		{"1.500,00 €", 1500, "EUR", false},
+		{"€200,50", 200.5, "EUR", false},
+		{"1.500.000", 1500000, "", false},
+		{"1,500,000.25", 1500000.25, "", false},
+		{"1,5,0", 0, "", true},
+		{"1.5,0.0", 0, "", true},
+		{"1.5", 1.5, "", false},
+		{"N/A", 0, "", true},
+		{"-", 0, "", true},
+	}
+	for _, test := range tests {
+		amount, currency, err := parseAmount(test.value)
+		if (err != nil) != test.err || amount != test.amount || currency != test.currency {
+			t.Errorf("%q: expected %v %s (error=%t), but got %v %s (%v)", test.value, test.amount, test.currency, test.err, amount, currency, err)
+		}
+	}
+}
+
+func TestCosts_validateCost(t *testing.T) {
+	costs := []struct {
+		cost Cost
+		err  bool
+	}{
+		{Cost{Kind: costKindEditing, Hours: "2h", Date: "2030-01-21"}, false},
+		{Cost{Kind: costKindEditorInvoice, Amount: "$300", Date: "2030-01-21"}, false},
+		{Cost{Kind: costKindOther, Date: "2030-01-21"}, true},
+		{Cost{Kind: costKindEditing, Hours: "2h", Date: "21.01.2030"}, true},
+		{Cost{Kind: costKindEditorInvoice, Amount: "a lot", Date: "2030-01-21"}, true},
+	}
+	for _, cost := range costs {
+		if err := validateCost(cost.cost); (err != nil) != cost.err {
+			t.Errorf("%+v: expected error=%t, but got %v", cost.cost, cost.err, err)
+		}
+	}
+}
+
+func TestCosts_getCostReport(t *testing.T) {
+	videos := []Video{
+		{
+			Name:        "sponsored",
+			Category:    "kubernetes",
+			Sponsorship: Sponsorship{Amount: "$2,000"},
+			Costs: []Cost{
+				{Kind: costKindEditorInvoice, Amount: "300", Currency: "USD", Date: "2030-01-10"},
+				{Kind: costKindRecording, Hours: "2h30m", Date: "2030-01-05"},
+				{Kind: costKindStockFootage, Amount: "$50", Date: "2030-02-01"},
+			},
+		},
+		{
+			Name:        "mixed-currencies",
+			Category:    "kubernetes",
+			Sponsorship: Sponsorship{Amount: "1000"},
+			Costs: []Cost{
+				{Kind: costKindEditorInvoice, Amount: "€100", Currency: "USD", Date: "2030-01-15"},
+			},
+		},
+		{
+			Name:     "not-sponsored",
+			Category: "gitops",
+			Costs:    []Cost{{Kind: costKindEditing, Hours: "1.5", Date: "2030-01-20"}},
+		},
+		{Name: "no-costs", Category: "gitops", Sponsorship: Sponsorship{Amount: "500"}},
+	}
+	report := getCostReport(videos, "USD")
+	january := report.Months["2030-01"]
+	if january["kubernetes"].Amounts["USD"] != 300 || january["kubernetes"].Amounts["EUR"] != 100 || january["kubernetes"].Hours != 150*time.Minute {
+		t.Errorf("Unexpected January kubernetes totals %+v", january["kubernetes"])
+	}
+	if january["gitops"].Hours != 90*time.Minute || report.Months["2030-02"]["kubernetes"].Amounts["USD"] != 50 {
+		t.Errorf("Unexpected totals %+v", report.Months)
+	}
+	if len(report.Videos) != 3 {
+		t.Fatalf("Expected only videos with costs, but got %+v", report.Videos)
+	}
+	expected := map[string]struct {
+		hasMargin bool
+		margin    float64
+	}{
+		"mixed-currencies": {false, 0},
+		"not-sponsored":    {false, 0},
+		"sponsored":        {true, 1650},
+	}
+	for _, video := range report.Videos {
+		if video.HasMargin != expected[video.Name].hasMargin || video.Margin != expected[video.Name].margin {
+			t.Errorf("%s: expected margin %v (%t), but got %v (%t)", video.Name, expected[video.Name].margin, expected[video.Name].hasMargin, video.Margin, video.HasMargin)
+		}
+	}
+}
+
+func TestCosts_getCostReportRevenue(t *testing.T) {
+	videos := []Video{
+		{Name: "unpaid", Date: "2030-01-21T16:00", Sponsorship: Sponsorship{Amount: "$2,000", InvoiceSentDate: "2030-01-22"}},
+		{Name: "paid", Date: "2030-01-28T16:00", Sponsorship: Sponsorship{Amount: "$2,000", InvoiceSentDate: "2030-01-29", PaidDate: "2030-03-30", AmountInvoiced: "$1,800"}},
+		{Name: "paid-without-amount", Date: "2030-02-04T16:00", Sponsorship: Sponsorship{Amount: "€500", InvoiceSentDate: "2030-02-05", PaidDate: "2030-03-01"}},
+		{Name: "not-sponsored", Date: "2030-01-21T16:00"},
+		{Name: "no-date", Sponsorship: Sponsorship{Amount: "1000"}},
+	}
+	expected := map[string]map[string]float64{
+		"2030-01": {"USD": 2000},
+		"2030-03": {"USD": 1800, "EUR": 500},
+	}
+	report := getCostReport(videos, "USD")
+	if !reflect.DeepEqual(report.Revenue, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, report.Revenue)
+	}
+	if text := getCostReportText(report); !strings.Contains(text, "2030-03: 500.00 EUR + 1800.00 USD") {
+		t.Errorf("Expected the revenue in the report\nGot: %s", text)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const createStepDirectory = "category directory"
+const createStepManuscript = "manuscript"
+const createStepVideo = "video file"
+const createStepIndex = "index entry"
+
+// ErrCreateVideo tells which step of creating a video failed. Whatever was created before the failure is removed.
+type ErrCreateVideo struct {
+	Step string
+	Path string
+	Err  error
+}
+
+func (e *ErrCreateVideo) Error() string {
+	return fmt.Sprintf("creating the %s (%s) failed: %s", e.Step, e.Path, e.Err)
+}
+
+func (e *ErrCreateVideo) Unwrap() error {
+	return e.Err
+}
+
+// VideoCreationState is what exists of a video. The video file is written when the video is edited for the first time so a created video has the manuscript and the index entry.
+type VideoCreationState struct {
+	Manuscript bool
+	Video      bool
+	Indexed    bool
+}
+
+func (s VideoCreationState) IsCreated() bool {
+	return s.Manuscript && s.Indexed
+}
+
+// IsPartial is true when a previous creation stopped midway (or the files were changed outside of the tool) and the video can be completed instead of created.
+func (s VideoCreationState) IsPartial() bool {
+	return !s.IsCreated() && (s.Manuscript || s.Video || s.Indexed)
+}
+
+func (s VideoCreationState) String() string {
+	found := []string{}
+	missing := []string{}
+	for _, part := range []struct {
+		name   string
+		exists bool
+	}{
+		{"the manuscript", s.Manuscript},
+		{"the video file", s.Video},
+		{"the index entry", s.Indexed},
+	} {
+		if part.exists {
+			found = append(found, part.name)
+		} else {
+			missing = append(missing, part.name)
+		}
+	}
+	return fmt.Sprintf("%s exists without %s", strings.Join(found, " and "), strings.Join(missing, " and "))
+}
+
+// VideoCreator creates the files of new videos. The index is written last so that a failure never leaves an entry without files.
+type VideoCreator struct {
+	ManuscriptDir    string
+	IndexPath        string
+	MkdirAll         func(string, os.FileMode) error
+	CreateManuscript func(string) error
+	WriteIndex       func([]VideoIndex) error
+	// Template replaces the built-in manuscript and, if it has a front matter, writes the video file with its defaults.
+	Template *ManuscriptTemplate
+}
+
+func NewVideoCreator(yaml YAML) VideoCreator {
+	return VideoCreator{
+		ManuscriptDir:    "manuscript",
+		IndexPath:        yaml.IndexPath,
+		MkdirAll:         os.MkdirAll,
+		CreateManuscript: createManuscript,
+		WriteIndex:       yaml.writeIndex,
+	}
+}
+
+func (v VideoCreator) getPaths(vi VideoIndex) (string, string, string, error) {
+	name, err := sanitizeVideoName(vi.Name, getNameOptions())
+	if err != nil {
+		return "", "", "", err
+	}
+	dir := filepath.Join(v.ManuscriptDir, getImportCategoryDir(vi.Category))
+	return dir, filepath.Join(dir, name+".md"), filepath.Join(dir, name+".yaml"), nil
+}
+
+func isVideoIndexed(vi VideoIndex, index []VideoIndex) bool {
+	name, err := sanitizeVideoName(vi.Name, getNameOptions())
+	if err != nil {
+		return false
+	}
+	for _, item := range index {
+		if getVideoFileName(item.Name) == name && getImportCategoryDir(item.Category) == getImportCategoryDir(vi.Category) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v VideoCreator) GetState(vi VideoIndex, index []VideoIndex) (VideoCreationState, error) {
+	_, manuscriptPath, videoPath, err := v.getPaths(vi)
+	if err != nil {
+		return VideoCreationState{}, err
+	}
+	state := VideoCreationState{Indexed: isVideoIndexed(vi, index)}
+	if _, err := os.Stat(manuscriptPath); err == nil {
+		state.Manuscript = true
+	}
+	if _, err := os.Stat(videoPath); err == nil {
+		state.Video = true
+	}
+	return state, nil
+}
+
+// Create creates the category directory, if needed, the manuscript, and the index entry. It returns the index with the new entry.
+func (v VideoCreator) Create(vi VideoIndex, index []VideoIndex) ([]VideoIndex, error) {
+	state, err := v.GetState(vi, index)
+	if err != nil {
+		return index, err
+	}
+	if state.IsCreated() || state.IsPartial() {
+		return index, fmt.Errorf("video %s already exists (%s)", vi.Name, state)
+	}
+	return v.Complete(vi, state, index)
+}
+
+// Complete creates what is missing of a partially created video. Existing files are adopted as they are.
+func (v VideoCreator) Complete(vi VideoIndex, state VideoCreationState, index []VideoIndex) (updated []VideoIndex, err error) {
+	dir, manuscriptPath, videoPath, err := v.getPaths(vi)
+	if err != nil {
+		return index, err
+	}
+	created := []string{}
+	defer func() {
+		if err != nil {
+			for i := len(created) - 1; i >= 0; i-- {
+				os.Remove(created[i])
+			}
+		}
+		output.Event(outputActionCreate, videoPath, "created", err)
+	}()
+	if _, statErr := os.Stat(dir); os.IsNotExist(statErr) {
+		if err := v.MkdirAll(dir, 0755); err != nil {
+			return index, &ErrCreateVideo{Step: createStepDirectory, Path: dir, Err: err}
+		}
+		created = append(created, dir)
+	}
+	if !state.Manuscript {
+		// The manuscript did not exist so it is removed even if it was only partially written.
+		created = append(created, manuscriptPath)
+		createManuscript := v.CreateManuscript
+		if v.Template != nil {
+			createManuscript = func(path string) error { return writeNewManuscript(path, v.Template.Content) }
+		}
+		if err := createManuscript(manuscriptPath); err != nil {
+			return index, &ErrCreateVideo{Step: createStepManuscript, Path: manuscriptPath, Err: err}
+		}
+	}
+	if v.Template != nil && v.Template.Defaults != nil && !state.Video {
+		video := *v.Template.Defaults
+		video.Name = vi.Name
+		video.Category = vi.Category
+		video.Path = videoPath
+		created = append(created, videoPath)
+		yaml := YAML{}
+		if err := yaml.writeVideo(&video, videoPath); err != nil {
+			return index, &ErrCreateVideo{Step: createStepVideo, Path: videoPath, Err: err}
+		}
+	}
+	if state.Indexed {
+		return index, nil
+	}
+	updated = append(append([]VideoIndex{}, index...), vi)
+	if err := v.WriteIndex(updated); err != nil {
+		return index, &ErrCreateVideo{Step: createStepIndex, Path: v.IndexPath, Err: err}
+	}
+	return updated, nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func getTestVideoCreator(dir string, index *[]VideoIndex) VideoCreator {
+	return VideoCreator{
+		ManuscriptDir:    dir,
+		IndexPath:        filepath.Join(dir, "index.yaml"),
+		MkdirAll:         os.MkdirAll,
+		CreateManuscript: createManuscript,
+		WriteIndex: func(vi []VideoIndex) error {
+			*index = vi
+			return nil
+		},
+	}
+}
+
+func getTestDirFiles(t *testing.T, dir string) []string {
+	files := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir {
+			relative, _ := filepath.Rel(dir, path)
+			files = append(files, relative)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestCreate_VideoCreatorCreate(t *testing.T) {
+	dir := t.TempDir()
+	written := []VideoIndex{}
+	creator := getTestVideoCreator(dir, &written)
+	existing := []VideoIndex{{Name: "Other", Category: "development"}}
+	vi := VideoIndex{Name: "Kubernetes Operators", Category: "development"}
+	index, err := creator.Create(vi, existing)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expected := []VideoIndex{existing[0], vi}
+	if !reflect.DeepEqual(index, expected) || !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected: %v\nGot: %v (written %v)", expected, index, written)
+	}
+	if files := getTestDirFiles(t, dir); !reflect.DeepEqual(files, []string{"development", filepath.Join("development", "kubernetes-operators.md")}) {
+		t.Errorf("Expected the category directory and the manuscript, but got %v", files)
+	}
+	if _, err := creator.Create(vi, index); err == nil {
+		t.Errorf("Expected an error when the video already exists")
+	}
+}
+
+// Whatever was created before a step failed is removed so that the video can be created again.
+func TestCreate_VideoCreatorFailures(t *testing.T) {
+	failure := errors.New("no space left on device")
+	tests := []struct {
+		name        string
+		categoryDir bool
+		fail        func(*VideoCreator)
+		step        string
+		expected    []string
+	}{
+		{"directory", false, func(c *VideoCreator) {
+			c.MkdirAll = func(string, os.FileMode) error { return failure }
+		}, createStepDirectory, []string{}},
+		{"manuscript", false, func(c *VideoCreator) {
+			c.CreateManuscript = func(path string) error {
+				os.WriteFile(path, []byte("partial"), 0644)
+				return failure
+			}
+		}, createStepManuscript, []string{}},
+		{"index", false, func(c *VideoCreator) {
+			c.WriteIndex = func([]VideoIndex) error { return failure }
+		}, createStepIndex, []string{}},
+		{"index in an existing category", true, func(c *VideoCreator) {
+			c.WriteIndex = func([]VideoIndex) error { return failure }
+		}, createStepIndex, []string{"development"}},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		if test.categoryDir {
+			if err := os.Mkdir(filepath.Join(dir, "development"), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		written := []VideoIndex{}
+		creator := getTestVideoCreator(dir, &written)
+		test.fail(&creator)
+		existing := []VideoIndex{{Name: "Other", Category: "development"}}
+		index, err := creator.Create(VideoIndex{Name: "Kubernetes Operators", Category: "development"}, existing)
+		var createErr *ErrCreateVideo
+		if !errors.As(err, &createErr) || createErr.Step != test.step || !errors.Is(err, failure) {
+			t.Errorf("%s: Expected the %s step to fail, but got %v", test.name, test.step, err)
+		}
+		if !reflect.DeepEqual(index, existing) {
+			t.Errorf("%s: Expected the index to be unchanged, but got %v", test.name, index)
+		}
+		if files := getTestDirFiles(t, dir); !reflect.DeepEqual(files, test.expected) {
+			t.Errorf("%s: Expected: %v\nGot: %v", test.name, test.expected, files)
+		}
+	}
+}
+
+func TestCreate_VideoCreatorComplete(t *testing.T) {
+	vi := VideoIndex{Name: "Kubernetes Operators", Category: "development"}
+	tests := []struct {
+		name  string
+		state VideoCreationState
+	}{
+		{"manuscript without the index entry", VideoCreationState{Manuscript: true}},
+		{"video file without the index entry", VideoCreationState{Video: true}},
+		{"manuscript and video file without the index entry", VideoCreationState{Manuscript: true, Video: true}},
+		{"index entry without files", VideoCreationState{Indexed: true}},
+		{"index entry and video file without the manuscript", VideoCreationState{Video: true, Indexed: true}},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		categoryDir := filepath.Join(dir, "development")
+		if err := os.Mkdir(categoryDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		manuscriptPath := filepath.Join(categoryDir, "kubernetes-operators.md")
+		videoPath := filepath.Join(categoryDir, "kubernetes-operators.yaml")
+		if test.state.Manuscript {
+			os.WriteFile(manuscriptPath, []byte("# Written before"), 0644)
+		}
+		if test.state.Video {
+			os.WriteFile(videoPath, []byte("title: Written before\n"), 0644)
+		}
+		index := []VideoIndex{{Name: "Other", Category: "development"}}
+		if test.state.Indexed {
+			index = append(index, vi)
+		}
+		written := append([]VideoIndex{}, index...)
+		creator := getTestVideoCreator(dir, &written)
+		state, err := creator.GetState(vi, index)
+		if err != nil || state != test.state || !state.IsPartial() {
+			t.Errorf("%s: Expected the partial state %+v, but got %+v and %v", test.name, test.state, state, err)
+			continue
+		}
+		if _, err := creator.Create(vi, index); err == nil {
+			t.Errorf("%s: Expected creating a partially created video to fail", test.name)
+		}
+		updated, err := creator.Complete(vi, state, index)
+		if err != nil {
+			t.Errorf("%s: Expected no error, but got %v", test.name, err)
+			continue
+		}
+		if state, _ := creator.GetState(vi, written); !state.IsCreated() || !isVideoIndexed(vi, updated) {
+			t.Errorf("%s: Expected the video to be created, but got %+v", test.name, state)
+		}
+		if data, _ := os.ReadFile(manuscriptPath); test.state.Manuscript && string(data) != "# Written before" {
+			t.Errorf("%s: Expected the existing manuscript to be adopted, but got %q", test.name, data)
+		}
+		if data, _ := os.ReadFile(videoPath); test.state.Video && string(data) != "title: Written before\n" {
+			t.Errorf("%s: Expected the existing video file to be adopted, but got %q", test.name, data)
+		}
+	}
+	if state := (VideoCreationState{Manuscript: true, Indexed: true}); state.IsPartial() || !state.IsCreated() {
+		t.Errorf("Expected a video with the manuscript and the index entry to be created")
+	}
+	if state := (VideoCreationState{}); state.IsPartial() || state.IsCreated() {
+		t.Errorf("Expected a video without files to be neither partial nor created")
+	}
+}
+
+func TestCreate_VideoCreationStateString(t *testing.T) {
+	expected := "the manuscript exists without the video file and the index entry"
+	if actual := (VideoCreationState{Manuscript: true}).String(); actual != expected {
+		t.Errorf("Expected: %s\nGot: %s", expected, actual)
+	}
+}
+
+func TestCreate_VideoCreatorTemplate(t *testing.T) {
+	dir := t.TempDir()
+	written := []VideoIndex{}
+	creator := getTestVideoCreator(dir, &written)
+	creator.Template = &ManuscriptTemplate{Name: "tutorial", Content: "## Intro\n\nMy template\n", Defaults: &Video{Tags: "kubernetes"}}
+	vi := VideoIndex{Name: "Kubernetes Operators", Category: "development"}
+	if _, err := creator.Create(vi, nil); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	content, _, err := readManuscript(filepath.Join(dir, "development", "kubernetes-operators.md"))
+	if err != nil || content != creator.Template.Content {
+		t.Errorf("Expected: the rendered template\nGot: %q (%v)", content, err)
+	}
+	video, err := readVideo(filepath.Join(dir, "development", "kubernetes-operators.yaml"))
+	if err != nil || video.Tags != "kubernetes" || video.Name != vi.Name || video.Category != vi.Category {
+		t.Errorf("Expected: the video file with the template defaults\nGot: %+v (%v)", video, err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type Attribution = workflow.Attribution
+
+const creditsHeader = "▬▬▬▬▬▬ 🙏 Credits 🙏 ▬▬▬▬▬▬"
+
+func getDefaultCreditsLicenses() []string {
+	return []string{"CC0-1.0", "CC-BY-4.0", "CC-BY-SA-4.0", "MIT", "Apache-2.0", "Pixabay", "Unsplash", "Pexels"}
+}
+
+func getDefaultCreditsAttributionRequired() []string {
+	return []string{"CC-BY-4.0", "CC-BY-SA-4.0"}
+}
+
+func getCreditLine(attribution Attribution) string {
+	line := attribution.Asset
+	if len(attribution.Author) > 0 {
+		line = fmt.Sprintf("%s by %s", line, attribution.Author)
+	}
+	line = fmt.Sprintf("%s (%s)", line, attribution.License)
+	if len(attribution.UsedIn) > 0 {
+		line = fmt.Sprintf("%s, used in %s", line, attribution.UsedIn)
+	}
+	if len(attribution.SourceURL) > 0 {
+		line = fmt.Sprintf("%s: %s", line, attribution.SourceURL)
+	}
+	return line
+}
+
+// getCreditsSection returns the Credits section of the description or an empty string if there is nothing to credit.
+func getCreditsSection(attributions []Attribution) string {
+	if len(attributions) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString(creditsHeader + "\n")
+	for _, attribution := range attributions {
+		builder.WriteString(fmt.Sprintf("➡ %s\n", getCreditLine(attribution)))
+	}
+	return builder.String() + "\n"
+}
+
+func getCreditsMarkdown(video Video) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# Credits: %s\n\n", video.Name))
+	for _, attribution := range video.Attributions {
+		builder.WriteString(fmt.Sprintf("- %s\n", getCreditLine(attribution)))
+	}
+	return builder.String()
+}
+
+// getCreditsPath returns the path of the credits file next to the manuscript.
+func getCreditsPath(gist string) string {
+	return strings.TrimSuffix(gist, filepath.Ext(gist)) + ".CREDITS.md"
+}
+
+func exportCredits(video Video) (string, error) {
+	if len(video.Gist) == 0 {
+		return "", fmt.Errorf("the video has no manuscript to export the credits next to")
+	}
+	path := getCreditsPath(video.Gist)
+	return path, os.WriteFile(path, []byte(getCreditsMarkdown(video)), 0644)
+}
+
+func validateAttribution(attribution Attribution, credits SettingsCredits) error {
+	if len(strings.TrimSpace(attribution.Asset)) == 0 {
+		return fmt.Errorf("the asset name is required")
+	}
+	if !slices.Contains(credits.Licenses, attribution.License) {
+		return fmt.Errorf("license %q of %s is not one of %s", attribution.License, attribution.Asset, strings.Join(credits.Licenses, ", "))
+	}
+	if slices.Contains(credits.AttributionRequired, attribution.License) && len(strings.TrimSpace(attribution.Author)) == 0 {
+		return fmt.Errorf("%s requires attribution, so the author of %s is required", attribution.License, attribution.Asset)
+	}
+	if len(attribution.SourceURL) > 0 {
+		if sourceURL, err := url.Parse(attribution.SourceURL); err != nil || (sourceURL.Scheme != "http" && sourceURL.Scheme != "https") {
+			return fmt.Errorf("source URL %q of %s must be an http or https URL", attribution.SourceURL, attribution.Asset)
+		}
+	}
+	return nil
+}
+
+// getCreditsErrors validates the attributions and checks that those with licenses that require attribution are rendered in the Credits section of the assembled description.
+func getCreditsErrors(attributions []Attribution, description string, credits SettingsCredits) []string {
+	errors := []string{}
+	section := ""
+	if start := strings.Index(description, creditsHeader); start >= 0 {
+		section = description[start+len(creditsHeader):]
+		if end := strings.Index(section, "▬▬▬▬▬▬"); end >= 0 {
+			section = section[:end]
+		}
+	}
+	for _, attribution := range attributions {
+		if err := validateAttribution(attribution, credits); err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		if slices.Contains(credits.AttributionRequired, attribution.License) && !strings.Contains(section, getCreditLine(attribution)) {
+			errors = append(errors, fmt.Sprintf("%s requires attribution but it's missing from the Credits section of the description", attribution.Asset))
+		}
+	}
+	return errors
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func getCreditsTestSettings() SettingsCredits {
+	return SettingsCredits{Licenses: []string{"CC0-1.0", "CC-BY-4.0", "MIT"}, AttributionRequired: []string{"CC-BY-4.0"}}
+}
+
+func TestCredits_getCreditsSection(t *testing.T) {
+	attributions := []Attribution{
+		{Asset: "Upbeat", Author: "Jane Doe", License: "CC-BY-4.0", SourceURL: "https://music.example.com/upbeat", UsedIn: "intro music"},
+		{Asset: "crossplane", License: "Apache-2.0"},
+	}
+	expected := creditsHeader + `
+➡ Upbeat by Jane Doe (CC-BY-4.0), used in intro music: https://music.example.com/upbeat
+➡ crossplane (Apache-2.0)
+
+`
+	if actual := getCreditsSection(attributions); actual != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, actual)
+	}
+	if actual := getCreditsSection(nil); actual != "" {
+		t.Errorf("Expected no section\nGot: %q", actual)
+	}
+}
+
+func TestCredits_exportCredits(t *testing.T) {
+	gist := filepath.Join(t.TempDir(), "video.md")
+	video := Video{Name: "video", Gist: gist, Attributions: []Attribution{{Asset: "Upbeat", Author: "Jane Doe", License: "CC-BY-4.0"}}}
+	path, err := exportCredits(video)
+	if err != nil {
+		t.Fatalf("Expected no error\nGot: %v", err)
+	}
+	if expected := filepath.Join(filepath.Dir(gist), "video.CREDITS.md"); path != expected {
+		t.Errorf("Expected: %s\nGot: %s", expected, path)
+	}
+	data, _ := os.ReadFile(path)
+	if expected := "# Credits: video\n\n- Upbeat by Jane Doe (CC-BY-4.0)\n"; string(data) != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, string(data))
+	}
+	if _, err := exportCredits(Video{}); err == nil {
+		t.Errorf("Expected an error for a video without a manuscript")
+	}
+}
+
+func TestCredits_validateAttribution(t *testing.T) {
+	tests := map[string]struct {
+		attribution Attribution
+		expected    string
+	}{
+		"valid": {
+			attribution: Attribution{Asset: "Upbeat", Author: "Jane Doe", License: "CC-BY-4.0", SourceURL: "https://music.example.com/upbeat"},
+		},
+		"no attribution required": {
+			attribution: Attribution{Asset: "Clip", License: "CC0-1.0"},
+		},
+		"no asset": {
+			attribution: Attribution{License: "MIT"},
+			expected:    "the asset name is required",
+		},
+		"license not allowed": {
+			attribution: Attribution{Asset: "Song", License: "All rights reserved"},
+			expected:    `license "All rights reserved" of Song is not one of CC0-1.0, CC-BY-4.0, MIT`,
+		},
+		"no author": {
+			attribution: Attribution{Asset: "Upbeat", License: "CC-BY-4.0"},
+			expected:    "CC-BY-4.0 requires attribution, so the author of Upbeat is required",
+		},
+		"invalid source URL": {
+			attribution: Attribution{Asset: "Clip", License: "CC0-1.0", SourceURL: "example.com/clip"},
+			expected:    `source URL "example.com/clip" of Clip must be an http or https URL`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateAttribution(tc.attribution, getCreditsTestSettings())
+			actual := ""
+			if err != nil {
+				actual = err.Error()
+			}
+			if actual != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCredits_getCreditsErrors(t *testing.T) {
+	required := Attribution{Asset: "Upbeat", Author: "Jane Doe", License: "CC-BY-4.0"}
+	optional := Attribution{Asset: "Clip", License: "CC0-1.0"}
+	attributions := []Attribution{required, optional}
+	credits := getCreditsTestSettings()
+
+	description := getUploadRequest(Video{Description: "Description", Attributions: attributions}, UploadStatus{}).Snippet.Description
+	if errors := getCreditsErrors(attributions, description, credits); len(errors) > 0 {
+		t.Errorf("Expected the assembled description to credit everything\nGot: %v", errors)
+	}
+
+	// A credit elsewhere in the description does not count.
+	description = "Upbeat by Jane Doe (CC-BY-4.0)\n" + getCreditsSection([]Attribution{optional}) + "▬▬▬▬▬▬ 👋 Contact me 👋 ▬▬▬▬▬▬"
+	expected := []string{"Upbeat requires attribution but it's missing from the Credits section of the description"}
+	if errors := getCreditsErrors(attributions, description, credits); !slices.Equal(errors, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, errors)
+	}
+
+	expected = []string{`license "GPL-3.0" of Lib is not one of CC0-1.0, CC-BY-4.0, MIT`}
+	if errors := getCreditsErrors([]Attribution{{Asset: "Lib", License: "GPL-3.0"}}, "", credits); !slices.Equal(errors, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, errors)
+	}
+}
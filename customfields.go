@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+const customFieldTypeString = "string"
+const customFieldTypeBool = "bool"
+const customFieldTypeDate = "date"
+const customFieldTypeSelect = "select"
+
+const customFieldPhaseInit = "init"
+const customFieldPhaseWork = "work"
+const customFieldPhaseDefine = "define"
+const customFieldPhaseEdit = "edit"
+const customFieldPhasePublish = "publish"
+
+// CustomField is a video field declared in settings (customFields) rather than in the Video struct.
+// Values are stored as strings in Video.CustomFields.
+type CustomField struct {
+	Key     string
+	Label   string
+	Type    string
+	Options []string
+	Phase   string
+}
+
+func getPhaseCustomFields(fields []CustomField, phase string) []CustomField {
+	phaseFields := []CustomField{}
+	for _, field := range fields {
+		if field.Phase == phase {
+			phaseFields = append(phaseFields, field)
+		}
+	}
+	return phaseFields
+}
+
+func getCustomField(fields []CustomField, key string) (CustomField, bool) {
+	for _, field := range fields {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return CustomField{}, false
+}
+
+func validateCustomFieldValue(field CustomField, value string) error {
+	if len(value) == 0 {
+		return nil
+	}
+	switch field.Type {
+	case customFieldTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be true or false", field.Label)
+		}
+	case customFieldTypeDate:
+		if _, err := parseDate(value); err != nil {
+			return err
+		}
+	case customFieldTypeSelect:
+		if !slices.Contains(field.Options, value) {
+			return fmt.Errorf("%s must be one of %v", field.Label, field.Options)
+		}
+	case customFieldTypeString, "":
+	default:
+		return fmt.Errorf("%s has an unknown type %s", field.Label, field.Type)
+	}
+	return nil
+}
+
+func isCustomFieldCompleted(field CustomField, value string) bool {
+	if len(value) == 0 || value == "-" || value == "N/A" {
+		return false
+	}
+	if field.Type == customFieldTypeBool {
+		completed, _ := strconv.ParseBool(value)
+		return completed
+	}
+	return validateCustomFieldValue(field, value) == nil
+}
+
+func getCustomFieldsCompletion(fields []CustomField, values map[string]string, phase string) (completed, total int) {
+	for _, field := range getPhaseCustomFields(fields, phase) {
+		total++
+		if isCustomFieldCompleted(field, values[field.Key]) {
+			completed++
+		}
+	}
+	return completed, total
+}
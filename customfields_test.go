@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var testCustomFields = []CustomField{
+	{Key: "hardware", Label: "Hardware used", Type: customFieldTypeString, Phase: customFieldPhaseInit},
+	{Key: "homelab", Label: "Homelab", Type: customFieldTypeBool, Phase: customFieldPhaseInit},
+	{Key: "cfpDate", Label: "CFP date", Type: customFieldTypeDate, Phase: customFieldPhaseInit},
+	{Key: "level", Label: "Level", Type: customFieldTypeSelect, Options: []string{"beginner", "advanced"}, Phase: customFieldPhaseInit},
+	{Key: "cfpLink", Label: "CFP link", Type: customFieldTypeString, Phase: customFieldPhasePublish},
+}
+
+func TestCustomFields_validateCustomFieldValue(t *testing.T) {
+	values := []struct {
+		field int
+		value string
+		valid bool
+	}{
+		{0, "Raspberry Pi", true},
+		{1, "true", true},
+		{1, "yes please", false},
+		{2, "2030-01-21", true},
+		{2, "2030-01-21T16:00", true},
+		{2, "tomorrow", false},
+		{3, "advanced", true},
+		{3, "expert", false},
+		{3, "", true},
+	}
+	for _, value := range values {
+		err := validateCustomFieldValue(testCustomFields[value.field], value.value)
+		if (err == nil) != value.valid {
+			t.Errorf("Expected %q to be valid=%t for %s, but got %v", value.value, value.valid, testCustomFields[value.field].Key, err)
+		}
+	}
+	if err := validateCustomFieldValue(CustomField{Label: "Broken", Type: "number"}, "1"); err == nil {
+		t.Errorf("Expected an error for an unknown type")
+	}
+}
+
+func TestCustomFields_getCustomFieldsCompletion(t *testing.T) {
+	values := map[string]string{
+		"hardware": "Raspberry Pi",
+		"homelab":  "false",
+		"cfpDate":  "not a date",
+		"level":    "advanced",
+		"cfpLink":  "https://example.com",
+	}
+	completed, total := getCustomFieldsCompletion(testCustomFields, values, customFieldPhaseInit)
+	if completed != 2 || total != 4 {
+		t.Errorf("Expected 2/4, but got %d/%d", completed, total)
+	}
+	completed, total = getCustomFieldsCompletion(testCustomFields, values, customFieldPhaseEdit)
+	if completed != 0 || total != 0 {
+		t.Errorf("Expected 0/0, but got %d/%d", completed, total)
+	}
+}
+
+func TestCustomFields_unknownKeysArePreserved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	if err := os.WriteFile(path, []byte("name: something\ncustomfields:\n  removedfield: keep me\n  hardware: Raspberry Pi\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	yaml := YAML{}
+	video := yaml.GetVideo(path)
+	video.CustomFields["hardware"] = "NUC"
+	yaml.WriteVideo(&video, path)
+	expected := map[string]string{"removedfield": "keep me", "hardware": "NUC"}
+	if actual := yaml.GetVideo(path).CustomFields; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateFormat = "2006-01-02T15:04"
+const dayFormat = "2006-01-02"
+
+// parseDate accepts both the full date format used for publish dates (2030-01-21T16:00) and plain days (2030-01-21).
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.Parse(dateFormat, value); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(dayFormat, value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("date %q must be in the %s or %s format", value, dateFormat, dayFormat)
+}
+
+const dateBandNone = 0
+const dateBandImminent = 1
+const dateBandFarFuture = 2
+
+// DateHorizon is a period relative to now. Months are kept apart from days since they differ in length.
+type DateHorizon struct {
+	Months int
+	Days   int
+}
+
+var dateHorizonPattern = regexp.MustCompile(`^(\d+)(d|w|mo)$`)
+
+// parseDateHorizon accepts a positive number followed by d (days), w (weeks), or mo (months) (e.g., 7d, 6w, or 3mo).
+func parseDateHorizon(value string) (DateHorizon, error) {
+	matches := dateHorizonPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(value)))
+	if matches == nil {
+		return DateHorizon{}, fmt.Errorf("%q must be a number followed by d, w, or mo (e.g., 7d, 6w, or 3mo)", value)
+	}
+	amount, err := strconv.Atoi(matches[1])
+	if err != nil || amount == 0 {
+		return DateHorizon{}, fmt.Errorf("%q must be greater than zero", value)
+	}
+	switch matches[2] {
+	case "w":
+		return DateHorizon{Days: amount * 7}, nil
+	case "mo":
+		return DateHorizon{Months: amount}, nil
+	}
+	return DateHorizon{Days: amount}, nil
+}
+
+func (h DateHorizon) IsZero() bool {
+	return h.Months == 0 && h.Days == 0
+}
+
+func (h DateHorizon) From(now time.Time) time.Time {
+	return now.AddDate(0, h.Months, h.Days)
+}
+
+// getDateBand tells whether the date is imminent (between now and the imminent horizon, inclusive) or far in the future (after the far future horizon).
+// Past dates, dates that cannot be parsed, and horizons that are not set belong to no band.
+func getDateBand(value string, now time.Time, imminent, farFuture DateHorizon) int {
+	date, err := time.ParseInLocation(dateFormat, value, now.Location())
+	if err != nil || date.Before(now) {
+		return dateBandNone
+	}
+	if !imminent.IsZero() && !date.After(imminent.From(now)) {
+		return dateBandImminent
+	}
+	if !farFuture.IsZero() && date.After(farFuture.From(now)) {
+		return dateBandFarFuture
+	}
+	return dateBandNone
+}
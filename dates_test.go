@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDates_parseDateHorizon(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected DateHorizon
+		valid    bool
+	}{
+		{"7d", DateHorizon{Days: 7}, true},
+		{"6w", DateHorizon{Days: 42}, true},
+		{"3mo", DateHorizon{Months: 3}, true},
+		{" 3MO ", DateHorizon{Months: 3}, true},
+		{"", DateHorizon{}, false},
+		{"0d", DateHorizon{}, false},
+		{"-7d", DateHorizon{}, false},
+		{"3m", DateHorizon{}, false},
+		{"1.5w", DateHorizon{}, false},
+		{"soon", DateHorizon{}, false},
+		{"99999999999999999999d", DateHorizon{}, false},
+	}
+	for _, test := range tests {
+		actual, err := parseDateHorizon(test.value)
+		if (err == nil) != test.valid {
+			t.Errorf("%q: Expected valid=%t, but got %v", test.value, test.valid, err)
+		}
+		if actual != test.expected {
+			t.Errorf("%q: Expected: %+v\nGot: %+v", test.value, test.expected, actual)
+		}
+	}
+}
+
+func TestDates_getDateBand(t *testing.T) {
+	now := time.Date(2030, 1, 15, 16, 0, 0, 0, time.UTC)
+	imminent, farFuture := DateHorizon{Days: 7}, DateHorizon{Days: 42}
+	tests := []struct {
+		name      string
+		date      string
+		imminent  DateHorizon
+		farFuture DateHorizon
+		expected  int
+	}{
+		{"past", "2030-01-15T15:59", imminent, farFuture, dateBandNone},
+		{"now", "2030-01-15T16:00", imminent, farFuture, dateBandImminent},
+		{"exactly at the imminent threshold", "2030-01-22T16:00", imminent, farFuture, dateBandImminent},
+		{"right after the imminent threshold", "2030-01-22T16:01", imminent, farFuture, dateBandNone},
+		{"exactly at the far future threshold", "2030-02-26T16:00", imminent, farFuture, dateBandNone},
+		{"right after the far future threshold", "2030-02-26T16:01", imminent, farFuture, dateBandFarFuture},
+		{"months", "2030-04-15T16:01", imminent, DateHorizon{Months: 3}, dateBandFarFuture},
+		{"months boundary", "2030-04-15T16:00", imminent, DateHorizon{Months: 3}, dateBandNone},
+		{"imminent disabled", "2030-01-16T16:00", DateHorizon{}, farFuture, dateBandNone},
+		{"far future disabled", "2031-01-15T16:00", imminent, DateHorizon{}, dateBandNone},
+		{"invalid", "next week", imminent, farFuture, dateBandNone},
+	}
+	for _, test := range tests {
+		if actual := getDateBand(test.date, now, test.imminent, test.farFuture); actual != test.expected {
+			t.Errorf("%s: Expected: %d\nGot: %d", test.name, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type DemoEnvironment = workflow.DemoEnvironment
+
+const demoMarker = "$"
+
+var demoClient = &http.Client{Timeout: ruleWebhookTimeout}
+
+// DemoReminder is the body of the reminder webhook.
+type DemoReminder struct {
+	Video       string          `json:"video"`
+	Category    string          `json:"category"`
+	Days        int             `json:"days"`
+	Environment DemoEnvironment `json:"environment"`
+}
+
+// getDemoAge returns how many days the environment has been running. Environments with an unknown creation date are never reported.
+func getDemoAge(environment DemoEnvironment, now time.Time) (int, bool) {
+	created, err := time.ParseInLocation(dayFormat, environment.Created, now.Location())
+	if err != nil {
+		return 0, false
+	}
+	return int(now.Sub(created).Hours() / 24), true
+}
+
+func isDemoStale(environment DemoEnvironment, now time.Time, reminderDays int) bool {
+	if environment.Destroyed {
+		return false
+	}
+	age, ok := getDemoAge(environment, now)
+	return ok && age >= reminderDays
+}
+
+func getUndestroyedDemos(video Video) []DemoEnvironment {
+	undestroyed := []DemoEnvironment{}
+	for _, environment := range video.DemoEnvironments {
+		if !environment.Destroyed {
+			undestroyed = append(undestroyed, environment)
+		}
+	}
+	return undestroyed
+}
+
+func hasStaleDemos(video Video, now time.Time, reminderDays int) bool {
+	for _, environment := range video.DemoEnvironments {
+		if isDemoStale(environment, now, reminderDays) {
+			return true
+		}
+	}
+	return false
+}
+
+func getDemoTitle(environment DemoEnvironment) string {
+	title := environment.Provider
+	if len(environment.Description) > 0 {
+		title = fmt.Sprintf("%s - %s", title, environment.Description)
+	}
+	if environment.MonthlyCost > 0 {
+		title = fmt.Sprintf("%s, $%.2f/month", title, environment.MonthlyCost)
+	}
+	if environment.Destroyed {
+		return fmt.Sprintf("%s (destroyed on %s)", title, environment.DestroyedDate)
+	}
+	return fmt.Sprintf("%s (running since %s)", title, environment.Created)
+}
+
+// getStaleDemosWarning lists the videos with environments that should have been destroyed already.
+func getStaleDemosWarning(videos []Video, now time.Time, reminderDays int) string {
+	lines := []string{}
+	for _, video := range videos {
+		for _, environment := range video.DemoEnvironments {
+			if isDemoStale(environment, now, reminderDays) {
+				lines = append(lines, fmt.Sprintf("%s: %s", video.Name, getDemoTitle(environment)))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d demo environments have been running for %d days or more:\n%s", len(lines), reminderDays, strings.Join(lines, "\n"))
+}
+
+// requiresDemoAcknowledgment returns the environments that are still running when the video becomes published.
+func requiresDemoAcknowledgment(before, after Video) []DemoEnvironment {
+	if workflow.GetPhase(before) == workflow.PhasePublished || workflow.GetPhase(after) != workflow.PhasePublished {
+		return nil
+	}
+	return getUndestroyedDemos(after)
+}
+
+// notifyStaleDemos calls the webhook once for each stale environment and marks it as reminded. It returns the videos that changed and have to be written.
+func notifyStaleDemos(client *http.Client, webhook string, videos []Video, now time.Time, reminderDays int) ([]Video, error) {
+	changed := []Video{}
+	for _, video := range videos {
+		// The environments are copied so that only the returned videos are marked as reminded.
+		video.DemoEnvironments = slices.Clone(video.DemoEnvironments)
+		reminded := false
+		for i, environment := range video.DemoEnvironments {
+			if !isDemoStale(environment, now, reminderDays) || len(environment.Reminded) > 0 {
+				continue
+			}
+			age, _ := getDemoAge(environment, now)
+			if err := postDemoReminder(client, webhook, DemoReminder{Video: video.Name, Category: video.Category, Days: age, Environment: environment}); err != nil {
+				if reminded {
+					changed = append(changed, video)
+				}
+				return changed, err
+			}
+			video.DemoEnvironments[i].Reminded = now.Format(dayFormat)
+			reminded = true
+		}
+		if reminded {
+			changed = append(changed, video)
+		}
+	}
+	return changed, nil
+}
+
+func postDemoReminder(client *http.Client, webhook string, reminder DemoReminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ruleWebhookTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("demo reminder webhook responded with %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getDemoTestVideos() []Video {
+	return []Video{
+		{Name: "stale", Category: "ai", DemoEnvironments: []DemoEnvironment{
+			{Provider: "AWS", Description: "EKS cluster", Created: "2030-01-10", MonthlyCost: 150},
+			{Provider: "Azure", Created: "2030-01-01", Destroyed: true, DestroyedDate: "2030-01-05"},
+		}},
+		{Name: "fresh", Category: "ai", DemoEnvironments: []DemoEnvironment{{Provider: "Google Cloud", Created: "2030-01-18"}}},
+		{Name: "unknown", Category: "ai", DemoEnvironments: []DemoEnvironment{{Provider: "Civo"}}},
+		{Name: "none", Category: "ai"},
+	}
+}
+
+func TestDemo_isDemoStale(t *testing.T) {
+	now := time.Date(2030, 1, 21, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		environment DemoEnvironment
+		expected    bool
+	}{
+		{DemoEnvironment{Created: "2030-01-14"}, true},
+		{DemoEnvironment{Created: "2030-01-15"}, false},
+		{DemoEnvironment{Created: "2030-01-01", Destroyed: true}, false},
+		{DemoEnvironment{Created: "FIXME"}, false},
+		{DemoEnvironment{}, false},
+	}
+	for _, test := range tests {
+		if actual := isDemoStale(test.environment, now, 7); actual != test.expected {
+			t.Errorf("%+v\nExpected: %t\nGot: %t", test.environment, test.expected, actual)
+		}
+	}
+}
+
+func TestDemo_getStaleDemosWarning(t *testing.T) {
+	now := time.Date(2030, 1, 21, 9, 0, 0, 0, time.UTC)
+	expected := "1 demo environments have been running for 7 days or more:\nstale: AWS - EKS cluster, $150.00/month (running since 2030-01-10)"
+	if actual := getStaleDemosWarning(getDemoTestVideos(), now, 7); actual != expected {
+		t.Errorf("Expected: %s\nGot: %s", expected, actual)
+	}
+	if actual := getStaleDemosWarning(getDemoTestVideos(), now, 30); len(actual) > 0 {
+		t.Errorf("Expected no warning with a longer threshold, but got %s", actual)
+	}
+}
+
+func TestDemo_getVideoOptionTitleMarker(t *testing.T) {
+	now := time.Date(2030, 1, 21, 9, 0, 0, 0, time.UTC)
+	original := settings.Demo.ReminderDays
+	defer func() { settings.Demo.ReminderDays = original }()
+	settings.Demo.ReminderDays = 7
+	videos := getDemoTestVideos()
+	if title := getVideoOptionTitle(videos[0], now); !strings.HasPrefix(title, demoMarker+" stale") {
+		t.Errorf("Expected the %s marker, but got %s", demoMarker, title)
+	}
+	for _, video := range videos[1:] {
+		if title := getVideoOptionTitle(video, now); strings.Contains(title, demoMarker) {
+			t.Errorf("Expected no marker for %s, but got %s", video.Name, title)
+		}
+	}
+}
+
+func TestDemo_requiresDemoAcknowledgment(t *testing.T) {
+	before := getDemoTestVideos()[0]
+	after := before
+	after.Repo = "https://github.com/vfarcic/demo"
+	if running := requiresDemoAcknowledgment(before, after); len(running) != 1 || running[0].Provider != "AWS" {
+		t.Errorf("Expected the running environment to require an acknowledgment, but got %v", running)
+	}
+	if running := requiresDemoAcknowledgment(after, after); len(running) > 0 {
+		t.Errorf("Expected no acknowledgment for a video that was already published, but got %v", running)
+	}
+	if running := requiresDemoAcknowledgment(before, before); len(running) > 0 {
+		t.Errorf("Expected no acknowledgment for a video that is not being published, but got %v", running)
+	}
+	destroyed := getDemoTestVideos()[0]
+	destroyed.DemoEnvironments = destroyed.DemoEnvironments[1:]
+	published := destroyed
+	published.Repo = "N/A"
+	if running := requiresDemoAcknowledgment(destroyed, published); len(running) > 0 {
+		t.Errorf("Expected no acknowledgment when all environments are destroyed, but got %v", running)
+	}
+}
+
+func TestDemo_notifyStaleDemos(t *testing.T) {
+	reminders := []DemoReminder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		reminder := DemoReminder{}
+		json.Unmarshal(data, &reminder)
+		reminders = append(reminders, reminder)
+	}))
+	defer server.Close()
+	now := time.Date(2030, 1, 21, 9, 0, 0, 0, time.UTC)
+	videos := getDemoTestVideos()
+	changed, err := notifyStaleDemos(server.Client(), server.URL, videos, now, 7)
+	if err != nil {
+		t.Fatalf("Expected the reminders to be sent, but got %v", err)
+	}
+	for _, video := range videos {
+		for _, environment := range video.DemoEnvironments {
+			if len(environment.Reminded) > 0 {
+				t.Errorf("Expected: the videos passed in to stay as they were\nGot: %+v", video)
+			}
+		}
+	}
+	if len(reminders) != 1 || reminders[0].Video != "stale" || reminders[0].Days != 11 || reminders[0].Environment.Provider != "AWS" {
+		t.Errorf("Unexpected reminders %+v", reminders)
+	}
+	if len(changed) != 1 || changed[0].DemoEnvironments[0].Reminded != "2030-01-21" {
+		t.Fatalf("Expected the reminded environment to be recorded, but got %+v", changed)
+	}
+	if _, err := notifyStaleDemos(server.Client(), server.URL, changed, now.AddDate(0, 0, 1), 7); err != nil || len(reminders) != 1 {
+		t.Errorf("Expected each environment to be reminded only once, but got %d reminders and %v", len(reminders), err)
+	}
+}
+
+func TestDemo_notifyStaleDemosFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	now := time.Date(2030, 1, 21, 9, 0, 0, 0, time.UTC)
+	changed, err := notifyStaleDemos(server.Client(), server.URL, getDemoTestVideos(), now, 7)
+	if err == nil || len(changed) > 0 {
+		t.Errorf("Expected an error and nothing marked as reminded, but got %v and %+v", err, changed)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+const descriptionMaxLength = 5000
+const descriptionLengthWarning = 4500
+
+const findingError = "error"
+const findingWarning = "warning"
+
+// DescriptionFinding points to a problem in the assembled description. Offset is in characters (not bytes).
+type DescriptionFinding struct {
+	Rule     string
+	Severity string
+	Offset   int
+	Message  string
+}
+
+func (f DescriptionFinding) String() string {
+	return fmt.Sprintf("%s at %d: %s", f.Severity, f.Offset, f.Message)
+}
+
+// YouTube turns everything up to the next whitespace into a link.
+var descriptionURLRegex = regexp.MustCompile(`https?://\S+`)
+var descriptionMarkdownLinkRegex = regexp.MustCompile(`\[[^\]\n]+\]\(\s*https?://[^)\s]+\s*\)`)
+
+const descriptionTrailingPunctuation = `.,;:!?)]}'"`
+
+// lintDescription checks the description as it will be uploaded. ctaURL is the link that must be above the fold (empty when there is none).
+func lintDescription(description, ctaURL string, ctaMaxOffset int) []DescriptionFinding {
+	findings := []DescriptionFinding{}
+	if len(ctaURL) > 0 {
+		index := strings.Index(description, ctaURL)
+		switch {
+		case index < 0:
+			findings = append(findings, DescriptionFinding{Rule: "cta", Severity: findingError, Message: fmt.Sprintf("%s is not in the description", ctaURL)})
+		case utf8.RuneCountInString(description[:index]) > ctaMaxOffset:
+			findings = append(findings, DescriptionFinding{Rule: "cta", Severity: findingWarning, Offset: utf8.RuneCountInString(description[:index]), Message: fmt.Sprintf("%s is not within the first %d characters and will be hidden until the description is expanded", ctaURL, ctaMaxOffset)})
+		}
+	}
+	markdownLinks := descriptionMarkdownLinkRegex.FindAllStringIndex(description, -1)
+	for _, match := range markdownLinks {
+		findings = append(findings, DescriptionFinding{Rule: "markdown", Severity: findingWarning, Offset: utf8.RuneCountInString(description[:match[0]]), Message: fmt.Sprintf("%s is a Markdown link which YouTube shows literally", description[match[0]:match[1]])})
+	}
+	seen := map[string]bool{}
+	for _, match := range descriptionURLRegex.FindAllStringIndex(description, -1) {
+		link := description[match[0]:match[1]]
+		trimmed := strings.TrimRight(link, descriptionTrailingPunctuation)
+		insideMarkdown := false
+		for _, markdown := range markdownLinks {
+			if match[0] >= markdown[0] && match[0] < markdown[1] {
+				insideMarkdown = true
+			}
+		}
+		if len(trimmed) < len(link) && !insideMarkdown {
+			offset := utf8.RuneCountInString(description[:match[0]+len(trimmed)])
+			findings = append(findings, DescriptionFinding{Rule: "punctuation", Severity: findingWarning, Offset: offset, Message: fmt.Sprintf("%q would become part of the link %s", link[len(trimmed):], trimmed)})
+		}
+		if seen[trimmed] {
+			findings = append(findings, DescriptionFinding{Rule: "duplicate", Severity: findingWarning, Offset: utf8.RuneCountInString(description[:match[0]]), Message: fmt.Sprintf("%s is in the description more than once", trimmed)})
+		}
+		seen[trimmed] = true
+	}
+	length := utf8.RuneCountInString(description)
+	switch {
+	case length > descriptionMaxLength:
+		findings = append(findings, DescriptionFinding{Rule: "length", Severity: findingError, Offset: descriptionMaxLength, Message: fmt.Sprintf("the description has %d characters, YouTube accepts up to %d", length, descriptionMaxLength)})
+	case length > descriptionLengthWarning:
+		findings = append(findings, DescriptionFinding{Rule: "length", Severity: findingWarning, Offset: descriptionLengthWarning, Message: fmt.Sprintf("the description has %d characters and is close to the limit of %d", length, descriptionMaxLength)})
+	}
+	return findings
+}
+
+// lintVideoDescription lints the assembled description. The project link of sponsored videos is the call to action.
+func lintVideoDescription(video Video) []DescriptionFinding {
+	ctaURL := ""
+	if workflow.IsSponsored(video.Sponsorship) && len(video.ProjectURL) > 0 && video.ProjectURL != "N/A" && video.ProjectURL != "-" {
+		ctaURL = getProjectURL(video)
+	}
+	description := getUploadRequest(video, UploadStatus{}).Snippet.Description
+	return lintDescription(description, ctaURL, settings.Description.CTAMaxOffset)
+}
+
+func getDescriptionFindingsText(findings []DescriptionFinding) string {
+	if len(findings) == 0 {
+		return "The description has no issues."
+	}
+	lines := []string{}
+	for _, finding := range findings {
+		line := finding.String()
+		if finding.Severity == findingError {
+			line = redStyle.Render(line)
+		} else {
+			line = orangeStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
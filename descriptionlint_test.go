@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDescriptionLint_lintDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		ctaURL      string
+		expected    []DescriptionFinding
+	}{
+		{
+			name:        "clean",
+			description: "Try it at https://example.com now.\nMore at https://devopstoolkit.live",
+			ctaURL:      "https://example.com",
+			expected:    []DescriptionFinding{},
+		},
+		{
+			name:        "cta below the fold",
+			description: strings.Repeat("a", 160) + " https://example.com",
+			ctaURL:      "https://example.com",
+			expected:    []DescriptionFinding{{Rule: "cta", Severity: findingWarning, Offset: 161}},
+		},
+		{
+			name:        "cta missing",
+			description: "Nothing to see here.",
+			ctaURL:      "https://example.com",
+			expected:    []DescriptionFinding{{Rule: "cta", Severity: findingError}},
+		},
+		{
+			name:        "trailing punctuation",
+			description: "▬ Visit (https://example.com). Or https://example.org, or https://example.net/path?a=b",
+			expected:    []DescriptionFinding{{Rule: "punctuation", Severity: findingWarning, Offset: 28}, {Rule: "punctuation", Severity: findingWarning, Offset: 53}},
+		},
+		{
+			name:        "markdown link",
+			description: "See [the docs](https://example.com/docs) for details",
+			expected:    []DescriptionFinding{{Rule: "markdown", Severity: findingWarning, Offset: 4}},
+		},
+		{
+			name:        "duplicate link",
+			description: "https://example.com\nhttps://example.org\nhttps://example.com.",
+			expected:    []DescriptionFinding{{Rule: "punctuation", Severity: findingWarning, Offset: 59}, {Rule: "duplicate", Severity: findingWarning, Offset: 40}},
+		},
+		{
+			name:        "close to the limit",
+			description: strings.Repeat("a", 4600),
+			expected:    []DescriptionFinding{{Rule: "length", Severity: findingWarning, Offset: descriptionLengthWarning}},
+		},
+		{
+			name:        "over the limit",
+			description: strings.Repeat("ä", 5001),
+			expected:    []DescriptionFinding{{Rule: "length", Severity: findingError, Offset: descriptionMaxLength}},
+		},
+	}
+	for _, test := range tests {
+		actual := lintDescription(test.description, test.ctaURL, 150)
+		if len(actual) != len(test.expected) {
+			t.Errorf("%s: expected %d findings, but got %v", test.name, len(test.expected), actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i].Rule != test.expected[i].Rule || actual[i].Severity != test.expected[i].Severity || actual[i].Offset != test.expected[i].Offset {
+				t.Errorf("%s\nExpected: %+v\nGot: %+v", test.name, test.expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func TestDescriptionLint_lintVideoDescription(t *testing.T) {
+	settings.Description.CTAMaxOffset = 150
+	video := Video{Name: "my-video", Title: "Something", Description: "About something."}
+	if findings := lintVideoDescription(video); len(findings) != 0 {
+		t.Errorf("Expected the standard description to have no findings, but got %v", findings)
+	}
+	video.Description = "See [Crossplane](https://crossplane.io)."
+	findings := lintVideoDescription(video)
+	if len(findings) != 1 || findings[0].Rule != "markdown" {
+		t.Errorf("Expected a Markdown finding, but got %v", findings)
+	}
+	video = Video{Name: "my-video", Title: "Something", Description: strings.Repeat("About something. ", 10), ProjectName: "Crossplane", ProjectURL: "https://crossplane.io", Sponsorship: Sponsorship{Amount: "1000"}}
+	findings = lintVideoDescription(video)
+	if len(findings) != 1 || findings[0].Rule != "cta" || findings[0].Severity != findingWarning {
+		t.Errorf("Expected the sponsored link to be reported as below the fold, but got %v", findings)
+	}
+	video.Description = fmt.Sprintf("Try Crossplane at %s\n%s", getProjectURL(video), video.Description)
+	findings = lintVideoDescription(video)
+	if len(findings) != 1 || findings[0].Rule != "duplicate" {
+		t.Errorf("Expected only the repeated link in the additional info to be reported, but got %v", findings)
+	}
+}
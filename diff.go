@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+
+	"devopstoolkitseries/youtube-automation/pkg/utils"
+)
+
+type DiffRun = utils.DiffRun
+
+// renderWordDiff shows additions in green and removals in red.
+func renderWordDiff(runs []DiffRun) string {
+	var builder strings.Builder
+	for _, run := range runs {
+		switch run.Op {
+		case utils.DiffInsert:
+			builder.WriteString(greenStyle.Render(run.Text))
+		case utils.DiffDelete:
+			builder.WriteString(redStyle.Strikethrough(true).Render(run.Text))
+		default:
+			builder.WriteString(run.Text)
+		}
+	}
+	return builder.String()
+}
@@ -1,40 +1,196 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	gomail "gopkg.in/mail.v2"
 )
 
+const emailTimeout = 30 * time.Second
+
+var ErrEmailAddress = errors.New("invalid email address")
+var ErrEmailAuth = errors.New("email server rejected the credentials")
+var ErrEmailRecipient = errors.New("email server rejected the recipient")
+var ErrEmailConnection = errors.New("could not communicate with the email server")
+
 type Email struct {
 	password string
+	replyTo  string
+	host     string
+	port     int
+	logPath  string
+}
+
+type EmailLogEntry struct {
+	Time      time.Time
+	From      string
+	To        []string
+	Subject   string
+	MessageID string
+	Error     string `json:",omitempty"`
 }
 
 func NewEmail(password string) *Email {
 	return &Email{
 		password: password,
+		replyTo:  settings.Email.ReplyTo,
+		host:     "smtp.gmail.com",
+		port:     587,
+		logPath:  "email.log",
 	}
 }
 
-func (e *Email) Send(from string, to []string, subject, body string, attachmentPath string) error {
-	to = append(to, from)
+func newEmailContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), emailTimeout)
+}
+
+func (e *Email) Send(ctx context.Context, from string, to []string, subject, body string, attachmentPath string) error {
+	// The recipients are copied so that trimming them does not change the caller's slice.
+	to = append(slices.Clone(to), from)
+	for i := range to {
+		to[i] = strings.TrimSpace(to[i])
+		if _, err := mail.ParseAddress(to[i]); err != nil {
+			return fmt.Errorf("%w: %q", ErrEmailAddress, to[i])
+		}
+	}
+	messageID := getMessageID(from)
 	msg := gomail.NewMessage()
 	msg.SetHeader("From", from)
 	msg.SetHeader("To", to...)
 	msg.SetHeader("Subject", subject)
+	msg.SetHeader("Message-ID", messageID)
+	if len(e.replyTo) > 0 {
+		msg.SetHeader("Reply-To", e.replyTo)
+	}
 	msg.SetBody("text/html", body)
 	if attachmentPath != "" {
 		msg.Attach(attachmentPath)
 	}
-	dialer := gomail.NewDialer("smtp.gmail.com", 587, from, e.password)
-	if err := dialer.DialAndSend(msg); err != nil {
+	dialer := gomail.NewDialer(e.host, e.port, from, e.password)
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Timeout = time.Until(deadline)
+	}
+	dialer.RetryFailure = false
+	result := make(chan error, 1)
+	go func() {
+		result <- dialer.DialAndSend(msg)
+	}()
+	var err error
+	select {
+	case err = <-result:
+		err = classifyEmailError(err)
+	case <-ctx.Done():
+		err = fmt.Errorf("%w: %s", ErrEmailConnection, ctx.Err())
+	}
+	e.log(EmailLogEntry{Time: time.Now(), From: from, To: to, Subject: subject, MessageID: messageID}, err)
+	return err
+}
+
+func (e *Email) SendTest(ctx context.Context, from string) error {
+	body := fmt.Sprintf("This is a test email sent at %s to verify the email configuration.", time.Now().Format(time.RFC1123))
+	return e.Send(ctx, from, []string{}, "Test email", body, "")
+}
+
+// Check connects and authenticates to the email server without sending anything.
+func (e *Email) Check(ctx context.Context, from string) error {
+	dialer := gomail.NewDialer(e.host, e.port, from, e.password)
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Timeout = time.Until(deadline)
+	}
+	result := make(chan error, 1)
+	go func() {
+		closer, err := dialer.Dial()
+		if err == nil {
+			err = closer.Close()
+		}
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		return classifyEmailError(err)
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %s", ErrEmailConnection, ctx.Err())
+	}
+}
+
+// log appends the delivery to the email log. It's best effort and never fails the send.
+func (e *Email) log(entry EmailLogEntry, err error) {
+	if len(e.logPath) == 0 {
+		return
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	data, jsonErr := json.Marshal(entry)
+	if jsonErr != nil {
+		return
+	}
+	file, fileErr := os.OpenFile(e.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if fileErr != nil {
+		return
+	}
+	defer file.Close()
+	file.Write(append(data, '\n'))
+}
+
+func classifyEmailError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var sendErr *gomail.SendError
+	if errors.As(err, &sendErr) {
+		err = sendErr.Cause
+	}
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		switch {
+		case protoErr.Code == 530 || protoErr.Code == 534 || protoErr.Code == 535:
+			return fmt.Errorf("%w: %s", ErrEmailAuth, err)
+		case protoErr.Code >= 550 && protoErr.Code <= 553:
+			return fmt.Errorf("%w: %s", ErrEmailRecipient, err)
+		}
+		return err
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %s", ErrEmailConnection, err)
+	}
+	return err
+}
+
+func getMessageID(from string) string {
+	domain := "localhost"
+	if index := strings.LastIndex(from, "@"); index >= 0 {
+		domain = strings.Trim(from[index+1:], "> ")
+	}
+	random := make([]byte, 8)
+	rand.Read(random)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
+
+func (e *Email) SendThumbnail(ctx context.Context, from, to string, video Video) error {
+	subject := fmt.Sprintf("Thumbnail: %s", video.ProjectName)
+	err := e.Send(ctx, from, []string{to}, subject, getThumbnailEmailBody(video), "")
+	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (e *Email) SendThumbnail(from, to string, video Video) error {
+func getThumbnailEmailBody(video Video) string {
 	logos := ""
 	if video.ProjectURL != "" && video.ProjectURL != "-" && video.ProjectURL != "N/A" {
 		logos = video.ProjectURL
@@ -48,38 +204,46 @@ func (e *Email) SendThumbnail(from, to string, video Video) error {
 	if len(logos) > 0 {
 		logos = fmt.Sprintf("<li>Logo: %s</li>", logos)
 	}
-	subject := fmt.Sprintf("Thumbnail: %s", video.ProjectName)
 	taglineIdeas := ""
 	if len(video.TaglineIdeas) > 0 && video.TaglineIdeas != "N/A" && video.TaglineIdeas != "-" {
 		taglineIdeas = fmt.Sprintf("Ideas:<br/>%s", video.TaglineIdeas)
 	}
-	body := fmt.Sprintf(`<strong>Material:</strong>
+	thumbnailText := ""
+	if len(video.ThumbnailText) > 0 {
+		thumbnailText = fmt.Sprintf("<li>Thumbnail text: %s</li>\n", video.ThumbnailText)
+	}
+	return fmt.Sprintf(`<strong>Material:</strong>
 <br/><br/>
 All the material is available at %s.
 <br/><br/>
 <strong>Thumbnail:</strong>
 <br/><br/>
+Title: %s
+<br/><br/>
 Elements:
 <ul>
 %s
-<li>Text: %s</li>
-<li>Screenshots: screenshot-*.png</li>
+%s<li>Text: %s</li>
+%s<li>Screenshots: screenshot-*.png</li>
 <li>If possible, make 3 versions of the thumbnail (e.g., different color, with or without me, anything else you can think of). There's no need to make anything time-demanding. Simple variations should do. The goal is to use YouTube AB testing feature to see which thumbnail works the best.</li>
 </ul>
 %s
-`, video.Location, logos, video.Tagline, taglineIdeas)
-	err := e.Send(from, []string{to}, subject, body, "")
-	if err != nil {
-		return err
-	}
-	return nil
+`, video.Location, video.Title, logos, getSponsorAssetsEmailItem(video), video.Tagline, thumbnailText, taglineIdeas)
 }
 
-func (e *Email) SendEdit(from, to string, video Video) error {
+func (e *Email) SendEdit(ctx context.Context, from, to string, video Video) error {
 	if len(video.Gist) == 0 {
 		return fmt.Errorf("Gist is empty")
 	}
 	subject := fmt.Sprintf("Video: %s", video.ProjectName)
+	err := e.Send(ctx, from, []string{to}, subject, getEditEmailBody(video), video.Gist)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func getEditEmailBody(video Video) string {
 	animations := strings.Split(video.Animations, "\n")
 	animationsString := ""
 	for i := range animations {
@@ -104,7 +268,7 @@ func (e *Email) SendEdit(from, to string, video Video) error {
 		video.ProjectName,
 		video.Title,
 		animationsString,
-		video.Members,
+		formatMembers(getRenderedMembers(video.Members, settings.Members.Exclude)),
 	)
 	body := fmt.Sprintf(`<strong>Material:</strong>
 <br/><br/>
@@ -112,18 +276,13 @@ All the material is available at %s.
 <br/><br/>
 <strong>Animations:</strong>
 <ul>
-%s
+%s%s
 </ul>
-`, video.Location, animationsString)
-	body = strings.ReplaceAll(body, "\n<li></li>", "")
-	err := e.Send(from, []string{to}, subject, body, video.Gist)
-	if err != nil {
-		return err
-	}
-	return nil
+`, video.Location, getSponsorAssetsEmailItem(video), animationsString)
+	return strings.ReplaceAll(body, "\n<li></li>", "")
 }
 
-func (e *Email) SendSponsors(from, to string, videoID, sponsorshipPrice string) error {
+func (e *Email) SendSponsors(ctx context.Context, from, to string, videoID, sponsorshipPrice string) error {
 	subject := "DevOps Toolkit Video Sponsorship"
 	body := fmt.Sprintf(`Hi,
 <br><br>
@@ -133,7 +292,7 @@ I'll send the invoice for %s in a separate message.
 `, videoID, sponsorshipPrice)
 	toArray := strings.Split(to, ",")
 	toArray = append(toArray, settings.Email.FinanceTo)
-	err := e.Send(from, toArray, subject, body, "")
+	err := e.Send(ctx, from, toArray, subject, body, "")
 	if err != nil {
 		return err
 	}
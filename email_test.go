@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startFakeSMTPServer accepts a single connection and speaks just enough SMTP for gomail.
+// Recipients listed in reject are refused with 550. When silent is true, the server never greets the client.
+func startFakeSMTPServer(t *testing.T, reject []string, silent bool) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error occurred while starting the fake SMTP server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if silent {
+			time.Sleep(2 * time.Second)
+			return
+		}
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("220 localhost ESMTP\r\n"))
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			if inData {
+				if line == "." {
+					inData = false
+					conn.Write([]byte("250 OK\r\n"))
+				}
+				continue
+			}
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				conn.Write([]byte("250-localhost\r\n250 8BITMIME\r\n"))
+			case strings.HasPrefix(command, "RCPT TO:"):
+				rejected := false
+				for _, address := range reject {
+					if strings.Contains(line, address) {
+						rejected = true
+					}
+				}
+				if rejected {
+					conn.Write([]byte("550 5.1.1 No such user\r\n"))
+				} else {
+					conn.Write([]byte("250 OK\r\n"))
+				}
+			case command == "DATA":
+				inData = true
+				conn.Write([]byte("354 Go ahead\r\n"))
+			case command == "QUIT":
+				conn.Write([]byte("221 Bye\r\n"))
+				return
+			default:
+				conn.Write([]byte("250 OK\r\n"))
+			}
+		}
+	}()
+	address := listener.Addr().(*net.TCPAddr)
+	return address.IP.String(), address.Port
+}
+
+func getTestEmail(t *testing.T, host string, port int) *Email {
+	return &Email{host: host, port: port, replyTo: "reply@example.com", logPath: filepath.Join(t.TempDir(), "email.log")}
+}
+
+func readEmailLog(t *testing.T, path string) []EmailLogEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading the email log: %v", err)
+	}
+	entries := []EmailLogEntry{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		entry := EmailLogEntry{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Error occurred while parsing the email log: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestEmail_SendSuccess(t *testing.T) {
+	host, port := startFakeSMTPServer(t, nil, false)
+	email := getTestEmail(t, host, port)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	to := make([]string, 1, 2)
+	to[0] = " designer@example.com"
+	if err := email.Send(ctx, "me@example.com", to, "Thumbnail", "Body", ""); err != nil {
+		t.Fatalf("Expected the email to be sent, but got %v", err)
+	}
+	if to[0] != " designer@example.com" || slices.Contains(to[:cap(to)], "me@example.com") {
+		t.Errorf("Expected: the recipients to stay as they were\nGot: %q", to[:cap(to)])
+	}
+	entries := readEmailLog(t, email.logPath)
+	if len(entries) != 1 || entries[0].Error != "" || !strings.HasSuffix(entries[0].MessageID, "@example.com>") {
+		t.Errorf("Unexpected email log entries: %v", entries)
+	}
+}
+
+func TestEmail_SendRejectedRecipient(t *testing.T) {
+	host, port := startFakeSMTPServer(t, []string{"typo@example.com"}, false)
+	email := getTestEmail(t, host, port)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := email.Send(ctx, "me@example.com", []string{"typo@example.com"}, "Thumbnail", "Body", "")
+	if !errors.Is(err, ErrEmailRecipient) {
+		t.Fatalf("Expected a rejected recipient error, but got %v", err)
+	}
+	entries := readEmailLog(t, email.logPath)
+	if len(entries) != 1 || entries[0].Error == "" {
+		t.Errorf("Expected the failure to be logged, but got %v", entries)
+	}
+}
+
+func TestEmail_SendTimeout(t *testing.T) {
+	host, port := startFakeSMTPServer(t, nil, true)
+	email := getTestEmail(t, host, port)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := email.Send(ctx, "me@example.com", []string{"designer@example.com"}, "Thumbnail", "Body", "")
+	if !errors.Is(err, ErrEmailConnection) {
+		t.Fatalf("Expected a connection error, but got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected the send to give up after the context timeout, but it took %v", time.Since(start))
+	}
+}
+
+func TestEmail_SendInvalidAddress(t *testing.T) {
+	email := getTestEmail(t, "127.0.0.1", 1)
+	err := email.Send(context.Background(), "me@example.com", []string{"designer@@example"}, "Thumbnail", "Body", "")
+	if !errors.Is(err, ErrEmailAddress) {
+		t.Errorf("Expected an invalid address error, but got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const aiFeedbackPath = "ai-feedback.jsonl"
+
+// AIFeedbackEntry records how an AI suggestion ended up in a video.
+// Only the suggestion and the saved value are stored, never the manuscript.
+type AIFeedbackEntry struct {
+	Time       time.Time `json:"time"`
+	Endpoint   string    `json:"endpoint"`
+	Field      string    `json:"field"`
+	Suggestion string    `json:"suggestion"`
+	Final      string    `json:"final"`
+	Edited     bool      `json:"edited"`
+	Distance   int       `json:"distance"`
+	VideoId    string    `json:"videoId,omitempty"`
+	VideoName  string    `json:"videoName,omitempty"`
+}
+
+type AIFeedbackStats struct {
+	Endpoint        string
+	Count           int
+	Unchanged       int
+	AcceptanceRate  float64
+	AverageDistance float64
+}
+
+func newAIFeedbackEntry(endpoint, field, suggestion, final string, video Video) AIFeedbackEntry {
+	suggestion = strings.TrimSpace(suggestion)
+	final = strings.TrimSpace(final)
+	distance := getEditDistance(suggestion, final)
+	return AIFeedbackEntry{
+		Time:       time.Now(),
+		Endpoint:   endpoint,
+		Field:      field,
+		Suggestion: suggestion,
+		Final:      final,
+		Edited:     distance > 0,
+		Distance:   distance,
+		VideoId:    video.VideoId,
+		VideoName:  video.Name,
+	}
+}
+
+// getClosestSuggestion returns the line of the AI output that is the closest to the final value.
+// It's used for outputs that contain multiple suggestions (e.g., titles) out of which one is picked.
+func getClosestSuggestion(output, final string) string {
+	closest := strings.TrimSpace(output)
+	closestDistance := -1
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		distance := getEditDistance(line, strings.TrimSpace(final))
+		if closestDistance < 0 || distance < closestDistance {
+			closest, closestDistance = line, distance
+		}
+	}
+	return closest
+}
+
+// recordAIFeedback appends the entry to the feedback file. It's best effort and never fails the save.
+func recordAIFeedback(path string, entry AIFeedbackEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.Write(append(data, '\n'))
+}
+
+func readAIFeedback(path string) ([]AIFeedbackEntry, error) {
+	entries := []AIFeedbackEntry{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry := AIFeedbackEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func getAIFeedbackStats(entries []AIFeedbackEntry) []AIFeedbackStats {
+	statsMap := map[string]*AIFeedbackStats{}
+	distances := map[string]int{}
+	for _, entry := range entries {
+		stats, ok := statsMap[entry.Endpoint]
+		if !ok {
+			stats = &AIFeedbackStats{Endpoint: entry.Endpoint}
+			statsMap[entry.Endpoint] = stats
+		}
+		stats.Count++
+		if !entry.Edited {
+			stats.Unchanged++
+		}
+		distances[entry.Endpoint] += entry.Distance
+	}
+	allStats := []AIFeedbackStats{}
+	for endpoint, stats := range statsMap {
+		stats.AcceptanceRate = float64(stats.Unchanged) / float64(stats.Count)
+		stats.AverageDistance = float64(distances[endpoint]) / float64(stats.Count)
+		allStats = append(allStats, *stats)
+	}
+	sort.Slice(allStats, func(i, j int) bool {
+		return allStats[i].Endpoint < allStats[j].Endpoint
+	})
+	return allStats
+}
+
+func getAIFeedbackReport(stats []AIFeedbackStats) string {
+	if len(stats) == 0 {
+		return "There is no AI feedback recorded yet."
+	}
+	lines := []string{}
+	for _, stat := range stats {
+		lines = append(lines, fmt.Sprintf("%s: %d suggestions, %.0f%% used unchanged, %.1f average edit distance", stat.Endpoint, stat.Count, stat.AcceptanceRate*100, stat.AverageDistance))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getEditDistance returns the Levenshtein distance between two strings.
+func getEditDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
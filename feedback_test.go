@@ -0,0 +1,56 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFeedback_getEditDistance(t *testing.T) {
+	distances := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"Title", "Title", 0},
+		{"", "abc", 3},
+		{"café", "cafe", 1},
+	}
+	for _, distance := range distances {
+		if actual := getEditDistance(distance.a, distance.b); actual != distance.expected {
+			t.Errorf("Expected the distance between %q and %q to be %d, but got %d", distance.a, distance.b, distance.expected, actual)
+		}
+	}
+}
+
+func TestFeedback_getClosestSuggestion(t *testing.T) {
+	output := "1. Kubernetes Is Dead\n\n2. Why Kubernetes Won\n3. Stop Using Helm"
+	if actual := getClosestSuggestion(output, "Why Kubernetes Won!"); actual != "2. Why Kubernetes Won" {
+		t.Errorf("Expected the second suggestion, but got %q", actual)
+	}
+}
+
+func TestFeedback_getAIFeedbackStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-feedback.jsonl")
+	video := Video{Name: "something", VideoId: "abc"}
+	recordAIFeedback(path, newAIFeedbackEntry("title_dot", "Title", "Why Kubernetes Won", "Why Kubernetes Won", video))
+	recordAIFeedback(path, newAIFeedbackEntry("title_dot", "Title", "Stop Using Helm", "Stop Using Helm Now", video))
+	recordAIFeedback(path, newAIFeedbackEntry("description_dot", "Description", "Description.\n", "Description.", video))
+	entries, err := readAIFeedback(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading the feedback: %v", err)
+	}
+	expected := []AIFeedbackStats{
+		{Endpoint: "description_dot", Count: 1, Unchanged: 1, AcceptanceRate: 1, AverageDistance: 0},
+		{Endpoint: "title_dot", Count: 2, Unchanged: 1, AcceptanceRate: 0.5, AverageDistance: 2},
+	}
+	if actual := getAIFeedbackStats(entries); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+	missing, err := readAIFeedback(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil || len(missing) != 0 {
+		t.Errorf("Expected no entries and no error for a missing file, but got %v and %v", missing, err)
+	}
+}
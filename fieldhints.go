@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The limits of the fields shown while typing. They are soft: exceeding them is highlighted but does not block saving.
+const titleRecommendedLength = 70
+const titleMaxLength = 100
+const tweetMaxLength = 280
+const tagsMaxLength = 500
+const descriptionTagsShown = 3
+const descriptionTagsMaxCount = 15
+
+// FieldHint is shown beneath a field and updated as the value changes.
+type FieldHint struct {
+	Text    string
+	Warning bool
+}
+
+func getTitleHint(title string) FieldHint {
+	length := utf8.RuneCountInString(strings.TrimSpace(title))
+	switch {
+	case length > titleMaxLength:
+		return FieldHint{Text: fmt.Sprintf("%d characters; YouTube accepts up to %d", length, titleMaxLength), Warning: true}
+	case length > titleRecommendedLength:
+		return FieldHint{Text: fmt.Sprintf("%d characters; titles longer than %d are cut off in search results", length, titleRecommendedLength), Warning: true}
+	}
+	return FieldHint{Text: fmt.Sprintf("%d/%d characters", length, titleRecommendedLength)}
+}
+
+func getTweetHint(tweet string) FieldHint {
+	length := utf8.RuneCountInString(strings.TrimSpace(tweet))
+	if length > tweetMaxLength {
+		return FieldHint{Text: fmt.Sprintf("%d/%d characters; %d too many", length, tweetMaxLength, length-tweetMaxLength), Warning: true}
+	}
+	return FieldHint{Text: fmt.Sprintf("%d/%d characters", length, tweetMaxLength)}
+}
+
+func getTagsHint(tags string) FieldHint {
+	split := splitTags(tags)
+	length := utf8.RuneCountInString(strings.Join(split, ","))
+	text := fmt.Sprintf("%d tags, %d/%d characters", len(split), length, tagsMaxLength)
+	if length > tagsMaxLength {
+		return FieldHint{Text: fmt.Sprintf("%s; YouTube rejects longer tags", text), Warning: true}
+	}
+	return FieldHint{Text: text}
+}
+
+func getDescriptionTagsHint(tags string) FieldHint {
+	count := 0
+	for _, word := range strings.Fields(tags) {
+		if strings.HasPrefix(word, "#") {
+			count++
+		}
+	}
+	switch {
+	case count > descriptionTagsMaxCount:
+		return FieldHint{Text: fmt.Sprintf("%d hashtags; YouTube ignores all of them when there are more than %d", count, descriptionTagsMaxCount), Warning: true}
+	case count > descriptionTagsShown:
+		return FieldHint{Text: fmt.Sprintf("%d hashtags; only the first %d are shown above the title", count, descriptionTagsShown)}
+	}
+	return FieldHint{Text: fmt.Sprintf("%d hashtags", count)}
+}
+
+// getDateHint warns about publish dates in the past. Dates that cannot be parsed have no hint since the format is validated separately.
+func getDateHint(date string, now time.Time) FieldHint {
+	parsed, err := time.Parse(dateFormat, strings.TrimSpace(date))
+	if err != nil {
+		return FieldHint{}
+	}
+	if parsed.Before(now) {
+		return FieldHint{Text: "The date is in the past", Warning: true}
+	}
+	return FieldHint{}
+}
+
+// fieldHints are the hints of the fields edited with AI, keyed by the field names.
+var fieldHints = map[string]func(string) FieldHint{
+	"Title":            getTitleHint,
+	"Tweet":            getTweetHint,
+	"Tags":             getTagsHint,
+	"Description Tags": getDescriptionTagsHint,
+}
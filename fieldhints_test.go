@@ -0,0 +1,60 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestFieldHints_boundaries(t *testing.T) {
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	hashtags := func(count int) string {
+		return strings.TrimSpace(strings.Repeat("#tag ", count))
+	}
+	tests := []struct {
+		name     string
+		actual   FieldHint
+		expected FieldHint
+	}{
+		{"title at the recommended length", getTitleHint(strings.Repeat("a", 70)), FieldHint{Text: "70/70 characters"}},
+		{"title over the recommended length", getTitleHint(strings.Repeat("a", 71)), FieldHint{Text: "71 characters; titles longer than 70 are cut off in search results", Warning: true}},
+		{"title at the maximum length", getTitleHint(strings.Repeat("a", 100)), FieldHint{Text: "100 characters; titles longer than 70 are cut off in search results", Warning: true}},
+		{"title over the maximum length", getTitleHint(strings.Repeat("a", 101)), FieldHint{Text: "101 characters; YouTube accepts up to 100", Warning: true}},
+		{"title with multi-byte characters", getTitleHint(strings.Repeat("ü", 70)), FieldHint{Text: "70/70 characters"}},
+		{"empty tweet", getTweetHint(""), FieldHint{Text: "0/280 characters"}},
+		{"tweet at the limit", getTweetHint(strings.Repeat("a", 280)), FieldHint{Text: "280/280 characters"}},
+		{"tweet over the limit", getTweetHint(strings.Repeat("a", 281)), FieldHint{Text: "281/280 characters; 1 too many", Warning: true}},
+		{"tags at the limit", getTagsHint(strings.Repeat("a", 249) + ", " + strings.Repeat("b", 250)), FieldHint{Text: "2 tags, 500/500 characters"}},
+		{"tags over the limit", getTagsHint(strings.Repeat("a", 250) + "," + strings.Repeat("b", 250)), FieldHint{Text: "2 tags, 501/500 characters; YouTube rejects longer tags", Warning: true}},
+		{"shown hashtags", getDescriptionTagsHint(hashtags(3)), FieldHint{Text: "3 hashtags"}},
+		{"hashtags not shown above the title", getDescriptionTagsHint(hashtags(4)), FieldHint{Text: "4 hashtags; only the first 3 are shown above the title"}},
+		{"hashtags at the limit", getDescriptionTagsHint(hashtags(15)), FieldHint{Text: "15 hashtags; only the first 3 are shown above the title"}},
+		{"hashtags over the limit", getDescriptionTagsHint(hashtags(16)), FieldHint{Text: "16 hashtags; YouTube ignores all of them when there are more than 15", Warning: true}},
+		{"date in the past", getDateHint("2030-01-21T15:59", now), FieldHint{Text: "The date is in the past", Warning: true}},
+		{"date now", getDateHint("2030-01-21T16:00", now), FieldHint{}},
+		{"date in the future", getDateHint("2030-01-21T16:01", now), FieldHint{}},
+		{"date in a wrong format", getDateHint("21.01.2030", now), FieldHint{}},
+	}
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("%s\nExpected: %+v\nGot: %+v", test.name, test.expected, test.actual)
+		}
+	}
+}
+
+// Hints are only shown. A form with values over the limits is submitted like any other.
+func TestFieldHints_formIsSavedDespiteWarnings(t *testing.T) {
+	title := ""
+	form := newForm(huh.NewGroup(withInputHint(huh.NewInput().Title("Title").Value(&title), &title, getTitleHint)))
+	typed := strings.Repeat("a", 120)
+	if err := runFormOnce(form, tea.WithInput(strings.NewReader(typed+"\r")), tea.WithOutput(io.Discard), tea.WithoutSignalHandler()); err != nil {
+		t.Fatalf("Expected the form to be submitted, but got %v", err)
+	}
+	if title != typed {
+		t.Errorf("Expected: %q\nGot: %q", typed, title)
+	}
+}
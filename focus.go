@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+
+	"github.com/charmbracelet/huh"
+)
+
+var focusStatePath = filepath.Join(".index", "focus.json")
+
+const focusProgressBarWidth = 20
+
+const focusEditManuscript = -1
+const focusReadiness = -2
+const focusNote = -3
+const focusRecord = -4
+
+// focusPhases are the phases in the order they are worked on.
+var focusPhases = []string{customFieldPhaseInit, customFieldPhaseWork, customFieldPhaseDefine, customFieldPhaseEdit, customFieldPhasePublish}
+
+var focusPhaseTitles = map[string]string{
+	customFieldPhaseInit:    "Initialize",
+	customFieldPhaseWork:    "Work",
+	customFieldPhaseDefine:  "Define",
+	customFieldPhaseEdit:    "Edit",
+	customFieldPhasePublish: "Publish",
+}
+
+// FocusState is the video that is being focused on. It is kept until focus mode is exited so that it can be resumed after a restart.
+type FocusState struct {
+	Name     string
+	Category string
+	Started  string
+}
+
+// FocusField is an incomplete field and the phase whose editor sets it.
+type FocusField struct {
+	Phase string
+	Name  string
+}
+
+// getPhaseFields returns the fields counted by the progress of the phase, including its custom fields.
+func getPhaseFields(video Video, phase string, s Settings) []workflow.Field {
+	fields := getBuiltInPhaseFields(video, phase, s)
+	for _, customField := range getPhaseCustomFields(s.CustomFields, phase) {
+		fields = append(fields, workflow.Field{Name: customField.Label, Done: isCustomFieldCompleted(customField, video.CustomFields[customField.Key])})
+	}
+	return fields
+}
+
+// getIncompleteFields returns the incomplete fields of all phases, sorted by the phase order.
+func getIncompleteFields(video Video, s Settings) []FocusField {
+	incomplete := []FocusField{}
+	for _, phase := range focusPhases {
+		for _, field := range getPhaseFields(video, phase, s) {
+			if !field.Done {
+				incomplete = append(incomplete, FocusField{Phase: phase, Name: field.Name})
+			}
+		}
+	}
+	return incomplete
+}
+
+func getProgressBar(tasks Tasks, width int) string {
+	filled := 0
+	if tasks.Total > 0 {
+		filled = tasks.Completed * width / tasks.Total
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+func getFocusHeader(video Video, s Settings) string {
+	lines := []string{fmt.Sprintf("%s (%s)", video.Name, video.Category)}
+	for _, phase := range focusPhases {
+		tasks := workflow.GetProgress(getPhaseFields(video, phase, s))
+		line := fmt.Sprintf("%-10s %s %d/%d", focusPhaseTitles[phase], getProgressBar(tasks, focusProgressBarWidth), tasks.Completed, tasks.Total)
+		if tasks.Completed == tasks.Total {
+			line = greenStyle.Render(line)
+		} else {
+			line = orangeStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getFocusCountdown returns the time left until the publish date rounded down to days or, within the last day, to hours.
+func getFocusCountdown(date string, now time.Time) string {
+	parsed, err := time.Parse(dateFormat, date)
+	if err != nil {
+		return "No publish date"
+	}
+	left := parsed.Sub(now)
+	switch {
+	case left < 0:
+		return fmt.Sprintf("The publish date %s has passed", date)
+	case left < 24*time.Hour:
+		return fmt.Sprintf("Publishes in %d hours (%s)", int(left.Hours()), date)
+	}
+	return fmt.Sprintf("Publishes in %d days (%s)", int(left.Hours()/24), date)
+}
+
+func loadFocusState(path string) (FocusState, error) {
+	state := FocusState{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("focus state %s is corrupted: %w", path, err)
+	}
+	return state, nil
+}
+
+func saveFocusState(path string, state FocusState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+func clearFocusState(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// getFocusVideoIndex returns the position of the focused video in the index or -1 if it is not there anymore.
+func getFocusVideoIndex(state FocusState, vi []VideoIndex) int {
+	for i := range vi {
+		if vi[i].Name == state.Name && vi[i].Category == state.Category {
+			return i
+		}
+	}
+	return -1
+}
+
+func openInEditor(path string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("the video has no manuscript")
+	}
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Wait()
+}
+
+// ChooseResumeFocus offers to resume focus mode that was not exited before the CLI stopped.
+func (c *Choices) ChooseResumeFocus() {
+	state, err := loadFocusState(focusStatePath)
+	if err != nil {
+		return
+	}
+	yaml := YAML{IndexPath: "index.yaml"}
+	vi := yaml.GetIndex()
+	index := getFocusVideoIndex(state, vi)
+	if index < 0 {
+		clearFocusState(focusStatePath)
+		return
+	}
+	resume := true
+	form := newForm(huh.NewGroup(huh.NewConfirm().Title(fmt.Sprintf("Resume focus on %s (since %s)?", state.Name, state.Started)).Value(&resume)))
+	if err := runForm(form); err != nil {
+		return
+	}
+	if !resume {
+		clearFocusState(focusStatePath)
+		return
+	}
+	if err := c.ChooseFocus(c.getVideo(vi, index)); err != nil {
+		output.Error(err.Error())
+	}
+}
+
+// ChooseFocus is a workspace for a single video that lists what is left to do across all phases until the user exits it.
+func (c *Choices) ChooseFocus(video Video) error {
+	state := FocusState{Name: video.Name, Category: video.Category, Started: time.Now().Format(dateFormat)}
+	if previous, err := loadFocusState(focusStatePath); err == nil && previous.Name == state.Name && previous.Category == state.Category {
+		state = previous
+	}
+	if err := saveFocusState(focusStatePath, state); err != nil {
+		return err
+	}
+	for {
+		incomplete := getIncompleteFields(video, settings)
+		options := huh.NewOptions[int]()
+		for i, field := range incomplete {
+			options = append(options, huh.NewOption(fmt.Sprintf("%s: %s", focusPhaseTitles[field.Phase], field.Name), i))
+		}
+		options = append(options,
+			huh.NewOption("Open the manuscript in $EDITOR", focusEditManuscript),
+			huh.NewOption("Run the readiness check", focusReadiness),
+			huh.NewOption("Log a note", focusNote),
+			huh.NewOption("Record session", focusRecord),
+			huh.NewOption("Exit focus mode", actionReturn),
+		)
+		selected := actionReturn
+		form := newForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(getFocusHeader(video, settings)).
+					Description(fmt.Sprintf("%d incomplete fields. %s.", len(incomplete), getFocusCountdown(video.Date, time.Now()))).
+					Options(options...).
+					Value(&selected),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		switch selected {
+		case focusEditManuscript:
+			if err := openInEditor(video.Gist); err != nil {
+				output.Error(err.Error())
+			}
+		case focusReadiness:
+			score := getQualityScore(video, settings.Quality.Weights, settings.Tags.Aliases)
+			output.ResultText(fmt.Sprintf("%s\n%s", getQualityScoreText(score), getDescriptionFindingsText(lintVideoDescription(video))))
+		case focusNote:
+			if err := c.ChooseNote(&video); err != nil {
+				output.Error(err.Error())
+			}
+		case focusRecord:
+			if err := c.ChooseRecordSession(video); err != nil {
+				output.Error(err.Error())
+			}
+			// The session is saved to the file, not to the video in this loop.
+			yaml := YAML{}
+			reloaded := yaml.GetVideo(video.Path)
+			reloaded.Name, reloaded.Path, reloaded.Index, reloaded.Category = video.Name, video.Path, video.Index, video.Category
+			video = reloaded
+		case actionReturn:
+			return clearFocusState(focusStatePath)
+		default:
+			edited, err := c.ChoosePhaseTasks(video, incomplete[selected].Phase)
+			if err != nil {
+				output.Error(err.Error())
+			}
+			video = edited
+		}
+	}
+}
+
+func (c *Choices) ChooseNote(video *Video) error {
+	note := ""
+	form := newForm(huh.NewGroup(huh.NewText().Title("Note").Value(&note)))
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(note)) == 0 {
+		return nil
+	}
+	video.Notes = append(video.Notes, strings.TrimSpace(note))
+	yaml := YAML{}
+	yaml.WriteVideo(video, video.Path)
+	return nil
+}
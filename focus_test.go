@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFocus_getIncompleteFields(t *testing.T) {
+	s := Settings{CustomFields: []CustomField{{Key: "reviewer", Label: "Reviewer", Type: customFieldTypeString, Phase: customFieldPhaseEdit}}}
+	video := Video{
+		ProjectName: "Crossplane",
+		Code:        true,
+		Title:       "Title",
+		Timecodes:   "00:00 Intro",
+		HugoPath:    "post.md",
+	}
+	incomplete := getIncompleteFields(video, s)
+	phases := []int{}
+	names := map[string]bool{}
+	for _, field := range incomplete {
+		phases = append(phases, slices.Index(focusPhases, field.Phase))
+		names[field.Name] = true
+	}
+	if !slices.IsSorted(phases) {
+		t.Errorf("Expected the fields to be sorted by the phase order\nGot: %v", incomplete)
+	}
+	for _, name := range []string{"Project URL", "Screen", "Description", "Thumbnail", "Reviewer", "Upload video"} {
+		if !names[name] {
+			t.Errorf("Expected %s to be incomplete\nGot: %v", name, incomplete)
+		}
+	}
+	for _, name := range []string{"Project name", "Code", "Title", "Timecodes", "Hugo post"} {
+		if names[name] {
+			t.Errorf("Expected %s to be complete\nGot: %v", name, incomplete)
+		}
+	}
+	expected := 0
+	for _, phase := range focusPhases {
+		for _, field := range getPhaseFields(video, phase, s) {
+			if !field.Done {
+				expected++
+			}
+		}
+	}
+	if len(incomplete) != expected {
+		t.Errorf("Expected: %d incomplete fields\nGot: %d", expected, len(incomplete))
+	}
+
+	video.CustomFields = map[string]string{"reviewer": "Viktor"}
+	if slices.Contains(getIncompleteFields(video, s), FocusField{Phase: customFieldPhaseEdit, Name: "Reviewer"}) {
+		t.Errorf("Expected the custom field to be complete")
+	}
+}
+
+func TestFocus_getFocusCountdown(t *testing.T) {
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		date     string
+		expected string
+	}{
+		"days":    {date: "2030-01-24T18:00", expected: "Publishes in 3 days (2030-01-24T18:00)"},
+		"hours":   {date: "2030-01-22T10:30", expected: "Publishes in 18 hours (2030-01-22T10:30)"},
+		"passed":  {date: "2030-01-20T16:00", expected: "The publish date 2030-01-20T16:00 has passed"},
+		"no date": {expected: "No publish date"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getFocusCountdown(tc.date, now); actual != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFocus_resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".index", "focus.json")
+	if _, err := loadFocusState(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected no state to resume\nGot: %v", err)
+	}
+	state := FocusState{Name: "crossplane", Category: "devops", Started: "2030-01-21T16:00"}
+	if err := saveFocusState(path, state); err != nil {
+		t.Fatalf("Expected no error\nGot: %v", err)
+	}
+	loaded, err := loadFocusState(path)
+	if err != nil || loaded != state {
+		t.Fatalf("Expected: %+v\nGot: %+v, %v", state, loaded, err)
+	}
+	vi := []VideoIndex{{Name: "crossplane", Category: "ai"}, {Name: "crossplane", Category: "devops"}}
+	if index := getFocusVideoIndex(loaded, vi); index != 1 {
+		t.Errorf("Expected: 1\nGot: %d", index)
+	}
+	if index := getFocusVideoIndex(loaded, vi[:1]); index != -1 {
+		t.Errorf("Expected a video that is not in the index to be missing\nGot: %d", index)
+	}
+	if err := clearFocusState(path); err != nil {
+		t.Fatalf("Expected no error\nGot: %v", err)
+	}
+	if err := clearFocusState(path); err != nil {
+		t.Errorf("Expected clearing a cleared state to succeed\nGot: %v", err)
+	}
+	if _, err := loadFocusState(path); !os.IsNotExist(err) {
+		t.Errorf("Expected no state after it was cleared\nGot: %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Selects with fewer options than this are shown without asking for a filter first.
+const selectFilterMinOptions = 10
+
+// newForm creates a form with the theme, accessibility, and help settings applied so that all forms look the same.
+func newForm(groups ...*huh.Group) *huh.Form {
+	return huh.NewForm(groups...).
+		WithTheme(getFormTheme(settings.UI.Theme)).
+		WithAccessible(settings.UI.Accessible).
+		WithShowHelp(!settings.UI.HideHelp)
+}
+
+// errFormInterrupted means that the form stopped without being completed or aborted by the user (e.g., a panic in the terminal UI or a killed program).
+var errFormInterrupted = errors.New("the form was interrupted")
+
+// formProgramOptions are the extra options of the program each form run uses (e.g., the input in tests).
+var formProgramOptions = func() []tea.ProgramOption {
+	return nil
+}
+
+// runForm runs the form and, if it is interrupted, offers to resume it instead of losing what was typed.
+// Fields write to the bound variables as they change and the same form is run again, so the resumed form starts with the values entered before the interruption.
+func runForm(form *huh.Form) error {
+	for {
+		err := runFormOnce(form, formProgramOptions()...)
+		if !errors.Is(err, errFormInterrupted) || !confirmFormResume(err) {
+			return err
+		}
+	}
+}
+
+// runFormOnce runs the form as huh does, except that Ctrl+Z suspends it and panics are returned as interruptions.
+func runFormOnce(form *huh.Form, options ...tea.ProgramOption) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", errFormInterrupted, r)
+		}
+	}()
+	if settings.UI.Accessible {
+		return form.Run()
+	}
+	if getFormFieldCount(form) == 0 {
+		return nil
+	}
+	form.SubmitCmd = tea.Quit
+	form.CancelCmd = tea.Quit
+	// Like huh, forms are rendered to stderr so that stdout has only the results (e.g., with --json).
+	_, err = tea.NewProgram(suspendableForm{form}, append([]tea.ProgramOption{tea.WithOutput(os.Stderr), tea.WithReportFocus()}, options...)...).Run()
+	switch {
+	case form.State == huh.StateAborted:
+		return huh.ErrUserAborted
+	case err != nil:
+		return fmt.Errorf("%w: %w", errFormInterrupted, err)
+	case form.State != huh.StateCompleted:
+		// Bubble Tea recovers from panics in the UI, restores the terminal, and returns without an error.
+		return errFormInterrupted
+	}
+	return nil
+}
+
+// getFormFieldCount counts the fields of all the groups. huh does not export them, so they are read through reflection.
+// A form without fields has nothing to focus and would fail as soon as it is rendered.
+func getFormFieldCount(form *huh.Form) int {
+	groups := reflect.ValueOf(form).Elem().FieldByName("selector").Elem().FieldByName("items")
+	count := 0
+	for i := 0; i < groups.Len(); i++ {
+		count += groups.Index(i).Elem().FieldByName("selector").Elem().FieldByName("items").Len()
+	}
+	return count
+}
+
+// suspendableForm suspends the program on Ctrl+Z. Bubble Tea releases the terminal before the process is stopped and restores it once it continues (SIGCONT) so the form only needs to be redrawn for the current size of the terminal.
+type suspendableForm struct {
+	*huh.Form
+}
+
+func (f suspendableForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlZ {
+			return f, tea.Suspend
+		}
+	case tea.ResumeMsg:
+		return f, tea.Batch(tea.ClearScreen, tea.WindowSize())
+	}
+	_, cmd := f.Form.Update(msg)
+	return f, cmd
+}
+
+var confirmFormResume = func(err error) bool {
+	output.Warn(err.Error())
+	resume := true
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("The form was interrupted. Resume it with the values entered so far?").
+				Affirmative("Resume form").
+				Negative("Discard").
+				Value(&resume),
+		),
+	)
+	if err := runFormOnce(form, formProgramOptions()...); err != nil {
+		return false
+	}
+	return resume
+}
+
+func renderFieldHint(hint FieldHint) string {
+	if hint.Warning {
+		return orangeStyle.Render(hint.Text)
+	}
+	return hint.Text
+}
+
+// hintAccessor writes the value to the bound variable and keeps a copy for the hint. Hints are computed by huh outside of the update loop
+// that writes the bound variable, so they read the copy under a lock instead.
+type hintAccessor struct {
+	mu      sync.Mutex
+	value   *string
+	current string
+}
+
+func newHintAccessor(value *string) *hintAccessor {
+	return &hintAccessor{value: value, current: *value}
+}
+
+func (a *hintAccessor) Get() string {
+	return *a.value
+}
+
+func (a *hintAccessor) Set(value string) {
+	*a.value = value
+	a.mu.Lock()
+	a.current = value
+	a.mu.Unlock()
+}
+
+func (a *hintAccessor) hint(hint func(string) FieldHint) func() string {
+	return func() string {
+		a.mu.Lock()
+		current := a.current
+		a.mu.Unlock()
+		return renderFieldHint(hint(current))
+	}
+}
+
+// withInputHint shows the hint of the value beneath the input and re-renders it as the value changes. Unlike Validate, hints never block submitting the form.
+func withInputHint(input *huh.Input, value *string, hint func(string) FieldHint) *huh.Input {
+	accessor := newHintAccessor(value)
+	return input.Accessor(accessor).DescriptionFunc(accessor.hint(hint), value)
+}
+
+func withTextHint(text *huh.Text, value *string, hint func(string) FieldHint) *huh.Text {
+	accessor := newHintAccessor(value)
+	return text.Accessor(accessor).DescriptionFunc(accessor.hint(hint), value)
+}
+
+func getFormTheme(name string) *huh.Theme {
+	switch strings.ToLower(name) {
+	case "base":
+		return huh.ThemeBase()
+	case "dracula":
+		return huh.ThemeDracula()
+	case "base16":
+		return huh.ThemeBase16()
+	case "catppuccin":
+		return huh.ThemeCatppuccin()
+	default:
+		return huh.ThemeCharm()
+	}
+}
+
+// askOptionsFilter asks for the text used to narrow down long lists of options. Short lists are not filtered.
+func askOptionsFilter(count int) (string, error) {
+	if count < selectFilterMinOptions {
+		return "", nil
+	}
+	filter := ""
+	form := newForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Filter").
+				Description("Leave empty to list all.").
+				Value(&filter),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(filter), nil
+}
+
+// filterOptions keeps the options whose labels, without styling, contain the filter ignoring the case.
+// Options with one of the always values (e.g., Return) are kept regardless of the filter.
+func filterOptions[T comparable](options []huh.Option[T], filter string, always ...T) []huh.Option[T] {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if len(filter) == 0 {
+		return options
+	}
+	filtered := []huh.Option[T]{}
+	for _, option := range options {
+		if slices.Contains(always, option.Value) || strings.Contains(strings.ToLower(ansi.Strip(option.Key)), filter) {
+			filtered = append(filtered, option)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestForm_getFormTheme(t *testing.T) {
+	themes := map[string]*huh.Theme{
+		"":           huh.ThemeCharm(),
+		"charm":      huh.ThemeCharm(),
+		"Dracula":    huh.ThemeDracula(),
+		"base16":     huh.ThemeBase16(),
+		"catppuccin": huh.ThemeCatppuccin(),
+		"base":       huh.ThemeBase(),
+		"unknown":    huh.ThemeCharm(),
+	}
+	for name, expected := range themes {
+		if actual := getFormTheme(name); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected theme %q to be resolved to a different theme", name)
+		}
+	}
+}
+
+// All forms must be created through newForm so that the configured theme is applied uniformly.
+func TestForm_newFormIsUsedEverywhere(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Error occurred while listing files: %v", err)
+	}
+	for _, file := range files {
+		if file == "form.go" || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Error occurred while reading %s: %v", file, err)
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			if strings.Contains(line, "huh.NewForm(") && !strings.HasPrefix(strings.TrimSpace(line), "//") {
+				t.Errorf("Expected %s:%d to use newForm instead of huh.NewForm", file, i+1)
+			}
+		}
+	}
+}
+
+func TestForm_filterOptions(t *testing.T) {
+	options := []huh.Option[int]{
+		huh.NewOption("Sorted by date", -1),
+		huh.NewOption(greenStyle.Render("Kubernetes Operators (2030-01-21T16:00)"), 0),
+		huh.NewOption(orangeStyle.Render("Crossplane Compositions"), 1),
+		huh.NewOption("Argo CD vs. Flux (sponsored)", 2),
+		huh.NewOption("\x1b[32mHelm Charts\x1b[0m", 3),
+		huh.NewOption("Return", actionReturn),
+	}
+	tests := []struct {
+		filter   string
+		expected []int
+	}{
+		{"", []int{-1, 0, 1, 2, 3, actionReturn}},
+		{"kubernetes", []int{-1, 0, actionReturn}},
+		{"  OPERATORS ", []int{-1, 0, actionReturn}},
+		{"o", []int{-1, 0, 1, 2, actionReturn}},
+		{"helm charts", []int{-1, 3, actionReturn}},
+		{"sponsored", []int{-1, 2, actionReturn}},
+		{"32m", []int{-1, actionReturn}},
+		{"terraform", []int{-1, actionReturn}},
+	}
+	for _, test := range tests {
+		actual := []int{}
+		for _, option := range filterOptions(options, test.filter, -1, actionReturn) {
+			actual = append(actual, option.Value)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Filter %q\nExpected: %v\nGot: %v", test.filter, test.expected, actual)
+		}
+	}
+}
+
+func getTestFormOptions(input string, extra ...tea.ProgramOption) []tea.ProgramOption {
+	return append([]tea.ProgramOption{tea.WithInput(strings.NewReader(input)), tea.WithOutput(io.Discard), tea.WithoutSignalHandler()}, extra...)
+}
+
+func TestForm_runFormOnce(t *testing.T) {
+	name := ""
+	form := newForm(huh.NewGroup(huh.NewInput().Title("Name").Value(&name)))
+	if err := runFormOnce(form, getTestFormOptions("Kubernetes\r")...); err != nil {
+		t.Fatalf("Expected the form to be completed, but got %v", err)
+	}
+	if name != "Kubernetes" {
+		t.Errorf("Expected: %q\nGot: %q", "Kubernetes", name)
+	}
+	form = newForm(huh.NewGroup(huh.NewInput().Title("Name").Value(&name)))
+	if err := runFormOnce(form, getTestFormOptions("\x03")...); !errors.Is(err, huh.ErrUserAborted) {
+		t.Errorf("Expected Ctrl+C to abort the form, but got %v", err)
+	}
+}
+
+func TestForm_runFormOnceWithoutFields(t *testing.T) {
+	for name, form := range map[string]*huh.Form{"no groups": newForm(), "no fields": newForm(huh.NewGroup())} {
+		if err := runFormOnce(form, getTestFormOptions("")...); err != nil {
+			t.Errorf("%s\nExpected: no error\nGot: %v", name, err)
+		}
+	}
+}
+
+// An interrupted form is run again with the values entered before the interruption.
+func TestForm_runFormOnceResumesAfterInterruption(t *testing.T) {
+	name := ""
+	form := newForm(huh.NewGroup(huh.NewInput().Title("Name").Value(&name)))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := runFormOnce(form, getTestFormOptions("Kubernetes", tea.WithContext(ctx))...); !errors.Is(err, errFormInterrupted) {
+		t.Fatalf("Expected the killed form to be interrupted, but got %v", err)
+	}
+	if name != "Kubernetes" {
+		t.Errorf("Expected the value typed before the interruption to be kept\nExpected: %q\nGot: %q", "Kubernetes", name)
+	}
+	if err := runFormOnce(form, getTestFormOptions(" Operators\r")...); err != nil {
+		t.Fatalf("Expected the resumed form to be completed, but got %v", err)
+	}
+	if name != "Kubernetes Operators" {
+		t.Errorf("Expected the resumed form to be pre-filled\nExpected: %q\nGot: %q", "Kubernetes Operators", name)
+	}
+}
+
+func TestForm_runFormOnceRecoversFromPanics(t *testing.T) {
+	name := ""
+	panicked := false
+	form := newForm(huh.NewGroup(
+		huh.NewInput().Title("Name").Value(&name).Validate(func(string) error {
+			if !panicked {
+				panicked = true
+				panic("renderer failed")
+			}
+			return nil
+		}),
+	))
+	// Bubble Tea would recover from the panic itself and print the stack trace. It's disabled so that the panic reaches the runner.
+	err := runFormOnce(form, getTestFormOptions("Kubernetes\r", tea.WithoutCatchPanics())...)
+	if !errors.Is(err, errFormInterrupted) || !strings.Contains(err.Error(), "renderer failed") {
+		t.Fatalf("Expected the panic to be returned as an interruption, but got %v", err)
+	}
+	if name != "Kubernetes" {
+		t.Errorf("Expected: %q\nGot: %q", "Kubernetes", name)
+	}
+}
+
+func TestForm_runForm(t *testing.T) {
+	originalOptions, originalConfirm := formProgramOptions, confirmFormResume
+	defer func() {
+		formProgramOptions, confirmFormResume = originalOptions, originalConfirm
+	}()
+	tests := []struct {
+		name     string
+		resume   bool
+		expected string
+		err      error
+	}{
+		{"resumed", true, "Kubernetes Operators", nil},
+		{"discarded", false, "Kubernetes", errFormInterrupted},
+	}
+	for _, test := range tests {
+		runs := 0
+		formProgramOptions = func() []tea.ProgramOption {
+			runs++
+			if runs == 1 {
+				ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+				t.Cleanup(cancel)
+				return getTestFormOptions("Kubernetes", tea.WithContext(ctx))
+			}
+			return getTestFormOptions(" Operators\r")
+		}
+		asked := 0
+		confirmFormResume = func(error) bool {
+			asked++
+			return test.resume
+		}
+		name := ""
+		err := runForm(newForm(huh.NewGroup(huh.NewInput().Title("Name").Value(&name))))
+		if !errors.Is(err, test.err) || (err != nil && test.err == nil) {
+			t.Errorf("%s: Expected error %v, but got %v", test.name, test.err, err)
+		}
+		if name != test.expected {
+			t.Errorf("%s\nExpected: %q\nGot: %q", test.name, test.expected, name)
+		}
+		if asked != 1 {
+			t.Errorf("%s: Expected to be asked to resume once, but was asked %d times", test.name, asked)
+		}
+	}
+}
+
+func TestForm_suspendableForm(t *testing.T) {
+	form := suspendableForm{newForm(huh.NewGroup(huh.NewInput().Title("Name")))}
+	if _, cmd := form.Update(tea.KeyMsg{Type: tea.KeyCtrlZ}); cmd == nil || !reflect.DeepEqual(cmd(), tea.SuspendMsg{}) {
+		t.Errorf("Expected Ctrl+Z to suspend the program")
+	}
+	if _, cmd := form.Update(tea.ResumeMsg{}); cmd == nil {
+		t.Errorf("Expected the form to be redrawn once the program is resumed")
+	}
+}
+
+// All forms must be run through runForm so that interrupted forms can be resumed.
+func TestForm_runFormIsUsedEverywhere(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Error occurred while listing files: %v", err)
+	}
+	for _, file := range files {
+		if file == "form.go" || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Error occurred while reading %s: %v", file, err)
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			if strings.Contains(line, ".Run()") && !strings.HasPrefix(strings.TrimSpace(line), "//") {
+				t.Errorf("Expected %s:%d to use runForm instead of running the form directly", file, i+1)
+			}
+		}
+	}
+}
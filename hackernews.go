@@ -8,5 +8,5 @@ func postHackerNews(title, videoId string) {
 		title,
 		getYouTubeURL(videoId),
 	)
-	println(confirmationStyle.Render(message))
+	output.Result(message)
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const highlightChapterTitle = "⭐ Highlight"
+
+var highlightTimestampRegex = regexp.MustCompile(`\b(\d{1,2}:)?\d{1,2}:\d{2}\b`)
+
+// parseLegacyHighlight splits highlights written before HighlightTimestamp existed (e.g., "Demo of the new CLI (12:34)") into the text and the timestamp.
+func parseLegacyHighlight(highlight string) (text, timestamp string) {
+	for _, match := range highlightTimestampRegex.FindAllStringIndex(highlight, -1) {
+		candidate := highlight[match[0]:match[1]]
+		if _, err := parseTimestamp(candidate); err != nil {
+			continue
+		}
+		text = highlight[:match[0]] + highlight[match[1]:]
+		text = strings.ReplaceAll(text, "()", "")
+		text = strings.ReplaceAll(text, "[]", "")
+		text = strings.TrimSpace(text)
+		text = strings.TrimSuffix(strings.TrimSuffix(text, " at"), " @")
+		text = strings.Trim(text, " -:")
+		return text, candidate
+	}
+	return highlight, ""
+}
+
+func migrateHighlight(video *Video) {
+	if len(video.HighlightTimestamp) > 0 {
+		return
+	}
+	video.Highlight, video.HighlightTimestamp = parseLegacyHighlight(video.Highlight)
+}
+
+// validateHighlightTimestamp checks the timestamp format and, when the duration is known (non-zero), that it is within the video.
+func validateHighlightTimestamp(timestamp string, duration time.Duration) error {
+	if len(timestamp) == 0 {
+		return nil
+	}
+	value, err := parseTimestamp(timestamp)
+	if err != nil {
+		return err
+	}
+	if duration > 0 && value >= duration {
+		return fmt.Errorf("highlight (%s) must be before the end of the video (%s)", timestamp, formatTimestamp(duration))
+	}
+	return nil
+}
+
+// injectHighlightChapter adds the highlight to the timecodes so that it shows up as a YouTube chapter.
+// Chapters are sorted by time and duplicates are removed. Timecodes that still contain TODOs are left alone.
+func injectHighlightChapter(timecodes, timestamp string) string {
+	if len(timestamp) == 0 || len(timecodes) == 0 || timecodes == "N/A" || strings.Contains(timecodes, "TODO:") {
+		return timecodes
+	}
+	type chapter struct {
+		time time.Duration
+		line string
+	}
+	chapters := []chapter{}
+	other := []string{}
+	seen := map[string]bool{}
+	lines := append(strings.Split(timecodes, "\n"), fmt.Sprintf("%s %s", timestamp, highlightChapterTitle))
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || seen[line] || (i < len(lines)-1 && strings.HasSuffix(line, highlightChapterTitle)) {
+			continue
+		}
+		seen[line] = true
+		fields := strings.Fields(line)
+		value, err := parseTimestamp(fields[0])
+		if err != nil {
+			other = append(other, line)
+			continue
+		}
+		chapters = append(chapters, chapter{time: value, line: line})
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].time < chapters[j].time
+	})
+	output := []string{}
+	for _, chapter := range chapters {
+		output = append(output, chapter.line)
+	}
+	return strings.Join(append(output, other...), "\n")
+}
+
+// getUploadTimecodes returns the timecodes as they are uploaded. The highlight chapter is added only if the chapters still follow YouTube rules with it.
+func getUploadTimecodes(video Video) string {
+	withHighlight := injectHighlightChapter(video.Timecodes, video.HighlightTimestamp)
+	if _, violations := getChapterViolations(withHighlight); len(violations) == 0 {
+		return getCanonicalTimecodes(withHighlight)
+	}
+	return getCanonicalTimecodes(video.Timecodes)
+}
+
+// getHighlightURL returns the link to the video that starts at the highlight.
+func getHighlightURL(videoId, timestamp string) string {
+	value, err := parseTimestamp(timestamp)
+	if err != nil {
+		return getYouTubeURL(videoId)
+	}
+	return fmt.Sprintf("%s?t=%d", getYouTubeURL(videoId), int(value.Seconds()))
+}
+
+func replaceHighlightPlaceholder(message, videoId, timestamp string) string {
+	return strings.ReplaceAll(message, "[HIGHLIGHT]", getHighlightURL(videoId, timestamp))
+}
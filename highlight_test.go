@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHighlight_parseLegacyHighlight(t *testing.T) {
+	highlights := []struct {
+		highlight string
+		text      string
+		timestamp string
+	}{
+		{"Demo of the new CLI (12:34)", "Demo of the new CLI", "12:34"},
+		{"1:02:03 - The big reveal", "The big reveal", "1:02:03"},
+		{"Crossplane compositions at 05:10", "Crossplane compositions", "05:10"},
+		{"Nothing to see here", "Nothing to see here", ""},
+		{"Invalid 12:99 time", "Invalid 12:99 time", ""},
+		{"", "", ""},
+	}
+	for _, highlight := range highlights {
+		text, timestamp := parseLegacyHighlight(highlight.highlight)
+		if text != highlight.text || timestamp != highlight.timestamp {
+			t.Errorf("Expected %q to be parsed into %q and %q, but got %q and %q", highlight.highlight, highlight.text, highlight.timestamp, text, timestamp)
+		}
+	}
+	video := Video{Highlight: "Demo (12:34)", HighlightTimestamp: "01:00"}
+	migrateHighlight(&video)
+	if video.Highlight != "Demo (12:34)" || video.HighlightTimestamp != "01:00" {
+		t.Errorf("Expected videos with a highlight timestamp not to be migrated, but got %+v", video)
+	}
+}
+
+func TestHighlight_injectHighlightChapter(t *testing.T) {
+	timecodes := "00:00 Intro\n05:00 Setup\n05:00 Setup\n12:00 ⭐ Highlight\n20:00 Pros and Cons"
+	expected := "00:00 Intro\n05:00 Setup\n08:30 ⭐ Highlight\n20:00 Pros and Cons"
+	if actual := injectHighlightChapter(timecodes, "08:30"); actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+	if actual := injectHighlightChapter("00:00 TODO:", "08:30"); actual != "00:00 TODO:" {
+		t.Errorf("Expected unfinished timecodes to be left alone, but got %q", actual)
+	}
+	if actual := injectHighlightChapter(timecodes, ""); actual != timecodes {
+		t.Errorf("Expected timecodes without a highlight to be left alone, but got %q", actual)
+	}
+}
+
+func TestHighlight_getUploadTimecodes(t *testing.T) {
+	timecodes := "00:00 Intro\n05:00 Setup\n20:00 Pros and Cons"
+	tests := map[string]struct {
+		highlight string
+		expected  string
+	}{
+		"highlight":           {highlight: "08:30", expected: "00:00 Intro\n05:00 Setup\n08:30 ⭐ Highlight\n20:00 Pros and Cons"},
+		"too close":           {highlight: "05:05", expected: timecodes},
+		"without a highlight": {expected: timecodes},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getUploadTimecodes(Video{Timecodes: timecodes, HighlightTimestamp: test.highlight}); actual != test.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestHighlight_validateHighlightTimestamp(t *testing.T) {
+	if err := validateHighlightTimestamp("", 0); err != nil {
+		t.Errorf("Expected an empty timestamp to be valid, but got %v", err)
+	}
+	if err := validateHighlightTimestamp("12:34", 0); err != nil {
+		t.Errorf("Expected a timestamp to be valid when the duration is unknown, but got %v", err)
+	}
+	if err := validateHighlightTimestamp("12:34", 10*time.Minute); err == nil {
+		t.Errorf("Expected a timestamp after the end of the video to be invalid")
+	}
+	if err := validateHighlightTimestamp("twelve", 0); err == nil {
+		t.Errorf("Expected an invalid timestamp to be rejected")
+	}
+	if actual := replaceHighlightPlaceholder("Watch [HIGHLIGHT]", "abc", "01:02:03"); actual != "Watch https://youtu.be/abc?t=3723" {
+		t.Errorf("Unexpected highlight link %q", actual)
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,27 +9,23 @@ import (
 
 type Hugo struct{}
 
-func (r *Hugo) Post(gist, title, date string) (string, error) {
+func (r *Hugo) Post(gist, title, slug, date string) (string, error) {
 	if gist == "N/A" {
 		return "", nil
 	}
-	post := r.getPost(gist, title, date)
-	return r.hugoFromMarkdown(gist, title, post)
+	post, err := r.getPost(gist, title, date, "")
+	if err != nil {
+		return "", err
+	}
+	return r.hugoFromMarkdown(settings.Hugo.Path, gist, slug, post)
 }
 
-func (r *Hugo) hugoFromMarkdown(filePath, title, post string) (string, error) {
-	categoryDir := settings.Hugo.Path + "/" + strings.Replace(filepath.Dir(filePath), "manuscript", "content", 1)
-	postDir := title
-	postDir = strings.ReplaceAll(postDir, " ", "-")
-	postDir = strings.ReplaceAll(postDir, "(", "")
-	postDir = strings.ReplaceAll(postDir, ")", "")
-	postDir = strings.ReplaceAll(postDir, ":", "")
-	postDir = strings.ReplaceAll(postDir, "&", "")
-	postDir = strings.ReplaceAll(postDir, "/", "-")
-	postDir = strings.ReplaceAll(postDir, "'", "")
-	postDir = strings.ReplaceAll(postDir, "!", "")
-	postDir = strings.ToLower(postDir)
-	fullDir := categoryDir + "/" + postDir
+func (r *Hugo) hugoFromMarkdown(hugoDir, filePath, slug, post string) (string, error) {
+	if len(slug) == 0 {
+		return "", fmt.Errorf("the post needs a slug")
+	}
+	categoryDir := hugoDir + "/" + strings.Replace(filepath.Dir(filePath), "manuscript", "content", 1)
+	fullDir := categoryDir + "/" + slug
 	os.Mkdir(fullDir, os.FileMode(0755))
 	hugoPath := fullDir + "/_index.md"
 	hugoPath = strings.Replace(hugoPath, "//", "/", -1)
@@ -41,10 +36,13 @@ func (r *Hugo) hugoFromMarkdown(filePath, title, post string) (string, error) {
 	return hugoPath, nil
 }
 
-func (r *Hugo) getPost(filePath, title, date string) string {
-	contentBytes, err := os.ReadFile(filePath)
+func (r *Hugo) getPost(filePath, title, date, videoId string) (string, error) {
+	manuscript, _, err := readManuscript(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
+	}
+	if len(videoId) == 0 {
+		videoId = "FIXME:"
 	}
 	content := fmt.Sprintf(`
 +++
@@ -57,9 +55,102 @@ FIXME:
 
 <!--more-->
 
-{{< youtube FIXME: >}}
+{{< youtube %s >}}
 
 %s
-`, title, date, string(contentBytes))
-	return content
+%s
+`, title, date, videoId, manuscript, hugoManualMarker)
+	return content, nil
+}
+
+// Everything below the marker is written manually and preserved when posts are regenerated.
+const hugoManualMarker = "<!-- Manual content below is preserved when the post is regenerated -->"
+
+type HugoRegeneration struct {
+	Path    string
+	Changed bool
+	Added   int
+	Removed int
+	Skipped string
+	Error   string
+}
+
+// Regenerate re-renders posts of all videos with a Hugo path using the current template, keeping the manual content below the marker.
+// Posts without the marker are skipped since the manual content cannot be separated from the generated one.
+// Nothing is written when dryRun is true.
+func (r *Hugo) Regenerate(videos []Video, dryRun bool) []HugoRegeneration {
+	results := []HugoRegeneration{}
+	for _, video := range videos {
+		if len(video.HugoPath) == 0 {
+			continue
+		}
+		result := HugoRegeneration{Path: video.HugoPath}
+		existingBytes, err := os.ReadFile(video.HugoPath)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		existing := string(existingBytes)
+		markerIndex := strings.Index(existing, hugoManualMarker)
+		if markerIndex < 0 {
+			result.Skipped = "the manual content marker is missing"
+			results = append(results, result)
+			continue
+		}
+		post, err := r.getPost(video.Gist, video.Title, video.Date, video.VideoId)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		post = post[:strings.Index(post, hugoManualMarker)] + existing[markerIndex:]
+		result.Changed = post != existing
+		result.Added, result.Removed = getLineDiff(existing, post)
+		if result.Changed && !dryRun {
+			if err := os.WriteFile(video.HugoPath, []byte(post), 0644); err != nil {
+				result.Error = err.Error()
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func getHugoRegenerationSummary(results []HugoRegeneration) string {
+	lines := []string{}
+	for _, result := range results {
+		switch {
+		case len(result.Error) > 0:
+			lines = append(lines, fmt.Sprintf("%s: failed (%s)", result.Path, result.Error))
+		case len(result.Skipped) > 0:
+			lines = append(lines, fmt.Sprintf("%s: skipped (%s)", result.Path, result.Skipped))
+		case result.Changed:
+			lines = append(lines, fmt.Sprintf("%s: +%d -%d", result.Path, result.Added, result.Removed))
+		}
+	}
+	if len(lines) == 0 {
+		return "All Hugo posts are up to date."
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getLineDiff returns the number of lines added and removed between two texts based on their longest common subsequence.
+func getLineDiff(before, after string) (added, removed int) {
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+	common := make([][]int, len(beforeLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(afterLines)+1)
+	}
+	for i := len(beforeLines) - 1; i >= 0; i-- {
+		for j := len(afterLines) - 1; j >= 0; j-- {
+			if beforeLines[i] == afterLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+	return len(afterLines) - common[0][0], len(beforeLines) - common[0][0]
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeHugoFixture(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Error occurred while creating %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+}
+
+func TestHugo_Regenerate(t *testing.T) {
+	dir := t.TempDir()
+	gist := filepath.Join(dir, "manuscript", "video.md")
+	writeHugoFixture(t, gist, "## Intro\n\nNew manuscript.")
+	hugo := Hugo{}
+	current, err := hugo.getPost(gist, "Current", "2030-01-21T16:00", "abc")
+	if err != nil {
+		t.Fatalf("Error occurred while rendering the post: %v", err)
+	}
+	manual := "\nManually added links.\n"
+	outdated := filepath.Join(dir, "content", "outdated", "_index.md")
+	writeHugoFixture(t, outdated, "+++\ntitle = 'Old'\n+++\n\nOld manuscript.\n"+hugoManualMarker+manual)
+	upToDate := filepath.Join(dir, "content", "current", "_index.md")
+	writeHugoFixture(t, upToDate, current)
+	noMarker := filepath.Join(dir, "content", "no-marker", "_index.md")
+	writeHugoFixture(t, noMarker, "+++\ntitle = 'Manual'\n+++\n")
+	videos := []Video{
+		{Title: "Outdated", Date: "2030-01-21T16:00", Gist: gist, HugoPath: outdated, VideoId: "xyz"},
+		{Title: "Current", Date: "2030-01-21T16:00", Gist: gist, HugoPath: upToDate, VideoId: "abc"},
+		{Title: "No Marker", Date: "2030-01-21T16:00", Gist: gist, HugoPath: noMarker},
+		{Title: "Not Published", Gist: gist},
+	}
+
+	results := hugo.Regenerate(videos, true)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, but got %v", results)
+	}
+	if !results[0].Changed || results[0].Added == 0 || results[0].Removed == 0 {
+		t.Errorf("Expected the outdated post to have a diff, but got %+v", results[0])
+	}
+	if results[1].Changed {
+		t.Errorf("Expected the current post to be unchanged, but got %+v", results[1])
+	}
+	if results[2].Changed || len(results[2].Skipped) == 0 {
+		t.Errorf("Expected the post without the marker to be skipped, but got %+v", results[2])
+	}
+	if content, _ := os.ReadFile(outdated); !strings.Contains(string(content), "Old manuscript.") {
+		t.Errorf("Expected the dry run not to write anything, but got %s", content)
+	}
+
+	hugo.Regenerate(videos, false)
+	content, _ := os.ReadFile(outdated)
+	if !strings.Contains(string(content), "title = 'Outdated'") || !strings.Contains(string(content), "{{< youtube xyz >}}") || !strings.HasSuffix(string(content), hugoManualMarker+manual) {
+		t.Errorf("Expected the post to be regenerated with the manual content preserved, but got %s", content)
+	}
+	if content, _ := os.ReadFile(noMarker); string(content) != "+++\ntitle = 'Manual'\n+++\n" {
+		t.Errorf("Expected the post without the marker to be left alone, but got %s", content)
+	}
+}
+
+func TestHugo_getLineDiff(t *testing.T) {
+	added, removed := getLineDiff("a\nb\nc", "a\nx\nc\nd")
+	if added != 2 || removed != 1 {
+		t.Errorf("Expected +2 -1, but got +%d -%d", added, removed)
+	}
+}
+
+func TestHugo_hugoFromMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	video := Video{Title: "GitOps: What's Next?", Gist: "manuscript/devops/gitops.md"}
+	writeHugoFixture(t, filepath.Join(dir, "content", "devops", "placeholder"), "")
+	hugo := Hugo{}
+	tests := map[string]struct {
+		slug     string
+		expected string
+	}{
+		"derived from the title": {slug: getVideoSlug(video), expected: dir + "/content/devops/gitops-what-s-next/_index.md"},
+		"overridden":             {slug: "gitops-next", expected: dir + "/content/devops/gitops-next/_index.md"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, err := hugo.hugoFromMarkdown(dir, video.Gist, tc.slug, "post")
+			if err != nil {
+				t.Fatalf("Expected no error\nGot: %v", err)
+			}
+			if path != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, path)
+			}
+			if content, _ := os.ReadFile(path); string(content) != "post" {
+				t.Errorf("Expected the post to be written\nGot: %s", content)
+			}
+		})
+	}
+	if _, err := hugo.hugoFromMarkdown(dir, video.Gist, "", "post"); err == nil {
+		t.Errorf("Expected an error without a slug")
+	}
+}
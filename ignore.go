@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// IgnoreMatcher decides which paths under a root directory should be skipped.
+// Patterns follow the .gitignore syntax and are read from .yamignore or, if it does not exist, .gitignore.
+// Dot files and directories are always ignored.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+var manuscriptIgnore *IgnoreMatcher
+var manuscriptIgnoreOnce sync.Once
+
+func getManuscriptIgnore() *IgnoreMatcher {
+	manuscriptIgnoreOnce.Do(func() {
+		matcher, err := NewIgnoreMatcherFromDir("manuscript")
+		if err != nil {
+			output.Error(err.Error())
+			matcher = NewIgnoreMatcher(nil)
+		}
+		manuscriptIgnore = matcher
+	})
+	return manuscriptIgnore
+}
+
+func NewIgnoreMatcherFromDir(root string) (*IgnoreMatcher, error) {
+	for _, name := range []string{".yamignore", ".gitignore"} {
+		file, err := os.Open(filepath.Join(root, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		lines := []string{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return NewIgnoreMatcher(lines), nil
+	}
+	return NewIgnoreMatcher(nil), nil
+}
+
+func NewIgnoreMatcher(lines []string) *IgnoreMatcher {
+	matcher := &IgnoreMatcher{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern := ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			pattern.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		expression := globToRegex(line)
+		if anchored {
+			expression = "^" + expression + "$"
+		} else {
+			expression = "^(.*/)?" + expression + "$"
+		}
+		pattern.regex = regexp.MustCompile(expression)
+		matcher.patterns = append(matcher.patterns, pattern)
+	}
+	return matcher
+}
+
+// Match reports whether the path (relative to the root and using forward slashes) is ignored, either directly or through one of its parent directories.
+func (m *IgnoreMatcher) Match(path string, isDir bool) bool {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	for i := range parts {
+		if m.matchOne(strings.Join(parts[:i+1], "/"), i < len(parts)-1 || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *IgnoreMatcher) matchOne(path string, isDir bool) bool {
+	if strings.HasPrefix(filepath.Base(path), ".") {
+		return true
+	}
+	ignored := false
+	for _, pattern := range m.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if pattern.regex.MatchString(path) {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
+
+// WalkDir walks the root directory skipping everything the matcher ignores.
+func (m *IgnoreMatcher) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fn(path, entry, err)
+		}
+		relPath, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+		if relPath != "." && m.Match(relPath, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(path, entry, err)
+	})
+}
+
+func globToRegex(glob string) string {
+	var builder strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			builder.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**"):
+			builder.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			builder.WriteString("[^/]*")
+		case glob[i] == '?':
+			builder.WriteString("[^/]")
+		case glob[i] == '[':
+			end := strings.Index(glob[i:], "]")
+			if end < 0 {
+				builder.WriteString(regexp.QuoteMeta(glob[i:]))
+				return builder.String()
+			}
+			class := glob[i : i+end+1]
+			if strings.HasPrefix(class, "[!") {
+				class = "[^" + class[2:]
+			}
+			builder.WriteString(class)
+			i += end
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIgnoreMatcher_Match(t *testing.T) {
+	matcher := NewIgnoreMatcher([]string{
+		"# Helpers",
+		"node_modules/",
+		"drafts/*",
+		"!drafts/keep.md",
+		"**/tmp/**",
+		"/archive",
+		"*.bak",
+	})
+	paths := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"category-01", true, false},
+		{"category-01/video.yaml", false, false},
+		{".obsidian", true, true},
+		{"category-01/.hidden.md", false, true},
+		{"category-01/demo/node_modules", true, true},
+		{"category-01/demo/node_modules/pkg/index.js", false, true},
+		{"category-01/node_modules", false, false},
+		{"drafts/idea.md", false, true},
+		{"drafts/keep.md", false, false},
+		{"category-01/tmp/file.md", false, true},
+		{"category-01/a/b/tmp", true, true},
+		{"archive", true, true},
+		{"category-01/archive", true, false},
+		{"category-01/video.md.bak", false, true},
+	}
+	for _, path := range paths {
+		if actual := matcher.Match(path.path, path.isDir); actual != path.expected {
+			t.Errorf("Expected %s to be ignored=%t, but got %t", path.path, path.expected, actual)
+		}
+	}
+}
+
+func TestIgnoreMatcher_WalkDir(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"category-01/demo/node_modules/pkg", ".obsidian", "drafts"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("Error occurred while creating %s: %v", dir, err)
+		}
+	}
+	for _, file := range []string{"category-01/video.yaml", "category-01/demo/node_modules/pkg/video.yaml", ".obsidian/video.yaml", "drafts/idea.yaml", ".yamignore"} {
+		if err := os.WriteFile(filepath.Join(root, file), []byte("node_modules/\ndrafts/\n"), 0644); err != nil {
+			t.Fatalf("Error occurred while writing %s: %v", file, err)
+		}
+	}
+	matcher, err := NewIgnoreMatcherFromDir(root)
+	if err != nil {
+		t.Fatalf("Error occurred while loading the ignore file: %v", err)
+	}
+	actual := []string{}
+	err = matcher.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			relPath, _ := filepath.Rel(root, path)
+			actual = append(actual, relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error occurred while walking %s: %v", root, err)
+	}
+	expected := []string{filepath.Join("category-01", "video.yaml")}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+const importStatusCreated = "created"
+const importStatusDuplicate = "skipped as duplicate"
+const importStatusFailed = "failed"
+
+// importDateFormats are the formats Notion uses in CSV exports. The publish date and day formats are accepted as well.
+var importDateFormats = []string{"January 2, 2006 3:04 PM", "January 2, 2006"}
+
+// importColumns maps the accepted header names to the columns. Notion exports use Name or Title for the page title.
+var importColumns = map[string]string{
+	"name":         "name",
+	"title":        "name",
+	"category":     "category",
+	"date":         "date",
+	"publish date": "date",
+	"notes":        "notes",
+	"note":         "notes",
+	"description":  "notes",
+}
+
+var importDryRun bool
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports data from other tools.",
+}
+
+var importIdeasCmd = &cobra.Command{
+	Use:   "ideas FILE",
+	Short: "Creates videos from a CSV file (name, category, and optional date and notes columns) or a Notion CSV export.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+		defer file.Close()
+		rows, err := parseIdeasCSV(file)
+		if err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+		yaml := YAML{IndexPath: "index.yaml"}
+		options := ImportIdeasOptions{
+			ManuscriptDir:   "manuscript",
+			DryRun:          importDryRun,
+			MaxRows:         settings.Import.MaxRows,
+			DefaultTime:     settings.Schedule.Time,
+			ConfirmCategory: confirmImportCategory,
+			Write:           yaml.writeVideo,
+		}
+		index, results, err := importIdeas(rows, yaml.GetIndex(), options)
+		if err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+		if !importDryRun {
+			yaml.WriteIndex(index)
+		}
+		output.Result(getImportReport(results, importDryRun))
+		os.Exit(0)
+	},
+}
+
+func init() {
+	importIdeasCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Validate the file and report what would be created without changing anything.")
+	importCmd.AddCommand(importIdeasCmd)
+}
+
+type IdeaRow struct {
+	Line     int
+	Name     string
+	Category string
+	Date     string
+	Notes    string
+}
+
+type ImportIdeasOptions struct {
+	ManuscriptDir string
+	DryRun        bool
+	MaxRows       int
+	// DefaultTime is used for dates without time (e.g., 16:00). Midnight is used when it's empty.
+	DefaultTime string
+	// ConfirmCategory is asked once per category that does not exist. Rows in declined categories fail.
+	ConfirmCategory func(category string) bool
+	Write           func(*Video, string) error
+}
+
+type ImportResult struct {
+	Line        int
+	Name        string
+	Category    string
+	NewCategory bool
+	Status      string
+	Reason      string
+}
+
+// parseIdeasCSV requires a header row with at least the name and the category columns. Unknown columns are ignored so that Notion exports with extra properties work.
+func parseIdeasCSV(reader io.Reader) ([]IdeaRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("the file is not a valid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	columns := map[string]int{}
+	for i, header := range records[0] {
+		header = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+		if column, ok := importColumns[header]; ok {
+			if _, exists := columns[column]; !exists {
+				columns[column] = i
+			}
+		}
+	}
+	for _, required := range []string{"name", "category"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("the header row has no %s column", required)
+		}
+	}
+	rows := []IdeaRow{}
+	for i, record := range records[1:] {
+		value := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		row := IdeaRow{Line: i + 2, Name: value("name"), Category: value("category"), Date: value("date"), Notes: value("notes")}
+		if len(row.Name) == 0 && len(row.Category) == 0 && len(row.Date) == 0 && len(row.Notes) == 0 {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseImportDate returns the date in the format used for publish dates.
+func parseImportDate(value, defaultTime string) (string, error) {
+	if date, err := time.Parse(dateFormat, value); err == nil {
+		return date.Format(dateFormat), nil
+	}
+	if date, err := time.Parse(importDateFormats[0], value); err == nil {
+		return date.Format(dateFormat), nil
+	}
+	day, err := time.Parse(dayFormat, value)
+	if err != nil {
+		if day, err = time.Parse(importDateFormats[1], value); err != nil {
+			return "", fmt.Errorf("date %q must be in the %s, %s, or Notion (e.g., January 21, 2030) format", value, dateFormat, dayFormat)
+		}
+	}
+	if len(defaultTime) > 0 {
+		at, err := time.Parse("15:04", defaultTime)
+		if err != nil {
+			return "", err
+		}
+		day = day.Add(time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute)
+	}
+	return day.Format(dateFormat), nil
+}
+
+func getImportCategoryDir(category string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(category)), " ", "-")
+}
+
+// importIdeas creates a video for each row and returns the index with the new entries. Each row either creates all its files or, if any of them fails, none.
+// Nothing is written in a dry run but the rows are validated the same way.
+func importIdeas(rows []IdeaRow, index []VideoIndex, options ImportIdeasOptions) ([]VideoIndex, []ImportResult, error) {
+	if options.MaxRows > 0 && len(rows) > options.MaxRows {
+		return index, nil, fmt.Errorf("the file has %d rows and at most %d can be imported at once", len(rows), options.MaxRows)
+	}
+	index = append([]VideoIndex{}, index...)
+	existing := map[string]bool{}
+	for _, item := range index {
+		existing[getImportCategoryDir(item.Category)+"/"+getVideoFileName(item.Name)] = true
+	}
+	categories := map[string]bool{}
+	results := []ImportResult{}
+	for _, row := range rows {
+		result := ImportResult{Line: row.Line, Name: row.Name, Category: row.Category, Status: importStatusFailed}
+		item, newCategory, err := importIdea(row, options, existing, categories)
+		switch {
+		case errors.Is(err, errImportDuplicate):
+			result.Status = importStatusDuplicate
+			err = nil
+		case err != nil:
+			result.Reason = err.Error()
+		default:
+			result.Status = importStatusCreated
+			result.NewCategory = newCategory
+			index = append(index, item)
+		}
+		if !options.DryRun {
+			output.Event(outputActionImport, fmt.Sprintf("%s/%s", getImportCategoryDir(row.Category), row.Name), result.Status, err)
+		}
+		results = append(results, result)
+	}
+	return index, results, nil
+}
+
+var errImportDuplicate = errors.New("duplicate")
+
+// importIdea returns the index entry of the created video and whether its category was created.
+func importIdea(row IdeaRow, options ImportIdeasOptions, existing, categories map[string]bool) (VideoIndex, bool, error) {
+	if len(row.Name) == 0 {
+		return VideoIndex{}, false, fmt.Errorf("name is required")
+	}
+	category := getImportCategoryDir(row.Category)
+	if len(category) == 0 || strings.ContainsAny(category, `/\`) || strings.HasPrefix(category, ".") {
+		return VideoIndex{}, false, fmt.Errorf("category %q is not valid", row.Category)
+	}
+	name, err := sanitizeVideoName(row.Name, getNameOptions())
+	if err != nil {
+		return VideoIndex{}, false, err
+	}
+	date := ""
+	if len(row.Date) > 0 {
+		if date, err = parseImportDate(row.Date, options.DefaultTime); err != nil {
+			return VideoIndex{}, false, err
+		}
+	}
+	dir := filepath.Join(options.ManuscriptDir, category)
+	key := category + "/" + name
+	if _, found := getExistingVideoPath(dir, name); found || existing[key] {
+		return VideoIndex{}, false, errImportDuplicate
+	}
+	newCategory := false
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		newCategory = true
+		create, asked := categories[category]
+		if !asked {
+			create = options.DryRun || options.ConfirmCategory == nil || options.ConfirmCategory(category)
+			categories[category] = create
+		}
+		if !create {
+			return VideoIndex{}, false, fmt.Errorf("category %s does not exist", category)
+		}
+	}
+	existing[key] = true
+	item := VideoIndex{Name: getIndexedVideoName(row.Name, name), Category: category}
+	if options.DryRun {
+		return item, newCategory, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		delete(existing, key)
+		return VideoIndex{}, false, err
+	}
+	gist := filepath.Join(dir, name+".md")
+	if err := createManuscript(gist); err != nil {
+		delete(existing, key)
+		return VideoIndex{}, false, err
+	}
+	if len(date) > 0 || len(row.Notes) > 0 {
+		video := Video{Name: item.Name, Category: category, Path: filepath.Join(dir, name+".yaml"), Gist: gist, Date: date}
+		if len(row.Notes) > 0 {
+			video.Notes = []string{row.Notes}
+		}
+		if err := options.Write(&video, video.Path); err != nil {
+			os.Remove(gist)
+			os.Remove(video.Path)
+			delete(existing, key)
+			return VideoIndex{}, false, err
+		}
+	}
+	return item, newCategory, nil
+}
+
+func confirmImportCategory(category string) bool {
+	create := false
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Category %s does not exist. Create it?", category)).
+				Affirmative("Create").
+				Negative("Skip its rows").
+				Value(&create),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return false
+	}
+	return create
+}
+
+func getImportReport(results []ImportResult, dryRun bool) string {
+	var builder strings.Builder
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+		status := result.Status
+		if dryRun && status == importStatusCreated {
+			status = "would be created"
+		}
+		line := fmt.Sprintf("Line %d: %s (%s) %s", result.Line, result.Name, result.Category, status)
+		if result.NewCategory {
+			line = fmt.Sprintf("%s in a new category", line)
+		}
+		if len(result.Reason) > 0 {
+			line = fmt.Sprintf("%s: %s", line, result.Reason)
+		}
+		builder.WriteString(line + "\n")
+	}
+	created := "created"
+	if dryRun {
+		created = "would be created (dry run)"
+	}
+	builder.WriteString(fmt.Sprintf("\n%d %s, %d skipped as duplicates, %d failed.", counts[importStatusCreated], created, counts[importStatusDuplicate], counts[importStatusFailed]))
+	return builder.String()
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func getImportTestOptions(t *testing.T) (ImportIdeasOptions, map[string]Video) {
+	dir := filepath.Join(t.TempDir(), "manuscript")
+	if err := os.MkdirAll(filepath.Join(dir, "ai"), 0755); err != nil {
+		t.Fatalf("Error occurred while creating the category: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ai", "existing-file.md"), []byte("## Intro\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing the manuscript: %v", err)
+	}
+	written := map[string]Video{}
+	return ImportIdeasOptions{
+		ManuscriptDir: dir,
+		MaxRows:       20,
+		DefaultTime:   "16:00",
+		Write: func(video *Video, path string) error {
+			if video.Name == "Broken Disk" {
+				return errors.New("disk full")
+			}
+			written[path] = *video
+			return nil
+		},
+	}, written
+}
+
+func getImportStatuses(results []ImportResult) []string {
+	statuses := []string{}
+	for _, result := range results {
+		statuses = append(statuses, result.Status)
+	}
+	return statuses
+}
+
+func TestImportIdeas_parseIdeasCSV(t *testing.T) {
+	notion := "\ufeffName,Tags,Category,Publish Date,Created\nArgo CD,gitops,K8s,\"January 21, 2030\",\"January 1, 2030 10:00 AM\"\n,,,,\n"
+	rows, err := parseIdeasCSV(strings.NewReader(notion))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expected := []IdeaRow{{Line: 2, Name: "Argo CD", Category: "K8s", Date: "January 21, 2030"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, rows)
+	}
+	malformed := []string{
+		"",
+		"name,date\nArgo CD,2030-01-21\n",
+		"title,notes\nArgo CD,GitOps\n",
+		"name,category\n\"Argo CD,k8s\n",
+	}
+	for _, data := range malformed {
+		if _, err := parseIdeasCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected %q to be rejected", data)
+		}
+	}
+}
+
+func TestImportIdeas_parseImportDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"2030-01-21T10:00", "2030-01-21T10:00"},
+		{"2030-01-21", "2030-01-21T16:00"},
+		{"January 21, 2030", "2030-01-21T16:00"},
+		{"January 21, 2030 9:30 AM", "2030-01-21T09:30"},
+	}
+	for _, test := range tests {
+		if actual, err := parseImportDate(test.value, "16:00"); err != nil || actual != test.expected {
+			t.Errorf("%q: Expected: %s\nGot: %s and %v", test.value, test.expected, actual, err)
+		}
+	}
+	if _, err := parseImportDate("21/01/2030", "16:00"); err == nil {
+		t.Errorf("Expected 21/01/2030 to be rejected")
+	}
+}
+
+func TestImportIdeas_importIdeas(t *testing.T) {
+	options, written := getImportTestOptions(t)
+	asked := []string{}
+	options.ConfirmCategory = func(category string) bool {
+		asked = append(asked, category)
+		return category == "new-one"
+	}
+	index := []VideoIndex{{Name: "Existing Index", Category: "ai"}}
+	rows := []IdeaRow{
+		{Line: 2, Name: "First Idea", Category: "AI", Date: "2030-01-21", Notes: "From the spreadsheet"},
+		{Line: 3, Name: "existing index", Category: "ai"},
+		{Line: 4, Name: "Existing File", Category: "ai"},
+		{Line: 5, Name: "First Idea?", Category: "ai"},
+		{Line: 6, Name: "In New", Category: "New One"},
+		{Line: 7, Name: "Also In New", Category: "new one"},
+		{Line: 8, Name: "Declined", Category: "Other"},
+		{Line: 9, Name: "Bad Date", Category: "ai", Date: "someday"},
+		{Line: 10, Name: "", Category: "ai"},
+		{Line: 11, Name: "Escape", Category: "../secrets"},
+		{Line: 12, Name: "Broken Disk", Category: "ai", Notes: "Will fail"},
+	}
+	updated, results, err := importIdeas(rows, index, options)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expected := []string{importStatusCreated, importStatusDuplicate, importStatusDuplicate, importStatusDuplicate, importStatusCreated, importStatusCreated, importStatusFailed, importStatusFailed, importStatusFailed, importStatusFailed, importStatusFailed}
+	if !reflect.DeepEqual(getImportStatuses(results), expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, getImportStatuses(results))
+	}
+	if !reflect.DeepEqual(asked, []string{"new-one", "other"}) {
+		t.Errorf("Expected each missing category to be confirmed once, but got %v", asked)
+	}
+	if !results[4].NewCategory || results[5].NewCategory {
+		t.Errorf("Expected only the row that created the category to report it")
+	}
+	expectedIndex := []VideoIndex{{Name: "Existing Index", Category: "ai"}, {Name: "First Idea", Category: "ai"}, {Name: "In New", Category: "new-one"}, {Name: "Also In New", Category: "new-one"}}
+	if !reflect.DeepEqual(updated, expectedIndex) {
+		t.Errorf("Expected: %v\nGot: %v", expectedIndex, updated)
+	}
+	video := written[filepath.Join(options.ManuscriptDir, "ai", "first-idea.yaml")]
+	if video.Date != "2030-01-21T16:00" || !reflect.DeepEqual(video.Notes, []string{"From the spreadsheet"}) {
+		t.Errorf("Expected the date and the notes to be written, but got %+v", video)
+	}
+	for _, path := range []string{"ai/first-idea.md", "new-one/in-new.md", "new-one/also-in-new.md"} {
+		if !manuscriptExists(filepath.Join(options.ManuscriptDir, path)) {
+			t.Errorf("Expected %s to be created", path)
+		}
+	}
+	if manuscriptExists(filepath.Join(options.ManuscriptDir, "ai", "broken-disk.md")) {
+		t.Errorf("Expected the manuscript of the failed row to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(options.ManuscriptDir, "other")); !os.IsNotExist(err) {
+		t.Errorf("Expected the declined category not to be created")
+	}
+	report := getImportReport(results, false)
+	if !strings.Contains(report, "Line 9: Bad Date (ai) failed: date \"someday\"") || !strings.HasSuffix(report, "3 created, 3 skipped as duplicates, 5 failed.") {
+		t.Errorf("Unexpected report %q", report)
+	}
+}
+
+func TestImportIdeas_importIdeasDryRun(t *testing.T) {
+	options, written := getImportTestOptions(t)
+	options.DryRun = true
+	options.ConfirmCategory = func(category string) bool {
+		t.Errorf("Expected no confirmation in a dry run, but %s was asked", category)
+		return false
+	}
+	rows := []IdeaRow{{Line: 2, Name: "First Idea", Category: "ai", Notes: "Notes"}, {Line: 3, Name: "In New", Category: "new"}, {Line: 4, Name: "first idea", Category: "ai"}}
+	updated, results, err := importIdeas(rows, nil, options)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expected := []string{importStatusCreated, importStatusCreated, importStatusDuplicate}
+	if !reflect.DeepEqual(getImportStatuses(results), expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, getImportStatuses(results))
+	}
+	if len(updated) != 2 || len(written) > 0 || manuscriptExists(filepath.Join(options.ManuscriptDir, "ai", "first-idea.md")) {
+		t.Errorf("Expected nothing to be written in a dry run")
+	}
+	if _, err := os.Stat(filepath.Join(options.ManuscriptDir, "new")); !os.IsNotExist(err) {
+		t.Errorf("Expected the category not to be created in a dry run")
+	}
+	report := getImportReport(results, true)
+	if !strings.Contains(report, "Line 3: In New (new) would be created in a new category") || !strings.HasSuffix(report, "2 would be created (dry run), 1 skipped as duplicates, 0 failed.") {
+		t.Errorf("Unexpected report %q", report)
+	}
+}
+
+func TestImportIdeas_importIdeasMaxRows(t *testing.T) {
+	options, _ := getImportTestOptions(t)
+	options.MaxRows = 1
+	rows := []IdeaRow{{Line: 2, Name: "One", Category: "ai"}, {Line: 3, Name: "Two", Category: "ai"}}
+	if _, results, err := importIdeas(rows, nil, options); err == nil || len(results) > 0 {
+		t.Errorf("Expected the import to be rejected, but got %v and %v", results, err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UnpaidInvoice is an invoice that was sent but not paid. DaysOverdue is zero until the due date has passed.
+type UnpaidInvoice struct {
+	Video       Video
+	Due         time.Time
+	DaysOverdue int
+}
+
+func parseInvoiceDay(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(dayFormat, value)
+}
+
+// validateInvoice checks the invoicing fields of the sponsorship. Empty fields are valid since invoices are recorded over time.
+func validateInvoice(sponsorship Sponsorship) error {
+	if len(sponsorship.AmountInvoiced) > 0 {
+		if _, _, err := parseAmount(sponsorship.AmountInvoiced); err != nil {
+			return err
+		}
+	}
+	dates := map[string]string{"sent": sponsorship.InvoiceSentDate, "due": sponsorship.DueDate, "paid": sponsorship.PaidDate}
+	for _, name := range []string{"sent", "due", "paid"} {
+		if _, err := parseInvoiceDay(dates[name]); err != nil {
+			return fmt.Errorf("%s date %q must be in the %s format", name, dates[name], dayFormat)
+		}
+	}
+	if len(sponsorship.InvoiceSentDate) == 0 && (len(sponsorship.DueDate) > 0 || len(sponsorship.PaidDate) > 0) {
+		return fmt.Errorf("the sent date is required for invoices with a due or a paid date")
+	}
+	sent, _ := parseInvoiceDay(sponsorship.InvoiceSentDate)
+	if due, _ := parseInvoiceDay(sponsorship.DueDate); !due.IsZero() && due.Before(sent) {
+		return fmt.Errorf("due date %s is before the sent date %s", sponsorship.DueDate, sponsorship.InvoiceSentDate)
+	}
+	if paid, _ := parseInvoiceDay(sponsorship.PaidDate); !paid.IsZero() && paid.Before(sent) {
+		return fmt.Errorf("paid date %s is before the sent date %s", sponsorship.PaidDate, sponsorship.InvoiceSentDate)
+	}
+	return nil
+}
+
+// getInvoiceDueDate returns the due date or, when it is not set, the sent date plus the payment terms. It is zero if the invoice was not sent.
+func getInvoiceDueDate(sponsorship Sponsorship, termDays int) time.Time {
+	if due, err := parseInvoiceDay(sponsorship.DueDate); err == nil && !due.IsZero() {
+		return due
+	}
+	sent, err := parseInvoiceDay(sponsorship.InvoiceSentDate)
+	if err != nil || sent.IsZero() {
+		return time.Time{}
+	}
+	return sent.AddDate(0, 0, termDays)
+}
+
+func isInvoicePaid(sponsorship Sponsorship) bool {
+	return len(sponsorship.PaidDate) > 0
+}
+
+// getUnpaidInvoices returns the invoices that were sent but not paid, sorted by the due date.
+func getUnpaidInvoices(videos []Video, now time.Time, termDays int) []UnpaidInvoice {
+	unpaid := []UnpaidInvoice{}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, video := range videos {
+		if isInvoicePaid(video.Sponsorship) {
+			continue
+		}
+		due := getInvoiceDueDate(video.Sponsorship, termDays)
+		if due.IsZero() {
+			continue
+		}
+		invoice := UnpaidInvoice{Video: video, Due: due}
+		if today.After(due) {
+			invoice.DaysOverdue = int(today.Sub(due).Hours() / 24)
+		}
+		unpaid = append(unpaid, invoice)
+	}
+	sort.SliceStable(unpaid, func(i, j int) bool {
+		return unpaid[i].Due.Before(unpaid[j].Due)
+	})
+	return unpaid
+}
+
+func getOverdueInvoices(unpaid []UnpaidInvoice) []UnpaidInvoice {
+	overdue := []UnpaidInvoice{}
+	for _, invoice := range unpaid {
+		if invoice.DaysOverdue > 0 {
+			overdue = append(overdue, invoice)
+		}
+	}
+	return overdue
+}
+
+func getInvoiceTitle(invoice UnpaidInvoice) string {
+	number := invoice.Video.Sponsorship.InvoiceNumber
+	if len(number) == 0 {
+		number = "without a number"
+	}
+	amount := invoice.Video.Sponsorship.AmountInvoiced
+	if len(amount) == 0 {
+		amount = invoice.Video.Sponsorship.Amount
+	}
+	return fmt.Sprintf("%s: invoice %s, %s, due %s", invoice.Video.Name, number, amount, invoice.Due.Format(dayFormat))
+}
+
+func getUnpaidInvoicesText(unpaid []UnpaidInvoice) string {
+	if len(unpaid) == 0 {
+		return "There are no unpaid invoices."
+	}
+	lines := []string{"Unpaid invoices:"}
+	for _, invoice := range unpaid {
+		line := getInvoiceTitle(invoice)
+		if invoice.DaysOverdue > 0 {
+			line = redStyle.Render(fmt.Sprintf("%s (%d days overdue)", line, invoice.DaysOverdue))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func getOverdueInvoicesWarning(unpaid []UnpaidInvoice) string {
+	overdue := getOverdueInvoices(unpaid)
+	if len(overdue) == 0 {
+		return ""
+	}
+	invoices := []string{}
+	for _, invoice := range overdue {
+		invoices = append(invoices, fmt.Sprintf("%s (%dd)", invoice.Video.Name, invoice.DaysOverdue))
+	}
+	return fmt.Sprintf("Overdue invoices: %s", strings.Join(invoices, ", "))
+}
+
+// getInvoiceReminder drafts the follow-up to the sponsor contacts about an unpaid invoice.
+func getInvoiceReminder(video Video, due time.Time) (string, string) {
+	number := video.Sponsorship.InvoiceNumber
+	subject := fmt.Sprintf("Invoice %s: DevOps Toolkit Video Sponsorship", number)
+	amount := video.Sponsorship.AmountInvoiced
+	if len(amount) == 0 {
+		amount = video.Sponsorship.Amount
+	}
+	body := fmt.Sprintf(`Hi,
+<br><br>
+I wanted to follow up on invoice %s for %s, sent on %s for the sponsorship of the video "%s". It was due on %s and I could not find the payment yet.
+<br><br>
+Could you please check its status and let me know when I can expect it?
+`, number, amount, video.Sponsorship.InvoiceSentDate, video.Title, due.Format(dayFormat))
+	return subject, body
+}
+
+func (e *Email) SendInvoiceReminder(ctx context.Context, from string, video Video, due time.Time) error {
+	subject, body := getInvoiceReminder(video, due)
+	return e.Send(ctx, from, strings.Split(video.Sponsorship.Emails, ","), subject, body, "")
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvoices_getUnpaidInvoices(t *testing.T) {
+	now := time.Date(2030, 3, 10, 16, 0, 0, 0, time.UTC)
+	videos := []Video{
+		{Name: "paid", Sponsorship: Sponsorship{InvoiceSentDate: "2030-01-01", PaidDate: "2030-02-15"}},
+		{Name: "net-60", Sponsorship: Sponsorship{InvoiceSentDate: "2030-01-01"}},
+		{Name: "due-today", Sponsorship: Sponsorship{InvoiceSentDate: "2030-02-01", DueDate: "2030-03-10"}},
+		{Name: "not-sent", Sponsorship: Sponsorship{Amount: "1000"}},
+		{Name: "due-later", Sponsorship: Sponsorship{InvoiceSentDate: "2030-03-01"}},
+		{Name: "explicit-due", Sponsorship: Sponsorship{InvoiceSentDate: "2030-02-20", DueDate: "2030-03-05"}},
+	}
+	unpaid := getUnpaidInvoices(videos, now, 60)
+	type result struct {
+		name string
+		due  string
+		days int
+	}
+	actual := []result{}
+	for _, invoice := range unpaid {
+		actual = append(actual, result{invoice.Video.Name, invoice.Due.Format(dayFormat), invoice.DaysOverdue})
+	}
+	expected := []result{
+		{"net-60", "2030-03-02", 8},
+		{"explicit-due", "2030-03-05", 5},
+		{"due-today", "2030-03-10", 0},
+		{"due-later", "2030-04-30", 0},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+	if warning := getOverdueInvoicesWarning(unpaid); warning != "Overdue invoices: net-60 (8d), explicit-due (5d)" {
+		t.Errorf("Unexpected warning %s", warning)
+	}
+	if warning := getOverdueInvoicesWarning(unpaid[2:]); len(warning) > 0 {
+		t.Errorf("Expected no warning without overdue invoices\nGot: %s", warning)
+	}
+}
+
+func TestInvoices_validateInvoice(t *testing.T) {
+	tests := map[string]struct {
+		sponsorship Sponsorship
+		expected    string
+	}{
+		"empty":          {},
+		"sent":           {sponsorship: Sponsorship{InvoiceNumber: "2030-007", InvoiceSentDate: "2030-01-01", AmountInvoiced: "$1,500"}},
+		"paid":           {sponsorship: Sponsorship{InvoiceSentDate: "2030-01-01", DueDate: "2030-03-01", PaidDate: "2030-02-15"}},
+		"invalid amount": {sponsorship: Sponsorship{AmountInvoiced: "fifteen hundred"}, expected: `amount "fifteen hundred" is not a number`},
+		"invalid date":   {sponsorship: Sponsorship{InvoiceSentDate: "01/01/2030"}, expected: `sent date "01/01/2030" must be in the 2006-01-02 format`},
+		"paid not sent":  {sponsorship: Sponsorship{PaidDate: "2030-02-15"}, expected: "the sent date is required for invoices with a due or a paid date"},
+		"due before":     {sponsorship: Sponsorship{InvoiceSentDate: "2030-01-10", DueDate: "2030-01-01"}, expected: "due date 2030-01-01 is before the sent date 2030-01-10"},
+		"paid before":    {sponsorship: Sponsorship{InvoiceSentDate: "2030-01-10", PaidDate: "2030-01-01"}, expected: "paid date 2030-01-01 is before the sent date 2030-01-10"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := ""
+			if err := validateInvoice(tc.sponsorship); err != nil {
+				actual = err.Error()
+			}
+			if actual != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestInvoices_getInvoiceReminder(t *testing.T) {
+	video := Video{
+		Title:       "Argo CD Explained",
+		Sponsorship: Sponsorship{Amount: "$2,000", Emails: "sponsor@example.com", InvoiceNumber: "2030-007", InvoiceSentDate: "2030-01-01", AmountInvoiced: "$1,800"},
+	}
+	subject, body := getInvoiceReminder(video, time.Date(2030, 3, 2, 0, 0, 0, 0, time.UTC))
+	if subject != "Invoice 2030-007: DevOps Toolkit Video Sponsorship" {
+		t.Errorf("Unexpected subject %s", subject)
+	}
+	for _, expected := range []string{"invoice 2030-007 for $1,800", "sent on 2030-01-01", `the video "Argo CD Explained"`, "due on 2030-03-02"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the body to contain %s\nGot: %s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// legacyNormalizedVersion is the first schema version whose files were written after legacy values were normalized.
+// Older files are normalized in memory when they are read and written in the canonical form only when they are saved.
+const legacyNormalizedVersion = 2
+
+const legacyDefaultTime = "16:00"
+
+const legacyRuleBool = "boolean"
+const legacyRuleDate = "date"
+const legacyRuleAmount = "amount"
+
+// legacyAmountSpaceRegex finds whitespace (including non-breaking and thin spaces) used as a thousands separator.
+var legacyAmountSpaceRegex = regexp.MustCompile(`(\d)[\s\x{00A0}\x{2009}\x{202F}]+(\d)`)
+
+var legacyBools = map[string]bool{"true": true, "yes": true, "y": true, "on": true, "false": false, "no": false, "n": false, "off": false}
+
+var migrationsCmd = &cobra.Command{
+	Use:   "migrations",
+	Short: "Inspects how video files written by older versions are migrated.",
+}
+
+var migrationsReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Lists the legacy values of all videos that will be normalized when they are saved and those that need a manual review. Nothing is written.",
+	Run: func(cmd *cobra.Command, args []string) {
+		yaml := YAML{IndexPath: "index.yaml"}
+		choices := Choices{}
+		for _, item := range yaml.GetIndex() {
+			path := choices.GetFilePath(item.Category, item.Name, "yaml")
+			if _, err := readVideo(path); err != nil {
+				output.Error(err.Error())
+			}
+		}
+		output.ResultText(getLegacyReportText(legacyReport))
+	},
+}
+
+func init() {
+	migrationsCmd.AddCommand(migrationsReportCmd)
+}
+
+// LegacyCoercion is a legacy value that was converted into its canonical form. Field is the YAML path (e.g., sponsorship.amount).
+type LegacyCoercion struct {
+	Rule  string
+	Field string
+	From  string
+	To    string
+}
+
+// LegacyReview is a legacy value that could not be converted confidently. Values that cannot be decoded at all are left unset.
+type LegacyReview struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+type LegacyNormalization struct {
+	Coercions []LegacyCoercion
+	Reviews   []LegacyReview
+}
+
+// LegacyReport keeps the normalizations of the files read in this session, keyed by their paths.
+type LegacyReport struct {
+	mu    sync.Mutex
+	Files map[string]LegacyNormalization
+}
+
+var legacyReport = NewLegacyReport()
+
+func NewLegacyReport() *LegacyReport {
+	return &LegacyReport{Files: map[string]LegacyNormalization{}}
+}
+
+// Add replaces what was recorded for the path since the file might have changed since it was last read.
+func (r *LegacyReport) Add(path string, normalization LegacyNormalization) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(normalization.Coercions) == 0 && len(normalization.Reviews) == 0 {
+		delete(r.Files, path)
+		return
+	}
+	r.Files[path] = normalization
+}
+
+// RuleCounts returns how many values each rule coerced across all files.
+func (r *LegacyReport) RuleCounts() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := map[string]int{}
+	for _, normalization := range r.Files {
+		for _, coercion := range normalization.Coercions {
+			counts[coercion.Rule]++
+		}
+	}
+	return counts
+}
+
+func getLegacyReportText(report *LegacyReport) string {
+	if len(report.Files) == 0 {
+		return "There are no legacy values."
+	}
+	paths := []string{}
+	for path := range report.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	counts := report.RuleCounts()
+	lines := []string{fmt.Sprintf("Legacy values in %d files are normalized in memory and written only when the videos are saved.", len(paths))}
+	for _, rule := range []string{legacyRuleBool, legacyRuleDate, legacyRuleAmount} {
+		if counts[rule] > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %d", rule, counts[rule]))
+		}
+	}
+	reviews := []string{}
+	for _, path := range paths {
+		normalization := report.Files[path]
+		for _, coercion := range normalization.Coercions {
+			lines = append(lines, fmt.Sprintf("%s %s: %q -> %q", path, coercion.Field, coercion.From, coercion.To))
+		}
+		for _, review := range normalization.Reviews {
+			reviews = append(reviews, fmt.Sprintf("%s %s: %q %s", path, review.Field, review.Value, review.Reason))
+		}
+	}
+	if len(reviews) > 0 {
+		lines = append(lines, "", "Review manually:")
+		lines = append(lines, reviews...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getNodeSchemaVersion returns the schema version of a video document without decoding the rest of it.
+func getNodeSchemaVersion(document *yaml.Node) int {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return 0
+	}
+	mapping := document.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == "schemaversion" {
+			var version int
+			if err := mapping.Content[i+1].Decode(&version); err == nil {
+				return version
+			}
+		}
+	}
+	return 0
+}
+
+// normalizeLegacyVideo coerces legacy values of a video document in place so that it can be decoded into the current structure.
+func normalizeLegacyVideo(document *yaml.Node) LegacyNormalization {
+	normalization := LegacyNormalization{}
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		normalizeLegacyNode(document.Content[0], reflect.TypeOf(Video{}), "", &normalization)
+	}
+	return normalization
+}
+
+func normalizeLegacyNode(node *yaml.Node, t reflect.Type, path string, normalization *LegacyNormalization) {
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := map[string]reflect.StructField{}
+		for i := 0; i < t.NumField(); i++ {
+			fields[strings.ToLower(t.Field(i).Name)] = t.Field(i)
+		}
+		content := []*yaml.Node{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if ok && !normalizeLegacyField(value, field.Type, strings.TrimPrefix(path+"."+key.Value, "."), normalization) {
+				continue
+			}
+			content = append(content, key, value)
+		}
+		node.Content = content
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			normalizeLegacyNode(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), normalization)
+		}
+	}
+}
+
+// normalizeLegacyField returns false if the value cannot be decoded and has to be removed.
+func normalizeLegacyField(node *yaml.Node, t reflect.Type, path string, normalization *LegacyNormalization) bool {
+	if node.Kind != yaml.ScalarNode {
+		normalizeLegacyNode(node, t, path, normalization)
+		return true
+	}
+	switch {
+	case t.Kind() == reflect.Bool && node.Tag != "!!bool" && node.Tag != "!!null":
+		value, ok := legacyBools[strings.ToLower(strings.TrimSpace(node.Value))]
+		if !ok {
+			normalization.Reviews = append(normalization.Reviews, LegacyReview{Field: path, Value: node.Value, Reason: "is not a boolean and was left unset"})
+			return false
+		}
+		normalization.Coercions = append(normalization.Coercions, LegacyCoercion{Rule: legacyRuleBool, Field: path, From: node.Value, To: fmt.Sprint(value)})
+		setLegacyScalar(node, "!!bool", fmt.Sprint(value))
+	case path == "date":
+		if to, ok, review := normalizeLegacyDate(node.Value); review {
+			normalization.Reviews = append(normalization.Reviews, LegacyReview{Field: path, Value: node.Value, Reason: fmt.Sprintf("is not a date in the %s format", dateFormat)})
+		} else if ok {
+			normalization.Coercions = append(normalization.Coercions, LegacyCoercion{Rule: legacyRuleDate, Field: path, From: node.Value, To: to})
+			setLegacyScalar(node, "!!str", to)
+		}
+	case path == "sponsorship.amount" || path == "sponsored":
+		if to, ok, review := normalizeLegacyAmount(node.Value); review {
+			normalization.Reviews = append(normalization.Reviews, LegacyReview{Field: path, Value: node.Value, Reason: "is not an amount"})
+		} else if ok {
+			normalization.Coercions = append(normalization.Coercions, LegacyCoercion{Rule: legacyRuleAmount, Field: path, From: node.Value, To: to})
+			setLegacyScalar(node, "!!str", to)
+		}
+	}
+	return true
+}
+
+func setLegacyScalar(node *yaml.Node, tag, value string) {
+	node.Tag = tag
+	node.Value = value
+	node.Style = 0
+}
+
+// normalizeLegacyDate returns the canonical date and whether it differs from the value. Review is true if the value is not a date at all.
+func normalizeLegacyDate(value string) (string, bool, bool) {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return value, false, false
+	}
+	if _, err := time.Parse(dateFormat, trimmed); err == nil {
+		return trimmed, trimmed != value, false
+	}
+	if date, err := time.Parse(dayFormat, trimmed); err == nil {
+		return fmt.Sprintf("%sT%s", date.Format(dayFormat), legacyDefaultTime), true, false
+	}
+	if date, err := time.Parse("2006-01-02 15:04", trimmed); err == nil {
+		return date.Format(dateFormat), true, false
+	}
+	return value, false, true
+}
+
+// normalizeLegacyAmount removes whitespace used as a thousands separator. Placeholders like N/A are left alone and anything else with digits that cannot be parsed needs a review.
+func normalizeLegacyAmount(value string) (string, bool, bool) {
+	trimmed := strings.TrimSpace(value)
+	if _, _, err := parseAmount(trimmed); err == nil || !strings.ContainsAny(trimmed, "0123456789") {
+		return trimmed, len(trimmed) > 0 && trimmed != value, false
+	}
+	normalized := trimmed
+	for legacyAmountSpaceRegex.MatchString(normalized) {
+		normalized = legacyAmountSpaceRegex.ReplaceAllString(normalized, "$1$2")
+	}
+	if _, _, err := parseAmount(normalized); err != nil {
+		return value, false, true
+	}
+	return normalized, true, false
+}
+
+// readLegacyVideo decodes the video file, normalizing legacy values first if it was written before they were normalized.
+func readLegacyVideo(path string, data []byte, video *Video) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	if document.Kind == 0 {
+		return nil
+	}
+	if getNodeSchemaVersion(&document) < legacyNormalizedVersion {
+		legacyReport.Add(path, normalizeLegacyVideo(&document))
+	}
+	return document.Decode(video)
+}
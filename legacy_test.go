@@ -0,0 +1,206 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLegacy_normalizeLegacyDate(t *testing.T) {
+	tests := map[string]struct {
+		value      string
+		expected   string
+		changed    bool
+		needReview bool
+	}{
+		"canonical":     {value: "2024-05-06T16:00", expected: "2024-05-06T16:00"},
+		"empty":         {value: "", expected: ""},
+		"day only":      {value: "2024-05-06", expected: "2024-05-06T16:00", changed: true},
+		"space":         {value: "2024-05-06 09:30", expected: "2024-05-06T09:30", changed: true},
+		"padded":        {value: " 2024-05-06T16:00 ", expected: "2024-05-06T16:00", changed: true},
+		"unrecognized":  {value: "next Tuesday", expected: "next Tuesday", needReview: true},
+		"day and month": {value: "06/05/2024", expected: "06/05/2024", needReview: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, changed, review := normalizeLegacyDate(tc.value)
+			if got != tc.expected || changed != tc.changed || review != tc.needReview {
+				t.Errorf("Expected: %q, %t, %t\nGot: %q, %t, %t", tc.expected, tc.changed, tc.needReview, got, changed, review)
+			}
+		})
+	}
+}
+
+func TestLegacy_normalizeLegacyAmount(t *testing.T) {
+	tests := map[string]struct {
+		value      string
+		expected   string
+		changed    bool
+		needReview bool
+	}{
+		"canonical":    {value: "$1000", expected: "$1000"},
+		"space":        {value: "$1 000", expected: "$1000", changed: true},
+		"non-breaking": {value: "1 000 EUR", expected: "1000 EUR", changed: true},
+		"millions":     {value: "$1 000 000", expected: "$1000000", changed: true},
+		"placeholder":  {value: "N/A", expected: "N/A"},
+		"empty":        {value: "", expected: ""},
+		"two amounts":  {value: "$500 + $300", expected: "$500 + $300", needReview: true},
+		"padded":       {value: " $500 ", expected: "$500", changed: true},
+		"abbreviated":  {value: "1.5k USD", expected: "1.5k USD", needReview: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, changed, review := normalizeLegacyAmount(tc.value)
+			if got != tc.expected || changed != tc.changed || review != tc.needReview {
+				t.Errorf("Expected: %q, %t, %t\nGot: %q, %t, %t", tc.expected, tc.changed, tc.needReview, got, changed, review)
+			}
+		})
+	}
+}
+
+func TestLegacy_normalizeLegacyVideo(t *testing.T) {
+	tests := map[string]struct {
+		data      string
+		coercions []LegacyCoercion
+		reviews   []LegacyReview
+		expected  Video
+	}{
+		"quoted booleans": {
+			data:      "name: my-video\nhead: \"true\"\nscreen: \"False\"\n",
+			coercions: []LegacyCoercion{{Rule: legacyRuleBool, Field: "head", From: "true", To: "true"}, {Rule: legacyRuleBool, Field: "screen", From: "False", To: "false"}},
+			expected:  Video{Name: "my-video", Head: true},
+		},
+		"yes and no": {
+			data:      "head: yes\nscreen: no\n",
+			coercions: []LegacyCoercion{{Rule: legacyRuleBool, Field: "head", From: "yes", To: "true"}, {Rule: legacyRuleBool, Field: "screen", From: "no", To: "false"}},
+			expected:  Video{Head: true},
+		},
+		"unknown boolean": {
+			data:     "name: my-video\nhead: maybe\n",
+			reviews:  []LegacyReview{{Field: "head", Value: "maybe", Reason: "is not a boolean and was left unset"}},
+			expected: Video{Name: "my-video"},
+		},
+		"nested amount": {
+			data:      "sponsorship:\n  amount: $1 000\n",
+			coercions: []LegacyCoercion{{Rule: legacyRuleAmount, Field: "sponsorship.amount", From: "$1 000", To: "$1000"}},
+			expected:  Video{Sponsorship: Sponsorship{Amount: "$1000"}},
+		},
+		"date": {
+			data:      "date: 2024-05-06\n",
+			coercions: []LegacyCoercion{{Rule: legacyRuleDate, Field: "date", From: "2024-05-06", To: "2024-05-06T16:00"}},
+			expected:  Video{Date: "2024-05-06T16:00"},
+		},
+		"unrecognized date": {
+			data:     "date: soon\n",
+			reviews:  []LegacyReview{{Field: "date", Value: "soon", Reason: "is not a date in the 2006-01-02T15:04 format"}},
+			expected: Video{Date: "soon"},
+		},
+		"canonical": {
+			data:     "name: my-video\nhead: true\ndate: 2024-05-06T16:00\nsponsorship:\n  amount: N/A\n",
+			expected: Video{Name: "my-video", Head: true, Date: "2024-05-06T16:00", Sponsorship: Sponsorship{Amount: "N/A"}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var document yaml.Node
+			if err := yaml.Unmarshal([]byte(tc.data), &document); err != nil {
+				t.Fatalf("Error occurred while parsing the data: %v", err)
+			}
+			normalization := normalizeLegacyVideo(&document)
+			if len(normalization.Coercions) != len(tc.coercions) || (len(tc.coercions) > 0 && !reflect.DeepEqual(normalization.Coercions, tc.coercions)) {
+				t.Errorf("Expected: %+v\nGot: %+v", tc.coercions, normalization.Coercions)
+			}
+			if len(normalization.Reviews) != len(tc.reviews) || (len(tc.reviews) > 0 && !reflect.DeepEqual(normalization.Reviews, tc.reviews)) {
+				t.Errorf("Expected: %+v\nGot: %+v", tc.reviews, normalization.Reviews)
+			}
+			var video Video
+			if err := document.Decode(&video); err != nil {
+				t.Fatalf("Expected the normalized document to decode, but got %v", err)
+			}
+			if !reflect.DeepEqual(video, tc.expected) {
+				t.Errorf("Expected: %+v\nGot: %+v", tc.expected, video)
+			}
+		})
+	}
+}
+
+func TestLegacy_LegacyReport(t *testing.T) {
+	report := NewLegacyReport()
+	report.Add("a.yaml", LegacyNormalization{Coercions: []LegacyCoercion{
+		{Rule: legacyRuleBool, Field: "head", From: "yes", To: "true"},
+		{Rule: legacyRuleDate, Field: "date", From: "2024-05-06", To: "2024-05-06T16:00"},
+	}})
+	report.Add("b.yaml", LegacyNormalization{
+		Coercions: []LegacyCoercion{{Rule: legacyRuleBool, Field: "screen", From: "no", To: "false"}},
+		Reviews:   []LegacyReview{{Field: "date", Value: "soon", Reason: "is not a date"}},
+	})
+	report.Add("c.yaml", LegacyNormalization{})
+	expected := map[string]int{legacyRuleBool: 2, legacyRuleDate: 1}
+	if got := report.RuleCounts(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, got)
+	}
+	if len(report.Files) != 2 {
+		t.Errorf("Expected: files without legacy values not to be reported\nGot: %v", report.Files)
+	}
+	text := getLegacyReportText(report)
+	for _, expected := range []string{"in 2 files", "boolean: 2", "date: 1", `a.yaml head: "yes" -> "true"`, "Review manually:", `b.yaml date: "soon" is not a date`} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected: %q in the report\nGot: %s", expected, text)
+		}
+	}
+	report.Add("b.yaml", LegacyNormalization{})
+	if _, ok := report.Files["b.yaml"]; ok {
+		t.Errorf("Expected: a file that was saved since not to be reported\nGot: %v", report.Files)
+	}
+	if got := getLegacyReportText(NewLegacyReport()); got != "There are no legacy values." {
+		t.Errorf("Expected: There are no legacy values.\nGot: %s", got)
+	}
+}
+
+func TestLegacy_readVideoNoWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	legacy := "name: my-video\ndate: 2024-05-06\nhead: \"true\"\nsponsorship:\n  amount: $1 000\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	defer delete(legacyReport.Files, path)
+	video, err := readVideo(path)
+	if err != nil {
+		t.Fatalf("Expected the legacy video to be read, but got %v", err)
+	}
+	if video.Date != "2024-05-06T16:00" || !video.Head || video.Sponsorship.Amount != "$1000" {
+		t.Errorf("Expected: normalized values\nGot: %+v", video)
+	}
+	if got := len(legacyReport.Files[path].Coercions); got != 3 {
+		t.Errorf("Expected: 3 coercions in the report\nGot: %d", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	if string(data) != legacy {
+		t.Errorf("Expected: the file not to be written until the video is saved\nGot: %q", string(data))
+	}
+}
+
+func TestLegacy_readVideoCurrentVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	data := "schemaversion: 2\nname: my-video\ndate: 2024-05-06\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	video, err := readVideo(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if video.Date != "2024-05-06" {
+		t.Errorf("Expected: files at the current version not to be normalized\nGot: %s", video.Date)
+	}
+	if _, ok := legacyReport.Files[path]; ok {
+		t.Errorf("Expected: %s not to be reported", path)
+	}
+}
@@ -9,5 +9,5 @@ import (
 func postLinkedIn(message, videoId string) {
 	message = strings.ReplaceAll(message, "[YouTube Link]", getYouTubeURL(videoId))
 	clipboard.WriteAll(message)
-	println(confirmationStyle.Render("The message has be copied to clipboard. Please paste it into LinkedIn manually."))
+	output.Result("The message has be copied to clipboard. Please paste it into LinkedIn manually.")
 }
@@ -3,6 +3,8 @@ package main
 func main() {
 	getArgs()
 	choices := Choices{}
+	choices.ChooseOnboarding()
+	choices.ChooseResumeFocus()
 	for {
 		choices.ChooseIndex()
 	}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const manuscriptTemplate = `## Intro
+
+FIXME: Welcome to DevOps Toolkit, the channel where we...
+
+FIXME: Explanation...
+
+FIXME: This is...
+
+FIXME: It's supposed to...
+
+## Setup
+
+FIXME:
+
+## FIXME:
+
+FIXME:
+
+## FIXME: Pros and Cons
+
+TODO: Header: Cons; Items: FIXME:
+
+TODO: Header: Pros; Items: FIXME:
+
+## Destroy
+
+FIXME:
+`
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ManuscriptEncoding describes characteristics of a manuscript file that are lost when the content is normalized.
+type ManuscriptEncoding struct {
+	BOM  bool
+	CRLF bool
+}
+
+// detectManuscriptEncoding reports whether the content starts with a UTF-8 BOM and whether most of its lines end with CRLF.
+func detectManuscriptEncoding(data []byte) ManuscriptEncoding {
+	lf := bytes.Count(data, []byte("\n"))
+	crlf := bytes.Count(data, []byte("\r\n"))
+	return ManuscriptEncoding{
+		BOM:  bytes.HasPrefix(data, utf8BOM),
+		CRLF: crlf > 0 && crlf*2 >= lf,
+	}
+}
+
+// normalizeManuscript strips the BOM and converts all line endings to LF.
+func normalizeManuscript(data []byte) string {
+	content := string(bytes.TrimPrefix(data, utf8BOM))
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.ReplaceAll(content, "\r", "\n")
+}
+
+// readManuscript returns the normalized content of a manuscript together with its original encoding so that it can be restored on write.
+func readManuscript(path string) (string, ManuscriptEncoding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ManuscriptEncoding{}, err
+	}
+	return normalizeManuscript(data), detectManuscriptEncoding(data), nil
+}
+
+func writeManuscript(path, content string, encoding ManuscriptEncoding) error {
+	content = normalizeManuscript([]byte(content))
+	if encoding.CRLF {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	data := []byte(content)
+	if encoding.BOM {
+		data = append(append([]byte{}, utf8BOM...), data...)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// getManuscriptPath returns the path of the manuscript next to the YAML file of a video.
+// Only the extension is replaced so that ".yaml" elsewhere in the path (e.g., a directory named my.yaml.videos) is kept.
+func getManuscriptPath(yamlPath string) string {
+	return strings.TrimSuffix(yamlPath, filepath.Ext(yamlPath)) + ".md"
+}
+
+func manuscriptExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// createManuscript writes the manuscript template to the path. Existing files are never overwritten.
+func createManuscript(path string) error {
+	return writeNewManuscript(path, manuscriptTemplate)
+}
+
+func writeNewManuscript(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(content)
+	return err
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const manuscriptFixture = "# [[title]] #\n\nTODO: Logo: nix.png\n\n## Intro\n\nTODO: Thumbnail: abc\n\n## Nix Pros and Cons\n\nTODO: Logos: jenkins.png\n"
+
+func writeManuscriptFixture(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	return path
+}
+
+func getManuscriptFixtures() map[string][]byte {
+	crlf := []byte(toCRLF(manuscriptFixture))
+	return map[string][]byte{
+		"lf":       []byte(manuscriptFixture),
+		"crlf":     crlf,
+		"bom":      append(append([]byte{}, utf8BOM...), manuscriptFixture...),
+		"bom-crlf": append(append([]byte{}, utf8BOM...), crlf...),
+	}
+}
+
+func toCRLF(content string) string {
+	return strings.ReplaceAll(content, "\n", "\r\n")
+}
+
+func TestManuscript_detectManuscriptEncoding(t *testing.T) {
+	expected := map[string]ManuscriptEncoding{
+		"lf":       {},
+		"crlf":     {CRLF: true},
+		"bom":      {BOM: true},
+		"bom-crlf": {BOM: true, CRLF: true},
+	}
+	for name, data := range getManuscriptFixtures() {
+		if actual := detectManuscriptEncoding(data); actual != expected[name] {
+			t.Errorf("Expected %s to be detected as %v, but got %v", name, expected[name], actual)
+		}
+	}
+}
+
+func TestManuscript_getAnimationsFromMarkdown(t *testing.T) {
+	expectedAnimations := []string{"Logo: nix.png", "Thumbnail: abc", "Section: Nix Pros and Cons", "Logos: jenkins.png"}
+	expectedSections := []string{"Section: Nix Pros and Cons"}
+	repo := &Repo{}
+	for name, data := range getManuscriptFixtures() {
+		path := writeManuscriptFixture(t, "video.md", data)
+		animations, sections, err := repo.getAnimationsFromMarkdown(path)
+		if err != nil {
+			t.Fatalf("Error occurred while getting animations from %s: %v", name, err)
+		}
+		if !reflect.DeepEqual(animations, expectedAnimations) {
+			t.Errorf("%s\nExpected: %q\nGot: %q", name, expectedAnimations, animations)
+		}
+		if !reflect.DeepEqual(sections, expectedSections) {
+			t.Errorf("%s\nExpected: %q\nGot: %q", name, expectedSections, sections)
+		}
+	}
+}
+
+func TestManuscript_getAnimationsFromScript(t *testing.T) {
+	script := "\xef\xbb\xbf#!/bin/sh\r\n\r\n# TODO: Logo: nix.png\r\n\r\n# Nix #\r\n"
+	path := writeManuscriptFixture(t, "video.sh", []byte(script))
+	repo := &Repo{}
+	animations, sections, err := repo.getAnimationsFromScript(path)
+	if err != nil {
+		t.Fatalf("Error occurred while getting animations: %v", err)
+	}
+	expectedAnimations := []string{"Logo: nix.png", "Section: Nix"}
+	if !reflect.DeepEqual(animations, expectedAnimations) || !reflect.DeepEqual(sections, []string{"Section: Nix"}) {
+		t.Errorf("Expected: %q\nGot: %q and %q", expectedAnimations, animations, sections)
+	}
+}
+
+func TestManuscript_CleanupGistPreservesEncoding(t *testing.T) {
+	gist := "# Title #\n\n# Additional Info:\n# https://example.com\n\n##########\n# Setup #\n##########\n\n# FIXME: Comment\n\nkubectl get pods\n"
+	for name, data := range map[string][]byte{
+		"lf":       []byte(gist),
+		"bom-crlf": append(append([]byte{}, utf8BOM...), toCRLF(gist)...),
+	} {
+		path := writeManuscriptFixture(t, "gist.sh", data)
+		repo := &Repo{}
+		if err := repo.CleanupGist(path); err != nil {
+			t.Fatalf("Error occurred while cleaning up %s: %v", name, err)
+		}
+		output, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Error occurred while reading %s: %v", path, err)
+		}
+		if actual, expected := detectManuscriptEncoding(output), detectManuscriptEncoding(data); actual != expected {
+			t.Errorf("Expected %s to keep the encoding %v, but got %v", name, expected, actual)
+		}
+		if normalized, _, _ := readManuscript(path); normalized != normalizeManuscript(mustCleanupGist(t, []byte(gist))) {
+			t.Errorf("Expected %s to be cleaned up the same way as the LF version, but got %q", name, normalized)
+		}
+	}
+}
+
+func mustCleanupGist(t *testing.T, data []byte) []byte {
+	path := writeManuscriptFixture(t, "expected.sh", data)
+	repo := &Repo{}
+	if err := repo.CleanupGist(path); err != nil {
+		t.Fatalf("Error occurred while cleaning up %s: %v", path, err)
+	}
+	output, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	return output
+}
+
+func TestManuscript_getManuscriptPath(t *testing.T) {
+	paths := map[string]string{
+		"manuscript/devops/my-video.yaml":            "manuscript/devops/my-video.md",
+		"manuscript/my.yaml.videos/my-video.yaml":    "manuscript/my.yaml.videos/my-video.md",
+		"manuscript/devops/my-video.yaml-notes.yaml": "manuscript/devops/my-video.yaml-notes.md",
+	}
+	for path, expected := range paths {
+		if actual := getManuscriptPath(path); actual != expected {
+			t.Errorf("Expected: %s\nGot: %s", expected, actual)
+		}
+	}
+}
+
+func TestManuscript_createManuscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devops", "my-video.md")
+	if manuscriptExists(path) {
+		t.Fatalf("Expected %s not to exist", path)
+	}
+	if err := createManuscript(path); err != nil {
+		t.Fatalf("Error occurred while creating the manuscript: %v", err)
+	}
+	if !manuscriptExists(path) {
+		t.Fatalf("Expected %s to exist", path)
+	}
+	content, _, err := readManuscript(path)
+	if err != nil || content != manuscriptTemplate {
+		t.Errorf("Expected the template to be written, but got %q (%v)", content, err)
+	}
+	if err := os.WriteFile(path, []byte("# Edited"), 0644); err != nil {
+		t.Fatalf("Error occurred while editing the manuscript: %v", err)
+	}
+	if err := createManuscript(path); err == nil {
+		t.Errorf("Expected an error when the manuscript already exists")
+	}
+	if content, _, _ := readManuscript(path); content != "# Edited" {
+		t.Errorf("Expected the existing manuscript to be kept, but got %q", content)
+	}
+	if manuscriptExists(filepath.Dir(path)) {
+		t.Errorf("Expected directories not to be treated as manuscripts")
+	}
+}
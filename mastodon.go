@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Mastodon posts statuses with an access token of an application created in the account preferences (Development) with the write:statuses and write:media scopes.
+// Requests that fail because of the network, 5xx, or 429 are retried with an exponential backoff and jitter. Statuses are posted with an idempotency key
+// so that a retry of a request that reached the server does not post the status twice.
+// Retried is the number of retries made by the client so far.
+type Mastodon struct {
+	client   *http.Client
+	settings SettingsMastodon
+	backoff  time.Duration
+	Retried  int
+}
+
+type mastodonError struct {
+	Error string `json:"error"`
+}
+
+// ErrMastodonAuth means that the server rejected the access token. It is not retried since it's a configuration problem.
+type ErrMastodonAuth struct {
+	Status  string
+	Message string
+}
+
+func (e *ErrMastodonAuth) Error() string {
+	return fmt.Sprintf("Mastodon rejected the access token (%s): %s", e.Status, e.Message)
+}
+
+// ErrMastodonRejected means that the server rejected the request as invalid (e.g., a status that is too long). It is not retried since it would fail again.
+type ErrMastodonRejected struct {
+	Status  string
+	Message string
+}
+
+func (e *ErrMastodonRejected) Error() string {
+	return fmt.Sprintf("Mastodon rejected the request (%s): %s", e.Status, e.Message)
+}
+
+type mastodonResponseError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *mastodonResponseError) Error() string {
+	if len(e.Message) > 0 {
+		return fmt.Sprintf("Mastodon responded with %s: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("Mastodon responded with %s", e.Status)
+}
+
+func NewMastodon(settings SettingsMastodon) *Mastodon {
+	return &Mastodon{
+		client:   &http.Client{Timeout: 60 * time.Second},
+		settings: settings,
+		backoff:  time.Second,
+	}
+}
+
+func isMastodonEnabled(settings SettingsMastodon) bool {
+	return len(settings.Server) > 0
+}
+
+// getMastodonStatus replaces the YouTube link placeholder of the message or, if there is none, appends the link.
+func getMastodonStatus(message, videoId string) string {
+	link := getYouTubeURL(videoId)
+	if strings.Contains(message, "[YouTube Link]") {
+		return strings.ReplaceAll(message, "[YouTube Link]", link)
+	}
+	return fmt.Sprintf("%s\n\n%s", strings.TrimSpace(message), link)
+}
+
+// isMastodonRetryable is true for network errors and for responses that might succeed later.
+func isMastodonRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.As(err, new(*ErrMastodonAuth)) || errors.As(err, new(*ErrMastodonRejected)) {
+		return false
+	}
+	responseErr := &mastodonResponseError{}
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode >= 500 || responseErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// getMastodonBackoff doubles the delay with each attempt and adds up to half of it as jitter so that retries of concurrent runs do not align.
+func getMastodonBackoff(backoff time.Duration, attempt int, random *rand.Rand) time.Duration {
+	delay := backoff << (attempt - 1)
+	return delay + time.Duration(random.Int63n(int64(delay)/2+1))
+}
+
+// do sends the request up to settings.MaxAttempts times. The body is a slice so that it can be sent again, and the header is the same in all attempts.
+func (m *Mastodon) do(ctx context.Context, method, path string, header http.Header, body []byte, result any) error {
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for attempt := 1; ; attempt++ {
+		err := m.doOnce(ctx, method, path, header, body, result)
+		if err == nil || attempt >= m.settings.MaxAttempts || !isMastodonRetryable(ctx, err) {
+			return err
+		}
+		m.Retried++
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(getMastodonBackoff(m.backoff, attempt, random)):
+		}
+	}
+}
+
+func (m *Mastodon) doOnce(ctx context.Context, method, path string, header http.Header, body []byte, result any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(m.settings.Server, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	request.Header.Set("Authorization", "Bearer "+m.settings.AccessToken)
+	response, err := m.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		mastodonErr := mastodonError{}
+		json.NewDecoder(response.Body).Decode(&mastodonErr)
+		switch response.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return &ErrMastodonAuth{Status: response.Status, Message: mastodonErr.Error}
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return &ErrMastodonRejected{Status: response.Status, Message: mastodonErr.Error}
+		}
+		return &mastodonResponseError{StatusCode: response.StatusCode, Status: response.Status, Message: mastodonErr.Error}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// VerifyCredentials checks that the server accepts the access token.
+func (m *Mastodon) VerifyCredentials(ctx context.Context) error {
+	return m.do(ctx, http.MethodGet, "/api/v1/accounts/verify_credentials", nil, nil, nil)
+}
+
+// UploadMedia uploads the image and returns its ID to be attached to a status.
+func (m *Mastodon) UploadMedia(ctx context.Context, path, description string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return "", err
+	}
+	if err := writer.WriteField("description", description); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	media := struct {
+		ID string `json:"id"`
+	}{}
+	header := http.Header{"Content-Type": {writer.FormDataContentType()}}
+	if err := m.do(ctx, http.MethodPost, "/api/v2/media", header, body.Bytes(), &media); err != nil {
+		return "", err
+	}
+	return media.ID, nil
+}
+
+// CreatePost posts the status with the attached media and returns the URL of the post.
+// The idempotency key is derived from the status so that the server ignores the same status posted again, whether by a retry or by another run.
+func (m *Mastodon) CreatePost(ctx context.Context, status string, mediaIDs []string) (string, error) {
+	form := url.Values{"status": {status}}
+	for _, id := range mediaIDs {
+		form.Add("media_ids[]", id)
+	}
+	posted := struct {
+		URL string `json:"url"`
+	}{}
+	header := http.Header{
+		"Content-Type":    {"application/x-www-form-urlencoded"},
+		"Idempotency-Key": {getMastodonIdempotencyKey(status)},
+	}
+	if err := m.do(ctx, http.MethodPost, "/api/v1/statuses", header, []byte(form.Encode()), &posted); err != nil {
+		return "", err
+	}
+	return posted.URL, nil
+}
+
+func getMastodonIdempotencyKey(status string) string {
+	sum := sha256.Sum256([]byte(status))
+	return hex.EncodeToString(sum[:])
+}
+
+// PostVideo posts the tweet of the video with its link and, if enabled, its thumbnail. A thumbnail that cannot be uploaded does not stop the post.
+func (m *Mastodon) PostVideo(ctx context.Context, video Video) (string, error) {
+	mediaIDs := []string{}
+	if m.settings.AttachThumbnail && len(video.Thumbnail) > 0 {
+		id, err := m.UploadMedia(ctx, video.Thumbnail, video.Title)
+		if err != nil {
+			output.Warn(fmt.Sprintf("The thumbnail was not attached to the Mastodon post: %s", err))
+		} else {
+			mediaIDs = append(mediaIDs, id)
+		}
+	}
+	status := getMastodonStatus(replaceHighlightPlaceholder(video.Tweet, video.VideoId, video.HighlightTimestamp), video.VideoId)
+	return m.CreatePost(ctx, status, mediaIDs)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startFakeMastodonServer serves the media and the statuses endpoints and records the statuses that were posted. Media uploads fail if failMedia is set.
+// Statuses posted again with the same idempotency key are not recorded.
+func startFakeMastodonServer(t *testing.T, failMedia bool, posted *[]string, mediaIDs *[]string) *httptest.Server {
+	keys := map[string]bool{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/media", func(w http.ResponseWriter, r *http.Request) {
+		if failMedia {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error": "File type not supported"}`)
+			return
+		}
+		if _, _, err := r.FormFile("file"); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"id": "media-1"}`)
+	})
+	mux.HandleFunc("/api/v1/statuses", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "The access token is invalid"}`)
+			return
+		}
+		key := r.Header.Get("Idempotency-Key")
+		if len(key) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "The idempotency key is missing"}`)
+			return
+		}
+		if keys[key] {
+			fmt.Fprint(w, `{"url": "https://mastodon.example.com/@me/1"}`)
+			return
+		}
+		keys[key] = true
+		r.ParseForm()
+		*posted = append(*posted, r.FormValue("status"))
+		*mediaIDs = append(*mediaIDs, r.Form["media_ids[]"]...)
+		fmt.Fprint(w, `{"url": "https://mastodon.example.com/@me/1"}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMastodon_getMastodonStatus(t *testing.T) {
+	tests := map[string]struct {
+		message  string
+		expected string
+	}{
+		"append":      {message: "New video! ", expected: "New video!\n\nhttps://youtu.be/123"},
+		"placeholder": {message: "Watch [YouTube Link] now", expected: "Watch https://youtu.be/123 now"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := getMastodonStatus(tc.message, "123"); got != tc.expected {
+				t.Errorf("Expected: %q\nGot: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMastodon_PostVideo(t *testing.T) {
+	thumbnail := filepath.Join(t.TempDir(), "thumbnail.png")
+	if err := os.WriteFile(thumbnail, []byte("png"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", thumbnail, err)
+	}
+	video := Video{Title: "My video", Tweet: "New video!", VideoId: "123", Thumbnail: thumbnail}
+	tests := map[string]struct {
+		token           string
+		attachThumbnail bool
+		failMedia       bool
+		expectedMedia   []string
+		err             bool
+	}{
+		"without thumbnail":       {token: "token"},
+		"with thumbnail":          {token: "token", attachThumbnail: true, expectedMedia: []string{"media-1"}},
+		"thumbnail upload failed": {token: "token", attachThumbnail: true, failMedia: true},
+		"invalid token":           {token: "wrong", err: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			posted := []string{}
+			mediaIDs := []string{}
+			server := startFakeMastodonServer(t, tc.failMedia, &posted, &mediaIDs)
+			mastodon := NewMastodon(SettingsMastodon{Server: server.URL + "/", AccessToken: tc.token, AttachThumbnail: tc.attachThumbnail})
+			url, err := mastodon.PostVideo(context.Background(), video)
+			if tc.err {
+				if err == nil || !strings.Contains(err.Error(), "The access token is invalid") {
+					t.Errorf("Expected: the error of the server\nGot: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected: no error\nGot: %v", err)
+			}
+			if url != "https://mastodon.example.com/@me/1" || len(posted) != 1 || posted[0] != "New video!\n\nhttps://youtu.be/123" {
+				t.Errorf("Expected: the status with the link to be posted\nGot: %s %v", url, posted)
+			}
+			if strings.Join(mediaIDs, ",") != strings.Join(tc.expectedMedia, ",") {
+				t.Errorf("Expected: media %v\nGot: %v", tc.expectedMedia, mediaIDs)
+			}
+		})
+	}
+}
+
+func TestMastodon_PostVideoRetries(t *testing.T) {
+	thumbnail := filepath.Join(t.TempDir(), "thumbnail.png")
+	if err := os.WriteFile(thumbnail, []byte("png"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", thumbnail, err)
+	}
+	video := Video{Title: "My video", Tweet: "Watch [YouTube Link]", VideoId: "123", Thumbnail: thumbnail}
+	tests := map[string]struct {
+		status          int
+		expectedRetried int
+		expectedPosted  []string
+		expectedMedia   []string
+		authErr         bool
+		rejected        bool
+	}{
+		"bad gateway":       {status: http.StatusBadGateway, expectedRetried: 4, expectedPosted: []string{"Watch https://youtu.be/123"}, expectedMedia: []string{"media-1"}},
+		"too many requests": {status: http.StatusTooManyRequests, expectedRetried: 4, expectedPosted: []string{"Watch https://youtu.be/123"}, expectedMedia: []string{"media-1"}},
+		"unauthorized":      {status: http.StatusUnauthorized, expectedRetried: 0, authErr: true},
+		"bad request":       {status: http.StatusBadRequest, expectedRetried: 0, rejected: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			posted := []string{}
+			mediaIDs := []string{}
+			fake := startFakeMastodonServer(t, false, &posted, &mediaIDs)
+			// Each endpoint fails twice before the request is passed to the fake server.
+			failures := map[string]int{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if failures[r.URL.Path] < 2 {
+					failures[r.URL.Path]++
+					w.WriteHeader(tc.status)
+					return
+				}
+				fake.Config.Handler.ServeHTTP(w, r)
+			}))
+			t.Cleanup(server.Close)
+			mastodon := NewMastodon(SettingsMastodon{Server: server.URL, AccessToken: "token", AttachThumbnail: true, MaxAttempts: 3})
+			mastodon.backoff = time.Millisecond
+			_, err := mastodon.PostVideo(context.Background(), video)
+			if tc.authErr != errors.As(err, new(*ErrMastodonAuth)) {
+				t.Errorf("Expected: an authentication error %t\nGot: %v", tc.authErr, err)
+			}
+			if tc.rejected != errors.As(err, new(*ErrMastodonRejected)) {
+				t.Errorf("Expected: a rejected request %t\nGot: %v", tc.rejected, err)
+			}
+			if !tc.authErr && !tc.rejected && err != nil {
+				t.Fatalf("Expected: no error\nGot: %v", err)
+			}
+			if mastodon.Retried != tc.expectedRetried {
+				t.Errorf("Expected: %d retries\nGot: %d", tc.expectedRetried, mastodon.Retried)
+			}
+			if strings.Join(posted, ",") != strings.Join(tc.expectedPosted, ",") || strings.Join(mediaIDs, ",") != strings.Join(tc.expectedMedia, ",") {
+				t.Errorf("Expected: %v with media %v\nGot: %v with media %v", tc.expectedPosted, tc.expectedMedia, posted, mediaIDs)
+			}
+		})
+	}
+}
+
+func TestMastodon_PostVideoRetriesExhausted(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+	mastodon := NewMastodon(SettingsMastodon{Server: server.URL, AccessToken: "token", MaxAttempts: 2})
+	mastodon.backoff = time.Millisecond
+	if _, err := mastodon.PostVideo(context.Background(), Video{Tweet: "New video!", VideoId: "123"}); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Expected: the last error of the server\nGot: %v", err)
+	}
+	if requests != 2 || mastodon.Retried != 1 {
+		t.Errorf("Expected: 2 requests with 1 retry\nGot: %d requests with %d retries", requests, mastodon.Retried)
+	}
+}
+
+func TestMastodon_CreatePostTwice(t *testing.T) {
+	posted := []string{}
+	mediaIDs := []string{}
+	server := startFakeMastodonServer(t, false, &posted, &mediaIDs)
+	mastodon := NewMastodon(SettingsMastodon{Server: server.URL, AccessToken: "token", MaxAttempts: 1})
+	for _, status := range []string{"New video!", "New video!", "Another video!"} {
+		if _, err := mastodon.CreatePost(context.Background(), status, nil); err != nil {
+			t.Fatalf("Expected: no error\nGot: %v", err)
+		}
+	}
+	if expected := []string{"New video!", "Another video!"}; strings.Join(posted, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected: %v\nGot: %v", expected, posted)
+	}
+}
+
+func TestMastodon_getMastodonBackoff(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for attempt, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		actual := getMastodonBackoff(time.Second, attempt, random)
+		if actual < expected || actual > expected+expected/2 {
+			t.Errorf("Attempt %d\nExpected: between %s and %s\nGot: %s", attempt, expected, expected+expected/2, actual)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// parseMembers accepts members separated by commas, new lines, or both and returns them deduplicated in the canonical (alphabetical) order.
+func parseMembers(text string) []string {
+	members := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		for _, member := range strings.Split(line, ",") {
+			member = strings.TrimSpace(member)
+			if len(member) == 0 || containsMember(members, member) {
+				continue
+			}
+			members = append(members, member)
+		}
+	}
+	slices.SortFunc(members, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return members
+}
+
+func formatMembers(members []string) string {
+	return strings.Join(members, ", ")
+}
+
+// containsMember matches names exactly since YouTube display names that differ only in the case belong to different members.
+func containsMember(members []string, member string) bool {
+	return slices.Contains(members, member)
+}
+
+// getRenderedMembers returns the members that can be thanked publicly. Those who opted out (members.exclude) are never included.
+func getRenderedMembers(text string, exclude []string) []string {
+	rendered := []string{}
+	for _, member := range parseMembers(text) {
+		if !containsMember(exclude, member) {
+			rendered = append(rendered, member)
+		}
+	}
+	return rendered
+}
+
+func getMembersDescription(members []string) string {
+	if len(members) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("▬▬▬▬▬▬ 🙏 Thanks to our members 🙏 ▬▬▬▬▬▬\n%s\n\n", formatMembers(members))
+}
+
+func getOutroWithMembers(outro string, members []string) string {
+	if len(members) == 0 {
+		return outro
+	}
+	thanks := fmt.Sprintf("Thanks to our members: %s.", formatMembers(members))
+	if len(strings.TrimSpace(outro)) == 0 {
+		return thanks
+	}
+	return fmt.Sprintf("%s\n\n%s", outro, thanks)
+}
+
+// getMembersDiff returns members that joined and those that left since the stored list.
+func getMembersDiff(stored, current []string) (added, removed []string) {
+	for _, member := range current {
+		if !containsMember(stored, member) {
+			added = append(added, member)
+		}
+	}
+	for _, member := range stored {
+		if !containsMember(current, member) {
+			removed = append(removed, member)
+		}
+	}
+	return added, removed
+}
+
+// fetchChannelMembers lists current members through the YouTube Memberships API which is available only to channels with memberships enabled.
+func fetchChannelMembers(ctx context.Context, service *youtube.Service) ([]string, error) {
+	names := []string{}
+	call := service.Members.List([]string{"snippet"}).Mode("all_current").MaxResults(1000)
+	err := call.Pages(ctx, func(response *youtube.MemberListResponse) error {
+		for _, member := range response.Items {
+			if member.Snippet != nil && member.Snippet.MemberDetails != nil {
+				names = append(names, member.Snippet.MemberDetails.DisplayName)
+			}
+		}
+		return nil
+	})
+	apiErr := &googleapi.Error{}
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden && slices.ContainsFunc(apiErr.Errors, func(item googleapi.ErrorItem) bool {
+		return item.Reason == "insufficientPermissions"
+	}) {
+		return nil, fmt.Errorf("%w: the token was created without the channel memberships scope", ErrYouTubeReconsent)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error listing channel members: %w", err)
+	}
+	return parseMembers(strings.Join(names, "\n")), nil
+}
+
+func getChannelMembers(ctx context.Context) ([]string, error) {
+	service, err := youtube.New(getClient())
+	if err != nil {
+		return nil, fmt.Errorf("Error creating YouTube client: %v", err)
+	}
+	return fetchChannelMembers(ctx, service)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestMembers_parseMembers(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"", []string{}},
+		{"Jane Doe, bob, Alice", []string{"Alice", "bob", "Jane Doe"}},
+		{"Jane Doe\nbob\n\nAlice\n", []string{"Alice", "bob", "Jane Doe"}},
+		{"Jane Doe, bob\nalice,\n bob ", []string{"alice", "bob", "Jane Doe"}},
+		{"bob, Bob", []string{"bob", "Bob"}},
+	}
+	for _, test := range tests {
+		if actual := parseMembers(test.text); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Text %q\nExpected: %v\nGot: %v", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestMembers_getRenderedMembers(t *testing.T) {
+	members := getRenderedMembers("Jane Doe, Bob, bob, Alice", []string{"Bob"})
+	if formatMembers(members) != "Alice, bob, Jane Doe" {
+		t.Errorf("Expected only Bob to be excluded, but got %v", members)
+	}
+	video := Video{Title: "Something", Members: "Jane Doe, Bob"}
+	settings.Members.Exclude = []string{"Bob"}
+	defer func() { settings.Members.Exclude = nil }()
+	description := getUploadRequest(video, UploadStatus{}).Snippet.Description
+	if !strings.Contains(description, "Thanks to our members 🙏 ▬▬▬▬▬▬\nJane Doe\n") || strings.Contains(description, "Bob") {
+		t.Errorf("Expected only Jane Doe to be thanked in the description, but got:\n%s", description)
+	}
+	if outro := getOutroWithMembers("Bye.", getRenderedMembers(video.Members, settings.Members.Exclude)); outro != "Bye.\n\nThanks to our members: Jane Doe." {
+		t.Errorf("Unexpected outro %q", outro)
+	}
+	if description := getUploadRequest(Video{Members: "Bob"}, UploadStatus{}).Snippet.Description; strings.Contains(description, "Thanks to our members") {
+		t.Errorf("Expected no members block when all members are excluded")
+	}
+}
+
+func TestMembers_fetchChannelMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"items": [{"snippet": {"memberDetails": {"displayName": "Jane Doe"}}}, {"snippet": {"memberDetails": {"displayName": "Carol"}}}], "nextPageToken": "2"}`))
+			return
+		}
+		w.Write([]byte(`{"items": [{"snippet": {"memberDetails": {"displayName": "alice"}}}]}`))
+	}))
+	defer server.Close()
+	service, err := youtube.New(server.Client())
+	if err != nil {
+		t.Fatalf("Error occurred while creating the service: %v", err)
+	}
+	service.BasePath = server.URL + "/"
+	current, err := fetchChannelMembers(context.Background(), service)
+	if err != nil {
+		t.Fatalf("Expected the members to be listed, but got %v", err)
+	}
+	if !reflect.DeepEqual(current, []string{"alice", "Carol", "Jane Doe"}) {
+		t.Errorf("Unexpected members %v", current)
+	}
+	added, removed := getMembersDiff(parseMembers("Jane Doe, Bob, alice"), current)
+	if !reflect.DeepEqual(added, []string{"Carol"}) || !reflect.DeepEqual(removed, []string{"Bob"}) {
+		t.Errorf("Expected Carol to be added and Bob removed, but got %v and %v", added, removed)
+	}
+}
+
+func TestMembers_fetchChannelMembersWithoutScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": 403, "errors": [{"reason": "insufficientPermissions"}]}}`))
+	}))
+	defer server.Close()
+	service, err := youtube.New(server.Client())
+	if err != nil {
+		t.Fatalf("Error occurred while creating the service: %v", err)
+	}
+	service.BasePath = server.URL + "/"
+	if _, err := fetchChannelMembers(context.Background(), service); !errors.Is(err, ErrYouTubeReconsent) {
+		t.Errorf("Expected: %v\nGot: %v", ErrYouTubeReconsent, err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const moveResolutionAbort = 0
+const moveResolutionRename = 1
+const moveResolutionOverwrite = 2
+
+type ErrMoveCollision struct {
+	Paths         []string
+	SuggestedName string
+}
+
+func (e *ErrMoveCollision) Error() string {
+	return fmt.Sprintf("the destination already contains %s", strings.Join(e.Paths, ", "))
+}
+
+func getExistingVideoPaths(dir, name string) []string {
+	paths := []string{}
+	for _, extension := range []string{"yaml", "md"} {
+		path := filepath.Join(dir, fmt.Sprintf("%s.%s", name, extension))
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// moveVideo moves the YAML and the manuscript of the video to the directory of another category and returns the video with updated paths.
+// Nothing is changed when the destination already has files with the same name, unless the resolution is to rename the moved video or to overwrite the existing files.
+// Renamed videos take their material directory with them if it is not taken as well.
+// The moved video is written the same way as any other save so that the search index, the phase summary, and the history follow it.
+func moveVideo(video Video, category, targetDir string, resolution int) (Video, error) {
+	sourceYaml := video.Path
+	sourceMd := strings.TrimSuffix(sourceYaml, ".yaml") + ".md"
+	if filepath.Clean(filepath.Dir(sourceYaml)) == filepath.Clean(targetDir) {
+		return video, fmt.Errorf("video %s is already in %s", video.Name, targetDir)
+	}
+	name := strings.TrimSuffix(filepath.Base(sourceYaml), ".yaml")
+	collisions := getExistingVideoPaths(targetDir, name)
+	if len(collisions) > 0 {
+		suggestedName := ""
+		if collision, ok := checkVideoNameCollision(targetDir, name).(*ErrNameCollision); ok {
+			suggestedName = collision.SuggestedName
+		}
+		switch resolution {
+		case moveResolutionRename:
+			if len(suggestedName) == 0 {
+				return video, fmt.Errorf("no other name was found for %s in %s", name, targetDir)
+			}
+			name = suggestedName
+		case moveResolutionOverwrite:
+			for _, path := range collisions {
+				if err := os.Remove(path); err != nil {
+					return video, err
+				}
+			}
+		default:
+			return video, &ErrMoveCollision{Paths: collisions, SuggestedName: suggestedName}
+		}
+	}
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return video, err
+	}
+	moved := video
+	moved.Category = category
+	moved.Path = filepath.Join(targetDir, name+".yaml")
+	targetMd := filepath.Join(targetDir, name+".md")
+	if filepath.Clean(video.Gist) == filepath.Clean(sourceMd) {
+		moved.Gist = targetMd
+	}
+	renamed := len(collisions) > 0 && resolution == moveResolutionRename
+	sourceMaterial := getMaterialDir(video)
+	moveMaterial := false
+	if renamed {
+		moved.Name = name
+		if len(video.Location) == 0 {
+			if _, err := os.Stat(sourceMaterial); err == nil {
+				if _, err := os.Stat(getMaterialDir(moved)); os.IsNotExist(err) {
+					moveMaterial = true
+				} else {
+					moved.Location = sourceMaterial
+				}
+			}
+		}
+	}
+	// The history is moved first so that the save is recorded in it. It is not worth failing the move for.
+	os.Rename(getAuditLogPath(sourceYaml), getAuditLogPath(moved.Path))
+	yaml := YAML{}
+	if err := yaml.writeMovedVideo(&moved, moved.Path, video); err != nil {
+		os.Rename(getAuditLogPath(moved.Path), getAuditLogPath(sourceYaml))
+		return video, err
+	}
+	if _, err := os.Stat(sourceMd); err == nil {
+		if err := os.Rename(sourceMd, targetMd); err != nil {
+			os.Remove(moved.Path)
+			os.Rename(getAuditLogPath(moved.Path), getAuditLogPath(sourceYaml))
+			return video, err
+		}
+	}
+	if moveMaterial {
+		if err := os.Rename(sourceMaterial, getMaterialDir(moved)); err != nil {
+			return moved, err
+		}
+	}
+	if err := os.Remove(sourceYaml); err != nil && !os.IsNotExist(err) {
+		return moved, err
+	}
+	removeFromSearchIndex(sourceYaml)
+	return moved, nil
+}
+
+// setMovedIndexEntry points the index entry of the moved video to its new location and removes the entry of the video it overwrote, if any.
+func setMovedIndexEntry(vi []VideoIndex, index int, entry VideoIndex) []VideoIndex {
+	updated := []VideoIndex{}
+	for i, item := range vi {
+		if i == index {
+			updated = append(updated, entry)
+		} else if item != entry {
+			updated = append(updated, item)
+		}
+	}
+	return updated
+}
+
+// MoveResult is the outcome of moving one of the videos in a batch. Moved is the video with updated paths if Err is nil.
+type MoveResult struct {
+	Video Video
+	Moved Video
+	Err   error
+}
+
+// moveVideos moves each of the videos to the category and updates the index entries of those that were moved.
+// Failures, including collisions, do not stop the batch; colliding videos are left in place so that they can be moved one by one.
+func moveVideos(vi []VideoIndex, videos []Video, category, targetDir string) []MoveResult {
+	results := []MoveResult{}
+	for _, video := range videos {
+		moved, err := moveVideo(video, category, targetDir, moveResolutionAbort)
+		output.Event(outputActionMove, video.Path, moved.Path, err)
+		if err == nil {
+			vi[video.Index] = VideoIndex{Name: moved.Name, Category: category}
+		}
+		results = append(results, MoveResult{Video: video, Moved: moved, Err: err})
+	}
+	return results
+}
+
+func getMoveResultsText(results []MoveResult) string {
+	moved := 0
+	lines := []string{}
+	for _, result := range results {
+		if result.Err != nil {
+			lines = append(lines, redStyle.Render(fmt.Sprintf("%s: %v", result.Video.Name, result.Err)))
+			continue
+		}
+		moved++
+		lines = append(lines, fmt.Sprintf("%s: %s", result.Video.Name, result.Moved.Path))
+	}
+	return strings.Join(append([]string{fmt.Sprintf("Moved %d of %d videos.", moved, len(results))}, lines...), "\n")
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeMoveTestVideo(t *testing.T, dir, name string) Video {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Error occurred while creating %s: %v", dir, err)
+	}
+	path := filepath.Join(dir, name+".yaml")
+	video := Video{Name: name, Path: path, Category: filepath.Base(dir), Gist: filepath.Join(dir, name+".md"), Title: name}
+	yaml := YAML{}
+	yaml.WriteVideo(&video, path)
+	if err := os.WriteFile(video.Gist, []byte("# "+dir), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", video.Gist, err)
+	}
+	return video
+}
+
+func assertMoveFiles(t *testing.T, exist []string, missing []string) {
+	for _, path := range exist {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to exist, but got %v", path, err)
+		}
+	}
+	for _, path := range missing {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s not to exist", path)
+		}
+	}
+}
+
+func TestMove_moveVideo(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "drafts")
+	target := filepath.Join(root, "kubernetes")
+	video := writeMoveTestVideo(t, source, "my-video")
+	video.Gist = filepath.Join(source, ".", "my-video.md")
+	moved, err := moveVideo(video, "kubernetes", target, moveResolutionAbort)
+	if err != nil {
+		t.Fatalf("Expected the video to be moved, but got %v", err)
+	}
+	assertMoveFiles(t, []string{filepath.Join(target, "my-video.yaml"), filepath.Join(target, "my-video.md")}, []string{video.Path, filepath.Join(source, "my-video.md")})
+	yaml := YAML{}
+	stored := yaml.GetVideo(moved.Path)
+	if stored.Path != filepath.Join(target, "my-video.yaml") || stored.Gist != filepath.Join(target, "my-video.md") || stored.Category != "kubernetes" || stored.Name != "my-video" {
+		t.Errorf("Expected the paths inside the YAML to be updated, but got %s %s %s %s", stored.Path, stored.Gist, stored.Category, stored.Name)
+	}
+	if _, err := moveVideo(moved, "kubernetes", target, moveResolutionAbort); err == nil {
+		t.Errorf("Expected an error when moving the video to the directory it is already in")
+	}
+}
+
+// The moved video is the same video, so it must not fire the rules of new videos or be recorded as created again.
+func TestMove_moveVideoCreatedRule(t *testing.T) {
+	settingsOrig := settings
+	defer func() { settings = settingsOrig }()
+	root := t.TempDir()
+	source := filepath.Join(root, "drafts")
+	target := filepath.Join(root, "kubernetes")
+	video := writeMoveTestVideo(t, source, "my-video")
+	settings.Rules = []Rule{{
+		Name:    "created",
+		Trigger: RuleTrigger{Type: ruleTriggerCreated},
+		Actions: []RuleAction{{Type: ruleActionAddNote, Note: "{{.Name}} was created"}},
+	}}
+	moved, err := moveVideo(video, "kubernetes", target, moveResolutionAbort)
+	if err != nil {
+		t.Fatalf("Expected the video to be moved, but got %v", err)
+	}
+	if stored, _ := readVideo(moved.Path); len(stored.Notes) > 0 {
+		t.Errorf("Expected: the created rule not to fire\nGot: %v", stored.Notes)
+	}
+	entries, _ := readAuditLog(moved.Path)
+	creates := 0
+	for _, entry := range entries {
+		if entry.Action == auditActionCreate {
+			creates++
+		}
+	}
+	if creates != 1 {
+		t.Errorf("Expected: 1 create entry\nGot: %+v", entries)
+	}
+}
+
+func TestMove_moveVideoCollision(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "drafts")
+	target := filepath.Join(root, "kubernetes")
+	video := writeMoveTestVideo(t, source, "my-video")
+	existing := writeMoveTestVideo(t, target, "my-video")
+
+	_, err := moveVideo(video, "kubernetes", target, moveResolutionAbort)
+	var collision *ErrMoveCollision
+	if !errors.As(err, &collision) {
+		t.Fatalf("Expected a collision, but got %v", err)
+	}
+	expectedPaths := []string{existing.Path, existing.Gist}
+	if !slices.Equal(collision.Paths, expectedPaths) || collision.SuggestedName != "my-video-2" {
+		t.Errorf("Expected collision with %v suggesting my-video-2, but got %v suggesting %s", expectedPaths, collision.Paths, collision.SuggestedName)
+	}
+	assertMoveFiles(t, []string{video.Path, video.Gist, existing.Path, existing.Gist}, nil)
+
+	moved, err := moveVideo(video, "kubernetes", target, moveResolutionRename)
+	if err != nil {
+		t.Fatalf("Expected the video to be moved under a new name, but got %v", err)
+	}
+	if moved.Name != "my-video-2" || moved.Path != filepath.Join(target, "my-video-2.yaml") || moved.Gist != filepath.Join(target, "my-video-2.md") {
+		t.Errorf("Expected the video to be renamed to my-video-2, but got %s %s %s", moved.Name, moved.Path, moved.Gist)
+	}
+	assertMoveFiles(t, []string{existing.Path, existing.Gist, moved.Path, moved.Gist}, []string{video.Path, video.Gist})
+}
+
+func TestMove_moveVideoOverwrite(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "drafts")
+	target := filepath.Join(root, "kubernetes")
+	video := writeMoveTestVideo(t, source, "my-video")
+	existing := writeMoveTestVideo(t, target, "my-video")
+	if err := os.Remove(video.Gist); err != nil {
+		t.Fatalf("Error occurred while removing %s: %v", video.Gist, err)
+	}
+	moved, err := moveVideo(video, "kubernetes", target, moveResolutionOverwrite)
+	if err != nil {
+		t.Fatalf("Expected the existing files to be overwritten, but got %v", err)
+	}
+	assertMoveFiles(t, []string{moved.Path}, []string{video.Path, existing.Gist})
+	yaml := YAML{}
+	if stored := yaml.GetVideo(moved.Path); stored.Category != "kubernetes" || stored.Gist != existing.Gist {
+		t.Errorf("Expected the moved video to replace the existing one, but got %+v", stored)
+	}
+}
+
+func TestMove_setMovedIndexEntry(t *testing.T) {
+	vi := []VideoIndex{{Name: "my-video", Category: "kubernetes"}, {Name: "other", Category: "drafts"}, {Name: "my-video", Category: "drafts"}}
+	actual := setMovedIndexEntry(vi, 2, VideoIndex{Name: "my-video", Category: "kubernetes"})
+	expected := []VideoIndex{{Name: "other", Category: "drafts"}, {Name: "my-video", Category: "kubernetes"}}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestMove_moveVideos(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "drafts")
+	target := filepath.Join(root, "kubernetes")
+	first := writeMoveTestVideo(t, source, "first")
+	colliding := writeMoveTestVideo(t, source, "colliding")
+	last := writeMoveTestVideo(t, source, "last")
+	existing := writeMoveTestVideo(t, target, "colliding")
+	first.Index, colliding.Index, last.Index = 0, 1, 2
+	vi := []VideoIndex{{Name: "first", Category: "drafts"}, {Name: "colliding", Category: "drafts"}, {Name: "last", Category: "drafts"}}
+
+	results := moveVideos(vi, []Video{first, colliding, last}, "kubernetes", target)
+	if len(results) != 3 {
+		t.Fatalf("Expected: 3 results\nGot: %d", len(results))
+	}
+	var collision *ErrMoveCollision
+	if results[0].Err != nil || !errors.As(results[1].Err, &collision) || results[2].Err != nil {
+		t.Errorf("Expected: only the colliding video to fail\nGot: %v, %v, %v", results[0].Err, results[1].Err, results[2].Err)
+	}
+	expected := []VideoIndex{{Name: "first", Category: "kubernetes"}, {Name: "colliding", Category: "drafts"}, {Name: "last", Category: "kubernetes"}}
+	if !slices.Equal(vi, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, vi)
+	}
+	assertMoveFiles(t,
+		[]string{filepath.Join(target, "first.yaml"), filepath.Join(target, "last.yaml"), colliding.Path, colliding.Gist, existing.Path, existing.Gist},
+		[]string{first.Path, last.Path},
+	)
+	if text := getMoveResultsText(results); !strings.Contains(text, "Moved 2 of 3 videos.") {
+		t.Errorf("Expected: Moved 2 of 3 videos.\nGot: %s", text)
+	}
+}
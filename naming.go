@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NameOptions control how video names are converted to file names.
+// The zero value keeps the original behavior (lowercase, spaces to hyphens, no question marks).
+type NameOptions struct {
+	// Transliterate converts accented characters to their ASCII base (e.g., é to e) instead of preserving them.
+	Transliterate bool
+	// MaxLength limits the number of characters in the name. Zero means no limit.
+	MaxLength int
+	// ReservedNames cannot be used as video names.
+	ReservedNames []string
+}
+
+type ErrNameCollision struct {
+	Name          string
+	Path          string
+	SuggestedName string
+}
+
+func (e *ErrNameCollision) Error() string {
+	return fmt.Sprintf("video %s already exists (%s)", e.Name, e.Path)
+}
+
+func getNameOptions() NameOptions {
+	return NameOptions{
+		Transliterate: settings.Names.Transliterate,
+		MaxLength:     settings.Names.MaxLength,
+		ReservedNames: settings.Names.Reserved,
+	}
+}
+
+// getVideoFileName returns the file name of an indexed video. Names are sanitized once, when videos are created, and stored in the index,
+// so lookups apply only the conversion all videos were always created with. Changing the naming settings does not move existing videos.
+func getVideoFileName(name string) string {
+	fileName := strings.ReplaceAll(strings.ToLower(name), " ", "-")
+	return strings.ReplaceAll(fileName, "?", "")
+}
+
+// getIndexedVideoName returns the name a new video is stored with in the index. It is the name as typed, unless the naming settings
+// give it a file name that differs from the one getVideoFileName derives, in which case the file name is stored so that lookups find it.
+func getIndexedVideoName(name, fileName string) string {
+	if getVideoFileName(name) == fileName {
+		return name
+	}
+	return fileName
+}
+
+// sanitizeVideoName converts the name of a new video to the form used for file names.
+func sanitizeVideoName(name string, options NameOptions) (string, error) {
+	sanitized := strings.ToLower(name)
+	sanitized = strings.ReplaceAll(sanitized, " ", "-")
+	sanitized = strings.ReplaceAll(sanitized, "?", "")
+	if options.Transliterate {
+		transformer := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+		transliterated, _, err := transform.String(transformer, sanitized)
+		if err != nil {
+			return "", err
+		}
+		sanitized = transliterated
+	}
+	if options.MaxLength > 0 && utf8.RuneCountInString(sanitized) > options.MaxLength {
+		sanitized = strings.TrimRight(string([]rune(sanitized)[:options.MaxLength]), "-")
+	}
+	if len(sanitized) == 0 {
+		return "", fmt.Errorf("video name %q is empty after sanitization", name)
+	}
+	for _, reserved := range options.ReservedNames {
+		if strings.EqualFold(sanitized, reserved) {
+			return "", fmt.Errorf("video name %q is reserved", sanitized)
+		}
+	}
+	return sanitized, nil
+}
+
+// checkVideoNameCollision returns ErrNameCollision with the first free suffixed name (-2, -3, ...) if a video with the same name already exists in the directory.
+func checkVideoNameCollision(dir, name string) error {
+	path, exists := getExistingVideoPath(dir, name)
+	if !exists {
+		return nil
+	}
+	collision := &ErrNameCollision{Name: name, Path: path}
+	for suffix := 2; ; suffix++ {
+		suggested := fmt.Sprintf("%s-%d", name, suffix)
+		if _, exists := getExistingVideoPath(dir, suggested); !exists {
+			collision.SuggestedName = suggested
+			return collision
+		}
+	}
+}
+
+func getExistingVideoPath(dir, name string) (string, bool) {
+	for _, extension := range []string{"md", "yaml"} {
+		path := filepath.Join(dir, fmt.Sprintf("%s.%s", name, extension))
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
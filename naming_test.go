@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNaming_sanitizeVideoName(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  NameOptions
+		expected string
+		err      bool
+	}{
+		{"already-clean", NameOptions{}, "already-clean", false},
+		{"Test Video?", NameOptions{}, "test-video", false},
+		{"Café Déjà Vu", NameOptions{}, "café-déjà-vu", false},
+		{"Café Déjà Vu", NameOptions{Transliterate: true}, "cafe-deja-vu", false},
+		{"A Very Long Video Name", NameOptions{MaxLength: 12}, "a-very-long", false},
+		{"Ünïcödé", NameOptions{MaxLength: 3}, "ünï", false},
+		{"Index", NameOptions{ReservedNames: []string{"index"}}, "", true},
+		{"?", NameOptions{}, "", true},
+	}
+	for _, test := range tests {
+		actual, err := sanitizeVideoName(test.name, test.options)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected error=%t, but got %v", test.name, test.err, err)
+		}
+		if actual != test.expected {
+			t.Errorf("%s: expected %q, but got %q", test.name, test.expected, actual)
+		}
+	}
+}
+
+// Naming settings apply only to new videos. Paths of indexed videos stay the same when they change.
+func TestNaming_GetFilePath(t *testing.T) {
+	settingsOrig := settings
+	defer func() { settings = settingsOrig }()
+	c := Choices{}
+	expected := "manuscript/ai/café-déjà-vu-and-a-very-long-name.yaml"
+	settings.Names = SettingsNames{Transliterate: true, MaxLength: 10, Reserved: []string{"café-déjà-vu-and-a-very-long-name"}}
+	if actual := c.GetFilePath("ai", "Café Déjà Vu and a Very Long Name?", "yaml"); actual != expected {
+		t.Errorf("Expected: %s\nGot: %s", expected, actual)
+	}
+}
+
+func TestNaming_getIndexedVideoName(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		options  NameOptions
+		expected string
+	}{
+		"default":       {name: "Test Video?", expected: "Test Video?"},
+		"transliterate": {name: "Café Déjà Vu", options: NameOptions{Transliterate: true}, expected: "cafe-deja-vu"},
+		"max length":    {name: "A Very Long Video Name", options: NameOptions{MaxLength: 12}, expected: "a-very-long"},
+		"short enough":  {name: "Short Name", options: NameOptions{MaxLength: 12}, expected: "Short Name"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fileName, _ := sanitizeVideoName(test.name, test.options)
+			if actual := getIndexedVideoName(test.name, fileName); actual != test.expected {
+				t.Errorf("Expected: %s\nGot: %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNaming_checkVideoNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"test-video.md", "test-video-2.yaml", "other.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte{}, 0644); err != nil {
+			t.Fatalf("Error occurred while writing %s: %v", file, err)
+		}
+	}
+	tests := []struct {
+		name      string
+		suggested string
+	}{
+		{"new-video", ""},
+		{"test-video", "test-video-3"},
+		{"other", "other-2"},
+	}
+	for _, test := range tests {
+		err := checkVideoNameCollision(dir, test.name)
+		var collision *ErrNameCollision
+		if len(test.suggested) == 0 {
+			if err != nil {
+				t.Errorf("%s: expected no collision, but got %v", test.name, err)
+			}
+			continue
+		}
+		if !errors.As(err, &collision) {
+			t.Fatalf("%s: expected a name collision, but got %v", test.name, err)
+		}
+		if collision.SuggestedName != test.suggested || len(collision.Path) == 0 {
+			t.Errorf("%s: expected the suggested name %s, but got %+v", test.name, test.suggested, collision)
+		}
+	}
+}
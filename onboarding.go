@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var initMinimal bool
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Sets up the working directory (manuscript, index.yaml, and settings.yaml).",
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		if initMinimal {
+			err = runMinimalInit(".")
+		} else {
+			err = runInitWizard(".")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Whoops. There was an error while setting up the working directory '%s'", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// needsOnboarding reports whether the directory was never set up (neither the index nor the manuscript directory exist).
+func needsOnboarding(root string) bool {
+	_, indexErr := os.Stat(filepath.Join(root, "index.yaml"))
+	_, manuscriptErr := os.Stat(filepath.Join(root, "manuscript"))
+	return os.IsNotExist(indexErr) && os.IsNotExist(manuscriptErr)
+}
+
+// scaffoldWorkingDir creates whatever is missing out of the manuscript directory, index.yaml, and settings.yaml.
+// Existing files are never modified so it's safe to run it on a partially configured directory.
+func scaffoldWorkingDir(root, settingsContent string) ([]string, error) {
+	created := []string{}
+	manuscriptDir := filepath.Join(root, "manuscript")
+	if _, err := os.Stat(manuscriptDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(manuscriptDir, 0755); err != nil {
+			return created, err
+		}
+		created = append(created, manuscriptDir)
+	}
+	files := []struct {
+		path    string
+		content string
+	}{
+		{filepath.Join(root, "index.yaml"), "[]\n"},
+		{filepath.Join(root, "settings.yaml"), settingsContent},
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file.path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(file.path, []byte(file.content), 0644); err != nil {
+			return created, err
+		}
+		created = append(created, file.path)
+	}
+	return created, nil
+}
+
+// getSettingsTemplate returns a commented settings file. Keys without values are left empty so that the settings are valid until they are filled in.
+func getSettingsTemplate(values map[string]string) string {
+	value := func(key string) string {
+		return strconv.Quote(values[key])
+	}
+	return fmt.Sprintf(`# Passwords and keys are not stored here.
+# Use the EMAIL_PASSWORD, AI_KEY, and YOUTUBE_API_KEY environment variables instead.
+email:
+  # The address emails are sent from (Gmail).
+  from: %s
+  # Who receives thumbnail requests.
+  thumbnailTo: %s
+  # Who receives edit requests.
+  editTo: %s
+  # Who receives sponsorship related emails.
+  financeTo: %s
+ai:
+  # Azure OpenAI endpoint and deployment.
+  endpoint: %s
+  deployment: %s
+hugo:
+  # Path to the repo with Hugo posts.
+  path: %s
+# teleprompter:
+#   lineWidth: 50
+#   html: false
+# ui:
+#   theme: charm
+# schedule:
+#   weekdays: [Tuesday, Thursday]
+#   time: "16:00"
+#   minGapDays: 3
+`, value("email.from"), value("email.thumbnailTo"), value("email.editTo"), value("email.financeTo"), value("ai.endpoint"), value("ai.deployment"), value("hugo.path"))
+}
+
+func runMinimalInit(root string) error {
+	created, err := scaffoldWorkingDir(root, getSettingsTemplate(nil))
+	printScaffolded(created)
+	return err
+}
+
+func runInitWizard(root string) error {
+	values := map[string]string{}
+	if _, err := os.Stat(filepath.Join(root, "settings.yaml")); os.IsNotExist(err) {
+		if err := askSettings(values); err != nil {
+			return err
+		}
+	} else {
+		output.Info("settings.yaml already exists and was left untouched.")
+	}
+	created, err := scaffoldWorkingDir(root, getSettingsTemplate(values))
+	printScaffolded(created)
+	if err != nil {
+		return err
+	}
+	return askYouTubeAuthorization(root)
+}
+
+func askSettings(values map[string]string) error {
+	keys := []struct {
+		key   string
+		title string
+	}{
+		{"email.from", "Email to send messages from (Gmail)"},
+		{"email.thumbnailTo", "Email to send thumbnail requests to"},
+		{"email.editTo", "Email to send edit requests to"},
+		{"email.financeTo", "Email to send sponsorship emails to"},
+		{"ai.endpoint", "Azure OpenAI endpoint"},
+		{"ai.deployment", "Azure OpenAI deployment"},
+		{"hugo.path", "Path to the repo with Hugo posts"},
+	}
+	inputs := make([]string, len(keys))
+	fields := []huh.Field{}
+	for i, key := range keys {
+		fields = append(fields, huh.NewInput().Title(key.title).Description("Leave empty to fill it in later.").Value(&inputs[i]))
+	}
+	testEmail := false
+	fields = append(fields, huh.NewConfirm().Title("Send a test email (requires the EMAIL_PASSWORD environment variable)?").Value(&testEmail))
+	form := newForm(huh.NewGroup(fields...).Title("Settings"))
+	if err := runForm(form); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		values[key.key] = strings.TrimSpace(inputs[i])
+	}
+	if testEmail && len(values["email.from"]) > 0 {
+		email := NewEmail(os.Getenv("EMAIL_PASSWORD"))
+		ctx, cancel := newEmailContext()
+		defer cancel()
+		if err := email.SendTest(ctx, values["email.from"]); err != nil {
+			output.Error(fmt.Sprintf("Email is not working yet: %s", err.Error()))
+		} else {
+			output.Info(fmt.Sprintf("Test email was sent to %s.", values["email.from"]))
+		}
+	}
+	return nil
+}
+
+func askYouTubeAuthorization(root string) error {
+	if _, err := os.Stat(filepath.Join(root, "client_secret.json")); os.IsNotExist(err) {
+		output.Info("Download the OAuth client_secret.json from the Google Cloud console into this directory to enable YouTube uploads.")
+		return nil
+	}
+	cacheFile, err := getTokenPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(cacheFile); err == nil {
+		return nil
+	}
+	authorize := true
+	form := newForm(huh.NewGroup(huh.NewConfirm().Title("Authorize YouTube access now?").Value(&authorize)))
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if authorize {
+		getClient()
+		output.Info(fmt.Sprintf("YouTube token was stored in %s.", cacheFile))
+	}
+	return nil
+}
+
+func printScaffolded(created []string) {
+	if len(created) == 0 {
+		output.Info("The working directory is already set up.")
+		return
+	}
+	output.Result(fmt.Sprintf("Created:\n- %s", strings.Join(created, "\n- ")))
+}
+
+// ChooseOnboarding offers the setup wizard when the CLI is started in a directory that was never set up.
+func (c *Choices) ChooseOnboarding() {
+	if !needsOnboarding(".") {
+		return
+	}
+	setup := true
+	form := newForm(huh.NewGroup(huh.NewConfirm().Title("This directory is not set up yet (no index.yaml or manuscript). Run the setup wizard?").Value(&setup)))
+	if err := runForm(form); err != nil || !setup {
+		return
+	}
+	if err := runInitWizard("."); err != nil {
+		output.Error(err.Error())
+	}
+	// The settings were not there when the CLI started.
+	if err := readSettings(); err != nil {
+		output.Error(fmt.Sprintf("Error reading config file, %s", err))
+	}
+}
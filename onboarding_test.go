@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOnboarding_scaffoldWorkingDir(t *testing.T) {
+	root := t.TempDir()
+	if !needsOnboarding(root) {
+		t.Errorf("Expected an empty directory to need onboarding")
+	}
+	created, err := scaffoldWorkingDir(root, getSettingsTemplate(map[string]string{"email.from": "me@example.com"}))
+	if err != nil {
+		t.Fatalf("Error occurred while scaffolding: %v", err)
+	}
+	expected := []string{filepath.Join(root, "manuscript"), filepath.Join(root, "index.yaml"), filepath.Join(root, "settings.yaml")}
+	if !reflect.DeepEqual(created, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, created)
+	}
+	if needsOnboarding(root) {
+		t.Errorf("Expected a scaffolded directory not to need onboarding")
+	}
+	yaml := YAML{IndexPath: filepath.Join(root, "index.yaml")}
+	if index := yaml.GetIndex(); len(index) != 0 {
+		t.Errorf("Expected an empty index, but got %v", index)
+	}
+}
+
+func TestOnboarding_scaffoldWorkingDirIsIdempotent(t *testing.T) {
+	root := t.TempDir()
+	settingsPath := filepath.Join(root, "settings.yaml")
+	if err := os.WriteFile(settingsPath, []byte("email:\n  from: me@example.com\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", settingsPath, err)
+	}
+	created, err := scaffoldWorkingDir(root, getSettingsTemplate(nil))
+	if err != nil {
+		t.Fatalf("Error occurred while scaffolding: %v", err)
+	}
+	if expected := []string{filepath.Join(root, "manuscript"), filepath.Join(root, "index.yaml")}; !reflect.DeepEqual(created, expected) {
+		t.Errorf("Expected only the gaps to be filled: %v\nGot: %v", expected, created)
+	}
+	created, err = scaffoldWorkingDir(root, getSettingsTemplate(nil))
+	if err != nil || len(created) != 0 {
+		t.Errorf("Expected a re-run to create nothing, but got %v and %v", created, err)
+	}
+	if content, _ := os.ReadFile(settingsPath); string(content) != "email:\n  from: me@example.com\n" {
+		t.Errorf("Expected the existing settings to be left untouched, but got %s", content)
+	}
+}
+
+// The settings written by `init --minimal` must not stop the CLI from starting before they are filled in.
+func TestOnboarding_getSettingsTemplate(t *testing.T) {
+	template := getSettingsTemplate(nil)
+	if findings := getSettingsKeyFindings([]byte(template)); hasConfigErrors(findings) {
+		t.Errorf("Expected: no errors\nGot: %s", getConfigFindingsText(findings))
+	}
+	if strings.Contains(template, "FIXME") {
+		t.Errorf("Expected: empty values instead of placeholders\nGot: %s", template)
+	}
+	if expected := "  from: \"me@example.com\"\n"; !strings.Contains(getSettingsTemplate(map[string]string{"email.from": "me@example.com"}), expected) {
+		t.Errorf("Expected: %q", expected)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const outputModeDefault = "default"
+const outputModeQuiet = "quiet"
+const outputModeJSON = "json"
+
+const outputActionError = "error"
+const outputActionResult = "result"
+const outputActionCreate = "create"
+const outputActionSave = "save"
+const outputActionDelete = "delete"
+const outputActionMove = "move"
+const outputActionRelocate = "relocate"
+const outputActionRefresh = "refresh"
+const outputActionImport = "import"
+const outputActionPublish = "publish"
+const outputActionCleanup = "cleanup"
+
+const outputResultFailed = "failed"
+
+var outputQuiet bool
+var outputJSON bool
+
+// OutputEvent is one line of the --json output.
+type OutputEvent struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Video  string    `json:"video,omitempty"`
+	Result string    `json:"result,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Output is the single sink for user-facing messages.
+// The default mode keeps the styled messages. The quiet mode prints only results (stdout) and errors (stderr). The JSON mode prints one event per line (stdout) and errors also to stderr.
+type Output struct {
+	Mode   string
+	Stdout io.Writer
+	Stderr io.Writer
+	// Messages is where styled messages go in the default mode. It's stderr, like println, unless replaced.
+	Messages io.Writer
+	Now      func() time.Time
+}
+
+var output = newOutput(outputModeDefault, os.Stdout, os.Stderr)
+
+func newOutput(mode string, stdout, stderr io.Writer) *Output {
+	return &Output{Mode: mode, Stdout: stdout, Stderr: stderr, Messages: stderr, Now: time.Now}
+}
+
+func getOutputMode(quiet, json bool) (string, error) {
+	switch {
+	case quiet && json:
+		return "", fmt.Errorf("--quiet and --json cannot be used together")
+	case quiet:
+		return outputModeQuiet, nil
+	case json:
+		return outputModeJSON, nil
+	}
+	return outputModeDefault, nil
+}
+
+// Info is a decorative confirmation or progress message.
+func (o *Output) Info(message string) {
+	if o.Mode == outputModeDefault {
+		fmt.Fprintln(o.Messages, confirmationStyle.Render(message))
+	}
+}
+
+func (o *Output) Warn(message string) {
+	if o.Mode == outputModeDefault {
+		fmt.Fprintln(o.Messages, orangeStyle.Render(message))
+	}
+}
+
+// Print is an unstyled decorative message.
+func (o *Output) Print(message string) {
+	if o.Mode == outputModeDefault {
+		fmt.Fprintln(o.Messages, message)
+	}
+}
+
+func (o *Output) Error(message string) {
+	switch o.Mode {
+	case outputModeDefault:
+		fmt.Fprintln(o.Messages, errorStyle.Render(message))
+	case outputModeJSON:
+		o.writeEvent(OutputEvent{Action: outputActionError, Error: message})
+		fmt.Fprintln(o.Stderr, message)
+	default:
+		fmt.Fprintln(o.Stderr, message)
+	}
+}
+
+// Result is the outcome of what was asked for (e.g., a report) or something that has to be done manually to complete it.
+func (o *Output) Result(message string) {
+	if o.Mode == outputModeDefault {
+		fmt.Fprintln(o.Messages, confirmationStyle.Render(message))
+		return
+	}
+	o.writeResult(message)
+}
+
+// ResultText is an unstyled Result (e.g., search results).
+func (o *Output) ResultText(message string) {
+	if o.Mode == outputModeDefault {
+		fmt.Fprintln(o.Messages, message)
+		return
+	}
+	o.writeResult(message)
+}
+
+func (o *Output) writeResult(message string) {
+	if o.Mode == outputModeJSON {
+		o.writeEvent(OutputEvent{Action: outputActionResult, Result: message})
+	} else {
+		fmt.Fprintln(o.Stdout, message)
+	}
+}
+
+// Event records an operation performed on a video. The default mode prints nothing since the flows already confirm what they did.
+func (o *Output) Event(action, video, result string, err error) {
+	event := OutputEvent{Action: action, Video: video, Result: result}
+	if err != nil {
+		event.Result, event.Error = outputResultFailed, err.Error()
+	}
+	switch o.Mode {
+	case outputModeJSON:
+		o.writeEvent(event)
+		if err != nil {
+			fmt.Fprintln(o.Stderr, err.Error())
+		}
+	case outputModeQuiet:
+		if err != nil {
+			fmt.Fprintf(o.Stderr, "%s %s: %s\n", action, video, err)
+		} else {
+			fmt.Fprintf(o.Stdout, "%s %s: %s\n", action, video, result)
+		}
+	}
+}
+
+func (o *Output) writeEvent(event OutputEvent) {
+	if event.Time.IsZero() {
+		event.Time = o.Now()
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	o.Stdout.Write(append(data, '\n'))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setTestOutput(t *testing.T, mode string) (*bytes.Buffer, *bytes.Buffer) {
+	previous := output
+	t.Cleanup(func() { output = previous })
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	output = newOutput(mode, stdout, stderr)
+	output.Now = func() time.Time { return time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC) }
+	return stdout, stderr
+}
+
+func TestOutput_getOutputMode(t *testing.T) {
+	tests := []struct {
+		quiet    bool
+		json     bool
+		expected string
+	}{
+		{false, false, outputModeDefault},
+		{true, false, outputModeQuiet},
+		{false, true, outputModeJSON},
+	}
+	for _, test := range tests {
+		if actual, err := getOutputMode(test.quiet, test.json); err != nil || actual != test.expected {
+			t.Errorf("Expected: %s\nGot: %s and %v", test.expected, actual, err)
+		}
+	}
+	if _, err := getOutputMode(true, true); err == nil {
+		t.Errorf("Expected --quiet and --json to be rejected together")
+	}
+}
+
+func TestOutput_JSONEvents(t *testing.T) {
+	stdout, stderr := setTestOutput(t, outputModeJSON)
+	dir := t.TempDir()
+	video := Video{Name: "argo", Category: "k8s", Path: filepath.Join(dir, "k8s", "argo.yaml"), Gist: filepath.Join(dir, "k8s", "argo.md")}
+	output.Info("Decorative")
+	creator := VideoCreator{ManuscriptDir: dir, MkdirAll: os.MkdirAll, CreateManuscript: createManuscript, WriteIndex: func([]VideoIndex) error { return nil }}
+	if _, err := creator.Create(VideoIndex{Name: video.Name, Category: video.Category}, nil); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	yaml := YAML{}
+	video.Title = "Argo CD"
+	if err := yaml.writeVideo(&video, video.Path); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := deleteVideo(video); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := deleteVideo(video); err == nil {
+		t.Fatalf("Expected deleting a deleted video to fail")
+	}
+	output.Result("Done")
+	events := []OutputEvent{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		event := OutputEvent{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Expected one JSON event per line, but got %q", line)
+		}
+		event.Time = time.Time{}
+		events = append(events, event)
+	}
+	expected := []OutputEvent{
+		{Action: outputActionCreate, Video: video.Path, Result: "created"},
+		{Action: outputActionSave, Video: video.Path, Result: "saved"},
+		{Action: outputActionDelete, Video: video.Path, Result: "deleted"},
+		{Action: outputActionDelete, Video: video.Path, Result: outputResultFailed, Error: events[len(events)-2].Error},
+		{Action: outputActionResult, Result: "Done"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, events)
+	}
+	if len(expected[3].Error) == 0 || !strings.Contains(stderr.String(), expected[3].Error) {
+		t.Errorf("Expected the error to be written to stderr as well, but got %q", stderr.String())
+	}
+	if _, err := os.Stat(video.Path); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be deleted", video.Path)
+	}
+}
+
+func TestOutput_quiet(t *testing.T) {
+	stdout, stderr := setTestOutput(t, outputModeQuiet)
+	output.Info("Decorative")
+	output.Warn("Warning")
+	output.Print("Progress")
+	output.Result("Report")
+	output.ResultText("Search results")
+	output.Event(outputActionSave, "manuscript/k8s/argo.yaml", "saved", nil)
+	output.Event(outputActionSave, "manuscript/k8s/argo.yaml", "saved", errors.New("disk full"))
+	output.Error("Something failed")
+	expectedStdout := "Report\nSearch results\nsave manuscript/k8s/argo.yaml: saved\n"
+	if stdout.String() != expectedStdout {
+		t.Errorf("Expected: %q\nGot: %q", expectedStdout, stdout.String())
+	}
+	expectedStderr := "save manuscript/k8s/argo.yaml: disk full\nSomething failed\n"
+	if stderr.String() != expectedStderr {
+		t.Errorf("Expected: %q\nGot: %q", expectedStderr, stderr.String())
+	}
+}
+
+func TestOutput_default(t *testing.T) {
+	stdout, stderr := setTestOutput(t, outputModeDefault)
+	output.Event(outputActionSave, "manuscript/k8s/argo.yaml", "saved", nil)
+	if stdout.Len() > 0 || stderr.Len() > 0 {
+		t.Errorf("Expected events to be silent in the default mode, but got %q and %q", stdout.String(), stderr.String())
+	}
+	output.Info("Saved")
+	if !strings.Contains(stderr.String(), "Saved") {
+		t.Errorf("Expected messages on stderr like before, but got %q", stderr.String())
+	}
+}
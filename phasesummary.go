@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+const phaseSummaryVersion = 3
+
+var phaseSummaryPath = filepath.Join(".index", "phases.json")
+
+var phasesCmd = &cobra.Command{
+	Use:   "phases",
+	Short: "Manages the summary used to list videos by phase.",
+}
+
+var phasesVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Recomputes the phases of all videos from their YAML files and reports where the summary differs.",
+	Run: func(cmd *cobra.Command, args []string) {
+		summary, err := loadPhaseSummary(phaseSummaryPath)
+		if err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+		yaml := YAML{IndexPath: "index.yaml"}
+		choices := Choices{}
+		paths := []string{}
+		for _, item := range yaml.GetIndex() {
+			paths = append(paths, choices.GetFilePath(item.Category, item.Name, "yaml"))
+		}
+		mismatches, err := verifyPhaseSummary(summary, paths)
+		if err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+		if len(mismatches) == 0 {
+			output.Result("The phase summary matches the videos.")
+			os.Exit(0)
+		}
+		output.ResultText(getPhaseSummaryMismatchesText(mismatches))
+		os.Exit(1)
+	},
+}
+
+func init() {
+	phasesCmd.AddCommand(phasesVerifyCmd)
+}
+
+// PhaseSummary keeps what listing videos by phase needs, keyed by the paths of the YAML files, so that the files do not have to be read every time.
+// Entries are valid only while the modification time and the size of their files stay the same.
+type PhaseSummary struct {
+	Version int
+	Entries map[string]PhaseSummaryEntry
+}
+
+type PhaseSummaryEntry struct {
+	Phase     int
+	Date      string
+	Sponsored bool
+	Blocked   bool
+	// Demos is true if any of the demo environments was not destroyed.
+	Demos bool
+	// Invoice is true if an invoice was sent and not paid yet.
+	Invoice bool
+	// Slug is the slug of the Hugo post, whether it is published or not.
+	Slug    string
+	ModTime int64
+	Size    int64
+}
+
+// PhaseSummaryMismatch is a field of a summary entry that differs from what the video file says even though the file did not change. It means the summary was not maintained correctly.
+type PhaseSummaryMismatch struct {
+	Path    string
+	Field   string
+	Summary string
+	Actual  string
+}
+
+func NewPhaseSummary() *PhaseSummary {
+	return &PhaseSummary{Version: phaseSummaryVersion, Entries: map[string]PhaseSummaryEntry{}}
+}
+
+func newPhaseSummaryEntry(video Video, info os.FileInfo) PhaseSummaryEntry {
+	sponsored, blocked := getTrendSponsorship(video)
+	return PhaseSummaryEntry{
+		Phase:     workflow.GetPhase(video),
+		Date:      video.Date,
+		Sponsored: sponsored,
+		Blocked:   blocked,
+		Demos:     len(getUndestroyedDemos(video)) > 0,
+		Invoice:   !isInvoicePaid(video.Sponsorship) && !getInvoiceDueDate(video.Sponsorship, 0).IsZero(),
+		Slug:      getUsedSlug(video),
+		ModTime:   info.ModTime().UnixNano(),
+		Size:      info.Size(),
+	}
+}
+
+// Get returns the entry of the path if the file did not change since it was summarized.
+func (s *PhaseSummary) Get(path string) (PhaseSummaryEntry, bool) {
+	entry, ok := s.Entries[path]
+	if !ok {
+		return entry, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.ModTime().UnixNano() != entry.ModTime || info.Size() != entry.Size {
+		return entry, false
+	}
+	return entry, true
+}
+
+// Set summarizes the video stored in the path. It does nothing if the file does not exist.
+func (s *PhaseSummary) Set(path string, video Video) {
+	info, err := os.Stat(path)
+	if err != nil {
+		delete(s.Entries, path)
+		return
+	}
+	s.Entries[path] = newPhaseSummaryEntry(video, info)
+}
+
+// Refresh returns the entries of the paths, in the same order, and the number of videos that had to be read.
+// Only videos whose files changed since they were summarized (or were never summarized) are read and their entries updated.
+// They are read concurrently so read must be safe to call from multiple goroutines.
+func (s *PhaseSummary) Refresh(paths []string, read func(i int) Video) ([]PhaseSummaryEntry, int) {
+	entries := make([]PhaseSummaryEntry, len(paths))
+	stale := []int{}
+	for i, path := range paths {
+		if entry, ok := s.Get(path); ok {
+			entries[i] = entry
+			continue
+		}
+		stale = append(stale, i)
+	}
+	videos := make([]Video, len(stale))
+	forEachConcurrently(len(stale), videoLoadWorkers, func(j int) {
+		videos[j] = read(stale[j])
+	})
+	for j, i := range stale {
+		s.Set(paths[i], videos[j])
+		if entry, ok := s.Entries[paths[i]]; ok {
+			entries[i] = entry
+		} else {
+			entries[i] = PhaseSummaryEntry{Phase: workflow.GetPhase(videos[j]), Date: videos[j].Date}
+		}
+	}
+	return entries, len(stale)
+}
+
+func getPhaseSummaryCounts(entries []PhaseSummaryEntry) map[int]int {
+	phases := map[int]int{}
+	for _, entry := range entries {
+		phases[entry.Phase]++
+	}
+	return phases
+}
+
+// getPhaseSummarySnapshot is newPhaseSnapshot for summary entries.
+func getPhaseSummarySnapshot(entries []PhaseSummaryEntry, overdue int, now time.Time) PhaseSnapshot {
+	snapshot := newPhaseSnapshot(nil, getPhaseSummaryCounts(entries), overdue, now)
+	snapshot.Total = len(entries)
+	for _, entry := range entries {
+		if entry.Sponsored {
+			snapshot.Sponsored++
+		}
+		if entry.Blocked {
+			snapshot.Blocked++
+		}
+	}
+	return snapshot
+}
+
+// verifyPhaseSummary reads all the videos and compares them with the entries of files that did not change since they were summarized.
+func verifyPhaseSummary(summary *PhaseSummary, paths []string) ([]PhaseSummaryMismatch, error) {
+	mismatches := []PhaseSummaryMismatch{}
+	for _, path := range paths {
+		entry, ok := summary.Get(path)
+		if !ok {
+			continue
+		}
+		video, err := readVideo(path)
+		if err != nil {
+			return mismatches, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return mismatches, err
+		}
+		actual := newPhaseSummaryEntry(video, info)
+		fields := []struct {
+			name            string
+			summary, actual string
+		}{
+			{"Phase", fmt.Sprint(entry.Phase), fmt.Sprint(actual.Phase)},
+			{"Date", entry.Date, actual.Date},
+			{"Sponsored", fmt.Sprint(entry.Sponsored), fmt.Sprint(actual.Sponsored)},
+			{"Blocked", fmt.Sprint(entry.Blocked), fmt.Sprint(actual.Blocked)},
+			{"Demos", fmt.Sprint(entry.Demos), fmt.Sprint(actual.Demos)},
+			{"Invoice", fmt.Sprint(entry.Invoice), fmt.Sprint(actual.Invoice)},
+			{"Slug", entry.Slug, actual.Slug},
+		}
+		for _, field := range fields {
+			if field.summary != field.actual {
+				mismatches = append(mismatches, PhaseSummaryMismatch{Path: path, Field: field.name, Summary: field.summary, Actual: field.actual})
+			}
+		}
+	}
+	sort.SliceStable(mismatches, func(i, j int) bool {
+		return mismatches[i].Path < mismatches[j].Path
+	})
+	return mismatches, nil
+}
+
+func getPhaseSummaryMismatchesText(mismatches []PhaseSummaryMismatch) string {
+	lines := []string{}
+	for _, mismatch := range mismatches {
+		lines = append(lines, fmt.Sprintf("%s: %s is %q in the summary and %q in the video", mismatch.Path, mismatch.Field, mismatch.Summary, mismatch.Actual))
+	}
+	return fmt.Sprintf("%d fields of the phase summary do not match the videos:\n%s", len(mismatches), strings.Join(lines, "\n"))
+}
+
+func loadPhaseSummary(path string) (*PhaseSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	summary := NewPhaseSummary()
+	if err := json.Unmarshal(data, summary); err != nil {
+		return nil, fmt.Errorf("phase summary %s is corrupted: %w", path, err)
+	}
+	if summary.Version != phaseSummaryVersion {
+		return nil, fmt.Errorf("phase summary %s was created by a different version (%d)", path, summary.Version)
+	}
+	if summary.Entries == nil {
+		summary.Entries = map[string]PhaseSummaryEntry{}
+	}
+	return summary, nil
+}
+
+func savePhaseSummary(path string, summary *PhaseSummary) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// updatePhaseSummary summarizes the video if the summary exists. Failures are ignored since entries of changed files are not trusted anyway.
+func updatePhaseSummary(path string, video Video) {
+	summary, err := loadPhaseSummary(phaseSummaryPath)
+	if err != nil {
+		return
+	}
+	summary.Set(path, video)
+	savePhaseSummary(phaseSummaryPath, summary)
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writePhaseSummaryTestVideo(t testing.TB, path string, video Video) {
+	t.Helper()
+	data, err := yaml.Marshal(&video)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readPhaseSummaryTestVideo(paths []string) func(i int) Video {
+	return func(i int) Video {
+		video, _ := readVideo(paths[i])
+		return video
+	}
+}
+
+func TestPhaseSummary_Refresh(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml"), filepath.Join(dir, "missing.yaml")}
+	writePhaseSummaryTestVideo(t, paths[0], Video{Date: "2030-01-21T16:00"})
+	writePhaseSummaryTestVideo(t, paths[1], Video{Sponsorship: Sponsorship{Amount: "1000", Blocked: "waiting"}})
+	summary := NewPhaseSummary()
+
+	entries, reads := summary.Refresh(paths, readPhaseSummaryTestVideo(paths))
+	if reads != 3 {
+		t.Errorf("Expected all videos to be read\nGot: %d reads", reads)
+	}
+	expected := []PhaseSummaryEntry{
+		{Phase: videosPhaseStarted, Date: "2030-01-21T16:00"},
+		{Phase: videosPhaseSponsoredBlocked, Sponsored: true, Blocked: true},
+		{Phase: videosPhaseIdeas},
+	}
+	for i := range expected {
+		entries[i].ModTime, entries[i].Size = 0, 0
+		if entries[i] != expected[i] {
+			t.Errorf("Expected: %+v\nGot: %+v", expected[i], entries[i])
+		}
+	}
+	if _, ok := summary.Entries[paths[2]]; ok {
+		t.Errorf("Expected no entry for a missing file")
+	}
+
+	if _, reads = summary.Refresh(paths, readPhaseSummaryTestVideo(paths)); reads != 1 {
+		t.Errorf("Expected only the missing video to be read\nGot: %d reads", reads)
+	}
+
+	// An edit made outside of the tool changes the size or the modification time.
+	writePhaseSummaryTestVideo(t, paths[0], Video{Date: "2030-01-21T16:00", Repo: "N/A"})
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(paths[0], future, future); err != nil {
+		t.Fatal(err)
+	}
+	entries, reads = summary.Refresh(paths, readPhaseSummaryTestVideo(paths))
+	if reads != 2 {
+		t.Errorf("Expected the edited and the missing videos to be read\nGot: %d reads", reads)
+	}
+	if entries[0].Phase != videosPhasePublished {
+		t.Errorf("Expected the edited video to be published\nGot: %d", entries[0].Phase)
+	}
+}
+
+func TestPhaseSummary_newPhaseSummaryEntryInvoice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	writePhaseSummaryTestVideo(t, path, Video{})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		sponsorship Sponsorship
+		expected    bool
+	}{
+		"not sent": {sponsorship: Sponsorship{Amount: "1000"}},
+		"sent":     {sponsorship: Sponsorship{Amount: "1000", InvoiceSentDate: "2030-01-21"}, expected: true},
+		"paid":     {sponsorship: Sponsorship{Amount: "1000", InvoiceSentDate: "2030-01-21", PaidDate: "2030-02-01"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := newPhaseSummaryEntry(Video{Repo: "N/A", Sponsorship: test.sponsorship}, info).Invoice; actual != test.expected {
+				t.Errorf("Expected: %t\nGot: %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPhaseSummary_newPhaseSummaryEntrySlug(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	writePhaseSummaryTestVideo(t, path, Video{})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		video    Video
+		expected string
+	}{
+		"derived":   {video: Video{Title: "Argo CD Explained"}, expected: "argo-cd-explained"},
+		"published": {video: Video{Title: "Something Else", HugoPath: "hugo/content/devops/argo-cd/_index.md"}, expected: "argo-cd"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := newPhaseSummaryEntry(test.video, info).Slug; actual != test.expected {
+				t.Errorf("Expected: %s\nGot: %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPhaseSummary_getPhaseSummarySnapshot(t *testing.T) {
+	entries := []PhaseSummaryEntry{
+		{Phase: videosPhaseSponsoredBlocked, Sponsored: true, Blocked: true},
+		{Phase: videosPhaseIdeas, Sponsored: true},
+		{Phase: videosPhaseIdeas},
+	}
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	actual := getPhaseSummarySnapshot(entries, 1, now)
+	videos := []Video{
+		{Sponsorship: Sponsorship{Amount: "1000", Blocked: "waiting"}},
+		{Sponsorship: Sponsorship{Amount: "500"}},
+		{},
+	}
+	expected := newPhaseSnapshot(videos, map[int]int{videosPhaseSponsoredBlocked: 1, videosPhaseIdeas: 2}, 1, now)
+	if actual.Total != expected.Total || actual.Sponsored != expected.Sponsored || actual.Blocked != expected.Blocked || actual.Overdue != expected.Overdue || actual.Phases["ideas"] != expected.Phases["ideas"] {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, actual)
+	}
+}
+
+func TestPhaseSummary_verifyPhaseSummary(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")}
+	writePhaseSummaryTestVideo(t, paths[0], Video{Date: "2030-01-21T16:00"})
+	writePhaseSummaryTestVideo(t, paths[1], Video{})
+	summary := NewPhaseSummary()
+	summary.Refresh(paths, readPhaseSummaryTestVideo(paths))
+
+	mismatches, err := verifyPhaseSummary(summary, paths)
+	if err != nil || len(mismatches) != 0 {
+		t.Fatalf("Expected no mismatches\nGot: %v, %v", mismatches, err)
+	}
+
+	entry := summary.Entries[paths[0]]
+	entry.Phase = videosPhasePublished
+	entry.Date = ""
+	summary.Entries[paths[0]] = entry
+	mismatches, err = verifyPhaseSummary(summary, paths)
+	if err != nil {
+		t.Fatalf("Expected no error\nGot: %v", err)
+	}
+	expected := []PhaseSummaryMismatch{
+		{Path: paths[0], Field: "Phase", Summary: "0", Actual: "4"},
+		{Path: paths[0], Field: "Date", Summary: "", Actual: "2030-01-21T16:00"},
+	}
+	if len(mismatches) != len(expected) {
+		t.Fatalf("Expected: %+v\nGot: %+v", expected, mismatches)
+	}
+	for i := range expected {
+		if mismatches[i] != expected[i] {
+			t.Errorf("Expected: %+v\nGot: %+v", expected[i], mismatches[i])
+		}
+	}
+	if text := getPhaseSummaryMismatchesText(mismatches); !strings.HasPrefix(text, "2 fields of the phase summary do not match the videos:\n") {
+		t.Errorf("Unexpected text %s", text)
+	}
+
+	// Entries of files changed outside of the tool are stale, not wrong.
+	writePhaseSummaryTestVideo(t, paths[0], Video{Date: "2030-01-21T16:00", Delayed: true})
+	if mismatches, _ = verifyPhaseSummary(summary, paths); len(mismatches) != 0 {
+		t.Errorf("Expected stale entries to be skipped\nGot: %+v", mismatches)
+	}
+}
+
+func TestPhaseSummary_updatePhaseSummary(t *testing.T) {
+	dir := t.TempDir()
+	pathOrig := phaseSummaryPath
+	defer func() { phaseSummaryPath = pathOrig }()
+	phaseSummaryPath = filepath.Join(dir, ".index", "phases.json")
+	videoPath := filepath.Join(dir, "a.yaml")
+
+	y := YAML{}
+	if err := y.writeVideo(&Video{Date: "2030-01-21T16:00"}, videoPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(phaseSummaryPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the summary not to be created by writes\nGot: %v", err)
+	}
+
+	if err := savePhaseSummary(phaseSummaryPath, NewPhaseSummary()); err != nil {
+		t.Fatal(err)
+	}
+	if err := y.writeVideo(&Video{Date: "2030-01-21T16:00", Delayed: true}, videoPath); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := loadPhaseSummary(phaseSummaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := summary.Get(videoPath)
+	if !ok || entry.Phase != videosPhaseDelayed {
+		t.Errorf("Expected a fresh entry of a delayed video\nGot: %+v, %t", entry, ok)
+	}
+
+	if err := os.WriteFile(phaseSummaryPath, []byte(`{"Version": 1, "Entries": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPhaseSummary(phaseSummaryPath); err == nil {
+		t.Errorf("Expected an error for a corrupted summary")
+	}
+}
+
+// BenchmarkPhaseSummary_Refresh compares listing phases from a fresh summary with reading all videos.
+// The time of reading grows with the size of the files while the time of the summary does not.
+func BenchmarkPhaseSummary_Refresh(b *testing.B) {
+	for _, size := range []int{1 << 10, 1 << 16} {
+		dir := b.TempDir()
+		paths := []string{}
+		for i := 0; i < 100; i++ {
+			path := filepath.Join(dir, strings.Repeat("v", i+1)+".yaml")
+			writePhaseSummaryTestVideo(b, path, Video{Date: "2030-01-21T16:00", Description: strings.Repeat("x", size)})
+			paths = append(paths, path)
+		}
+		read := readPhaseSummaryTestVideo(paths)
+		b.Run(fmt.Sprintf("read/%dKB", size>>10), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				NewPhaseSummary().Refresh(paths, read)
+			}
+		})
+		summary := NewPhaseSummary()
+		summary.Refresh(paths, read)
+		b.Run(fmt.Sprintf("summary/%dKB", size>>10), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				summary.Refresh(paths, read)
+			}
+		})
+	}
+}
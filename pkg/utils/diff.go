@@ -0,0 +1,136 @@
+// Package utils contains text helpers used by youtube-automation that have no UI dependencies.
+package utils
+
+import (
+	"regexp"
+	"slices"
+)
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// Inputs with more token pairs than this are not diffed word by word. The whole text is reported as replaced instead.
+// Memory is linear in the number of tokens, so the cap only bounds the time.
+const diffMaxCells = 16_000_000
+
+type DiffRun struct {
+	Op   string
+	Text string
+}
+
+// Markdown links, words (including URLs), and whitespace are separate tokens so that links are never split.
+var diffTokenRegex = regexp.MustCompile(`\[[^\]\n]*\]\([^)\s]*\)|\s+|[^\s]+`)
+
+func tokenizeWords(text string) []string {
+	return diffTokenRegex.FindAllString(text, -1)
+}
+
+// GetWordDiff returns the runs of equal, inserted, and deleted text needed to turn before into after.
+func GetWordDiff(before, after string) []DiffRun {
+	beforeTokens := tokenizeWords(before)
+	afterTokens := tokenizeWords(after)
+	prefix := 0
+	for prefix < len(beforeTokens) && prefix < len(afterTokens) && beforeTokens[prefix] == afterTokens[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(beforeTokens)-prefix && suffix < len(afterTokens)-prefix && beforeTokens[len(beforeTokens)-1-suffix] == afterTokens[len(afterTokens)-1-suffix] {
+		suffix++
+	}
+	beforeMiddle := beforeTokens[prefix : len(beforeTokens)-suffix]
+	afterMiddle := afterTokens[prefix : len(afterTokens)-suffix]
+	if (len(beforeMiddle)+1)*(len(afterMiddle)+1) > diffMaxCells {
+		return mergeDiffRuns([]DiffRun{{Op: DiffDelete, Text: before}, {Op: DiffInsert, Text: after}})
+	}
+	runs := getTokenRuns(DiffEqual, beforeTokens[:prefix])
+	runs = append(runs, diffTokens(beforeMiddle, afterMiddle)...)
+	runs = append(runs, getTokenRuns(DiffEqual, beforeTokens[len(beforeTokens)-suffix:])...)
+	return mergeDiffRuns(runs)
+}
+
+// diffTokens finds the longest common subsequence with Hirschberg's algorithm so that only two rows of lengths are kept at a time.
+func diffTokens(before, after []string) []DiffRun {
+	switch {
+	case len(before) == 0:
+		return getTokenRuns(DiffInsert, after)
+	case len(after) == 0:
+		return getTokenRuns(DiffDelete, before)
+	case len(before) == 1:
+		index := slices.Index(after, before[0])
+		if index < 0 {
+			return append(getTokenRuns(DiffDelete, before), getTokenRuns(DiffInsert, after)...)
+		}
+		runs := getTokenRuns(DiffInsert, after[:index])
+		runs = append(runs, DiffRun{Op: DiffEqual, Text: before[0]})
+		return append(runs, getTokenRuns(DiffInsert, after[index+1:])...)
+	}
+	middle := len(before) / 2
+	forward := getCommonLengths(before[:middle], after)
+	backward := getCommonLengths(reversed(before[middle:]), reversed(after))
+	split := 0
+	for j := range forward {
+		if forward[j]+backward[len(after)-j] > forward[split]+backward[len(after)-split] {
+			split = j
+		}
+	}
+	return append(diffTokens(before[:middle], after[:split]), diffTokens(before[middle:], after[split:])...)
+}
+
+// getCommonLengths returns the length of the longest common subsequence of before and each prefix of after.
+func getCommonLengths(before, after []string) []int {
+	previous := make([]int, len(after)+1)
+	current := make([]int, len(after)+1)
+	for i := range before {
+		for j := range after {
+			if before[i] == after[j] {
+				current[j+1] = previous[j] + 1
+			} else {
+				current[j+1] = max(previous[j+1], current[j])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous
+}
+
+func reversed(tokens []string) []string {
+	result := slices.Clone(tokens)
+	slices.Reverse(result)
+	return result
+}
+
+func getTokenRuns(op string, tokens []string) []DiffRun {
+	runs := []DiffRun{}
+	for _, token := range tokens {
+		runs = append(runs, DiffRun{Op: op, Text: token})
+	}
+	return runs
+}
+
+func mergeDiffRuns(runs []DiffRun) []DiffRun {
+	merged := []DiffRun{}
+	for _, run := range runs {
+		if len(run.Text) == 0 {
+			continue
+		}
+		if len(merged) > 0 && merged[len(merged)-1].Op == run.Op {
+			merged[len(merged)-1].Text += run.Text
+			continue
+		}
+		merged = append(merged, run)
+	}
+	return merged
+}
+
+// HasDiffChanges returns whether any of the runs inserts or deletes text.
+func HasDiffChanges(runs []DiffRun) bool {
+	for _, run := range runs {
+		if run.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff_GetWordDiff(t *testing.T) {
+	diffs := []struct {
+		before   string
+		after    string
+		expected []DiffRun
+	}{
+		{
+			"Kubernetes is hard.",
+			"Kubernetes is easy.",
+			[]DiffRun{{DiffEqual, "Kubernetes is "}, {DiffDelete, "hard."}, {DiffInsert, "easy."}},
+		},
+		{
+			"First paragraph.\n\nSecond paragraph.",
+			"First paragraph.\n\nNew paragraph.\n\nSecond paragraph.",
+			[]DiffRun{{DiffEqual, "First paragraph.\n\n"}, {DiffInsert, "New paragraph.\n\n"}, {DiffEqual, "Second paragraph."}},
+		},
+		{
+			"See [the docs](https://example.com/a-b) for more.",
+			"See [the new docs](https://example.com/a-b) for more.",
+			[]DiffRun{{DiffEqual, "See "}, {DiffDelete, "[the docs](https://example.com/a-b)"}, {DiffInsert, "[the new docs](https://example.com/a-b)"}, {DiffEqual, " for more."}},
+		},
+		{
+			"Same text",
+			"Same text",
+			[]DiffRun{{DiffEqual, "Same text"}},
+		},
+	}
+	for _, diff := range diffs {
+		actual := GetWordDiff(diff.before, diff.after)
+		if !reflect.DeepEqual(actual, diff.expected) {
+			t.Errorf("Expected: %q\nGot: %q", diff.expected, actual)
+		}
+	}
+}
+
+func TestDiff_GetWordDiffRebuildsBothTexts(t *testing.T) {
+	before := "Watch https://youtu.be/abc?t=10 now.\n\n- item one\n- item two"
+	after := "Watch https://youtu.be/xyz?t=10 today.\n\n- item one\n- item three"
+	var rebuiltBefore, rebuiltAfter strings.Builder
+	for _, run := range GetWordDiff(before, after) {
+		if run.Op != DiffInsert {
+			rebuiltBefore.WriteString(run.Text)
+		}
+		if run.Op != DiffDelete {
+			rebuiltAfter.WriteString(run.Text)
+		}
+		if run.Op != DiffEqual && strings.Contains(run.Text, "youtu.be/") && !strings.Contains(run.Text, "?t=10") {
+			t.Errorf("Expected URLs not to be split, but got %q", run.Text)
+		}
+	}
+	if rebuiltBefore.String() != before || rebuiltAfter.String() != after {
+		t.Errorf("Expected the runs to rebuild both texts, but got %q and %q", rebuiltBefore.String(), rebuiltAfter.String())
+	}
+}
+
+func TestDiff_GetWordDiffCapsLargeInputs(t *testing.T) {
+	before := strings.Repeat("a ", 3000)
+	after := strings.Repeat("b ", 3000)
+	expected := []DiffRun{{DiffDelete, before}, {DiffInsert, after}}
+	if actual := GetWordDiff(before, after); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected large inputs to be reported as a single replacement, but got %d runs", len(actual))
+	}
+}
+
+func TestDiff_GetWordDiffLongInputs(t *testing.T) {
+	before := strings.Repeat("word ", 2000) + "end."
+	after := strings.Repeat("word ", 1000) + "new " + strings.Repeat("word ", 1000) + "end!"
+	expected := []DiffRun{{DiffEqual, strings.Repeat("word ", 1000)}, {DiffInsert, "new "}, {DiffEqual, strings.Repeat("word ", 1000)}, {DiffDelete, "end."}, {DiffInsert, "end!"}}
+	if actual := GetWordDiff(before, after); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %q\nGot: %q", expected, actual)
+	}
+}
+
+func TestDiff_HasDiffChanges(t *testing.T) {
+	if HasDiffChanges([]DiffRun{{DiffEqual, "Same text"}}) || !HasDiffChanges(GetWordDiff("a", "b")) {
+		t.Errorf("Expected only inserted or deleted text to be a change")
+	}
+}
@@ -0,0 +1,250 @@
+package workflow
+
+type Video struct {
+	SchemaVersion       int
+	Name                string
+	Index               int
+	Path                string
+	Category            string
+	Init                Tasks
+	Work                Tasks
+	Define              Tasks
+	Edit                Tasks
+	Publish             Tasks
+	ProjectName         string
+	ProjectURL          string
+	Sponsorship         Sponsorship
+	Sponsored           string // TODO: Remove
+	SponsorshipBlocked  string // TODO: Remove
+	Date                string
+	Delayed             bool
+	Code                bool
+	Screen              bool
+	Head                bool
+	Thumbnails          bool
+	Diagrams            bool
+	Title               string
+	Description         string
+	Highlight           string
+	HighlightTimestamp  string
+	Tags                string
+	DescriptionTags     string
+	Location            string
+	Tagline             string
+	TaglineIdeas        string
+	Intro               string
+	Outro               string
+	OtherLogos          string
+	Screenshots         bool
+	RequestThumbnail    bool
+	Thumbnail           string
+	ThumbnailText       string
+	Thumbnail02         string
+	Thumbnail03         string
+	Members             string
+	Animations          string
+	RequestEdit         bool
+	Movie               bool
+	Timecodes           string
+	Gist                string
+	HugoPath            string
+	RelatedVideos       string
+	UploadVideo         string
+	VideoId             string
+	Tweet               string
+	TweetPosted         bool
+	LinkedInPosted      bool
+	SlackPosted         bool
+	HNPosted            bool
+	TCPosted            bool
+	YouTubeHighlight    bool
+	YouTubeComment      bool
+	YouTubeCommentReply bool
+	Slides              bool
+	GDE                 bool
+	Repo                string
+	TwitterSpace        bool
+	NotifiedSponsors    bool
+	Clips               []Clip
+	Talks               []Talk
+	PublishPending      []string
+	CustomFields        map[string]string
+	Visibility          string
+	MadeForKids         string
+	Costs               []Cost
+	RedditTitle         string
+	RedditPosted        map[string]string
+	UploadedSnapshot    UploadedSnapshot
+	Notes               []string
+	Supersedes          string
+	SupersededBy        string
+	Podcast             PodcastEpisode
+	// YouTubeCategoryID overrides the default category. It's set when the category is pulled from YouTube.
+	YouTubeCategoryID string
+	RenderCleanup     RenderCleanup
+	DemoEnvironments  []DemoEnvironment
+	ThumbnailHistory  []ThumbnailChange
+	ContentFlags      ContentFlags
+	RecordingSessions []RecordingSession
+	Attributions      []Attribution
+	// Slug is the name of the Hugo post directory. It is derived from the title when empty.
+	Slug string
+	// TargetKeywords are comma separated search phrases. The first one is the primary keyword.
+	TargetKeywords string
+	Sections       []ManuscriptSection
+	MastodonPosted bool
+	// SlackThreadTS are the timestamps of the Slack posts keyed by the channel IDs. Replies are threaded under them.
+	SlackThreadTS map[string]string
+	// Analytics is the last snapshot of the YouTube statistics. It's refreshed when it's older than youtube.statsTTLHours.
+	Analytics Analytics
+	// Secrets are encrypted values keyed by their names. Plaintext values are never stored.
+	Secrets map[string]string
+	// ID is assigned when the video is saved for the first time. Unlike the name and the category, it does not change when the video is moved or renamed.
+	ID string
+}
+
+type Tasks struct {
+	Completed int
+	Total     int
+}
+
+type Sponsorship struct {
+	Amount       string
+	Emails       string
+	Blocked      string
+	BlockedSince string
+	LastNudged   string
+	Assets       []SponsorAsset
+	// Sponsor is the name matched against the owners of blackout windows.
+	Sponsor string
+	// Invoicing dates are in the 2006-01-02 format. DueDate defaults to InvoiceSentDate plus the payment terms.
+	InvoiceNumber   string
+	InvoiceSentDate string
+	DueDate         string
+	PaidDate        string
+	AmountInvoiced  string
+}
+
+// SponsorAsset is one item of the asset pack sponsors send (e.g., a logo or brand guidelines).
+type SponsorAsset struct {
+	Type         string
+	Received     bool
+	Path         string
+	ReceivedDate string
+}
+
+// RenderCleanup records the render file (and the raw footage) removed from the disk after the video was published, so that it can be identified later.
+type RenderCleanup struct {
+	Path            string
+	Size            int64
+	SHA256          string
+	Mode            string
+	ArchivePath     string
+	Material        string
+	MaterialArchive string
+	Date            string
+}
+
+// DemoEnvironment is cloud infrastructure created for the demo. Reminded is the day the reminder webhook was called for it.
+type DemoEnvironment struct {
+	Provider      string
+	Description   string
+	Created       string
+	MonthlyCost   float64
+	Destroyed     bool
+	DestroyedDate string
+	Reminded      string
+}
+
+// ThumbnailChange is a thumbnail that was replaced after the video was published and when it was replaced.
+type ThumbnailChange struct {
+	Path       string
+	ReplacedAt string
+}
+
+// ContentFlags describe content that is not suitable for everyone. DisclaimerText replaces the configured disclaimer if set.
+type ContentFlags struct {
+	AgeRestricted            bool
+	ContainsSecurityExploits bool
+	DisclaimerText           string
+}
+
+// RecordingSession is a guided recording session. Sessions that were not completed can be resumed.
+// Seconds is the time spent in the session so far and Checked are the titles of the checklist items that were confirmed.
+type RecordingSession struct {
+	Started   string
+	Completed string
+	Seconds   int
+	Checked   []string
+}
+
+// ManuscriptSection tracks a "## Section" of the manuscript. ID is derived from the heading so that it survives reordering.
+// Orphaned sections are no longer in the manuscript (e.g., the heading was renamed) and are kept until they are removed so that their flags are not lost.
+type ManuscriptSection struct {
+	ID       string
+	Heading  string
+	Recorded bool
+	Edited   bool
+	Orphaned bool
+}
+
+// Attribution credits a reused asset (e.g., stock footage, music, or an open-source project). UsedIn describes where in the video it's used.
+type Attribution struct {
+	Asset     string
+	Author    string
+	License   string
+	SourceURL string
+	UsedIn    string
+}
+
+// PodcastEpisode tracks the audio-only republishing of the video.
+type PodcastEpisode struct {
+	Published bool
+	Episode   int
+	URL       string
+	AudioPath string
+}
+
+type Clip struct {
+	Start   string
+	End     string
+	Title   string
+	Hook    string
+	Status  string
+	VideoId string
+}
+
+type Talk struct {
+	Conference  string
+	CFPDeadline string
+	Status      string
+	Date        string
+}
+
+// Cost is either money spent on a video (e.g., an editor invoice) or time spent on it, or both.
+type Cost struct {
+	Kind     string
+	Amount   string
+	Currency string
+	Hours    string
+	Note     string
+	Date     string
+}
+
+// UploadedSnapshot is the metadata that was sent to YouTube. It changes only when the video is uploaded or when the current values are pushed to YouTube.
+type UploadedSnapshot struct {
+	Title       string
+	Description string
+	Tags        []string
+	CategoryID  string
+	Language    string
+	UploadedAt  string
+}
+
+// Analytics are the statistics of the uploaded video as reported by YouTube at FetchedAt (RFC 3339).
+type Analytics struct {
+	Views     uint64
+	Likes     uint64
+	Comments  uint64
+	FetchedAt string
+}
@@ -0,0 +1,240 @@
+// Package workflow contains the phase and progress calculations used by youtube-automation.
+// It has no UI dependencies so that other tools can reuse the same logic. The API follows semantic versioning (see Version).
+package workflow
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Version is the semantic version of the package API. Breaking changes bump the major version.
+const Version = "1.2.0"
+
+const (
+	PhasePublished = iota
+	PhasePublishPending
+	PhaseEditRequested
+	PhaseMaterialDone
+	PhaseStarted
+	PhaseDelayed
+	PhaseSponsoredBlocked
+	PhaseIdeas
+)
+
+// GetPhase returns the phase the video is in. Delayed and blocked videos take precedence over their progress.
+func GetPhase(video Video) int {
+	// TODO: Remove
+	if len(video.Sponsorship.Blocked) == 0 {
+		video.Sponsorship.Blocked = video.SponsorshipBlocked
+	}
+	if video.Delayed {
+		return PhaseDelayed
+	} else if len(video.Sponsorship.Blocked) > 0 {
+		return PhaseSponsoredBlocked
+	} else if len(video.Repo) > 0 {
+		return PhasePublished
+	} else if len(video.UploadVideo) > 0 && len(video.Tweet) > 0 {
+		return PhasePublishPending
+	} else if video.RequestEdit {
+		return PhaseEditRequested
+	} else if video.Code && video.Screen && video.Head && video.Diagrams {
+		return PhaseMaterialDone
+	} else if len(video.Date) > 0 {
+		return PhaseStarted
+	}
+	return PhaseIdeas
+}
+
+// Count returns how many of the fields are set. Strings and slices are set when not empty and bools when true.
+func Count(fields []interface{}) (completed, total int) {
+	for _, field := range fields {
+		valueType := reflect.TypeOf(field)
+		if valueType.Kind() == reflect.String && len(field.(string)) > 0 {
+			completed++
+		} else if valueType.Kind() == reflect.Bool && field.(bool) {
+			completed++
+		} else if valueType.Kind() == reflect.Slice && reflect.Indirect(reflect.ValueOf(field)).Len() > 0 {
+			completed++
+		}
+		total++
+	}
+	return completed, total
+}
+
+// IsSponsorshipDone returns whether there is nothing left to do for the sponsor, either because there is none or because their emails are known.
+func IsSponsorshipDone(sponsorship Sponsorship) bool {
+	return !IsSponsored(sponsorship) || len(sponsorship.Emails) > 0
+}
+
+// IsSponsored returns whether the video has a sponsor. N/A and - mean that there is none.
+func IsSponsored(sponsorship Sponsorship) bool {
+	return len(sponsorship.Amount) > 0 && sponsorship.Amount != "N/A" && sponsorship.Amount != "-"
+}
+
+// IsSponsorNotified is the publish criterion for sponsors. Videos without a sponsor have nobody to notify.
+func IsSponsorNotified(video Video) bool {
+	return video.NotifiedSponsors || !IsSponsored(video.Sponsorship)
+}
+
+// IsRedditPosted returns whether the video was posted to all the subreddits.
+func IsRedditPosted(posted map[string]string, subreddits []string) bool {
+	for _, subreddit := range subreddits {
+		if len(posted[subreddit]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Field is a field counted by the progress of a phase. Value is nil for fields whose progress is derived from other fields.
+type Field struct {
+	Name  string
+	Done  bool
+	Value interface{}
+}
+
+// newField is done when the value is set, the same way Count counts it.
+func newField(name string, value interface{}) Field {
+	completed, _ := Count([]interface{}{value})
+	return Field{Name: name, Done: completed > 0, Value: value}
+}
+
+// GetProgress counts the fields that are done.
+func GetProgress(fields []Field) Tasks {
+	tasks := Tasks{Total: len(fields)}
+	for _, field := range fields {
+		if field.Done {
+			tasks.Completed++
+		}
+	}
+	return tasks
+}
+
+func GetInitFields(video Video) []Field {
+	fields := []Field{
+		newField("Project name", video.ProjectName),
+		newField("Project URL", video.ProjectURL),
+		newField("Sponsorship amount", video.Sponsorship.Amount),
+		newField("Gist path", video.Gist),
+		newField("Publish date", video.Date),
+		{Name: "Sponsorship emails", Done: IsSponsorshipDone(video.Sponsorship)},
+		{Name: "Sponsorship blocked", Done: video.Sponsorship.Blocked == ""},
+		{Name: "Delayed", Done: !video.Delayed},
+	}
+	if IsSponsored(video.Sponsorship) {
+		for _, asset := range video.Sponsorship.Assets {
+			fields = append(fields, Field{Name: "Sponsor asset: " + asset.Type, Done: asset.Received, Value: asset.Received})
+		}
+	}
+	return fields
+}
+
+func GetInitProgress(video Video) Tasks {
+	return GetProgress(GetInitFields(video))
+}
+
+func GetWorkFields(video Video) []Field {
+	return []Field{
+		newField("Code", video.Code),
+		newField("Screen", video.Screen),
+		newField("Talking head", video.Head),
+		newField("Related videos", video.RelatedVideos),
+		newField("Thumbnails", video.Thumbnails),
+		newField("Diagrams", video.Diagrams),
+		newField("Files location", video.Location),
+		newField("Tagline", video.Tagline),
+		newField("Tagline ideas", video.TaglineIdeas),
+		newField("Other logos", video.OtherLogos),
+		newField("Screenshots", video.Screenshots),
+	}
+}
+
+func GetWorkProgress(video Video) Tasks {
+	return GetProgress(GetWorkFields(video))
+}
+
+func GetDefineFields(video Video) []Field {
+	return []Field{
+		newField("Title", video.Title),
+		newField("Description", video.Description),
+		newField("Tags", video.Tags),
+		newField("Description tags", video.DescriptionTags),
+		newField("Thumbnail request", video.RequestThumbnail),
+		newField("Gist path", video.Gist),
+		newField("Animations", video.Animations),
+		newField("Tweet", video.Tweet),
+		newField("Intro", video.Intro),
+		newField("Outro", video.Outro),
+	}
+}
+
+func GetDefineProgress(video Video) Tasks {
+	return GetProgress(GetDefineFields(video))
+}
+
+func GetEditFields(video Video) []Field {
+	return []Field{
+		newField("Thumbnail text", video.ThumbnailText),
+		newField("Thumbnail", video.Thumbnail),
+		newField("Thumbnail 02", video.Thumbnail02),
+		newField("Thumbnail 03", video.Thumbnail03),
+		newField("Members", video.Members),
+		newField("Edit request", video.RequestEdit),
+		newField("Movie", video.Movie),
+		newField("Slides", video.Slides),
+		{Name: "Timecodes", Done: !strings.Contains(video.Timecodes, "TODO:")},
+	}
+}
+
+func GetEditProgress(video Video) Tasks {
+	return GetProgress(GetEditFields(video))
+}
+
+// PublishCriteria are the optional publishing tasks. Reddit counts only when there are subreddits to post to and the podcast and Mastodon only when they're enabled.
+type PublishCriteria struct {
+	Subreddits []string
+	Podcast    bool
+	Mastodon   bool
+}
+
+// IsPodcastPublished returns whether the episode is published and has what a feed needs to list it.
+func IsPodcastPublished(episode PodcastEpisode) bool {
+	return episode.Published && episode.Episode > 0 && len(episode.URL) > 0
+}
+
+// GetPublishProgress counts Reddit only when there are subreddits to post to.
+func GetPublishProgress(video Video, subreddits []string) Tasks {
+	return GetPublishProgressFor(video, PublishCriteria{Subreddits: subreddits})
+}
+
+func GetPublishProgressFor(video Video, criteria PublishCriteria) Tasks {
+	return GetProgress(GetPublishFields(video, criteria))
+}
+
+func GetPublishFields(video Video, criteria PublishCriteria) []Field {
+	fields := []Field{
+		newField("Hugo post", video.HugoPath),
+		newField("Upload video", video.UploadVideo),
+		newField("Tweet posted", video.TweetPosted),
+		newField("LinkedIn post", video.LinkedInPosted),
+		newField("Slack post", video.SlackPosted),
+		newField("Hacker News post", video.HNPosted),
+		newField("Technology Conversations post", video.TCPosted),
+		newField("YouTube highlight", video.YouTubeHighlight),
+		newField("Pinned comment", video.YouTubeComment),
+		newField("Replies to comments", video.YouTubeCommentReply),
+		newField("GDE post", video.GDE),
+		newField("Twitter Space post", video.TwitterSpace),
+		newField("Code repository", video.Repo),
+	}
+	if len(criteria.Subreddits) > 0 {
+		fields = append(fields, Field{Name: "Reddit posts", Done: IsRedditPosted(video.RedditPosted, criteria.Subreddits)})
+	}
+	if criteria.Podcast {
+		fields = append(fields, Field{Name: "Podcast episode", Done: IsPodcastPublished(video.Podcast)})
+	}
+	if criteria.Mastodon {
+		fields = append(fields, newField("Mastodon post", video.MastodonPosted))
+	}
+	return append(fields, Field{Name: "Sponsors notified", Done: IsSponsorNotified(video)})
+}
@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"testing"
+)
+
+func TestWorkflow_GetPhase(t *testing.T) {
+	tests := []struct {
+		name     string
+		video    Video
+		expected int
+	}{
+		{"idea", Video{}, PhaseIdeas},
+		{"started", Video{Date: "2030-01-01T16:00"}, PhaseStarted},
+		{"material done", Video{Date: "2030-01-01T16:00", Code: true, Screen: true, Head: true, Diagrams: true}, PhaseMaterialDone},
+		{"edit requested", Video{RequestEdit: true, Code: true, Screen: true, Head: true, Diagrams: true}, PhaseEditRequested},
+		{"publish pending", Video{UploadVideo: "video.mp4", Tweet: "tweet", RequestEdit: true}, PhasePublishPending},
+		{"published", Video{Repo: "N/A", UploadVideo: "video.mp4", Tweet: "tweet"}, PhasePublished},
+		{"sponsored blocked", Video{Repo: "N/A", Sponsorship: Sponsorship{Blocked: "waiting"}}, PhaseSponsoredBlocked},
+		{"sponsored blocked (old field)", Video{Repo: "N/A", SponsorshipBlocked: "waiting"}, PhaseSponsoredBlocked},
+		{"delayed", Video{Delayed: true, Repo: "N/A", Sponsorship: Sponsorship{Blocked: "waiting"}}, PhaseDelayed},
+	}
+	for _, test := range tests {
+		if actual := GetPhase(test.video); actual != test.expected {
+			t.Errorf("%s: Expected: %d\nGot: %d", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestWorkflow_Count(t *testing.T) {
+	completed, total := Count([]interface{}{"", "value", false, true, []string{}, []string{"value"}})
+	if completed != 3 || total != 6 {
+		t.Errorf("Expected: 3 of 6\nGot: %d of %d", completed, total)
+	}
+}
+
+func TestWorkflow_GetProgress(t *testing.T) {
+	video := Video{
+		ProjectName:  "Argo CD",
+		Date:         "2030-01-01T16:00",
+		Sponsorship:  Sponsorship{Amount: "1000", Blocked: "waiting"},
+		Code:         true,
+		Location:     "Home",
+		Title:        "Title",
+		Timecodes:    "00:00 Intro\nTODO: Timecodes",
+		Thumbnail:    "thumbnail.png",
+		UploadVideo:  "video.mp4",
+		RedditPosted: map[string]string{"kubernetes": "https://reddit.com/1"},
+	}
+	podcast := video
+	podcast.Podcast = PodcastEpisode{Published: true, Episode: 3, URL: "https://podcast.example.com/3"}
+	assets := []SponsorAsset{{Type: "logo-svg", Received: true, Path: "logo.svg"}, {Type: "approved-copy"}}
+	sponsoredAssets := video
+	sponsoredAssets.Sponsorship.Assets = assets
+	notSponsoredAssets := video
+	mastodon := video
+	mastodon.MastodonPosted = true
+	notSponsoredAssets.Sponsorship = Sponsorship{Amount: "N/A", Assets: assets}
+	tests := []struct {
+		name     string
+		actual   Tasks
+		expected Tasks
+	}{
+		{"init", GetInitProgress(video), Tasks{Completed: 4, Total: 8}},
+		{"init with sponsor assets", GetInitProgress(sponsoredAssets), Tasks{Completed: 5, Total: 10}},
+		{"init ignores sponsor assets of videos that are not sponsored", GetInitProgress(notSponsoredAssets), Tasks{Completed: 6, Total: 8}},
+		{"work", GetWorkProgress(video), Tasks{Completed: 2, Total: 11}},
+		{"define", GetDefineProgress(video), Tasks{Completed: 1, Total: 10}},
+		{"edit", GetEditProgress(video), Tasks{Completed: 1, Total: 9}},
+		{"publish", GetPublishProgress(video, nil), Tasks{Completed: 1, Total: 14}},
+		{"publish with a subreddit", GetPublishProgress(video, []string{"kubernetes"}), Tasks{Completed: 2, Total: 15}},
+		{"publish with a missing subreddit", GetPublishProgress(video, []string{"kubernetes", "devops"}), Tasks{Completed: 1, Total: 15}},
+		{"publish without the podcast workflow", GetPublishProgressFor(podcast, PublishCriteria{}), Tasks{Completed: 1, Total: 14}},
+		{"publish with a podcast episode", GetPublishProgressFor(podcast, PublishCriteria{Podcast: true}), Tasks{Completed: 2, Total: 15}},
+		{"publish with a missing podcast episode", GetPublishProgressFor(video, PublishCriteria{Podcast: true}), Tasks{Completed: 1, Total: 15}},
+		{"publish with a Mastodon post", GetPublishProgressFor(mastodon, PublishCriteria{Mastodon: true}), Tasks{Completed: 2, Total: 15}},
+		{"publish without the Mastodon post", GetPublishProgressFor(video, PublishCriteria{Mastodon: true}), Tasks{Completed: 1, Total: 15}},
+	}
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("%s: Expected: %+v\nGot: %+v", test.name, test.expected, test.actual)
+		}
+	}
+}
+
+func TestWorkflow_IsSponsorNotified(t *testing.T) {
+	tests := []struct {
+		video    Video
+		expected bool
+	}{
+		{Video{}, true},
+		{Video{Sponsorship: Sponsorship{Amount: "N/A"}}, true},
+		{Video{Sponsorship: Sponsorship{Amount: "1000"}}, false},
+		{Video{Sponsorship: Sponsorship{Amount: "1000"}, NotifiedSponsors: true}, true},
+	}
+	for _, test := range tests {
+		if actual := IsSponsorNotified(test.video); actual != test.expected {
+			t.Errorf("Expected %+v to be %t", test.video.Sponsorship, test.expected)
+		}
+	}
+}
+
+func TestWorkflow_IsSponsored(t *testing.T) {
+	tests := []struct {
+		sponsorship Sponsorship
+		expected    bool
+	}{
+		{Sponsorship{}, false},
+		{Sponsorship{Amount: "N/A"}, false},
+		{Sponsorship{Amount: "-"}, false},
+		{Sponsorship{Amount: "1000"}, true},
+	}
+	for _, test := range tests {
+		if actual := IsSponsored(test.sponsorship); actual != test.expected {
+			t.Errorf("Expected %+v to be %t", test.sponsorship, test.expected)
+		}
+	}
+}
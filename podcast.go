@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type PodcastEpisode = workflow.PodcastEpisode
+
+func getPublishCriteria(s Settings) workflow.PublishCriteria {
+	return workflow.PublishCriteria{Subreddits: getSubredditNames(s.Reddit.Subreddits), Podcast: s.Podcast.Enabled, Mastodon: isMastodonEnabled(s.Mastodon)}
+}
+
+// validatePodcastEpisode makes sure that the episode number is set when the episode is published and that no other video uses it.
+func validatePodcastEpisode(video Video, videos []Video) error {
+	episode := video.Podcast
+	if episode.Episode < 0 {
+		return fmt.Errorf("episode number must not be negative")
+	}
+	if episode.Published && episode.Episode == 0 {
+		return fmt.Errorf("episode number is required for published episodes")
+	}
+	if episode.Episode == 0 {
+		return nil
+	}
+	for _, other := range videos {
+		if other.Path != video.Path && other.Podcast.Episode == episode.Episode {
+			return fmt.Errorf("episode %d is already used by %s", episode.Episode, other.Name)
+		}
+	}
+	return nil
+}
+
+// getNextPodcastEpisode returns the number after the highest one in the catalog.
+func getNextPodcastEpisode(videos []Video) int {
+	next := 1
+	for _, video := range videos {
+		next = max(next, video.Podcast.Episode+1)
+	}
+	return next
+}
+
+// getPublishedPodcastEpisodes returns videos with published episodes ordered by the episode number.
+func getPublishedPodcastEpisodes(videos []Video) []Video {
+	episodes := []Video{}
+	for _, video := range videos {
+		if workflow.IsPodcastPublished(video.Podcast) {
+			episodes = append(episodes, video)
+		}
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].Podcast.Episode < episodes[j].Podcast.Episode
+	})
+	return episodes
+}
+
+// getVideosMissingPodcast returns published videos without a published episode.
+func getVideosMissingPodcast(videos []Video) []Video {
+	missing := []Video{}
+	for _, video := range videos {
+		if workflow.GetPhase(video) == videosPhasePublished && !workflow.IsPodcastPublished(video.Podcast) {
+			missing = append(missing, video)
+		}
+	}
+	return missing
+}
+
+func getPodcastReport(videos []Video, enabled bool) string {
+	var builder strings.Builder
+	episodes := getPublishedPodcastEpisodes(videos)
+	if len(episodes) == 0 {
+		builder.WriteString("There are no published podcast episodes.\n")
+	}
+	for _, video := range episodes {
+		builder.WriteString(fmt.Sprintf("%d. %s (%s)\n", video.Podcast.Episode, video.Title, video.Podcast.URL))
+	}
+	if enabled {
+		if missing := getVideosMissingPodcast(videos); len(missing) > 0 {
+			builder.WriteString(fmt.Sprintf("\nPublished videos without a podcast episode (%d):\n", len(missing)))
+			for _, video := range missing {
+				builder.WriteString(fmt.Sprintf("- %s\n", video.Name))
+			}
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func getPodcastTestVideos() []Video {
+	return []Video{
+		{Path: "manuscript/ai/01.yaml", Name: "01", Title: "First", VideoId: "abc", Repo: "N/A", Podcast: PodcastEpisode{Published: true, Episode: 2, URL: "https://podcast.example.com/2"}},
+		{Path: "manuscript/ai/02.yaml", Name: "02", Title: "Second", VideoId: "def", Repo: "N/A", Podcast: PodcastEpisode{Published: true, Episode: 1, URL: "https://podcast.example.com/1"}},
+		{Path: "manuscript/ai/03.yaml", Name: "03", Title: "Third", VideoId: "ghi", Repo: "N/A", Podcast: PodcastEpisode{Episode: 3}},
+		{Path: "manuscript/ai/04.yaml", Name: "04", Title: "Fourth"},
+	}
+}
+
+func TestPodcast_validatePodcastEpisode(t *testing.T) {
+	tests := []struct {
+		name    string
+		episode PodcastEpisode
+		path    string
+		valid   bool
+	}{
+		{"new number", PodcastEpisode{Published: true, Episode: 4}, "manuscript/ai/04.yaml", true},
+		{"number used by another video", PodcastEpisode{Published: true, Episode: 2}, "manuscript/ai/04.yaml", false},
+		{"number used by an unpublished episode", PodcastEpisode{Episode: 3}, "manuscript/ai/04.yaml", false},
+		{"own number", PodcastEpisode{Published: true, Episode: 2}, "manuscript/ai/01.yaml", true},
+		{"published without a number", PodcastEpisode{Published: true}, "manuscript/ai/04.yaml", false},
+		{"not published without a number", PodcastEpisode{}, "manuscript/ai/04.yaml", true},
+		{"negative number", PodcastEpisode{Episode: -1}, "manuscript/ai/04.yaml", false},
+	}
+	for _, test := range tests {
+		err := validatePodcastEpisode(Video{Path: test.path, Podcast: test.episode}, getPodcastTestVideos())
+		if (err == nil) != test.valid {
+			t.Errorf("%s: Expected valid=%t, but got %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestPodcast_getPublishedPodcastEpisodes(t *testing.T) {
+	actual := []string{}
+	for _, video := range getPublishedPodcastEpisodes(getPodcastTestVideos()) {
+		actual = append(actual, video.Name)
+	}
+	expected := []string{"02", "01"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestPodcast_getNextPodcastEpisode(t *testing.T) {
+	if actual := getNextPodcastEpisode(getPodcastTestVideos()); actual != 4 {
+		t.Errorf("Expected: 4\nGot: %d", actual)
+	}
+	if actual := getNextPodcastEpisode(nil); actual != 1 {
+		t.Errorf("Expected: 1\nGot: %d", actual)
+	}
+}
+
+func TestPodcast_getPodcastReport(t *testing.T) {
+	videos := getPodcastTestVideos()
+	report := getPodcastReport(videos, false)
+	if !strings.HasPrefix(report, "1. Second (https://podcast.example.com/1)\n2. First") {
+		t.Errorf("Expected episodes in order, but got %q", report)
+	}
+	if strings.Contains(report, "without a podcast episode") {
+		t.Errorf("Expected missing episodes not to be listed when the workflow is disabled, but got %q", report)
+	}
+	report = getPodcastReport(videos, true)
+	if !strings.Contains(report, "Published videos without a podcast episode (1):\n- 03") {
+		t.Errorf("Expected 03 to be listed as missing, but got %q", report)
+	}
+}
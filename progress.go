@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const videosSortByDate = 0
+const videosSortByCompletion = 1
+const videosSortByName = 2
+
+var videosSortNames = []string{"date", "completion", "name"}
+
+// videosSortOrder is remembered for the rest of the session.
+var videosSortOrder = videosSortByDate
+
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+func getVideoTasks(video Video) []Tasks {
+	return []Tasks{video.Init, video.Work, video.Define, video.Edit, video.Publish}
+}
+
+// getVideoCompletion returns the percentage of completed tasks across all phases.
+// Sponsored blocked and delayed videos are not treated differently so that the percentage shows how much work is done.
+func getVideoCompletion(video Video) int {
+	completed, total := 0, 0
+	for _, tasks := range getVideoTasks(video) {
+		completed += tasks.Completed
+		total += tasks.Total
+	}
+	if total == 0 {
+		return 0
+	}
+	return completed * 100 / total
+}
+
+// getCompletionSparkline returns one character per phase, from init to publish, with the height showing how much of the phase is done.
+func getCompletionSparkline(video Video) string {
+	var builder strings.Builder
+	for _, tasks := range getVideoTasks(video) {
+		level := 0
+		if tasks.Total > 0 {
+			level = tasks.Completed * (len(sparklineLevels) - 1) / tasks.Total
+		}
+		builder.WriteRune(sparklineLevels[level])
+	}
+	return builder.String()
+}
+
+func getCompletionText(video Video) string {
+	return fmt.Sprintf("%s %d%%", getCompletionSparkline(video), getVideoCompletion(video))
+}
+
+// sortVideos sorts videos by the order and, when they are equal, by name.
+func sortVideos(videos []Video, order int) {
+	sort.SliceStable(videos, func(i, j int) bool {
+		switch order {
+		case videosSortByCompletion:
+			completion1, completion2 := getVideoCompletion(videos[i]), getVideoCompletion(videos[j])
+			if completion1 != completion2 {
+				return completion1 > completion2
+			}
+		case videosSortByDate:
+			date1, _ := time.Parse(dateFormat, videos[i].Date)
+			date2, _ := time.Parse(dateFormat, videos[j].Date)
+			if !date1.Equal(date2) {
+				return date1.Before(date2)
+			}
+		}
+		return videos[i].Name < videos[j].Name
+	})
+}
+
+func getNextSortOrder(order int) int {
+	return (order + 1) % len(videosSortNames)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func getProgressTestVideos() []Video {
+	return []Video{
+		{Name: "done", Date: "2030-01-02T16:00", Init: Tasks{Completed: 4, Total: 4}, Work: Tasks{Completed: 5, Total: 5}, Define: Tasks{Completed: 6, Total: 6}, Edit: Tasks{Completed: 3, Total: 3}, Publish: Tasks{Completed: 2, Total: 2}},
+		{Name: "halfway", Date: "2030-01-01T16:00", Init: Tasks{Completed: 4, Total: 4}, Work: Tasks{Completed: 5, Total: 5}, Define: Tasks{Completed: 1, Total: 6}, Edit: Tasks{Completed: 0, Total: 3}, Publish: Tasks{Completed: 0, Total: 2}},
+		{Name: "blocked", Date: "2030-01-03T16:00", Sponsorship: Sponsorship{Blocked: "waiting for the sponsor"}, Init: Tasks{Completed: 4, Total: 4}, Work: Tasks{Completed: 5, Total: 5}, Define: Tasks{Completed: 1, Total: 6}, Edit: Tasks{Completed: 0, Total: 3}, Publish: Tasks{Completed: 0, Total: 2}},
+		{Name: "idea"},
+	}
+}
+
+func TestProgress_getVideoCompletion(t *testing.T) {
+	expected := []int{100, 50, 50, 0}
+	for i, video := range getProgressTestVideos() {
+		if actual := getVideoCompletion(video); actual != expected[i] {
+			t.Errorf("%s: expected %d%%, but got %d%%", video.Name, expected[i], actual)
+		}
+	}
+}
+
+func TestProgress_getCompletionText(t *testing.T) {
+	expected := []string{"█████ 100%", "██▂▁▁ 50%", "██▂▁▁ 50%", "▁▁▁▁▁ 0%"}
+	for i, video := range getProgressTestVideos() {
+		if actual := getCompletionText(video); actual != expected[i] {
+			t.Errorf("%s\nExpected: %s\nGot: %s", video.Name, expected[i], actual)
+		}
+	}
+}
+
+func TestProgress_sortVideos(t *testing.T) {
+	tests := []struct {
+		order    int
+		expected []string
+	}{
+		{videosSortByDate, []string{"idea", "halfway", "done", "blocked"}},
+		{videosSortByCompletion, []string{"done", "blocked", "halfway", "idea"}},
+		{videosSortByName, []string{"blocked", "done", "halfway", "idea"}},
+	}
+	for _, test := range tests {
+		videos := getProgressTestVideos()
+		sortVideos(videos, test.order)
+		actual := []string{}
+		for _, video := range videos {
+			actual = append(actual, video.Name)
+		}
+		if !slices.Equal(actual, test.expected) {
+			t.Errorf("Sorted by %s\nExpected: %v\nGot: %v", videosSortNames[test.order], test.expected, actual)
+		}
+	}
+}
+
+func TestProgress_getNextSortOrder(t *testing.T) {
+	order := videosSortByDate
+	for _, expected := range []int{videosSortByCompletion, videosSortByName, videosSortByDate} {
+		order = getNextSortOrder(order)
+		if order != expected {
+			t.Errorf("Expected %d, but got %d", expected, order)
+		}
+	}
+}
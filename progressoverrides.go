@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+// progressCriteriaFilled counts the field as done when it is set. It's the default.
+const progressCriteriaFilled = "filled_only"
+
+// progressCriteriaTrue counts the field as done only when its value is true. Fields that are not booleans are never done.
+const progressCriteriaTrue = "true_only"
+
+// progressCriteriaAlways counts the field as done whether it is set or not.
+const progressCriteriaAlways = "empty_or_filled"
+
+var progressCriteria = []string{progressCriteriaFilled, progressCriteriaTrue, progressCriteriaAlways}
+
+// getProgressFieldKey returns the key of a field in progress overrides: its name in lowercase without spaces (e.g., otherlogos for Other logos).
+func getProgressFieldKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), " ", "")
+}
+
+func getProgressOverride(overrides map[string]map[string]SettingsProgressField, phase, name string) (SettingsProgressField, bool) {
+	for key, override := range overrides[phase] {
+		if getProgressFieldKey(key) == getProgressFieldKey(name) {
+			return override, true
+		}
+	}
+	return SettingsProgressField{}, false
+}
+
+// applyProgressOverrides removes disabled fields and applies the criteria of the others.
+// Fields without a value, like Delayed, are derived from other fields and keep their own criterion with true_only.
+func applyProgressOverrides(fields []workflow.Field, phase string, overrides map[string]map[string]SettingsProgressField) []workflow.Field {
+	applied := []workflow.Field{}
+	for _, field := range fields {
+		override, ok := getProgressOverride(overrides, phase, field.Name)
+		if ok && override.Enabled != nil && !*override.Enabled {
+			continue
+		}
+		if ok && override.Criteria == progressCriteriaAlways {
+			field.Done = true
+		}
+		if ok && override.Criteria == progressCriteriaTrue && field.Value != nil {
+			value, isBool := field.Value.(bool)
+			field.Done = isBool && value
+		}
+		applied = append(applied, field)
+	}
+	return applied
+}
+
+func getWorkflowPhaseFields(video Video, phase string, criteria workflow.PublishCriteria) []workflow.Field {
+	switch phase {
+	case customFieldPhaseInit:
+		return workflow.GetInitFields(video)
+	case customFieldPhaseWork:
+		return workflow.GetWorkFields(video)
+	case customFieldPhaseDefine:
+		return workflow.GetDefineFields(video)
+	case customFieldPhaseEdit:
+		return workflow.GetEditFields(video)
+	case customFieldPhasePublish:
+		return workflow.GetPublishFields(video, criteria)
+	}
+	return []workflow.Field{}
+}
+
+// getBuiltInPhaseFields returns the fields of the Video struct counted by the progress of the phase with the overrides from settings applied.
+func getBuiltInPhaseFields(video Video, phase string, s Settings) []workflow.Field {
+	return applyProgressOverrides(getWorkflowPhaseFields(video, phase, getPublishCriteria(s)), phase, s.Progress)
+}
+
+// getBuiltInPhaseProgress is the progress stored in the video. Custom fields are added to it by their own form.
+func getBuiltInPhaseProgress(video Video, phase string, s Settings) Tasks {
+	return workflow.GetProgress(getBuiltInPhaseFields(video, phase, s))
+}
+
+// refreshVideoProgress recomputes the progress of all phases from the fields, including custom ones, so that the stored progress
+// follows the overrides in settings and the menus show the same counts as the focus view.
+func refreshVideoProgress(video *Video, s Settings) {
+	progress := map[string]*Tasks{
+		customFieldPhaseInit:    &video.Init,
+		customFieldPhaseWork:    &video.Work,
+		customFieldPhaseDefine:  &video.Define,
+		customFieldPhaseEdit:    &video.Edit,
+		customFieldPhasePublish: &video.Publish,
+	}
+	for phase, tasks := range progress {
+		*tasks = workflow.GetProgress(getPhaseFields(*video, phase, s))
+	}
+}
+
+// isProgressFieldBool returns whether the field of the phase is a boolean and can, therefore, be done with true_only.
+// Fields without a value are derived from other fields and are treated as booleans.
+func isProgressFieldBool(phase, name string) bool {
+	for _, field := range getWorkflowPhaseFields(Video{}, phase, workflow.PublishCriteria{Subreddits: []string{""}, Podcast: true, Mastodon: true}) {
+		if getProgressFieldKey(field.Name) == getProgressFieldKey(name) {
+			_, isBool := field.Value.(bool)
+			return isBool || field.Value == nil
+		}
+	}
+	return true
+}
+
+// getProgressFieldKeys returns the keys of all the fields of the phase that can be overridden, including the optional ones.
+// Sponsor assets are not included since they differ from one video to another.
+func getProgressFieldKeys(phase string) []string {
+	keys := []string{}
+	for _, field := range getWorkflowPhaseFields(Video{}, phase, workflow.PublishCriteria{Subreddits: []string{""}, Podcast: true, Mastodon: true}) {
+		keys = append(keys, getProgressFieldKey(field.Name))
+	}
+	return keys
+}
+
+// getProgressOverrideFindings warns about overrides of phases and fields that do not exist since they would be silently ignored.
+func getProgressOverrideFindings(s Settings) []ConfigFinding {
+	findings := []ConfigFinding{}
+	phases := make([]string, 0, len(s.Progress))
+	for phase := range s.Progress {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	for _, phase := range phases {
+		if !slices.Contains(focusPhases, phase) {
+			findings = append(findings, ConfigFinding{Path: "progress." + phase, Severity: configSeverityWarning, Message: fmt.Sprintf("is not one of %s", strings.Join(focusPhases, ", "))})
+			continue
+		}
+		keys := getProgressFieldKeys(phase)
+		names := make([]string, 0, len(s.Progress[phase]))
+		for name := range s.Progress[phase] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			path := fmt.Sprintf("progress.%s.%s", phase, name)
+			if !slices.Contains(keys, getProgressFieldKey(name)) {
+				findings = append(findings, ConfigFinding{Path: path, Severity: configSeverityWarning, Message: fmt.Sprintf("is not a field of the %s phase", phase)})
+			}
+			if criteria := s.Progress[phase][name].Criteria; len(criteria) > 0 && !slices.Contains(progressCriteria, criteria) {
+				findings = append(findings, ConfigFinding{Path: path + ".criteria", Severity: configSeverityError, Message: fmt.Sprintf("%q is not one of %s", criteria, strings.Join(progressCriteria, ", "))})
+			}
+		}
+	}
+	return findings
+}
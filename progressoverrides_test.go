@@ -0,0 +1,103 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+func TestProgressOverrides_getBuiltInPhaseProgress(t *testing.T) {
+	disabled := false
+	video := Video{Code: true, Screen: true, Tagline: "Something"}
+	tests := map[string]struct {
+		overrides map[string]map[string]SettingsProgressField
+		expected  Tasks
+	}{
+		"none":        {expected: Tasks{Completed: 3, Total: 11}},
+		"disabled":    {overrides: map[string]map[string]SettingsProgressField{"work": {"diagrams": {Enabled: &disabled}}}, expected: Tasks{Completed: 3, Total: 10}},
+		"always":      {overrides: map[string]map[string]SettingsProgressField{"work": {"otherLogos": {Criteria: progressCriteriaAlways}}}, expected: Tasks{Completed: 4, Total: 11}},
+		"filled":      {overrides: map[string]map[string]SettingsProgressField{"work": {"OtherLogos": {Criteria: progressCriteriaFilled}}}, expected: Tasks{Completed: 3, Total: 11}},
+		"true bool":   {overrides: map[string]map[string]SettingsProgressField{"work": {"code": {Criteria: progressCriteriaTrue}}}, expected: Tasks{Completed: 3, Total: 11}},
+		"true string": {overrides: map[string]map[string]SettingsProgressField{"work": {"tagline": {Criteria: progressCriteriaTrue}}}, expected: Tasks{Completed: 2, Total: 11}},
+		"other phase": {overrides: map[string]map[string]SettingsProgressField{"edit": {"diagrams": {Enabled: &disabled}}}, expected: Tasks{Completed: 3, Total: 11}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := Settings{Progress: test.overrides}
+			stored := getBuiltInPhaseProgress(video, customFieldPhaseWork, s)
+			if stored != test.expected {
+				t.Errorf("Expected: %+v\nGot: %+v", test.expected, stored)
+			}
+			if focus := workflow.GetProgress(getPhaseFields(video, customFieldPhaseWork, s)); focus != stored {
+				t.Errorf("Expected: the focus progress to match the stored one %+v\nGot: %+v", stored, focus)
+			}
+		})
+	}
+}
+
+func TestProgressOverrides_ColorFromField(t *testing.T) {
+	settingsOrig := settings
+	defer func() { settings = settingsOrig }()
+	disabled := false
+	settings.Progress = map[string]map[string]SettingsProgressField{
+		"work": {"diagrams": {Enabled: &disabled}, "otherLogos": {Criteria: progressCriteriaAlways}, "code": {Criteria: progressCriteriaTrue}, "tagline": {Criteria: progressCriteriaTrue}},
+	}
+	c := Choices{}
+	tests := map[string]struct {
+		name     string
+		done     bool
+		expected string
+	}{
+		"disabled": {name: "Diagrams", expected: "Title"},
+		"always":   {name: "Other logos", expected: greenStyle.Render("Title")},
+		"not done": {name: "Code", expected: redStyle.Render("Title")},
+		"done":     {name: "Code", done: true, expected: greenStyle.Render("Title")},
+		"true":     {name: "Tagline", done: true, expected: redStyle.Render("Title")},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := c.ColorFromField(customFieldPhaseWork, test.name, "Title", test.done); actual != test.expected {
+				t.Errorf("Expected: %q\nGot: %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestProgressOverrides_readVideo(t *testing.T) {
+	settingsOrig := settings
+	defer func() { settings = settingsOrig }()
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	video := Video{Name: "my-video", Path: path, Code: true, Screen: true}
+	yaml := YAML{}
+	if err := yaml.writeVideo(&video, path); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	disabled := false
+	settings.Progress = map[string]map[string]SettingsProgressField{"work": {"diagrams": {Enabled: &disabled}}}
+	actual, err := readVideo(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	if expected := (Tasks{Completed: 2, Total: 10}); actual.Work != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, actual.Work)
+	}
+}
+
+func TestProgressOverrides_getProgressOverrideFindings(t *testing.T) {
+	disabled := false
+	s := Settings{Progress: map[string]map[string]SettingsProgressField{
+		"work":    {"diagrams": {Enabled: &disabled}, "diagram": {Enabled: &disabled}},
+		"publish": {"mastodonPost": {Criteria: "always"}},
+		"review":  {"title": {Enabled: &disabled}},
+	}}
+	expected := []ConfigFinding{
+		{Path: "progress.publish.mastodonPost.criteria", Severity: configSeverityError, Message: `"always" is not one of filled_only, true_only, empty_or_filled`},
+		{Path: "progress.review", Severity: configSeverityWarning, Message: "is not one of init, work, define, edit, publish"},
+		{Path: "progress.work.diagram", Severity: configSeverityWarning, Message: "is not a field of the work phase"},
+	}
+	if actual := getProgressOverrideFindings(s); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
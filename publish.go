@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const publishStepHugo = "hugo"
+const publishStepUpload = "upload"
+const publishStepThumbnail = "thumbnail"
+
+// Publisher performs the publishing actions that have side effects outside of the video YAML.
+type Publisher interface {
+	CreateHugoPost(video Video) (string, error)
+	UploadVideo(video Video) (string, error)
+	UploadThumbnail(video Video) error
+}
+
+type youTubePublisher struct{}
+
+func (p youTubePublisher) CreateHugoPost(video Video) (string, error) {
+	hugo := Hugo{}
+	return hugo.Post(video.Gist, video.Title, getVideoSlug(video), video.Date)
+}
+
+func (p youTubePublisher) UploadVideo(video Video) (string, error) {
+	return uploadVideo(video)
+}
+
+// UploadThumbnail refuses invalid thumbnails. Since it runs after the video upload, the video stays uploaded and the thumbnail stays pending.
+func (p youTubePublisher) UploadThumbnail(video Video) error {
+	if err := validateThumbnail(video.Thumbnail); err != nil {
+		return fmt.Errorf("the thumbnail was not uploaded: %w", err)
+	}
+	return uploadThumbnail(video)
+}
+
+// PublishStep is a single publishing action. Steps without Rollback are irreversible.
+type PublishStep struct {
+	Name     string
+	Run      func(video *Video) error
+	Rollback func(video *Video) error
+}
+
+type PublishResult struct {
+	Completed  []string
+	RolledBack []string
+	Pending    []string
+	Err        error
+}
+
+// publishVideo runs the publishing steps that were requested or left pending by a previous run.
+// A pending Hugo step is run only if createHugo is still requested. Otherwise, it is no longer pending.
+func publishVideo(video *Video, publisher Publisher, createHugo, upload bool) PublishResult {
+	if !createHugo {
+		video.PublishPending = slices.DeleteFunc(slices.Clone(video.PublishPending), func(name string) bool {
+			return name == publishStepHugo
+		})
+	}
+	steps := []PublishStep{}
+	if createHugo && len(video.HugoPath) == 0 {
+		steps = append(steps, PublishStep{
+			Name: publishStepHugo,
+			Run: func(video *Video) error {
+				path, err := publisher.CreateHugoPost(*video)
+				video.HugoPath = path
+				return err
+			},
+			Rollback: func(video *Video) error {
+				if err := removeHugoPost(video.HugoPath, getVideoSlug(*video)); err != nil {
+					return err
+				}
+				video.HugoPath = ""
+				return nil
+			},
+		})
+	}
+	if upload || slices.Contains(video.PublishPending, publishStepUpload) {
+		steps = append(steps, PublishStep{
+			Name: publishStepUpload,
+			Run: func(video *Video) error {
+				videoId, err := publisher.UploadVideo(*video)
+				if err != nil {
+					return err
+				}
+				video.VideoId = videoId
+				video.UploadedSnapshot = getUploadedSnapshot(*video, time.Now())
+				return nil
+			},
+		})
+	}
+	if upload || slices.Contains(video.PublishPending, publishStepUpload) || slices.Contains(video.PublishPending, publishStepThumbnail) {
+		steps = append(steps, PublishStep{
+			Name: publishStepThumbnail,
+			Run: func(video *Video) error {
+				return publisher.UploadThumbnail(*video)
+			},
+		})
+	}
+	return runPublishSteps(video, steps)
+}
+
+// runPublishSteps runs the steps in order. When a step fails, completed steps are rolled back in reverse order
+// until an irreversible one is reached since everything before it might already be referenced (e.g., the Hugo post in the YouTube description).
+// The steps that were rolled back, the failed step, and those after it are stored in the video as pending so that the next run can resume.
+// A step stays pending until it succeeds.
+func runPublishSteps(video *Video, steps []PublishStep) PublishResult {
+	result := PublishResult{}
+	for i, step := range steps {
+		err := step.Run(video)
+		recordAuditAction(video.Path, step.Name, err)
+		if err != nil {
+			result.Err = fmt.Errorf("%s failed: %w", step.Name, err)
+			for _, pending := range steps[i:] {
+				result.Pending = append(result.Pending, pending.Name)
+			}
+			for j := i - 1; j >= 0 && steps[j].Rollback != nil; j-- {
+				rollbackErr := steps[j].Rollback(video)
+				recordAuditAction(video.Path, auditActionRollback+steps[j].Name, rollbackErr)
+				if rollbackErr != nil {
+					result.Err = fmt.Errorf("%w; rollback of %s failed: %v", result.Err, steps[j].Name, rollbackErr)
+					break
+				}
+				result.Completed = result.Completed[:len(result.Completed)-1]
+				result.RolledBack = append(result.RolledBack, steps[j].Name)
+				result.Pending = append([]string{steps[j].Name}, result.Pending...)
+			}
+			for _, pending := range result.Pending {
+				if !slices.Contains(video.PublishPending, pending) {
+					video.PublishPending = append(video.PublishPending, pending)
+				}
+			}
+			return result
+		}
+		video.PublishPending = slices.DeleteFunc(video.PublishPending, func(name string) bool {
+			return name == step.Name
+		})
+		result.Completed = append(result.Completed, step.Name)
+	}
+	return result
+}
+
+// removeHugoPost deletes the post and its directory if nothing else is left in it.
+// The directory is removed only if it is the post's own (named after the slug) so that a shared one is never deleted.
+func removeHugoPost(path, slug string) error {
+	if len(path) == 0 {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if len(slug) == 0 || filepath.Base(dir) != slug {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil || len(entries) > 0 {
+		return err
+	}
+	return os.Remove(dir)
+}
+
+func getPublishResultMessage(result PublishResult) string {
+	lines := []string{}
+	if len(result.Completed) > 0 {
+		lines = append(lines, fmt.Sprintf("Completed: %s", strings.Join(result.Completed, ", ")))
+	}
+	if len(result.RolledBack) > 0 {
+		lines = append(lines, fmt.Sprintf("Rolled back: %s", strings.Join(result.RolledBack, ", ")))
+	}
+	if len(result.Pending) > 0 {
+		lines = append(lines, fmt.Sprintf("Pending: %s", strings.Join(result.Pending, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
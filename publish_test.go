@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+type fakePublisher struct {
+	dir           string
+	failHugo      bool
+	failUpload    bool
+	failThumbnail bool
+}
+
+func (p fakePublisher) CreateHugoPost(video Video) (string, error) {
+	if p.failHugo {
+		return "", errors.New("hugo is broken")
+	}
+	path := filepath.Join(p.dir, getVideoSlug(video), "_index.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(video.Title), 0644)
+}
+
+func (p fakePublisher) UploadVideo(video Video) (string, error) {
+	if p.failUpload {
+		return "", errors.New("youtube is down")
+	}
+	return "abc", nil
+}
+
+func (p fakePublisher) UploadThumbnail(video Video) error {
+	if p.failThumbnail {
+		return errors.New("thumbnail is too big")
+	}
+	return nil
+}
+
+func TestPublish_publishVideo(t *testing.T) {
+	tests := []struct {
+		name           string
+		publisher      fakePublisher
+		expected       PublishResult
+		hugoExists     bool
+		videoId        string
+		publishPending []string
+	}{
+		{
+			name:       "success",
+			expected:   PublishResult{Completed: []string{publishStepHugo, publishStepUpload, publishStepThumbnail}},
+			hugoExists: true,
+			videoId:    "abc",
+		},
+		{
+			name:           "hugo failure",
+			publisher:      fakePublisher{failHugo: true},
+			expected:       PublishResult{Pending: []string{publishStepHugo, publishStepUpload, publishStepThumbnail}},
+			publishPending: []string{publishStepHugo, publishStepUpload, publishStepThumbnail},
+		},
+		{
+			name:           "upload failure",
+			publisher:      fakePublisher{failUpload: true},
+			expected:       PublishResult{RolledBack: []string{publishStepHugo}, Pending: []string{publishStepHugo, publishStepUpload, publishStepThumbnail}},
+			publishPending: []string{publishStepHugo, publishStepUpload, publishStepThumbnail},
+		},
+		{
+			name:           "thumbnail failure",
+			publisher:      fakePublisher{failThumbnail: true},
+			expected:       PublishResult{Completed: []string{publishStepHugo, publishStepUpload}, Pending: []string{publishStepThumbnail}},
+			hugoExists:     true,
+			videoId:        "abc",
+			publishPending: []string{publishStepThumbnail},
+		},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		test.publisher.dir = dir
+		path := filepath.Join(dir, "video.yaml")
+		video := Video{Title: "Something", UploadVideo: "video.mp4"}
+		result := publishVideo(&video, test.publisher, true, true)
+		if (result.Err == nil) != (len(test.expected.Pending) == 0) {
+			t.Errorf("%s: unexpected error %v", test.name, result.Err)
+		}
+		result.Err = nil
+		if !slices.Equal(result.Completed, test.expected.Completed) || !slices.Equal(result.RolledBack, test.expected.RolledBack) || !slices.Equal(result.Pending, test.expected.Pending) {
+			t.Errorf("%s\nExpected: %v\nGot: %v", test.name, test.expected, result)
+		}
+		yaml := YAML{}
+		yaml.WriteVideo(&video, path)
+		saved := yaml.GetVideo(path)
+		if saved.VideoId != test.videoId || !slices.Equal(saved.PublishPending, test.publishPending) {
+			t.Errorf("%s: expected video ID %q and pending %v, but got %q and %v", test.name, test.videoId, test.publishPending, saved.VideoId, saved.PublishPending)
+		}
+		_, err := os.Stat(filepath.Join(dir, "something", "_index.md"))
+		if hugoExists := err == nil; hugoExists != test.hugoExists || (len(saved.HugoPath) > 0) != test.hugoExists {
+			t.Errorf("%s: expected the Hugo post to exist=%t, but got %t with the path %q", test.name, test.hugoExists, hugoExists, saved.HugoPath)
+		}
+	}
+}
+
+func TestPublish_publishVideoResumesPending(t *testing.T) {
+	video := Video{Title: "Something", HugoPath: "post/_index.md", VideoId: "abc", PublishPending: []string{publishStepThumbnail}}
+	result := publishVideo(&video, fakePublisher{dir: t.TempDir()}, true, false)
+	if !slices.Equal(result.Completed, []string{publishStepThumbnail}) || result.Err != nil {
+		t.Errorf("Expected only the thumbnail to be uploaded, but got %v", result)
+	}
+	if len(video.PublishPending) != 0 {
+		t.Errorf("Expected no pending steps, but got %v", video.PublishPending)
+	}
+}
+
+// A Hugo post that was rolled back is created on the next run together with the upload.
+func TestPublish_publishVideoResumesRolledBackHugo(t *testing.T) {
+	dir := t.TempDir()
+	video := Video{Title: "Something", UploadVideo: "video.mp4"}
+	publishVideo(&video, fakePublisher{dir: dir, failUpload: true}, true, true)
+	result := publishVideo(&video, fakePublisher{dir: dir}, true, false)
+	expected := []string{publishStepHugo, publishStepUpload, publishStepThumbnail}
+	if !slices.Equal(result.Completed, expected) || result.Err != nil {
+		t.Errorf("Expected: %v\nGot: %v", expected, result)
+	}
+	if len(video.PublishPending) != 0 || len(video.HugoPath) == 0 {
+		t.Errorf("Expected: the Hugo post and no pending steps\nGot: %q and %v", video.HugoPath, video.PublishPending)
+	}
+}
+
+func TestPublish_removeHugoPost(t *testing.T) {
+	tests := map[string]struct {
+		dir      string
+		other    bool
+		dirExist bool
+	}{
+		"own directory":        {dir: "something"},
+		"own directory in use": {dir: "something", other: true, dirExist: true},
+		"shared directory":     {dir: "posts", dirExist: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), test.dir)
+			path := filepath.Join(dir, "_index.md")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte("post"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if test.other {
+				if err := os.WriteFile(filepath.Join(dir, "image.png"), []byte("image"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := removeHugoPost(path, "something"); err != nil {
+				t.Fatalf("Expected: the post to be removed\nGot: %v", err)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("Expected: %s to be removed\nGot: %v", path, err)
+			}
+			if _, err := os.Stat(dir); (err == nil) != test.dirExist {
+				t.Errorf("Expected: directory exists %t\nGot: %v", test.dirExist, err)
+			}
+		})
+	}
+}
+
+func TestPublish_youTubePublisherUploadThumbnailInvalid(t *testing.T) {
+	video := Video{VideoId: "abc", Thumbnail: filepath.Join(t.TempDir(), "missing.png")}
+	if err := (youTubePublisher{}).UploadThumbnail(video); err == nil {
+		t.Errorf("Expected the invalid thumbnail not to be uploaded")
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+const qualityTitle = "title"
+const qualityDescription = "description"
+const qualityTags = "tags"
+const qualityTweet = "tweet"
+const qualityThumbnailText = "thumbnailText"
+const qualityChapters = "chapters"
+
+// qualityChecks is the order in which the breakdown is shown.
+var qualityChecks = []string{qualityTitle, qualityDescription, qualityTags, qualityTweet, qualityThumbnailText, qualityChapters}
+
+// tweetPlaceholderRegex finds anything that looks like a placeholder. Only [HIGHLIGHT] is replaced when posting.
+var tweetPlaceholderRegex = regexp.MustCompile(`\[[A-Za-z][A-Za-z _-]*\]`)
+
+func getDefaultQualityWeights() map[string]int {
+	return map[string]int{
+		qualityTitle:         25,
+		qualityDescription:   20,
+		qualityTags:          15,
+		qualityTweet:         15,
+		qualityThumbnailText: 10,
+		qualityChapters:      15,
+	}
+}
+
+// QualityCheck is one of the heuristics. Ratio is the part of the weight that was earned and Lost explains the rest.
+type QualityCheck struct {
+	Name   string
+	Weight int
+	Ratio  float64
+	Lost   []string
+}
+
+func (c QualityCheck) Points() float64 {
+	return float64(c.Weight) * c.Ratio
+}
+
+type QualityScore struct {
+	Score  int
+	Checks []QualityCheck
+}
+
+// getQualityScore rates how ready the metadata of the video is from 0 to 100. Each check contributes its weight multiplied by how much of it passed.
+// The checks reuse the hints and the linters shown while editing so that the score never disagrees with them.
+func getQualityScore(video Video, weights map[string]int, aliases map[string]string) QualityScore {
+	checks := map[string]func(Video, map[string]string) (float64, []string){
+		qualityTitle:         checkTitleQuality,
+		qualityDescription:   checkDescriptionQuality,
+		qualityTags:          checkTagsQuality,
+		qualityTweet:         checkTweetQuality,
+		qualityThumbnailText: checkThumbnailTextQuality,
+		qualityChapters:      checkChaptersQuality,
+	}
+	score := QualityScore{}
+	total, earned := 0, 0.0
+	for _, name := range qualityChecks {
+		weight := weights[name]
+		if weight <= 0 {
+			continue
+		}
+		ratio, lost := checks[name](video, aliases)
+		check := QualityCheck{Name: name, Weight: weight, Ratio: math.Max(0, ratio), Lost: lost}
+		score.Checks = append(score.Checks, check)
+		total += weight
+		earned += check.Points()
+	}
+	if total > 0 {
+		score.Score = int(math.Round(earned * 100 / float64(total)))
+	}
+	return score
+}
+
+func isQualityFieldEmpty(value string) bool {
+	value = strings.TrimSpace(value)
+	return len(value) == 0 || value == "N/A" || value == "-"
+}
+
+// checkTitleQuality gives half of the points for the length and half for a keyword (the primary target keyword if set, otherwise the project name or one of the tags).
+func checkTitleQuality(video Video, _ map[string]string) (float64, []string) {
+	if isQualityFieldEmpty(video.Title) {
+		return 0, []string{"the title is not set"}
+	}
+	ratio, lost := 1.0, []string{}
+	if hint := getTitleHint(video.Title); hint.Warning {
+		ratio -= 0.5
+		lost = append(lost, hint.Text)
+	}
+	if keyword := getPrimaryKeyword(video); len(keyword) > 0 {
+		if !isKeywordInTitle(video) {
+			ratio -= 0.5
+			lost = append(lost, fmt.Sprintf("the title does not contain the primary keyword %q", keyword))
+		}
+		return ratio, lost
+	}
+	keywords := splitTags(video.Tags)
+	if !isQualityFieldEmpty(video.ProjectName) {
+		keywords = append(keywords, video.ProjectName)
+	}
+	title := strings.ToLower(video.Title)
+	found := false
+	for _, keyword := range keywords {
+		if len(keyword) > 0 && strings.Contains(title, strings.ToLower(keyword)) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		ratio -= 0.5
+		lost = append(lost, "the title contains neither the project name nor any of the tags")
+	}
+	return ratio, lost
+}
+
+// checkDescriptionQuality loses all the points for linter errors and a quarter for each warning or a primary keyword missing from the beginning.
+func checkDescriptionQuality(video Video, _ map[string]string) (float64, []string) {
+	if isQualityFieldEmpty(video.Description) {
+		return 0, []string{"the description is not set"}
+	}
+	ratio, lost := 1.0, []string{}
+	if keyword := getPrimaryKeyword(video); len(keyword) > 0 && !isKeywordInDescriptionPrefix(video) {
+		ratio -= 0.25
+		lost = append(lost, fmt.Sprintf("the first %d characters of the description do not contain the primary keyword %q", keywordsDescriptionPrefix, keyword))
+	}
+	for _, finding := range lintVideoDescription(video) {
+		if finding.Severity == findingError {
+			ratio = 0
+		} else {
+			ratio -= 0.25
+		}
+		lost = append(lost, finding.Message)
+	}
+	return ratio, lost
+}
+
+func checkTagsQuality(video Video, aliases map[string]string) (float64, []string) {
+	if len(splitTags(video.Tags)) == 0 {
+		return 0, []string{"there are no tags"}
+	}
+	ratio, lost := 1.0, []string{}
+	if hint := getTagsHint(video.Tags); hint.Warning {
+		ratio -= 0.5
+		lost = append(lost, hint.Text)
+	}
+	if warnings := getTagAliasWarnings(video.Tags, aliases); len(warnings) > 0 {
+		ratio -= 0.5
+		lost = append(lost, warnings...)
+	}
+	return ratio, lost
+}
+
+// checkTweetQuality requires placeholders to be known and [HIGHLIGHT] to have a timestamp to link to.
+func checkTweetQuality(video Video, _ map[string]string) (float64, []string) {
+	if isQualityFieldEmpty(video.Tweet) {
+		return 0, []string{"the tweet is not set"}
+	}
+	ratio, lost := 1.0, []string{}
+	if hint := getTweetHint(video.Tweet); hint.Warning {
+		ratio -= 0.5
+		lost = append(lost, hint.Text)
+	}
+	for _, placeholder := range tweetPlaceholderRegex.FindAllString(video.Tweet, -1) {
+		if placeholder != "[HIGHLIGHT]" {
+			ratio -= 0.5
+			lost = append(lost, fmt.Sprintf("%s is not a known placeholder and would be posted as it is", placeholder))
+		} else if len(video.HighlightTimestamp) == 0 {
+			ratio -= 0.5
+			lost = append(lost, "[HIGHLIGHT] has no highlight timestamp and would link to the start of the video")
+		}
+	}
+	return ratio, lost
+}
+
+func checkThumbnailTextQuality(video Video, _ map[string]string) (float64, []string) {
+	if isQualityFieldEmpty(video.ThumbnailText) {
+		return 0, []string{"the thumbnail text is not set"}
+	}
+	if err := validateThumbnailText(video.ThumbnailText); err != nil {
+		return 0.5, []string{err.Error()}
+	}
+	return 1, nil
+}
+
+// checkChaptersQuality loses all the points when YouTube would not show the chapters and half of them when they can be fixed automatically.
+func checkChaptersQuality(video Video, _ map[string]string) (float64, []string) {
+	if isQualityFieldEmpty(video.Timecodes) {
+		return 0, []string{"there are no chapters"}
+	}
+	_, violations := getChapterViolations(video.Timecodes)
+	ratio, lost := 1.0, []string{}
+	for _, violation := range violations {
+		if violation.Blocker {
+			ratio = 0
+		} else if ratio > 0 {
+			ratio = 0.5
+		}
+		lost = append(lost, violation.String())
+	}
+	return ratio, lost
+}
+
+func getQualityScoreText(score QualityScore) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Quality score: %d/100", score.Score))
+	for _, check := range score.Checks {
+		if len(check.Lost) == 0 {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("\n%s (-%s): %s", check.Name, formatQualityPoints(float64(check.Weight)-check.Points()), strings.Join(check.Lost, "; ")))
+	}
+	return builder.String()
+}
+
+func formatQualityPoints(points float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", points), "0"), ".")
+}
+
+// getAverageQualityScore returns zero when there are no videos.
+func getAverageQualityScore(videos []Video, weights map[string]int, aliases map[string]string) int {
+	if len(videos) == 0 {
+		return 0
+	}
+	total := 0
+	for _, video := range videos {
+		total += getQualityScore(video, weights, aliases).Score
+	}
+	return int(math.Round(float64(total) / float64(len(videos))))
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func getQualityTestVideos() map[string]Video {
+	return map[string]Video{
+		"complete": {
+			Title:              "Argo CD Explained",
+			ProjectName:        "Argo CD",
+			Description:        "Everything about GitOps with Argo CD.",
+			Tags:               "argo cd,gitops,kubernetes",
+			Tweet:              "Argo CD explained. Jump to the best part: [HIGHLIGHT]",
+			HighlightTimestamp: "01:00",
+			ThumbnailText:      "GitOps done right",
+			Timecodes:          "00:00 Intro\n01:00 Setup\n05:00 Demo",
+		},
+		"flawed": {
+			Title:         strings.Repeat("Something very long ", 5),
+			Description:   "Everything about GitOps.",
+			Tags:          "k8s,gitops",
+			Tweet:         "Watch it at [YouTube Link]",
+			ThumbnailText: "This thumbnail text has far too many words in it",
+			Timecodes:     "00:10 Intro\n01:00 Setup\n05:00 Demo",
+		},
+		"empty": {},
+	}
+}
+
+func TestQuality_getQualityScore(t *testing.T) {
+	aliases := map[string]string{"k8s": "kubernetes"}
+	tests := []struct {
+		video    string
+		expected int
+	}{
+		{"complete", 100},
+		{"flawed", 48},
+		{"empty", 0},
+	}
+	videos := getQualityTestVideos()
+	for _, test := range tests {
+		if actual := getQualityScore(videos[test.video], getDefaultQualityWeights(), aliases).Score; actual != test.expected {
+			t.Errorf("%s\nExpected: %d\nGot: %d\n%s", test.video, test.expected, actual, getQualityScoreText(getQualityScore(videos[test.video], getDefaultQualityWeights(), aliases)))
+		}
+	}
+}
+
+func TestQuality_getQualityScoreBreakdown(t *testing.T) {
+	score := getQualityScore(getQualityTestVideos()["flawed"], getDefaultQualityWeights(), map[string]string{"k8s": "kubernetes"})
+	ratios := map[string]float64{}
+	for _, check := range score.Checks {
+		ratios[check.Name] = check.Ratio
+	}
+	expected := map[string]float64{
+		qualityTitle:         0,
+		qualityDescription:   1,
+		qualityTags:          0.5,
+		qualityTweet:         0.5,
+		qualityThumbnailText: 0.5,
+		qualityChapters:      0.5,
+	}
+	if !reflect.DeepEqual(ratios, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, ratios)
+	}
+	text := getQualityScoreText(score)
+	for _, line := range []string{"Quality score: 48/100", "tags (-7.5): k8s should be kubernetes", "tweet (-7.5): [YouTube Link] is not a known placeholder"} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Expected: %s\nGot: %s", line, text)
+		}
+	}
+	if strings.Contains(text, "description") {
+		t.Errorf("Expected checks without lost points to be left out\nGot: %s", text)
+	}
+}
+
+func TestQuality_getQualityScoreWeights(t *testing.T) {
+	video := getQualityTestVideos()["complete"]
+	video.Timecodes = ""
+	weights := getDefaultQualityWeights()
+	if actual := getQualityScore(video, weights, nil).Score; actual != 85 {
+		t.Errorf("Expected missing chapters to cost their weight of 15\nGot: %d", actual)
+	}
+	weights[qualityChapters] = 0
+	if actual := getQualityScore(video, weights, nil).Score; actual != 100 {
+		t.Errorf("Expected disabled chapters not to count\nGot: %d", actual)
+	}
+	weights = map[string]int{qualityTitle: 1, qualityChapters: 3}
+	if actual := getQualityScore(video, weights, nil).Score; actual != 25 {
+		t.Errorf("Expected chapters to be three quarters of the score\nGot: %d", actual)
+	}
+	if actual := getQualityScore(video, map[string]int{}, nil).Score; actual != 0 {
+		t.Errorf("Expected no score without weights\nGot: %d", actual)
+	}
+}
+
+func TestQuality_getAverageQualityScore(t *testing.T) {
+	videos := getQualityTestVideos()
+	if actual := getAverageQualityScore([]Video{videos["complete"], videos["empty"]}, getDefaultQualityWeights(), nil); actual != 50 {
+		t.Errorf("Expected: 50\nGot: %d", actual)
+	}
+	if actual := getAverageQualityScore(nil, getDefaultQualityWeights(), nil); actual != 0 {
+		t.Errorf("Expected: 0\nGot: %d", actual)
+	}
+}
+
+func TestQuality_getQualityScoreKeywords(t *testing.T) {
+	tests := map[string]struct {
+		keywords    string
+		description string
+		expected    map[string]float64
+	}{
+		"primary keyword in the title and the description": {
+			keywords: "argo cd,gitops tutorial",
+			expected: map[string]float64{qualityTitle: 1, qualityDescription: 1},
+		},
+		"primary keyword missing": {
+			keywords: "gitops tutorial,argo cd",
+			expected: map[string]float64{qualityTitle: 0.5, qualityDescription: 0.75},
+		},
+		"primary keyword after the first 150 characters": {
+			keywords:    "argo cd",
+			description: strings.Repeat("GitOps ", 25) + "with Argo CD.",
+			expected:    map[string]float64{qualityTitle: 1, qualityDescription: 0.75},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			video := getQualityTestVideos()["complete"]
+			video.TargetKeywords = tc.keywords
+			if len(tc.description) > 0 {
+				video.Description = tc.description
+			}
+			ratios := map[string]float64{}
+			for _, check := range getQualityScore(video, getDefaultQualityWeights(), nil).Checks {
+				if _, ok := tc.expected[check.Name]; ok {
+					ratios[check.Name] = check.Ratio
+				}
+			}
+			if !reflect.DeepEqual(ratios, tc.expected) {
+				t.Errorf("Expected: %v\nGot: %v", tc.expected, ratios)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"gopkg.in/yaml.v3"
+)
+
+const indexDiscrepancyMissing = "missing"
+const indexDiscrepancyUnindexed = "unindexed"
+const indexDiscrepancyMismatch = "mismatch"
+const indexDiscrepancyDuplicate = "duplicate"
+const indexDiscrepancyUnreadable = "unreadable"
+
+// IndexDiscrepancy is a difference between the index and the video files found while rebuilding the index.
+type IndexDiscrepancy struct {
+	Kind    string
+	Path    string
+	Message string
+}
+
+// readIndexFile reads the index without stopping on a corrupted file since that is what rebuilding the index recovers from.
+func readIndexFile(path string) ([]VideoIndex, error) {
+	index := []VideoIndex{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return index, err
+	}
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return []VideoIndex{}, fmt.Errorf("index %s is corrupted: %w", path, err)
+	}
+	return index, nil
+}
+
+func getIndexEntryPath(root string, vi VideoIndex, extension string) string {
+	return filepath.Join(root, getImportCategoryDir(vi.Category), fmt.Sprintf("%s.%s", getVideoFileName(vi.Name), extension))
+}
+
+// findIndexVideoFiles returns the video YAML files in the category directories of the root, sorted by their paths.
+func findIndexVideoFiles(root string, ignore *IgnoreMatcher) ([]string, error) {
+	categories, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, category := range categories {
+		if !category.IsDir() || ignore.Match(category.Name(), true) {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(root, category.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if !file.IsDir() && filepath.Ext(file.Name()) == ".yaml" && !ignore.Match(filepath.Join(category.Name(), file.Name()), false) {
+				paths = append(paths, filepath.Join(root, category.Name(), file.Name()))
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// isVideoFileNamed is true if the video file does not store a name or the name is sanitized into the file name.
+func isVideoFileNamed(video Video, path string) bool {
+	if len(video.Name) == 0 {
+		return true
+	}
+	return getVideoFileName(video.Name) == strings.TrimSuffix(filepath.Base(path), ".yaml")
+}
+
+// getVideoFileMismatch compares the name and the category stored in the video file with its location. It is empty when they match or are not stored.
+func getVideoFileMismatch(video Video, path string) string {
+	mismatches := []string{}
+	if !isVideoFileNamed(video, path) {
+		mismatches = append(mismatches, fmt.Sprintf("name %q does not match the file name %s", video.Name, strings.TrimSuffix(filepath.Base(path), ".yaml")))
+	}
+	category := filepath.Base(filepath.Dir(path))
+	if len(video.Category) > 0 && getImportCategoryDir(video.Category) != category {
+		mismatches = append(mismatches, fmt.Sprintf("category %q does not match the directory %s", video.Category, category))
+	}
+	return strings.Join(mismatches, ", ")
+}
+
+// rebuildIndex returns the index that matches the video files in the root and the discrepancies with the current index.
+// Entries with a YAML file or, since the video file is written on the first edit, a manuscript are kept in their order.
+// Files that are not indexed are added after them, named after the name stored in the file if it matches the file name and after the file otherwise.
+func rebuildIndex(root string, index []VideoIndex, ignore *IgnoreMatcher) ([]VideoIndex, []IndexDiscrepancy, error) {
+	paths, err := findIndexVideoFiles(root, ignore)
+	if err != nil {
+		return index, nil, err
+	}
+	rebuilt := []VideoIndex{}
+	discrepancies := []IndexDiscrepancy{}
+	indexed := map[string]bool{}
+	for _, vi := range index {
+		path := getIndexEntryPath(root, vi, "yaml")
+		if indexed[path] {
+			discrepancies = append(discrepancies, IndexDiscrepancy{Kind: indexDiscrepancyDuplicate, Path: path, Message: fmt.Sprintf("%s (%s) is indexed more than once", vi.Name, vi.Category)})
+			continue
+		}
+		_, yamlErr := os.Stat(path)
+		_, mdErr := os.Stat(getIndexEntryPath(root, vi, "md"))
+		if yamlErr != nil && mdErr != nil {
+			discrepancies = append(discrepancies, IndexDiscrepancy{Kind: indexDiscrepancyMissing, Path: path, Message: fmt.Sprintf("%s (%s) has no files", vi.Name, vi.Category)})
+			continue
+		}
+		indexed[path] = true
+		rebuilt = append(rebuilt, vi)
+	}
+	for _, path := range paths {
+		video, err := readVideo(path)
+		if err != nil {
+			discrepancies = append(discrepancies, IndexDiscrepancy{Kind: indexDiscrepancyUnreadable, Path: path, Message: err.Error()})
+			video = Video{}
+		}
+		mismatch := getVideoFileMismatch(video, path)
+		if len(mismatch) > 0 {
+			discrepancies = append(discrepancies, IndexDiscrepancy{Kind: indexDiscrepancyMismatch, Path: path, Message: mismatch})
+		}
+		if indexed[path] {
+			continue
+		}
+		vi := VideoIndex{Name: strings.TrimSuffix(filepath.Base(path), ".yaml"), Category: filepath.Base(filepath.Dir(path))}
+		if len(video.Name) > 0 && isVideoFileNamed(video, path) {
+			vi.Name = video.Name
+		}
+		discrepancies = append(discrepancies, IndexDiscrepancy{Kind: indexDiscrepancyUnindexed, Path: path, Message: fmt.Sprintf("%s (%s) is not indexed", vi.Name, vi.Category)})
+		indexed[path] = true
+		rebuilt = append(rebuilt, vi)
+	}
+	return rebuilt, discrepancies, nil
+}
+
+func getIndexDiscrepanciesText(discrepancies []IndexDiscrepancy) string {
+	lines := []string{}
+	for _, discrepancy := range discrepancies {
+		lines = append(lines, fmt.Sprintf("%s: %s: %s", discrepancy.Kind, discrepancy.Path, discrepancy.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ChooseRebuildIndex recreates index.yaml from the video files after showing what would change.
+func (c *Choices) ChooseRebuildIndex(indexPath string) error {
+	index, readErr := readIndexFile(indexPath)
+	if readErr != nil {
+		output.Warn(fmt.Sprintf("%s. It will be rebuilt from the video files only.", readErr))
+	}
+	rebuilt, discrepancies, err := rebuildIndex("manuscript", index, getManuscriptIgnore())
+	if err != nil {
+		return err
+	}
+	if len(discrepancies) > 0 {
+		output.ResultText(getIndexDiscrepanciesText(discrepancies))
+	}
+	if readErr == nil && slices.Equal(rebuilt, index) {
+		output.Result("The index matches the video files.")
+		return nil
+	}
+	confirmed := false
+	form := newForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Write the index with %d videos (currently %d)?", len(rebuilt), len(index))).
+				Affirmative("Write").
+				Negative("Cancel").
+				Value(&confirmed),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+	yaml := YAML{IndexPath: indexPath}
+	if err := yaml.writeIndex(rebuilt); err != nil {
+		return err
+	}
+	output.Info(fmt.Sprintf("The index was rebuilt with %d videos.", len(rebuilt)))
+	return nil
+}
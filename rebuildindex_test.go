@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeRebuildIndexTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getRebuildIndexTestRoot(t *testing.T) string {
+	root := filepath.Join(t.TempDir(), "manuscript")
+	writeRebuildIndexTestFile(t, filepath.Join(root, "development", "kubernetes-operators.yaml"), "name: Kubernetes Operators\ncategory: development\n")
+	writeRebuildIndexTestFile(t, filepath.Join(root, "development", "kubernetes-operators.md"), "## Intro\n")
+	writeRebuildIndexTestFile(t, filepath.Join(root, "devops", "argo-cd.yaml"), "name: Argo CD\ncategory: devops\n")
+	// Created but never edited so there is only the manuscript.
+	writeRebuildIndexTestFile(t, filepath.Join(root, "devops", "flux.md"), "## Intro\n")
+	return root
+}
+
+func TestRebuildIndex_rebuildIndexHealthy(t *testing.T) {
+	root := getRebuildIndexTestRoot(t)
+	index := []VideoIndex{
+		{Name: "Argo CD", Category: "devops"},
+		{Name: "Kubernetes Operators", Category: "development"},
+		{Name: "Flux", Category: "devops"},
+	}
+	rebuilt, discrepancies, err := rebuildIndex(root, index, NewIgnoreMatcher(nil))
+	if err != nil {
+		t.Fatalf("Expected: no error\nGot: %v", err)
+	}
+	if !reflect.DeepEqual(rebuilt, index) || len(discrepancies) != 0 {
+		t.Errorf("Expected: the index unchanged without discrepancies\nGot: %v %v", rebuilt, discrepancies)
+	}
+	again, _, _ := rebuildIndex(root, rebuilt, NewIgnoreMatcher(nil))
+	if !reflect.DeepEqual(again, rebuilt) {
+		t.Errorf("Expected: rebuilding to be idempotent\nGot: %v", again)
+	}
+}
+
+func TestRebuildIndex_rebuildIndexBroken(t *testing.T) {
+	root := getRebuildIndexTestRoot(t)
+	writeRebuildIndexTestFile(t, filepath.Join(root, "devops", "renamed.yaml"), "name: Old Name\ncategory: development\n")
+	writeRebuildIndexTestFile(t, filepath.Join(root, "devops", "broken.yaml"), "name: [\n")
+	writeRebuildIndexTestFile(t, filepath.Join(root, "ignored", "video.yaml"), "name: Video\n")
+	index := []VideoIndex{
+		{Name: "Kubernetes Operators", Category: "development"},
+		{Name: "Deleted", Category: "devops"},
+		{Name: "Kubernetes Operators", Category: "development"},
+		{Name: "Flux", Category: "devops"},
+	}
+	ignore := NewIgnoreMatcher([]string{"ignored/"})
+	rebuilt, discrepancies, err := rebuildIndex(root, index, ignore)
+	if err != nil {
+		t.Fatalf("Expected: no error\nGot: %v", err)
+	}
+	expected := []VideoIndex{
+		{Name: "Kubernetes Operators", Category: "development"},
+		{Name: "Flux", Category: "devops"},
+		{Name: "Argo CD", Category: "devops"},
+		{Name: "broken", Category: "devops"},
+		{Name: "renamed", Category: "devops"},
+	}
+	if !reflect.DeepEqual(rebuilt, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, rebuilt)
+	}
+	kinds := map[string]int{}
+	for _, discrepancy := range discrepancies {
+		kinds[discrepancy.Kind]++
+	}
+	expectedKinds := map[string]int{indexDiscrepancyDuplicate: 1, indexDiscrepancyMissing: 1, indexDiscrepancyUnindexed: 3, indexDiscrepancyMismatch: 1, indexDiscrepancyUnreadable: 1}
+	if !reflect.DeepEqual(kinds, expectedKinds) {
+		t.Errorf("Expected: %v\nGot: %v\n%s", expectedKinds, kinds, getIndexDiscrepanciesText(discrepancies))
+	}
+	again, discrepancies, _ := rebuildIndex(root, rebuilt, ignore)
+	if !reflect.DeepEqual(again, rebuilt) {
+		t.Errorf("Expected: the fixed index to stay the same\nGot: %v", again)
+	}
+	for _, discrepancy := range discrepancies {
+		if discrepancy.Kind != indexDiscrepancyMismatch && discrepancy.Kind != indexDiscrepancyUnreadable {
+			t.Errorf("Expected: only the problems inside the files to remain\nGot: %+v", discrepancy)
+		}
+	}
+}
+
+func TestRebuildIndex_readIndexFile(t *testing.T) {
+	dir := t.TempDir()
+	if index, err := readIndexFile(filepath.Join(dir, "missing.yaml")); err != nil || len(index) != 0 {
+		t.Errorf("Expected: an empty index\nGot: %v (%v)", index, err)
+	}
+	path := filepath.Join(dir, "index.yaml")
+	writeRebuildIndexTestFile(t, path, "- name: Argo CD\n  category: devops\n- name: [\n")
+	if index, err := readIndexFile(path); err == nil || len(index) != 0 {
+		t.Errorf("Expected: an error and an empty index for a corrupted file\nGot: %v (%v)", index, err)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type RecordingSession = workflow.RecordingSession
+
+const recordCheckDemo = "Demo environment is created"
+const recordCheckManuscript = "Manuscript has no TODOs"
+const recordCheckLocation = "Files location is set"
+
+func getDefaultRecordChecklist() []string {
+	return []string{
+		"Camera settings (exposure, focus, white balance)",
+		"Audio check",
+		"Screen resolution",
+		"Manuscript is open",
+		"OBS scenes",
+	}
+}
+
+// RecordChecklistItem is a step of a recording session. Automatic items are checked by the tool and Detail explains the result.
+type RecordChecklistItem struct {
+	Title     string
+	Automatic bool
+	Passed    bool
+	Detail    string
+}
+
+// getManuscriptTODOs returns the number of lines of the manuscript that still have TODO or FIXME placeholders.
+func getManuscriptTODOs(manuscript string) int {
+	count := 0
+	for _, line := range strings.Split(manuscript, "\n") {
+		if strings.Contains(line, "TODO") || strings.Contains(line, "FIXME") {
+			count++
+		}
+	}
+	return count
+}
+
+// getRecordChecklist returns the automatic checks followed by the common items and the items of the category of the video.
+func getRecordChecklist(video Video, manuscript string, record SettingsRecord) []RecordChecklistItem {
+	demos := len(getUndestroyedDemos(video))
+	todos := getManuscriptTODOs(manuscript)
+	checklist := []RecordChecklistItem{
+		{Title: recordCheckDemo, Automatic: true, Passed: demos > 0, Detail: fmt.Sprintf("%d demo environments are logged as created.", demos)},
+		{Title: recordCheckManuscript, Automatic: true, Passed: todos == 0, Detail: fmt.Sprintf("%d lines of the manuscript have TODO or FIXME.", todos)},
+		{Title: recordCheckLocation, Automatic: true, Passed: len(video.Location) > 0, Detail: fmt.Sprintf("Files location: %s", video.Location)},
+	}
+	for _, title := range append(append([]string{}, record.Checklist...), record.Categories[video.Category]...) {
+		checklist = append(checklist, RecordChecklistItem{Title: title})
+	}
+	return checklist
+}
+
+// getRemainingRecordChecklist removes the items that were confirmed earlier in the session.
+func getRemainingRecordChecklist(checklist []RecordChecklistItem, session RecordingSession) []RecordChecklistItem {
+	remaining := []RecordChecklistItem{}
+	for _, item := range checklist {
+		if !slices.Contains(session.Checked, item.Title) {
+			remaining = append(remaining, item)
+		}
+	}
+	return remaining
+}
+
+// startRecordingSession resumes the last session that was not completed or starts a new one. It returns the video and the index of the session.
+func startRecordingSession(video Video, now time.Time) (Video, int, bool) {
+	if count := len(video.RecordingSessions); count > 0 && len(video.RecordingSessions[count-1].Completed) == 0 {
+		return video, count - 1, true
+	}
+	video.RecordingSessions = append(slices.Clone(video.RecordingSessions), RecordingSession{Started: now.Format(dateFormat)})
+	return video, len(video.RecordingSessions) - 1, false
+}
+
+// pauseRecordingSession adds the time spent since the session was (re)started so that it can be resumed later.
+func pauseRecordingSession(video Video, index int, elapsed time.Duration) Video {
+	video.RecordingSessions = slices.Clone(video.RecordingSessions)
+	video.RecordingSessions[index].Seconds += int(elapsed.Round(time.Second).Seconds())
+	return video
+}
+
+// completeRecordingSession marks what was recorded as done, updates the progress of the work phase, and appends a note with the duration of the session.
+// What was not recorded is left as it was.
+func completeRecordingSession(video Video, index int, elapsed time.Duration, head, screen bool, now time.Time) Video {
+	video = pauseRecordingSession(video, index, elapsed)
+	session := &video.RecordingSessions[index]
+	session.Completed = now.Format(dateFormat)
+	recorded := []string{}
+	if head {
+		video.Head = true
+		recorded = append(recorded, "talking head")
+	}
+	if screen {
+		video.Screen = true
+		recorded = append(recorded, "screen")
+	}
+	video.Work = workflow.GetProgress(getPhaseFields(video, customFieldPhaseWork, settings))
+	what := "nothing was recorded"
+	if len(recorded) > 0 {
+		what = fmt.Sprintf("recorded %s", strings.Join(recorded, " and "))
+	}
+	note := fmt.Sprintf("Recording session on %s took %s, %s.", now.Format(dayFormat), time.Duration(session.Seconds)*time.Second, what)
+	video.Notes = append(slices.Clone(video.Notes), note)
+	return video
+}
+
+func getRecordingSessionsText(sessions []RecordingSession) string {
+	if len(sessions) == 0 {
+		return "There were no recording sessions."
+	}
+	lines := []string{}
+	for _, session := range sessions {
+		status := "not completed"
+		if len(session.Completed) > 0 {
+			status = fmt.Sprintf("completed %s", session.Completed)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s, %s", session.Started, time.Duration(session.Seconds)*time.Second, status))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecord_getRecordChecklist(t *testing.T) {
+	record := SettingsRecord{
+		Checklist:  []string{"Audio check", "OBS scenes"},
+		Categories: map[string][]string{"ama": {"Chat is open"}},
+	}
+	video := Video{
+		Category:         "ama",
+		Location:         "https://drive.example.com/video",
+		DemoEnvironments: []DemoEnvironment{{Provider: "GKE", Destroyed: true}},
+	}
+	checklist := getRecordChecklist(video, "## Intro\n\nFIXME: Intro\nTODO: Demo\n", record)
+	titles := []string{}
+	for _, item := range checklist {
+		titles = append(titles, item.Title)
+	}
+	expected := []string{recordCheckDemo, recordCheckManuscript, recordCheckLocation, "Audio check", "OBS scenes", "Chat is open"}
+	if !slices.Equal(titles, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, titles)
+	}
+	if checklist[0].Passed || checklist[1].Passed || !checklist[2].Passed {
+		t.Errorf("Expected only the location check to pass\nGot: %+v", checklist[:3])
+	}
+	if checklist[1].Detail != "2 lines of the manuscript have TODO or FIXME." {
+		t.Errorf("Unexpected detail %s", checklist[1].Detail)
+	}
+
+	checklist = getRecordChecklist(Video{Category: "devops"}, "", record)
+	if len(checklist) != 5 {
+		t.Errorf("Expected no category items\nGot: %+v", checklist)
+	}
+}
+
+func TestRecord_completeRecordingSession(t *testing.T) {
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		video          Video
+		head, screen   bool
+		expectedHead   bool
+		expectedScreen bool
+		expectedNote   string
+	}{
+		"head only": {
+			head:         true,
+			expectedHead: true,
+			expectedNote: "Recording session on 2030-01-21 took 45m0s, recorded talking head.",
+		},
+		"head and screen": {
+			head:           true,
+			screen:         true,
+			expectedHead:   true,
+			expectedScreen: true,
+			expectedNote:   "Recording session on 2030-01-21 took 45m0s, recorded talking head and screen.",
+		},
+		"nothing keeps what was recorded before": {
+			video:          Video{Screen: true},
+			expectedScreen: true,
+			expectedNote:   "Recording session on 2030-01-21 took 45m0s, nothing was recorded.",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			video, index, _ := startRecordingSession(tc.video, now.Add(-time.Hour))
+			video = completeRecordingSession(video, index, 45*time.Minute, tc.head, tc.screen, now)
+			if video.Head != tc.expectedHead || video.Screen != tc.expectedScreen {
+				t.Errorf("Expected head %t and screen %t\nGot: %t and %t", tc.expectedHead, tc.expectedScreen, video.Head, video.Screen)
+			}
+			if expected := getBuiltInPhaseProgress(video, customFieldPhaseWork, settings); video.Work != expected || video.Work.Total == 0 {
+				t.Errorf("Expected: work progress %+v\nGot: %+v", expected, video.Work)
+			}
+			if len(video.Notes) != 1 || video.Notes[0] != tc.expectedNote {
+				t.Errorf("Expected: %s\nGot: %v", tc.expectedNote, video.Notes)
+			}
+			if video.RecordingSessions[index].Completed != "2030-01-21T16:00" {
+				t.Errorf("Expected the session to be completed\nGot: %+v", video.RecordingSessions[index])
+			}
+		})
+	}
+}
+
+func TestRecord_startRecordingSession(t *testing.T) {
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	record := SettingsRecord{Checklist: []string{"Audio check", "OBS scenes"}}
+
+	video, index, resumed := startRecordingSession(Video{}, now)
+	if resumed || index != 0 || video.RecordingSessions[0].Started != "2030-01-21T16:00" {
+		t.Fatalf("Expected a new session\nGot: %+v, %d, %t", video.RecordingSessions, index, resumed)
+	}
+	video.RecordingSessions[index].Checked = []string{recordCheckDemo, recordCheckManuscript, recordCheckLocation, "Audio check"}
+	video = pauseRecordingSession(video, index, 10*time.Minute)
+
+	video, index, resumed = startRecordingSession(video, now.Add(time.Hour))
+	if !resumed || index != 0 || len(video.RecordingSessions) != 1 {
+		t.Fatalf("Expected the session to be resumed\nGot: %+v, %d, %t", video.RecordingSessions, index, resumed)
+	}
+	remaining := getRemainingRecordChecklist(getRecordChecklist(video, "", record), video.RecordingSessions[index])
+	if len(remaining) != 1 || remaining[0].Title != "OBS scenes" {
+		t.Errorf("Expected only OBS scenes to remain\nGot: %+v", remaining)
+	}
+	video = completeRecordingSession(video, index, 5*time.Minute, false, true, now.Add(time.Hour))
+	if video.RecordingSessions[index].Seconds != 900 {
+		t.Errorf("Expected the time of both parts of the session\nGot: %d seconds", video.RecordingSessions[index].Seconds)
+	}
+
+	if _, index, resumed = startRecordingSession(video, now.Add(2*time.Hour)); resumed || index != 1 {
+		t.Errorf("Expected a new session after a completed one\nGot: %d, %t", index, resumed)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+const redditAuthURL = "https://www.reddit.com/api/v1/access_token"
+const redditAPIURL = "https://oauth.reddit.com"
+
+var ErrRedditRateLimited = errors.New("Reddit rate limit was reached")
+var ErrRedditFlairRequired = errors.New("subreddit requires a flair")
+
+// Reddit submits links through a script app (https://www.reddit.com/prefs/apps) authenticated with the username and the password of its owner.
+type Reddit struct {
+	client   *http.Client
+	authURL  string
+	apiURL   string
+	settings SettingsReddit
+	delay    time.Duration
+	token    string
+}
+
+type RedditResult struct {
+	Subreddit string
+	URL       string
+	Err       error
+}
+
+type redditSubmitResponse struct {
+	JSON struct {
+		Errors [][]string `json:"errors"`
+		Data   struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	} `json:"json"`
+}
+
+func NewReddit(settings SettingsReddit) *Reddit {
+	return &Reddit{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		authURL:  redditAuthURL,
+		apiURL:   redditAPIURL,
+		settings: settings,
+		delay:    time.Duration(settings.MinDelaySeconds) * time.Second,
+	}
+}
+
+func (r *Reddit) authenticate(ctx context.Context) error {
+	form := url.Values{"grant_type": {"password"}, "username": {r.settings.Username}, "password": {r.settings.Password}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, r.authURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(r.settings.ClientID, r.settings.ClientSecret)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("User-Agent", r.settings.UserAgent)
+	response, err := r.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	token := struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return fmt.Errorf("Reddit authentication failed with %s", response.Status)
+	}
+	if len(token.AccessToken) == 0 {
+		return fmt.Errorf("Reddit authentication failed: %s", token.Error)
+	}
+	r.token = token.AccessToken
+	return nil
+}
+
+// SubmitLink posts the link to the subreddit and returns the URL of the post.
+func (r *Reddit) SubmitLink(ctx context.Context, subreddit SettingsRedditSubreddit, title, link string) (string, error) {
+	if len(r.token) == 0 {
+		if err := r.authenticate(ctx); err != nil {
+			return "", err
+		}
+	}
+	form := url.Values{"api_type": {"json"}, "kind": {"link"}, "sr": {subreddit.Name}, "title": {title}, "url": {link}, "resubmit": {"true"}}
+	if len(subreddit.FlairID) > 0 {
+		form.Set("flair_id", subreddit.FlairID)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, r.apiURL+"/api/submit", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Authorization", "Bearer "+r.token)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("User-Agent", r.settings.UserAgent)
+	response, err := r.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusTooManyRequests {
+		return "", ErrRedditRateLimited
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Reddit responded with %s", response.Status)
+	}
+	submit := redditSubmitResponse{}
+	if err := json.NewDecoder(response.Body).Decode(&submit); err != nil {
+		return "", err
+	}
+	for _, submitErr := range submit.JSON.Errors {
+		if len(submitErr) == 0 {
+			continue
+		}
+		message := strings.Join(submitErr[1:], ": ")
+		switch submitErr[0] {
+		case "RATELIMIT":
+			return "", fmt.Errorf("%w: %s", ErrRedditRateLimited, message)
+		case "SUBMIT_VALIDATION_FLAIR_REQUIRED":
+			return "", fmt.Errorf("%w: %s", ErrRedditFlairRequired, message)
+		}
+		return "", fmt.Errorf("Reddit rejected the post: %s (%s)", submitErr[0], message)
+	}
+	return submit.JSON.Data.URL, nil
+}
+
+// PostAll submits the link to all the subreddits that were not posted to yet, waiting between submissions to respect the Reddit rate limits.
+// Failures are reported per subreddit so that one failure does not stop the others.
+func (r *Reddit) PostAll(ctx context.Context, subreddits []SettingsRedditSubreddit, posted map[string]string, title, link string) []RedditResult {
+	results := []RedditResult{}
+	for _, subreddit := range subreddits {
+		if len(posted[subreddit.Name]) > 0 {
+			continue
+		}
+		if len(results) > 0 && r.delay > 0 {
+			select {
+			case <-ctx.Done():
+				results = append(results, RedditResult{Subreddit: subreddit.Name, Err: ctx.Err()})
+				continue
+			case <-time.After(r.delay):
+			}
+		}
+		url, err := r.SubmitLink(ctx, subreddit, title, link)
+		results = append(results, RedditResult{Subreddit: subreddit.Name, URL: url, Err: err})
+	}
+	return results
+}
+
+func getRedditTitle(video Video) string {
+	if len(video.RedditTitle) > 0 {
+		return video.RedditTitle
+	}
+	return video.Title
+}
+
+func isRedditPosted(posted map[string]string, subreddits []SettingsRedditSubreddit) bool {
+	return workflow.IsRedditPosted(posted, getSubredditNames(subreddits))
+}
+
+func getSubredditNames(subreddits []SettingsRedditSubreddit) []string {
+	names := []string{}
+	for _, subreddit := range subreddits {
+		names = append(names, subreddit.Name)
+	}
+	return names
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// startFakeRedditServer serves the token and the submit endpoints. Submissions to subreddits in failures are answered with the given Reddit error code.
+func startFakeRedditServer(t *testing.T, failures map[string]string, flairs map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/access_token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "id" || clientSecret != "secret" || r.FormValue("grant_type") != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "invalid_grant"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token": "token"}`)
+	})
+	mux.HandleFunc("/api/submit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		subreddit := r.FormValue("sr")
+		if code, ok := failures[subreddit]; ok {
+			fmt.Fprintf(w, `{"json": {"errors": [["%s", "something went wrong", "sr"]]}}`, code)
+			return
+		}
+		if flair, ok := flairs[subreddit]; ok && r.FormValue("flair_id") != flair {
+			fmt.Fprint(w, `{"json": {"errors": [["SUBMIT_VALIDATION_FLAIR_REQUIRED", "Your post must contain post flair.", "flair"]]}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"json": {"errors": [], "data": {"url": "https://www.reddit.com/r/%s/comments/abc/%s/"}}}`, subreddit, r.FormValue("kind"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getTestReddit(server *httptest.Server) *Reddit {
+	reddit := NewReddit(SettingsReddit{ClientID: "id", ClientSecret: "secret", Username: "user", Password: "pass", UserAgent: "test"})
+	reddit.authURL = server.URL + "/api/v1/access_token"
+	reddit.apiURL = server.URL
+	return reddit
+}
+
+func TestReddit_PostAll(t *testing.T) {
+	server := startFakeRedditServer(t, map[string]string{"golang": "RATELIMIT"}, nil)
+	reddit := getTestReddit(server)
+	subreddits := []SettingsRedditSubreddit{{Name: "kubernetes"}, {Name: "golang"}, {Name: "devops"}, {Name: "docker"}}
+	posted := map[string]string{"docker": "https://www.reddit.com/r/docker/comments/old/"}
+	results := reddit.PostAll(context.Background(), subreddits, posted, "My video", "https://youtu.be/123")
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, but got %d: %v", len(results), results)
+	}
+	expected := []RedditResult{
+		{Subreddit: "kubernetes", URL: "https://www.reddit.com/r/kubernetes/comments/abc/link/"},
+		{Subreddit: "golang"},
+		{Subreddit: "devops", URL: "https://www.reddit.com/r/devops/comments/abc/link/"},
+	}
+	for i := range expected {
+		if results[i].Subreddit != expected[i].Subreddit || results[i].URL != expected[i].URL {
+			t.Errorf("Expected: %v\nGot: %v", expected[i], results[i])
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("Expected kubernetes and devops to succeed, but got %v and %v", results[0].Err, results[2].Err)
+	}
+	if !errors.Is(results[1].Err, ErrRedditRateLimited) {
+		t.Errorf("Expected golang to be rate limited, but got %v", results[1].Err)
+	}
+}
+
+func TestReddit_SubmitLinkFlair(t *testing.T) {
+	server := startFakeRedditServer(t, nil, map[string]string{"kubernetes": "flair-1"})
+	reddit := getTestReddit(server)
+	_, err := reddit.SubmitLink(context.Background(), SettingsRedditSubreddit{Name: "kubernetes"}, "My video", "https://youtu.be/123")
+	if !errors.Is(err, ErrRedditFlairRequired) {
+		t.Errorf("Expected a flair required error, but got %v", err)
+	}
+	url, err := reddit.SubmitLink(context.Background(), SettingsRedditSubreddit{Name: "kubernetes", FlairID: "flair-1"}, "My video", "https://youtu.be/123")
+	if err != nil {
+		t.Fatalf("Expected the post with the flair to succeed, but got %v", err)
+	}
+	if url != "https://www.reddit.com/r/kubernetes/comments/abc/link/" {
+		t.Errorf("Unexpected post URL %s", url)
+	}
+}
+
+func TestReddit_SubmitLinkAuthentication(t *testing.T) {
+	server := startFakeRedditServer(t, nil, nil)
+	reddit := getTestReddit(server)
+	reddit.settings.ClientSecret = "wrong"
+	_, err := reddit.SubmitLink(context.Background(), SettingsRedditSubreddit{Name: "kubernetes"}, "My video", "https://youtu.be/123")
+	if err == nil {
+		t.Errorf("Expected an authentication error")
+	}
+}
+
+func TestReddit_isRedditPosted(t *testing.T) {
+	subreddits := []SettingsRedditSubreddit{{Name: "kubernetes"}, {Name: "devops"}}
+	tests := []struct {
+		posted   map[string]string
+		expected bool
+	}{
+		{nil, false},
+		{map[string]string{"kubernetes": "https://reddit.com/1"}, false},
+		{map[string]string{"kubernetes": "https://reddit.com/1", "devops": "https://reddit.com/2"}, true},
+	}
+	for _, test := range tests {
+		if actual := isRedditPosted(test.posted, subreddits); actual != test.expected {
+			t.Errorf("Expected %t for %v, but got %t", test.expected, test.posted, actual)
+		}
+	}
+}
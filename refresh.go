@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atotto/clipboard"
+)
+
+// videosHideSuperseded is remembered for the rest of the session.
+var videosHideSuperseded = false
+
+// newRefreshedVideo starts a new version of an old video. Only what describes the subject (e.g., the project, the title and the description with its links) is copied.
+// Progress, dates, sponsorship and everything related to publishing the old video start from scratch.
+func newRefreshedVideo(old Video, name, category, path string) Video {
+	return Video{
+		Name:            name,
+		Category:        category,
+		Path:            path,
+		ProjectName:     old.ProjectName,
+		ProjectURL:      old.ProjectURL,
+		Title:           old.Title,
+		Description:     old.Description,
+		Tags:            old.Tags,
+		DescriptionTags: old.DescriptionTags,
+		RelatedVideos:   old.RelatedVideos,
+		Visibility:      old.Visibility,
+		MadeForKids:     old.MadeForKids,
+		CustomFields:    maps.Clone(old.CustomFields),
+		Gist:            getManuscriptPath(path),
+		Supersedes:      old.Path,
+	}
+}
+
+// createRefreshedVideo writes the new version of the video, with a copy of the old manuscript, and links both videos to each other.
+// It returns both videos as they were written.
+func createRefreshedVideo(old Video, name, category, dir string, write func(*Video, string) error) (Video, Video, error) {
+	if paths := getExistingVideoPaths(dir, name); len(paths) > 0 {
+		return Video{}, old, fmt.Errorf("%s already exists", strings.Join(paths, ", "))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Video{}, old, err
+	}
+	refreshed := newRefreshedVideo(old, name, category, filepath.Join(dir, name+".yaml"))
+	if data, err := os.ReadFile(old.Gist); err == nil {
+		if err := os.WriteFile(refreshed.Gist, data, 0644); err != nil {
+			return Video{}, old, err
+		}
+	} else if err := createManuscript(refreshed.Gist); err != nil {
+		return Video{}, old, err
+	}
+	if err := write(&refreshed, refreshed.Path); err != nil {
+		return Video{}, old, err
+	}
+	old.SupersededBy = refreshed.Path
+	if err := write(&old, old.Path); err != nil {
+		return refreshed, old, err
+	}
+	return refreshed, old, nil
+}
+
+// getSupersededComment is meant to be pinned to the old video once the new version is published.
+func getSupersededComment(refreshed Video) string {
+	return fmt.Sprintf("There is a newer version of this video: %s\n▶️ %s", refreshed.Title, getYouTubeURL(refreshed.VideoId))
+}
+
+func copySupersededComment(old, refreshed Video) {
+	clipboard.WriteAll(getSupersededComment(refreshed))
+	output.Result(fmt.Sprintf("The comment pointing viewers of %s to the new version has been copied to clipboard. Please post and pin it on %s.", old.Name, getYouTubeURL(old.VideoId)))
+}
+
+// filterSupersededVideos removes superseded videos if they should be hidden and returns how many were removed.
+func filterSupersededVideos(videos []Video, hide bool) ([]Video, int) {
+	if !hide {
+		return videos, 0
+	}
+	filtered := []Video{}
+	for _, video := range videos {
+		if len(video.SupersededBy) == 0 {
+			filtered = append(filtered, video)
+		}
+	}
+	return filtered, len(videos) - len(filtered)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getRefreshTestVideo(dir string) Video {
+	return Video{
+		Name:             "argo",
+		Category:         "k8s",
+		Path:             filepath.Join(dir, "k8s", "argo.yaml"),
+		Gist:             filepath.Join(dir, "k8s", "argo.md"),
+		ProjectName:      "Argo CD",
+		Title:            "Argo CD Tutorial",
+		Description:      "Links: https://argo-cd.readthedocs.io",
+		Tags:             "argo,gitops",
+		CustomFields:     map[string]string{"level": "beginner"},
+		Date:             "2020-01-07T16:00",
+		VideoId:          "old",
+		Code:             true,
+		Sponsorship:      Sponsorship{Amount: "1000"},
+		Publish:          Tasks{Completed: 10, Total: 15},
+		UploadedSnapshot: UploadedSnapshot{Title: "Argo CD Tutorial"},
+	}
+}
+
+func TestRefresh_newRefreshedVideo(t *testing.T) {
+	old := getRefreshTestVideo("manuscript")
+	actual := newRefreshedVideo(old, "argo-2", "gitops", "manuscript/gitops/argo-2.yaml")
+	expected := Video{
+		Name:         "argo-2",
+		Category:     "gitops",
+		Path:         "manuscript/gitops/argo-2.yaml",
+		Gist:         "manuscript/gitops/argo-2.md",
+		ProjectName:  "Argo CD",
+		Title:        "Argo CD Tutorial",
+		Description:  "Links: https://argo-cd.readthedocs.io",
+		Tags:         "argo,gitops",
+		CustomFields: map[string]string{"level": "beginner"},
+		Supersedes:   old.Path,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, actual)
+	}
+	actual.CustomFields["level"] = "advanced"
+	if old.CustomFields["level"] != "beginner" {
+		t.Errorf("Expected custom fields of the old video not to change")
+	}
+}
+
+func TestRefresh_createRefreshedVideo(t *testing.T) {
+	dir := t.TempDir()
+	old := getRefreshTestVideo(dir)
+	if err := os.MkdirAll(filepath.Dir(old.Path), 0755); err != nil {
+		t.Fatalf("Error occurred while creating the category: %v", err)
+	}
+	if err := os.WriteFile(old.Gist, []byte("## Intro\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing the manuscript: %v", err)
+	}
+	yaml := YAML{}
+	if err := yaml.writeVideo(&old, old.Path); err != nil {
+		t.Fatalf("Error occurred while writing the old video: %v", err)
+	}
+	refreshed, updated, err := createRefreshedVideo(old, "argo-2", "k8s", filepath.Dir(old.Path), yaml.writeVideo)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	storedOld, _ := readVideo(old.Path)
+	storedNew, _ := readVideo(refreshed.Path)
+	if storedOld.SupersededBy != refreshed.Path || updated.SupersededBy != refreshed.Path {
+		t.Errorf("Expected the old video to be superseded by %s, but got %q", refreshed.Path, storedOld.SupersededBy)
+	}
+	if storedNew.Supersedes != old.Path {
+		t.Errorf("Expected the new video to supersede %s, but got %q", old.Path, storedNew.Supersedes)
+	}
+	if manuscript, err := os.ReadFile(refreshed.Gist); err != nil || string(manuscript) != "## Intro\n" {
+		t.Errorf("Expected the manuscript to be copied, but got %q and %v", manuscript, err)
+	}
+	if _, _, err := createRefreshedVideo(old, "argo-2", "k8s", filepath.Dir(old.Path), yaml.writeVideo); err == nil {
+		t.Errorf("Expected an existing video not to be overwritten")
+	}
+}
+
+func TestRefresh_supersededMarkerAndFilter(t *testing.T) {
+	videos := []Video{{Name: "argo", SupersededBy: "manuscript/k8s/argo-2.yaml"}, {Name: "argo-2", Supersedes: "manuscript/k8s/argo.yaml"}}
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	if title := getVideoOptionTitle(videos[0], now); !strings.Contains(title, "argo (superseded)") {
+		t.Errorf("Expected the superseded marker, but got %q", title)
+	}
+	if title := getVideoOptionTitle(videos[1], now); strings.Contains(title, "superseded") {
+		t.Errorf("Expected no marker on the new version, but got %q", title)
+	}
+	visible, hidden := filterSupersededVideos(videos, true)
+	if len(visible) != 1 || visible[0].Name != "argo-2" || hidden != 1 {
+		t.Errorf("Expected the superseded video to be hidden, but got %v and %d", visible, hidden)
+	}
+	if visible, hidden := filterSupersededVideos(videos, false); len(visible) != 2 || hidden != 0 {
+		t.Errorf("Expected all videos to be shown, but got %v and %d", visible, hidden)
+	}
+}
+
+func TestRefresh_getSupersededComment(t *testing.T) {
+	expected := "There is a newer version of this video: Argo CD in 2030\n▶️ https://youtu.be/new"
+	if actual := getSupersededComment(Video{Title: "Argo CD in 2030", VideoId: "new"}); actual != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, actual)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const relatedSuggestionsCount = 5
+
+type RelatedSuggestion struct {
+	Video Video
+	Score float64
+	URL   string
+}
+
+// getRelatedTerms returns term frequencies of the title stems and tags of a video.
+// Manuscript terms are added when the video is in the full-text search index.
+func getRelatedTerms(video Video, documentTerms map[string]int) map[string]float64 {
+	terms := map[string]float64{}
+	for _, stem := range getSearchStems(video.Title) {
+		terms[stem]++
+	}
+	for _, tag := range splitTags(video.Tags) {
+		terms["tag:"+strings.ToLower(tag)] += 2
+	}
+	for stem, count := range documentTerms {
+		terms[stem] += math.Log(1 + float64(count))
+	}
+	return terms
+}
+
+// getSearchIndexTerms inverts the search index into terms per video path.
+func getSearchIndexTerms(index *SearchIndex) map[string]map[string]int {
+	output := map[string]map[string]int{}
+	if index == nil {
+		return output
+	}
+	for stem, postings := range index.Terms {
+		for path, count := range postings {
+			if output[path] == nil {
+				output[path] = map[string]int{}
+			}
+			output[path][stem] = count
+		}
+	}
+	return output
+}
+
+func isRelatedVideoListed(related string, other Video) bool {
+	return len(other.VideoId) > 0 && strings.Contains(related, other.VideoId)
+}
+
+// suggestRelatedVideos ranks published videos by the TF-IDF cosine similarity of their titles, tags, and (if indexed) manuscripts.
+// The video itself and videos already listed in its related videos are excluded.
+func suggestRelatedVideos(video Video, videos []Video, index *SearchIndex, count int) []RelatedSuggestion {
+	indexTerms := getSearchIndexTerms(index)
+	documents := map[string]map[string]float64{}
+	frequencies := map[string]int{}
+	for _, other := range append([]Video{video}, videos...) {
+		if _, ok := documents[other.Path]; ok {
+			continue
+		}
+		terms := getRelatedTerms(other, indexTerms[other.Path])
+		documents[other.Path] = terms
+		for term := range terms {
+			frequencies[term]++
+		}
+	}
+	weigh := func(terms map[string]float64) map[string]float64 {
+		weights := map[string]float64{}
+		for term, frequency := range terms {
+			weights[term] = frequency * math.Log(float64(len(documents))/float64(frequencies[term]))
+		}
+		return weights
+	}
+	target := weigh(documents[video.Path])
+	suggestions := []RelatedSuggestion{}
+	for _, other := range videos {
+		if other.Path == video.Path || len(other.VideoId) == 0 || isRelatedVideoListed(video.RelatedVideos, other) {
+			continue
+		}
+		score := getCosineSimilarity(target, weigh(documents[other.Path]))
+		if score <= 0 {
+			continue
+		}
+		suggestions = append(suggestions, RelatedSuggestion{Video: other, Score: score, URL: getYouTubeURL(other.VideoId)})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		if suggestions[i].Video.Title != suggestions[j].Video.Title {
+			return suggestions[i].Video.Title < suggestions[j].Video.Title
+		}
+		return suggestions[i].Video.Path < suggestions[j].Video.Path
+	})
+	if len(suggestions) > count {
+		suggestions = suggestions[:count]
+	}
+	return suggestions
+}
+
+// getCosineSimilarity sums terms in a sorted order so that floating point rounding does not depend on map iteration.
+func getCosineSimilarity(a, b map[string]float64) float64 {
+	dot, normA, normB := 0.0, 0.0, 0.0
+	for _, term := range getSortedTerms(a) {
+		dot += a[term] * b[term]
+		normA += a[term] * a[term]
+	}
+	for _, term := range getSortedTerms(b) {
+		normB += b[term] * b[term]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func getSortedTerms(terms map[string]float64) []string {
+	output := make([]string, 0, len(terms))
+	for term := range terms {
+		output = append(output, term)
+	}
+	sort.Strings(output)
+	return output
+}
+
+// formatRelatedVideo returns the "Title: URL" line used in the related videos field.
+func formatRelatedVideo(suggestion RelatedSuggestion) string {
+	return fmt.Sprintf("%s: %s", suggestion.Video.Title, suggestion.URL)
+}
+
+func appendRelatedVideos(related string, suggestions []RelatedSuggestion) string {
+	lines := []string{}
+	if trimmed := strings.TrimSpace(related); len(trimmed) > 0 {
+		lines = append(lines, trimmed)
+	}
+	for _, suggestion := range suggestions {
+		if !isRelatedVideoListed(related, suggestion.Video) {
+			lines = append(lines, formatRelatedVideo(suggestion))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
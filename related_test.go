@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func getRelatedTestCatalog() []Video {
+	return []Video{
+		{Path: "manuscript/gitops/new.yaml", Title: "Argo CD Rollouts Explained", Tags: "argo cd,gitops,kubernetes"},
+		{Path: "manuscript/gitops/01.yaml", Title: "Argo CD Tutorial", Tags: "argo cd,gitops,kubernetes", VideoId: "argo"},
+		{Path: "manuscript/gitops/02.yaml", Title: "Flux Tutorial", Tags: "flux,gitops,kubernetes", VideoId: "flux"},
+		{Path: "manuscript/gitops/03.yaml", Title: "Progressive Delivery with Argo Rollouts", Tags: "argo rollouts,kubernetes", VideoId: "rollouts"},
+		{Path: "manuscript/gitops/04.yaml", Title: "Argo CD Image Updater", Tags: "argo cd,gitops", VideoId: "updater"},
+		{Path: "manuscript/gitops/05.yaml", Title: "Argo CD ApplicationSets", Tags: "argo cd,gitops,kubernetes"},
+		{Path: "manuscript/ai/01.yaml", Title: "Local LLMs with Ollama", Tags: "ai,llm", VideoId: "ollama"},
+	}
+}
+
+func TestRelated_suggestRelatedVideos(t *testing.T) {
+	catalog := getRelatedTestCatalog()
+	video := catalog[0]
+	tests := []struct {
+		name     string
+		related  string
+		index    *SearchIndex
+		count    int
+		expected []string
+	}{
+		{"ranks by title and tags", "", nil, 5, []string{"argo", "updater", "rollouts", "flux"}},
+		{"limits the count", "", nil, 2, []string{"argo", "updater"}},
+		{"excludes listed videos", "Argo CD Tutorial: https://youtu.be/argo", nil, 5, []string{"updater", "rollouts", "flux"}},
+		{"uses manuscript terms", "", &SearchIndex{Terms: map[string]map[string]int{
+			"canary":  {"manuscript/gitops/new.yaml": 3, "manuscript/gitops/02.yaml": 5},
+			"analysi": {"manuscript/gitops/new.yaml": 2, "manuscript/gitops/02.yaml": 2},
+		}}, 2, []string{"argo", "flux"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			video.RelatedVideos = test.related
+			actual := []string{}
+			for _, suggestion := range suggestRelatedVideos(video, catalog, test.index, test.count) {
+				actual = append(actual, suggestion.Video.VideoId)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected: %v\nGot: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRelated_suggestRelatedVideosDeterministic(t *testing.T) {
+	catalog := getRelatedTestCatalog()
+	expected := suggestRelatedVideos(catalog[0], catalog, nil, 5)
+	for i := 0; i < 20; i++ {
+		if actual := suggestRelatedVideos(catalog[0], catalog, nil, 5); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Expected: %v\nGot: %v", expected, actual)
+		}
+	}
+}
+
+func TestRelated_appendRelatedVideos(t *testing.T) {
+	suggestions := []RelatedSuggestion{
+		{Video: Video{Title: "Argo CD Tutorial", VideoId: "argo"}, URL: "https://youtu.be/argo"},
+		{Video: Video{Title: "Flux Tutorial", VideoId: "flux"}, URL: "https://youtu.be/flux"},
+	}
+	tests := []struct {
+		related  string
+		expected string
+	}{
+		{"", "Argo CD Tutorial: https://youtu.be/argo\nFlux Tutorial: https://youtu.be/flux"},
+		{"Kustomize: https://youtu.be/kustomize\n", "Kustomize: https://youtu.be/kustomize\nArgo CD Tutorial: https://youtu.be/argo\nFlux Tutorial: https://youtu.be/flux"},
+		{"Flux Tutorial: https://youtu.be/flux", "Flux Tutorial: https://youtu.be/flux\nArgo CD Tutorial: https://youtu.be/argo"},
+	}
+	for _, test := range tests {
+		if actual := appendRelatedVideos(test.related, suggestions); actual != test.expected {
+			t.Errorf("Expected: %q\nGot: %q", test.expected, actual)
+		}
+	}
+}
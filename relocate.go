@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrAmbiguousVideoLocation is returned when a missing video exists in more than one category. The tool never guesses which one is right.
+type ErrAmbiguousVideoLocation struct {
+	Path    string
+	Matches []string
+}
+
+func (e *ErrAmbiguousVideoLocation) Error() string {
+	return fmt.Sprintf("%s was not found and there are multiple candidates: %s", e.Path, strings.Join(e.Matches, ", "))
+}
+
+// findVideoLocations looks for a file with the same name as the missing path in each category directory of the root.
+func findVideoLocations(root, path string, ignore *IgnoreMatcher) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	matches := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || ignore.Match(entry.Name(), true) {
+			continue
+		}
+		candidate := filepath.Join(root, entry.Name(), filepath.Base(path))
+		if filepath.Clean(candidate) == filepath.Clean(path) {
+			continue
+		}
+		if _, err := os.Stat(candidate); err == nil {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches, nil
+}
+
+// findVideoLocation returns the only location of a missing video, an empty string if there is none, or ErrAmbiguousVideoLocation.
+func findVideoLocation(root, path string, ignore *IgnoreMatcher) (string, error) {
+	matches, err := findVideoLocations(root, path, ignore)
+	if err != nil {
+		return "", err
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	}
+	return "", &ErrAmbiguousVideoLocation{Path: path, Matches: matches}
+}
+
+// relocateVideo points the video and its index entry to the new location. The manuscript (Gist) is moved along if it was in the old directory.
+func relocateVideo(vi VideoIndex, oldPath, newPath string, index []VideoIndex, write func(*Video, string) error) (Video, []VideoIndex, error) {
+	video, err := readVideo(newPath)
+	if err != nil {
+		return video, index, err
+	}
+	category := filepath.Base(filepath.Dir(newPath))
+	oldDir := filepath.Dir(oldPath)
+	if len(video.Gist) > 0 && filepath.Clean(filepath.Dir(video.Gist)) == filepath.Clean(oldDir) {
+		video.Gist = filepath.Join(filepath.Dir(newPath), filepath.Base(video.Gist))
+	}
+	video.Name = vi.Name
+	video.Category = category
+	video.Path = newPath
+	if err := write(&video, newPath); err != nil {
+		return video, index, err
+	}
+	updated := make([]VideoIndex, len(index))
+	copy(updated, index)
+	for i := range updated {
+		if updated[i] == vi {
+			updated[i].Category = category
+		}
+	}
+	return video, updated, nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+func writeRelocateTestVideo(t *testing.T, path string, video Video) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Error occurred while creating %s: %v", filepath.Dir(path), err)
+	}
+	data, _ := yaml.Marshal(&video)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+}
+
+func TestRelocate_renamedCategory(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "manuscript")
+	oldPath := filepath.Join(root, "k8s", "argo.yaml")
+	writeRelocateTestVideo(t, oldPath, Video{Title: "Argo CD", Category: "k8s", Path: oldPath, Gist: filepath.Join(root, "k8s", "argo.md")})
+	writeRelocateTestVideo(t, filepath.Join(root, ".obsidian", "argo.yaml"), Video{})
+	if err := os.Rename(filepath.Join(root, "k8s"), filepath.Join(root, "kubernetes")); err != nil {
+		t.Fatalf("Error occurred while renaming the category: %v", err)
+	}
+	newPath, err := findVideoLocation(root, oldPath, NewIgnoreMatcher(nil))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expectedPath := filepath.Join(root, "kubernetes", "argo.yaml")
+	if newPath != expectedPath {
+		t.Fatalf("Expected: %s\nGot: %s", expectedPath, newPath)
+	}
+	vi := VideoIndex{Name: "argo", Category: "k8s"}
+	index := []VideoIndex{{Name: "flux", Category: "k8s"}, vi}
+	var written Video
+	write := func(video *Video, path string) error {
+		written = *video
+		return nil
+	}
+	video, updated, err := relocateVideo(vi, oldPath, newPath, index, write)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expectedGist := filepath.Join(root, "kubernetes", "argo.md")
+	if video.Category != "kubernetes" || video.Path != expectedPath || video.Gist != expectedGist || video.Title != "Argo CD" || !reflect.DeepEqual(written, video) {
+		t.Errorf("Unexpected video %+v", video)
+	}
+	expectedIndex := []VideoIndex{{Name: "flux", Category: "k8s"}, {Name: "argo", Category: "kubernetes"}}
+	if !reflect.DeepEqual(updated, expectedIndex) {
+		t.Errorf("Expected: %v\nGot: %v", expectedIndex, updated)
+	}
+	if index[1].Category != "k8s" {
+		t.Errorf("Expected the original index not to change")
+	}
+}
+
+func TestRelocate_notFound(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "manuscript")
+	writeRelocateTestVideo(t, filepath.Join(root, "k8s", "flux.yaml"), Video{})
+	newPath, err := findVideoLocation(root, filepath.Join(root, "old", "argo.yaml"), NewIgnoreMatcher(nil))
+	if err != nil || len(newPath) > 0 {
+		t.Errorf("Expected nothing to be found, but got %q and %v", newPath, err)
+	}
+}
+
+func TestRelocate_ambiguous(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "manuscript")
+	writeRelocateTestVideo(t, filepath.Join(root, "ai", "argo.yaml"), Video{})
+	writeRelocateTestVideo(t, filepath.Join(root, "kubernetes", "argo.yaml"), Video{})
+	newPath, err := findVideoLocation(root, filepath.Join(root, "k8s", "argo.yaml"), NewIgnoreMatcher(nil))
+	var ambiguous *ErrAmbiguousVideoLocation
+	if !errors.As(err, &ambiguous) || len(newPath) > 0 {
+		t.Fatalf("Expected the location to be ambiguous, but got %q and %v", newPath, err)
+	}
+	expected := []string{filepath.Join(root, "ai", "argo.yaml"), filepath.Join(root, "kubernetes", "argo.yaml")}
+	if !reflect.DeepEqual(ambiguous.Matches, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, ambiguous.Matches)
+	}
+}
+
+func TestRelocate_recordAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "argo.yaml")
+	recordAudit(path, AuditEntry{Action: auditActionRelocate, From: "manuscript/k8s/argo.yaml", To: path})
+	entries, err := readAuditLog(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading the audit log: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != auditActionRelocate || entries[0].From != "manuscript/k8s/argo.yaml" || entries[0].To != path {
+		t.Fatalf("Unexpected entries %+v", entries)
+	}
+	if actual := getAuditText(entries); !strings.HasSuffix(actual, "cli relocate from manuscript/k8s/argo.yaml to "+path) {
+		t.Errorf("Expected: the paths of the relocation\nGot: %s", actual)
+	}
+}
+
+// The caller's index is updated in place so that writing it later does not revert the relocation.
+func TestRelocate_getVideoUpdatesIndex(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	originalOptions := formProgramOptions
+	defer func() { formProgramOptions = originalOptions }()
+	formProgramOptions = func() []tea.ProgramOption {
+		return getTestFormOptions("\r")
+	}
+	writeRelocateTestVideo(t, filepath.Join("manuscript", "kubernetes", "argo.yaml"), Video{Title: "Argo CD"})
+	vi := []VideoIndex{{Name: "flux", Category: "k8s"}, {Name: "argo", Category: "k8s"}}
+	c := Choices{}
+	video := c.getVideo(vi, 1)
+	if video.Title != "Argo CD" || video.Index != 1 {
+		t.Errorf("Expected: the relocated video\nGot: %+v", video)
+	}
+	expected := []VideoIndex{{Name: "flux", Category: "k8s"}, {Name: "argo", Category: "kubernetes"}}
+	if !reflect.DeepEqual(vi, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, vi)
+	}
+	index := YAML{IndexPath: "index.yaml"}
+	if actual := index.GetIndex(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
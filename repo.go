@@ -60,13 +60,12 @@ func (r *Repo) GetAnimations(filePath string) (animations, sections []string, er
 
 // TODO: Remove
 func (r *Repo) getAnimationsFromScript(filePath string) (animations, sections []string, err error) {
-	file, err := os.Open(filePath)
+	content, _, err := readManuscript(filePath)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(strings.NewReader(content))
 	for scanner.Scan() {
 		line := scanner.Text()
 		line = strings.TrimSpace(line)
@@ -100,53 +99,30 @@ func (r *Repo) getAnimationsFromScript(filePath string) (animations, sections []
 }
 
 func (r *Repo) getAnimationsFromMarkdown(filePath string) (animations, sections []string, err error) {
-	file, err := os.Open(filePath)
+	cues, err := r.GetAnimationCues(filePath)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer file.Close()
+	animations, sections = getAnimationLines(cues)
+	return animations, sections, nil
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSpace(line)
-		line = strings.ReplaceAll(line, " ", " ")
-		if strings.HasPrefix(line, "TODO:") {
-			line = strings.ReplaceAll(line, "TODO:", "")
-			line = strings.TrimSpace(line)
-			animations = append(animations, line)
-		} else if strings.HasPrefix(line, "## ") {
-			containsAny := false
-			for _, value := range []string{"## Intro", "## Setup", "## Destroy"} {
-				if line == value {
-					containsAny = true
-					break
-				}
-			}
-			if !containsAny {
-				line = strings.Replace(line, "## ", "", 1)
-				line = strings.TrimSpace(line)
-				line = fmt.Sprintf("Section: %s", line)
-				animations = append(animations, line)
-				sections = append(sections, line)
-			}
-		}
-	}
-	if err := scanner.Err(); err != nil {
-		return nil, nil, err
+// GetAnimationCues parses a markdown manuscript using the cue prefixes and header levels from settings.
+func (r *Repo) GetAnimationCues(filePath string) ([]AnimationCue, error) {
+	content, _, err := readManuscript(filePath)
+	if err != nil {
+		return nil, err
 	}
-
-	return animations, sections, nil
+	return parseAnimationCues(content, getAnimationOptions(settings.Animations)), nil
 }
 
 func (r *Repo) CleanupGist(filePath string) error {
-	file, err := os.Open(filePath)
+	content, encoding, err := readManuscript(filePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(strings.NewReader(content))
 	var lines, outputLines []string
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -190,6 +166,6 @@ func (r *Repo) CleanupGist(filePath string) error {
 		}
 	}
 
-	err = os.WriteFile(filePath, []byte(strings.Join(outputLines, "\n")), 0644)
+	err = writeManuscript(filePath, strings.Join(outputLines, "\n"), encoding)
 	return err
 }
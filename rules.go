@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const ruleTriggerCreated = "created"
+const ruleTriggerPhase = "phase"
+const ruleTriggerField = "field"
+
+const ruleActionEmail = "email"
+const ruleActionSetField = "setField"
+const ruleActionAddNote = "addNote"
+const ruleActionWebhook = "webhook"
+
+const ruleWebhookTimeout = 30 * time.Second
+
+// Rule runs its actions when a video is written and the trigger matches the change.
+type Rule struct {
+	Name    string
+	Trigger RuleTrigger
+	Actions []RuleAction
+}
+
+// RuleTrigger matches a newly created video (created), a video entering a phase (phase), or a field changing to a value (field).
+type RuleTrigger struct {
+	Type  string
+	Phase string
+	Field string
+	Value string
+}
+
+// RuleAction fields used depend on the type. Subject, Body, Note, and Value are Go templates executed with the video.
+type RuleAction struct {
+	Type    string
+	To      []string
+	Subject string
+	Body    string
+	Field   string
+	Value   string
+	Note    string
+	URL     string
+}
+
+type ruleActionType struct {
+	validate func(action RuleAction) error
+	// apply changes the video before it is written.
+	apply func(video *Video, action RuleAction) error
+	// notify has side effects outside of the video and runs after the video is written.
+	notify func(runner *RuleRunner, video Video, action RuleAction) error
+}
+
+var rulePhases = map[string]int{
+	"published":        videosPhasePublished,
+	"publishPending":   videosPhasePublishPending,
+	"editRequested":    videosPhaseEditRequested,
+	"materialDone":     videosPhaseMaterialDone,
+	"started":          videosPhaseStarted,
+	"delayed":          videosPhaseDelayed,
+	"sponsoredBlocked": videosPhaseSponsoredBlocked,
+	"ideas":            videosPhaseIdeas,
+}
+
+var ruleActionTypes = map[string]ruleActionType{
+	ruleActionEmail: {
+		validate: func(action RuleAction) error {
+			if len(action.To) == 0 || len(action.Subject) == 0 {
+				return fmt.Errorf("email actions require to and subject")
+			}
+			return validateRuleTemplates(action.Subject, action.Body)
+		},
+		notify: func(runner *RuleRunner, video Video, action RuleAction) error {
+			subject, err := executeRuleTemplate(action.Subject, video)
+			if err != nil {
+				return err
+			}
+			body, err := executeRuleTemplate(action.Body, video)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := newEmailContext()
+			defer cancel()
+			return runner.Email.Send(ctx, runner.From, action.To, subject, body, "")
+		},
+	},
+	ruleActionSetField: {
+		validate: func(action RuleAction) error {
+			if _, err := getVideoFieldValue(Video{}, action.Field); err != nil {
+				return err
+			}
+			return validateRuleTemplates(action.Value)
+		},
+		apply: func(video *Video, action RuleAction) error {
+			value, err := executeRuleTemplate(action.Value, *video)
+			if err != nil {
+				return err
+			}
+			return setVideoFieldValue(video, action.Field, value)
+		},
+	},
+	ruleActionAddNote: {
+		validate: func(action RuleAction) error {
+			if len(action.Note) == 0 {
+				return fmt.Errorf("addNote actions require a note")
+			}
+			return validateRuleTemplates(action.Note)
+		},
+		apply: func(video *Video, action RuleAction) error {
+			note, err := executeRuleTemplate(action.Note, *video)
+			if err != nil {
+				return err
+			}
+			video.Notes = append(video.Notes, note)
+			return nil
+		},
+	},
+	ruleActionWebhook: {
+		validate: func(action RuleAction) error {
+			webhookURL, err := url.Parse(action.URL)
+			if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") {
+				return fmt.Errorf("webhook URL %q must be an http or https URL", action.URL)
+			}
+			return nil
+		},
+		notify: func(runner *RuleRunner, video Video, action RuleAction) error {
+			data, err := json.Marshal(withoutSecrets(video))
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), ruleWebhookTimeout)
+			defer cancel()
+			request, err := http.NewRequestWithContext(ctx, http.MethodPost, action.URL, bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			request.Header.Set("Content-Type", "application/json")
+			response, err := runner.Client.Do(request)
+			if err != nil {
+				return err
+			}
+			defer response.Body.Close()
+			if response.StatusCode >= 300 {
+				return fmt.Errorf("webhook responded with %s", response.Status)
+			}
+			return nil
+		},
+	},
+}
+
+// validateRules rejects unknown triggers, phases, actions, and fields so that mistakes are found at startup rather than when a rule should fire.
+func validateRules(rules []Rule) error {
+	errs := []error{}
+	for i, rule := range rules {
+		name := rule.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		switch rule.Trigger.Type {
+		case ruleTriggerCreated:
+		case ruleTriggerPhase:
+			if _, ok := rulePhases[rule.Trigger.Phase]; !ok {
+				errs = append(errs, fmt.Errorf("rule %s: unknown phase %q", name, rule.Trigger.Phase))
+			}
+		case ruleTriggerField:
+			if _, err := getVideoFieldValue(Video{}, rule.Trigger.Field); err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %w", name, err))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("rule %s: unknown trigger %q", name, rule.Trigger.Type))
+		}
+		if len(rule.Actions) == 0 {
+			errs = append(errs, fmt.Errorf("rule %s: at least one action is required", name))
+		}
+		for _, action := range rule.Actions {
+			actionType, ok := ruleActionTypes[action.Type]
+			if !ok {
+				errs = append(errs, fmt.Errorf("rule %s: unknown action %q", name, action.Type))
+				continue
+			}
+			if err := actionType.validate(action); err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// getMatchingRules returns the rules triggered by the change from before to after. Before is nil when the video is created.
+// It does not run anything so it can be used to find out which rules would fire for a hypothetical change.
+func getMatchingRules(rules []Rule, before *Video, after Video) []Rule {
+	matching := []Rule{}
+	c := Choices{}
+	for _, rule := range rules {
+		matches := false
+		switch rule.Trigger.Type {
+		case ruleTriggerCreated:
+			matches = before == nil
+		case ruleTriggerPhase:
+			phase := c.getPhase(after)
+			matches = phase == rulePhases[rule.Trigger.Phase] && (before == nil || c.getPhase(*before) != phase)
+		case ruleTriggerField:
+			value, _ := getVideoFieldValue(after, rule.Trigger.Field)
+			previous := ""
+			if before != nil {
+				previous, _ = getVideoFieldValue(*before, rule.Trigger.Field)
+			}
+			matches = value == rule.Trigger.Value && previous != value
+		}
+		if matches {
+			matching = append(matching, rule)
+		}
+	}
+	return matching
+}
+
+// RuleRunner executes the actions of matching rules. A failing action is reported and does not prevent the others from running.
+type RuleRunner struct {
+	Email  *Email
+	From   string
+	Client *http.Client
+}
+
+func NewRuleRunner() *RuleRunner {
+	return &RuleRunner{
+		Email:  NewEmail(settings.Email.Password),
+		From:   settings.Email.From,
+		Client: &http.Client{Timeout: ruleWebhookTimeout},
+	}
+}
+
+func (r *RuleRunner) Apply(rules []Rule, video *Video) []error {
+	errs := []error{}
+	for _, rule := range rules {
+		for _, action := range rule.Actions {
+			if apply := ruleActionTypes[action.Type].apply; apply != nil {
+				if err := apply(video, action); err != nil {
+					errs = append(errs, fmt.Errorf("rule %s: %s failed: %w", rule.Name, action.Type, err))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func (r *RuleRunner) Notify(rules []Rule, video Video) []error {
+	errs := []error{}
+	for _, rule := range rules {
+		for _, action := range rule.Actions {
+			if notify := ruleActionTypes[action.Type].notify; notify != nil {
+				if err := notify(r, video, action); err != nil {
+					errs = append(errs, fmt.Errorf("rule %s: %s failed: %w", rule.Name, action.Type, err))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func printRuleErrors(errs []error) {
+	for _, err := range errs {
+		output.Error(err.Error())
+	}
+}
+
+// getPreviousVideo returns nil if the video was not written before. A file that exists but cannot be read is an error so that it is not mistaken for a new video.
+func getPreviousVideo(path string) (*Video, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	video, err := readVideo(path)
+	if err != nil {
+		return nil, err
+	}
+	return &video, nil
+}
+
+func validateRuleTemplates(texts ...string) error {
+	for _, text := range texts {
+		if _, err := template.New("rule").Parse(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func executeRuleTemplate(text string, video Video) (string, error) {
+	tmpl, err := template.New("rule").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, video); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// getVideoField finds the Video field by its name ignoring the case so that names match those in the YAML.
+func getVideoField(video reflect.Value, name string) (reflect.Value, error) {
+	field := video.FieldByNameFunc(func(fieldName string) bool {
+		return strings.EqualFold(fieldName, name)
+	})
+	if !field.IsValid() {
+		return field, fmt.Errorf("unknown field %q", name)
+	}
+	switch field.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int:
+		return field, nil
+	}
+	return field, fmt.Errorf("field %q is not a string, a boolean, or a number", name)
+}
+
+// getVideoFieldValue returns the value of a Video field or a custom field declared in settings.
+func getVideoFieldValue(video Video, name string) (string, error) {
+	if _, ok := getCustomField(settings.CustomFields, name); ok {
+		return video.CustomFields[name], nil
+	}
+	field, err := getVideoField(reflect.ValueOf(video), name)
+	if err != nil {
+		return "", err
+	}
+	switch field.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	case reflect.Int:
+		return strconv.Itoa(int(field.Int())), nil
+	}
+	return field.String(), nil
+}
+
+func setVideoFieldValue(video *Video, name, value string) error {
+	if customField, ok := getCustomField(settings.CustomFields, name); ok {
+		if err := validateCustomFieldValue(customField, value); err != nil {
+			return err
+		}
+		if video.CustomFields == nil {
+			video.CustomFields = map[string]string{}
+		}
+		video.CustomFields[name] = value
+		return nil
+	}
+	field, err := getVideoField(reflect.ValueOf(video).Elem(), name)
+	if err != nil {
+		return err
+	}
+	switch field.Kind() {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("field %q must be true or false, not %q", name, value)
+		}
+		field.SetBool(parsed)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("field %q must be a number, not %q", name, value)
+		}
+		field.SetInt(int64(parsed))
+	default:
+		field.SetString(value)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRules_validateRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []Rule
+		expected []string
+	}{
+		{
+			name: "valid",
+			rules: []Rule{
+				{Name: "editor", Trigger: RuleTrigger{Type: ruleTriggerPhase, Phase: "materialDone"}, Actions: []RuleAction{{Type: ruleActionEmail, To: []string{"editor@example.com"}, Subject: "{{.Name}} is ready"}}},
+				{Name: "delayed", Trigger: RuleTrigger{Type: ruleTriggerField, Field: "delayed", Value: "true"}, Actions: []RuleAction{{Type: ruleActionSetField, Field: "date", Value: ""}}},
+			},
+		},
+		{
+			name:     "unknown trigger",
+			rules:    []Rule{{Name: "broken", Trigger: RuleTrigger{Type: "deleted"}, Actions: []RuleAction{{Type: ruleActionAddNote, Note: "Gone"}}}},
+			expected: []string{`rule broken: unknown trigger "deleted"`},
+		},
+		{
+			name:     "unknown phase",
+			rules:    []Rule{{Name: "broken", Trigger: RuleTrigger{Type: ruleTriggerPhase, Phase: "done"}, Actions: []RuleAction{{Type: ruleActionAddNote, Note: "Done"}}}},
+			expected: []string{`rule broken: unknown phase "done"`},
+		},
+		{
+			name:     "unknown field and action",
+			rules:    []Rule{{Name: "broken", Trigger: RuleTrigger{Type: ruleTriggerField, Field: "color", Value: "red"}, Actions: []RuleAction{{Type: "tweet"}, {Type: ruleActionSetField, Field: "costs"}}}},
+			expected: []string{`rule broken: unknown field "color"`, `rule broken: unknown action "tweet"`, `rule broken: field "costs" is not a string, a boolean, or a number`},
+		},
+		{
+			name:     "invalid action settings",
+			rules:    []Rule{{Trigger: RuleTrigger{Type: ruleTriggerCreated}, Actions: []RuleAction{{Type: ruleActionEmail, Subject: "Hi"}, {Type: ruleActionWebhook, URL: "ftp://example.com"}, {Type: ruleActionAddNote, Note: "{{.Name"}}}},
+			expected: []string{"rule #1: email actions require to and subject", `rule #1: webhook URL "ftp://example.com" must be an http or https URL`, "rule #1: template"},
+		},
+	}
+	for _, test := range tests {
+		err := validateRules(test.rules)
+		if len(test.expected) == 0 {
+			if err != nil {
+				t.Errorf("%s: expected no error, but got %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+			continue
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("%s: expected the error to contain %q, but got %v", test.name, expected, err)
+			}
+		}
+	}
+}
+
+func TestRules_getMatchingRules(t *testing.T) {
+	rules := []Rule{
+		{Name: "created", Trigger: RuleTrigger{Type: ruleTriggerCreated}},
+		{Name: "materialDone", Trigger: RuleTrigger{Type: ruleTriggerPhase, Phase: "materialDone"}},
+		{Name: "delayed", Trigger: RuleTrigger{Type: ruleTriggerField, Field: "Delayed", Value: "true"}},
+	}
+	started := Video{Name: "my-video", Date: "2030-01-21T16:00"}
+	materialDone := started
+	materialDone.Code, materialDone.Screen, materialDone.Head, materialDone.Diagrams = true, true, true, true
+	delayed := materialDone
+	delayed.Delayed = true
+	tests := []struct {
+		name     string
+		before   *Video
+		after    Video
+		expected []string
+	}{
+		{"created", nil, started, []string{"created"}},
+		{"phase transition", &started, materialDone, []string{"materialDone"}},
+		{"same phase", &materialDone, materialDone, []string{}},
+		{"field changed", &materialDone, delayed, []string{"delayed"}},
+		{"field unchanged", &delayed, delayed, []string{}},
+	}
+	for _, test := range tests {
+		actual := []string{}
+		for _, rule := range getMatchingRules(rules, test.before, test.after) {
+			actual = append(actual, rule.Name)
+		}
+		if strings.Join(actual, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s\nExpected: %v\nGot: %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestRules_RuleRunner(t *testing.T) {
+	requests := 0
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	host, port := startFakeSMTPServer(t, nil, false)
+	runner := &RuleRunner{Email: getTestEmail(t, host, port), From: "me@example.com", Client: server.Client()}
+	rules := []Rule{{
+		Name:    "delayed",
+		Trigger: RuleTrigger{Type: ruleTriggerField, Field: "delayed", Value: "true"},
+		Actions: []RuleAction{
+			{Type: ruleActionWebhook, URL: server.URL},
+			{Type: ruleActionSetField, Field: "date", Value: ""},
+			{Type: ruleActionSetField, Field: "code", Value: "maybe"},
+			{Type: ruleActionAddNote, Note: "{{.Name}} was delayed"},
+			{Type: ruleActionEmail, To: []string{"editor@example.com"}, Subject: "{{.Name}} was delayed"},
+		},
+	}}
+	video := Video{Name: "my-video", Date: "2030-01-21T16:00", Delayed: true, Secrets: map[string]string{"license": secretPrefix + "abc"}}
+	applyErrs := runner.Apply(rules, &video)
+	if len(applyErrs) != 1 || !strings.Contains(applyErrs[0].Error(), "setField failed") {
+		t.Errorf("Expected only the invalid setField action to fail, but got %v", applyErrs)
+	}
+	if len(video.Date) > 0 || len(video.Notes) != 1 || video.Notes[0] != "my-video was delayed" {
+		t.Errorf("Expected the date to be cleared and the note to be added, but got %q and %v", video.Date, video.Notes)
+	}
+	notifyErrs := runner.Notify(rules, video)
+	if len(notifyErrs) != 1 || !strings.Contains(notifyErrs[0].Error(), "webhook failed") {
+		t.Errorf("Expected only the webhook to fail, but got %v", notifyErrs)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the webhook to be called once, but it was called %d times", requests)
+	}
+	if !strings.Contains(body, "my-video") || strings.Contains(body, "license") || strings.Contains(body, secretPrefix) {
+		t.Errorf("Expected the webhook payload without secrets, but got %s", body)
+	}
+	entries := readEmailLog(t, runner.Email.logPath)
+	if len(entries) != 1 || entries[0].Subject != "my-video was delayed" || entries[0].Error != "" {
+		t.Errorf("Expected the email to be sent despite the webhook failure, but got %v", entries)
+	}
+}
+
+// The caller keeps editing the video it saved, so what the rules changed has to be in it too.
+func TestRules_writeVideoUpdatesCaller(t *testing.T) {
+	settingsOrig := settings
+	defer func() { settings = settingsOrig }()
+	settings.Rules = []Rule{{
+		Name:    "delayed",
+		Trigger: RuleTrigger{Type: ruleTriggerField, Field: "delayed", Value: "true"},
+		Actions: []RuleAction{{Type: ruleActionAddNote, Note: "{{.Name}} was delayed"}},
+	}}
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	video := Video{Name: "my-video", Path: path}
+	yaml := YAML{}
+	yaml.WriteVideo(&video, path)
+	video.Delayed = true
+	yaml.WriteVideo(&video, path)
+	if len(video.Notes) != 1 {
+		t.Fatalf("Expected: the note added by the rule\nGot: %v", video.Notes)
+	}
+	video.Title = "My video"
+	yaml.WriteVideo(&video, path)
+	if saved, _ := readVideo(path); len(saved.Notes) != 1 {
+		t.Errorf("Expected: the next save to keep the note\nGot: %v", saved.Notes)
+	}
+}
+
+func TestRules_getPreviousVideo(t *testing.T) {
+	dir := t.TempDir()
+	if previous, err := getPreviousVideo(filepath.Join(dir, "missing.yaml")); previous != nil || err != nil {
+		t.Errorf("Expected: no video for a missing file\nGot: %v %v", previous, err)
+	}
+	broken := filepath.Join(dir, "broken.yaml")
+	if err := os.WriteFile(broken, []byte("name: [unclosed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if previous, err := getPreviousVideo(broken); previous != nil || err == nil {
+		t.Errorf("Expected: an error instead of treating the video as new\nGot: %v %v", previous, err)
+	}
+	unreadable := filepath.Join(dir, "unreadable.yaml")
+	if err := os.Mkdir(unreadable, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if previous, err := getPreviousVideo(unreadable); previous != nil || err == nil {
+		t.Errorf("Expected: an error for a file that cannot be read\nGot: %v %v", previous, err)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Schedule struct {
+	Weekdays []time.Weekday
+	Hour     int
+	Minute   int
+	MinGap   time.Duration
+}
+
+type ScheduledVideo struct {
+	Name      string
+	Date      time.Time
+	Immovable bool
+}
+
+func NewSchedule(weekdays []string, at string, minGapDays int) (Schedule, error) {
+	schedule := Schedule{MinGap: time.Duration(minGapDays) * 24 * time.Hour}
+	for _, weekday := range weekdays {
+		found := false
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(day.String(), strings.TrimSpace(weekday)) {
+				schedule.Weekdays = append(schedule.Weekdays, day)
+				found = true
+			}
+		}
+		if !found {
+			return Schedule{}, fmt.Errorf("%s is not a valid weekday", weekday)
+		}
+	}
+	if len(at) > 0 {
+		slotTime, err := time.Parse("15:04", at)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("schedule time %q must be in the 15:04 format", at)
+		}
+		schedule.Hour, schedule.Minute = slotTime.Hour(), slotTime.Minute()
+	}
+	return schedule, nil
+}
+
+// GetSlots expands the cadence into the next count slots after the specified time.
+func (s Schedule) GetSlots(after time.Time, count int) []time.Time {
+	slots := []time.Time{}
+	if len(s.Weekdays) == 0 {
+		return slots
+	}
+	day := time.Date(after.Year(), after.Month(), after.Day(), s.Hour, s.Minute, 0, 0, after.Location())
+	for len(slots) < count {
+		for _, weekday := range s.Weekdays {
+			if day.Weekday() == weekday && day.After(after) {
+				slots = append(slots, day)
+				break
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return slots
+}
+
+// GetConflicts returns scheduled videos that are closer to the proposed date than the minimum gap or at the same time.
+func (s Schedule) GetConflicts(proposed time.Time, scheduled []ScheduledVideo) []ScheduledVideo {
+	conflicts := []ScheduledVideo{}
+	for _, video := range scheduled {
+		gap := proposed.Sub(video.Date)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap == 0 || gap < s.MinGap {
+			conflicts = append(conflicts, video)
+		}
+	}
+	return conflicts
+}
+
+// GetSuggestions returns the next count free slots after the specified time.
+// Slots that conflict with already scheduled videos, sponsored ones included, are skipped.
+func (s Schedule) GetSuggestions(after time.Time, scheduled []ScheduledVideo, count int) []time.Time {
+	suggestions := []time.Time{}
+	if len(s.Weekdays) == 0 {
+		return suggestions
+	}
+	for len(suggestions) < count {
+		slots := s.GetSlots(after, count)
+		for _, slot := range slots {
+			if len(s.GetConflicts(slot, scheduled)) == 0 && len(suggestions) < count {
+				suggestions = append(suggestions, slot)
+			}
+		}
+		after = slots[len(slots)-1]
+	}
+	return suggestions
+}
+
+func getScheduleConflictMessage(conflicts []ScheduledVideo) string {
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Date.Before(conflicts[j].Date)
+	})
+	lines := []string{"The publish date is too close to:"}
+	for _, conflict := range conflicts {
+		line := fmt.Sprintf("- %s (%s)", conflict.Name, conflict.Date.Format(dateFormat))
+		if conflict.Immovable {
+			line = fmt.Sprintf("%s, sponsored and cannot be moved", line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getScheduleHorizons ignores horizons that cannot be parsed since the settings are validated at startup.
+func getScheduleHorizons(s SettingsSchedule) (DateHorizon, DateHorizon) {
+	imminent, _ := parseDateHorizon(s.Imminent)
+	farFuture, _ := parseDateHorizon(s.FarFuture)
+	return imminent, farFuture
+}
+
+// renderScheduleDate styles the date when it is imminent or far in the future.
+func renderScheduleDate(date, text string, now time.Time, s SettingsSchedule) string {
+	imminent, farFuture := getScheduleHorizons(s)
+	switch getDateBand(date, now, imminent, farFuture) {
+	case dateBandImminent:
+		return imminentStyle.Render(text)
+	case dateBandFarFuture:
+		return farFutureStyle.Render(text)
+	}
+	return text
+}
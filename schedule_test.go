@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func getTestSchedule(t *testing.T, minGapDays int) Schedule {
+	schedule, err := NewSchedule([]string{"Tuesday", "thursday"}, "16:00", minGapDays)
+	if err != nil {
+		t.Fatalf("Error occurred while creating the schedule: %v", err)
+	}
+	return schedule
+}
+
+func getTestDates(t *testing.T, values ...string) []time.Time {
+	dates := []time.Time{}
+	for _, value := range values {
+		date, err := parseDate(value)
+		if err != nil {
+			t.Fatalf("Error occurred while parsing %s: %v", value, err)
+		}
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+func TestSchedule_NewSchedule(t *testing.T) {
+	if _, err := NewSchedule([]string{"Funday"}, "16:00", 0); err == nil {
+		t.Errorf("Expected an error for an invalid weekday")
+	}
+	if _, err := NewSchedule([]string{"Monday"}, "4pm", 0); err == nil {
+		t.Errorf("Expected an error for an invalid time")
+	}
+}
+
+func TestSchedule_GetSlots(t *testing.T) {
+	schedule := getTestSchedule(t, 0)
+	after := getTestDates(t, "2030-01-29T17:00")[0]
+	actual := schedule.GetSlots(after, 4)
+	expected := getTestDates(t, "2030-01-31T16:00", "2030-02-05T16:00", "2030-02-07T16:00", "2030-02-12T16:00")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestSchedule_GetConflicts(t *testing.T) {
+	schedule := getTestSchedule(t, 0)
+	dates := getTestDates(t, "2030-01-31T16:00", "2030-02-05T16:00")
+	scheduled := []ScheduledVideo{{Name: "video-01", Date: dates[0]}, {Name: "video-02", Date: dates[1], Immovable: true}}
+	if conflicts := schedule.GetConflicts(dates[1], scheduled); len(conflicts) != 1 || conflicts[0].Name != "video-02" {
+		t.Errorf("Expected a conflict with video-02, but got %v", conflicts)
+	}
+	if conflicts := schedule.GetConflicts(getTestDates(t, "2030-02-01T16:00")[0], scheduled); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, but got %v", conflicts)
+	}
+}
+
+func TestSchedule_GetSuggestionsMinGap(t *testing.T) {
+	schedule := getTestSchedule(t, 3)
+	after := getTestDates(t, "2030-01-27T00:00")[0]
+	scheduled := []ScheduledVideo{
+		{Name: "video-01", Date: getTestDates(t, "2030-01-29T16:00")[0], Immovable: true},
+		{Name: "video-02", Date: getTestDates(t, "2030-02-07T10:00")[0]},
+	}
+	if conflicts := schedule.GetConflicts(getTestDates(t, "2030-01-31T16:00")[0], scheduled); len(conflicts) != 1 {
+		t.Errorf("Expected the minimum gap to cause a conflict, but got %v", conflicts)
+	}
+	actual := schedule.GetSuggestions(after, scheduled, 3)
+	expected := getTestDates(t, "2030-02-12T16:00", "2030-02-14T16:00", "2030-02-19T16:00")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// videoSchemaVersion is the version of the video YAML structure written by this version of the tool.
+// Whenever the structure changes in a way that old files cannot be read as they are, increase it and register a migration.
+const videoSchemaVersion = 3
+
+var ErrVideoSchemaNewer = errors.New("video was created by a newer version of youtube-automation")
+
+// videoMigrations upgrade a video from the version equal to the index to the next one.
+// Files written before versioning was introduced have version 0.
+var videoMigrations = []func(video *Video){
+	migrateLegacySponsorship,
+	// Legacy values are normalized before the file is decoded (see normalizeLegacyVideo).
+	func(video *Video) {},
+	// Only videos written before the highlight had its own timestamp are parsed so that a highlight that happens to end with a time stays as it is.
+	migrateHighlight,
+}
+
+func migrateVideo(video *Video) error {
+	if video.SchemaVersion > videoSchemaVersion {
+		return fmt.Errorf("%w (schema version %d, supported up to %d); please upgrade", ErrVideoSchemaNewer, video.SchemaVersion, videoSchemaVersion)
+	}
+	for version := video.SchemaVersion; version < videoSchemaVersion; version++ {
+		videoMigrations[version](video)
+	}
+	video.SchemaVersion = videoSchemaVersion
+	return nil
+}
+
+// migrateLegacySponsorship moves the top-level sponsorship fields into Sponsorship.
+func migrateLegacySponsorship(video *Video) {
+	if len(video.Sponsorship.Amount) == 0 {
+		video.Sponsorship.Amount = video.Sponsored
+	}
+	if len(video.Sponsorship.Blocked) == 0 {
+		video.Sponsorship.Blocked = video.SponsorshipBlocked
+	}
+	video.Sponsored = ""
+	video.SponsorshipBlocked = ""
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchema_readVideoMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	legacy := "name: my-video\nsponsored: 1000\nsponsorshipblocked: Waiting for the contract\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	video, err := readVideo(path)
+	if err != nil {
+		t.Fatalf("Expected the legacy video to be migrated, but got %v", err)
+	}
+	if video.SchemaVersion != videoSchemaVersion {
+		t.Errorf("Expected schema version %d, but got %d", videoSchemaVersion, video.SchemaVersion)
+	}
+	if video.Sponsorship.Amount != "1000" || video.Sponsorship.Blocked != "Waiting for the contract" {
+		t.Errorf("Expected the sponsorship to be migrated, but got %+v", video.Sponsorship)
+	}
+	if len(video.Sponsored) > 0 || len(video.SponsorshipBlocked) > 0 {
+		t.Errorf("Expected the legacy sponsorship fields to be cleared, but got %q and %q", video.Sponsored, video.SponsorshipBlocked)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	if string(data) != legacy {
+		t.Errorf("Expected the file to be upgraded only on the next write, but got %q", string(data))
+	}
+}
+
+func TestSchema_readVideoNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	if err := os.WriteFile(path, []byte("schemaversion: 99\nname: my-video\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	if _, err := readVideo(path); !errors.Is(err, ErrVideoSchemaNewer) {
+		t.Errorf("Expected a newer version error, but got %v", err)
+	}
+}
+
+func TestSchema_migrateVideo(t *testing.T) {
+	video := Video{SchemaVersion: videoSchemaVersion, Sponsored: "500"}
+	if err := migrateVideo(&video); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if video.Sponsored != "500" || len(video.Sponsorship.Amount) > 0 {
+		t.Errorf("Expected a current video not to be migrated again, but got %+v", video)
+	}
+}
+
+func TestSchema_migrateVideoHighlight(t *testing.T) {
+	tests := map[string]struct {
+		version   int
+		highlight string
+		timestamp string
+	}{
+		"legacy":  {version: 2, highlight: "Demo", timestamp: "12:34"},
+		"current": {version: videoSchemaVersion, highlight: "Demo (12:34)"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			video := Video{SchemaVersion: test.version, Highlight: "Demo (12:34)"}
+			if err := migrateVideo(&video); err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if video.Highlight != test.highlight || video.HighlightTimestamp != test.timestamp {
+				t.Errorf("Expected: %q and %q\nGot: %q and %q", test.highlight, test.timestamp, video.Highlight, video.HighlightTimestamp)
+			}
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+const searchIndexVersion = 3
+const searchWeightTitle = 5
+const searchWeightTag = 4
+const searchWeightDescription = 3
+const searchWeightManuscript = 1
+const searchSnippetRadius = 60
+
+// searchJournalMaxSize is the size of the journal above which it is folded into the index.
+const searchJournalMaxSize = 1 << 20
+
+var searchIndexPath = filepath.Join(".index", "search.json")
+
+var searchWordRegex = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// SearchIndex is an inverted index of stemmed words in titles, tags, descriptions, project names, and manuscripts.
+// Terms map each stem to the score it contributes to each video (keyed by the path of its YAML).
+type SearchIndex struct {
+	Version   int
+	Documents map[string]SearchDocument
+	Terms     map[string]map[string]int
+}
+
+type SearchDocument struct {
+	Name        string
+	Category    string
+	Title       string
+	Description string
+	Gist        string
+	Stems       []string
+}
+
+// searchJournalEntry is a change appended to the journal of the index so that saving a video does not rewrite the whole index.
+// Entries without a document remove the video.
+type searchJournalEntry struct {
+	Path     string
+	Document *SearchDocument `json:",omitempty"`
+	Scores   map[string]int  `json:",omitempty"`
+}
+
+type SearchResult struct {
+	Path     string
+	Name     string
+	Category string
+	Score    int
+	Snippet  string
+	Phase    int
+}
+
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{Version: searchIndexVersion, Documents: map[string]SearchDocument{}, Terms: map[string]map[string]int{}}
+}
+
+// stemWord reduces common English inflections (e.g., deploying, deployed, and deploys) to the same stem.
+func stemWord(word string) string {
+	word = strings.ToLower(word)
+	if strings.HasSuffix(word, "ies") && len(word) > 4 {
+		return word[:len(word)-3] + "y"
+	}
+	stem := word
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if !strings.HasSuffix(word, suffix) || len(word)-len(suffix) < 3 {
+			continue
+		}
+		if suffix == "es" && !strings.HasSuffix(word, "ches") && !strings.HasSuffix(word, "shes") && !strings.HasSuffix(word, "xes") && !strings.HasSuffix(word, "sses") {
+			continue
+		}
+		if suffix == "s" && (strings.HasSuffix(word, "ss") || strings.HasSuffix(word, "us")) {
+			continue
+		}
+		stem = word[:len(word)-len(suffix)]
+		// running -> runn -> run
+		if n := len(stem); (suffix == "ing" || suffix == "ed") && stem[n-1] == stem[n-2] && !strings.ContainsRune("lsz", rune(stem[n-1])) {
+			stem = stem[:n-1]
+		}
+		break
+	}
+	if len(stem) > 3 && strings.HasSuffix(stem, "e") {
+		stem = stem[:len(stem)-1]
+	}
+	return stem
+}
+
+func getSearchStems(text string) []string {
+	stems := []string{}
+	for _, word := range searchWordRegex.FindAllString(text, -1) {
+		if len(word) < 2 {
+			continue
+		}
+		stems = append(stems, stemWord(word))
+	}
+	return stems
+}
+
+func (i *SearchIndex) Add(path string, video Video, manuscript string) {
+	document, scores := getSearchDocument(video, manuscript)
+	i.set(path, document, scores)
+}
+
+func (i *SearchIndex) set(path string, document SearchDocument, scores map[string]int) {
+	i.Remove(path)
+	i.Documents[path] = document
+	for stem, score := range scores {
+		if i.Terms[stem] == nil {
+			i.Terms[stem] = map[string]int{}
+		}
+		i.Terms[stem][path] = score
+	}
+}
+
+// getSearchDocument returns the document of the video and the score each of its stems contributes.
+func getSearchDocument(video Video, manuscript string) (SearchDocument, map[string]int) {
+	document := SearchDocument{Name: video.Name, Category: video.Category, Title: video.Title, Description: video.Description, Gist: video.Gist}
+	scores := map[string]int{}
+	fields := []struct {
+		text   string
+		weight int
+	}{
+		{video.Title, searchWeightTitle},
+		{video.Name, searchWeightTitle},
+		{video.Tags, searchWeightTag},
+		{video.Description, searchWeightDescription},
+		{video.ProjectName, searchWeightDescription},
+		{manuscript, searchWeightManuscript},
+	}
+	for _, field := range fields {
+		for _, stem := range getSearchStems(field.text) {
+			if scores[stem] == 0 {
+				document.Stems = append(document.Stems, stem)
+			}
+			scores[stem] += field.weight
+		}
+	}
+	return document, scores
+}
+
+func (i *SearchIndex) Remove(path string) {
+	for _, stem := range i.Documents[path].Stems {
+		delete(i.Terms[stem], path)
+		if len(i.Terms[stem]) == 0 {
+			delete(i.Terms, stem)
+		}
+	}
+	delete(i.Documents, path)
+}
+
+// Search returns videos that contain all the words of the query, best matches first.
+// Title matches weigh more than tag matches, those more than description and project matches, and those more than manuscript matches.
+func (i *SearchIndex) Search(query string) []SearchResult {
+	stems := getSearchStems(query)
+	if len(stems) == 0 {
+		return nil
+	}
+	scores := map[string]int{}
+	for path := range i.Terms[stems[0]] {
+		scores[path] = 0
+	}
+	for _, stem := range stems {
+		postings := i.Terms[stem]
+		for path := range scores {
+			if score, ok := postings[path]; ok {
+				scores[path] += score
+			} else {
+				delete(scores, path)
+			}
+		}
+	}
+	results := []SearchResult{}
+	for path, score := range scores {
+		document := i.Documents[path]
+		results = append(results, SearchResult{Path: path, Name: document.Name, Category: document.Category, Score: score})
+	}
+	sortSearchResults(results)
+	return results
+}
+
+func sortSearchResults(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Name < results[j].Name
+	})
+}
+
+// getSearchSnippet returns the text around the first line that contains any of the stems with all matching words highlighted.
+func getSearchSnippet(text string, stems []string, highlight func(string) string) string {
+	for _, line := range strings.Split(text, "\n") {
+		matches := searchWordRegex.FindAllStringIndex(line, -1)
+		first := -1
+		for _, match := range matches {
+			if containsStem(stems, line[match[0]:match[1]]) {
+				first = match[0]
+				break
+			}
+		}
+		if first < 0 {
+			continue
+		}
+		start := max(0, first-searchSnippetRadius)
+		end := min(len(line), first+searchSnippetRadius)
+		for start > 0 && line[start-1] != ' ' {
+			start--
+		}
+		for end < len(line) && line[end] != ' ' {
+			end++
+		}
+		snippet := searchWordRegex.ReplaceAllStringFunc(line[start:end], func(word string) string {
+			if containsStem(stems, word) {
+				return highlight(word)
+			}
+			return word
+		})
+		snippet = strings.TrimSpace(snippet)
+		if start > 0 {
+			snippet = "…" + snippet
+		}
+		if end < len(line) {
+			snippet += "…"
+		}
+		return snippet
+	}
+	return ""
+}
+
+func containsStem(stems []string, word string) bool {
+	stem := stemWord(word)
+	for _, s := range stems {
+		if s == stem {
+			return true
+		}
+	}
+	return false
+}
+
+// addSearchSnippets sets the snippet of each result from the title, the description, or the manuscript, whichever matches first.
+func (i *SearchIndex) addSearchSnippets(results []SearchResult, query string, highlight func(string) string) {
+	stems := getSearchStems(query)
+	for r := range results {
+		document := i.Documents[results[r].Path]
+		texts := []string{document.Title, document.Description}
+		if manuscript, _, err := readManuscript(document.Gist); err == nil {
+			texts = append(texts, manuscript)
+		}
+		for _, text := range texts {
+			if snippet := getSearchSnippet(text, stems, highlight); len(snippet) > 0 {
+				results[r].Snippet = snippet
+				break
+			}
+		}
+	}
+}
+
+// searchVideoMetadata is used when the index is missing or corrupted. It matches words of the query against names, titles, tags, descriptions, and project names without stemming.
+func searchVideoMetadata(videos []Video, query string, highlight func(string) string) []SearchResult {
+	words := strings.Fields(strings.ToLower(query))
+	results := []SearchResult{}
+	for _, video := range videos {
+		score := 0
+		matched := true
+		for _, word := range words {
+			wordScore := 0
+			if strings.Contains(strings.ToLower(video.Title), word) || strings.Contains(strings.ToLower(video.Name), word) {
+				wordScore += searchWeightTitle
+			}
+			if strings.Contains(strings.ToLower(video.Tags), word) {
+				wordScore += searchWeightTag
+			}
+			if strings.Contains(strings.ToLower(video.Description), word) || strings.Contains(strings.ToLower(video.ProjectName), word) {
+				wordScore += searchWeightDescription
+			}
+			if wordScore == 0 {
+				matched = false
+				break
+			}
+			score += wordScore
+		}
+		if !matched || len(words) == 0 {
+			continue
+		}
+		snippet := getSearchSnippet(video.Description, getSearchStems(query), highlight)
+		results = append(results, SearchResult{Path: video.Path, Name: video.Name, Category: video.Category, Score: score, Snippet: snippet})
+	}
+	sortSearchResults(results)
+	return results
+}
+
+// getSearchResultVideos sets the phase of each result and returns the videos of the results in the same order.
+// Results of videos that are no longer in the index are dropped.
+func getSearchResultVideos(results []SearchResult, videos []Video) ([]SearchResult, []Video) {
+	byPath := map[string]Video{}
+	for _, video := range videos {
+		byPath[video.Path] = video
+	}
+	found := []SearchResult{}
+	foundVideos := []Video{}
+	for _, result := range results {
+		video, ok := byPath[result.Path]
+		if !ok {
+			continue
+		}
+		result.Phase = workflow.GetPhase(video)
+		found = append(found, result)
+		foundVideos = append(foundVideos, video)
+	}
+	return found, foundVideos
+}
+
+func getSearchJournalPath(path string) string {
+	return path + ".journal"
+}
+
+// loadSearchIndex reads the index and applies the changes from its journal.
+func loadSearchIndex(path string) (*SearchIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	index := NewSearchIndex()
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("search index %s is corrupted: %w", path, err)
+	}
+	if index.Version != searchIndexVersion {
+		return nil, fmt.Errorf("search index %s was created by a different version (%d)", path, index.Version)
+	}
+	journal, err := os.ReadFile(getSearchJournalPath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(journal)), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		entry := searchJournalEntry{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("search index journal %s is corrupted: %w", getSearchJournalPath(path), err)
+		}
+		if entry.Document == nil {
+			index.Remove(entry.Path)
+		} else {
+			index.set(entry.Path, *entry.Document, entry.Scores)
+		}
+	}
+	return index, nil
+}
+
+func saveSearchIndex(path string, index *SearchIndex) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return err
+	}
+	// Replaying the journal over the index it was folded into changes nothing, so it does not matter if it is not removed.
+	if err := os.Remove(getSearchJournalPath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func buildSearchIndex(videos []Video) *SearchIndex {
+	index := NewSearchIndex()
+	for _, video := range videos {
+		manuscript, _, _ := readManuscript(video.Gist)
+		index.Add(video.Path, video, manuscript)
+	}
+	return index
+}
+
+// updateSearchIndex reindexes the video if the search index exists. Failures are ignored since searching falls back to metadata when the index is broken.
+func updateSearchIndex(path string, video Video) {
+	manuscript, _, _ := readManuscript(video.Gist)
+	document, scores := getSearchDocument(video, manuscript)
+	appendSearchJournal(searchIndexPath, searchJournalEntry{Path: path, Document: &document, Scores: scores})
+}
+
+// removeFromSearchIndex removes the video from the search index if it exists.
+func removeFromSearchIndex(path string) {
+	appendSearchJournal(searchIndexPath, searchJournalEntry{Path: path})
+}
+
+// appendSearchJournal records the change in the journal of the index and folds the journal into the index once it grows too big.
+func appendSearchJournal(path string, entry searchJournalEntry) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(getSearchJournalPath(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(getSearchJournalPath(path))
+	if err != nil || info.Size() < searchJournalMaxSize {
+		return err
+	}
+	index, err := loadSearchIndex(path)
+	if err != nil {
+		return err
+	}
+	return saveSearchIndex(path, index)
+}
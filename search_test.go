@@ -0,0 +1,226 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func getSearchTestIndex() *SearchIndex {
+	index := NewSearchIndex()
+	index.Add("a.yaml", Video{Name: "argo-cd", Category: "gitops", Title: "Deploying with Argo CD"}, "Argo CD syncs manifests.")
+	index.Add("b.yaml", Video{Name: "flux", Category: "gitops", Title: "Flux", Description: "How to deploy with Flux."}, "Flux is similar to Argo CD.")
+	index.Add("c.yaml", Video{Name: "crossplane", Category: "iac", Title: "Crossplane"}, "We deployed the database.\nThe deployment worked.")
+	return index
+}
+
+func getSearchResultPaths(results []SearchResult) []string {
+	paths := []string{}
+	for _, result := range results {
+		paths = append(paths, result.Path)
+	}
+	return paths
+}
+
+func TestSearch_stemWord(t *testing.T) {
+	words := map[string]string{
+		"deploy":       "deploy",
+		"deploying":    "deploy",
+		"deployed":     "deploy",
+		"deploys":      "deploy",
+		"release":      "releas",
+		"releases":     "releas",
+		"released":     "releas",
+		"running":      "run",
+		"planned":      "plan",
+		"installing":   "install",
+		"dependencies": "dependency",
+		"pushes":       "push",
+		"class":        "class",
+		"status":       "status",
+	}
+	for word, expected := range words {
+		if actual := stemWord(word); actual != expected {
+			t.Errorf("%s: expected %s, but got %s", word, expected, actual)
+		}
+	}
+}
+
+func TestSearch_Search(t *testing.T) {
+	index := getSearchTestIndex()
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"deploy", []string{"a.yaml", "b.yaml", "c.yaml"}},
+		{"argo", []string{"a.yaml", "b.yaml"}},
+		{"Argo Flux", []string{"b.yaml"}},
+		{"kubernetes", []string{}},
+		{"", []string{}},
+	}
+	for _, test := range tests {
+		actual := getSearchResultPaths(index.Search(test.query))
+		if !slices.Equal(actual, test.expected) {
+			t.Errorf("%q\nExpected: %v\nGot: %v", test.query, test.expected, actual)
+		}
+	}
+}
+
+func TestSearch_SearchIncremental(t *testing.T) {
+	index := getSearchTestIndex()
+	index.Add("c.yaml", Video{Name: "crossplane", Category: "iac", Title: "Crossplane"}, "Compositions.")
+	if actual := getSearchResultPaths(index.Search("deploy")); !slices.Equal(actual, []string{"a.yaml", "b.yaml"}) {
+		t.Errorf("Expected the old manuscript to be removed from the index, but got %v", actual)
+	}
+	if actual := getSearchResultPaths(index.Search("composition")); !slices.Equal(actual, []string{"c.yaml"}) {
+		t.Errorf("Expected the new manuscript to be indexed, but got %v", actual)
+	}
+	index.Remove("a.yaml")
+	if _, ok := index.Terms[stemWord("syncs")]; ok {
+		t.Errorf("Expected terms used only by the removed video to be removed")
+	}
+}
+
+func TestSearch_getSearchSnippet(t *testing.T) {
+	highlight := func(word string) string { return "[" + word + "]" }
+	stems := getSearchStems("deploy")
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Intro\nWe deployed it and kept deploying.", "We [deployed] it and kept [deploying]."},
+		{"Nothing here.", ""},
+		{"This is a very long line with a lot of words before we finally get to the part where we deploy the application and then keep talking about it for a very long time.", "…with a lot of words before we finally get to the part where we [deploy] the application and then keep talking about it for a very…"},
+	}
+	for _, test := range tests {
+		if actual := getSearchSnippet(test.text, stems, highlight); actual != test.expected {
+			t.Errorf("Expected: %s\nGot: %s", test.expected, actual)
+		}
+	}
+}
+
+func TestSearch_loadSearchIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".index", "search.json")
+	if _, err := loadSearchIndex(path); !os.IsNotExist(err) {
+		t.Errorf("Expected a missing index error, but got %v", err)
+	}
+	if err := saveSearchIndex(path, getSearchTestIndex()); err != nil {
+		t.Fatalf("Error occurred while saving the index: %v", err)
+	}
+	index, err := loadSearchIndex(path)
+	if err != nil {
+		t.Fatalf("Error occurred while loading the index: %v", err)
+	}
+	if actual := getSearchResultPaths(index.Search("argo")); !slices.Equal(actual, []string{"a.yaml", "b.yaml"}) {
+		t.Errorf("Expected the loaded index to work, but got %v", actual)
+	}
+	if err := os.WriteFile(path, []byte(`{"Version": 1, "Terms": [`), 0644); err != nil {
+		t.Fatalf("Error occurred while corrupting the index: %v", err)
+	}
+	if _, err := loadSearchIndex(path); err == nil {
+		t.Errorf("Expected an error for a corrupted index")
+	}
+}
+
+func TestSearch_appendSearchJournal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".index", "search.json")
+	if err := appendSearchJournal(path, searchJournalEntry{Path: "a.yaml"}); err == nil {
+		t.Errorf("Expected no journal without an index")
+	}
+	if err := saveSearchIndex(path, getSearchTestIndex()); err != nil {
+		t.Fatalf("Error occurred while saving the index: %v", err)
+	}
+	snapshot, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading the index: %v", err)
+	}
+	document, scores := getSearchDocument(Video{Name: "kargo", Title: "Kargo"}, "Kargo promotes Argo CD apps.")
+	for _, entry := range []searchJournalEntry{{Path: "a.yaml"}, {Path: "d.yaml", Document: &document, Scores: scores}} {
+		if err := appendSearchJournal(path, entry); err != nil {
+			t.Fatalf("Error occurred while appending to the journal: %v", err)
+		}
+	}
+	if actual, _ := os.ReadFile(path); string(actual) != string(snapshot) {
+		t.Errorf("Expected the index not to be rewritten")
+	}
+	index, err := loadSearchIndex(path)
+	if err != nil {
+		t.Fatalf("Error occurred while loading the index: %v", err)
+	}
+	if actual := getSearchResultPaths(index.Search("argo")); !slices.Equal(actual, []string{"b.yaml", "d.yaml"}) {
+		t.Errorf("Expected: [b.yaml d.yaml]\nGot: %v", actual)
+	}
+	if err := saveSearchIndex(path, index); err != nil {
+		t.Fatalf("Error occurred while saving the index: %v", err)
+	}
+	if _, err := os.Stat(getSearchJournalPath(path)); !os.IsNotExist(err) {
+		t.Errorf("Expected the journal to be folded into the index, but got %v", err)
+	}
+}
+
+func TestSearch_searchVideoMetadata(t *testing.T) {
+	videos := []Video{
+		{Name: "flux", Path: "b.yaml", Title: "Flux", Description: "How to deploy with Flux."},
+		{Name: "argo-cd", Path: "a.yaml", Title: "Argo CD", Description: "Deploy with Argo CD."},
+		{Name: "other", Path: "c.yaml", Title: "Other", Description: "Argo is mentioned here."},
+	}
+	results := searchVideoMetadata(videos, "argo deploy", func(word string) string { return "[" + word + "]" })
+	if actual := getSearchResultPaths(results); !slices.Equal(actual, []string{"a.yaml"}) {
+		t.Fatalf("Expected only videos matching all words, but got %v", actual)
+	}
+	if results[0].Snippet != "[Deploy] with [Argo] CD." {
+		t.Errorf("Unexpected snippet %s", results[0].Snippet)
+	}
+	if actual := getSearchResultPaths(searchVideoMetadata(videos, "argo", func(word string) string { return word })); !slices.Equal(actual, []string{"a.yaml", "c.yaml"}) {
+		t.Errorf("Expected title matches to rank first, but got %v", actual)
+	}
+}
+
+func TestSearch_SearchRanking(t *testing.T) {
+	videos := []Video{
+		{Name: "description", Path: "description.yaml", Title: "Something", Description: "Running Kubernetes locally."},
+		{Name: "tag", Path: "tag.yaml", Title: "Something", Tags: "kubernetes,kind"},
+		{Name: "title", Path: "title.yaml", Title: "Kubernetes Locally"},
+		{Name: "project", Path: "project.yaml", Title: "Something", ProjectName: "Kubernetes"},
+	}
+	index := NewSearchIndex()
+	for _, video := range videos {
+		index.Add(video.Path, video, "")
+	}
+	tests := map[string]struct {
+		query    string
+		expected []string
+	}{
+		"title over tag over description": {query: "KUBERNETES", expected: []string{"title.yaml", "tag.yaml", "description.yaml", "project.yaml"}},
+		"all terms":                       {query: "kubernetes kind", expected: []string{"tag.yaml"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getSearchResultPaths(index.Search(test.query)); !slices.Equal(actual, test.expected) {
+				t.Errorf("Expected: %v\nGot: %v", test.expected, actual)
+			}
+			if actual := getSearchResultPaths(searchVideoMetadata(videos, test.query, func(word string) string { return word })); !slices.Equal(actual, test.expected) {
+				t.Errorf("Expected metadata search: %v\nGot: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSearch_getSearchResultVideos(t *testing.T) {
+	videos := []Video{
+		{Name: "published", Path: "published.yaml", Repo: "https://github.com/vfarcic/something"},
+		{Name: "delayed", Path: "delayed.yaml", Delayed: true},
+	}
+	results := []SearchResult{{Path: "delayed.yaml"}, {Path: "deleted.yaml"}, {Path: "published.yaml"}}
+	actual, actualVideos := getSearchResultVideos(results, videos)
+	expected := []SearchResult{{Path: "delayed.yaml", Phase: videosPhaseDelayed}, {Path: "published.yaml", Phase: videosPhasePublished}}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+	if len(actualVideos) != 2 || actualVideos[0].Name != "delayed" || actualVideos[1].Name != "published" {
+		t.Errorf("Expected the videos in the order of the results, but got %v", actualVideos)
+	}
+}
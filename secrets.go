@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const secretsKeyEnv = "VIDEO_SECRETS_KEY"
+
+// secretPrefix marks encrypted values and their format so that plaintext can never be mistaken for a secret.
+const secretPrefix = "enc:v1:"
+
+const secretMask = "••••••"
+
+var ErrSecretsKeyUnavailable = errors.New("the secrets key is not available; values are unavailable")
+
+var ErrSecretUnavailable = errors.New("the value cannot be decrypted with the current key; it is unavailable")
+
+// SecretStatus is what can be shown about a secret without revealing it.
+type SecretStatus struct {
+	Name string
+	Set  bool
+}
+
+// loadSecretsKey reads the base64 encoded 256-bit key from the environment variable or, if it is not set, from the key file.
+func loadSecretsKey(keyFile string) ([]byte, error) {
+	encoded := os.Getenv(secretsKeyEnv)
+	if len(encoded) == 0 {
+		data, err := os.ReadFile(keyFile)
+		if os.IsNotExist(err) {
+			return nil, ErrSecretsKeyUnavailable
+		}
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(data)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("the secrets key must be 32 bytes encoded as base64")
+	}
+	return key, nil
+}
+
+// createSecretsKey writes a new random key. It refuses to replace an existing file since that would make all the values encrypted with it unavailable.
+func createSecretsKey(keyFile string) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(keyFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func newSecretsCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, ErrSecretsKeyUnavailable
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecret uses AES-256-GCM with a random nonce stored in front of the ciphertext.
+func encryptSecret(key []byte, plaintext string) (string, error) {
+	aead, err := newSecretsCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return secretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key []byte, value string) (string, error) {
+	aead, err := newSecretsCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(value, secretPrefix) {
+		return "", ErrSecretUnavailable
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, secretPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrSecretUnavailable
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", ErrSecretUnavailable
+	}
+	return string(plaintext), nil
+}
+
+// validateSecretsEncrypted is the last line of defense against writing plaintext values to the video file.
+func validateSecretsEncrypted(secrets map[string]string) error {
+	for name, value := range secrets {
+		if len(value) > 0 && !strings.HasPrefix(value, secretPrefix) {
+			return fmt.Errorf("secret %s is not encrypted and will not be written", name)
+		}
+	}
+	return nil
+}
+
+func getSecretStatuses(secrets map[string]string) []SecretStatus {
+	statuses := []SecretStatus{}
+	for name, value := range secrets {
+		statuses = append(statuses, SecretStatus{Name: name, Set: len(value) > 0})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func getSecretTitle(status SecretStatus) string {
+	if !status.Set {
+		return fmt.Sprintf("%s: (not set)", status.Name)
+	}
+	return fmt.Sprintf("%s: %s", status.Name, secretMask)
+}
+
+// withoutSecrets returns a copy of the video that can leave the machine (e.g., in webhooks) without the secrets, not even encrypted ones.
+func withoutSecrets(video Video) Video {
+	video.Secrets = nil
+	return video
+}
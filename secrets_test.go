@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getSecretsTestKey(t *testing.T) []byte {
+	key, err := createSecretsKey(filepath.Join(t.TempDir(), ".secrets.key"))
+	if err != nil {
+		t.Fatalf("Error occurred while creating the key: %v", err)
+	}
+	return key
+}
+
+func TestSecrets_encryptSecret(t *testing.T) {
+	key := getSecretsTestKey(t)
+	encrypted, err := encryptSecret(key, "trial-password")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !strings.HasPrefix(encrypted, secretPrefix) || strings.Contains(encrypted, "trial-password") {
+		t.Errorf("Expected an encrypted value, but got %q", encrypted)
+	}
+	if again, _ := encryptSecret(key, "trial-password"); again == encrypted {
+		t.Errorf("Expected a different nonce for each encryption")
+	}
+	decrypted, err := decryptSecret(key, encrypted)
+	if err != nil || decrypted != "trial-password" {
+		t.Errorf("Expected: trial-password\nGot: %q and %v", decrypted, err)
+	}
+	if _, err := decryptSecret(getSecretsTestKey(t), encrypted); !errors.Is(err, ErrSecretUnavailable) {
+		t.Errorf("Expected another key to make the value unavailable, but got %v", err)
+	}
+	if _, err := decryptSecret(key, "trial-password"); !errors.Is(err, ErrSecretUnavailable) {
+		t.Errorf("Expected a plaintext value to be unavailable, but got %v", err)
+	}
+}
+
+func TestSecrets_loadSecretsKey(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, ".secrets.key")
+	t.Setenv(secretsKeyEnv, "")
+	if _, err := loadSecretsKey(keyFile); !errors.Is(err, ErrSecretsKeyUnavailable) {
+		t.Errorf("Expected a missing key file to make the key unavailable, but got %v", err)
+	}
+	if _, err := decryptSecret(nil, secretPrefix+"abc"); !errors.Is(err, ErrSecretsKeyUnavailable) {
+		t.Errorf("Expected values to be unavailable without a key, but got %v", err)
+	}
+	created, err := createSecretsKey(keyFile)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if info, _ := os.Stat(keyFile); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the key file to be readable only by the owner, but got %v", info.Mode().Perm())
+	}
+	if _, err := createSecretsKey(keyFile); err == nil {
+		t.Errorf("Expected an existing key file not to be replaced")
+	}
+	loaded, err := loadSecretsKey(keyFile)
+	if err != nil || string(loaded) != string(created) {
+		t.Errorf("Expected the created key to be loaded, but got %v", err)
+	}
+	t.Setenv(secretsKeyEnv, "not a key")
+	if _, err := loadSecretsKey(keyFile); err == nil || errors.Is(err, ErrSecretsKeyUnavailable) {
+		t.Errorf("Expected an invalid key in the environment to be reported, but got %v", err)
+	}
+}
+
+func TestSecrets_writeVideo(t *testing.T) {
+	key := getSecretsTestKey(t)
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	yaml := YAML{}
+	if err := yaml.writeVideo(&Video{Name: "video", Secrets: map[string]string{"license": "trial-password"}}, path); err == nil {
+		t.Errorf("Expected a plaintext secret to be rejected")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be written")
+	}
+	encrypted, _ := encryptSecret(key, "trial-password")
+	if err := yaml.writeVideo(&Video{Name: "video", Secrets: map[string]string{"license": encrypted}}, path); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "trial-password") {
+		t.Errorf("Expected no plaintext in the video file, but got %s", data)
+	}
+	video, err := readVideo(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if value, err := decryptSecret(nil, video.Secrets["license"]); !errors.Is(err, ErrSecretsKeyUnavailable) || len(value) > 0 {
+		t.Errorf("Expected the value to be unavailable without the key, but got %q and %v", value, err)
+	}
+	if value, _ := decryptSecret(key, video.Secrets["license"]); value != "trial-password" {
+		t.Errorf("Expected: trial-password\nGot: %q", value)
+	}
+}
+
+func TestSecrets_getSecretTitle(t *testing.T) {
+	statuses := getSecretStatuses(map[string]string{"trial": secretPrefix + "abc", "license": ""})
+	actual := []string{}
+	for _, status := range statuses {
+		actual = append(actual, getSecretTitle(status))
+	}
+	expected := []string{"license: (not set)", "trial: " + secretMask}
+	if strings.Join(actual, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type ManuscriptSection = workflow.ManuscriptSection
+
+// getManuscriptHeadings returns the section headings in the order they appear in the manuscript. Unlike animation cues, Intro, Setup, and Destroy are included since they are recorded as well.
+func getManuscriptHeadings(content string, levels []int) []string {
+	headings := []string{}
+	inCode := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, " ", " "))
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if heading, ok := getAnimationHeader(line, levels); ok && len(heading) > 0 {
+			headings = append(headings, heading)
+		}
+	}
+	return headings
+}
+
+// getSectionID hashes the heading ignoring case and whitespace so that it does not change when sections are reordered or reformatted.
+// Repeated headings get the number of the occurrence as a suffix.
+func getSectionID(heading string, occurrence int) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(heading), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	id := hex.EncodeToString(sum[:6])
+	if occurrence > 1 {
+		id = fmt.Sprintf("%s-%d", id, occurrence)
+	}
+	return id
+}
+
+// syncSections matches the stored sections with the headings of the manuscript. New headings are added unchecked and
+// stored sections that are not in the manuscript anymore are kept as orphaned, after the others, if any of their flags are set.
+func syncSections(stored []ManuscriptSection, headings []string) []ManuscriptSection {
+	byID := map[string]ManuscriptSection{}
+	for _, section := range stored {
+		byID[section.ID] = section
+	}
+	synced := []ManuscriptSection{}
+	matched := map[string]bool{}
+	occurrences := map[string]int{}
+	for _, heading := range headings {
+		base := getSectionID(heading, 1)
+		occurrences[base]++
+		id := getSectionID(heading, occurrences[base])
+		section, ok := byID[id]
+		if !ok {
+			section = ManuscriptSection{ID: id}
+		}
+		section.Heading = heading
+		section.Orphaned = false
+		synced = append(synced, section)
+		matched[id] = true
+	}
+	for _, section := range stored {
+		if matched[section.ID] || (!section.Recorded && !section.Edited) {
+			continue
+		}
+		section.Orphaned = true
+		synced = append(synced, section)
+	}
+	return synced
+}
+
+// getSectionsProgress counts the sections that are still in the manuscript.
+func getSectionsProgress(sections []ManuscriptSection) (recorded, edited, total int) {
+	for _, section := range sections {
+		if section.Orphaned {
+			continue
+		}
+		total++
+		if section.Recorded {
+			recorded++
+		}
+		if section.Edited {
+			edited++
+		}
+	}
+	return recorded, edited, total
+}
+
+func getOrphanedSections(sections []ManuscriptSection) []ManuscriptSection {
+	orphaned := []ManuscriptSection{}
+	for _, section := range sections {
+		if section.Orphaned {
+			orphaned = append(orphaned, section)
+		}
+	}
+	return orphaned
+}
+
+// applySectionsRecorded marks the talking head and the screen as done when enabled and all sections are recorded. It never unmarks them.
+func applySectionsRecorded(video Video, enabled bool) Video {
+	recorded, _, total := getSectionsProgress(video.Sections)
+	if enabled && total > 0 && recorded == total {
+		video.Head = true
+		video.Screen = true
+	}
+	return video
+}
+
+func getSectionTitle(section ManuscriptSection) string {
+	if section.Orphaned {
+		return fmt.Sprintf("%s (orphaned)", section.Heading)
+	}
+	return section.Heading
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSections_getManuscriptHeadings(t *testing.T) {
+	content := "# Title\n\n## Intro\n\nText\n\n```sh\n## Not a heading\n```\n\n##  Setup  \n\n### Details\n\n## Demo\n"
+	expected := []string{"Intro", "Setup", "Demo"}
+	if actual := getManuscriptHeadings(content, []int{2}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestSections_syncSectionsReordered(t *testing.T) {
+	stored := syncSections(nil, []string{"Intro", "Setup", "Demo"})
+	stored[1].Recorded = true
+	stored[2].Edited = true
+
+	synced := syncSections(stored, []string{"Demo", "intro", "Setup"})
+	expected := []ManuscriptSection{
+		{ID: stored[2].ID, Heading: "Demo", Edited: true},
+		{ID: stored[0].ID, Heading: "intro"},
+		{ID: stored[1].ID, Heading: "Setup", Recorded: true},
+	}
+	if !reflect.DeepEqual(synced, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, synced)
+	}
+	if id := getSectionID("  Setup ", 1); id != stored[1].ID {
+		t.Errorf("Expected the ID to ignore whitespace\nGot: %s and %s", id, stored[1].ID)
+	}
+}
+
+func TestSections_syncSectionsRenamed(t *testing.T) {
+	stored := syncSections(nil, []string{"Intro", "Demo", "Destroy"})
+	stored[1].Recorded = true
+
+	synced := syncSections(stored, []string{"Intro", "Demo With Argo CD", "Destroy"})
+	headings := []string{}
+	for _, section := range synced {
+		headings = append(headings, getSectionTitle(section))
+	}
+	expected := []string{"Intro", "Demo With Argo CD", "Destroy", "Demo (orphaned)"}
+	if !reflect.DeepEqual(headings, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, headings)
+	}
+	if synced[1].Recorded || !synced[3].Recorded {
+		t.Errorf("Expected the renamed section to be unchecked and the orphaned one to keep its flags\nGot: %+v", synced)
+	}
+	if recorded, _, total := getSectionsProgress(synced); recorded != 0 || total != 3 {
+		t.Errorf("Expected orphaned sections not to count\nGot: %d/%d", recorded, total)
+	}
+
+	// Orphaned sections come back when the heading is restored.
+	restored := syncSections(synced, []string{"Intro", "Demo", "Destroy"})
+	if len(restored) != 3 || !restored[1].Recorded || restored[1].Orphaned {
+		t.Errorf("Expected the orphaned section to be matched again\nGot: %+v", restored)
+	}
+
+	// Unchecked sections have nothing to lose and are dropped.
+	if synced := syncSections(stored, []string{"Demo"}); len(synced) != 1 {
+		t.Errorf("Expected unchecked sections that are gone to be dropped\nGot: %+v", synced)
+	}
+}
+
+func TestSections_syncSectionsRepeatedHeadings(t *testing.T) {
+	stored := syncSections(nil, []string{"Demo", "Demo"})
+	if stored[0].ID == stored[1].ID {
+		t.Fatalf("Expected repeated headings to have different IDs\nGot: %+v", stored)
+	}
+	stored[1].Recorded = true
+	synced := syncSections(stored, []string{"Intro", "Demo", "Demo"})
+	if synced[1].Recorded || !synced[2].Recorded {
+		t.Errorf("Expected the flags to follow the occurrence\nGot: %+v", synced)
+	}
+}
+
+func TestSections_applySectionsRecorded(t *testing.T) {
+	all := []ManuscriptSection{{ID: "a", Recorded: true}, {ID: "b", Recorded: true}, {ID: "c", Orphaned: true}}
+	some := []ManuscriptSection{{ID: "a", Recorded: true}, {ID: "b"}}
+	tests := map[string]struct {
+		video    Video
+		enabled  bool
+		expected bool
+	}{
+		"all recorded":            {video: Video{Sections: all}, enabled: true, expected: true},
+		"disabled":                {video: Video{Sections: all}},
+		"some recorded":           {video: Video{Sections: some}, enabled: true},
+		"no sections":             {enabled: true},
+		"already done stays done": {video: Video{Sections: some, Head: true, Screen: true}, enabled: true, expected: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			video := applySectionsRecorded(tc.video, tc.enabled)
+			if video.Head != tc.expected || video.Screen != tc.expected {
+				t.Errorf("Expected head and screen to be %t\nGot: %t and %t", tc.expected, video.Head, video.Screen)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// keywordsDescriptionPrefix is how much of the description is shown in search results without expanding it.
+const keywordsDescriptionPrefix = 150
+
+const keywordsMaxSuggestions = 5
+
+type KeywordSuggestion struct {
+	Keyword string `json:"keyword"`
+	Notes   string `json:"notes"`
+}
+
+// getSlug sanitizes the text into lowercase ASCII words separated with dashes.
+func getSlug(text string) string {
+	return strings.Trim(assetSlugRegex.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
+
+// getVideoSlug returns the slug of the video, derived from the title if it was not overridden.
+func getVideoSlug(video Video) string {
+	if len(strings.TrimSpace(video.Slug)) > 0 {
+		return video.Slug
+	}
+	return getSlug(video.Title)
+}
+
+// getHugoSlug returns the slug of a published post, which is the name of the directory of its _index.md.
+func getHugoSlug(hugoPath string) string {
+	if len(hugoPath) == 0 {
+		return ""
+	}
+	return filepath.Base(filepath.Dir(hugoPath))
+}
+
+// validateSlug checks that the slug is sanitized and that no other video uses it or was published with it.
+// The slugs of the other videos map to their names.
+func validateSlug(slug string, others map[string]string) error {
+	if len(slug) == 0 {
+		return fmt.Errorf("the slug is required")
+	}
+	if sanitized := getSlug(slug); sanitized != slug {
+		return fmt.Errorf("slug %q should be %q", slug, sanitized)
+	}
+	if name, ok := others[slug]; ok {
+		return fmt.Errorf("slug %s is already used by %s", slug, name)
+	}
+	return nil
+}
+
+// getUsedSlug returns the slug the video was published with or, if it was not, the one it will be published with.
+func getUsedSlug(video Video) string {
+	if len(video.HugoPath) > 0 {
+		return getHugoSlug(video.HugoPath)
+	}
+	return getVideoSlug(video)
+}
+
+// getValidKeywordSuggestions drops empty and duplicate keywords and keeps at most keywordsMaxSuggestions.
+func getValidKeywordSuggestions(parsed []KeywordSuggestion) []KeywordSuggestion {
+	suggestions := []KeywordSuggestion{}
+	seen := map[string]bool{}
+	for _, suggestion := range parsed {
+		suggestion.Keyword = strings.TrimSpace(suggestion.Keyword)
+		key := strings.ToLower(suggestion.Keyword)
+		if len(key) == 0 || seen[key] || strings.Contains(key, ",") {
+			continue
+		}
+		seen[key] = true
+		suggestions = append(suggestions, suggestion)
+		if len(suggestions) == keywordsMaxSuggestions {
+			break
+		}
+	}
+	return suggestions
+}
+
+func getPrimaryKeyword(video Video) string {
+	keywords := splitTags(video.TargetKeywords)
+	if len(keywords) == 0 {
+		return ""
+	}
+	return keywords[0]
+}
+
+func isKeywordInTitle(video Video) bool {
+	keyword := getPrimaryKeyword(video)
+	return len(keyword) > 0 && strings.Contains(strings.ToLower(video.Title), strings.ToLower(keyword))
+}
+
+// isKeywordInDescriptionPrefix checks the part of the description that is visible before it is expanded.
+func isKeywordInDescriptionPrefix(video Video) bool {
+	keyword := getPrimaryKeyword(video)
+	if len(keyword) == 0 {
+		return false
+	}
+	prefix := []rune(video.Description)
+	if len(prefix) > keywordsDescriptionPrefix {
+		prefix = prefix[:keywordsDescriptionPrefix]
+	}
+	return strings.Contains(strings.ToLower(string(prefix)), strings.ToLower(keyword))
+}
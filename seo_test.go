@@ -0,0 +1,124 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSeo_getSlug(t *testing.T) {
+	tests := map[string]struct {
+		title    string
+		expected string
+	}{
+		"words":       {title: "Argo CD Explained", expected: "argo-cd-explained"},
+		"punctuation": {title: "GitOps: What's Next (& Why)?!", expected: "gitops-what-s-next-why"},
+		"slashes":     {title: "CI/CD in 2030", expected: "ci-cd-in-2030"},
+		"empty":       {expected: ""},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getSlug(tc.title); actual != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, actual)
+			}
+		})
+	}
+	if actual := getVideoSlug(Video{Title: "Argo CD Explained", Slug: "argo-cd"}); actual != "argo-cd" {
+		t.Errorf("Expected the overridden slug\nGot: %s", actual)
+	}
+}
+
+func TestSeo_validateSlug(t *testing.T) {
+	videos := []Video{
+		{Name: "published", Path: "published.yaml", Title: "Something Else", HugoPath: "hugo/content/devops/argo-cd-explained/_index.md"},
+		{Name: "overridden", Path: "overridden.yaml", Title: "Crossplane", Slug: "crossplane-intro"},
+		{Name: "derived", Path: "derived.yaml", Title: "KubeVela Explained"},
+		{Name: "current", Path: "current.yaml", Title: "Current", HugoPath: "hugo/content/devops/current/_index.md"},
+	}
+	tests := map[string]struct {
+		slug     string
+		expected string
+	}{
+		"unique":              {slug: "argo-cd-2030"},
+		"own post":            {slug: "current"},
+		"published post":      {slug: "argo-cd-explained", expected: "slug argo-cd-explained is already used by published"},
+		"published old title": {slug: "something-else"},
+		"overridden slug":     {slug: "crossplane-intro", expected: "slug crossplane-intro is already used by overridden"},
+		"derived slug":        {slug: "kubevela-explained", expected: "slug kubevela-explained is already used by derived"},
+		"not sanitized":       {slug: "Argo CD", expected: `slug "Argo CD" should be "argo-cd"`},
+		"empty":               {expected: "the slug is required"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			others := map[string]string{}
+			for _, video := range videos {
+				if video.Path != "current.yaml" {
+					others[getUsedSlug(video)] = video.Name
+				}
+			}
+			actual := ""
+			if err := validateSlug(tc.slug, others); err != nil {
+				actual = err.Error()
+			}
+			if actual != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSeo_getValidKeywordSuggestions(t *testing.T) {
+	parsed := []KeywordSuggestion{
+		{Keyword: " argo cd tutorial "},
+		{Keyword: ""},
+		{Keyword: "Argo CD Tutorial"},
+		{Keyword: "gitops, argo"},
+		{Keyword: "gitops"},
+		{Keyword: "argo cd vs flux"},
+		{Keyword: "kubernetes deployments"},
+		{Keyword: "continuous delivery"},
+		{Keyword: "one too many"},
+	}
+	expected := []string{"argo cd tutorial", "gitops", "argo cd vs flux", "kubernetes deployments", "continuous delivery"}
+	actual := []string{}
+	for _, suggestion := range getValidKeywordSuggestions(parsed) {
+		actual = append(actual, suggestion.Keyword)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestSeo_keywordPresence(t *testing.T) {
+	tests := map[string]struct {
+		video               Video
+		expectedTitle       bool
+		expectedDescription bool
+	}{
+		"both": {
+			video:               Video{TargetKeywords: "Argo CD, gitops", Title: "Argo CD Explained", Description: "Everything about argo cd."},
+			expectedTitle:       true,
+			expectedDescription: true,
+		},
+		"only the primary keyword counts": {
+			video: Video{TargetKeywords: "flux,argo cd", Title: "Argo CD Explained", Description: "Argo CD"},
+		},
+		"no keywords": {
+			video: Video{Title: "Argo CD Explained", Description: "Argo CD"},
+		},
+		"description cut at 150 characters": {
+			video:         Video{TargetKeywords: "argo cd", Title: "Argo CD", Description: strings.Repeat("Ä", 148) + "argo cd"},
+			expectedTitle: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := isKeywordInTitle(tc.video); actual != tc.expectedTitle {
+				t.Errorf("Expected the keyword in the title to be %t\nGot: %t", tc.expectedTitle, actual)
+			}
+			if actual := isKeywordInDescriptionPrefix(tc.video); actual != tc.expectedDescription {
+				t.Errorf("Expected the keyword in the description to be %t\nGot: %t", tc.expectedDescription, actual)
+			}
+		})
+	}
+}
@@ -1,10 +1,169 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/atotto/clipboard"
 )
 
+const slackAPIURL = "https://slack.com/api"
+
+var slackMarkdownLinkRegex = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+
 func postSlack(videoId string) {
 	clipboard.WriteAll(getYouTubeURL(videoId))
-	println(confirmationStyle.Render("The video URL has been copied to clipboard. Please paste it into Slack manually."))
+	output.Result("The video URL has been copied to clipboard. Please paste it into Slack manually.")
+}
+
+// Slack posts messages with a bot token that has the chat:write scope. The bot has to be a member of the channels.
+type Slack struct {
+	client   *http.Client
+	settings SettingsSlack
+	url      string
+}
+
+func NewSlack(settings SettingsSlack) *Slack {
+	return &Slack{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		settings: settings,
+		url:      slackAPIURL,
+	}
+}
+
+// isSlackEnabled is false when there are no channels, in which case the URL is copied to the clipboard to be posted manually.
+func isSlackEnabled(settings SettingsSlack) bool {
+	return len(settings.Channels) > 0
+}
+
+// getSlackText escapes the characters Slack uses for links and mentions and converts Markdown links into Slack links.
+func getSlackText(text string) string {
+	text = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+	return slackMarkdownLinkRegex.ReplaceAllString(text, "<$2|$1>")
+}
+
+// getSlackChapters returns the reply with the timecodes. It's empty if there are no timecodes or they are not final yet.
+func getSlackChapters(timecodes string) (string, bool) {
+	if len(strings.TrimSpace(timecodes)) == 0 {
+		return "", true
+	}
+	if strings.Contains(timecodes, "FIXME:") || strings.Contains(timecodes, "TODO:") {
+		return "", false
+	}
+	return fmt.Sprintf("*Chapters*\n%s", getSlackText(strings.TrimSpace(timecodes))), true
+}
+
+func (s *Slack) do(ctx context.Context, method string, body any, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/"+method, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+s.settings.Token)
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("Slack responded with %s", response.Status)
+	}
+	raw := json.RawMessage{}
+	if err := json.NewDecoder(response.Body).Decode(&raw); err != nil {
+		return err
+	}
+	// Slack responds with 200 even when the call failed.
+	status := struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}{}
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return err
+	}
+	if !status.OK {
+		return fmt.Errorf("Slack %s failed: %s", method, status.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// VerifyCredentials checks that Slack accepts the token.
+func (s *Slack) VerifyCredentials(ctx context.Context) error {
+	return s.do(ctx, "auth.test", struct{}{}, nil)
+}
+
+// PostMessage posts the text to the channel or, if threadTS is set, replies to that message. It returns the timestamp of the posted message.
+func (s *Slack) PostMessage(ctx context.Context, channel, text, threadTS string) (string, error) {
+	message := struct {
+		Channel  string `json:"channel"`
+		Text     string `json:"text"`
+		ThreadTS string `json:"thread_ts,omitempty"`
+	}{Channel: channel, Text: text, ThreadTS: threadTS}
+	posted := struct {
+		TS string `json:"ts"`
+	}{}
+	if err := s.do(ctx, "chat.postMessage", message, &posted); err != nil {
+		return "", err
+	}
+	return posted.TS, nil
+}
+
+// PostVideo posts the title and the link of the video to each channel it was not posted to yet and, in channels with threads enabled,
+// replies with the description and the chapters. The timestamps of the posts are stored in video.SlackThreadTS keyed by the channels
+// once their threads are complete so that a re-run skips them. It stops at the first channel that fails, keeping the timestamps of those
+// that succeeded. It returns the number of channels the video was posted to.
+func (s *Slack) PostVideo(ctx context.Context, video *Video) (int, error) {
+	posted := 0
+	for _, channel := range s.settings.Channels {
+		if len(video.SlackThreadTS[channel.ID]) > 0 {
+			output.Info(fmt.Sprintf("The video was already posted to Slack channel %s.", channel.ID))
+			continue
+		}
+		ts, err := s.postThread(ctx, channel, *video)
+		if err != nil {
+			return posted, fmt.Errorf("channel %s: %w", channel.ID, err)
+		}
+		if video.SlackThreadTS == nil {
+			video.SlackThreadTS = map[string]string{}
+		}
+		video.SlackThreadTS[channel.ID] = ts
+		posted++
+	}
+	return posted, nil
+}
+
+// postThread posts the video to the channel and, if threads are enabled, replies with the description and the chapters.
+// It returns the timestamp of the post.
+func (s *Slack) postThread(ctx context.Context, channel SettingsSlackChannel, video Video) (string, error) {
+	ts, err := s.PostMessage(ctx, channel.ID, fmt.Sprintf("*%s*\n%s", getSlackText(video.Title), getYouTubeURL(video.VideoId)), "")
+	if err != nil || !channel.Thread {
+		return ts, err
+	}
+	if len(strings.TrimSpace(video.Description)) > 0 {
+		if _, err := s.PostMessage(ctx, channel.ID, getSlackText(video.Description), ts); err != nil {
+			return "", err
+		}
+	}
+	chapters, ok := getSlackChapters(video.Timecodes)
+	if !ok {
+		output.Warn(fmt.Sprintf("The chapters were not posted to Slack channel %s since the timecodes are not final.", channel.ID))
+	}
+	if len(chapters) > 0 {
+		if _, err := s.PostMessage(ctx, channel.ID, chapters, ts); err != nil {
+			return "", err
+		}
+	}
+	return ts, nil
 }
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type slackTestMessage struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+// startFakeSlackServer records the messages that were posted and responds with sequential timestamps. It responds with ok:false if failWith is set,
+// only to replies if failReplies is set.
+func startFakeSlackServer(t *testing.T, failWith string, failReplies bool, posted *[]slackTestMessage) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" || r.Header.Get("Authorization") != "Bearer token" {
+			fmt.Fprint(w, `{"ok": false, "error": "invalid_auth"}`)
+			return
+		}
+		message := slackTestMessage{}
+		json.NewDecoder(r.Body).Decode(&message)
+		if len(failWith) > 0 && (!failReplies || len(message.ThreadTS) > 0) {
+			fmt.Fprintf(w, `{"ok": false, "error": %q}`, failWith)
+			return
+		}
+		*posted = append(*posted, message)
+		fmt.Fprintf(w, `{"ok": true, "ts": "1700000000.%06d"}`, len(*posted))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestSlack(url string, channels ...SettingsSlackChannel) *Slack {
+	slack := NewSlack(SettingsSlack{Token: "token", Channels: channels})
+	slack.url = url
+	return slack
+}
+
+func TestSlack_PostVideo(t *testing.T) {
+	tests := map[string]struct {
+		timecodes string
+		posted    map[string]string
+		expected  []slackTestMessage
+	}{
+		"threads": {
+			timecodes: "00:00 Intro\n01:00 [Demo](https://example.com)",
+			expected: []slackTestMessage{
+				{Channel: "C1", Text: "*Title &amp; more*\nhttps://youtu.be/123"},
+				{Channel: "C1", Text: "Description", ThreadTS: "1700000000.000001"},
+				{Channel: "C1", Text: "*Chapters*\n00:00 Intro\n01:00 <https://example.com|Demo>", ThreadTS: "1700000000.000001"},
+				{Channel: "C2", Text: "*Title &amp; more*\nhttps://youtu.be/123"},
+			},
+		},
+		"timecodes not final": {
+			timecodes: "FIXME: add timecodes",
+			expected: []slackTestMessage{
+				{Channel: "C1", Text: "*Title &amp; more*\nhttps://youtu.be/123"},
+				{Channel: "C1", Text: "Description", ThreadTS: "1700000000.000001"},
+				{Channel: "C2", Text: "*Title &amp; more*\nhttps://youtu.be/123"},
+			},
+		},
+		"already posted": {
+			posted: map[string]string{"C1": "1600000000.000001"},
+			expected: []slackTestMessage{
+				{Channel: "C2", Text: "*Title &amp; more*\nhttps://youtu.be/123"},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			posted := []slackTestMessage{}
+			server := startFakeSlackServer(t, "", false, &posted)
+			slack := newTestSlack(server.URL, SettingsSlackChannel{ID: "C1", Thread: true}, SettingsSlackChannel{ID: "C2"})
+			video := Video{Title: "Title & more", VideoId: "123", Description: "Description", Timecodes: test.timecodes, SlackThreadTS: test.posted}
+			expected := 2 - len(test.posted)
+			count, err := slack.PostVideo(context.Background(), &video)
+			if err != nil {
+				t.Fatalf("Error occurred while posting: %v", err)
+			}
+			if count != expected {
+				t.Errorf("Expected: posted to %d channels\nGot: %d", expected, count)
+			}
+			if !reflect.DeepEqual(posted, test.expected) {
+				t.Errorf("Expected: %+v\nGot: %+v", test.expected, posted)
+			}
+			if len(video.SlackThreadTS["C1"]) == 0 || len(video.SlackThreadTS["C2"]) == 0 {
+				t.Errorf("Expected: the timestamps of both channels\nGot: %v", video.SlackThreadTS)
+			}
+		})
+	}
+}
+
+func TestSlack_PostVideoRerun(t *testing.T) {
+	posted := []slackTestMessage{}
+	server := startFakeSlackServer(t, "", false, &posted)
+	slack := newTestSlack(server.URL, SettingsSlackChannel{ID: "C1", Thread: true})
+	video := Video{Title: "Title", VideoId: "123", Description: "Description"}
+	for i := 0; i < 2; i++ {
+		if _, err := slack.PostVideo(context.Background(), &video); err != nil {
+			t.Fatalf("Error occurred while posting: %v", err)
+		}
+	}
+	if len(posted) != 2 {
+		t.Errorf("Expected: the post and the description reply only once\nGot: %+v", posted)
+	}
+}
+
+func TestSlack_PostVideoError(t *testing.T) {
+	posted := []slackTestMessage{}
+	server := startFakeSlackServer(t, "channel_not_found", false, &posted)
+	slack := newTestSlack(server.URL, SettingsSlackChannel{ID: "C1"})
+	video := Video{Title: "Title", VideoId: "123"}
+	_, err := slack.PostVideo(context.Background(), &video)
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("Expected: the error returned by Slack\nGot: %v", err)
+	}
+	if len(video.SlackThreadTS) > 0 {
+		t.Errorf("Expected: no timestamps\nGot: %v", video.SlackThreadTS)
+	}
+}
+
+func TestSlack_PostVideoReplyError(t *testing.T) {
+	posted := []slackTestMessage{}
+	server := startFakeSlackServer(t, "msg_too_long", true, &posted)
+	slack := newTestSlack(server.URL, SettingsSlackChannel{ID: "C1", Thread: true})
+	video := Video{Title: "Title", VideoId: "123", Description: "Description"}
+	count, err := slack.PostVideo(context.Background(), &video)
+	if err == nil || !strings.Contains(err.Error(), "msg_too_long") || count != 0 {
+		t.Errorf("Expected: the error returned by Slack and no channels\nGot: %v and %d channels", err, count)
+	}
+	if len(video.SlackThreadTS) > 0 {
+		t.Errorf("Expected: no timestamps since the thread is not complete\nGot: %v", video.SlackThreadTS)
+	}
+}
+
+func TestSlack_getSlackChapters(t *testing.T) {
+	tests := map[string]struct {
+		timecodes string
+		expected  string
+		ok        bool
+	}{
+		"empty": {timecodes: " ", ok: true},
+		"fixme": {timecodes: "FIXME: later"},
+		"final": {timecodes: "00:00 Intro <start>\n", expected: "*Chapters*\n00:00 Intro &lt;start&gt;", ok: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := getSlackChapters(test.timecodes)
+			if actual != test.expected || ok != test.ok {
+				t.Errorf("Expected: %q %t\nGot: %q %t", test.expected, test.ok, actual, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/utils"
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+	"google.golang.org/api/youtube/v3"
+)
+
+type UploadedSnapshot = workflow.UploadedSnapshot
+
+type SnapshotDifference struct {
+	Field    string
+	Uploaded string
+	Current  string
+}
+
+func getUploadedSnapshot(video Video, now time.Time) UploadedSnapshot {
+	snippet := getUploadRequest(video, UploadStatus{}).Snippet
+	return UploadedSnapshot{
+		Title:       snippet.Title,
+		Description: snippet.Description,
+		Tags:        snippet.Tags,
+		CategoryID:  snippet.CategoryId,
+		Language:    snippet.DefaultLanguage,
+		UploadedAt:  now.UTC().Format(time.RFC3339),
+	}
+}
+
+func hasUploadedSnapshot(video Video) bool {
+	return len(video.UploadedSnapshot.UploadedAt) > 0
+}
+
+// getSnapshotDifferences compares what was uploaded with what would be uploaded from the current values of the video.
+func getSnapshotDifferences(video Video) []SnapshotDifference {
+	uploaded := video.UploadedSnapshot
+	current := getUploadedSnapshot(video, time.Time{})
+	differences := []SnapshotDifference{}
+	fields := []SnapshotDifference{
+		{"Title", uploaded.Title, current.Title},
+		{"Description", uploaded.Description, current.Description},
+		{"Tags", strings.Join(uploaded.Tags, ","), strings.Join(current.Tags, ",")},
+		{"Category", uploaded.CategoryID, current.CategoryID},
+		{"Language", uploaded.Language, current.Language},
+	}
+	for _, field := range fields {
+		if field.Uploaded != field.Current {
+			differences = append(differences, field)
+		}
+	}
+	return differences
+}
+
+func getSnapshotDifferencesText(differences []SnapshotDifference) string {
+	if len(differences) == 0 {
+		return "The current values match what was uploaded."
+	}
+	var builder strings.Builder
+	for _, difference := range differences {
+		builder.WriteString(fmt.Sprintf("%s:\n%s\n\n", difference.Field, renderWordDiff(utils.GetWordDiff(difference.Uploaded, difference.Current))))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// getMetadataUpdateRequest contains only the snippet so that the status (visibility, schedule) set during the upload is left untouched.
+func getMetadataUpdateRequest(video Video) *youtube.Video {
+	snippet := getUploadRequest(video, UploadStatus{}).Snippet
+	snippet.ChannelId = ""
+	return &youtube.Video{Id: video.VideoId, Snippet: snippet}
+}
+
+// pushUploadedMetadata updates the video on YouTube with the current values and returns the new snapshot.
+func pushUploadedMetadata(service *youtube.Service, video Video, now time.Time) (UploadedSnapshot, error) {
+	if len(video.VideoId) == 0 {
+		return UploadedSnapshot{}, fmt.Errorf("the video was not uploaded yet")
+	}
+	if _, err := service.Videos.Update([]string{"snippet"}, getMetadataUpdateRequest(video)).Do(); err != nil {
+		return UploadedSnapshot{}, fmt.Errorf("Error updating the video on YouTube: %w", err)
+	}
+	return getUploadedSnapshot(video, now), nil
+}
+
+func updateUploadedMetadata(video Video) (UploadedSnapshot, error) {
+	service, err := youtube.New(getClient())
+	if err != nil {
+		return UploadedSnapshot{}, fmt.Errorf("Error creating YouTube client: %v", err)
+	}
+	return pushUploadedMetadata(service, video, time.Now())
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestSnapshot_publishVideoCreatesSnapshot(t *testing.T) {
+	video := Video{Title: "Something", Description: "About something", Tags: "a,b", UploadVideo: "video.mp4"}
+	if result := publishVideo(&video, fakePublisher{dir: t.TempDir()}, false, true); result.Err != nil {
+		t.Fatalf("Expected the upload to succeed, but got %v", result.Err)
+	}
+	snapshot := video.UploadedSnapshot
+	if snapshot.Title != "Something" || !strings.Contains(snapshot.Description, "About something") || strings.Join(snapshot.Tags, ",") != "a,b" {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+	if snapshot.CategoryID != "28" || len(snapshot.Language) > 0 || len(snapshot.UploadedAt) == 0 {
+		t.Errorf("Expected the category and the upload time to be set and the language of the channel to be used, but got %+v", snapshot)
+	}
+}
+
+func TestSnapshot_publishVideoFailedUpload(t *testing.T) {
+	video := Video{Title: "Something", UploadVideo: "video.mp4"}
+	publishVideo(&video, fakePublisher{dir: t.TempDir(), failUpload: true}, false, true)
+	if hasUploadedSnapshot(video) {
+		t.Errorf("Expected no snapshot after a failed upload, but got %+v", video.UploadedSnapshot)
+	}
+}
+
+func TestSnapshot_getSnapshotDifferences(t *testing.T) {
+	video := Video{Title: "Something", Description: "About something", Tags: "a,b"}
+	video.UploadedSnapshot = getUploadedSnapshot(video, time.Now())
+	if differences := getSnapshotDifferences(video); len(differences) != 0 {
+		t.Errorf("Expected no differences, but got %v", differences)
+	}
+	video.Title = "Something else"
+	video.Tags = "a,c"
+	differences := getSnapshotDifferences(video)
+	if len(differences) != 2 {
+		t.Fatalf("Expected 2 differences, but got %v", differences)
+	}
+	expected := []SnapshotDifference{{"Title", "Something", "Something else"}, {"Tags", "a,b", "a,c"}}
+	for i := range expected {
+		if differences[i] != expected[i] {
+			t.Errorf("Expected: %v\nGot: %v", expected[i], differences[i])
+		}
+	}
+	if video.UploadedSnapshot.Title != "Something" {
+		t.Errorf("Expected the snapshot to stay unchanged, but got %s", video.UploadedSnapshot.Title)
+	}
+}
+
+func TestSnapshot_pushUploadedMetadata(t *testing.T) {
+	settingsOrig := settings
+	defer func() { settings = settingsOrig }()
+	settings.Upload.Language = "en"
+	var method, part string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		part = r.URL.Query().Get("part")
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+		w.Write(data)
+	}))
+	defer server.Close()
+	service, err := youtube.New(server.Client())
+	if err != nil {
+		t.Fatalf("Error occurred while creating the service: %v", err)
+	}
+	service.BasePath = server.URL + "/"
+	video := Video{VideoId: "abc", Title: "Something else", Description: "About something", Tags: "a,b"}
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	snapshot, err := pushUploadedMetadata(service, video, now)
+	if err != nil {
+		t.Fatalf("Expected the update to succeed, but got %v", err)
+	}
+	if method != http.MethodPut || part != "snippet" {
+		t.Errorf("Expected PUT with the snippet part, but got %s with %s", method, part)
+	}
+	if body["id"] != "abc" || body["status"] != nil {
+		t.Errorf("Expected the request to contain the video ID and no status, but got %v", body)
+	}
+	snippet, _ := body["snippet"].(map[string]interface{})
+	if snippet["title"] != "Something else" || snippet["categoryId"] != "28" || snippet["defaultLanguage"] != "en" {
+		t.Errorf("Unexpected snippet %v", snippet)
+	}
+	if snapshot.Title != "Something else" || snapshot.UploadedAt != "2030-01-21T16:00:00Z" {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+}
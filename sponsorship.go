@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type BlockedSponsorship struct {
+	Video Video
+	Days  int
+}
+
+func isSponsorshipBlocked(sponsorship Sponsorship) bool {
+	return len(sponsorship.Blocked) > 0 && sponsorship.Blocked != "-" && sponsorship.Blocked != "N/A"
+}
+
+// updateBlockedSince records when the sponsorship became blocked and forgets it, together with the nudges, once it is unblocked.
+func updateBlockedSince(sponsorship *Sponsorship, now time.Time) {
+	if !isSponsorshipBlocked(*sponsorship) {
+		sponsorship.BlockedSince = ""
+		sponsorship.LastNudged = ""
+		return
+	}
+	if !isBlockedSinceKnown(*sponsorship) {
+		sponsorship.BlockedSince = now.Format(dayFormat)
+	}
+}
+
+// backfillBlockedSince sets since when the sponsorship is blocked for videos that were blocked before it was tracked. The file was written
+// after the sponsorship was blocked so its modification time makes the blocked duration shorter rather than unknown.
+func backfillBlockedSince(sponsorship *Sponsorship, modified time.Time) {
+	if isSponsorshipBlocked(*sponsorship) && !isBlockedSinceKnown(*sponsorship) {
+		sponsorship.BlockedSince = modified.Format(dayFormat)
+	}
+}
+
+func isBlockedSinceKnown(sponsorship Sponsorship) bool {
+	since, err := parseDate(sponsorship.BlockedSince)
+	return err == nil && !since.IsZero()
+}
+
+// getBlockedDays returns -1 when it is not known since when the sponsorship is blocked.
+func getBlockedDays(sponsorship Sponsorship, now time.Time) int {
+	since, err := parseDate(sponsorship.BlockedSince)
+	if err != nil {
+		return -1
+	}
+	return int(now.Sub(since).Hours() / 24)
+}
+
+func getBlockedTitle(sponsorship Sponsorship, now time.Time) string {
+	days := getBlockedDays(sponsorship, now)
+	if days < 0 {
+		return fmt.Sprintf("Blocked: %s", sponsorship.Blocked)
+	}
+	return fmt.Sprintf("Blocked %dd: %s", days, sponsorship.Blocked)
+}
+
+// getOverdueSponsorships returns videos blocked for at least the threshold, longest blocked first.
+func getOverdueSponsorships(videos []Video, now time.Time, thresholdDays int) []BlockedSponsorship {
+	overdue := []BlockedSponsorship{}
+	for _, video := range videos {
+		if !isSponsorshipBlocked(video.Sponsorship) {
+			continue
+		}
+		if days := getBlockedDays(video.Sponsorship, now); days >= thresholdDays {
+			overdue = append(overdue, BlockedSponsorship{Video: video, Days: days})
+		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].Days > overdue[j].Days
+	})
+	return overdue
+}
+
+func getOverdueSponsorshipsWarning(overdue []BlockedSponsorship, thresholdDays int) string {
+	if len(overdue) == 0 {
+		return ""
+	}
+	videos := []string{}
+	for _, blocked := range overdue {
+		videos = append(videos, fmt.Sprintf("%s (%dd: %s)", blocked.Video.Name, blocked.Days, blocked.Video.Sponsorship.Blocked))
+	}
+	return fmt.Sprintf("Sponsored videos blocked for %d days or more: %s", thresholdDays, strings.Join(videos, ", "))
+}
+
+// validateSponsorNudge allows one nudge per threshold period and only after the sponsorship was blocked for the threshold.
+func validateSponsorNudge(sponsorship Sponsorship, now time.Time, thresholdDays int) error {
+	if !isSponsorshipBlocked(sponsorship) {
+		return fmt.Errorf("the sponsorship is not blocked")
+	}
+	if len(strings.TrimSpace(sponsorship.Emails)) == 0 {
+		return fmt.Errorf("there are no sponsorship emails to nudge")
+	}
+	if days := getBlockedDays(sponsorship, now); days < thresholdDays {
+		return fmt.Errorf("the sponsorship is blocked for %d days, sponsors are nudged after %d days", days, thresholdDays)
+	}
+	if lastNudged, err := parseDate(sponsorship.LastNudged); err == nil {
+		if next := lastNudged.AddDate(0, 0, thresholdDays); now.Before(next) {
+			return fmt.Errorf("the sponsor was already nudged on %s, the next nudge is possible on %s", sponsorship.LastNudged, next.Format(dayFormat))
+		}
+	}
+	return nil
+}
+
+func (e *Email) SendSponsorNudge(ctx context.Context, from string, video Video) error {
+	subject := fmt.Sprintf("DevOps Toolkit Video Sponsorship: %s", video.Title)
+	if len(video.Title) == 0 {
+		subject = "DevOps Toolkit Video Sponsorship"
+	}
+	body := fmt.Sprintf(`Hi,
+<br><br>
+I wanted to follow up on the sponsored video. It is currently on hold since %s: %s.
+<br><br>
+Please let me know whether there is anything I can do to move it forward.
+`, video.Sponsorship.BlockedSince, video.Sponsorship.Blocked)
+	return e.Send(ctx, from, strings.Split(video.Sponsorship.Emails, ","), subject, body, "")
+}
+
+// validateSponsorAsset checks that the type is in the taxonomy and that a received asset points to a file in the material directory. Relative paths are relative to that directory.
+func validateSponsorAsset(asset SponsorAsset, materialDir string, types []string) error {
+	if !slices.Contains(types, asset.Type) {
+		return fmt.Errorf("asset type %q is not one of %s", asset.Type, strings.Join(types, ", "))
+	}
+	if !asset.Received {
+		return nil
+	}
+	if len(asset.Path) == 0 {
+		return fmt.Errorf("the path of a received asset is required")
+	}
+	path := getSponsorAssetPath(asset, materialDir)
+	relative, err := filepath.Rel(filepath.Clean(materialDir), path)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is not in the material directory %s", asset.Path, materialDir)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("%s does not exist", path)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
+
+func getSponsorAssetPath(asset SponsorAsset, materialDir string) string {
+	if filepath.IsAbs(asset.Path) {
+		return filepath.Clean(asset.Path)
+	}
+	return filepath.Join(materialDir, asset.Path)
+}
+
+func isSponsorLogoAsset(asset SponsorAsset) bool {
+	return strings.HasPrefix(asset.Type, "logo")
+}
+
+func getSponsorAssetTitle(asset SponsorAsset) string {
+	if !asset.Received {
+		return fmt.Sprintf("%s: not received", asset.Type)
+	}
+	return fmt.Sprintf("%s: %s (received %s)", asset.Type, asset.Path, asset.ReceivedDate)
+}
+
+func getReceivedSponsorAssets(assets []SponsorAsset) []SponsorAsset {
+	received := []SponsorAsset{}
+	for _, asset := range assets {
+		if asset.Received {
+			received = append(received, asset)
+		}
+	}
+	return received
+}
+
+// getSponsorAssetWarnings is the pre-upload checklist of the asset pack. Videos that are not sponsored have nothing to check.
+func getSponsorAssetWarnings(video Video, materialDir string, types []string) []string {
+	if !workflow.IsSponsored(video.Sponsorship) {
+		return nil
+	}
+	warnings := []string{}
+	missing := []string{}
+	for _, asset := range video.Sponsorship.Assets {
+		if !asset.Received {
+			missing = append(missing, asset.Type)
+		} else if err := validateSponsorAsset(asset, materialDir, types); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Sponsor asset %s: %s", asset.Type, err))
+		}
+	}
+	if len(missing) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Sponsor assets not received yet: %s", strings.Join(missing, ", ")))
+	}
+	if video.OtherLogos != "" && video.OtherLogos != "-" && video.OtherLogos != "N/A" && !slices.ContainsFunc(getReceivedSponsorAssets(video.Sponsorship.Assets), isSponsorLogoAsset) {
+		warnings = append(warnings, fmt.Sprintf("Other logos (%s) are planned but no logo was received from the sponsor", video.OtherLogos))
+	}
+	return warnings
+}
+
+// getSponsorAssetsEmailItem lists received assets so that the designer or the editor knows what's available. It's empty when nothing was received.
+func getSponsorAssetsEmailItem(video Video) string {
+	if !workflow.IsSponsored(video.Sponsorship) {
+		return ""
+	}
+	items := []string{}
+	for _, asset := range getReceivedSponsorAssets(video.Sponsorship.Assets) {
+		items = append(items, fmt.Sprintf("%s (%s)", asset.Type, asset.Path))
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("<li>Sponsor assets: %s</li>\n", strings.Join(items, ", "))
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSponsorship_updateBlockedSince(t *testing.T) {
+	now := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	sponsorship := Sponsorship{Blocked: "Waiting on legal"}
+	updateBlockedSince(&sponsorship, now)
+	if sponsorship.BlockedSince != "2030-03-10" {
+		t.Errorf("Expected the block to be recorded on 2030-03-10, but got %q", sponsorship.BlockedSince)
+	}
+	updateBlockedSince(&sponsorship, now.AddDate(0, 0, 5))
+	if sponsorship.BlockedSince != "2030-03-10" {
+		t.Errorf("Expected the original block date to be kept, but got %q", sponsorship.BlockedSince)
+	}
+	sponsorship.Blocked = ""
+	sponsorship.LastNudged = "2030-03-12"
+	updateBlockedSince(&sponsorship, now)
+	if len(sponsorship.BlockedSince) > 0 || len(sponsorship.LastNudged) > 0 {
+		t.Errorf("Expected the block history to be cleared, but got %+v", sponsorship)
+	}
+	sponsorship.Blocked = "N/A"
+	updateBlockedSince(&sponsorship, now)
+	if len(sponsorship.BlockedSince) > 0 {
+		t.Errorf("Expected N/A not to be treated as blocked, but got %+v", sponsorship)
+	}
+}
+
+func TestSponsorship_backfillBlockedSince(t *testing.T) {
+	modified := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		sponsorship Sponsorship
+		expected    string
+	}{
+		"not tracked": {sponsorship: Sponsorship{Blocked: "Waiting on legal"}, expected: "2030-03-10"},
+		"zero":        {sponsorship: Sponsorship{Blocked: "Waiting on legal", BlockedSince: "0001-01-01"}, expected: "2030-03-10"},
+		"tracked":     {sponsorship: Sponsorship{Blocked: "Waiting on legal", BlockedSince: "2030-01-22"}, expected: "2030-01-22"},
+		"not blocked": {sponsorship: Sponsorship{Blocked: "N/A"}},
+		"unblocked":   {sponsorship: Sponsorship{}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			backfillBlockedSince(&test.sponsorship, modified)
+			if test.sponsorship.BlockedSince != test.expected {
+				t.Errorf("Expected: %q\nGot: %q", test.expected, test.sponsorship.BlockedSince)
+			}
+		})
+	}
+}
+
+func TestSponsorship_readVideoBackfillsBlockedSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	if err := os.WriteFile(path, []byte("name: my-video\nsponsorship:\n  amount: \"1000\"\n  blocked: Waiting on legal\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	modified := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatalf("Error occurred while changing the times of %s: %v", path, err)
+	}
+	video, err := readVideo(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	if video.Sponsorship.BlockedSince != "2030-03-10" {
+		t.Errorf("Expected: 2030-03-10\nGot: %q", video.Sponsorship.BlockedSince)
+	}
+}
+
+func TestSponsorship_getBlockedTitle(t *testing.T) {
+	now := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		sponsorship Sponsorship
+		expected    string
+	}{
+		{Sponsorship{Blocked: "waiting on legal", BlockedSince: "2030-01-22"}, "Blocked 47d: waiting on legal"},
+		{Sponsorship{Blocked: "waiting on legal", BlockedSince: "2030-03-10"}, "Blocked 0d: waiting on legal"},
+		{Sponsorship{Blocked: "waiting on legal"}, "Blocked: waiting on legal"},
+	}
+	for _, test := range tests {
+		if actual := getBlockedTitle(test.sponsorship, now); actual != test.expected {
+			t.Errorf("Expected: %s\nGot: %s", test.expected, actual)
+		}
+	}
+}
+
+func TestSponsorship_getOverdueSponsorships(t *testing.T) {
+	now := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	videos := []Video{
+		{Name: "recent", Sponsorship: Sponsorship{Blocked: "contract", BlockedSince: "2030-03-01"}},
+		{Name: "old", Sponsorship: Sponsorship{Blocked: "legal", BlockedSince: "2030-01-22"}},
+		{Name: "threshold", Sponsorship: Sponsorship{Blocked: "budget", BlockedSince: "2030-02-24"}},
+		{Name: "unblocked", Sponsorship: Sponsorship{BlockedSince: "2029-01-01"}},
+		{Name: "unknown", Sponsorship: Sponsorship{Blocked: "legal"}},
+	}
+	overdue := getOverdueSponsorships(videos, now, 14)
+	names := []string{}
+	for _, blocked := range overdue {
+		names = append(names, blocked.Video.Name)
+	}
+	if strings.Join(names, ",") != "old,threshold" {
+		t.Errorf("Expected old and threshold to be overdue, but got %v", names)
+	}
+	expected := "Sponsored videos blocked for 14 days or more: old (47d: legal), threshold (14d: budget)"
+	if actual := getOverdueSponsorshipsWarning(overdue, 14); actual != expected {
+		t.Errorf("Expected: %s\nGot: %s", expected, actual)
+	}
+	if actual := getOverdueSponsorshipsWarning(nil, 14); actual != "" {
+		t.Errorf("Expected no warning, but got %s", actual)
+	}
+}
+
+func TestSponsorship_validateSponsorNudge(t *testing.T) {
+	now := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	blocked := Sponsorship{Emails: "sponsor@example.com", Blocked: "legal", BlockedSince: "2030-01-22"}
+	tests := []struct {
+		name        string
+		lastNudged  string
+		blockedFrom string
+		expectError bool
+	}{
+		{"never nudged", "", "2030-01-22", false},
+		{"nudged within the period", "2030-03-01", "2030-01-22", true},
+		{"nudged a period ago", "2030-02-24", "2030-01-22", false},
+		{"blocked recently", "", "2030-03-05", true},
+	}
+	for _, test := range tests {
+		sponsorship := blocked
+		sponsorship.LastNudged = test.lastNudged
+		sponsorship.BlockedSince = test.blockedFrom
+		if err := validateSponsorNudge(sponsorship, now, 14); (err != nil) != test.expectError {
+			t.Errorf("%s: expected error=%t, but got %v", test.name, test.expectError, err)
+		}
+	}
+	if err := validateSponsorNudge(Sponsorship{Emails: "sponsor@example.com"}, now, 14); err == nil {
+		t.Errorf("Expected unblocked sponsorships not to be nudged")
+	}
+	if err := validateSponsorNudge(Sponsorship{Blocked: "legal", BlockedSince: "2030-01-22"}, now, 14); err == nil {
+		t.Errorf("Expected sponsorships without emails not to be nudged")
+	}
+}
+
+func TestSponsorship_validateSponsorAsset(t *testing.T) {
+	dir := t.TempDir()
+	materialDir := filepath.Join(dir, "material")
+	if err := os.MkdirAll(filepath.Join(materialDir, "sponsor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(materialDir, "sponsor", "logo.svg"), filepath.Join(dir, "outside.svg")} {
+		if err := os.WriteFile(path, []byte("<svg/>"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	types := []string{"logo-svg", "approved-copy"}
+	tests := []struct {
+		name        string
+		asset       SponsorAsset
+		expectError bool
+	}{
+		{"relative path", SponsorAsset{Type: "logo-svg", Received: true, Path: "sponsor/logo.svg"}, false},
+		{"absolute path", SponsorAsset{Type: "logo-svg", Received: true, Path: filepath.Join(materialDir, "sponsor", "logo.svg")}, false},
+		{"not received without a path", SponsorAsset{Type: "approved-copy"}, false},
+		{"unknown type", SponsorAsset{Type: "logo-gif"}, true},
+		{"received without a path", SponsorAsset{Type: "logo-svg", Received: true}, true},
+		{"missing file", SponsorAsset{Type: "logo-svg", Received: true, Path: "sponsor/logo.png"}, true},
+		{"directory", SponsorAsset{Type: "logo-svg", Received: true, Path: "sponsor"}, true},
+		{"outside of the material directory", SponsorAsset{Type: "logo-svg", Received: true, Path: "../outside.svg"}, true},
+		{"absolute path outside of the material directory", SponsorAsset{Type: "logo-svg", Received: true, Path: filepath.Join(dir, "outside.svg")}, true},
+	}
+	for _, test := range tests {
+		if err := validateSponsorAsset(test.asset, materialDir, types); (err != nil) != test.expectError {
+			t.Errorf("%s: expected error=%t, but got %v", test.name, test.expectError, err)
+		}
+	}
+}
+
+func TestSponsorship_getSponsorAssetWarnings(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "guidelines.pdf"), []byte("pdf"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	types := []string{"logo-svg", "brand-guidelines", "approved-copy"}
+	video := Video{
+		OtherLogos: "sponsor",
+		Sponsorship: Sponsorship{Amount: "1000", Assets: []SponsorAsset{
+			{Type: "logo-svg"},
+			{Type: "brand-guidelines", Received: true, Path: "guidelines.pdf"},
+			{Type: "approved-copy", Received: true, Path: "copy.txt"},
+		}},
+	}
+	expected := []string{
+		"Sponsor asset approved-copy: " + filepath.Join(dir, "copy.txt") + " does not exist",
+		"Sponsor assets not received yet: logo-svg",
+		"Other logos (sponsor) are planned but no logo was received from the sponsor",
+	}
+	if actual := getSponsorAssetWarnings(video, dir, types); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+	video.Sponsorship.Amount = "N/A"
+	if actual := getSponsorAssetWarnings(video, dir, types); len(actual) > 0 {
+		t.Errorf("Expected no warnings for videos that are not sponsored, but got %v", actual)
+	}
+}
+
+func TestSponsorship_getSponsorAssetsEmailItem(t *testing.T) {
+	video := Video{
+		Title:      "Something",
+		ProjectURL: "https://example.com",
+		Gist:       "manuscript/something.md",
+		Sponsorship: Sponsorship{Amount: "1000", Assets: []SponsorAsset{
+			{Type: "logo-svg", Received: true, Path: "sponsor/logo.svg"},
+			{Type: "brand-guidelines", Received: true, Path: "sponsor/guidelines.pdf"},
+			{Type: "approved-copy"},
+		}},
+	}
+	expected := "<li>Sponsor assets: logo-svg (sponsor/logo.svg), brand-guidelines (sponsor/guidelines.pdf)</li>"
+	for name, body := range map[string]string{"thumbnail": getThumbnailEmailBody(video), "edit": getEditEmailBody(video)} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the %s email to contain %q, but got:\n%s", name, expected, body)
+		}
+		if strings.Contains(body, "approved-copy") {
+			t.Errorf("Expected the %s email not to contain assets that were not received, but got:\n%s", name, body)
+		}
+	}
+	video.Sponsorship.Amount = "N/A"
+	if body := getEditEmailBody(video); strings.Contains(body, "Sponsor assets") {
+		t.Errorf("Expected no sponsor assets for videos that are not sponsored, but got:\n%s", body)
+	}
+}
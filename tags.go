@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+func splitTags(tags string) []string {
+	output := []string{}
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) > 0 {
+			output = append(output, tag)
+		}
+	}
+	return output
+}
+
+func sortTagCounts(counts map[string]int) []TagCount {
+	tagCounts := []TagCount{}
+	for tag, count := range counts {
+		tagCounts = append(tagCounts, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tagCounts, func(i, j int) bool {
+		if tagCounts[i].Count != tagCounts[j].Count {
+			return tagCounts[i].Count > tagCounts[j].Count
+		}
+		return tagCounts[i].Tag < tagCounts[j].Tag
+	})
+	return tagCounts
+}
+
+// getTagCounts aggregates tags across videos, most used first. Tags are compared case-insensitively.
+func getTagCounts(videos []Video) []TagCount {
+	counts := map[string]int{}
+	for _, video := range videos {
+		for _, tag := range splitTags(video.Tags) {
+			counts[strings.ToLower(tag)]++
+		}
+	}
+	return sortTagCounts(counts)
+}
+
+// normalizeTags replaces aliases with their canonical form and removes duplicates.
+func normalizeTags(tags string, aliases map[string]string) string {
+	output := []string{}
+	seen := map[string]bool{}
+	for _, tag := range splitTags(tags) {
+		if canonical, ok := getCanonicalTag(tag, aliases); ok {
+			tag = canonical
+		}
+		if seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		output = append(output, tag)
+	}
+	return strings.Join(output, ",")
+}
+
+func getTagAliasWarnings(tags string, aliases map[string]string) []string {
+	warnings := []string{}
+	for _, tag := range splitTags(tags) {
+		if canonical, ok := getCanonicalTag(tag, aliases); ok {
+			warnings = append(warnings, fmt.Sprintf("%s should be %s", tag, canonical))
+		}
+	}
+	return warnings
+}
+
+func getCanonicalTag(tag string, aliases map[string]string) (string, bool) {
+	for alias, canonical := range aliases {
+		if strings.EqualFold(alias, tag) {
+			return canonical, true
+		}
+	}
+	return "", false
+}
+
+// suggestTags returns the most common tags of other videos in the same category that the video does not have yet.
+func suggestTags(video Video, videos []Video, count int) []string {
+	existing := map[string]bool{}
+	for _, tag := range splitTags(video.Tags) {
+		existing[strings.ToLower(tag)] = true
+	}
+	counts := map[string]int{}
+	for _, other := range videos {
+		if other.Category != video.Category || other.Path == video.Path {
+			continue
+		}
+		for _, tag := range splitTags(other.Tags) {
+			if !existing[strings.ToLower(tag)] {
+				counts[strings.ToLower(tag)]++
+			}
+		}
+	}
+	suggestions := []string{}
+	for _, tagCount := range sortTagCounts(counts) {
+		if len(suggestions) == count {
+			break
+		}
+		suggestions = append(suggestions, tagCount.Tag)
+	}
+	return suggestions
+}
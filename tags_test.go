@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTags_getTagCounts(t *testing.T) {
+	videos := []Video{
+		{Tags: "kubernetes, GitOps,argo cd"},
+		{Tags: "Kubernetes,gitops"},
+		{Tags: "kubernetes,,crossplane"},
+		{},
+	}
+	expected := []TagCount{{"kubernetes", 3}, {"gitops", 2}, {"argo cd", 1}, {"crossplane", 1}}
+	if actual := getTagCounts(videos); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestTags_normalizeTags(t *testing.T) {
+	aliases := map[string]string{"k8s": "kubernetes", "CICD": "ci-cd"}
+	actual := normalizeTags("K8s, cicd,kubernetes, gitops", aliases)
+	if expected := "kubernetes,ci-cd,gitops"; actual != expected {
+		t.Errorf("Expected %q, but got %q", expected, actual)
+	}
+	warnings := getTagAliasWarnings("k8s,gitops", aliases)
+	if expected := []string{"k8s should be kubernetes"}; !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, warnings)
+	}
+}
+
+func TestTags_suggestTags(t *testing.T) {
+	video := Video{Path: "manuscript/gitops/new.yaml", Category: "gitops", Tags: "gitops"}
+	videos := []Video{
+		video,
+		{Path: "manuscript/gitops/01.yaml", Category: "gitops", Tags: "gitops,argo cd,kubernetes"},
+		{Path: "manuscript/gitops/02.yaml", Category: "gitops", Tags: "flux,kubernetes"},
+		{Path: "manuscript/gitops/03.yaml", Category: "gitops", Tags: "Argo CD,kubernetes,helm"},
+		{Path: "manuscript/ai/01.yaml", Category: "ai", Tags: "ai,llm,llm-ops,ai-agents"},
+	}
+	expected := []string{"kubernetes", "argo cd", "flux"}
+	if actual := suggestTags(video, videos, 3); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+const talkStatusPlanned = "planned"
+const talkStatusSubmitted = "submitted"
+const talkStatusAccepted = "accepted"
+const talkStatusRejected = "rejected"
+
+const talkDeadlineWarning = 14 * 24 * time.Hour
+
+type Talk = workflow.Talk
+
+type TalkDeadline struct {
+	Video    Video
+	Talk     Talk
+	Index    int
+	Deadline time.Time
+}
+
+var talkTransitions = map[string][]string{
+	talkStatusPlanned:   {talkStatusSubmitted},
+	talkStatusSubmitted: {talkStatusAccepted, talkStatusRejected},
+	talkStatusRejected:  {talkStatusSubmitted},
+	talkStatusAccepted:  {},
+}
+
+func validateTalkTransition(from, to string) error {
+	if from == "" || from == to {
+		return nil
+	}
+	for _, allowed := range talkTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("talk status cannot change from %s to %s", from, to)
+}
+
+// getUpcomingCFPs returns deadlines of talks that were not yet submitted, soonest first.
+func getUpcomingCFPs(videos []Video, now time.Time) []TalkDeadline {
+	deadlines := []TalkDeadline{}
+	for _, video := range videos {
+		for i, talk := range video.Talks {
+			if talk.Status != talkStatusPlanned && talk.Status != "" {
+				continue
+			}
+			deadline, err := parseDate(talk.CFPDeadline)
+			if err != nil || deadline.Before(now) {
+				continue
+			}
+			deadlines = append(deadlines, TalkDeadline{Video: video, Talk: talk, Index: i, Deadline: deadline})
+		}
+	}
+	sort.SliceStable(deadlines, func(i, j int) bool {
+		return deadlines[i].Deadline.Before(deadlines[j].Deadline)
+	})
+	return deadlines
+}
+
+func getTalkTitle(talk Talk, now time.Time) string {
+	title := fmt.Sprintf("%s (%s)", talk.Conference, talk.Status)
+	if len(talk.CFPDeadline) > 0 {
+		title = fmt.Sprintf("%s (CFP %s)", title, talk.CFPDeadline)
+	}
+	if deadline, err := parseDate(talk.CFPDeadline); err == nil && talk.Status == talkStatusPlanned && deadline.Sub(now) < talkDeadlineWarning {
+		return redStyle.Render(title)
+	}
+	if talk.Status == talkStatusAccepted {
+		return greenStyle.Render(title)
+	}
+	return orangeStyle.Render(title)
+}
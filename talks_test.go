@@ -0,0 +1,79 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTalks_validateTalkTransition(t *testing.T) {
+	transitions := []struct {
+		from  string
+		to    string
+		valid bool
+	}{
+		{"", talkStatusAccepted, true},
+		{talkStatusPlanned, talkStatusPlanned, true},
+		{talkStatusPlanned, talkStatusSubmitted, true},
+		{talkStatusPlanned, talkStatusAccepted, false},
+		{talkStatusSubmitted, talkStatusAccepted, true},
+		{talkStatusSubmitted, talkStatusRejected, true},
+		{talkStatusRejected, talkStatusAccepted, false},
+		{talkStatusRejected, talkStatusSubmitted, true},
+		{talkStatusAccepted, talkStatusRejected, false},
+	}
+	for _, transition := range transitions {
+		err := validateTalkTransition(transition.from, transition.to)
+		if (err == nil) != transition.valid {
+			t.Errorf("Expected %s -> %s to be valid=%t, but got %v", transition.from, transition.to, transition.valid, err)
+		}
+	}
+}
+
+func TestTalks_getUpcomingCFPs(t *testing.T) {
+	now := time.Date(2030, 1, 10, 12, 0, 0, 0, time.UTC)
+	videos := []Video{
+		{Name: "video-01", Talks: []Talk{
+			{Conference: "KubeCon", CFPDeadline: "2030-03-01", Status: talkStatusPlanned},
+			{Conference: "DevOpsDays", CFPDeadline: "2030-01-05", Status: talkStatusPlanned},
+		}},
+		{Name: "video-02", Talks: []Talk{
+			{Conference: "FOSDEM", CFPDeadline: "2030-01-15T18:00", Status: talkStatusPlanned},
+			{Conference: "QCon", CFPDeadline: "2030-01-12", Status: talkStatusSubmitted},
+		}},
+		{Name: "video-03", Talks: []Talk{
+			{Conference: "SREcon", CFPDeadline: "not a date", Status: talkStatusPlanned},
+		}},
+		{Name: "video-04"},
+	}
+	deadlines := getUpcomingCFPs(videos, now)
+	actual := []string{}
+	for _, deadline := range deadlines {
+		actual = append(actual, deadline.Video.Name+"/"+deadline.Talk.Conference)
+	}
+	expected := []string{"video-02/FOSDEM", "video-01/KubeCon"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+	if deadlines[1].Index != 0 {
+		t.Errorf("Expected the talk index 0, but got %d", deadlines[1].Index)
+	}
+}
+
+func TestTalks_yamlRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	video := Video{
+		Name: "something",
+		Talks: []Talk{
+			{Conference: "KubeCon", CFPDeadline: "2030-03-01", Status: talkStatusSubmitted, Date: "2030-06-10"},
+			{Conference: "FOSDEM", Status: talkStatusPlanned},
+		},
+	}
+	yaml := YAML{}
+	yaml.WriteVideo(&video, path)
+	actual := yaml.GetVideo(path)
+	if !reflect.DeepEqual(actual.Talks, video.Talks) {
+		t.Errorf("Expected: %v\nGot: %v", video.Talks, actual.Talks)
+	}
+}
@@ -8,5 +8,5 @@ func postTechnologyConversations(title, description, videoId, gist, projectName,
 	message += fmt.Sprintf("\n\nDescription:\n%s", description)
 	message += fmt.Sprintf("\n\nVideo ID:\n%s", videoId)
 	message += fmt.Sprintf("\n\nAdditional info:\n%s", getAdditionalInfo(gist, projectName, projectURL, relatedVideos))
-	println(confirmationStyle.Render(message))
+	output.Result(message)
 }
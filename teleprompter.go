@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const teleprompterDefaultLineWidth = 50
+
+func exportTeleprompterScript(video Video, lineWidth int, withHTML bool) (string, error) {
+	repo := Repo{}
+	_, sections, err := repo.GetAnimations(video.Gist)
+	if err != nil {
+		return "", err
+	}
+	dir := getMaterialDir(video)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	outro := getOutroWithMembers(video.Outro, getRenderedMembers(video.Members, settings.Members.Exclude))
+	text := getTeleprompterText(video.Intro, outro, sections, lineWidth)
+	path := filepath.Join(dir, "teleprompter.txt")
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return "", err
+	}
+	if withHTML {
+		htmlPath := filepath.Join(dir, "teleprompter.html")
+		if err := os.WriteFile(htmlPath, []byte(getTeleprompterHTML(video.Title, text)), 0644); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+func getTeleprompterText(intro, outro string, sections []string, lineWidth int) string {
+	blocks := []string{}
+	if len(strings.TrimSpace(intro)) > 0 {
+		blocks = append(blocks, wrapText(intro, lineWidth))
+	}
+	for _, section := range sections {
+		section = strings.TrimSpace(strings.TrimPrefix(section, "Section: "))
+		blocks = append(blocks, wrapText(fmt.Sprintf("Next: %s", section), lineWidth))
+	}
+	if len(strings.TrimSpace(outro)) > 0 {
+		blocks = append(blocks, wrapText(outro, lineWidth))
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+func getTeleprompterHTML(title, text string) string {
+	paragraphs := []string{}
+	for _, block := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		lines := strings.Split(block, "\n")
+		for i := range lines {
+			lines[i] = html.EscapeString(lines[i])
+		}
+		paragraphs = append(paragraphs, fmt.Sprintf("<p>%s</p>", strings.Join(lines, "<br/>\n")))
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { background: #000; color: #fff; font-family: sans-serif; font-size: 64px; line-height: 1.4; margin: 5%%; }
+p { margin-bottom: 2em; }
+</style>
+</head>
+<body>
+%s
+</body>
+</html>
+`, html.EscapeString(title), strings.Join(paragraphs, "\n"))
+}
+
+func wrapText(text string, lineWidth int) string {
+	if lineWidth <= 0 {
+		lineWidth = teleprompterDefaultLineWidth
+	}
+	text = strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n")
+	paragraphs := []string{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			continue
+		}
+		lines := []string{}
+		line := words[0]
+		for _, word := range words[1:] {
+			if len([]rune(line))+1+len([]rune(word)) > lineWidth {
+				lines = append(lines, line)
+				line = word
+			} else {
+				line = fmt.Sprintf("%s %s", line, word)
+			}
+		}
+		lines = append(lines, line)
+		paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+	}
+	return strings.Join(paragraphs, "\n")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTeleprompter_wrapText(t *testing.T) {
+	actual := wrapText("Kubernetes is everywhere and nobody can stop it\n\nReally", 20)
+	expected := "Kubernetes is\neverywhere and\nnobody can stop it\nReally"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestTeleprompter_getTeleprompterText(t *testing.T) {
+	actual := getTeleprompterText(
+		"Stop cooking your own platform.",
+		"Subscribe if you liked it.",
+		[]string{"Section: Ephemeral Shells", "Section: Pros and Cons"},
+		80,
+	)
+	expected := `Stop cooking your own platform.
+
+Next: Ephemeral Shells
+
+Next: Pros and Cons
+
+Subscribe if you liked it.
+`
+	if actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestTeleprompter_exportTeleprompterScript(t *testing.T) {
+	dir := t.TempDir()
+	gist := filepath.Join(dir, "video.md")
+	manuscript := "## Intro\n\nHi\n\n## Setup\n\nx\n\n## The Demo\n\nTODO: Logo: a.png\n\n## Destroy\n"
+	if err := os.WriteFile(gist, []byte(manuscript), 0644); err != nil {
+		t.Fatalf("Error occurred while writing the manuscript: %v", err)
+	}
+	video := Video{Title: "Demo <time>", Gist: gist, Location: dir, Intro: "Hello", Outro: "Bye"}
+	path, err := exportTeleprompterScript(video, 40, true)
+	if err != nil {
+		t.Fatalf("Error occurred while exporting the teleprompter script: %v", err)
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	if string(text) != "Hello\n\nNext: The Demo\n\nBye\n" {
+		t.Errorf("Unexpected teleprompter script:\n%s", string(text))
+	}
+	htmlText, err := os.ReadFile(filepath.Join(dir, "teleprompter.html"))
+	if err != nil {
+		t.Fatalf("Error occurred while reading the HTML version: %v", err)
+	}
+	if !strings.Contains(string(htmlText), "<title>Demo &lt;time&gt;</title>") || !strings.Contains(string(htmlText), "<p>Next: The Demo</p>") {
+		t.Errorf("Unexpected HTML teleprompter script:\n%s", string(htmlText))
+	}
+}
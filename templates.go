@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const templatesDir = "templates"
+
+// templateEmpty is the option for the built-in manuscript template.
+const templateEmpty = ""
+
+const templateFrontMatterSeparator = "---"
+
+// ManuscriptTemplateData are the values available to templates as {{.Name}}, {{.Category}}, and {{.Date}}.
+type ManuscriptTemplateData struct {
+	Name     string
+	Category string
+	Date     string
+}
+
+// ManuscriptTemplate is a rendered template. Defaults are the video fields set in the front matter and are nil if there is none.
+type ManuscriptTemplate struct {
+	Name     string
+	Content  string
+	Defaults *Video
+}
+
+// getManuscriptTemplates returns the names of the templates in the directory without the .md extension. There are none if the directory does not exist.
+func getManuscriptTemplates(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".md" {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".md"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// splitTemplateFrontMatter separates the YAML block between the --- lines at the top of the template from the manuscript.
+func splitTemplateFrontMatter(content string) (string, string, error) {
+	content = strings.TrimPrefix(content, string(utf8BOM))
+	if !strings.HasPrefix(content, templateFrontMatterSeparator+"\n") {
+		return "", content, nil
+	}
+	rest := strings.TrimPrefix(content, templateFrontMatterSeparator+"\n")
+	frontMatter, manuscript, found := strings.Cut(rest, "\n"+templateFrontMatterSeparator+"\n")
+	if !found {
+		if !strings.HasSuffix(rest, "\n"+templateFrontMatterSeparator) {
+			return "", "", fmt.Errorf("the front matter is not closed with %s", templateFrontMatterSeparator)
+		}
+		frontMatter = strings.TrimSuffix(rest, "\n"+templateFrontMatterSeparator)
+	}
+	return frontMatter, manuscript, nil
+}
+
+// renderManuscriptTemplate renders the template and decodes its front matter. Unknown placeholders and front matter keys are errors so that typos do not produce incomplete videos.
+func renderManuscriptTemplate(name, content string, data ManuscriptTemplateData) (ManuscriptTemplate, error) {
+	frontMatter, manuscript, err := splitTemplateFrontMatter(content)
+	if err != nil {
+		return ManuscriptTemplate{}, fmt.Errorf("template %s: %w", name, err)
+	}
+	parsed, err := template.New(name).Option("missingkey=error").Parse(manuscript)
+	if err != nil {
+		return ManuscriptTemplate{}, fmt.Errorf("template %s is malformed: %w", name, err)
+	}
+	rendered := bytes.Buffer{}
+	if err := parsed.Execute(&rendered, data); err != nil {
+		return ManuscriptTemplate{}, fmt.Errorf("template %s could not be rendered: %w", name, err)
+	}
+	if len(strings.TrimSpace(rendered.String())) == 0 {
+		return ManuscriptTemplate{}, fmt.Errorf("template %s is empty", name)
+	}
+	result := ManuscriptTemplate{Name: name, Content: rendered.String()}
+	if len(strings.TrimSpace(frontMatter)) > 0 {
+		defaults := Video{}
+		decoder := yaml.NewDecoder(strings.NewReader(frontMatter))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&defaults); err != nil {
+			return ManuscriptTemplate{}, fmt.Errorf("front matter of template %s is malformed: %w", name, err)
+		}
+		result.Defaults = &defaults
+	}
+	return result, nil
+}
+
+func loadManuscriptTemplate(dir, name string, vi VideoIndex, now time.Time) (ManuscriptTemplate, error) {
+	path := filepath.Join(dir, name+".md")
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ManuscriptTemplate{}, fmt.Errorf("template %s does not exist (%s)", name, path)
+	}
+	if err != nil {
+		return ManuscriptTemplate{}, err
+	}
+	return renderManuscriptTemplate(name, string(content), ManuscriptTemplateData{Name: vi.Name, Category: vi.Category, Date: now.Format(dayFormat)})
+}
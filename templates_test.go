@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplates_getManuscriptTemplates(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"tutorial.md", "review.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("## Intro\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "drafts.md"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := getManuscriptTemplates(dir)
+	if err != nil || !reflect.DeepEqual(templates, []string{"review", "tutorial"}) {
+		t.Errorf("Expected: [review tutorial]\nGot: %v (%v)", templates, err)
+	}
+	if templates, err := getManuscriptTemplates(filepath.Join(dir, "missing")); err != nil || len(templates) != 0 {
+		t.Errorf("Expected: no templates without the directory\nGot: %v (%v)", templates, err)
+	}
+}
+
+func TestTemplates_renderManuscriptTemplate(t *testing.T) {
+	data := ManuscriptTemplateData{Name: "My Video", Category: "development", Date: "2030-01-21"}
+	tests := map[string]struct {
+		content          string
+		expectedContent  string
+		expectedDefaults *Video
+		err              string
+	}{
+		"placeholders": {
+			content:         "## Intro\n\n{{.Name}} ({{.Category}}), {{.Date}}\n",
+			expectedContent: "## Intro\n\nMy Video (development), 2030-01-21\n",
+		},
+		"front matter": {
+			content:          "---\ntags: kubernetes,gitops\nlocation: Studio\n---\n## Intro\n",
+			expectedContent:  "## Intro\n",
+			expectedDefaults: &Video{Tags: "kubernetes,gitops", Location: "Studio"},
+		},
+		"unknown placeholder": {
+			content: "## {{.Title}}\n",
+			err:     "could not be rendered",
+		},
+		"malformed": {
+			content: "## {{.Name\n",
+			err:     "is malformed",
+		},
+		"empty": {
+			content: "---\ntags: kubernetes\n---\n\n",
+			err:     "is empty",
+		},
+		"unclosed front matter": {
+			content: "---\ntags: kubernetes\n## Intro\n",
+			err:     "is not closed",
+		},
+		"unknown front matter key": {
+			content: "---\ntagz: kubernetes\n---\n## Intro\n",
+			err:     "front matter of template tutorial is malformed",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			template, err := renderManuscriptTemplate("tutorial", tc.content, data)
+			if len(tc.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Expected: an error with %q\nGot: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected: no error\nGot: %v", err)
+			}
+			if template.Content != tc.expectedContent || !reflect.DeepEqual(template.Defaults, tc.expectedDefaults) {
+				t.Errorf("Expected: %q %+v\nGot: %q %+v", tc.expectedContent, tc.expectedDefaults, template.Content, template.Defaults)
+			}
+		})
+	}
+}
+
+func TestTemplates_loadManuscriptTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tutorial.md"), []byte("# {{.Name}} {{.Date}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	vi := VideoIndex{Name: "My Video", Category: "development"}
+	now := time.Date(2030, 1, 21, 10, 0, 0, 0, time.UTC)
+	template, err := loadManuscriptTemplate(dir, "tutorial", vi, now)
+	if err != nil || template.Content != "# My Video 2030-01-21\n" {
+		t.Errorf("Expected: # My Video 2030-01-21\nGot: %q (%v)", template.Content, err)
+	}
+	if _, err := loadManuscriptTemplate(dir, "missing", vi, now); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected: an error about the missing template\nGot: %v", err)
+	}
+}
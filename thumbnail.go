@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+type ThumbnailChange = workflow.ThumbnailChange
+
+// Files smaller than this are most likely placeholders dropped by the designer.
+const thumbnailMinSize = 50 * 1024
+
+// The limits YouTube applies to custom thumbnails.
+const thumbnailMaxSize = 2 * 1024 * 1024
+const thumbnailMinWidth = 1280
+const thumbnailMinHeight = 720
+
+func getMaterialDir(video Video) string {
+	if len(video.Location) > 0 {
+		if info, err := os.Stat(video.Location); err == nil && info.IsDir() {
+			return video.Location
+		}
+	}
+	return filepath.Join("material", strings.ReplaceAll(strings.ToLower(video.Name), " ", "-"))
+}
+
+func getThumbnailCandidates(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	candidates := []candidate{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.ToLower(entry.Name())
+		ext := filepath.Ext(name)
+		if !strings.Contains(name, "thumbnail") || (ext != ".jpg" && ext != ".jpeg" && ext != ".png") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() < thumbnailMinSize {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			path:    getRelativePath(filepath.Join(dir, entry.Name())),
+			modTime: info.ModTime(),
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].modTime.After(candidates[j].modTime)
+	})
+	paths := []string{}
+	for _, candidate := range candidates {
+		paths = append(paths, candidate.path)
+	}
+	return paths, nil
+}
+
+func getRelativePath(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	relPath, err := filepath.Rel(wd, absPath)
+	if err != nil {
+		return path
+	}
+	return relPath
+}
+
+// validateThumbnail checks the file before it is uploaded so that YouTube does not reject it after the video was changed locally.
+func validateThumbnail(path string) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return fmt.Errorf("thumbnail %s must be a JPEG or a PNG image", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("thumbnail %s does not exist", path)
+	}
+	// Small files are not rejected since a simple design compresses well. Placeholders are caught by the dimension checks.
+	if info.Size() > thumbnailMaxSize {
+		return fmt.Errorf("thumbnail %s has %d bytes and YouTube accepts up to %d", path, info.Size(), thumbnailMaxSize)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	config, format, err := image.DecodeConfig(file)
+	if err != nil {
+		return fmt.Errorf("thumbnail %s is not a valid image: %w", path, err)
+	}
+	switch {
+	case format != "jpeg" && format != "png":
+		return fmt.Errorf("thumbnail %s is a %s image even though it is named as a JPEG or a PNG", path, format)
+	case config.Width < thumbnailMinWidth || config.Height < thumbnailMinHeight:
+		return fmt.Errorf("thumbnail %s is %dx%d and YouTube requires at least %dx%d", path, config.Width, config.Height, thumbnailMinWidth, thumbnailMinHeight)
+	case config.Width*9 != config.Height*16:
+		return fmt.Errorf("thumbnail %s is %dx%d and does not have the 16:9 aspect ratio", path, config.Width, config.Height)
+	}
+	return nil
+}
+
+// getThumbnailTitle is orange with the reason when the thumbnail is set but would be rejected by validateThumbnail.
+func getThumbnailTitle(title, path string) string {
+	if len(path) == 0 {
+		return redStyle.Render(title)
+	}
+	if err := validateThumbnail(path); err != nil {
+		return orangeStyle.Render(fmt.Sprintf("%s (%s)", title, err))
+	}
+	return greenStyle.Render(title)
+}
+
+// replaceThumbnail uploads the new thumbnail of a published video and adds the previous one to the history.
+// The returned video has to be written by the caller. On failure, it's the video as it was.
+func replaceThumbnail(video Video, path string, publisher Publisher, now time.Time) (Video, error) {
+	if len(video.VideoId) == 0 {
+		return video, fmt.Errorf("%s was not uploaded yet", video.Name)
+	}
+	if filepath.Clean(path) == filepath.Clean(video.Thumbnail) {
+		return video, fmt.Errorf("%s is already the thumbnail", path)
+	}
+	if err := validateThumbnail(path); err != nil {
+		return video, err
+	}
+	replaced := video
+	replaced.Thumbnail = path
+	if err := publisher.UploadThumbnail(replaced); err != nil {
+		return video, fmt.Errorf("the thumbnail could not be uploaded: %w", err)
+	}
+	replaced.ThumbnailHistory = append(append([]ThumbnailChange{}, video.ThumbnailHistory...), ThumbnailChange{Path: video.Thumbnail, ReplacedAt: now.Format(dateFormat)})
+	return replaced, nil
+}
+
+func getThumbnailHistoryText(history []ThumbnailChange) string {
+	if len(history) == 0 {
+		return "The thumbnail was never replaced."
+	}
+	lines := []string{}
+	for _, change := range history {
+		lines = append(lines, fmt.Sprintf("%s: replaced %s", change.ReplacedAt, change.Path))
+	}
+	return strings.Join(lines, "\n")
+}
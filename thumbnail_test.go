@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeThumbnailFixture(t *testing.T, dir, name string, size int, modTime time.Time) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Error occurred while changing times of %s: %v", path, err)
+	}
+	return path
+}
+
+func TestThumbnail_getThumbnailCandidatesNone(t *testing.T) {
+	dir := t.TempDir()
+	writeThumbnailFixture(t, dir, "thumbnail-01.jpg", 1024, time.Now())
+	writeThumbnailFixture(t, dir, "screenshot-01.png", thumbnailMinSize, time.Now())
+	for _, path := range []string{dir, filepath.Join(dir, "does-not-exist")} {
+		candidates, err := getThumbnailCandidates(path)
+		if err != nil {
+			t.Errorf("Error occurred while getting thumbnail candidates: %v", err)
+		}
+		if len(candidates) != 0 {
+			t.Errorf("Expected no candidates in %s, but got %v", path, candidates)
+		}
+	}
+}
+
+func TestThumbnail_getThumbnailCandidatesOne(t *testing.T) {
+	dir := t.TempDir()
+	path := writeThumbnailFixture(t, dir, "thumbnail-01.jpg", thumbnailMinSize, time.Now())
+	candidates, err := getThumbnailCandidates(dir)
+	if err != nil {
+		t.Errorf("Error occurred while getting thumbnail candidates: %v", err)
+	}
+	expected := []string{getRelativePath(path)}
+	if !reflect.DeepEqual(candidates, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, candidates)
+	}
+}
+
+func TestThumbnail_getThumbnailCandidatesMultiple(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	older := writeThumbnailFixture(t, dir, "thumbnail-01.jpg", thumbnailMinSize, now.Add(-time.Hour))
+	newest := writeThumbnailFixture(t, dir, "Thumbnail-02.PNG", thumbnailMinSize, now)
+	oldest := writeThumbnailFixture(t, dir, "thumbnail-03.jpeg", thumbnailMinSize, now.Add(-2*time.Hour))
+	candidates, err := getThumbnailCandidates(dir)
+	if err != nil {
+		t.Errorf("Error occurred while getting thumbnail candidates: %v", err)
+	}
+	expected := []string{getRelativePath(newest), getRelativePath(older), getRelativePath(oldest)}
+	if !reflect.DeepEqual(candidates, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, candidates)
+	}
+}
+
+func TestThumbnail_getRelativePath(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Error occurred while getting the working directory: %v", err)
+	}
+	paths := map[string]string{
+		filepath.Join(wd, "material", "something", "thumbnail-01.jpg"): filepath.Join("material", "something", "thumbnail-01.jpg"),
+		"material/../material/something/thumbnail-01.jpg":              filepath.Join("material", "something", "thumbnail-01.jpg"),
+		"thumbnail-01.jpg": "thumbnail-01.jpg",
+	}
+	for path, expected := range paths {
+		if actual := getRelativePath(path); actual != expected {
+			t.Errorf("Expected %s to become %s, but got %s", path, expected, actual)
+		}
+	}
+}
+
+// writeThumbnailImage writes an image with noise so that it's not compressed below the minimum size.
+// The noise is limited to half of the maximum size so that large images are not compressed above it.
+// The format (png, jpeg, or gif) is independent of the name so that mislabeled files can be written.
+func writeThumbnailImage(t *testing.T, dir, name, format string, width, height int) string {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	random := rand.New(rand.NewSource(1))
+	random.Read(img.Pix[:min(len(img.Pix), thumbnailMaxSize/2)])
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Error occurred while creating %s: %v", path, err)
+	}
+	defer file.Close()
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(file, img, &jpeg.Options{Quality: 100})
+	case "gif":
+		err = gif.Encode(file, img, nil)
+	default:
+		err = png.Encode(file, img)
+	}
+	if err != nil {
+		t.Fatalf("Error occurred while encoding %s: %v", path, err)
+	}
+	return path
+}
+
+func TestThumbnail_validateThumbnail(t *testing.T) {
+	dir := t.TempDir()
+	valid := []string{
+		writeThumbnailImage(t, dir, "thumbnail.png", "png", 1280, 720),
+		writeThumbnailImage(t, dir, "thumbnail.jpg", "jpeg", 1920, 1080),
+	}
+	flat := filepath.Join(dir, "flat.png")
+	file, err := os.Create(flat)
+	if err != nil {
+		t.Fatalf("Error occurred while creating %s: %v", flat, err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, 1280, 720))); err != nil {
+		t.Fatalf("Error occurred while encoding %s: %v", flat, err)
+	}
+	if info, _ := os.Stat(flat); info.Size() >= thumbnailMinSize {
+		t.Fatalf("Expected %s to be smaller than %d bytes, but it has %d", flat, thumbnailMinSize, info.Size())
+	}
+	valid = append(valid, flat)
+	for _, path := range valid {
+		if err := validateThumbnail(path); err != nil {
+			t.Errorf("Expected %s to be valid, but got %v", filepath.Base(path), err)
+		}
+	}
+	invalid := []string{
+		writeThumbnailImage(t, dir, "small.png", "png", 640, 360),
+		writeThumbnailImage(t, dir, "square.png", "png", 1280, 1280),
+		writeThumbnailImage(t, dir, "four-three.jpg", "jpeg", 1280, 960),
+		writeThumbnailImage(t, dir, "animated.png", "gif", 1280, 720),
+		writeThumbnailFixture(t, dir, "placeholder.png", 1024, time.Now()),
+		writeThumbnailFixture(t, dir, "corrupt.png", thumbnailMinSize, time.Now()),
+		writeThumbnailFixture(t, dir, "huge.png", thumbnailMaxSize+1, time.Now()),
+		writeThumbnailFixture(t, dir, "thumbnail.gif", thumbnailMinSize, time.Now()),
+		filepath.Join(dir, "missing.png"),
+	}
+	for _, path := range invalid {
+		if err := validateThumbnail(path); err == nil {
+			t.Errorf("Expected %s to be invalid", filepath.Base(path))
+		}
+	}
+}
+
+func TestThumbnail_replaceThumbnail(t *testing.T) {
+	dir := t.TempDir()
+	path := writeThumbnailImage(t, dir, "thumbnail-02.png", "png", 1280, 720)
+	history := []ThumbnailChange{{Path: "thumbnail-00.png", ReplacedAt: "2030-01-01T16:00"}}
+	video := Video{Name: "something", VideoId: "abc", Thumbnail: "thumbnail-01.png", ThumbnailHistory: history}
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	replaced, err := replaceThumbnail(video, path, fakePublisher{}, now)
+	if err != nil {
+		t.Fatalf("Expected the thumbnail to be replaced, but got %v", err)
+	}
+	expected := []ThumbnailChange{history[0], {Path: "thumbnail-01.png", ReplacedAt: "2030-01-21T16:00"}}
+	if replaced.Thumbnail != path || !reflect.DeepEqual(replaced.ThumbnailHistory, expected) {
+		t.Errorf("Expected: %s with %v\nGot: %s with %v", path, expected, replaced.Thumbnail, replaced.ThumbnailHistory)
+	}
+	if len(video.ThumbnailHistory) != 1 {
+		t.Errorf("Expected the original history to stay unchanged, but got %v", video.ThumbnailHistory)
+	}
+}
+
+func TestThumbnail_replaceThumbnailFailures(t *testing.T) {
+	dir := t.TempDir()
+	valid := writeThumbnailImage(t, dir, "thumbnail-02.png", "png", 1280, 720)
+	narrow := writeThumbnailImage(t, dir, "narrow.png", "png", 600, 500)
+	video := Video{Name: "something", VideoId: "abc", Thumbnail: "thumbnail-01.png"}
+	tests := []struct {
+		name      string
+		video     Video
+		path      string
+		publisher fakePublisher
+	}{
+		{"invalid thumbnail", video, narrow, fakePublisher{}},
+		{"same thumbnail", video, video.Thumbnail, fakePublisher{}},
+		{"failed upload", video, valid, fakePublisher{failThumbnail: true}},
+		{"not uploaded", Video{Name: "something", Thumbnail: "thumbnail-01.png"}, valid, fakePublisher{}},
+	}
+	for _, test := range tests {
+		actual, err := replaceThumbnail(test.video, test.path, test.publisher, time.Now())
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+		if !reflect.DeepEqual(actual, test.video) {
+			t.Errorf("%s: expected the video to stay unchanged, but got %+v", test.name, actual)
+		}
+	}
+}
+
+func TestThumbnail_getThumbnailTitle(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]struct {
+		path     string
+		expected string
+	}{
+		"empty":   {path: "", expected: redStyle.Render("Thumbnail")},
+		"valid":   {path: writeThumbnailImage(t, dir, "thumbnail.png", "png", 1280, 720), expected: greenStyle.Render("Thumbnail")},
+		"missing": {path: filepath.Join(dir, "missing.png"), expected: orangeStyle.Render(fmt.Sprintf("Thumbnail (thumbnail %s does not exist)", filepath.Join(dir, "missing.png")))},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := getThumbnailTitle("Thumbnail", test.path); actual != test.expected {
+				t.Errorf("Expected: %s\nGot: %s", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const thumbnailTextMaxWords = 5
+
+type ThumbnailTextSuggestion struct {
+	Text  string `json:"text"`
+	Notes string `json:"notes"`
+}
+
+// getValidThumbnailTextSuggestions drops the suggestions returned by the thumbnail_text_dot fabric pattern that are empty or longer than five words.
+func getValidThumbnailTextSuggestions(parsed []ThumbnailTextSuggestion) []ThumbnailTextSuggestion {
+	suggestions := []ThumbnailTextSuggestion{}
+	for _, suggestion := range parsed {
+		suggestion.Text = strings.TrimSpace(suggestion.Text)
+		if validateThumbnailText(suggestion.Text) != nil || len(suggestion.Text) == 0 {
+			continue
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+func validateThumbnailText(text string) error {
+	if words := len(strings.Fields(text)); words > thumbnailTextMaxWords {
+		return fmt.Errorf("thumbnail text should have at most %d words, not %d", thumbnailTextMaxWords, words)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestThumbnailText_getValidThumbnailTextSuggestions(t *testing.T) {
+	output := "```json\n" + `[
+	{"text": "KUBERNETES IS DEAD?", "notes": "High contrast, three words"},
+	{"text": "Stop Writing YAML Now", "notes": "Readable at small sizes"},
+	{"text": "This one has way too many words in it", "notes": "Too long"},
+	{"text": " ", "notes": "Empty"}
+]` + "\n```"
+	parsed := []ThumbnailTextSuggestion{}
+	if err := parseAIJSON(output, &parsed); err != nil {
+		t.Fatalf("Expected the suggestions to be parsed, but got %v", err)
+	}
+	actual := getValidThumbnailTextSuggestions(parsed)
+	expected := []ThumbnailTextSuggestion{
+		{Text: "KUBERNETES IS DEAD?", Notes: "High contrast, three words"},
+		{Text: "Stop Writing YAML Now", Notes: "Readable at small sizes"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, actual)
+	}
+}
+
+func TestThumbnailText_getThumbnailEmailBody(t *testing.T) {
+	video := Video{Title: "Kubernetes Is Dead", Tagline: "Long live Kubernetes", ThumbnailText: "K8S IS DEAD?"}
+	body := getThumbnailEmailBody(video)
+	for _, expected := range []string{"Title: Kubernetes Is Dead", "<li>Text: Long live Kubernetes</li>", "<li>Thumbnail text: K8S IS DEAD?</li>"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the email to contain %q, but got:\n%s", expected, body)
+		}
+	}
+	if body := getThumbnailEmailBody(Video{Title: "Something"}); strings.Contains(body, "Thumbnail text") {
+		t.Errorf("Expected no thumbnail text when it is empty, but got:\n%s", body)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+// trendsDir holds one JSON lines file per year (e.g., trends/2030.jsonl) so that no file grows forever.
+var trendsDir = "trends"
+
+type trendPhase struct {
+	Key   string
+	Title string
+	Phase int
+}
+
+// trendPhases are shown in this order. Keys are the phase names used by rules.
+var trendPhases = []trendPhase{
+	{"published", "Published", videosPhasePublished},
+	{"publishPending", "Pending publish", videosPhasePublishPending},
+	{"editRequested", "Edit requested", videosPhaseEditRequested},
+	{"materialDone", "Material done", videosPhaseMaterialDone},
+	{"started", "Started", videosPhaseStarted},
+	{"delayed", "Delayed", videosPhaseDelayed},
+	{"sponsoredBlocked", "Sponsored blocked", videosPhaseSponsoredBlocked},
+	{"ideas", "Ideas", videosPhaseIdeas},
+}
+
+func getPhaseTitle(phase int) string {
+	for _, trend := range trendPhases {
+		if trend.Phase == phase {
+			return trend.Title
+		}
+	}
+	return fmt.Sprint(phase)
+}
+
+// PhaseSnapshot is the number of videos in each phase on a day.
+type PhaseSnapshot struct {
+	Date      string         `json:"date"`
+	Phases    map[string]int `json:"phases"`
+	Total     int            `json:"total"`
+	Sponsored int            `json:"sponsored"`
+	Blocked   int            `json:"blocked"`
+	Overdue   int            `json:"overdue"`
+}
+
+type trendGetter struct {
+	title string
+	value func(PhaseSnapshot) int
+}
+
+// TrendSeries has one value per day. Days without a snapshot are nil.
+type TrendSeries struct {
+	Title  string `json:"title"`
+	Values []*int `json:"values"`
+}
+
+// newPhaseSnapshot takes the phase counts that were already calculated for the list of phases instead of calculating them again.
+func newPhaseSnapshot(videos []Video, phases map[int]int, overdue int, now time.Time) PhaseSnapshot {
+	snapshot := PhaseSnapshot{Date: now.Format(dayFormat), Phases: map[string]int{}, Total: len(videos), Overdue: overdue}
+	for _, phase := range trendPhases {
+		snapshot.Phases[phase.Key] = phases[phase.Phase]
+	}
+	for _, video := range videos {
+		sponsored, blocked := getTrendSponsorship(video)
+		if sponsored {
+			snapshot.Sponsored++
+		}
+		if blocked {
+			snapshot.Blocked++
+		}
+	}
+	return snapshot
+}
+
+// getTrendSponsorship returns whether the video is sponsored and whether the sponsorship is blocked.
+func getTrendSponsorship(video Video) (bool, bool) {
+	amount, blocked := video.Sponsorship.Amount, video.Sponsorship.Blocked
+	// TODO: Remove
+	if len(amount) == 0 {
+		amount = video.Sponsored
+	}
+	if len(blocked) == 0 {
+		blocked = video.SponsorshipBlocked
+	}
+	return workflow.IsSponsored(Sponsorship{Amount: amount}), len(blocked) > 0
+}
+
+func getTrendsPath(dir string, year int) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.jsonl", year))
+}
+
+// readPhaseSnapshotsFile skips lines that cannot be parsed so that a manually edited or imported file does not break the history.
+func readPhaseSnapshotsFile(path string) ([]PhaseSnapshot, error) {
+	snapshots := []PhaseSnapshot{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return snapshots, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		snapshot := PhaseSnapshot{}
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			continue
+		}
+		if _, err := time.Parse(dayFormat, snapshot.Date); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, scanner.Err()
+}
+
+// readPhaseSnapshots returns the snapshots taken between the two days (inclusive), reading only the files of the years in between.
+func readPhaseSnapshots(dir string, from, to time.Time) ([]PhaseSnapshot, error) {
+	fromDay, toDay := from.Format(dayFormat), to.Format(dayFormat)
+	snapshots := []PhaseSnapshot{}
+	for year := from.Year(); year <= to.Year(); year++ {
+		yearSnapshots, err := readPhaseSnapshotsFile(getTrendsPath(dir, year))
+		if err != nil {
+			return nil, err
+		}
+		for _, snapshot := range yearSnapshots {
+			if snapshot.Date >= fromDay && snapshot.Date <= toDay {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+	return snapshots, nil
+}
+
+// recordPhaseSnapshot appends the snapshot unless one was already recorded within the interval, so restarting the CLI on the same day does not record it twice.
+func recordPhaseSnapshot(dir string, snapshot PhaseSnapshot, intervalDays int) (bool, error) {
+	date, err := time.Parse(dayFormat, snapshot.Date)
+	if err != nil {
+		return false, err
+	}
+	recent, err := readPhaseSnapshots(dir, date.AddDate(0, 0, -max(intervalDays, 1)+1), date)
+	if err != nil {
+		return false, err
+	}
+	if len(recent) > 0 {
+		return false, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	file, err := os.OpenFile(getTrendsPath(dir, date.Year()), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// getTrendSeries returns the days ending with to and a series per phase and per total. If a day has more than one snapshot, the last one is used.
+func getTrendSeries(snapshots []PhaseSnapshot, to time.Time, days int) ([]string, []TrendSeries) {
+	byDate := map[string]PhaseSnapshot{}
+	for _, snapshot := range snapshots {
+		byDate[snapshot.Date] = snapshot
+	}
+	dates := []string{}
+	for i := days - 1; i >= 0; i-- {
+		dates = append(dates, to.AddDate(0, 0, -i).Format(dayFormat))
+	}
+	getters := []trendGetter{}
+	for _, phase := range trendPhases {
+		key := phase.Key
+		getters = append(getters, trendGetter{phase.Title, func(snapshot PhaseSnapshot) int { return snapshot.Phases[key] }})
+	}
+	getters = append(getters,
+		trendGetter{"Total", func(snapshot PhaseSnapshot) int { return snapshot.Total }},
+		trendGetter{"Sponsored", func(snapshot PhaseSnapshot) int { return snapshot.Sponsored }},
+		trendGetter{"Blocked", func(snapshot PhaseSnapshot) int { return snapshot.Blocked }},
+		trendGetter{"Overdue", func(snapshot PhaseSnapshot) int { return snapshot.Overdue }},
+	)
+	series := []TrendSeries{}
+	for _, getter := range getters {
+		row := TrendSeries{Title: getter.title, Values: make([]*int, len(dates))}
+		for i, date := range dates {
+			if snapshot, ok := byDate[date]; ok {
+				value := getter.value(snapshot)
+				row.Values[i] = &value
+			}
+		}
+		series = append(series, row)
+	}
+	return dates, series
+}
+
+// getTrendSparkline scales the values between the lowest and the highest one in the series. Gaps are shown as spaces.
+func getTrendSparkline(values []*int) string {
+	lowest, highest := -1, -1
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		if lowest < 0 || *value < lowest {
+			lowest = *value
+		}
+		if *value > highest {
+			highest = *value
+		}
+	}
+	var builder strings.Builder
+	for _, value := range values {
+		switch {
+		case value == nil:
+			builder.WriteRune(' ')
+		case highest == lowest:
+			builder.WriteRune(sparklineLevels[0])
+		default:
+			builder.WriteRune(sparklineLevels[(*value-lowest)*(len(sparklineLevels)-1)/(highest-lowest)])
+		}
+	}
+	return builder.String()
+}
+
+func getTrendsText(dates []string, series []TrendSeries) string {
+	snapshots := 0
+	for _, value := range series[0].Values {
+		if value != nil {
+			snapshots++
+		}
+	}
+	if snapshots == 0 {
+		return "There are no snapshots yet. They are recorded when videos are listed."
+	}
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("From %s to %s (%d snapshots)\n\n", dates[0], dates[len(dates)-1], snapshots))
+	for _, row := range series {
+		latest := "-"
+		for _, value := range row.Values {
+			if value != nil {
+				latest = fmt.Sprintf("%d", *value)
+			}
+		}
+		builder.WriteString(fmt.Sprintf("%-18s %s %s\n", row.Title, getTrendSparkline(row.Values), latest))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getTrendsTestSnapshot(date string, ideas int) PhaseSnapshot {
+	return PhaseSnapshot{Date: date, Phases: map[string]int{"ideas": ideas, "published": 10}, Total: ideas + 10}
+}
+
+func getTrendValues(values []*int) []int {
+	output := []int{}
+	for _, value := range values {
+		if value == nil {
+			output = append(output, -1)
+		} else {
+			output = append(output, *value)
+		}
+	}
+	return output
+}
+
+func TestTrends_newPhaseSnapshot(t *testing.T) {
+	videos := []Video{
+		{Sponsorship: Sponsorship{Amount: "1000", Blocked: "waiting"}},
+		{Sponsored: "500"},
+		{Sponsorship: Sponsorship{Amount: "N/A"}},
+		{},
+	}
+	phases := map[int]int{videosPhaseSponsoredBlocked: 1, videosPhaseIdeas: 3}
+	actual := newPhaseSnapshot(videos, phases, 1, time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC))
+	if actual.Date != "2030-01-21" || actual.Total != 4 || actual.Sponsored != 2 || actual.Blocked != 1 || actual.Overdue != 1 {
+		t.Errorf("Unexpected snapshot %+v", actual)
+	}
+	if actual.Phases["ideas"] != 3 || actual.Phases["sponsoredBlocked"] != 1 || actual.Phases["published"] != 0 || len(actual.Phases) != len(trendPhases) {
+		t.Errorf("Unexpected phases %v", actual.Phases)
+	}
+}
+
+func TestTrends_recordPhaseSnapshot(t *testing.T) {
+	tests := []struct {
+		name         string
+		dates        []string
+		intervalDays int
+		expected     []bool
+	}{
+		{"same day is recorded once", []string{"2030-01-21", "2030-01-21", "2030-01-22"}, 1, []bool{true, false, true}},
+		{"interval", []string{"2030-01-21", "2030-01-22", "2030-01-23", "2030-01-24"}, 3, []bool{true, false, false, true}},
+		{"interval across years", []string{"2030-12-31", "2031-01-01", "2031-01-02"}, 2, []bool{true, false, true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			actual := []bool{}
+			for _, date := range test.dates {
+				recorded, err := recordPhaseSnapshot(dir, getTrendsTestSnapshot(date, 1), test.intervalDays)
+				if err != nil {
+					t.Fatalf("Expected no error, but got %v", err)
+				}
+				actual = append(actual, recorded)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected: %v\nGot: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTrends_rotation(t *testing.T) {
+	dir := t.TempDir()
+	for _, date := range []string{"2030-12-30", "2030-12-31", "2031-01-01"} {
+		if _, err := recordPhaseSnapshot(dir, getTrendsTestSnapshot(date, 1), 1); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+	}
+	for year, expected := range map[int]int{2030: 2, 2031: 1} {
+		snapshots, err := readPhaseSnapshotsFile(getTrendsPath(dir, year))
+		if err != nil || len(snapshots) != expected {
+			t.Errorf("Expected %d snapshots in %d, but got %d and %v", expected, year, len(snapshots), err)
+		}
+	}
+	snapshots, err := readPhaseSnapshots(dir, time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(snapshots) != 2 {
+		t.Errorf("Expected the query to span both years, but got %v and %v", snapshots, err)
+	}
+}
+
+func TestTrends_getTrendSeries(t *testing.T) {
+	dir := t.TempDir()
+	history := strings.Join([]string{
+		`{"date":"2030-01-17","phases":{"ideas":9,"published":10},"total":19}`,
+		`{"date":"2030-01-18","phases":{"ideas":8,"published":10},"total":18}`,
+		`not json`,
+		`{"date":"2030-01-21","phases":{"ideas":7,"published":10},"total":17}`,
+		`{"date":"2030-01-21","phases":{"ideas":6,"published":10},"total":16}`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "2030.jsonl"), []byte(history), 0644); err != nil {
+		t.Fatalf("Error occurred while writing the history: %v", err)
+	}
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	snapshots, err := readPhaseSnapshots(dir, now.AddDate(0, 0, -3), now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	dates, series := getTrendSeries(snapshots, now, 4)
+	expectedDates := []string{"2030-01-18", "2030-01-19", "2030-01-20", "2030-01-21"}
+	if !reflect.DeepEqual(dates, expectedDates) {
+		t.Errorf("Expected: %v\nGot: %v", expectedDates, dates)
+	}
+	for _, row := range series {
+		if row.Title != "Ideas" {
+			continue
+		}
+		expected := []int{8, -1, -1, 6}
+		if actual := getTrendValues(row.Values); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected: %v\nGot: %v", expected, actual)
+		}
+		if sparkline := getTrendSparkline(row.Values); sparkline != "█  ▁" {
+			t.Errorf("Expected gaps to be shown as spaces, but got %q", sparkline)
+		}
+	}
+	text := getTrendsText(dates, series)
+	if !strings.Contains(text, "From 2030-01-18 to 2030-01-21 (2 snapshots)") || !strings.Contains(text, "Total") {
+		t.Errorf("Unexpected text %q", text)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+const utmNamePlaceholder = "[NAME]"
+const linkCheckTimeout = 10 * time.Second
+
+func getDefaultUTMParameters() map[string]string {
+	return map[string]string{
+		"utm_source":   "youtube",
+		"utm_medium":   "video",
+		"utm_campaign": utmNamePlaceholder,
+	}
+}
+
+// addUTMParameters appends the parameters to the URL, replacing [NAME] with the name of the video.
+// Parameters that are already in the URL are left untouched, as are the existing query and the fragment.
+func addUTMParameters(rawURL string, parameters map[string]string, name string) (string, error) {
+	link, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL, err
+	}
+	if link.Scheme != "http" && link.Scheme != "https" || len(link.Host) == 0 {
+		return rawURL, fmt.Errorf("%q is not an http(s) URL", rawURL)
+	}
+	existing := link.Query()
+	values := url.Values{}
+	for key, value := range parameters {
+		if existing.Has(key) {
+			continue
+		}
+		values.Set(key, strings.ReplaceAll(value, utmNamePlaceholder, name))
+	}
+	if len(values) == 0 {
+		return link.String(), nil
+	}
+	if len(link.RawQuery) > 0 {
+		link.RawQuery = fmt.Sprintf("%s&%s", link.RawQuery, values.Encode())
+	} else {
+		link.RawQuery = values.Encode()
+	}
+	return link.String(), nil
+}
+
+// getProjectURL returns the project URL as it should be rendered in descriptions and posts.
+// Links of sponsored videos are tagged with UTM parameters while the ProjectURL stored in the YAML stays untouched.
+func getProjectURL(video Video) string {
+	if !workflow.IsSponsored(video.Sponsorship) || len(video.ProjectURL) == 0 || video.ProjectURL == "N/A" || video.ProjectURL == "-" {
+		return video.ProjectURL
+	}
+	tagged, err := addUTMParameters(video.ProjectURL, settings.UTM, video.Name)
+	if err != nil {
+		return video.ProjectURL
+	}
+	return tagged
+}
+
+type LinkCheckResult struct {
+	URL        string
+	StatusCode int
+	FinalURL   string
+	Err        error
+}
+
+func (r LinkCheckResult) OK() bool {
+	return r.Err == nil && r.StatusCode < 400
+}
+
+func (r LinkCheckResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s could not be reached: %s", r.URL, r.Err)
+	}
+	message := fmt.Sprintf("%s responded with %d", r.URL, r.StatusCode)
+	if r.FinalURL != r.URL {
+		message = fmt.Sprintf("%s after redirecting to %s", message, r.FinalURL)
+	}
+	return message
+}
+
+// checkLink follows redirects and reports the final status code and URL.
+// Servers that do not support HEAD requests are retried with GET.
+func checkLink(ctx context.Context, client *http.Client, link string) LinkCheckResult {
+	result := LinkCheckResult{URL: link}
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		request, err := http.NewRequestWithContext(ctx, method, link, nil)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			result.Err = err
+			continue
+		}
+		response.Body.Close()
+		result.Err = nil
+		result.StatusCode = response.StatusCode
+		result.FinalURL = response.Request.URL.String()
+		if method == http.MethodHead && (response.StatusCode == http.StatusMethodNotAllowed || response.StatusCode == http.StatusNotImplemented) {
+			continue
+		}
+		break
+	}
+	return result
+}
+
+func checkProjectURL(video Video) LinkCheckResult {
+	ctx, cancel := context.WithTimeout(context.Background(), linkCheckTimeout)
+	defer cancel()
+	return checkLink(ctx, http.DefaultClient, getProjectURL(video))
+}
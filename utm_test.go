@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUTM_addUTMParameters(t *testing.T) {
+	parameters := getDefaultUTMParameters()
+	tests := []struct {
+		url      string
+		expected string
+		err      bool
+	}{
+		{"https://example.com", "https://example.com?utm_campaign=my-video&utm_medium=video&utm_source=youtube", false},
+		{"https://example.com/docs/", "https://example.com/docs/?utm_campaign=my-video&utm_medium=video&utm_source=youtube", false},
+		{"https://example.com/?ref=abc&b=1", "https://example.com/?ref=abc&b=1&utm_campaign=my-video&utm_medium=video&utm_source=youtube", false},
+		{"https://example.com/docs#install", "https://example.com/docs?utm_campaign=my-video&utm_medium=video&utm_source=youtube#install", false},
+		{"https://example.com/?utm_source=newsletter", "https://example.com/?utm_source=newsletter&utm_campaign=my-video&utm_medium=video", false},
+		{" https://example.com ", "https://example.com?utm_campaign=my-video&utm_medium=video&utm_source=youtube", false},
+		{"example.com", "example.com", true},
+		{"ftp://example.com", "ftp://example.com", true},
+	}
+	for _, test := range tests {
+		actual, err := addUTMParameters(test.url, parameters, "my-video")
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected error=%t, but got %v", test.url, test.err, err)
+		}
+		if actual != test.expected {
+			t.Errorf("%s\nExpected: %s\nGot: %s", test.url, test.expected, actual)
+		}
+	}
+}
+
+func TestUTM_getProjectURL(t *testing.T) {
+	settings.UTM = map[string]string{"utm_source": "youtube"}
+	defer func() { settings.UTM = nil }()
+	videos := []struct {
+		video    Video
+		expected string
+	}{
+		{Video{Name: "sponsored", ProjectURL: "https://example.com", Sponsorship: Sponsorship{Amount: "1000"}}, "https://example.com?utm_source=youtube"},
+		{Video{Name: "not-sponsored", ProjectURL: "https://example.com", Sponsorship: Sponsorship{Amount: "N/A"}}, "https://example.com"},
+		{Video{Name: "no-url", ProjectURL: "N/A", Sponsorship: Sponsorship{Amount: "1000"}}, "N/A"},
+	}
+	for _, video := range videos {
+		if actual := getProjectURL(video.video); actual != video.expected {
+			t.Errorf("%s\nExpected: %s\nGot: %s", video.video.Name, video.expected, actual)
+		}
+	}
+}
+
+func TestUTM_checkLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/ok?"+r.URL.RawQuery, http.StatusMovedPermanently)
+		case "/gone":
+			http.Redirect(w, r, "/missing", http.StatusFound)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	tests := []struct {
+		path       string
+		statusCode int
+		finalPath  string
+		ok         bool
+	}{
+		{"/ok", http.StatusOK, "/ok", true},
+		{"/moved?utm_source=youtube", http.StatusOK, "/ok?utm_source=youtube", true},
+		{"/gone", http.StatusNotFound, "/missing", false},
+		{"/get-only", http.StatusOK, "/get-only", true},
+		{"/typo", http.StatusNotFound, "/typo", false},
+	}
+	for _, test := range tests {
+		result := checkLink(context.Background(), server.Client(), server.URL+test.path)
+		if result.StatusCode != test.statusCode || result.FinalURL != server.URL+test.finalPath || result.OK() != test.ok {
+			t.Errorf("%s: expected %d %s (ok=%t), but got %s", test.path, test.statusCode, test.finalPath, test.ok, result)
+		}
+	}
+	server.Close()
+	if result := checkLink(context.Background(), http.DefaultClient, server.URL+"/ok"); result.OK() || result.Err == nil {
+		t.Errorf("Expected an error for an unreachable server, but got %s", result)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"sync"
+)
+
+// videoLoadWorkers bounds how many video files are read at the same time.
+const videoLoadWorkers = 8
+
+// videoPromptMutex makes questions asked while videos are read concurrently (e.g., to relocate a missing video) wait for each other.
+var videoPromptMutex sync.Mutex
+
+// forEachConcurrently calls fn for each index from 0 to n-1 with at most workers calls running at the same time. It returns when all calls are done.
+func forEachConcurrently(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// getVideoConcurrently is getVideo that can be called from multiple goroutines. Videos without files might ask questions, so they are read one at a time.
+func (c *Choices) getVideoConcurrently(vi []VideoIndex, index int) Video {
+	if _, err := os.Stat(c.GetFilePath(vi[index].Category, vi[index].Name, "yaml")); err != nil {
+		videoPromptMutex.Lock()
+		defer videoPromptMutex.Unlock()
+	}
+	return c.getVideo(vi, index)
+}
+
+// getIndexedVideos reads the videos at the positions of the index concurrently and returns them in the same order.
+func (c *Choices) getIndexedVideos(vi []VideoIndex, positions []int) []Video {
+	videos := make([]Video, len(positions))
+	forEachConcurrently(len(positions), videoLoadWorkers, func(i int) {
+		videos[i] = c.getVideoConcurrently(vi, positions[i])
+	})
+	return videos
+}
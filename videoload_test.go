@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestVideoLoad_forEachConcurrently(t *testing.T) {
+	tests := map[string]struct {
+		n       int
+		workers int
+	}{
+		"more items than workers":  {n: 100, workers: 4},
+		"fewer items than workers": {n: 2, workers: 8},
+		"no items":                 {n: 0, workers: 4},
+		"no workers":               {n: 5, workers: 0},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var running, maxRunning int32
+			var mu sync.Mutex
+			calls := map[int]int{}
+			forEachConcurrently(tc.n, tc.workers, func(i int) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+						break
+					}
+				}
+				mu.Lock()
+				calls[i]++
+				mu.Unlock()
+				atomic.AddInt32(&running, -1)
+			})
+			if len(calls) != tc.n {
+				t.Errorf("Expected: %d calls\nGot: %d", tc.n, len(calls))
+			}
+			for i, count := range calls {
+				if count != 1 {
+					t.Errorf("Expected: %d to be called once\nGot: %d", i, count)
+				}
+			}
+			if limit := max(tc.workers, 1); int(maxRunning) > limit {
+				t.Errorf("Expected: at most %d calls at the same time\nGot: %d", limit, maxRunning)
+			}
+		})
+	}
+}
+
+// The order of the entries must follow the paths even though the videos are read concurrently.
+func TestVideoLoad_PhaseSummaryRefreshOrder(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{}
+	for i := 0; i < 50; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("video-%d.yaml", i))
+		writePhaseSummaryTestVideo(t, path, Video{Date: fmt.Sprintf("2030-01-%02dT16:00", i%28+1)})
+		paths = append(paths, path)
+	}
+	entries, reads := NewPhaseSummary().Refresh(paths, readPhaseSummaryTestVideo(paths))
+	if reads != len(paths) {
+		t.Errorf("Expected: %d reads\nGot: %d", len(paths), reads)
+	}
+	for i, entry := range entries {
+		if expected := fmt.Sprintf("2030-01-%02dT16:00", i%28+1); entry.Date != expected {
+			t.Errorf("Expected: %s\nGot: %s", expected, entry.Date)
+		}
+	}
+}
+
+// A video rewritten with WriteVideo is read again even if the summary was refreshed before.
+func TestVideoLoad_PhaseSummaryRefreshAfterWriteVideo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "video.yaml")
+	paths := []string{path}
+	y := YAML{}
+	if err := y.writeVideo(&Video{Date: "2030-01-21T16:00"}, path); err != nil {
+		t.Fatal(err)
+	}
+	summary := NewPhaseSummary()
+	summary.Refresh(paths, readPhaseSummaryTestVideo(paths))
+	if err := y.writeVideo(&Video{Date: "2030-01-21T16:00", Delayed: true}, path); err != nil {
+		t.Fatal(err)
+	}
+	entries, reads := summary.Refresh(paths, readPhaseSummaryTestVideo(paths))
+	if reads != 1 || entries[0].Phase != videosPhaseDelayed {
+		t.Errorf("Expected: the rewritten video to be read again and delayed\nGot: %d reads, phase %d", reads, entries[0].Phase)
+	}
+}
+
+// BenchmarkVideoLoad compares reading a few hundred videos one by one with reading them concurrently.
+func BenchmarkVideoLoad(b *testing.B) {
+	dir := b.TempDir()
+	paths := []string{}
+	for i := 0; i < 400; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("video-%d.yaml", i))
+		writePhaseSummaryTestVideo(b, path, getQualityTestVideos()["complete"])
+		paths = append(paths, path)
+	}
+	read := func(i int) {
+		if _, err := readVideo(paths[i]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.Run("sequential", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for i := range paths {
+				read(i)
+			}
+		}
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			forEachConcurrently(len(paths), videoLoadWorkers, read)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const visibilityPrivate = "private"
+const visibilityUnlisted = "unlisted"
+const visibilityPublic = "public"
+const visibilityScheduled = "scheduled"
+
+// UploadStatus is the effective YouTube status of an upload.
+type UploadStatus struct {
+	Visibility    string
+	PrivacyStatus string
+	PublishAt     string
+	MadeForKids   bool
+}
+
+// getUploadStatus resolves the visibility and made-for-kids settings from the video, the category defaults, and the global defaults, in that order.
+// Videos are scheduled (private with publishAt) unless configured otherwise.
+func getUploadStatus(video Video, defaults SettingsUpload) (UploadStatus, error) {
+	status := UploadStatus{Visibility: visibilityScheduled, MadeForKids: defaults.MadeForKids}
+	if len(defaults.Visibility) > 0 {
+		status.Visibility = defaults.Visibility
+	}
+	madeForKids := ""
+	if category, ok := defaults.Categories[video.Category]; ok {
+		if len(category.Visibility) > 0 {
+			status.Visibility = category.Visibility
+		}
+		madeForKids = category.MadeForKids
+	}
+	if len(video.Visibility) > 0 {
+		status.Visibility = video.Visibility
+	}
+	if len(video.MadeForKids) > 0 {
+		madeForKids = video.MadeForKids
+	}
+	if len(madeForKids) > 0 {
+		value, err := strconv.ParseBool(madeForKids)
+		if err != nil {
+			return UploadStatus{}, fmt.Errorf("made for kids must be true or false, not %s", madeForKids)
+		}
+		status.MadeForKids = value
+	}
+	// Videos with content flags are never made for kids, whatever the defaults say.
+	if hasContentFlags(video.ContentFlags) {
+		status.MadeForKids = false
+	}
+	switch status.Visibility {
+	case visibilityScheduled:
+		if len(video.Date) == 0 {
+			return UploadStatus{}, fmt.Errorf("scheduled videos require a publish date")
+		}
+		status.PrivacyStatus = visibilityPrivate
+		status.PublishAt = video.Date
+	case visibilityPrivate:
+		status.PrivacyStatus = visibilityPrivate
+	case visibilityPublic, visibilityUnlisted:
+		if len(video.Date) > 0 {
+			return UploadStatus{}, fmt.Errorf("%s videos cannot have a publish date (%s), use the scheduled visibility instead", status.Visibility, video.Date)
+		}
+		status.PrivacyStatus = status.Visibility
+	default:
+		return UploadStatus{}, fmt.Errorf("visibility %s is not one of private, unlisted, public, or scheduled", status.Visibility)
+	}
+	return status, nil
+}
+
+// getPublishAt converts the publish date, which has no timezone, into the RFC3339 time YouTube expects for publishAt.
+func getPublishAt(date, timezone string) (time.Time, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	publishAt, err := time.ParseInLocation(dateFormat, date, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("publish date %q must be in the %s format", date, dateFormat)
+	}
+	return publishAt, nil
+}
+
+// scheduleUploadStatus converts the publish date of a scheduled upload into publishAt. YouTube rejects publishAt in the past,
+// so videos whose publish date has passed are published immediately instead and the returned warning says so.
+func scheduleUploadStatus(status UploadStatus, timezone string, now time.Time) (UploadStatus, string, error) {
+	if len(status.PublishAt) == 0 {
+		return status, "", nil
+	}
+	publishAt, err := getPublishAt(status.PublishAt, timezone)
+	if err != nil {
+		return UploadStatus{}, "", err
+	}
+	if !publishAt.After(now) {
+		warning := fmt.Sprintf("The publish date %s has passed, so the video will be published immediately.", status.PublishAt)
+		status.PrivacyStatus = visibilityPublic
+		status.PublishAt = ""
+		return status, warning, nil
+	}
+	status.PublishAt = publishAt.Format(time.RFC3339)
+	return status, "", nil
+}
+
+// getScheduledUploadStatus resolves the upload status and schedules it in the timezone of the publish dates.
+func getScheduledUploadStatus(video Video, s Settings, now time.Time) (UploadStatus, string, error) {
+	status, err := getUploadStatus(video, s.Upload)
+	if err != nil {
+		return UploadStatus{}, "", err
+	}
+	return scheduleUploadStatus(status, s.Schedule.Timezone, now)
+}
+
+func getUploadStatusMessage(status UploadStatus) string {
+	message := fmt.Sprintf("The video will be uploaded as %s", status.PrivacyStatus)
+	if len(status.PublishAt) > 0 {
+		message = fmt.Sprintf("%s and scheduled to be published at %s", message, status.PublishAt)
+	} else if status.Visibility == visibilityScheduled {
+		message = fmt.Sprintf("%s immediately instead of scheduled", message)
+	}
+	if status.MadeForKids {
+		return fmt.Sprintf("%s, made for kids.", message)
+	}
+	return fmt.Sprintf("%s, not made for kids.", message)
+}
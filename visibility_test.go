@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVisibility_getUploadStatus(t *testing.T) {
+	defaults := SettingsUpload{
+		Categories: map[string]SettingsUploadCategory{
+			"shorts": {Visibility: visibilityUnlisted},
+			"kids":   {MadeForKids: "true"},
+		},
+	}
+	tests := []struct {
+		name     string
+		video    Video
+		defaults SettingsUpload
+		expected UploadStatus
+		err      bool
+	}{
+		{"default", Video{Date: "2030-01-21T16:00"}, defaults, UploadStatus{Visibility: visibilityScheduled, PrivacyStatus: "private", PublishAt: "2030-01-21T16:00"}, false},
+		{"scheduled without date", Video{}, defaults, UploadStatus{}, true},
+		{"category visibility", Video{Category: "shorts"}, defaults, UploadStatus{Visibility: visibilityUnlisted, PrivacyStatus: "unlisted"}, false},
+		{"category made for kids", Video{Category: "kids", Date: "2030-01-21T16:00"}, defaults, UploadStatus{Visibility: visibilityScheduled, PrivacyStatus: "private", PublishAt: "2030-01-21T16:00", MadeForKids: true}, false},
+		{"video overrides", Video{Category: "kids", Visibility: visibilityPrivate, MadeForKids: "false", Date: "2030-01-21T16:00"}, defaults, UploadStatus{Visibility: visibilityPrivate, PrivacyStatus: "private"}, false},
+		{"global defaults", Video{}, SettingsUpload{Visibility: visibilityPublic, MadeForKids: true}, UploadStatus{Visibility: visibilityPublic, PrivacyStatus: "public", MadeForKids: true}, false},
+		{"public with publish date", Video{Visibility: visibilityPublic, Date: "2030-01-21T16:00"}, defaults, UploadStatus{}, true},
+		{"unknown visibility", Video{Visibility: "secret"}, defaults, UploadStatus{}, true},
+		{"invalid made for kids", Video{MadeForKids: "maybe", Date: "2030-01-21T16:00"}, defaults, UploadStatus{}, true},
+	}
+	for _, test := range tests {
+		actual, err := getUploadStatus(test.video, test.defaults)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected error=%t, but got %v", test.name, test.err, err)
+		}
+		if actual != test.expected {
+			t.Errorf("%s\nExpected: %+v\nGot: %+v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestVisibility_getUploadRequest(t *testing.T) {
+	statuses := []UploadStatus{
+		{PrivacyStatus: "private", PublishAt: "2030-01-21T16:00"},
+		{PrivacyStatus: "public", MadeForKids: true},
+		{PrivacyStatus: "unlisted"},
+	}
+	for _, status := range statuses {
+		request := getUploadRequest(Video{Title: "Something", Tags: "a,b"}, status)
+		if request.Status.PrivacyStatus != status.PrivacyStatus || request.Status.PublishAt != status.PublishAt || request.Status.SelfDeclaredMadeForKids != status.MadeForKids {
+			t.Errorf("Expected the request status to match %+v, but got %+v", status, request.Status)
+		}
+		data, err := request.Status.MarshalJSON()
+		if err != nil {
+			t.Fatalf("Error occurred while marshaling the status: %v", err)
+		}
+		if !status.MadeForKids && !strings.Contains(string(data), `"selfDeclaredMadeForKids":false`) {
+			t.Errorf("Expected made for kids to be sent explicitly, but got %s", data)
+		}
+	}
+}
+
+func TestVisibility_getPublishAt(t *testing.T) {
+	tests := map[string]struct {
+		date     string
+		timezone string
+		expected string
+		err      bool
+	}{
+		"utc":             {date: "2030-01-21T16:00", timezone: "UTC", expected: "2030-01-21T16:00:00Z"},
+		"berlin":          {date: "2030-01-21T16:00", timezone: "Europe/Berlin", expected: "2030-01-21T16:00:00+01:00"},
+		"daylight saving": {date: "2030-07-21T16:00", timezone: "Europe/Berlin", expected: "2030-07-21T16:00:00+02:00"},
+		"invalid date":    {date: "2030-01-21", timezone: "UTC", err: true},
+		"invalid zone":    {date: "2030-01-21T16:00", timezone: "Mars/Olympus", err: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := getPublishAt(tc.date, tc.timezone)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected: error=%t\nGot: %v", tc.err, err)
+			}
+			if !tc.err && got.Format(time.RFC3339) != tc.expected {
+				t.Errorf("Expected: %s\nGot: %s", tc.expected, got.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestVisibility_scheduleUploadStatus(t *testing.T) {
+	now := time.Date(2030, 1, 21, 12, 0, 0, 0, time.UTC)
+	scheduled := UploadStatus{Visibility: visibilityScheduled, PrivacyStatus: visibilityPrivate, PublishAt: "2030-01-21T16:00"}
+	tests := map[string]struct {
+		status   UploadStatus
+		expected UploadStatus
+		warning  bool
+	}{
+		"future": {
+			status:   scheduled,
+			expected: UploadStatus{Visibility: visibilityScheduled, PrivacyStatus: visibilityPrivate, PublishAt: "2030-01-21T16:00:00Z"},
+		},
+		"past": {
+			status:   UploadStatus{Visibility: visibilityScheduled, PrivacyStatus: visibilityPrivate, PublishAt: "2030-01-21T09:00"},
+			expected: UploadStatus{Visibility: visibilityScheduled, PrivacyStatus: visibilityPublic},
+			warning:  true,
+		},
+		"not scheduled": {
+			status:   UploadStatus{Visibility: visibilityUnlisted, PrivacyStatus: visibilityUnlisted},
+			expected: UploadStatus{Visibility: visibilityUnlisted, PrivacyStatus: visibilityUnlisted},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, warning, err := scheduleUploadStatus(tc.status, "UTC", now)
+			if err != nil {
+				t.Fatalf("Expected: no error\nGot: %v", err)
+			}
+			if got != tc.expected || (len(warning) > 0) != tc.warning {
+				t.Errorf("Expected: %+v (warning=%t)\nGot: %+v (%q)", tc.expected, tc.warning, got, warning)
+			}
+		})
+	}
+	if message := getUploadStatusMessage(UploadStatus{Visibility: visibilityScheduled, PrivacyStatus: visibilityPublic}); !strings.Contains(message, "immediately") {
+		t.Errorf("Expected: an immediate upload to be stated\nGot: %s", message)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
+)
+
+func getDefaultWorkloadHours() map[string]float64 {
+	return map[string]float64{
+		"started":        6,
+		"materialDone":   4,
+		"editRequested":  2,
+		"publishPending": 1,
+	}
+}
+
+// WorkloadGroup is the queued work of the videos in a phase or a category.
+type WorkloadGroup struct {
+	Name   string
+	Videos int
+	Hours  float64
+}
+
+// WorkloadItem is a sponsored video that has to be worked on before its publish date. Videos in phases without an estimate are not Estimated.
+type WorkloadItem struct {
+	Name      string
+	Category  string
+	Phase     string
+	Date      time.Time
+	Hours     float64
+	Estimated bool
+}
+
+type Workload struct {
+	Phases     []WorkloadGroup
+	Categories []WorkloadGroup
+	MustDo     []WorkloadItem
+	Hours      float64
+	Capacity   float64
+}
+
+func (w Workload) ExceedsCapacity() bool {
+	return w.Capacity > 0 && w.Hours > w.Capacity
+}
+
+// getVideoWorkloadHours returns the hours left for the video in its phase or, when weighted, only the part of them that matches the work that is not completed yet.
+func getVideoWorkloadHours(video Video, hours float64, weighted bool) float64 {
+	if !weighted {
+		return hours
+	}
+	return hours * float64(100-getVideoCompletion(video)) / 100
+}
+
+// getWorkload multiplies the hours estimated for each phase by the videos in it. Phases without an estimate add no work.
+// Videos with a scheduled date within the must do days that are sponsored and not published are listed first, sorted by date, including those that are overdue
+// and those in phases without an estimate.
+func getWorkload(videos []Video, options SettingsWorkload, now time.Time) Workload {
+	workload := Workload{Capacity: options.WeeklyCapacity}
+	phases := map[string]*WorkloadGroup{}
+	categories := map[string]*WorkloadGroup{}
+	mustDoUntil := now.AddDate(0, 0, options.MustDoDays)
+	for _, video := range videos {
+		videoPhase := workflow.GetPhase(video)
+		phase := ""
+		for _, trend := range trendPhases {
+			if trend.Phase == videoPhase {
+				phase = trend.Key
+			}
+		}
+		estimate, estimated := options.Hours[phase]
+		estimated = estimated && estimate > 0
+		hours := 0.0
+		if estimated {
+			hours = getVideoWorkloadHours(video, estimate, options.Weighted)
+			if _, ok := phases[phase]; !ok {
+				phases[phase] = &WorkloadGroup{Name: phase}
+			}
+			phases[phase].Videos++
+			phases[phase].Hours += hours
+			if _, ok := categories[video.Category]; !ok {
+				categories[video.Category] = &WorkloadGroup{Name: video.Category}
+			}
+			categories[video.Category].Videos++
+			categories[video.Category].Hours += hours
+			workload.Hours += hours
+		}
+		date, err := time.Parse(dateFormat, video.Date)
+		if err == nil && len(phase) > 0 && videoPhase != videosPhasePublished && workflow.IsSponsored(video.Sponsorship) && !date.After(mustDoUntil) {
+			workload.MustDo = append(workload.MustDo, WorkloadItem{Name: video.Name, Category: video.Category, Phase: phase, Date: date, Hours: hours, Estimated: estimated})
+		}
+	}
+	for _, trend := range trendPhases {
+		if group, ok := phases[trend.Key]; ok {
+			workload.Phases = append(workload.Phases, *group)
+		}
+	}
+	for _, group := range categories {
+		workload.Categories = append(workload.Categories, *group)
+	}
+	sort.Slice(workload.Categories, func(i, j int) bool {
+		if workload.Categories[i].Hours != workload.Categories[j].Hours {
+			return workload.Categories[i].Hours > workload.Categories[j].Hours
+		}
+		return workload.Categories[i].Name < workload.Categories[j].Name
+	})
+	sort.SliceStable(workload.MustDo, func(i, j int) bool {
+		return workload.MustDo[i].Date.Before(workload.MustDo[j].Date)
+	})
+	return workload
+}
+
+func getWorkloadText(workload Workload) string {
+	var builder strings.Builder
+	if len(workload.MustDo) > 0 {
+		builder.WriteString("Must do this week:\n")
+		for _, item := range workload.MustDo {
+			hours := "no estimate"
+			if item.Estimated {
+				hours = "~" + formatWorkloadHours(item.Hours)
+			}
+			builder.WriteString(fmt.Sprintf("  %s (%s) publishes on %s, %s, %s\n", item.Name, item.Category, item.Date.Format(dayFormat), getTrendPhaseTitle(item.Phase), hours))
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("Per phase:\n")
+	for _, group := range workload.Phases {
+		builder.WriteString(fmt.Sprintf("  %s: %d videos, ~%s\n", getTrendPhaseTitle(group.Name), group.Videos, formatWorkloadHours(group.Hours)))
+	}
+	builder.WriteString("\nPer category:\n")
+	for _, group := range workload.Categories {
+		builder.WriteString(fmt.Sprintf("  %s: %d videos, ~%s\n", group.Name, group.Videos, formatWorkloadHours(group.Hours)))
+	}
+	builder.WriteString(fmt.Sprintf("\nYou have roughly %s of production work queued.", formatWorkloadHours(workload.Hours)))
+	return builder.String()
+}
+
+func getWorkloadCapacityWarning(workload Workload) string {
+	if !workload.ExceedsCapacity() {
+		return ""
+	}
+	return fmt.Sprintf("That is more than the weekly capacity of %s.", formatWorkloadHours(workload.Capacity))
+}
+
+func formatWorkloadHours(hours float64) string {
+	return fmt.Sprintf("%.1f hours", hours)
+}
+
+func getTrendPhaseTitle(key string) string {
+	for _, phase := range trendPhases {
+		if phase.Key == key {
+			return phase.Title
+		}
+	}
+	return key
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getWorkloadTestVideos() []Video {
+	halfway := Tasks{Completed: 1, Total: 2}
+	return []Video{
+		{Name: "started", Category: "ai", Date: "2030-02-20T16:00"},
+		{Name: "material", Category: "ai", Date: "2030-02-25T16:00", Code: true, Screen: true, Head: true, Diagrams: true, Init: halfway},
+		{Name: "sponsored", Category: "kubernetes", Date: "2030-01-24T16:00", Sponsorship: Sponsorship{Amount: "1000"}},
+		{Name: "overdue", Category: "kubernetes", Date: "2030-01-20T16:00", RequestEdit: true, Sponsorship: Sponsorship{Amount: "1000"}},
+		{Name: "later", Category: "kubernetes", Date: "2030-03-01T16:00", Sponsorship: Sponsorship{Amount: "1000"}},
+		{Name: "published", Category: "ai", Date: "2030-01-01T16:00", Repo: "N/A", Sponsorship: Sponsorship{Amount: "1000"}},
+		{Name: "delayed", Category: "ai", Date: "2030-01-22T16:00", Delayed: true, Sponsorship: Sponsorship{Amount: "1000"}},
+		{Name: "idea", Category: "ai"},
+	}
+}
+
+func TestWorkload_getWorkload(t *testing.T) {
+	now := time.Date(2030, 1, 21, 9, 0, 0, 0, time.UTC)
+	workload := getWorkload(getWorkloadTestVideos(), SettingsWorkload{Hours: getDefaultWorkloadHours(), WeeklyCapacity: 20, MustDoDays: 7}, now)
+	if workload.Hours != 24 {
+		t.Errorf("Expected: 24 hours\nGot: %v hours", workload.Hours)
+	}
+	expectedPhases := []WorkloadGroup{{"editRequested", 1, 2}, {"materialDone", 1, 4}, {"started", 3, 18}}
+	if !reflect.DeepEqual(workload.Phases, expectedPhases) {
+		t.Errorf("Expected: %v\nGot: %v", expectedPhases, workload.Phases)
+	}
+	expectedCategories := []WorkloadGroup{{"kubernetes", 3, 14}, {"ai", 2, 10}}
+	if !reflect.DeepEqual(workload.Categories, expectedCategories) {
+		t.Errorf("Expected: %v\nGot: %v", expectedCategories, workload.Categories)
+	}
+	mustDo := []string{}
+	for _, item := range workload.MustDo {
+		mustDo = append(mustDo, item.Name)
+	}
+	if expected := []string{"overdue", "delayed", "sponsored"}; !reflect.DeepEqual(mustDo, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, mustDo)
+	}
+	if !workload.ExceedsCapacity() {
+		t.Errorf("Expected %v hours to exceed the capacity of %v", workload.Hours, workload.Capacity)
+	}
+}
+
+func TestWorkload_getWorkloadWeighted(t *testing.T) {
+	videos := getWorkloadTestVideos()[:2]
+	options := SettingsWorkload{Hours: getDefaultWorkloadHours(), WeeklyCapacity: 20}
+	if workload := getWorkload(videos, options, time.Now()); workload.Hours != 10 {
+		t.Errorf("Expected: 10 hours\nGot: %v hours", workload.Hours)
+	}
+	options.Weighted = true
+	if workload := getWorkload(videos, options, time.Now()); workload.Hours != 8 {
+		t.Errorf("Expected the material done video to count only its remaining half (8 hours)\nGot: %v hours", workload.Hours)
+	}
+}
+
+func TestWorkload_getWorkloadCapacityWarning(t *testing.T) {
+	tests := []struct {
+		workload Workload
+		expected string
+	}{
+		{Workload{Hours: 19, Capacity: 20}, ""},
+		{Workload{Hours: 20, Capacity: 20}, ""},
+		{Workload{Hours: 22.5, Capacity: 20}, "That is more than the weekly capacity of 20.0 hours."},
+	}
+	for _, test := range tests {
+		if actual := getWorkloadCapacityWarning(test.workload); actual != test.expected {
+			t.Errorf("Expected: %q\nGot: %q", test.expected, actual)
+		}
+	}
+}
+
+func TestWorkload_getWorkloadText(t *testing.T) {
+	now := time.Date(2030, 1, 21, 9, 0, 0, 0, time.UTC)
+	text := getWorkloadText(getWorkload(getWorkloadTestVideos(), SettingsWorkload{Hours: getDefaultWorkloadHours(), WeeklyCapacity: 20, MustDoDays: 7}, now))
+	expected := []string{
+		"Must do this week:\n  overdue (kubernetes) publishes on 2030-01-20, Edit requested, ~2.0 hours\n  delayed (ai) publishes on 2030-01-22, Delayed, no estimate\n",
+		"  Started: 3 videos, ~18.0 hours\n",
+		"  kubernetes: 3 videos, ~14.0 hours\n",
+		"You have roughly 24.0 hours of production work queued.",
+	}
+	if !strings.HasPrefix(text, expected[0]) {
+		t.Errorf("Expected the must do videos first\nGot: %s", text)
+	}
+	for _, line := range expected[1:] {
+		if !strings.Contains(text, line) {
+			t.Errorf("Expected: %s\nGot: %s", line, text)
+		}
+	}
+}
@@ -1,9 +1,16 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
+	"devopstoolkitseries/youtube-automation/pkg/workflow"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,101 +23,112 @@ type VideoIndex struct {
 	Category string
 }
 
-type Video struct {
-	Name                string
-	Index               int
-	Path                string
-	Category            string
-	Init                Tasks
-	Work                Tasks
-	Define              Tasks
-	Edit                Tasks
-	Publish             Tasks
-	ProjectName         string
-	ProjectURL          string
-	Sponsorship         Sponsorship
-	Sponsored           string // TODO: Remove
-	SponsorshipBlocked  string // TODO: Remove
-	Date                string
-	Delayed             bool
-	Code                bool
-	Screen              bool
-	Head                bool
-	Thumbnails          bool
-	Diagrams            bool
-	Title               string
-	Description         string
-	Highlight           string
-	Tags                string
-	DescriptionTags     string
-	Location            string
-	Tagline             string
-	TaglineIdeas        string
-	OtherLogos          string
-	Screenshots         bool
-	RequestThumbnail    bool
-	Thumbnail           string
-	Thumbnail02         string
-	Thumbnail03         string
-	Members             string
-	Animations          string
-	RequestEdit         bool
-	Movie               bool
-	Timecodes           string
-	Gist                string
-	HugoPath            string
-	RelatedVideos       string
-	UploadVideo         string
-	VideoId             string
-	Tweet               string
-	TweetPosted         bool
-	LinkedInPosted      bool
-	SlackPosted         bool
-	HNPosted            bool
-	TCPosted            bool
-	YouTubeHighlight    bool
-	YouTubeComment      bool
-	YouTubeCommentReply bool
-	Slides              bool
-	GDE                 bool
-	Repo                string
-	TwitterSpace        bool
-	NotifiedSponsors    bool
-}
+type Video = workflow.Video
 
-type Tasks struct {
-	Completed int
-	Total     int
-}
+type Tasks = workflow.Tasks
 
-type Sponsorship struct {
-	Amount  string
-	Emails  string
-	Blocked string
-}
+type Sponsorship = workflow.Sponsorship
+
+type SponsorAsset = workflow.SponsorAsset
 
 func (y *YAML) GetVideo(path string) Video {
+	video, err := readVideo(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return video
+}
+
+// readVideo returns an empty video when the file does not exist. Videos written by older versions are migrated in memory and stored in the new form on the next write.
+func readVideo(path string) (Video, error) {
 	var video Video
 	data, err := os.ReadFile(path)
-	if err != nil {
-		return video
+	if errors.Is(err, os.ErrNotExist) {
+		return video, nil
 	}
-	err = yaml.Unmarshal(data, &video)
 	if err != nil {
+		return video, err
+	}
+	if err := readLegacyVideo(path, data, &video); err != nil {
+		return video, err
+	}
+	if err := migrateVideo(&video); err != nil {
+		return video, fmt.Errorf("%s: %w", path, err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		backfillBlockedSince(&video.Sponsorship, info.ModTime())
+	}
+	refreshVideoProgress(&video, settings)
+	return video, nil
+}
+
+// WriteVideo writes the video as changed by the rules so that callers that keep editing it do not overwrite what the rules did.
+func (y *YAML) WriteVideo(video *Video, path string) {
+	if err := y.writeVideo(video, path); err != nil {
 		log.Fatal(err)
 	}
-	return video
 }
 
-func (y *YAML) WriteVideo(video Video, path string) {
-	data, err := yaml.Marshal(&video)
+// writeVideo works like WriteVideo but returns the error so that callers writing many videos can report failures one by one.
+func (y *YAML) writeVideo(video *Video, path string) error {
+	previous, err := getPreviousVideo(path)
 	if err != nil {
-		log.Fatal(err)
+		output.Event(outputActionSave, path, "saved", err)
+		return err
+	}
+	return y.writeVideoOver(video, path, previous)
+}
+
+// writeMovedVideo writes the video to its new path and compares it with the video it was moved from so that the rules and the history do not treat it as a new one.
+func (y *YAML) writeMovedVideo(video *Video, path string, source Video) error {
+	return y.writeVideoOver(video, path, &source)
+}
+
+// writeVideoOver writes the video and runs the rules and records the history against the previous video, which is nil for new videos.
+func (y *YAML) writeVideoOver(video *Video, path string, previous *Video) (err error) {
+	defer func() {
+		output.Event(outputActionSave, path, "saved", err)
+	}()
+	var rules []Rule
+	var runner *RuleRunner
+	if len(settings.Rules) > 0 {
+		rules = getMatchingRules(settings.Rules, previous, *video)
+		runner = NewRuleRunner()
+		printRuleErrors(runner.Apply(rules, video))
+	}
+	updateBlockedSince(&video.Sponsorship, time.Now())
+	refreshVideoProgress(video, settings)
+	if len(video.ID) == 0 {
+		if video.ID, err = newVideoID(); err != nil {
+			return err
+		}
+	}
+	video.SchemaVersion = videoSchemaVersion
+	if err := validateSecretsEncrypted(video.Secrets); err != nil {
+		return err
 	}
-	err = os.WriteFile(path, data, 0644)
+	data, err := yaml.Marshal(video)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if err = writeFileAtomic(path, data, 0644); err != nil {
+		return err
+	}
+	updateSearchIndex(path, *video)
+	updatePhaseSummary(path, *video)
+	recordAuditSave(path, previous, *video)
+	if len(rules) > 0 {
+		printRuleErrors(runner.Notify(rules, *video))
 	}
+	return nil
+}
+
+func newVideoID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
 }
 
 func (y *YAML) GetIndex() []VideoIndex {
@@ -127,12 +145,49 @@ func (y *YAML) GetIndex() []VideoIndex {
 }
 
 func (y *YAML) WriteIndex(vi []VideoIndex) {
+	if err := y.writeIndex(vi); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (y *YAML) writeIndex(vi []VideoIndex) error {
 	data, err := yaml.Marshal(&vi)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	err = os.WriteFile(y.IndexPath, data, 0644)
+	return writeFileAtomic(y.IndexPath, data, 0644)
+}
+
+var renameFile = os.Rename
+
+// writeFileAtomic writes the data to a temporary file in the same directory and renames it over the path so that a crash or a full disk never leaves a truncated file behind.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	tmpPath := file.Name()
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := renameFile(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
 	}
+	return nil
 }
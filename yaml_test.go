@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestYAML_writeFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "video.yaml")
+	if err := writeFileAtomic(path, []byte("name: original\n"), 0644); err != nil {
+		t.Fatalf("Error occurred while writing %s: %v", path, err)
+	}
+	renameFile = func(oldPath, newPath string) error {
+		return errors.New("disk full")
+	}
+	defer func() { renameFile = os.Rename }()
+	if err := writeFileAtomic(path, []byte("name: updated\n"), 0644); err == nil {
+		t.Fatalf("Expected the write to fail when the rename fails")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	if string(data) != "name: original\n" {
+		t.Errorf("Expected the original content to be preserved, but got %q", string(data))
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", dir, err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected the temporary file to be removed, but found %d files", len(entries))
+	}
+}
+
+func TestYAML_WriteVideoSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.yaml")
+	yaml := YAML{}
+	yaml.WriteVideo(&Video{Name: "my-video"}, path)
+	video, err := readVideo(path)
+	if err != nil {
+		t.Fatalf("Error occurred while reading %s: %v", path, err)
+	}
+	if video.SchemaVersion != videoSchemaVersion || video.Name != "my-video" {
+		t.Errorf("Expected schema version %d and name my-video, but got %d and %s", videoSchemaVersion, video.SchemaVersion, video.Name)
+	}
+}
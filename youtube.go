@@ -13,10 +13,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 	"google.golang.org/api/youtube/v3"
 )
 
@@ -58,48 +58,13 @@ const launchWebServer = true
 // https://developers.google.com/api-client-library/python/guide/aaa_client_secrets
 // `
 
-// getClient uses a Context and Config to retrieve a Token
-// then generate a Client. It returns the generated Client.
-func getClient(scope string) *http.Client {
-	ctx := context.Background()
-
-	b, err := os.ReadFile("client_secret.json")
-	if err != nil {
-		log.Fatalf("Unable to read client secret file: %v", err)
-	}
-
-	// If modifying the scope, delete your previously saved credentials
-	// at ~/.credentials/youtube-go.json
-	config, err := google.ConfigFromJSON(b, scope)
+// getClient returns a client that uses the shared YouTube token, refreshing it when it expires.
+func getClient() *http.Client {
+	source, err := getYouTubeTokenSource()
 	if err != nil {
-		log.Fatalf("Unable to parse client secret file to config: %v", err)
-	}
-
-	// Use a redirect URI like this for a web app. The redirect URI must be a
-	// valid one for your OAuth2 credentials.
-	config.RedirectURL = "http://localhost:8090"
-	// Use the following redirect URI if launchWebServer=false in oauth2.go
-	// config.RedirectURL = "urn:ietf:wg:oauth:2.0:oob"
-
-	cacheFile, err := tokenCacheFile()
-	if err != nil {
-		log.Fatalf("Unable to get path to cached credential file. %v", err)
-	}
-	tok, err := tokenFromFile(cacheFile)
-	if err != nil {
-		authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-		if launchWebServer {
-			fmt.Println("Trying to get token from web")
-			tok, err = getTokenFromWeb(config, authURL)
-		} else {
-			fmt.Println("Trying to get token from prompt")
-			tok, err = getTokenFromPrompt(config, authURL)
-		}
-		if err == nil {
-			saveToken(cacheFile, tok)
-		}
+		log.Fatalf("Unable to get YouTube token: %v", err)
 	}
-	return config.Client(ctx, tok)
+	return oauth2.NewClient(context.Background(), source)
 }
 
 // startWebServer starts a web server that listens on http://localhost:8080.
@@ -154,14 +119,14 @@ func exchangeToken(config *oauth2.Config, code string) (*oauth2.Token, error) {
 // to enter the token on the command line. It returns the retrieved Token.
 func getTokenFromPrompt(config *oauth2.Config, authURL string) (*oauth2.Token, error) {
 	var code string
-	fmt.Printf("Go to the following link in your browser. After completing "+
+	// The prompt goes to stderr in all output modes since the authorization cannot be completed without it.
+	fmt.Fprintf(output.Stderr, "Go to the following link in your browser. After completing "+
 		"the authorization flow, enter the authorization code on the command "+
 		"line: \n%v\n", authURL)
 
 	if _, err := fmt.Scan(&code); err != nil {
 		log.Fatalf("Unable to read authorization code %v", err)
 	}
-	fmt.Println(authURL)
 	return exchangeToken(config, code)
 }
 
@@ -170,7 +135,7 @@ func getTokenFromPrompt(config *oauth2.Config, authURL string) (*oauth2.Token, e
 func getTokenFromWeb(config *oauth2.Config, authURL string) (*oauth2.Token, error) {
 	codeCh, err := startWebServer()
 	if err != nil {
-		fmt.Printf("Unable to start a web server.")
+		output.Error("Unable to start a web server.")
 		return nil, err
 	}
 
@@ -178,9 +143,8 @@ func getTokenFromWeb(config *oauth2.Config, authURL string) (*oauth2.Token, erro
 	if err != nil {
 		log.Fatalf("Unable to open authorization URL in web server: %v", err)
 	} else {
-		fmt.Println("Your browser has been opened to an authorization URL.",
-			" This program will resume once authorization has been provided.")
-		fmt.Println(authURL)
+		output.Info("Your browser has been opened to an authorization URL. This program will resume once authorization has been provided.")
+		fmt.Fprintln(output.Stderr, authURL)
 	}
 
 	// Wait for the web server to get the code.
@@ -214,36 +178,50 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 	return t, err
 }
 
-// saveToken uses a file path to create a file and store the
-// token in it.
-func saveToken(file string, token *oauth2.Token) {
-	fmt.Println("trying to save token")
-	fmt.Printf("Saving credential file to: %s\n", file)
-	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		log.Fatalf("Unable to cache oauth token: %v", err)
-	}
-	defer f.Close()
-	json.NewEncoder(f).Encode(token)
-}
-
-func uploadVideo(video Video) string {
+func uploadVideo(video Video) (string, error) {
 	if video.UploadVideo == "" {
-		log.Fatalf("You must provide a filename of a video file to upload")
-		return ""
+		return "", fmt.Errorf("You must provide a filename of a video file to upload")
 	}
 	if video.Thumbnail == "" {
-		log.Fatalf("You must provide a thumbnail of the video file to upload")
-		return ""
+		return "", fmt.Errorf("You must provide a thumbnail of the video file to upload")
 	}
-	client := getClient(youtube.YoutubeUploadScope)
+	// ConfirmUpload already warned that a past publish date is published immediately.
+	status, _, err := getScheduledUploadStatus(video, settings, time.Now())
+	if err != nil {
+		return "", err
+	}
+	client := getClient()
 	service, err := youtube.New(client)
 	if err != nil {
-		log.Fatalf("Error creating YouTube client: %v", err)
+		return "", fmt.Errorf("Error creating YouTube client: %v", err)
 	}
+	upload := getUploadRequest(video, status)
+	parts := []string{"snippet", "status"}
+	if upload.ContentDetails != nil {
+		parts = append(parts, "contentDetails")
+	}
+	call := service.Videos.Insert(parts, upload)
+	file, err := os.Open(video.UploadVideo)
+	if err != nil {
+		return "", fmt.Errorf("Error opening %v: %v", video.UploadVideo, err)
+	}
+	defer file.Close()
+
+	response, err := call.Media(file).Do()
+	if err != nil {
+		return "", fmt.Errorf("Error getting response from YouTube: %w", err)
+	}
+	output.Print(fmt.Sprintf("Upload successful! Video ID: %v", response.Id))
+	return response.Id, nil
+}
+
+// youtubeDefaultCategoryID is Science & Technology.
+const youtubeDefaultCategoryID = "28"
+
+func getUploadRequest(video Video, status UploadStatus) *youtube.Video {
 	timecodes := ""
 	if len(video.Timecodes) > 0 && video.Timecodes != "N/A" {
-		timecodes = fmt.Sprintf("▬▬▬▬▬▬ ⏱ Timecodes ⏱ ▬▬▬▬▬▬\n%s", video.Timecodes)
+		timecodes = fmt.Sprintf("▬▬▬▬▬▬ ⏱ Timecodes ⏱ ▬▬▬▬▬▬\n%s", getUploadTimecodes(video))
 	}
 	description := fmt.Sprintf(`%s
 
@@ -253,10 +231,10 @@ Consider joining the channel: https://www.youtube.com/c/devopstoolkit/join
 
 ▬▬▬▬▬▬ 🔗 Additional Info 🔗 ▬▬▬▬▬▬ 
 %s
-▬▬▬▬▬▬ 💰 Sponsorships 💰 ▬▬▬▬▬▬ 
+%s▬▬▬▬▬▬ 💰 Sponsorships 💰 ▬▬▬▬▬▬ 
 If you are interested in sponsoring this channel, please visit https://devopstoolkit.live/sponsor for more information. Alternatively, feel free to contact me over Twitter or LinkedIn (see below).
 
-▬▬▬▬▬▬ 👋 Contact me 👋 ▬▬▬▬▬▬ 
+%s▬▬▬▬▬▬ 👋 Contact me 👋 ▬▬▬▬▬▬ 
 ➡ BlueSky: https://vfarcic.bsky.social
 ➡ LinkedIn: https://www.linkedin.com/in/viktorfarcic/
 
@@ -265,18 +243,25 @@ If you are interested in sponsoring this channel, please visit https://devopstoo
 💬 Live streams: https://www.youtube.com/c/DevOpsParadox
 
 %s
-`, video.Description, video.DescriptionTags, getAdditionalInfo(video.HugoPath, video.ProjectName, video.ProjectURL, video.RelatedVideos), timecodes)
+%s`, video.Description, video.DescriptionTags, getAdditionalInfo(video.HugoPath, video.ProjectName, getProjectURL(video), video.RelatedVideos), getCreditsSection(video.Attributions), getMembersDescription(getRenderedMembers(video.Members, settings.Members.Exclude)), timecodes, getContentDisclaimer(video.ContentFlags, settings.Content.Disclaimer))
 
+	categoryID := youtubeDefaultCategoryID
+	if len(video.YouTubeCategoryID) > 0 {
+		categoryID = video.YouTubeCategoryID
+	}
 	upload := &youtube.Video{
 		Snippet: &youtube.VideoSnippet{
-			Title:       video.Title,
-			Description: description,
-			CategoryId:  "28",
-			ChannelId:   channelID,
+			Title:           video.Title,
+			Description:     description,
+			CategoryId:      categoryID,
+			ChannelId:       settings.YouTube.ChannelID,
+			DefaultLanguage: settings.Upload.Language,
 		},
 		Status: &youtube.VideoStatus{
-			PrivacyStatus: "private",
-			PublishAt:     video.Date,
+			PrivacyStatus:           status.PrivacyStatus,
+			PublishAt:               status.PublishAt,
+			SelfDeclaredMadeForKids: status.MadeForKids,
+			ForceSendFields:         []string{"SelfDeclaredMadeForKids"},
 		},
 		// MonetizationDetails: &youtube.VideoMonetizationDetails{
 		// 	Access: &youtube.AccessPolicy{
@@ -284,23 +269,16 @@ If you are interested in sponsoring this channel, please visit https://devopstoo
 		// 	},
 		// },
 	}
+	if video.ContentFlags.AgeRestricted {
+		upload.ContentDetails = &youtube.VideoContentDetails{
+			ContentRating: &youtube.ContentRating{YtRating: contentAgeRestrictedRating},
+		}
+	}
 	// The API returns a 400 Bad Request response if tags is an empty string.
 	if strings.Trim(video.Tags, "") != "" {
 		upload.Snippet.Tags = strings.Split(video.Tags, ",")
 	}
-	call := service.Videos.Insert([]string{"snippet", "status"}, upload)
-	file, err := os.Open(video.UploadVideo)
-	defer file.Close()
-	if err != nil {
-		log.Fatalf("Error opening %v: %v", video.UploadVideo, err)
-	}
-
-	response, err := call.Media(file).Do()
-	if err != nil {
-		log.Fatalf("Error getting response from YouTube: %v", err)
-	}
-	fmt.Printf("Upload successful! Video ID: %v\n", response.Id)
-	return response.Id
+	return upload
 }
 
 func getAdditionalInfo(hugoPath, projectName, projectURL, relatedVideosRaw string) string {
@@ -326,7 +304,7 @@ func getAdditionalInfo(hugoPath, projectName, projectURL, relatedVideosRaw strin
 }
 
 func uploadThumbnail(video Video) error {
-	client := getClient(youtube.YoutubeUploadScope)
+	client := getClient()
 
 	service, err := youtube.New(client)
 	if err != nil {
@@ -343,12 +321,12 @@ func uploadThumbnail(video Video) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("Thumbnail uploaded, URL: %s\n", response.Items[0].Default.Url)
+	output.Print(fmt.Sprintf("Thumbnail uploaded, URL: %s", response.Items[0].Default.Url))
 	return nil
 }
 
 func setPlaylists(video Video) error {
-	client := getClient(youtube.YoutubeScope)
+	client := getClient()
 	service, err := youtube.New(client)
 	if err != nil {
 		return err
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"devopstoolkitseries/youtube-automation/pkg/utils"
+	"google.golang.org/api/youtube/v3"
+)
+
+const syncPull = "pull"
+const syncPush = "push"
+
+// youtubeSyncBatchSize is the maximum number of IDs videos.list accepts in a single request.
+const youtubeSyncBatchSize = 50
+
+const youtubeSyncCacheTTL = time.Hour
+
+// SyncDrift is a field that differs between YouTube and the local values (or the snapshot of what was uploaded, if there is one).
+type SyncDrift struct {
+	Field string
+	Local string
+	Live  string
+	// Pullable is false for fields that cannot be derived back from YouTube (e.g., the description is composed from several fields).
+	Pullable bool
+}
+
+// youtubeSyncCache is remembered for the rest of the session.
+var youtubeSyncCache = newSyncDriftCache(youtubeSyncCacheTTL)
+
+// getSyncBaseline returns what YouTube is expected to have.
+func getSyncBaseline(video Video) UploadedSnapshot {
+	if hasUploadedSnapshot(video) {
+		return video.UploadedSnapshot
+	}
+	return getUploadedSnapshot(video, time.Time{})
+}
+
+func getSyncDrift(video Video, live *youtube.VideoSnippet) []SyncDrift {
+	baseline := getSyncBaseline(video)
+	fields := []SyncDrift{
+		{"Title", baseline.Title, live.Title, true},
+		{"Description", baseline.Description, live.Description, false},
+		{"Tags", joinSyncTags(baseline.Tags), joinSyncTags(live.Tags), true},
+		{"Category", baseline.CategoryID, live.CategoryId, true},
+	}
+	drift := []SyncDrift{}
+	for _, field := range fields {
+		if field.Local != field.Live {
+			drift = append(drift, field)
+		}
+	}
+	return drift
+}
+
+// joinSyncTags trims the tags and drops empty ones so that the spacing around commas (e.g., "a, b" and "a,b") is not reported as drift.
+func joinSyncTags(tags []string) string {
+	return strings.Join(splitTags(strings.Join(tags, ",")), ",")
+}
+
+func getSyncDriftText(drift []SyncDrift) string {
+	if len(drift) == 0 {
+		return "The video on YouTube matches the local values."
+	}
+	var builder strings.Builder
+	for _, field := range drift {
+		builder.WriteString(fmt.Sprintf("%s:\n%s\n\n", field.Field, renderWordDiff(utils.GetWordDiff(field.Local, field.Live))))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func fetchLiveSnippets(service *youtube.Service, videoIds []string) (map[string]*youtube.VideoSnippet, error) {
+	response, err := service.Videos.List([]string{"snippet"}).Id(videoIds...).Do()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting videos from YouTube: %w", err)
+	}
+	snippets := map[string]*youtube.VideoSnippet{}
+	for _, item := range response.Items {
+		if item.Snippet != nil {
+			snippets[item.Id] = item.Snippet
+		}
+	}
+	return snippets, nil
+}
+
+func fetchLiveSnippet(service *youtube.Service, videoId string) (*youtube.VideoSnippet, error) {
+	if len(videoId) == 0 {
+		return nil, fmt.Errorf("the video was not uploaded yet")
+	}
+	snippets, err := fetchLiveSnippets(service, []string{videoId})
+	if err != nil {
+		return nil, err
+	}
+	snippet, ok := snippets[videoId]
+	if !ok {
+		return nil, fmt.Errorf("video %s was not found on YouTube", videoId)
+	}
+	return snippet, nil
+}
+
+// applySyncResolutions pulls or pushes each field according to the resolutions keyed by field names. Fields without a resolution are left as they are.
+// All pushed fields are sent in a single update that keeps the live values of the other fields. Nothing is pulled if the push fails.
+func applySyncResolutions(service *youtube.Service, video Video, live *youtube.VideoSnippet, resolutions map[string]string) (Video, error) {
+	current := getUploadedSnapshot(video, time.Time{})
+	// The update replaces the whole snippet, so everything that can be set has to be copied from the live one.
+	pushed := &youtube.VideoSnippet{
+		Title:                live.Title,
+		Description:          live.Description,
+		Tags:                 live.Tags,
+		CategoryId:           live.CategoryId,
+		DefaultLanguage:      live.DefaultLanguage,
+		DefaultAudioLanguage: live.DefaultAudioLanguage,
+	}
+	push := false
+	for field, resolution := range resolutions {
+		if resolution != syncPush {
+			continue
+		}
+		push = true
+		switch field {
+		case "Title":
+			pushed.Title = current.Title
+		case "Description":
+			pushed.Description = current.Description
+		case "Tags":
+			pushed.Tags = splitTags(strings.Join(current.Tags, ","))
+		case "Category":
+			pushed.CategoryId = current.CategoryID
+		default:
+			return video, fmt.Errorf("%s cannot be pushed", field)
+		}
+	}
+	for field, resolution := range resolutions {
+		if resolution == syncPull && field != "Title" && field != "Tags" && field != "Category" {
+			return video, fmt.Errorf("%s cannot be pulled", field)
+		}
+	}
+	if push {
+		if _, err := service.Videos.Update([]string{"snippet"}, &youtube.Video{Id: video.VideoId, Snippet: pushed}).Do(); err != nil {
+			return video, fmt.Errorf("Error updating the video on YouTube: %w", err)
+		}
+	}
+	snapshot := hasUploadedSnapshot(video)
+	for field, resolution := range resolutions {
+		value := live
+		switch resolution {
+		case syncPush:
+			value = pushed
+		case syncPull:
+			switch field {
+			case "Title":
+				video.Title = live.Title
+			case "Tags":
+				video.Tags = strings.Join(live.Tags, ",")
+			case "Category":
+				video.YouTubeCategoryID = live.CategoryId
+			}
+		default:
+			continue
+		}
+		if !snapshot {
+			continue
+		}
+		switch field {
+		case "Title":
+			video.UploadedSnapshot.Title = value.Title
+		case "Description":
+			video.UploadedSnapshot.Description = value.Description
+		case "Tags":
+			video.UploadedSnapshot.Tags = value.Tags
+		case "Category":
+			video.UploadedSnapshot.CategoryID = value.CategoryId
+		}
+	}
+	return video, nil
+}
+
+// SyncDriftCache remembers whether videos drifted from YouTube so that listing many of them does not exhaust the quota.
+// Entries expire after the TTL or as soon as the local values change.
+type SyncDriftCache struct {
+	TTL     time.Duration
+	entries map[string]syncDriftEntry
+}
+
+type syncDriftEntry struct {
+	drift       bool
+	fingerprint string
+	checkedAt   time.Time
+}
+
+func newSyncDriftCache(ttl time.Duration) *SyncDriftCache {
+	return &SyncDriftCache{TTL: ttl, entries: map[string]syncDriftEntry{}}
+}
+
+func getSyncFingerprint(video Video) string {
+	baseline := getSyncBaseline(video)
+	return strings.Join([]string{baseline.Title, baseline.Description, strings.Join(baseline.Tags, ","), baseline.CategoryID}, "\x00")
+}
+
+// HasDrift returns whether each of the uploaded videos drifted, keyed by video IDs. Only videos that are not cached are fetched, in batches.
+// Videos that are not found on YouTube are left out.
+func (c *SyncDriftCache) HasDrift(service *youtube.Service, videos []Video, now time.Time) (map[string]bool, error) {
+	drift := map[string]bool{}
+	missing := []Video{}
+	for _, video := range videos {
+		if len(video.VideoId) == 0 {
+			continue
+		}
+		entry, ok := c.entries[video.VideoId]
+		if ok && entry.fingerprint == getSyncFingerprint(video) && now.Sub(entry.checkedAt) < c.TTL {
+			drift[video.VideoId] = entry.drift
+			continue
+		}
+		missing = append(missing, video)
+	}
+	for start := 0; start < len(missing); start += youtubeSyncBatchSize {
+		batch := missing[start:min(start+youtubeSyncBatchSize, len(missing))]
+		ids := []string{}
+		for _, video := range batch {
+			ids = append(ids, video.VideoId)
+		}
+		snippets, err := fetchLiveSnippets(service, ids)
+		if err != nil {
+			return drift, err
+		}
+		for _, video := range batch {
+			snippet, ok := snippets[video.VideoId]
+			if !ok {
+				continue
+			}
+			drifted := len(getSyncDrift(video, snippet)) > 0
+			c.entries[video.VideoId] = syncDriftEntry{drift: drifted, fingerprint: getSyncFingerprint(video), checkedAt: now}
+			drift[video.VideoId] = drifted
+		}
+	}
+	return drift, nil
+}
+
+func (c *SyncDriftCache) Invalidate(videoId string) {
+	delete(c.entries, videoId)
+}
+
+func newYouTubeService() (*youtube.Service, error) {
+	service, err := youtube.New(getClient())
+	if err != nil {
+		return nil, fmt.Errorf("Error creating YouTube client: %v", err)
+	}
+	return service, nil
+}
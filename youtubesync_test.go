@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+// fakeYouTube serves videos.list from the snippets keyed by video IDs and records the requests.
+type fakeYouTube struct {
+	snippets map[string]*youtube.VideoSnippet
+	lists    [][]string
+	updates  []youtube.Video
+}
+
+func (f *fakeYouTube) newService(t *testing.T) *youtube.Service {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			data, _ := io.ReadAll(r.Body)
+			video := youtube.Video{}
+			json.Unmarshal(data, &video)
+			f.updates = append(f.updates, video)
+			w.Write(data)
+			return
+		}
+		ids := []string{}
+		for _, id := range r.URL.Query()["id"] {
+			ids = append(ids, strings.Split(id, ",")...)
+		}
+		f.lists = append(f.lists, ids)
+		response := youtube.VideoListResponse{}
+		for _, id := range ids {
+			if snippet, ok := f.snippets[id]; ok {
+				response.Items = append(response.Items, &youtube.Video{Id: id, Snippet: snippet})
+			}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	service, err := youtube.New(server.Client())
+	if err != nil {
+		t.Fatalf("Error occurred while creating the service: %v", err)
+	}
+	service.BasePath = server.URL + "/"
+	return service
+}
+
+func getSyncTestVideo() Video {
+	return Video{VideoId: "abc", Title: "Something", Description: "About something", Tags: "a,b"}
+}
+
+func getSyncTestSnippet(video Video) *youtube.VideoSnippet {
+	snippet := getUploadRequest(video, UploadStatus{}).Snippet
+	snippet.ChannelId = ""
+	return snippet
+}
+
+func TestYouTubeSync_getSyncDrift(t *testing.T) {
+	video := getSyncTestVideo()
+	live := getSyncTestSnippet(video)
+	if drift := getSyncDrift(video, live); len(drift) != 0 {
+		t.Errorf("Expected no drift, but got %v", drift)
+	}
+	live.Tags = []string{"a", "c"}
+	live.CategoryId = "27"
+	expected := []SyncDrift{{"Tags", "a,b", "a,c", true}, {"Category", "28", "27", true}}
+	drift := getSyncDrift(video, live)
+	if len(drift) != len(expected) {
+		t.Fatalf("Expected: %v\nGot: %v", expected, drift)
+	}
+	for i := range expected {
+		if drift[i] != expected[i] {
+			t.Errorf("Expected: %v\nGot: %v", expected[i], drift[i])
+		}
+	}
+}
+
+func TestYouTubeSync_getSyncDriftTagSpacing(t *testing.T) {
+	video := getSyncTestVideo()
+	video.Tags = "a, b,"
+	live := getSyncTestSnippet(video)
+	live.Tags = []string{"a", "b"}
+	if drift := getSyncDrift(video, live); len(drift) != 0 {
+		t.Errorf("Expected: no drift\nGot: %v", drift)
+	}
+	live.Tags = []string{" a ", "", "b"}
+	video.Tags = "a,b"
+	if drift := getSyncDrift(video, live); len(drift) != 0 {
+		t.Errorf("Expected: no drift\nGot: %v", drift)
+	}
+}
+
+func TestYouTubeSync_getSyncDriftUsesSnapshot(t *testing.T) {
+	video := getSyncTestVideo()
+	live := getSyncTestSnippet(video)
+	video.UploadedSnapshot = getUploadedSnapshot(video, time.Now())
+	video.Title = "Changed locally"
+	if drift := getSyncDrift(video, live); len(drift) != 0 {
+		t.Errorf("Expected local changes not to be reported as drift when there is a snapshot, but got %v", drift)
+	}
+	live.Title = "Changed in Studio"
+	drift := getSyncDrift(video, live)
+	if len(drift) != 1 || drift[0].Local != "Something" || drift[0].Live != "Changed in Studio" {
+		t.Errorf("Expected the title to drift from the snapshot, but got %v", drift)
+	}
+}
+
+func TestYouTubeSync_fetchLiveSnippet(t *testing.T) {
+	video := getSyncTestVideo()
+	fake := &fakeYouTube{snippets: map[string]*youtube.VideoSnippet{"abc": getSyncTestSnippet(video)}}
+	service := fake.newService(t)
+	snippet, err := fetchLiveSnippet(service, "abc")
+	if err != nil || snippet.Title != "Something" {
+		t.Errorf("Expected the live snippet, but got %v and %v", snippet, err)
+	}
+	if _, err := fetchLiveSnippet(service, "missing"); err == nil {
+		t.Errorf("Expected an error for a video that is not on YouTube")
+	}
+	if _, err := fetchLiveSnippet(service, ""); err == nil {
+		t.Errorf("Expected an error for a video that was not uploaded")
+	}
+}
+
+func TestYouTubeSync_applySyncResolutions(t *testing.T) {
+	video := getSyncTestVideo()
+	video.UploadedSnapshot = getUploadedSnapshot(video, time.Now())
+	video.Title = "Changed locally"
+	live := getSyncTestSnippet(getSyncTestVideo())
+	live.Title = "Changed in Studio"
+	live.Tags = []string{"a", "c"}
+	live.CategoryId = "27"
+	live.DefaultAudioLanguage = "en"
+	fake := &fakeYouTube{}
+	updated, err := applySyncResolutions(fake.newService(t), video, live, map[string]string{"Title": syncPush, "Tags": syncPull})
+	if err != nil {
+		t.Fatalf("Expected the resolutions to be applied, but got %v", err)
+	}
+	if len(fake.updates) != 1 {
+		t.Fatalf("Expected a single update, but got %d", len(fake.updates))
+	}
+	pushed := fake.updates[0]
+	if pushed.Id != "abc" || pushed.Snippet.Title != "Changed locally" || strings.Join(pushed.Snippet.Tags, ",") != "a,c" || pushed.Snippet.CategoryId != "27" || pushed.Snippet.DefaultAudioLanguage != "en" {
+		t.Errorf("Expected only the title to be pushed, but got %+v", pushed.Snippet)
+	}
+	if updated.Title != "Changed locally" || updated.Tags != "a,c" || len(updated.YouTubeCategoryID) > 0 {
+		t.Errorf("Expected only the tags to be pulled, but got %+v", updated)
+	}
+	if updated.UploadedSnapshot.Title != "Changed locally" || strings.Join(updated.UploadedSnapshot.Tags, ",") != "a,c" || updated.UploadedSnapshot.CategoryID != "28" {
+		t.Errorf("Expected the snapshot to follow the resolved fields, but got %+v", updated.UploadedSnapshot)
+	}
+	if drift := getSyncDrift(updated, pushed.Snippet); len(drift) != 1 || drift[0].Field != "Category" {
+		t.Errorf("Expected only the unresolved category to drift, but got %v", drift)
+	}
+}
+
+func TestYouTubeSync_applySyncResolutionsPullCategory(t *testing.T) {
+	video := getSyncTestVideo()
+	live := getSyncTestSnippet(video)
+	live.CategoryId = "27"
+	live.DefaultAudioLanguage = "en"
+	fake := &fakeYouTube{}
+	updated, err := applySyncResolutions(fake.newService(t), video, live, map[string]string{"Category": syncPull})
+	if err != nil {
+		t.Fatalf("Expected the category to be pulled, but got %v", err)
+	}
+	if len(fake.updates) != 0 {
+		t.Errorf("Expected nothing to be pushed, but got %v", fake.updates)
+	}
+	if getUploadRequest(updated, UploadStatus{}).Snippet.CategoryId != "27" {
+		t.Errorf("Expected the pulled category to be used for uploads, but got %s", updated.YouTubeCategoryID)
+	}
+}
+
+func TestYouTubeSync_applySyncResolutionsDescriptionCannotBePulled(t *testing.T) {
+	video := getSyncTestVideo()
+	live := getSyncTestSnippet(video)
+	live.Description = "Edited in Studio"
+	fake := &fakeYouTube{}
+	if _, err := applySyncResolutions(fake.newService(t), video, live, map[string]string{"Description": syncPull, "Title": syncPush}); err == nil {
+		t.Errorf("Expected an error when pulling the description")
+	}
+	if len(fake.updates) != 0 {
+		t.Errorf("Expected nothing to be pushed when a resolution is invalid, but got %v", fake.updates)
+	}
+}
+
+func TestYouTubeSync_HasDrift(t *testing.T) {
+	videos := []Video{}
+	snippets := map[string]*youtube.VideoSnippet{}
+	for i := 0; i < youtubeSyncBatchSize+2; i++ {
+		video := getSyncTestVideo()
+		video.VideoId = strings.Repeat("v", i+1)
+		videos = append(videos, video)
+		snippets[video.VideoId] = getSyncTestSnippet(video)
+	}
+	snippets["v"].Title = "Changed in Studio"
+	videos = append(videos, Video{Title: "Not uploaded"})
+	fake := &fakeYouTube{snippets: snippets}
+	service := fake.newService(t)
+	cache := newSyncDriftCache(time.Hour)
+	now := time.Date(2030, 1, 21, 16, 0, 0, 0, time.UTC)
+	drift, err := cache.HasDrift(service, videos, now)
+	if err != nil {
+		t.Fatalf("Expected the drift to be checked, but got %v", err)
+	}
+	if len(fake.lists) != 2 || len(fake.lists[0]) != youtubeSyncBatchSize || len(fake.lists[1]) != 2 {
+		t.Errorf("Expected two batched requests, but got %v", fake.lists)
+	}
+	if len(drift) != youtubeSyncBatchSize+2 || !drift["v"] || drift["vv"] {
+		t.Errorf("Unexpected drift %v", drift)
+	}
+	if _, err := cache.HasDrift(service, videos, now.Add(time.Minute)); err != nil || len(fake.lists) != 2 {
+		t.Errorf("Expected cached results without requests, but got %d requests and %v", len(fake.lists), err)
+	}
+	videos[1].Title = "Changed locally"
+	drift, _ = cache.HasDrift(service, videos, now.Add(time.Minute))
+	if len(fake.lists) != 3 || len(fake.lists[2]) != 1 || !drift["vv"] {
+		t.Errorf("Expected only the changed video to be checked again, but got %v", fake.lists)
+	}
+	cache.Invalidate("v")
+	cache.HasDrift(service, videos, now.Add(time.Minute))
+	if len(fake.lists) != 4 || fake.lists[3][0] != "v" {
+		t.Errorf("Expected the invalidated video to be checked again, but got %v", fake.lists)
+	}
+	cache.HasDrift(service, videos, now.Add(2*time.Hour))
+	if len(fake.lists) != 6 {
+		t.Errorf("Expected expired entries to be checked again in batches, but got %d requests", len(fake.lists))
+	}
+}